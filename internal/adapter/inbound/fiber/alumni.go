@@ -0,0 +1,402 @@
+package fiber_inbound_adapter
+
+import (
+	"errors"
+	"time"
+
+	"prabogo/internal/domain"
+	alumni_domain "prabogo/internal/domain/alumni"
+	"prabogo/internal/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type alumniAdapter struct {
+	domain domain.Domain
+}
+
+func NewAlumniAdapter(d domain.Domain) *alumniAdapter {
+	return &alumniAdapter{domain: d}
+}
+
+// alumniError maps domain errors to HTTP status codes
+func alumniError(c *fiber.Ctx, err error, message string) error {
+	status := fiber.StatusBadRequest
+	switch {
+	case errors.Is(err, alumni_domain.ErrAlumniNotFound),
+		errors.Is(err, alumni_domain.ErrSurveyNotFound):
+		status = fiber.StatusNotFound
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"status":  "error",
+		"message": message + ": " + err.Error(),
+	})
+}
+
+// ==========================================
+// REGISTRY HANDLERS
+// ==========================================
+
+// GET /api/v1/sekolah/alumni?tahun_lulus=&kegiatan=&search=
+func (h *alumniAdapter) GetAlumniList(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	filter := model.AlumniFilter{
+		TahunLulus: c.QueryInt("tahun_lulus"),
+		Kegiatan:   c.Query("kegiatan"),
+		Search:     c.Query("search"),
+	}
+	list, err := h.domain.Alumni().GetAlumniList(c.Context(), tenantID, filter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data alumni",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   list,
+	})
+}
+
+// GET /api/v1/sekolah/alumni/:id
+func (h *alumniAdapter) GetAlumniDetail(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	alumni, err := h.domain.Alumni().GetAlumniDetail(c.Context(), tenantID, c.Params("id"))
+	if err != nil {
+		return alumniError(c, err, "Gagal mengambil detail alumni")
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   alumni,
+	})
+}
+
+// POST /api/v1/sekolah/alumni
+func (h *alumniAdapter) CreateAlumni(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var alumni model.Alumni
+	if err := c.BodyParser(&alumni); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+	alumni.TenantID = tenantID
+
+	if err := h.domain.Alumni().CreateAlumni(c.Context(), &alumni); err != nil {
+		return alumniError(c, err, "Gagal menambah alumni")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Alumni berhasil ditambahkan",
+		"data":    alumni,
+	})
+}
+
+// PUT /api/v1/sekolah/alumni/:id
+func (h *alumniAdapter) UpdateAlumni(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var alumni model.Alumni
+	if err := c.BodyParser(&alumni); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+	alumni.ID = c.Params("id")
+	alumni.TenantID = tenantID
+
+	if err := h.domain.Alumni().UpdateAlumni(c.Context(), &alumni); err != nil {
+		return alumniError(c, err, "Gagal memperbarui alumni")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Data alumni berhasil diperbarui",
+	})
+}
+
+// POST /api/v1/sekolah/alumni/kelulusan
+func (h *alumniAdapter) Kelulusan(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input model.KelulusanInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+
+	count, err := h.domain.Alumni().Kelulusan(c.Context(), tenantID, input)
+	if err != nil {
+		return alumniError(c, err, "Gagal memproses kelulusan")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Kelulusan berhasil diproses",
+		"data": fiber.Map{
+			"alumni_baru": count,
+		},
+	})
+}
+
+// POST /api/v1/sekolah/alumni/:id/kirim-link
+func (h *alumniAdapter) KirimLinkProfil(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	link, err := h.domain.Alumni().KirimLinkProfil(c.Context(), tenantID, c.Params("id"))
+	if err != nil {
+		return alumniError(c, err, "Gagal mengirim link pembaruan data")
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data": fiber.Map{
+			"link": link,
+		},
+	})
+}
+
+// GET /api/v1/sekolah/alumni/statistik
+func (h *alumniAdapter) GetStatistik(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	stats, err := h.domain.Alumni().GetStatistik(c.Context(), tenantID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil statistik alumni",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   stats,
+	})
+}
+
+// ==========================================
+// TRACER STUDY HANDLERS
+// ==========================================
+
+// tracerSurveyInput is the payload for a tracer study campaign
+type tracerSurveyInput struct {
+	Judul            string                   `json:"judul"`
+	Deskripsi        string                   `json:"deskripsi"`
+	TahunLulusDari   int                      `json:"tahun_lulus_dari"`
+	TahunLulusSampai int                      `json:"tahun_lulus_sampai"`
+	Pertanyaan       []model.TracerPertanyaan `json:"pertanyaan"`
+	Status           string                   `json:"status"`
+	TanggalMulai     string                   `json:"tanggal_mulai"`   // YYYY-MM-DD
+	TanggalSelesai   string                   `json:"tanggal_selesai"` // YYYY-MM-DD
+}
+
+func (in tracerSurveyInput) toModel() (*model.TracerSurvey, error) {
+	mulai, err := time.Parse("2006-01-02", in.TanggalMulai)
+	if err != nil {
+		return nil, err
+	}
+	selesai, err := time.Parse("2006-01-02", in.TanggalSelesai)
+	if err != nil {
+		return nil, err
+	}
+	return &model.TracerSurvey{
+		Judul:            in.Judul,
+		Deskripsi:        in.Deskripsi,
+		TahunLulusDari:   in.TahunLulusDari,
+		TahunLulusSampai: in.TahunLulusSampai,
+		Pertanyaan:       in.Pertanyaan,
+		Status:           in.Status,
+		TanggalMulai:     mulai,
+		TanggalSelesai:   selesai,
+	}, nil
+}
+
+// GET /api/v1/sekolah/alumni/tracer?status=
+func (h *alumniAdapter) GetSurveyList(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	list, err := h.domain.Alumni().GetSurveyList(c.Context(), tenantID, c.Query("status"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data tracer study",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   list,
+	})
+}
+
+// POST /api/v1/sekolah/alumni/tracer
+func (h *alumniAdapter) CreateSurvey(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input tracerSurveyInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+	survey, err := input.toModel()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format tanggal tidak valid (YYYY-MM-DD)",
+		})
+	}
+	survey.TenantID = tenantID
+
+	if err := h.domain.Alumni().CreateSurvey(c.Context(), survey); err != nil {
+		return alumniError(c, err, "Gagal membuat tracer study")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Tracer study berhasil dibuat",
+		"data":    survey,
+	})
+}
+
+// PUT /api/v1/sekolah/alumni/tracer/:id
+func (h *alumniAdapter) UpdateSurvey(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input tracerSurveyInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+	survey, err := input.toModel()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format tanggal tidak valid (YYYY-MM-DD)",
+		})
+	}
+	survey.ID = c.Params("id")
+	survey.TenantID = tenantID
+
+	if err := h.domain.Alumni().UpdateSurvey(c.Context(), survey); err != nil {
+		return alumniError(c, err, "Gagal memperbarui tracer study")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Tracer study berhasil diperbarui",
+	})
+}
+
+// GET /api/v1/sekolah/alumni/tracer/:id/hasil
+func (h *alumniAdapter) GetSurveyHasil(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	survey, jawaban, err := h.domain.Alumni().GetSurveyHasil(c.Context(), tenantID, c.Params("id"))
+	if err != nil {
+		return alumniError(c, err, "Gagal mengambil hasil tracer study")
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data": fiber.Map{
+			"survey":  survey,
+			"jawaban": jawaban,
+		},
+	})
+}
+
+// POST /api/v1/sekolah/alumni/tracer/:id/broadcast
+func (h *alumniAdapter) BroadcastSurvey(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	sent, err := h.domain.Alumni().BroadcastSurvey(c.Context(), tenantID, c.Params("id"))
+	if err != nil {
+		return alumniError(c, err, "Gagal mengirim undangan tracer study")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Undangan tracer study terkirim",
+		"data": fiber.Map{
+			"terkirim": sent,
+		},
+	})
+}
+
+// ==========================================
+// PUBLIC SELF-SERVICE HANDLERS
+// ==========================================
+
+// GET /api/v1/alumni/:token
+func (h *alumniAdapter) GetProfil(c *fiber.Ctx) error {
+	alumni, surveys, err := h.domain.Alumni().GetProfil(c.Context(), c.Params("token"))
+	if err != nil {
+		return alumniError(c, err, "Gagal mengambil data alumni")
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data": fiber.Map{
+			"alumni": alumni,
+			"survey": surveys,
+		},
+	})
+}
+
+// PUT /api/v1/alumni/:token
+func (h *alumniAdapter) UpdateProfil(c *fiber.Ctx) error {
+	var input model.AlumniProfilInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+
+	alumni, err := h.domain.Alumni().UpdateProfil(c.Context(), c.Params("token"), input)
+	if err != nil {
+		return alumniError(c, err, "Gagal memperbarui data")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Terima kasih, data Anda telah diperbarui",
+		"data":    alumni,
+	})
+}
+
+// POST /api/v1/alumni/:token/survey/:survey_id
+func (h *alumniAdapter) IsiSurvey(c *fiber.Ctx) error {
+	var input struct {
+		Jawaban map[string]string `json:"jawaban"`
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+
+	if err := h.domain.Alumni().IsiSurvey(c.Context(), c.Params("token"), c.Params("survey_id"), input.Jawaban); err != nil {
+		return alumniError(c, err, "Gagal menyimpan jawaban")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Terima kasih telah mengisi tracer study",
+	})
+}
@@ -0,0 +1,88 @@
+package fiber_inbound_adapter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"prabogo/internal/domain"
+	"prabogo/internal/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type dapodikAdapter struct {
+	domain domain.Domain
+}
+
+func NewDapodikAdapter(d domain.Domain) *dapodikAdapter {
+	return &dapodikAdapter{domain: d}
+}
+
+// GET /api/v1/sekolah/dapodik/export?jenis=siswa|guru|rombel
+func (h *dapodikAdapter) Export(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	jenis := strings.ToLower(c.Query("jenis", model.DapodikJenisSiswa))
+
+	data, err := h.domain.Dapodik().Export(c.Context(), tenantID, jenis)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal export data Dapodik: " + err.Error(),
+		})
+	}
+
+	filename := fmt.Sprintf("dapodik_%s_%s.xlsx", jenis, time.Now().Format("20060102"))
+	c.Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	return c.Send(data)
+}
+
+// POST /api/v1/sekolah/dapodik/import/preview (multipart: file, jenis)
+func (h *dapodikAdapter) PreviewImport(c *fiber.Ctx) error {
+	return h.importFile(c, false)
+}
+
+// POST /api/v1/sekolah/dapodik/import/apply (multipart: file, jenis)
+func (h *dapodikAdapter) ApplyImport(c *fiber.Ctx) error {
+	return h.importFile(c, true)
+}
+
+func (h *dapodikAdapter) importFile(c *fiber.Ctx, apply bool) error {
+	tenantID := c.Locals("tenant_id").(string)
+	jenis := strings.ToLower(c.FormValue("jenis"))
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "File XLSX wajib diunggah",
+		})
+	}
+	file, err := header.Open()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal membaca file",
+		})
+	}
+	defer file.Close()
+
+	var preview *model.DapodikPreview
+	if apply {
+		preview, err = h.domain.Dapodik().Apply(c.Context(), tenantID, jenis, file)
+	} else {
+		preview, err = h.domain.Dapodik().Preview(c.Context(), tenantID, jenis, file)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal impor data Dapodik: " + err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   preview,
+	})
+}
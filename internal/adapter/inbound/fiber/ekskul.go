@@ -0,0 +1,310 @@
+package fiber_inbound_adapter
+
+import (
+	"time"
+
+	"prabogo/internal/domain"
+	"prabogo/internal/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ekskulAdapter struct {
+	domain domain.Domain
+}
+
+func NewEkskulAdapter(d domain.Domain) *ekskulAdapter {
+	return &ekskulAdapter{domain: d}
+}
+
+// ==========================================
+// EKSKUL MASTER HANDLERS
+// ==========================================
+
+// GET /api/v1/sekolah/ekskul
+func (h *ekskulAdapter) GetEkskulList(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	list, err := h.domain.Ekskul().GetEkskulList(ctx, tenantID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data ekskul",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   list,
+	})
+}
+
+// POST /api/v1/sekolah/ekskul
+func (h *ekskulAdapter) CreateEkskul(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input model.Ekskul
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+	input.TenantID = tenantID
+
+	if err := h.domain.Ekskul().CreateEkskul(ctx, &input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal membuat ekskul: " + err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Ekskul berhasil ditambahkan",
+		"data":    input,
+	})
+}
+
+// PUT /api/v1/sekolah/ekskul/:id
+func (h *ekskulAdapter) UpdateEkskul(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input model.Ekskul
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+	input.ID = c.Params("id")
+	input.TenantID = tenantID
+
+	if err := h.domain.Ekskul().UpdateEkskul(ctx, &input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengupdate ekskul: " + err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Ekskul berhasil diupdate",
+		"data":    input,
+	})
+}
+
+// DELETE /api/v1/sekolah/ekskul/:id
+func (h *ekskulAdapter) DeleteEkskul(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	if err := h.domain.Ekskul().DeleteEkskul(ctx, tenantID, c.Params("id")); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal menghapus ekskul",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Ekskul berhasil dihapus",
+	})
+}
+
+// ==========================================
+// ANGGOTA HANDLERS
+// ==========================================
+
+// GET /api/v1/sekolah/ekskul/:id/anggota?semester=2025-2026-1
+func (h *ekskulAdapter) GetAnggota(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+	semesterID := c.Query("semester", "")
+
+	if semesterID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Parameter semester diperlukan",
+		})
+	}
+
+	list, err := h.domain.Ekskul().GetAnggota(ctx, tenantID, c.Params("id"), semesterID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data anggota ekskul",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   list,
+	})
+}
+
+// POST /api/v1/sekolah/ekskul/:id/anggota
+func (h *ekskulAdapter) AddAnggota(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input model.EkskulAnggota
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+	input.TenantID = tenantID
+	input.EkskulID = c.Params("id")
+
+	if err := h.domain.Ekskul().AddAnggota(ctx, &input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal menambahkan anggota: " + err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Anggota berhasil ditambahkan",
+		"data":    input,
+	})
+}
+
+// DELETE /api/v1/sekolah/ekskul/anggota/:anggota_id
+func (h *ekskulAdapter) RemoveAnggota(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	if err := h.domain.Ekskul().RemoveAnggota(ctx, tenantID, c.Params("anggota_id")); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal menghapus anggota",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Anggota berhasil dihapus",
+	})
+}
+
+// ==========================================
+// SESI & PRESENSI HANDLERS
+// ==========================================
+
+// GET /api/v1/sekolah/ekskul/:id/sesi?semester=2025-2026-1
+func (h *ekskulAdapter) GetSesi(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+	semesterID := c.Query("semester", "")
+
+	if semesterID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Parameter semester diperlukan",
+		})
+	}
+
+	list, err := h.domain.Ekskul().GetSesi(ctx, tenantID, c.Params("id"), semesterID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data sesi ekskul",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   list,
+	})
+}
+
+// POST /api/v1/sekolah/ekskul/:id/sesi
+func (h *ekskulAdapter) CreateSesi(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input struct {
+		SemesterID string                 `json:"semester_id"`
+		Tanggal    string                 `json:"tanggal"` // YYYY-MM-DD
+		Materi     string                 `json:"materi"`
+		Presensi   []model.EkskulPresensi `json:"presensi"`
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+
+	tanggal := time.Now()
+	if input.Tanggal != "" {
+		parsed, err := time.Parse("2006-01-02", input.Tanggal)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Format tanggal tidak valid (YYYY-MM-DD)",
+			})
+		}
+		tanggal = parsed
+	}
+
+	sesi := model.EkskulSesi{
+		TenantID:   tenantID,
+		EkskulID:   c.Params("id"),
+		SemesterID: input.SemesterID,
+		Tanggal:    tanggal,
+		Materi:     input.Materi,
+		Presensi:   input.Presensi,
+	}
+
+	if err := h.domain.Ekskul().CreateSesi(ctx, &sesi); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal menyimpan sesi: " + err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Sesi dan presensi berhasil disimpan",
+		"data":    sesi,
+	})
+}
+
+// ==========================================
+// PENILAIAN HANDLERS
+// ==========================================
+
+// POST /api/v1/sekolah/ekskul/penilaian
+func (h *ekskulAdapter) SavePenilaian(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input struct {
+		Items []model.EkskulPenilaianInput `json:"items"`
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+
+	if err := h.domain.Ekskul().SavePenilaian(ctx, tenantID, input.Items); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal menyimpan penilaian: " + err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Penilaian ekskul berhasil disimpan",
+	})
+}
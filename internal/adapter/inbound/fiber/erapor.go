@@ -2,8 +2,12 @@ package fiber_inbound_adapter
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 
 	"prabogo/internal/domain"
+	erapor_domain "prabogo/internal/domain/erapor"
 	"prabogo/internal/model"
 
 	"github.com/gofiber/fiber/v2"
@@ -105,6 +109,46 @@ func (h *eraporAdapter) DeleteSubject(c *fiber.Ctx) error {
 	})
 }
 
+// GET /api/v1/sekolah/erapor/subjects/:id/kkm
+func (h *eraporAdapter) GetKKMHistory(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	history, err := h.domain.ERapor().GetKKMHistory(c.Context(), tenantID, c.Params("id"))
+	if err != nil {
+		return remedialError(c, err, "Gagal mengambil riwayat KKM")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": history,
+	})
+}
+
+// PUT /api/v1/sekolah/erapor/subjects/:id/kkm
+func (h *eraporAdapter) SetSemesterKKM(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input struct {
+		SemesterID string `json:"semester_id"`
+		KKMValue   int    `json:"kkm_value"`
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	history, err := h.domain.ERapor().SetSemesterKKM(c.Context(), tenantID, c.Params("id"), input.SemesterID, input.KKMValue)
+	if err != nil {
+		return remedialError(c, err, "Gagal menyimpan KKM semester")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "KKM semester berhasil disimpan",
+		"data":    history,
+	})
+}
+
 // POST /api/v1/sekolah/erapor/grades
 func (h *eraporAdapter) SaveGrade(c *fiber.Ctx) error {
 	ctx := context.Background()
@@ -119,6 +163,11 @@ func (h *eraporAdapter) SaveGrade(c *fiber.Ctx) error {
 	input.TenantID = tenantID
 
 	grade, err := h.domain.ERapor().SaveGrade(ctx, &input)
+	if errors.Is(err, erapor_domain.ErrNilaiTerkunci) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Gagal menyimpan nilai: " + err.Error(),
+		})
+	}
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Gagal menyimpan nilai: " + err.Error(),
@@ -146,6 +195,11 @@ func (h *eraporAdapter) BatchSaveGrades(c *fiber.Ctx) error {
 	input.TenantID = tenantID
 
 	grades, err := h.domain.ERapor().BatchSaveGrades(ctx, &input)
+	if errors.Is(err, erapor_domain.ErrNilaiTerkunci) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Gagal menyimpan nilai batch: " + err.Error(),
+		})
+	}
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Gagal menyimpan nilai batch: " + err.Error(),
@@ -208,58 +262,356 @@ func (h *eraporAdapter) GetSubjectGrades(c *fiber.Ctx) error {
 	})
 }
 
-// GET /api/v1/sekolah/erapor/rapor/:student_id/:semester
-func (h *eraporAdapter) GetStudentRapor(c *fiber.Ctx) error {
-	ctx := context.Background()
-	studentID := c.Params("student_id")
-	semesterID := c.Params("semester")
+// assessmentError maps assessment domain errors to HTTP status codes
+func assessmentError(c *fiber.Ctx, err error, message string) error {
+	status := fiber.StatusBadRequest
+	switch {
+	case errors.Is(err, erapor_domain.ErrSubjectNotFound), errors.Is(err, erapor_domain.ErrAssessmentNotFound),
+		errors.Is(err, erapor_domain.ErrSiswaNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, erapor_domain.ErrNilaiTerkunci):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"error": message + ": " + err.Error(),
+	})
+}
+
+// GET /api/v1/sekolah/erapor/assessments?semester=&student_id=&subject_id=
+func (h *eraporAdapter) GetAssessments(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	semesterID := c.Query("semester", "")
+
+	if semesterID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Parameter semester diperlukan",
+		})
+	}
 
-	rapor, err := h.domain.ERapor().GetStudentRapor(ctx, studentID, semesterID)
+	entries, err := h.domain.ERapor().GetAssessmentEntries(c.Context(), tenantID, c.Query("student_id"), c.Query("subject_id"), semesterID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Gagal mengambil data rapor",
+			"error": "Gagal mengambil data penilaian",
 		})
 	}
 
 	return c.JSON(fiber.Map{
-		"data": rapor,
+		"data": entries,
 	})
 }
 
-// POST /api/v1/sekolah/erapor/generate
-func (h *eraporAdapter) GenerateRapor(c *fiber.Ctx) error {
-	ctx := context.Background()
+// POST /api/v1/sekolah/erapor/assessments
+func (h *eraporAdapter) CreateAssessment(c *fiber.Ctx) error {
 	tenantID := c.Locals("tenant_id").(string)
 
-	var input struct {
-		StudentID   string `json:"student_id"`
-		SemesterID  string `json:"semester_id"`
-		CatatanWali string `json:"catatan_wali"`
+	var input model.AssessmentEntryInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	input.TenantID = tenantID
+
+	entry, grade, err := h.domain.ERapor().AddAssessmentEntry(c.Context(), &input)
+	if err != nil {
+		return assessmentError(c, err, "Gagal menyimpan penilaian")
 	}
 
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Penilaian berhasil disimpan",
+		"data":    entry,
+		"grade":   grade,
+	})
+}
+
+// PUT /api/v1/sekolah/erapor/assessments/:id
+func (h *eraporAdapter) UpdateAssessment(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input model.AssessmentEntryInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	entry, grade, err := h.domain.ERapor().UpdateAssessmentEntry(c.Context(), tenantID, c.Params("id"), &input)
+	if err != nil {
+		return assessmentError(c, err, "Gagal mengupdate penilaian")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Penilaian berhasil diupdate",
+		"data":    entry,
+		"grade":   grade,
+	})
+}
+
+// DELETE /api/v1/sekolah/erapor/assessments/:id
+func (h *eraporAdapter) DeleteAssessment(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	grade, err := h.domain.ERapor().DeleteAssessmentEntry(c.Context(), tenantID, c.Params("id"))
+	if err != nil {
+		return assessmentError(c, err, "Gagal menghapus penilaian")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Penilaian berhasil dihapus",
+		"grade":   grade,
+	})
+}
+
+// remedialError maps remedial domain errors to HTTP status codes
+func remedialError(c *fiber.Ctx, err error, message string) error {
+	status := fiber.StatusBadRequest
+	switch {
+	case errors.Is(err, erapor_domain.ErrSubjectNotFound), errors.Is(err, erapor_domain.ErrNilaiBelumAda):
+		status = fiber.StatusNotFound
+	case errors.Is(err, erapor_domain.ErrNilaiTerkunci),
+		errors.Is(err, erapor_domain.ErrSudahTuntas),
+		errors.Is(err, erapor_domain.ErrBelumTuntas):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"error": message + ": " + err.Error(),
+	})
+}
+
+// POST /api/v1/sekolah/erapor/remedial
+func (h *eraporAdapter) RecordRemedial(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID, _ := c.Locals("user_id").(string)
+
+	var input model.RemedialInput
 	if err := c.BodyParser(&input); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Invalid request body",
 		})
 	}
+	input.TenantID = tenantID
+	input.CreatedBy = userID
+
+	session, grade, err := h.domain.ERapor().RecordRemedial(c.Context(), &input)
+	if err != nil {
+		return remedialError(c, err, "Gagal menyimpan sesi remedial")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Sesi remedial berhasil disimpan",
+		"data":    session,
+		"grade":   grade,
+	})
+}
+
+// GET /api/v1/sekolah/erapor/remedial?subject_id=&semester=&student_id=
+func (h *eraporAdapter) GetRemedialHistory(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	semesterID := c.Query("semester", "")
+
+	if semesterID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Parameter semester diperlukan",
+		})
+	}
+
+	sessions, err := h.domain.ERapor().GetRemedialHistory(c.Context(), tenantID, c.Query("subject_id"), semesterID, c.Query("student_id"))
+	if err != nil {
+		return remedialError(c, err, "Gagal mengambil riwayat remedial")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": sessions,
+	})
+}
+
+// GET /api/v1/sekolah/erapor/remedial/candidates?subject_id=&semester=&kelas_id=
+func (h *eraporAdapter) GetRemedialCandidates(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	semesterID := c.Query("semester", "")
+
+	if semesterID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Parameter semester diperlukan",
+		})
+	}
+
+	candidates, err := h.domain.ERapor().GetRemedialCandidates(c.Context(), tenantID, c.Query("subject_id"), semesterID, c.Query("kelas_id"))
+	if err != nil {
+		return remedialError(c, err, "Gagal mengambil daftar siswa di bawah KKM")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": candidates,
+	})
+}
+
+// capaianError maps CP/TP domain errors to HTTP status codes
+func capaianError(c *fiber.Ctx, err error, message string) error {
+	status := fiber.StatusBadRequest
+	switch {
+	case errors.Is(err, erapor_domain.ErrSubjectNotFound),
+		errors.Is(err, erapor_domain.ErrCapaianNotFound),
+		errors.Is(err, erapor_domain.ErrTujuanNotFound),
+		errors.Is(err, erapor_domain.ErrNilaiBelumAda):
+		status = fiber.StatusNotFound
+	case errors.Is(err, erapor_domain.ErrNilaiTerkunci):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"error": message + ": " + err.Error(),
+	})
+}
+
+// GET /api/v1/sekolah/erapor/capaian?subject_id=&fase=
+func (h *eraporAdapter) GetCapaianList(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
 
-	rapor, err := h.domain.ERapor().GenerateRapor(ctx, tenantID, input.StudentID, input.SemesterID, input.CatatanWali)
+	list, err := h.domain.ERapor().GetCapaianList(c.Context(), tenantID, c.Query("subject_id"), c.Query("fase"))
 	if err != nil {
+		return capaianError(c, err, "Gagal mengambil capaian pembelajaran")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": list,
+	})
+}
+
+// POST /api/v1/sekolah/erapor/capaian
+func (h *eraporAdapter) CreateCapaian(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var cp model.CapaianPembelajaran
+	if err := c.BodyParser(&cp); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	cp.ID = ""
+	cp.TenantID = tenantID
+
+	if err := h.domain.ERapor().SaveCapaian(c.Context(), &cp); err != nil {
+		return capaianError(c, err, "Gagal menyimpan capaian pembelajaran")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Capaian pembelajaran berhasil dibuat",
+		"data":    cp,
+	})
+}
+
+// PUT /api/v1/sekolah/erapor/capaian/:id
+func (h *eraporAdapter) UpdateCapaian(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var cp model.CapaianPembelajaran
+	if err := c.BodyParser(&cp); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	cp.ID = c.Params("id")
+	cp.TenantID = tenantID
+
+	if err := h.domain.ERapor().SaveCapaian(c.Context(), &cp); err != nil {
+		return capaianError(c, err, "Gagal mengupdate capaian pembelajaran")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Capaian pembelajaran berhasil diupdate",
+		"data":    cp,
+	})
+}
+
+// DELETE /api/v1/sekolah/erapor/capaian/:id
+func (h *eraporAdapter) DeleteCapaian(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	if err := h.domain.ERapor().DeleteCapaian(c.Context(), tenantID, c.Params("id")); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Gagal generate rapor: " + err.Error(),
+			"error": "Gagal menghapus capaian pembelajaran",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Capaian pembelajaran berhasil dihapus",
+	})
+}
+
+// POST /api/v1/sekolah/erapor/capaian/:id/tujuan
+func (h *eraporAdapter) CreateTujuan(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var tp model.TujuanPembelajaran
+	if err := c.BodyParser(&tp); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
 		})
 	}
+	tp.ID = ""
+	tp.TenantID = tenantID
+	tp.CPID = c.Params("id")
+
+	if err := h.domain.ERapor().SaveTujuan(c.Context(), &tp); err != nil {
+		return capaianError(c, err, "Gagal menyimpan tujuan pembelajaran")
+	}
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
 		"status":  "success",
-		"message": "Rapor berhasil digenerate",
-		"data":    rapor,
+		"message": "Tujuan pembelajaran berhasil dibuat",
+		"data":    tp,
 	})
 }
 
-// GET /api/v1/sekolah/erapor/stats
-func (h *eraporAdapter) GetStats(c *fiber.Ctx) error {
-	ctx := context.Background()
+// PUT /api/v1/sekolah/erapor/tujuan/:id
+func (h *eraporAdapter) UpdateTujuan(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var tp model.TujuanPembelajaran
+	if err := c.BodyParser(&tp); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	tp.ID = c.Params("id")
+	tp.TenantID = tenantID
+
+	if err := h.domain.ERapor().SaveTujuan(c.Context(), &tp); err != nil {
+		return capaianError(c, err, "Gagal mengupdate tujuan pembelajaran")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Tujuan pembelajaran berhasil diupdate",
+		"data":    tp,
+	})
+}
+
+// DELETE /api/v1/sekolah/erapor/tujuan/:id
+func (h *eraporAdapter) DeleteTujuan(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	if err := h.domain.ERapor().DeleteTujuan(c.Context(), tenantID, c.Params("id")); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal menghapus tujuan pembelajaran",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Tujuan pembelajaran berhasil dihapus",
+	})
+}
+
+// GET /api/v1/sekolah/erapor/tp-marks?subject_id=&semester=&student_id=
+func (h *eraporAdapter) GetTPMarks(c *fiber.Ctx) error {
 	tenantID := c.Locals("tenant_id").(string)
 	semesterID := c.Query("semester", "")
 
@@ -269,15 +621,1133 @@ func (h *eraporAdapter) GetStats(c *fiber.Ctx) error {
 		})
 	}
 
-	stats, err := h.domain.ERapor().GetGradeStats(ctx, tenantID, semesterID)
+	marks, err := h.domain.ERapor().GetTPAchievements(c.Context(), tenantID, c.Query("subject_id"), semesterID, c.Query("student_id"))
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Gagal mengambil statistik nilai",
+			"error": "Gagal mengambil nilai TP",
 		})
 	}
 
 	return c.JSON(fiber.Map{
-		"data": stats,
+		"data": marks,
+	})
+}
+
+// POST /api/v1/sekolah/erapor/tp-marks
+func (h *eraporAdapter) SaveTPMarks(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input model.TPAchievementInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	input.TenantID = tenantID
+
+	grades, err := h.domain.ERapor().SaveTPAchievements(c.Context(), &input)
+	if err != nil {
+		return capaianError(c, err, "Gagal menyimpan nilai TP")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Nilai TP berhasil disimpan",
+		"data":    grades,
+	})
+}
+
+// POST /api/v1/sekolah/erapor/capaian/generate
+func (h *eraporAdapter) GenerateCapaianDescriptions(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input struct {
+		SubjectID  string `json:"subject_id"`
+		SemesterID string `json:"semester_id"`
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	grades, err := h.domain.ERapor().RegenerateDescriptions(c.Context(), tenantID, input.SubjectID, input.SemesterID)
+	if err != nil {
+		return capaianError(c, err, "Gagal membuat deskripsi capaian")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Deskripsi capaian berhasil dibuat",
+		"count":   len(grades),
+		"data":    grades,
+	})
+}
+
+// PUT /api/v1/sekolah/erapor/grades/description
+func (h *eraporAdapter) UpdateGradeDescription(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input model.GradeDescriptionInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	input.TenantID = tenantID
+
+	grade, err := h.domain.ERapor().UpdateGradeDescription(c.Context(), &input)
+	if err != nil {
+		return capaianError(c, err, "Gagal mengupdate deskripsi capaian")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Deskripsi capaian berhasil diupdate",
+		"data":    grade,
+	})
+}
+
+// GET /api/v1/sekolah/erapor/rapor/:student_id/:semester
+func (h *eraporAdapter) GetStudentRapor(c *fiber.Ctx) error {
+	ctx := context.Background()
+	tenantID := c.Locals("tenant_id").(string)
+	studentID := c.Params("student_id")
+	semesterID := c.Params("semester")
+
+	rapor, err := h.domain.ERapor().GetStudentRapor(ctx, tenantID, studentID, semesterID)
+	if errors.Is(err, erapor_domain.ErrSiswaNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Siswa tidak ditemukan",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil data rapor",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": rapor,
+	})
+}
+
+// POST /api/v1/sekolah/erapor/generate
+func (h *eraporAdapter) GenerateRapor(c *fiber.Ctx) error {
+	ctx := context.Background()
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input struct {
+		StudentID   string               `json:"student_id"`
+		SemesterID  string               `json:"semester_id"`
+		CatatanWali string               `json:"catatan_wali"`
+		Kehadiran   model.AttendanceData `json:"kehadiran"`
+	}
+
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	rapor, err := h.domain.ERapor().GenerateRapor(ctx, tenantID, input.StudentID, input.SemesterID, input.CatatanWali, input.Kehadiran)
+	if errors.Is(err, erapor_domain.ErrSiswaNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Siswa tidak ditemukan",
+		})
+	}
+	if errors.Is(err, erapor_domain.ErrRaporTerkunci) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Gagal generate rapor: " + err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal generate rapor: " + err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Rapor berhasil digenerate",
+		"data":    rapor,
+	})
+}
+
+// GET /api/v1/sekolah/erapor/rapor/:id/cetak
+func (h *eraporAdapter) CetakRapor(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	pdfBytes, err := h.domain.ERapor().CetakRapor(c.Context(), tenantID, c.Params("id"))
+	if errors.Is(err, erapor_domain.ErrRaporNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Rapor tidak ditemukan",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal generate PDF rapor: " + err.Error(),
+		})
+	}
+
+	c.Set("Content-Type", "application/pdf")
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"rapor-%s.pdf\"", c.Params("id")))
+	return c.Send(pdfBytes)
+}
+
+// GET /api/v1/sekolah/erapor/rapor/:id/cetak-p5
+func (h *eraporAdapter) CetakRaporP5(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	pdfBytes, err := h.domain.ERapor().CetakRaporP5(c.Context(), tenantID, c.Params("id"))
+	if errors.Is(err, erapor_domain.ErrRaporNotFound) || errors.Is(err, erapor_domain.ErrP5NotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Rapor P5 tidak ditemukan",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal generate PDF rapor P5: " + err.Error(),
+		})
+	}
+
+	c.Set("Content-Type", "application/pdf")
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"rapor-p5-%s.pdf\"", c.Params("id")))
+	return c.Send(pdfBytes)
+}
+
+// p5Error maps P5 domain errors to HTTP status codes
+func p5Error(c *fiber.Ctx, err error, message string) error {
+	status := fiber.StatusBadRequest
+	switch {
+	case errors.Is(err, erapor_domain.ErrP5NotFound),
+		errors.Is(err, erapor_domain.ErrSiswaNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, erapor_domain.ErrNilaiTerkunci):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"error": message + ": " + err.Error(),
+	})
+}
+
+// GET /api/v1/sekolah/erapor/p5?semester=&kelas_id=
+func (h *eraporAdapter) GetP5ProjekList(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	semesterID := c.Query("semester", "")
+
+	if semesterID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Parameter semester diperlukan",
+		})
+	}
+
+	list, err := h.domain.ERapor().GetP5ProjekList(c.Context(), tenantID, semesterID, c.Query("kelas_id"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil projek P5",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data":    list,
+		"dimensi": model.DimensiP5,
+	})
+}
+
+// GET /api/v1/sekolah/erapor/p5/:id
+func (h *eraporAdapter) GetP5Projek(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	projek, penilaian, err := h.domain.ERapor().GetP5Projek(c.Context(), tenantID, c.Params("id"))
+	if err != nil {
+		return p5Error(c, err, "Gagal mengambil projek P5")
+	}
+
+	return c.JSON(fiber.Map{
+		"data":      projek,
+		"penilaian": penilaian,
+	})
+}
+
+// POST /api/v1/sekolah/erapor/p5
+func (h *eraporAdapter) CreateP5Projek(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var projek model.P5Projek
+	if err := c.BodyParser(&projek); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	projek.ID = ""
+	projek.TenantID = tenantID
+
+	if err := h.domain.ERapor().SaveP5Projek(c.Context(), &projek); err != nil {
+		return p5Error(c, err, "Gagal menyimpan projek P5")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Projek P5 berhasil dibuat",
+		"data":    projek,
+	})
+}
+
+// PUT /api/v1/sekolah/erapor/p5/:id
+func (h *eraporAdapter) UpdateP5Projek(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var projek model.P5Projek
+	if err := c.BodyParser(&projek); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	projek.ID = c.Params("id")
+	projek.TenantID = tenantID
+
+	if err := h.domain.ERapor().SaveP5Projek(c.Context(), &projek); err != nil {
+		return p5Error(c, err, "Gagal mengupdate projek P5")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Projek P5 berhasil diupdate",
+		"data":    projek,
+	})
+}
+
+// DELETE /api/v1/sekolah/erapor/p5/:id
+func (h *eraporAdapter) DeleteP5Projek(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	if err := h.domain.ERapor().DeleteP5Projek(c.Context(), tenantID, c.Params("id")); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal menghapus projek P5",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Projek P5 berhasil dihapus",
+	})
+}
+
+// POST /api/v1/sekolah/erapor/p5/:id/penilaian
+func (h *eraporAdapter) SaveP5Penilaian(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input model.P5PenilaianInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	input.TenantID = tenantID
+	input.ProjekID = c.Params("id")
+
+	penilaian, err := h.domain.ERapor().SaveP5Penilaian(c.Context(), &input)
+	if err != nil {
+		return p5Error(c, err, "Gagal menyimpan penilaian P5")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Penilaian P5 berhasil disimpan",
+		"data":    penilaian,
+	})
+}
+
+// sikapError maps sikap domain errors to HTTP status codes
+func sikapError(c *fiber.Ctx, err error, message string) error {
+	status := fiber.StatusBadRequest
+	switch {
+	case errors.Is(err, erapor_domain.ErrSiswaNotFound),
+		errors.Is(err, erapor_domain.ErrJurnalNotFound),
+		errors.Is(err, erapor_domain.ErrSikapNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, erapor_domain.ErrBukanPencatat):
+		status = fiber.StatusForbidden
+	case errors.Is(err, erapor_domain.ErrNilaiTerkunci):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"error": message + ": " + err.Error(),
+	})
+}
+
+// GET /api/v1/sekolah/erapor/sikap/jurnal?semester=&kelas_id=&student_id=
+func (h *eraporAdapter) GetJurnalSikap(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	semesterID := c.Query("semester", "")
+
+	if semesterID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Parameter semester diperlukan",
+		})
+	}
+
+	list, err := h.domain.ERapor().GetJurnalSikap(c.Context(), tenantID, semesterID, c.Query("kelas_id"), c.Query("student_id"))
+	if err != nil {
+		return sikapError(c, err, "Gagal mengambil jurnal sikap")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": list,
+	})
+}
+
+// POST /api/v1/sekolah/erapor/sikap/jurnal
+func (h *eraporAdapter) CreateJurnalSikap(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID, _ := c.Locals("user_id").(string)
+	role, _ := c.Locals("role").(string)
+
+	var input model.JurnalSikapInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	input.TenantID = tenantID
+	input.ObserverID = userID
+	input.ObserverRole = role
+
+	jurnal, err := h.domain.ERapor().CreateJurnalSikap(c.Context(), &input)
+	if err != nil {
+		return sikapError(c, err, "Gagal menyimpan jurnal sikap")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Jurnal sikap berhasil disimpan",
+		"data":    jurnal,
+	})
+}
+
+// DELETE /api/v1/sekolah/erapor/sikap/jurnal/:id
+func (h *eraporAdapter) DeleteJurnalSikap(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID, _ := c.Locals("user_id").(string)
+	role, _ := c.Locals("role").(string)
+
+	if err := h.domain.ERapor().DeleteJurnalSikap(c.Context(), tenantID, c.Params("id"), userID, role); err != nil {
+		return sikapError(c, err, "Gagal menghapus jurnal sikap")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Jurnal sikap berhasil dihapus",
+	})
+}
+
+// GET /api/v1/sekolah/erapor/sikap?semester=&kelas_id=&student_id=
+func (h *eraporAdapter) GetSikapSiswa(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	semesterID := c.Query("semester", "")
+
+	if semesterID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Parameter semester diperlukan",
+		})
+	}
+
+	list, err := h.domain.ERapor().GetSikapSiswa(c.Context(), tenantID, semesterID, c.Query("kelas_id"), c.Query("student_id"))
+	if err != nil {
+		return sikapError(c, err, "Gagal mengambil sikap siswa")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": list,
+	})
+}
+
+// POST /api/v1/sekolah/erapor/sikap/rekap
+func (h *eraporAdapter) RekapSikap(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input model.SikapRekapInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	input.TenantID = tenantID
+
+	list, err := h.domain.ERapor().RekapSikap(c.Context(), &input)
+	if err != nil {
+		return sikapError(c, err, "Gagal merekap sikap")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Rekap sikap berhasil dibuat",
+		"data":    list,
+	})
+}
+
+// PUT /api/v1/sekolah/erapor/sikap/:id
+func (h *eraporAdapter) UpdateSikapSiswa(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input struct {
+		Predikat  string `json:"predikat"`
+		Deskripsi string `json:"deskripsi"`
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	sikap, err := h.domain.ERapor().UpdateSikapSiswa(c.Context(), tenantID, c.Params("id"), input.Predikat, input.Deskripsi)
+	if err != nil {
+		return sikapError(c, err, "Gagal mengupdate sikap siswa")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Sikap siswa berhasil diupdate",
+		"data":    sikap,
+	})
+}
+
+// pesantrenError maps predicate set and tahfidz domain errors to HTTP status codes
+func pesantrenError(c *fiber.Ctx, err error, message string) error {
+	status := fiber.StatusBadRequest
+	switch {
+	case errors.Is(err, erapor_domain.ErrSubjectNotFound),
+		errors.Is(err, erapor_domain.ErrSiswaNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, erapor_domain.ErrNilaiTerkunci):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"error": message + ": " + err.Error(),
+	})
+}
+
+// GET /api/v1/sekolah/erapor/predikat
+func (h *eraporAdapter) GetPredicateSets(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	sets, err := h.domain.ERapor().GetPredicateSets(c.Context(), tenantID)
+	if err != nil {
+		return pesantrenError(c, err, "Gagal mengambil skala predikat")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": sets,
+	})
+}
+
+// PUT /api/v1/sekolah/erapor/predikat/:subject_type
+func (h *eraporAdapter) SavePredicateSet(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input model.PredicateSet
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	input.TenantID = tenantID
+	input.SubjectType = c.Params("subject_type")
+
+	if err := h.domain.ERapor().SavePredicateSet(c.Context(), &input); err != nil {
+		return pesantrenError(c, err, "Gagal menyimpan skala predikat")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Skala predikat berhasil disimpan",
+		"data":    input,
+	})
+}
+
+// DELETE /api/v1/sekolah/erapor/predikat/:subject_type
+func (h *eraporAdapter) DeletePredicateSet(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	if err := h.domain.ERapor().DeletePredicateSet(c.Context(), tenantID, c.Params("subject_type")); err != nil {
+		return pesantrenError(c, err, "Gagal menghapus skala predikat")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Skala predikat dikembalikan ke bawaan",
+	})
+}
+
+// POST /api/v1/sekolah/erapor/tahfidz/hitung
+func (h *eraporAdapter) HitungTahfidz(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input struct {
+		SubjectID  string `json:"subject_id"`
+		SemesterID string `json:"semester_id"`
+		KelasID    string `json:"kelas_id"`
+		StudentID  string `json:"student_id"`
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	grades, err := h.domain.ERapor().HitungTahfidz(c.Context(), tenantID, input.SubjectID, input.SemesterID, input.KelasID, input.StudentID)
+	if err != nil {
+		return pesantrenError(c, err, "Gagal menghitung nilai tahfidz")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Nilai tahfidz berhasil dihitung",
+		"data":    grades,
+	})
+}
+
+// legerError maps leger domain errors to HTTP status codes
+func legerError(c *fiber.Ctx, err error, message string) error {
+	status := fiber.StatusBadRequest
+	if errors.Is(err, erapor_domain.ErrKelasNotFound) {
+		status = fiber.StatusNotFound
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"error": message + ": " + err.Error(),
+	})
+}
+
+// legerOptions reads ?tie_rule=standar|padat|kehadiran&exclude=Diniyah,Tahfidz
+func legerOptions(c *fiber.Ctx) model.LegerOptions {
+	opts := model.LegerOptions{TieRule: c.Query("tie_rule")}
+	for _, group := range strings.Split(c.Query("exclude"), ",") {
+		if group = strings.TrimSpace(group); group != "" {
+			opts.ExcludeGroups = append(opts.ExcludeGroups, group)
+		}
+	}
+	return opts
+}
+
+// GET /api/v1/sekolah/erapor/leger?kelas_id=&semester=&tie_rule=&exclude=
+func (h *eraporAdapter) GetLeger(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	kelasID := c.Query("kelas_id")
+	semesterID := c.Query("semester")
+
+	if kelasID == "" || semesterID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Parameter kelas_id dan semester diperlukan",
+		})
+	}
+
+	leger, err := h.domain.ERapor().GetLeger(c.Context(), tenantID, kelasID, semesterID, legerOptions(c))
+	if err != nil {
+		return legerError(c, err, "Gagal menyusun leger nilai")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": leger,
+	})
+}
+
+// GET /api/v1/sekolah/erapor/leger/export?kelas_id=&semester=&format=xlsx|pdf&tie_rule=&exclude=
+func (h *eraporAdapter) ExportLeger(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	kelasID := c.Query("kelas_id")
+	semesterID := c.Query("semester")
+
+	if kelasID == "" || semesterID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Parameter kelas_id dan semester diperlukan",
+		})
+	}
+
+	format := c.Query("format", "xlsx")
+	content, filename, err := h.domain.ERapor().ExportLeger(c.Context(), tenantID, kelasID, semesterID, legerOptions(c), format)
+	if err != nil {
+		return legerError(c, err, "Gagal mengekspor leger nilai")
+	}
+
+	contentType := "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	if format == "pdf" {
+		contentType = "application/pdf"
+	}
+	c.Set("Content-Type", contentType)
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	return c.Send(content)
+}
+
+// kurikulumError maps curriculum profile domain errors to HTTP status codes
+func kurikulumError(c *fiber.Ctx, err error, message string) error {
+	status := fiber.StatusBadRequest
+	switch {
+	case errors.Is(err, erapor_domain.ErrKurikulumNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, erapor_domain.ErrKurikulumSistem),
+		errors.Is(err, erapor_domain.ErrKurikulumDipakai):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"error": message + ": " + err.Error(),
+	})
+}
+
+// GET /api/v1/sekolah/erapor/kurikulum
+func (h *eraporAdapter) GetCurriculumProfiles(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	profiles, err := h.domain.ERapor().GetCurriculumProfiles(c.Context(), tenantID)
+	if err != nil {
+		return kurikulumError(c, err, "Gagal mengambil profil kurikulum")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": profiles,
+	})
+}
+
+// POST /api/v1/sekolah/erapor/kurikulum
+// PUT /api/v1/sekolah/erapor/kurikulum/:id
+func (h *eraporAdapter) SaveCurriculumProfile(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input model.CurriculumProfile
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	input.TenantID = tenantID
+	input.ID = c.Params("id")
+	input.IsSystem = false
+
+	status := fiber.StatusOK
+	if input.ID == "" {
+		status = fiber.StatusCreated
+	}
+	if err := h.domain.ERapor().SaveCurriculumProfile(c.Context(), &input); err != nil {
+		return kurikulumError(c, err, "Gagal menyimpan profil kurikulum")
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Profil kurikulum berhasil disimpan",
+		"data":    input,
+	})
+}
+
+// DELETE /api/v1/sekolah/erapor/kurikulum/:id
+func (h *eraporAdapter) DeleteCurriculumProfile(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	if err := h.domain.ERapor().DeleteCurriculumProfile(c.Context(), tenantID, c.Params("id")); err != nil {
+		return kurikulumError(c, err, "Gagal menghapus profil kurikulum")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Profil kurikulum berhasil dihapus",
+	})
+}
+
+// GET /api/v1/sekolah/erapor/kurikulum/jenjang
+func (h *eraporAdapter) GetTenantCurricula(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	selected, jenjangs, err := h.domain.ERapor().GetTenantCurricula(c.Context(), tenantID)
+	if err != nil {
+		return kurikulumError(c, err, "Gagal mengambil kurikulum per jenjang")
+	}
+
+	return c.JSON(fiber.Map{
+		"data":    selected,
+		"jenjang": jenjangs,
+	})
+}
+
+// PUT /api/v1/sekolah/erapor/kurikulum/jenjang
+// Body: {"jenjang": "SMP", "curriculum_code": "MERDEKA"}; an empty jenjang sets the default
+func (h *eraporAdapter) SetTenantCurriculum(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input struct {
+		Jenjang        string `json:"jenjang"`
+		CurriculumCode string `json:"curriculum_code"`
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	selected, err := h.domain.ERapor().SetTenantCurriculum(c.Context(), tenantID, input.Jenjang, input.CurriculumCode)
+	if err != nil {
+		return kurikulumError(c, err, "Gagal memilih kurikulum")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Kurikulum berhasil dipilih",
+		"data":    selected,
+	})
+}
+
+// DELETE /api/v1/sekolah/erapor/kurikulum/jenjang/:jenjang
+func (h *eraporAdapter) DeleteTenantCurriculum(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	if err := h.domain.ERapor().DeleteTenantCurriculum(c.Context(), tenantID, c.Params("jenjang")); err != nil {
+		return kurikulumError(c, err, "Gagal menghapus pilihan kurikulum")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Jenjang kembali memakai kurikulum bawaan",
+	})
+}
+
+// raporStatusError maps lifecycle domain errors to HTTP status codes
+func raporStatusError(c *fiber.Ctx, err error, message string) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, erapor_domain.ErrRaporNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, erapor_domain.ErrTransisiTidakValid):
+		status = fiber.StatusConflict
+	case errors.Is(err, erapor_domain.ErrAlasanWajib):
+		status = fiber.StatusBadRequest
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"error": message + ": " + err.Error(),
+	})
+}
+
+// POST /api/v1/sekolah/erapor/rapor/:id/submit
+func (h *eraporAdapter) SubmitRapor(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID, _ := c.Locals("user_id").(string)
+
+	rapor, err := h.domain.ERapor().SubmitRapor(c.Context(), tenantID, c.Params("id"), userID)
+	if err != nil {
+		return raporStatusError(c, err, "Gagal mengajukan rapor")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Rapor diajukan, nilai siswa dikunci",
+		"data":    rapor,
+	})
+}
+
+// POST /api/v1/sekolah/erapor/rapor/:id/approve
+func (h *eraporAdapter) ApproveRapor(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID, _ := c.Locals("user_id").(string)
+
+	rapor, err := h.domain.ERapor().ApproveRapor(c.Context(), tenantID, c.Params("id"), userID)
+	if err != nil {
+		return raporStatusError(c, err, "Gagal menyetujui rapor")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Rapor disetujui",
+		"data":    rapor,
+	})
+}
+
+// POST /api/v1/sekolah/erapor/rapor/:id/publish
+func (h *eraporAdapter) PublishRapor(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID, _ := c.Locals("user_id").(string)
+
+	rapor, err := h.domain.ERapor().PublishRapor(c.Context(), tenantID, c.Params("id"), userID)
+	if err != nil {
+		return raporStatusError(c, err, "Gagal menerbitkan rapor")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Rapor diterbitkan ke portal wali",
+		"data":    rapor,
+	})
+}
+
+// POST /api/v1/sekolah/erapor/rapor/:id/unlock
+func (h *eraporAdapter) UnlockRapor(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID, _ := c.Locals("user_id").(string)
+
+	var input struct {
+		Alasan string `json:"alasan"`
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	rapor, err := h.domain.ERapor().UnlockRapor(c.Context(), tenantID, c.Params("id"), userID, input.Alasan)
+	if err != nil {
+		return raporStatusError(c, err, "Gagal membuka kunci rapor")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Kunci rapor dibuka, rapor kembali ke Draft",
+		"data":    rapor,
+	})
+}
+
+// GET /api/v1/sekolah/erapor/rapor/:id/riwayat
+func (h *eraporAdapter) GetRaporRiwayat(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	riwayat, err := h.domain.ERapor().GetRaporRiwayat(c.Context(), tenantID, c.Params("id"))
+	if err != nil {
+		return raporStatusError(c, err, "Gagal mengambil riwayat status rapor")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": riwayat,
+	})
+}
+
+// GET /api/v1/sekolah/portal/rapor
+func (h *eraporAdapter) GetRaporWaliList(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID, _ := c.Locals("user_id").(string)
+
+	rapors, err := h.domain.ERapor().GetRaporWaliList(c.Context(), tenantID, userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil rapor anak",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": rapors,
+	})
+}
+
+// GET /api/v1/sekolah/portal/rapor/:id/cetak
+func (h *eraporAdapter) CetakRaporWali(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID, _ := c.Locals("user_id").(string)
+
+	pdfBytes, err := h.domain.ERapor().CetakRaporWali(c.Context(), tenantID, userID, c.Params("id"))
+	if errors.Is(err, erapor_domain.ErrRaporNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Rapor tidak ditemukan",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal generate PDF rapor: " + err.Error(),
+		})
+	}
+
+	c.Set("Content-Type", "application/pdf")
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"rapor-%s.pdf\"", c.Params("id")))
+	return c.Send(pdfBytes)
+}
+
+// raporBatchError maps batch domain errors to HTTP status codes
+func raporBatchError(c *fiber.Ctx, err error, message string) error {
+	status := fiber.StatusBadRequest
+	switch {
+	case errors.Is(err, erapor_domain.ErrBatchNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, erapor_domain.ErrBatchBelumSelesai):
+		status = fiber.StatusConflict
+	case errors.Is(err, erapor_domain.ErrWorkerTidakTersedia):
+		status = fiber.StatusServiceUnavailable
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"error": message + ": " + err.Error(),
+	})
+}
+
+// POST /api/v1/sekolah/erapor/batch
+func (h *eraporAdapter) CreateRaporBatch(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input model.RaporBatchInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	batch, err := h.domain.ERapor().CreateBatch(c.Context(), tenantID, input)
+	if err != nil {
+		return raporBatchError(c, err, "Gagal membuat batch rapor")
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Batch rapor masuk antrian",
+		"data":    batch,
+	})
+}
+
+// GET /api/v1/sekolah/erapor/batch
+func (h *eraporAdapter) GetRaporBatchList(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	batches, err := h.domain.ERapor().GetBatchList(c.Context(), tenantID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Gagal mengambil daftar batch rapor",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": batches,
+	})
+}
+
+// GET /api/v1/sekolah/erapor/batch/:id
+func (h *eraporAdapter) GetRaporBatch(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	batch, err := h.domain.ERapor().GetBatch(c.Context(), tenantID, c.Params("id"))
+	if err != nil {
+		return raporBatchError(c, err, "Gagal mengambil batch rapor")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": batch,
+	})
+}
+
+// GET /api/v1/sekolah/erapor/batch/:id/download?jenis=zip|pdf
+func (h *eraporAdapter) DownloadRaporBatch(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	jenis := c.Query("jenis", model.RaporBatchFileZip)
+
+	file, batch, err := h.domain.ERapor().DownloadBatch(c.Context(), tenantID, c.Params("id"), jenis)
+	if err != nil {
+		return raporBatchError(c, err, "Gagal mengunduh batch rapor")
+	}
+
+	contentType := "application/zip"
+	if jenis == model.RaporBatchFilePDF {
+		contentType = "application/pdf"
+	}
+	c.Set("Content-Type", contentType)
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"rapor-%s-%s.%s\"", batch.KelasNama, batch.SemesterID, jenis))
+	return c.Send(file)
+}
+
+// statistikError maps grade statistics domain errors to HTTP status codes
+func statistikError(c *fiber.Ctx, err error, message string) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, erapor_domain.ErrSubjectNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, erapor_domain.ErrLebarKelasTidakSah):
+		status = fiber.StatusBadRequest
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"error": message + ": " + err.Error(),
+	})
+}
+
+// GET /api/v1/sekolah/erapor/stats?semester=&subject_id=&kelas_id=&tingkat=
+func (h *eraporAdapter) GetStats(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	semesterID := c.Query("semester", "")
+
+	if semesterID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Parameter semester diperlukan",
+		})
+	}
+
+	filter := model.GradeStatsFilter{
+		SubjectID: c.Query("subject_id"),
+		KelasID:   c.Query("kelas_id"),
+		Tingkat:   c.Query("tingkat"),
+	}
+	stats, err := h.domain.ERapor().GetGradeStats(c.Context(), tenantID, semesterID, filter)
+	if err != nil {
+		return statistikError(c, err, "Gagal mengambil statistik nilai")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": stats,
+	})
+}
+
+// GET /api/v1/sekolah/erapor/stats/kelas?semester=&tingkat=&subject_id=
+func (h *eraporAdapter) CompareKelasStats(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	semesterID := c.Query("semester")
+	tingkat := c.Query("tingkat")
+
+	if semesterID == "" || tingkat == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Parameter semester dan tingkat diperlukan",
+		})
+	}
+
+	stats, err := h.domain.ERapor().CompareKelas(c.Context(), tenantID, semesterID, tingkat, c.Query("subject_id"))
+	if err != nil {
+		return statistikError(c, err, "Gagal membandingkan kelas")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": stats,
+	})
+}
+
+// GET /api/v1/sekolah/erapor/stats/semester?semester=2024-2025-1,2024-2025-2&subject_id=&kelas_id=&tingkat=
+func (h *eraporAdapter) CompareSemesterStats(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	filter := model.GradeStatsFilter{
+		SubjectID: c.Query("subject_id"),
+		KelasID:   c.Query("kelas_id"),
+		Tingkat:   c.Query("tingkat"),
+	}
+	for _, semesterID := range strings.Split(c.Query("semester"), ",") {
+		if semesterID = strings.TrimSpace(semesterID); semesterID != "" {
+			filter.SemesterIDs = append(filter.SemesterIDs, semesterID)
+		}
+	}
+
+	stats, err := h.domain.ERapor().CompareSemesters(c.Context(), tenantID, filter)
+	if err != nil {
+		return statistikError(c, err, "Gagal membandingkan semester")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": stats,
+	})
+}
+
+// GET /api/v1/sekolah/erapor/stats/histogram?subject_id=&semester=&kelas_id=&lebar=10
+func (h *eraporAdapter) GetGradeHistogram(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	subjectID := c.Query("subject_id")
+	semesterID := c.Query("semester")
+
+	if subjectID == "" || semesterID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Parameter subject_id dan semester diperlukan",
+		})
+	}
+
+	histogram, err := h.domain.ERapor().GetGradeHistogram(c.Context(), tenantID, subjectID, semesterID, c.Query("kelas_id"), c.QueryInt("lebar", 10))
+	if err != nil {
+		return statistikError(c, err, "Gagal menyusun histogram nilai")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": histogram,
 	})
 }
 
@@ -0,0 +1,262 @@
+package fiber_inbound_adapter
+
+import (
+	"errors"
+	"time"
+
+	"prabogo/internal/domain"
+	konseling_domain "prabogo/internal/domain/konseling"
+	"prabogo/internal/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type konselingAdapter struct {
+	domain domain.Domain
+}
+
+func NewKonselingAdapter(d domain.Domain) *konselingAdapter {
+	return &konselingAdapter{domain: d}
+}
+
+// konselingError maps domain errors to HTTP status codes
+func konselingError(c *fiber.Ctx, err error, message string) error {
+	status := fiber.StatusBadRequest
+	switch {
+	case errors.Is(err, konseling_domain.ErrKasusNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, konseling_domain.ErrBukanWaliKelas):
+		status = fiber.StatusForbidden
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"status":  "error",
+		"message": message + ": " + err.Error(),
+	})
+}
+
+// ==========================================
+// KASUS HANDLERS
+// ==========================================
+
+// GET /api/v1/sekolah/konseling/kasus?santri_id=
+func (h *konselingAdapter) GetKasusList(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	list, err := h.domain.Konseling().GetKasusList(ctx, tenantID, c.Query("santri_id"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data kasus konseling",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   list,
+	})
+}
+
+// GET /api/v1/sekolah/konseling/kasus/:id
+func (h *konselingAdapter) GetKasusDetail(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	detail, err := h.domain.Konseling().GetKasusDetail(ctx, tenantID, c.Params("id"))
+	if err != nil {
+		return konselingError(c, err, "Gagal mengambil detail kasus")
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   detail,
+	})
+}
+
+// POST /api/v1/sekolah/konseling/kasus
+func (h *konselingAdapter) CreateKasus(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input model.KonselingKasus
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+	input.TenantID = tenantID
+	if userID, ok := c.Locals("user_id").(string); ok {
+		input.KonselorID = userID
+	}
+
+	if err := h.domain.Konseling().CreateKasus(ctx, &input); err != nil {
+		return konselingError(c, err, "Gagal membuat kasus konseling")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Kasus konseling berhasil dibuat",
+		"data":    input,
+	})
+}
+
+// PUT /api/v1/sekolah/konseling/kasus/:id
+func (h *konselingAdapter) UpdateKasus(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input model.KonselingKasus
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+	input.ID = c.Params("id")
+	input.TenantID = tenantID
+
+	if err := h.domain.Konseling().UpdateKasus(ctx, &input); err != nil {
+		return konselingError(c, err, "Gagal mengupdate kasus konseling")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Kasus konseling berhasil diupdate",
+		"data":    input,
+	})
+}
+
+// ==========================================
+// SESI HANDLERS
+// ==========================================
+
+// POST /api/v1/sekolah/konseling/kasus/:id/sesi
+func (h *konselingAdapter) AddSesi(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input struct {
+		Tanggal             string `json:"tanggal"` // YYYY-MM-DD
+		Catatan             string `json:"catatan"`
+		TindakLanjut        string `json:"tindak_lanjut"`
+		TanggalTindakLanjut string `json:"tanggal_tindak_lanjut"`
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+
+	sesi := model.KonselingSesi{
+		KasusID:      c.Params("id"),
+		Catatan:      input.Catatan,
+		TindakLanjut: input.TindakLanjut,
+	}
+	if userID, ok := c.Locals("user_id").(string); ok {
+		sesi.KonselorID = userID
+	}
+	if input.Tanggal != "" {
+		t, err := time.Parse("2006-01-02", input.Tanggal)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Format tanggal tidak valid (YYYY-MM-DD)",
+			})
+		}
+		sesi.Tanggal = t
+	}
+	if input.TanggalTindakLanjut != "" {
+		t, err := time.Parse("2006-01-02", input.TanggalTindakLanjut)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Format tanggal tindak lanjut tidak valid (YYYY-MM-DD)",
+			})
+		}
+		sesi.TanggalTindakLanjut = &t
+	}
+
+	if err := h.domain.Konseling().AddSesi(ctx, tenantID, &sesi); err != nil {
+		return konselingError(c, err, "Gagal menyimpan sesi konseling")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Sesi konseling berhasil disimpan",
+		"data":    sesi,
+	})
+}
+
+// PUT /api/v1/sekolah/konseling/kasus/:id/sesi/:sesi_id/tindak-lanjut
+func (h *konselingAdapter) SetTindakLanjutSelesai(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input struct {
+		Selesai bool `json:"selesai"`
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+
+	if err := h.domain.Konseling().SetTindakLanjutSelesai(ctx, tenantID, c.Params("id"), c.Params("sesi_id"), input.Selesai); err != nil {
+		return konselingError(c, err, "Gagal mengupdate tindak lanjut")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Tindak lanjut berhasil diupdate",
+	})
+}
+
+// POST /api/v1/sekolah/konseling/kasus/:id/pelanggaran
+func (h *konselingAdapter) LinkPelanggaran(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input struct {
+		PelanggaranIDs []string `json:"pelanggaran_ids"`
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+
+	if err := h.domain.Konseling().LinkPelanggaran(ctx, tenantID, c.Params("id"), input.PelanggaranIDs); err != nil {
+		return konselingError(c, err, "Gagal menautkan pelanggaran")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Pelanggaran berhasil ditautkan",
+	})
+}
+
+// ==========================================
+// RINGKASAN HANDLERS
+// ==========================================
+
+// GET /api/v1/sekolah/konseling/ringkasan?kelas_id=
+func (h *konselingAdapter) GetRingkasanKelas(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+	userID, _ := c.Locals("user_id").(string)
+	role, _ := c.Locals("role").(string)
+
+	list, err := h.domain.Konseling().GetRingkasanKelas(ctx, tenantID, c.Query("kelas_id"), userID, role)
+	if err != nil {
+		return konselingError(c, err, "Gagal mengambil ringkasan konseling")
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   list,
+	})
+}
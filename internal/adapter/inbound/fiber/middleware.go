@@ -185,3 +185,28 @@ func RequirePlan(requiredPlans ...string) fiber.Handler {
 		})
 	}
 }
+
+// RequireRole creates middleware that restricts a route group to specific user roles
+// Used for confidential modules (e.g. BK counseling) where the plan check is not enough
+func RequireRole(allowedRoles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		role, ok := c.Locals("role").(string)
+		if !ok || role == "" {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Akses ditolak. Informasi peran pengguna tidak ditemukan.",
+			})
+		}
+
+		for _, allowed := range allowedRoles {
+			if role == allowed {
+				return c.Next()
+			}
+		}
+
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error":         "Anda tidak memiliki akses ke fitur ini",
+			"required_role": allowedRoles,
+			"your_role":     role,
+		})
+	}
+}
@@ -0,0 +1,434 @@
+package fiber_inbound_adapter
+
+import (
+	"errors"
+	"time"
+
+	"prabogo/internal/domain"
+	perpustakaan_domain "prabogo/internal/domain/perpustakaan"
+	"prabogo/internal/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type perpustakaanAdapter struct {
+	domain domain.Domain
+}
+
+func NewPerpustakaanAdapter(d domain.Domain) *perpustakaanAdapter {
+	return &perpustakaanAdapter{domain: d}
+}
+
+// perpustakaanError maps domain errors to HTTP status codes
+func perpustakaanError(c *fiber.Ctx, err error, message string) error {
+	status := fiber.StatusBadRequest
+	switch {
+	case errors.Is(err, perpustakaan_domain.ErrBukuNotFound),
+		errors.Is(err, perpustakaan_domain.ErrEksemplarNotFound),
+		errors.Is(err, perpustakaan_domain.ErrPeminjamanNotFound):
+		status = fiber.StatusNotFound
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"status":  "error",
+		"message": message + ": " + err.Error(),
+	})
+}
+
+// ==========================================
+// KATALOG HANDLERS
+// ==========================================
+
+// GET /api/v1/sekolah/perpustakaan/buku?q=
+func (h *perpustakaanAdapter) GetBukuList(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	list, err := h.domain.Perpustakaan().GetBukuList(ctx, tenantID, c.Query("q"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil katalog buku",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   list,
+	})
+}
+
+// GET /api/v1/sekolah/perpustakaan/buku/:id
+func (h *perpustakaanAdapter) GetBukuDetail(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	buku, eksemplar, err := h.domain.Perpustakaan().GetBukuDetail(ctx, tenantID, c.Params("id"))
+	if err != nil {
+		return perpustakaanError(c, err, "Gagal mengambil detail buku")
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data": fiber.Map{
+			"buku":      buku,
+			"eksemplar": eksemplar,
+		},
+	})
+}
+
+// POST /api/v1/sekolah/perpustakaan/buku
+func (h *perpustakaanAdapter) CreateBuku(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input model.Buku
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+	input.TenantID = tenantID
+
+	if err := h.domain.Perpustakaan().CreateBuku(ctx, &input); err != nil {
+		return perpustakaanError(c, err, "Gagal menambahkan buku")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Buku berhasil ditambahkan",
+		"data":    input,
+	})
+}
+
+// PUT /api/v1/sekolah/perpustakaan/buku/:id
+func (h *perpustakaanAdapter) UpdateBuku(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input model.Buku
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+	input.ID = c.Params("id")
+	input.TenantID = tenantID
+
+	if err := h.domain.Perpustakaan().UpdateBuku(ctx, &input); err != nil {
+		return perpustakaanError(c, err, "Gagal mengupdate buku")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Buku berhasil diupdate",
+		"data":    input,
+	})
+}
+
+// DELETE /api/v1/sekolah/perpustakaan/buku/:id
+func (h *perpustakaanAdapter) DeleteBuku(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	if err := h.domain.Perpustakaan().DeleteBuku(ctx, tenantID, c.Params("id")); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal menghapus buku",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Buku berhasil dihapus",
+	})
+}
+
+// POST /api/v1/sekolah/perpustakaan/buku/:id/eksemplar
+func (h *perpustakaanAdapter) AddEksemplar(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input model.BukuEksemplar
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+	input.TenantID = tenantID
+	input.BukuID = c.Params("id")
+
+	if err := h.domain.Perpustakaan().AddEksemplar(ctx, &input); err != nil {
+		return perpustakaanError(c, err, "Gagal menambahkan eksemplar")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Eksemplar berhasil ditambahkan",
+		"data":    input,
+	})
+}
+
+// GET /api/v1/sekolah/perpustakaan/anggota?q=
+func (h *perpustakaanAdapter) SearchAnggota(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	list, err := h.domain.Perpustakaan().SearchAnggota(ctx, tenantID, c.Query("q"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mencari anggota",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   list,
+	})
+}
+
+// ==========================================
+// SIRKULASI HANDLERS
+// ==========================================
+
+// GET /api/v1/sekolah/perpustakaan/peminjaman?status=&anggota_tipe=&anggota_id=
+func (h *perpustakaanAdapter) GetPeminjamanList(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	filter := model.PeminjamanFilter{
+		TenantID:    tenantID,
+		Status:      c.Query("status"),
+		AnggotaTipe: c.Query("anggota_tipe"),
+		AnggotaID:   c.Query("anggota_id"),
+	}
+
+	list, err := h.domain.Perpustakaan().GetPeminjamanList(ctx, filter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data peminjaman",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   list,
+	})
+}
+
+// POST /api/v1/sekolah/perpustakaan/peminjaman
+func (h *perpustakaanAdapter) Pinjam(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input model.PeminjamanInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+	input.TenantID = tenantID
+
+	peminjaman, err := h.domain.Perpustakaan().Pinjam(ctx, &input)
+	if err != nil {
+		return perpustakaanError(c, err, "Gagal memproses peminjaman")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Peminjaman berhasil dicatat",
+		"data":    peminjaman,
+	})
+}
+
+// POST /api/v1/sekolah/perpustakaan/peminjaman/:id/kembali
+func (h *perpustakaanAdapter) Kembalikan(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input struct {
+		Hilang bool `json:"hilang"`
+	}
+	_ = c.BodyParser(&input) // Body is optional
+
+	peminjaman, err := h.domain.Perpustakaan().Kembalikan(ctx, tenantID, c.Params("id"), input.Hilang)
+	if err != nil {
+		return perpustakaanError(c, err, "Gagal memproses pengembalian")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Pengembalian berhasil dicatat",
+		"data":    peminjaman,
+	})
+}
+
+// POST /api/v1/sekolah/perpustakaan/peminjaman/:id/perpanjang
+func (h *perpustakaanAdapter) Perpanjang(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	peminjaman, err := h.domain.Perpustakaan().Perpanjang(ctx, tenantID, c.Params("id"))
+	if err != nil {
+		return perpustakaanError(c, err, "Gagal memperpanjang peminjaman")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Peminjaman berhasil diperpanjang",
+		"data":    peminjaman,
+	})
+}
+
+// POST /api/v1/sekolah/perpustakaan/peminjaman/:id/bayar-denda
+func (h *perpustakaanAdapter) BayarDenda(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	if err := h.domain.Perpustakaan().BayarDenda(ctx, tenantID, c.Params("id")); err != nil {
+		return perpustakaanError(c, err, "Gagal mencatat pembayaran denda")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Denda berhasil dilunasi",
+	})
+}
+
+// ==========================================
+// LAPORAN HANDLERS
+// ==========================================
+
+// GET /api/v1/sekolah/perpustakaan/laporan/terlambat
+func (h *perpustakaanAdapter) GetOverdue(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	list, err := h.domain.Perpustakaan().GetOverdue(ctx, tenantID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data keterlambatan",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   list,
+	})
+}
+
+// GET /api/v1/sekolah/perpustakaan/laporan/populer?from=2026-01-01&to=2026-06-30&limit=10
+func (h *perpustakaanAdapter) GetBukuPopuler(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	to := time.Now()
+	from := to.AddDate(0, -1, 0)
+	if v := c.Query("from"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Format tanggal tidak valid (YYYY-MM-DD)",
+			})
+		}
+		from = t
+	}
+	if v := c.Query("to"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Format tanggal tidak valid (YYYY-MM-DD)",
+			})
+		}
+		to = t
+	}
+
+	list, err := h.domain.Perpustakaan().GetBukuPopuler(ctx, tenantID, from, to, c.QueryInt("limit", 10))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil laporan buku populer",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   list,
+	})
+}
+
+// POST /api/v1/sekolah/perpustakaan/laporan/terlambat/notifikasi
+func (h *perpustakaanAdapter) SendOverdueNotices(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	sent, err := h.domain.Perpustakaan().SendOverdueNotices(ctx, tenantID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengirim notifikasi keterlambatan: " + err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Notifikasi keterlambatan terkirim",
+		"count":   sent,
+	})
+}
+
+// ==========================================
+// CONFIG HANDLERS
+// ==========================================
+
+// GET /api/v1/sekolah/perpustakaan/config
+func (h *perpustakaanAdapter) GetConfig(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	config, err := h.domain.Perpustakaan().GetConfig(ctx, tenantID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil konfigurasi perpustakaan",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   config,
+	})
+}
+
+// PUT /api/v1/sekolah/perpustakaan/config
+func (h *perpustakaanAdapter) SaveConfig(c *fiber.Ctx) error {
+	ctx := c.Context()
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input model.PerpustakaanConfig
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+	input.TenantID = tenantID
+
+	if err := h.domain.Perpustakaan().SaveConfig(ctx, &input); err != nil {
+		return perpustakaanError(c, err, "Gagal menyimpan konfigurasi")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Konfigurasi perpustakaan berhasil disimpan",
+		"data":    input,
+	})
+}
@@ -0,0 +1,474 @@
+package fiber_inbound_adapter
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"prabogo/internal/domain"
+	ppdb_domain "prabogo/internal/domain/ppdb"
+	"prabogo/internal/model"
+	"prabogo/utils/log"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ppdbAdapter struct {
+	domain domain.Domain
+}
+
+func NewPPDBAdapter(d domain.Domain) *ppdbAdapter {
+	return &ppdbAdapter{domain: d}
+}
+
+// ppdbError maps domain errors to HTTP status codes
+func ppdbError(c *fiber.Ctx, err error, message string) error {
+	status := fiber.StatusBadRequest
+	switch {
+	case errors.Is(err, ppdb_domain.ErrGelombangNotFound),
+		errors.Is(err, ppdb_domain.ErrPendaftarNotFound):
+		status = fiber.StatusNotFound
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"status":  "error",
+		"message": message + ": " + err.Error(),
+	})
+}
+
+// ppdbTenant resolves the active tenant from the :subdomain route param
+func (h *ppdbAdapter) ppdbTenant(c *fiber.Ctx) *model.Tenant {
+	tenant, err := h.domain.Tenant().FindBySubdomain(c.Context(), c.Params("subdomain"))
+	if err != nil || tenant == nil || tenant.Status != model.TenantStatusActive {
+		return nil
+	}
+	return tenant
+}
+
+func ppdbTenantNotFound(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+		"status":  "error",
+		"message": "Sekolah tidak ditemukan",
+	})
+}
+
+// ==========================================
+// PUBLIC HANDLERS
+// ==========================================
+
+// GET /api/v1/ppdb/:subdomain
+func (h *ppdbAdapter) GetPublicInfo(c *fiber.Ctx) error {
+	tenant := h.ppdbTenant(c)
+	if tenant == nil {
+		return ppdbTenantNotFound(c)
+	}
+
+	info, err := h.domain.PPDB().GetPublicInfo(c.Context(), tenant)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil informasi PPDB",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   info,
+	})
+}
+
+// POST /api/v1/ppdb/:subdomain/daftar
+func (h *ppdbAdapter) Daftar(c *fiber.Ctx) error {
+	tenant := h.ppdbTenant(c)
+	if tenant == nil {
+		return ppdbTenantNotFound(c)
+	}
+
+	var input struct {
+		model.PPDBPendaftar
+		TanggalLahir string `json:"tanggal_lahir"` // YYYY-MM-DD
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+
+	pendaftar := input.PPDBPendaftar
+	pendaftar.TenantID = tenant.ID
+	pendaftar.TanggalLahir = nil
+	if input.TanggalLahir != "" {
+		t, err := time.Parse("2006-01-02", input.TanggalLahir)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Format tanggal lahir tidak valid (YYYY-MM-DD)",
+			})
+		}
+		pendaftar.TanggalLahir = &t
+	}
+	for _, dok := range pendaftar.Dokumen {
+		if !isValidProofURL(dok.URL) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  "error",
+				"message": "URL dokumen tidak valid. Hanya URL HTTPS yang diizinkan.",
+			})
+		}
+	}
+
+	result, err := h.domain.PPDB().Daftar(c.Context(), &pendaftar)
+	if err != nil {
+		return ppdbError(c, err, "Gagal mendaftar")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Pendaftaran berhasil. Simpan nomor pendaftaran Anda.",
+		"data":    result,
+	})
+}
+
+// GET /api/v1/ppdb/:subdomain/status/:no?no_hp_wali=
+func (h *ppdbAdapter) CekStatus(c *fiber.Ctx) error {
+	tenant := h.ppdbTenant(c)
+	if tenant == nil {
+		return ppdbTenantNotFound(c)
+	}
+
+	p, err := h.domain.PPDB().CekStatus(c.Context(), tenant.ID, c.Params("no"), c.Query("no_hp_wali"))
+	if err != nil {
+		return ppdbError(c, err, "Gagal mengecek status pendaftaran")
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data": fiber.Map{
+			"no_pendaftaran": p.NoPendaftaran,
+			"nama":           p.Nama,
+			"gelombang_nama": p.GelombangNama,
+			"status":         p.Status,
+			"status_bayar":   p.StatusBayar,
+		},
+	})
+}
+
+// POST /api/v1/ppdb/:subdomain/status/:no/bayar
+func (h *ppdbAdapter) BuatPembayaran(c *fiber.Ctx) error {
+	tenant := h.ppdbTenant(c)
+	if tenant == nil {
+		return ppdbTenantNotFound(c)
+	}
+
+	var input struct {
+		NoHPWali string `json:"no_hp_wali"`
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+
+	snap, err := h.domain.PPDB().BuatPembayaran(c.Context(), tenant.ID, c.Params("no"), input.NoHPWali)
+	if err != nil {
+		return ppdbError(c, err, "Gagal membuat pembayaran")
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   snap,
+	})
+}
+
+// POST /api/v1/ppdb/payment/webhook
+func (h *ppdbAdapter) PaymentWebhook(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	var notification model.MidtransNotification
+	if err := c.BodyParser(&notification); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid notification payload",
+		})
+	}
+
+	// SECURITY: Verify Midtrans signature
+	if !notification.VerifySignature(os.Getenv("MIDTRANS_SERVER_KEY")) {
+		log.WithContext(ctx).Warnf("Invalid PPDB webhook signature for order: %s", notification.OrderID)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid signature",
+		})
+	}
+
+	if err := h.domain.Payment().HandlePPDBWebhook(ctx, &notification); err != nil {
+		log.WithContext(ctx).WithError(err).Errorf("Failed to handle PPDB webhook for order: %s", notification.OrderID)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to process notification",
+		})
+	}
+
+	paid := notification.TransactionStatus == "settlement" || notification.TransactionStatus == "capture"
+	if paid && (notification.FraudStatus == "accept" || notification.FraudStatus == "") {
+		// A non-2xx response makes Midtrans retry the notification
+		if err := h.domain.PPDB().ConfirmPembayaran(ctx, notification.OrderID); err != nil {
+			log.WithContext(ctx).WithError(err).Errorf("Failed to confirm PPDB payment for order: %s", notification.OrderID)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to confirm payment",
+			})
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "ok",
+	})
+}
+
+// ==========================================
+// GELOMBANG HANDLERS
+// ==========================================
+
+// ppdbGelombangInput is the admin payload for an admission wave
+type ppdbGelombangInput struct {
+	TahunAjaran      string `json:"tahun_ajaran"`
+	Nama             string `json:"nama"`
+	TanggalBuka      string `json:"tanggal_buka"`  // YYYY-MM-DD
+	TanggalTutup     string `json:"tanggal_tutup"` // YYYY-MM-DD
+	Kuota            int    `json:"kuota"`
+	BiayaPendaftaran int64  `json:"biaya_pendaftaran"`
+	IsActive         bool   `json:"is_active"`
+}
+
+func (in ppdbGelombangInput) toModel() (*model.PPDBGelombang, error) {
+	buka, err := time.Parse("2006-01-02", in.TanggalBuka)
+	if err != nil {
+		return nil, err
+	}
+	tutup, err := time.Parse("2006-01-02", in.TanggalTutup)
+	if err != nil {
+		return nil, err
+	}
+	return &model.PPDBGelombang{
+		TahunAjaran:      in.TahunAjaran,
+		Nama:             in.Nama,
+		TanggalBuka:      buka,
+		TanggalTutup:     tutup,
+		Kuota:            in.Kuota,
+		BiayaPendaftaran: in.BiayaPendaftaran,
+		IsActive:         in.IsActive,
+	}, nil
+}
+
+// GET /api/v1/sekolah/ppdb/gelombang
+func (h *ppdbAdapter) GetGelombangList(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	list, err := h.domain.PPDB().GetGelombangList(c.Context(), tenantID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil gelombang PPDB",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   list,
+	})
+}
+
+// POST /api/v1/sekolah/ppdb/gelombang
+func (h *ppdbAdapter) CreateGelombang(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input ppdbGelombangInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+	gelombang, err := input.toModel()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format tanggal tidak valid (YYYY-MM-DD)",
+		})
+	}
+	gelombang.TenantID = tenantID
+
+	if err := h.domain.PPDB().CreateGelombang(c.Context(), gelombang); err != nil {
+		return ppdbError(c, err, "Gagal membuat gelombang")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Gelombang berhasil dibuat",
+		"data":    gelombang,
+	})
+}
+
+// PUT /api/v1/sekolah/ppdb/gelombang/:id
+func (h *ppdbAdapter) UpdateGelombang(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input ppdbGelombangInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+	gelombang, err := input.toModel()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format tanggal tidak valid (YYYY-MM-DD)",
+		})
+	}
+	gelombang.ID = c.Params("id")
+	gelombang.TenantID = tenantID
+
+	if err := h.domain.PPDB().UpdateGelombang(c.Context(), gelombang); err != nil {
+		return ppdbError(c, err, "Gagal mengupdate gelombang")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Gelombang berhasil diupdate",
+		"data":    gelombang,
+	})
+}
+
+// ==========================================
+// SELEKSI HANDLERS
+// ==========================================
+
+// GET /api/v1/sekolah/ppdb/pendaftar?gelombang_id=&status=&q=
+func (h *ppdbAdapter) GetPendaftarList(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	list, err := h.domain.PPDB().GetPendaftarList(c.Context(), model.PPDBPendaftarFilter{
+		TenantID:    tenantID,
+		GelombangID: c.Query("gelombang_id"),
+		Status:      c.Query("status"),
+		Search:      c.Query("q"),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data pendaftar",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   list,
+	})
+}
+
+// GET /api/v1/sekolah/ppdb/pendaftar/:id
+func (h *ppdbAdapter) GetPendaftarDetail(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	p, err := h.domain.PPDB().GetPendaftarDetail(c.Context(), tenantID, c.Params("id"))
+	if err != nil {
+		return ppdbError(c, err, "Gagal mengambil detail pendaftar")
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   p,
+	})
+}
+
+// PUT /api/v1/sekolah/ppdb/pendaftar/:id/status
+func (h *ppdbAdapter) UpdateStatus(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input model.PPDBStatusInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+
+	p, err := h.domain.PPDB().UpdateStatus(c.Context(), tenantID, c.Params("id"), input)
+	if err != nil {
+		return ppdbError(c, err, "Gagal mengubah status pendaftar")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Status pendaftar berhasil diubah",
+		"data":    p,
+	})
+}
+
+// POST /api/v1/sekolah/ppdb/pendaftar/:id/dokumen
+func (h *ppdbAdapter) AddDokumen(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input model.PPDBDokumen
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+	if !isValidProofURL(input.URL) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "URL dokumen tidak valid. Hanya URL HTTPS yang diizinkan.",
+		})
+	}
+
+	if err := h.domain.PPDB().AddDokumen(c.Context(), tenantID, c.Params("id"), &input); err != nil {
+		return ppdbError(c, err, "Gagal menambahkan dokumen")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Dokumen berhasil ditambahkan",
+		"data":    input,
+	})
+}
+
+// POST /api/v1/sekolah/ppdb/pendaftar/:id/bayar
+func (h *ppdbAdapter) KonfirmasiBayar(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	if err := h.domain.PPDB().KonfirmasiBayarManual(c.Context(), tenantID, c.Params("id")); err != nil {
+		return ppdbError(c, err, "Gagal mengkonfirmasi pembayaran")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Pembayaran pendaftaran berhasil dikonfirmasi",
+	})
+}
+
+// POST /api/v1/sekolah/ppdb/pendaftar/:id/konversi
+func (h *ppdbAdapter) KonversiSiswa(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input model.PPDBKonversiInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+
+	siswaID, err := h.domain.PPDB().KonversiSiswa(c.Context(), tenantID, c.Params("id"), input)
+	if err != nil {
+		return ppdbError(c, err, "Gagal mengkonversi pendaftar")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Pendaftar berhasil dijadikan siswa",
+		"data": fiber.Map{
+			"siswa_id": siswaID,
+		},
+	})
+}
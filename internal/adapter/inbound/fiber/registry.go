@@ -98,3 +98,31 @@ func (a *adapter) Analytics() inbound_port.AnalyticsHttpPort {
 func (a *adapter) Export() inbound_port.ExportHttpPort {
 	return NewExportHandler(a.domain.Export())
 }
+
+func (a *adapter) Ekskul() inbound_port.EkskulHttpPort {
+	return NewEkskulAdapter(a.domain)
+}
+
+func (a *adapter) Konseling() inbound_port.KonselingHttpPort {
+	return NewKonselingAdapter(a.domain)
+}
+
+func (a *adapter) Perpustakaan() inbound_port.PerpustakaanHttpPort {
+	return NewPerpustakaanAdapter(a.domain)
+}
+
+func (a *adapter) PPDB() inbound_port.PPDBHttpPort {
+	return NewPPDBAdapter(a.domain)
+}
+
+func (a *adapter) Ujian() inbound_port.UjianHttpPort {
+	return NewUjianAdapter(a.domain)
+}
+
+func (a *adapter) Dapodik() inbound_port.DapodikHttpPort {
+	return NewDapodikAdapter(a.domain)
+}
+
+func (a *adapter) Alumni() inbound_port.AlumniHttpPort {
+	return NewAlumniAdapter(a.domain)
+}
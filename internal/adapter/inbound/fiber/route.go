@@ -9,6 +9,7 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/limiter"
 
+	"prabogo/internal/model"
 	inbound_port "prabogo/internal/port/inbound"
 )
 
@@ -99,6 +100,60 @@ func InitRoute(
 		return port.Onboarding().Status(c)
 	})
 
+	// PPDB Routes (Public, tenant-branded registration under the tenant subdomain)
+	ppdbLimiter := limiter.New(limiter.Config{
+		Max:        10,
+		Expiration: 60 * time.Second,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			return "ppdb:" + c.IP()
+		},
+		LimitReached: func(c *fiber.Ctx) error {
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"error": "Terlalu banyak percobaan. Silakan coba lagi dalam 1 menit.",
+			})
+		},
+	})
+	ppdbPublic := api.Group("/ppdb")
+	ppdbPublic.Post("/payment/webhook", func(c *fiber.Ctx) error {
+		return port.PPDB().PaymentWebhook(c)
+	})
+	ppdbPublic.Get("/:subdomain", func(c *fiber.Ctx) error {
+		return port.PPDB().GetPublicInfo(c)
+	})
+	ppdbPublic.Post("/:subdomain/daftar", ppdbLimiter, func(c *fiber.Ctx) error {
+		return port.PPDB().Daftar(c)
+	})
+	ppdbPublic.Get("/:subdomain/status/:no", ppdbLimiter, func(c *fiber.Ctx) error {
+		return port.PPDB().CekStatus(c)
+	})
+	ppdbPublic.Post("/:subdomain/status/:no/bayar", ppdbLimiter, func(c *fiber.Ctx) error {
+		return port.PPDB().BuatPembayaran(c)
+	})
+
+	// Public Alumni self-service (token link sent by the school)
+	alumniLimiter := limiter.New(limiter.Config{
+		Max:        20,
+		Expiration: 60 * time.Second,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			return "alumni:" + c.IP()
+		},
+		LimitReached: func(c *fiber.Ctx) error {
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"error": "Terlalu banyak percobaan. Silakan coba lagi dalam 1 menit.",
+			})
+		},
+	})
+	alumniPublic := api.Group("/alumni")
+	alumniPublic.Get("/:token", alumniLimiter, func(c *fiber.Ctx) error {
+		return port.Alumni().GetProfil(c)
+	})
+	alumniPublic.Put("/:token", alumniLimiter, func(c *fiber.Ctx) error {
+		return port.Alumni().UpdateProfil(c)
+	})
+	alumniPublic.Post("/:token/survey/:survey_id", alumniLimiter, func(c *fiber.Ctx) error {
+		return port.Alumni().IsiSurvey(c)
+	})
+
 	// Auth Routes with stricter rate limiting
 	auth := api.Group("/auth")
 
@@ -325,12 +380,67 @@ func InitRoute(
 	kepesantrenan.Post("/pelanggaran", func(c *fiber.Ctx) error {
 		return port.Sekolah().CreatePelanggaranSiswa(c)
 	})
+	// Violation points per semester and the sanctions they raise
+	kepesantrenan.Get("/poin", func(c *fiber.Ctx) error {
+		return port.Sekolah().GetRekapPoin(c)
+	})
+	kepesantrenan.Get("/poin/:santri_id", func(c *fiber.Ctx) error {
+		return port.Sekolah().GetPoinSantri(c)
+	})
+	kepesantrenan.Post("/poin/kredit", func(c *fiber.Ctx) error {
+		return port.Sekolah().CreatePoinKredit(c)
+	})
+	kepesantrenan.Get("/sanksi/ambang", func(c *fiber.Ctx) error {
+		return port.Sekolah().GetSanksiAmbang(c)
+	})
+	kepesantrenan.Put("/sanksi/ambang", RequireRole(model.RoleAdmin, model.RoleAdminPesantren, model.RoleAdminSekolah, model.RolePengasuh), func(c *fiber.Ctx) error {
+		return port.Sekolah().SaveSanksiAmbang(c)
+	})
+	kepesantrenan.Get("/sanksi", func(c *fiber.Ctx) error {
+		return port.Sekolah().GetSanksiList(c)
+	})
+	kepesantrenan.Put("/sanksi/:id/status", RequireRole(model.RoleAdmin, model.RoleAdminPesantren, model.RoleAdminSekolah, model.RolePengasuh), func(c *fiber.Ctx) error {
+		return port.Sekolah().UpdateSanksiStatus(c)
+	})
+	kepesantrenan.Post("/sanksi/:id/kirim", RequireRole(model.RoleAdmin, model.RoleAdminPesantren, model.RoleAdminSekolah, model.RolePengasuh), func(c *fiber.Ctx) error {
+		return port.Sekolah().KirimSanksiWali(c)
+	})
+	kepesantrenan.Get("/sanksi/:id/surat", func(c *fiber.Ctx) error {
+		return port.Sekolah().CetakSuratPeringatan(c)
+	})
 	kepesantrenan.Get("/perizinan", func(c *fiber.Ctx) error {
 		return port.Sekolah().GetPerizinanList(c)
 	})
 	kepesantrenan.Post("/perizinan", func(c *fiber.Ctx) error {
 		return port.Sekolah().CreatePerizinan(c)
 	})
+	kepesantrenan.Get("/perizinan/terlambat", func(c *fiber.Ctx) error {
+		return port.Sekolah().GetPerizinanTerlambat(c)
+	})
+	kepesantrenan.Post("/perizinan/terlambat/kirim", RequireRole(model.RoleAdmin, model.RoleAdminPesantren, model.RolePengasuh, model.RoleKeamanan), func(c *fiber.Ctx) error {
+		return port.Sekolah().KirimPerizinanTerlambat(c)
+	})
+	kepesantrenan.Post("/perizinan/gate", RequireRole(model.RoleAdmin, model.RoleAdminPesantren, model.RolePengasuh, model.RoleKeamanan), func(c *fiber.Ctx) error {
+		return port.Sekolah().ScanGatePass(c)
+	})
+	kepesantrenan.Get("/perizinan/:id", func(c *fiber.Ctx) error {
+		return port.Sekolah().GetPerizinan(c)
+	})
+	kepesantrenan.Post("/perizinan/:id/approve", RequireRole(model.RoleAdmin, model.RoleAdminPesantren, model.RolePengasuh, model.RoleKeamanan), func(c *fiber.Ctx) error {
+		return port.Sekolah().ApprovePerizinan(c)
+	})
+	kepesantrenan.Post("/perizinan/:id/reject", RequireRole(model.RoleAdmin, model.RoleAdminPesantren, model.RolePengasuh, model.RoleKeamanan), func(c *fiber.Ctx) error {
+		return port.Sekolah().RejectPerizinan(c)
+	})
+	kepesantrenan.Get("/perizinan/:id/gate-pass", func(c *fiber.Ctx) error {
+		return port.Sekolah().GetGatePass(c)
+	})
+	kepesantrenan.Post("/perizinan/:id/keluar", RequireRole(model.RoleAdmin, model.RoleAdminPesantren, model.RolePengasuh, model.RoleKeamanan), func(c *fiber.Ctx) error {
+		return port.Sekolah().CheckOutPerizinan(c)
+	})
+	kepesantrenan.Post("/perizinan/:id/kembali", RequireRole(model.RoleAdmin, model.RoleAdminPesantren, model.RolePengasuh, model.RoleKeamanan), func(c *fiber.Ctx) error {
+		return port.Sekolah().CheckInPerizinan(c)
+	})
 
 	// Asrama
 	asrama := sekolah.Group("/asrama")
@@ -352,6 +462,21 @@ func InitRoute(
 	asrama.Post("/penempatan", func(c *fiber.Ctx) error {
 		return port.Sekolah().CreatePenempatan(c)
 	})
+	asrama.Post("/penempatan/pindah", func(c *fiber.Ctx) error {
+		return port.Sekolah().PindahKamar(c)
+	})
+	asrama.Post("/penempatan/keluar", func(c *fiber.Ctx) error {
+		return port.Sekolah().KeluarAsrama(c)
+	})
+	asrama.Get("/penempatan/riwayat/:santri_id", func(c *fiber.Ctx) error {
+		return port.Sekolah().GetRiwayatPenempatan(c)
+	})
+	asrama.Post("/alokasi", RequireRole(model.RoleAdmin, model.RoleAdminPesantren, model.RoleAdminSekolah, model.RolePengasuh), func(c *fiber.Ctx) error {
+		return port.Sekolah().AlokasiPenempatan(c)
+	})
+	asrama.Get("/okupansi", func(c *fiber.Ctx) error {
+		return port.Sekolah().GetOkupansiAsrama(c)
+	})
 
 	// Tahfidz
 	tahfidz := sekolah.Group("/tahfidz")
@@ -361,6 +486,58 @@ func InitRoute(
 	tahfidz.Post("/setoran", func(c *fiber.Ctx) error {
 		return port.Sekolah().CreateTahfidzSetoran(c)
 	})
+	tahfidz.Get("/surah", func(c *fiber.Ctx) error {
+		return port.Sekolah().GetDaftarSurah(c)
+	})
+	tahfidz.Get("/progress/:santri_id", func(c *fiber.Ctx) error {
+		return port.Sekolah().GetTahfidzProgress(c)
+	})
+	tahfidz.Get("/leaderboard", func(c *fiber.Ctx) error {
+		return port.Sekolah().GetTahfidzLeaderboard(c)
+	})
+	tahfidz.Get("/murajaah/:santri_id", func(c *fiber.Ctx) error {
+		return port.Sekolah().GetMurajaahPlan(c)
+	})
+	tahfidz.Get("/alerts", func(c *fiber.Ctx) error {
+		return port.Sekolah().GetTahfidzAlerts(c)
+	})
+	tahfidz.Post("/alerts/kirim", RequireRole(model.RoleAdmin, model.RoleAdminSekolah, model.RoleAdminPesantren, model.RolePengasuh, model.RolePendidikan), func(c *fiber.Ctx) error {
+		return port.Sekolah().KirimTahfidzAlerts(c)
+	})
+	// Halaqah groups and hafalan targets; changes limited to the pesantren's management
+	tahfidz.Get("/halaqah", func(c *fiber.Ctx) error {
+		return port.Sekolah().GetHalaqahList(c)
+	})
+	tahfidz.Post("/halaqah", RequireRole(model.RoleAdmin, model.RoleAdminSekolah, model.RoleAdminPesantren, model.RolePengasuh, model.RolePendidikan), func(c *fiber.Ctx) error {
+		return port.Sekolah().CreateHalaqah(c)
+	})
+	tahfidz.Get("/halaqah/:id", func(c *fiber.Ctx) error {
+		return port.Sekolah().GetHalaqah(c)
+	})
+	tahfidz.Put("/halaqah/:id", RequireRole(model.RoleAdmin, model.RoleAdminSekolah, model.RoleAdminPesantren, model.RolePengasuh, model.RolePendidikan), func(c *fiber.Ctx) error {
+		return port.Sekolah().UpdateHalaqah(c)
+	})
+	tahfidz.Delete("/halaqah/:id", RequireRole(model.RoleAdmin, model.RoleAdminSekolah, model.RoleAdminPesantren, model.RolePengasuh, model.RolePendidikan), func(c *fiber.Ctx) error {
+		return port.Sekolah().DeleteHalaqah(c)
+	})
+	tahfidz.Post("/halaqah/:id/anggota", RequireRole(model.RoleAdmin, model.RoleAdminSekolah, model.RoleAdminPesantren, model.RolePengasuh, model.RolePendidikan), func(c *fiber.Ctx) error {
+		return port.Sekolah().AddHalaqahAnggota(c)
+	})
+	tahfidz.Delete("/halaqah/:id/anggota/:santri_id", RequireRole(model.RoleAdmin, model.RoleAdminSekolah, model.RoleAdminPesantren, model.RolePengasuh, model.RolePendidikan), func(c *fiber.Ctx) error {
+		return port.Sekolah().RemoveHalaqahAnggota(c)
+	})
+	tahfidz.Get("/target", func(c *fiber.Ctx) error {
+		return port.Sekolah().GetTahfidzTargets(c)
+	})
+	tahfidz.Post("/target", RequireRole(model.RoleAdmin, model.RoleAdminSekolah, model.RoleAdminPesantren, model.RolePengasuh, model.RolePendidikan), func(c *fiber.Ctx) error {
+		return port.Sekolah().SaveTahfidzTarget(c)
+	})
+	tahfidz.Get("/target/status", func(c *fiber.Ctx) error {
+		return port.Sekolah().GetTahfidzTargetStatus(c)
+	})
+	tahfidz.Delete("/target/:id", RequireRole(model.RoleAdmin, model.RoleAdminSekolah, model.RoleAdminPesantren, model.RolePengasuh, model.RolePendidikan), func(c *fiber.Ctx) error {
+		return port.Sekolah().DeleteTahfidzTarget(c)
+	})
 
 	// Diniyah
 	diniyah := sekolah.Group("/diniyah")
@@ -426,6 +603,12 @@ func InitRoute(
 	erapor.Delete("/subjects/:id", func(c *fiber.Ctx) error {
 		return port.ERapor().DeleteSubject(c)
 	})
+	erapor.Get("/subjects/:id/kkm", func(c *fiber.Ctx) error {
+		return port.ERapor().GetKKMHistory(c)
+	})
+	erapor.Put("/subjects/:id/kkm", RequireRole(model.RoleAdmin, model.RoleAdminSekolah, model.RoleKepalaSekolah), func(c *fiber.Ctx) error {
+		return port.ERapor().SetSemesterKKM(c)
+	})
 
 	// Grade Management
 	erapor.Post("/grades", func(c *fiber.Ctx) error {
@@ -441,7 +624,161 @@ func InitRoute(
 		return port.ERapor().GetSubjectGrades(c)
 	})
 
+	// Assessment entries (final grade recalculated per entry)
+	erapor.Get("/assessments", func(c *fiber.Ctx) error {
+		return port.ERapor().GetAssessments(c)
+	})
+	erapor.Post("/assessments", RequireRole(model.RoleAdmin, model.RoleGuru, model.RoleWaliKelas, model.RoleAdminSekolah), func(c *fiber.Ctx) error {
+		return port.ERapor().CreateAssessment(c)
+	})
+	erapor.Put("/assessments/:id", RequireRole(model.RoleAdmin, model.RoleGuru, model.RoleWaliKelas, model.RoleAdminSekolah), func(c *fiber.Ctx) error {
+		return port.ERapor().UpdateAssessment(c)
+	})
+	erapor.Delete("/assessments/:id", RequireRole(model.RoleAdmin, model.RoleGuru, model.RoleWaliKelas, model.RoleAdminSekolah), func(c *fiber.Ctx) error {
+		return port.ERapor().DeleteAssessment(c)
+	})
+
+	// K13 remedial & enrichment
+	erapor.Get("/remedial/candidates", func(c *fiber.Ctx) error {
+		return port.ERapor().GetRemedialCandidates(c)
+	})
+	erapor.Get("/remedial", func(c *fiber.Ctx) error {
+		return port.ERapor().GetRemedialHistory(c)
+	})
+	erapor.Post("/remedial", RequireRole(model.RoleAdmin, model.RoleGuru, model.RoleWaliKelas, model.RoleAdminSekolah), func(c *fiber.Ctx) error {
+		return port.ERapor().RecordRemedial(c)
+	})
+
+	// Kurikulum Merdeka CP/TP and capaian descriptions
+	erapor.Get("/capaian", func(c *fiber.Ctx) error {
+		return port.ERapor().GetCapaianList(c)
+	})
+	erapor.Post("/capaian", RequireRole(model.RoleAdmin, model.RoleGuru, model.RoleWaliKelas, model.RoleAdminSekolah), func(c *fiber.Ctx) error {
+		return port.ERapor().CreateCapaian(c)
+	})
+	erapor.Post("/capaian/generate", RequireRole(model.RoleAdmin, model.RoleGuru, model.RoleWaliKelas, model.RoleAdminSekolah), func(c *fiber.Ctx) error {
+		return port.ERapor().GenerateCapaianDescriptions(c)
+	})
+	erapor.Put("/capaian/:id", RequireRole(model.RoleAdmin, model.RoleGuru, model.RoleWaliKelas, model.RoleAdminSekolah), func(c *fiber.Ctx) error {
+		return port.ERapor().UpdateCapaian(c)
+	})
+	erapor.Delete("/capaian/:id", RequireRole(model.RoleAdmin, model.RoleGuru, model.RoleWaliKelas, model.RoleAdminSekolah), func(c *fiber.Ctx) error {
+		return port.ERapor().DeleteCapaian(c)
+	})
+	erapor.Post("/capaian/:id/tujuan", RequireRole(model.RoleAdmin, model.RoleGuru, model.RoleWaliKelas, model.RoleAdminSekolah), func(c *fiber.Ctx) error {
+		return port.ERapor().CreateTujuan(c)
+	})
+	erapor.Put("/tujuan/:id", RequireRole(model.RoleAdmin, model.RoleGuru, model.RoleWaliKelas, model.RoleAdminSekolah), func(c *fiber.Ctx) error {
+		return port.ERapor().UpdateTujuan(c)
+	})
+	erapor.Delete("/tujuan/:id", RequireRole(model.RoleAdmin, model.RoleGuru, model.RoleWaliKelas, model.RoleAdminSekolah), func(c *fiber.Ctx) error {
+		return port.ERapor().DeleteTujuan(c)
+	})
+	erapor.Get("/tp-marks", func(c *fiber.Ctx) error {
+		return port.ERapor().GetTPMarks(c)
+	})
+	erapor.Post("/tp-marks", RequireRole(model.RoleAdmin, model.RoleGuru, model.RoleWaliKelas, model.RoleAdminSekolah), func(c *fiber.Ctx) error {
+		return port.ERapor().SaveTPMarks(c)
+	})
+	erapor.Put("/grades/description", RequireRole(model.RoleAdmin, model.RoleGuru, model.RoleWaliKelas, model.RoleAdminSekolah), func(c *fiber.Ctx) error {
+		return port.ERapor().UpdateGradeDescription(c)
+	})
+
+	// P5 projects
+	erapor.Get("/p5", func(c *fiber.Ctx) error {
+		return port.ERapor().GetP5ProjekList(c)
+	})
+	erapor.Post("/p5", RequireRole(model.RoleAdmin, model.RoleGuru, model.RoleWaliKelas, model.RoleAdminSekolah), func(c *fiber.Ctx) error {
+		return port.ERapor().CreateP5Projek(c)
+	})
+	erapor.Get("/p5/:id", func(c *fiber.Ctx) error {
+		return port.ERapor().GetP5Projek(c)
+	})
+	erapor.Put("/p5/:id", RequireRole(model.RoleAdmin, model.RoleGuru, model.RoleWaliKelas, model.RoleAdminSekolah), func(c *fiber.Ctx) error {
+		return port.ERapor().UpdateP5Projek(c)
+	})
+	erapor.Delete("/p5/:id", RequireRole(model.RoleAdmin, model.RoleGuru, model.RoleWaliKelas, model.RoleAdminSekolah), func(c *fiber.Ctx) error {
+		return port.ERapor().DeleteP5Projek(c)
+	})
+	erapor.Post("/p5/:id/penilaian", RequireRole(model.RoleAdmin, model.RoleGuru, model.RoleWaliKelas, model.RoleAdminSekolah), func(c *fiber.Ctx) error {
+		return port.ERapor().SaveP5Penilaian(c)
+	})
+
+	// K13 sikap: journal by guru/wali kelas/BK, compiled by the wali kelas
+	erapor.Get("/sikap/jurnal", func(c *fiber.Ctx) error {
+		return port.ERapor().GetJurnalSikap(c)
+	})
+	erapor.Post("/sikap/jurnal", RequireRole(model.RoleAdmin, model.RoleGuru, model.RoleWaliKelas, model.RoleBK, model.RoleAdminSekolah), func(c *fiber.Ctx) error {
+		return port.ERapor().CreateJurnalSikap(c)
+	})
+	erapor.Delete("/sikap/jurnal/:id", RequireRole(model.RoleAdmin, model.RoleGuru, model.RoleWaliKelas, model.RoleBK, model.RoleAdminSekolah), func(c *fiber.Ctx) error {
+		return port.ERapor().DeleteJurnalSikap(c)
+	})
+	erapor.Get("/sikap", func(c *fiber.Ctx) error {
+		return port.ERapor().GetSikapSiswa(c)
+	})
+	erapor.Post("/sikap/rekap", RequireRole(model.RoleAdmin, model.RoleWaliKelas, model.RoleAdminSekolah), func(c *fiber.Ctx) error {
+		return port.ERapor().RekapSikap(c)
+	})
+	erapor.Put("/sikap/:id", RequireRole(model.RoleAdmin, model.RoleWaliKelas, model.RoleAdminSekolah), func(c *fiber.Ctx) error {
+		return port.ERapor().UpdateSikapSiswa(c)
+	})
+
+	// Predicate scales per subject type; tahfidz grades computed from setoran
+	erapor.Get("/predikat", func(c *fiber.Ctx) error {
+		return port.ERapor().GetPredicateSets(c)
+	})
+	erapor.Put("/predikat/:subject_type", RequireRole(model.RoleAdmin, model.RoleAdminSekolah, model.RoleAdminPesantren), func(c *fiber.Ctx) error {
+		return port.ERapor().SavePredicateSet(c)
+	})
+	erapor.Delete("/predikat/:subject_type", RequireRole(model.RoleAdmin, model.RoleAdminSekolah, model.RoleAdminPesantren), func(c *fiber.Ctx) error {
+		return port.ERapor().DeletePredicateSet(c)
+	})
+	erapor.Post("/tahfidz/hitung", RequireRole(model.RoleAdmin, model.RoleGuru, model.RoleWaliKelas, model.RolePengasuh, model.RoleAdminSekolah, model.RoleAdminPesantren), func(c *fiber.Ctx) error {
+		return port.ERapor().HitungTahfidz(c)
+	})
+
+	// Leger nilai with class ranking, for the wali kelas and school leadership
+	erapor.Get("/leger", RequireRole(model.RoleAdmin, model.RoleWaliKelas, model.RoleAdminSekolah, model.RoleKepalaSekolah), func(c *fiber.Ctx) error {
+		return port.ERapor().GetLeger(c)
+	})
+	erapor.Get("/leger/export", RequireRole(model.RoleAdmin, model.RoleWaliKelas, model.RoleAdminSekolah, model.RoleKepalaSekolah), func(c *fiber.Ctx) error {
+		return port.ERapor().ExportLeger(c)
+	})
+
+	// Curriculum profiles defined by the tenant, selected per jenjang for hybrid schools
+	erapor.Get("/kurikulum", func(c *fiber.Ctx) error {
+		return port.ERapor().GetCurriculumProfiles(c)
+	})
+	erapor.Post("/kurikulum", RequireRole(model.RoleAdmin, model.RoleAdminSekolah, model.RoleAdminPesantren), func(c *fiber.Ctx) error {
+		return port.ERapor().SaveCurriculumProfile(c)
+	})
+	erapor.Get("/kurikulum/jenjang", func(c *fiber.Ctx) error {
+		return port.ERapor().GetTenantCurricula(c)
+	})
+	erapor.Put("/kurikulum/jenjang", RequireRole(model.RoleAdmin, model.RoleAdminSekolah, model.RoleAdminPesantren), func(c *fiber.Ctx) error {
+		return port.ERapor().SetTenantCurriculum(c)
+	})
+	erapor.Delete("/kurikulum/jenjang/:jenjang", RequireRole(model.RoleAdmin, model.RoleAdminSekolah, model.RoleAdminPesantren), func(c *fiber.Ctx) error {
+		return port.ERapor().DeleteTenantCurriculum(c)
+	})
+	erapor.Put("/kurikulum/:id", RequireRole(model.RoleAdmin, model.RoleAdminSekolah, model.RoleAdminPesantren), func(c *fiber.Ctx) error {
+		return port.ERapor().SaveCurriculumProfile(c)
+	})
+	erapor.Delete("/kurikulum/:id", RequireRole(model.RoleAdmin, model.RoleAdminSekolah, model.RoleAdminPesantren), func(c *fiber.Ctx) error {
+		return port.ERapor().DeleteCurriculumProfile(c)
+	})
+
 	// Rapor
+	erapor.Get("/rapor/:id/cetak-p5", func(c *fiber.Ctx) error {
+		return port.ERapor().CetakRaporP5(c)
+	})
+	erapor.Get("/rapor/:id/cetak", func(c *fiber.Ctx) error {
+		return port.ERapor().CetakRapor(c)
+	})
+	erapor.Get("/rapor/:id/riwayat", func(c *fiber.Ctx) error {
+		return port.ERapor().GetRaporRiwayat(c)
+	})
 	erapor.Get("/rapor/:student_id/:semester", func(c *fiber.Ctx) error {
 		return port.ERapor().GetStudentRapor(c)
 	})
@@ -449,10 +786,56 @@ func InitRoute(
 		return port.ERapor().GenerateRapor(c)
 	})
 
-	// Stats
+	// Rapor approval workflow; grades are locked from Submitted on
+	erapor.Post("/rapor/:id/submit", RequireRole(model.RoleAdmin, model.RoleWaliKelas, model.RoleAdminSekolah, model.RoleAdminPesantren, model.RolePendidikan), func(c *fiber.Ctx) error {
+		return port.ERapor().SubmitRapor(c)
+	})
+	erapor.Post("/rapor/:id/approve", RequireRole(model.RoleAdmin, model.RoleKepalaSekolah, model.RolePengasuh), func(c *fiber.Ctx) error {
+		return port.ERapor().ApproveRapor(c)
+	})
+	erapor.Post("/rapor/:id/publish", RequireRole(model.RoleAdmin, model.RoleKepalaSekolah, model.RolePengasuh, model.RoleAdminSekolah, model.RoleAdminPesantren), func(c *fiber.Ctx) error {
+		return port.ERapor().PublishRapor(c)
+	})
+	erapor.Post("/rapor/:id/unlock", RequireRole(model.RoleAdmin, model.RoleKepalaSekolah, model.RolePengasuh), func(c *fiber.Ctx) error {
+		return port.ERapor().UnlockRapor(c)
+	})
+
+	// Parent portal: published rapor of the wali's own children
+	portal := sekolah.Group("/portal", RequireRole(model.RoleAdmin, model.RoleWaliSiswa, model.RoleWaliSantri))
+	portal.Get("/rapor", func(c *fiber.Ctx) error {
+		return port.ERapor().GetRaporWaliList(c)
+	})
+	portal.Get("/rapor/:id/cetak", func(c *fiber.Ctx) error {
+		return port.ERapor().CetakRaporWali(c)
+	})
+
+	// Batch generation per kelas (processed by the message worker)
+	erapor.Post("/batch", func(c *fiber.Ctx) error {
+		return port.ERapor().CreateRaporBatch(c)
+	})
+	erapor.Get("/batch", func(c *fiber.Ctx) error {
+		return port.ERapor().GetRaporBatchList(c)
+	})
+	erapor.Get("/batch/:id", func(c *fiber.Ctx) error {
+		return port.ERapor().GetRaporBatch(c)
+	})
+	erapor.Get("/batch/:id/download", func(c *fiber.Ctx) error {
+		return port.ERapor().DownloadRaporBatch(c)
+	})
+
+	// Grade statistics; the histogram is for teachers looking at their own subject
 	erapor.Get("/stats", func(c *fiber.Ctx) error {
 		return port.ERapor().GetStats(c)
 	})
+	erapor.Get("/stats/kelas", RequireRole(model.RoleAdmin, model.RoleAdminSekolah, model.RoleKepalaSekolah, model.RoleWaliKelas), func(c *fiber.Ctx) error {
+		return port.ERapor().CompareKelasStats(c)
+	})
+	erapor.Get("/stats/semester", RequireRole(model.RoleAdmin, model.RoleAdminSekolah, model.RoleKepalaSekolah, model.RoleWaliKelas), func(c *fiber.Ctx) error {
+		return port.ERapor().CompareSemesterStats(c)
+	})
+	erapor.Get("/stats/histogram", RequireRole(model.RoleAdmin, model.RoleGuru, model.RoleWaliKelas, model.RoleAdminSekolah, model.RoleKepalaSekolah), func(c *fiber.Ctx) error {
+		return port.ERapor().GetGradeHistogram(c)
+	})
 
 	// Curriculum Settings
 	erapor.Get("/curriculum", func(c *fiber.Ctx) error {
@@ -507,6 +890,250 @@ func InitRoute(
 		return port.SDM().SavePayrollConfig(c)
 	})
 
+	// Ekskul Routes (Extracurricular, attendance, rapor assessment)
+	ekskul := sekolah.Group("/ekskul")
+	ekskul.Get("/", func(c *fiber.Ctx) error {
+		return port.Ekskul().GetEkskulList(c)
+	})
+	ekskul.Post("/", func(c *fiber.Ctx) error {
+		return port.Ekskul().CreateEkskul(c)
+	})
+	ekskul.Post("/penilaian", func(c *fiber.Ctx) error {
+		return port.Ekskul().SavePenilaian(c)
+	})
+	ekskul.Delete("/anggota/:anggota_id", func(c *fiber.Ctx) error {
+		return port.Ekskul().RemoveAnggota(c)
+	})
+	ekskul.Put("/:id", func(c *fiber.Ctx) error {
+		return port.Ekskul().UpdateEkskul(c)
+	})
+	ekskul.Delete("/:id", func(c *fiber.Ctx) error {
+		return port.Ekskul().DeleteEkskul(c)
+	})
+	ekskul.Get("/:id/anggota", func(c *fiber.Ctx) error {
+		return port.Ekskul().GetAnggota(c)
+	})
+	ekskul.Post("/:id/anggota", func(c *fiber.Ctx) error {
+		return port.Ekskul().AddAnggota(c)
+	})
+	ekskul.Get("/:id/sesi", func(c *fiber.Ctx) error {
+		return port.Ekskul().GetSesi(c)
+	})
+	ekskul.Post("/:id/sesi", func(c *fiber.Ctx) error {
+		return port.Ekskul().CreateSesi(c)
+	})
+
+	// Konseling Routes (BK) - confidential, restricted by role
+	konseling := sekolah.Group("/konseling")
+	konseling.Get("/ringkasan", RequireRole(model.RoleBK, model.RoleKepalaSekolah, model.RoleWaliKelas), func(c *fiber.Ctx) error {
+		return port.Konseling().GetRingkasanKelas(c)
+	})
+	kasus := konseling.Group("/kasus", RequireRole(model.RoleBK, model.RoleKepalaSekolah))
+	kasus.Get("/", func(c *fiber.Ctx) error {
+		return port.Konseling().GetKasusList(c)
+	})
+	kasus.Post("/", func(c *fiber.Ctx) error {
+		return port.Konseling().CreateKasus(c)
+	})
+	kasus.Get("/:id", func(c *fiber.Ctx) error {
+		return port.Konseling().GetKasusDetail(c)
+	})
+	kasus.Put("/:id", func(c *fiber.Ctx) error {
+		return port.Konseling().UpdateKasus(c)
+	})
+	kasus.Post("/:id/sesi", func(c *fiber.Ctx) error {
+		return port.Konseling().AddSesi(c)
+	})
+	kasus.Put("/:id/sesi/:sesi_id/tindak-lanjut", func(c *fiber.Ctx) error {
+		return port.Konseling().SetTindakLanjutSelesai(c)
+	})
+	kasus.Post("/:id/pelanggaran", func(c *fiber.Ctx) error {
+		return port.Konseling().LinkPelanggaran(c)
+	})
+
+	// Perpustakaan Routes (Catalog, circulation, reports)
+	perpus := sekolah.Group("/perpustakaan", RequireRole(model.RoleAdmin, model.RolePerpustakaan, model.RoleAdminSekolah, model.RoleKepalaSekolah))
+	perpus.Get("/buku", func(c *fiber.Ctx) error {
+		return port.Perpustakaan().GetBukuList(c)
+	})
+	perpus.Post("/buku", func(c *fiber.Ctx) error {
+		return port.Perpustakaan().CreateBuku(c)
+	})
+	perpus.Get("/buku/:id", func(c *fiber.Ctx) error {
+		return port.Perpustakaan().GetBukuDetail(c)
+	})
+	perpus.Put("/buku/:id", func(c *fiber.Ctx) error {
+		return port.Perpustakaan().UpdateBuku(c)
+	})
+	perpus.Delete("/buku/:id", func(c *fiber.Ctx) error {
+		return port.Perpustakaan().DeleteBuku(c)
+	})
+	perpus.Post("/buku/:id/eksemplar", func(c *fiber.Ctx) error {
+		return port.Perpustakaan().AddEksemplar(c)
+	})
+	perpus.Get("/anggota", func(c *fiber.Ctx) error {
+		return port.Perpustakaan().SearchAnggota(c)
+	})
+	perpus.Get("/peminjaman", func(c *fiber.Ctx) error {
+		return port.Perpustakaan().GetPeminjamanList(c)
+	})
+	perpus.Post("/peminjaman", func(c *fiber.Ctx) error {
+		return port.Perpustakaan().Pinjam(c)
+	})
+	perpus.Post("/peminjaman/:id/kembali", func(c *fiber.Ctx) error {
+		return port.Perpustakaan().Kembalikan(c)
+	})
+	perpus.Post("/peminjaman/:id/perpanjang", func(c *fiber.Ctx) error {
+		return port.Perpustakaan().Perpanjang(c)
+	})
+	perpus.Post("/peminjaman/:id/bayar-denda", func(c *fiber.Ctx) error {
+		return port.Perpustakaan().BayarDenda(c)
+	})
+	perpus.Get("/laporan/terlambat", func(c *fiber.Ctx) error {
+		return port.Perpustakaan().GetOverdue(c)
+	})
+	perpus.Post("/laporan/terlambat/notifikasi", func(c *fiber.Ctx) error {
+		return port.Perpustakaan().SendOverdueNotices(c)
+	})
+	perpus.Get("/laporan/populer", func(c *fiber.Ctx) error {
+		return port.Perpustakaan().GetBukuPopuler(c)
+	})
+	perpus.Get("/config", func(c *fiber.Ctx) error {
+		return port.Perpustakaan().GetConfig(c)
+	})
+	perpus.Put("/config", func(c *fiber.Ctx) error {
+		return port.Perpustakaan().SaveConfig(c)
+	})
+
+	// PPDB Routes (Admission waves, selection, conversion to siswa)
+	ppdb := sekolah.Group("/ppdb", RequireRole(model.RoleAdmin, model.RoleAdminSekolah, model.RoleKepalaSekolah, model.RoleTataUsaha))
+	ppdb.Get("/gelombang", func(c *fiber.Ctx) error {
+		return port.PPDB().GetGelombangList(c)
+	})
+	ppdb.Post("/gelombang", func(c *fiber.Ctx) error {
+		return port.PPDB().CreateGelombang(c)
+	})
+	ppdb.Put("/gelombang/:id", func(c *fiber.Ctx) error {
+		return port.PPDB().UpdateGelombang(c)
+	})
+	ppdb.Get("/pendaftar", func(c *fiber.Ctx) error {
+		return port.PPDB().GetPendaftarList(c)
+	})
+	ppdb.Get("/pendaftar/:id", func(c *fiber.Ctx) error {
+		return port.PPDB().GetPendaftarDetail(c)
+	})
+	ppdb.Put("/pendaftar/:id/status", func(c *fiber.Ctx) error {
+		return port.PPDB().UpdateStatus(c)
+	})
+	ppdb.Post("/pendaftar/:id/dokumen", func(c *fiber.Ctx) error {
+		return port.PPDB().AddDokumen(c)
+	})
+	ppdb.Post("/pendaftar/:id/bayar", func(c *fiber.Ctx) error {
+		return port.PPDB().KonfirmasiBayar(c)
+	})
+	ppdb.Post("/pendaftar/:id/konversi", func(c *fiber.Ctx) error {
+		return port.PPDB().KonversiSiswa(c)
+	})
+
+	// Ujian Routes (PTS/PAS scheduling, seating, proctors, printouts)
+	ujian := sekolah.Group("/ujian", RequireRole(model.RoleAdmin, model.RoleAdminSekolah, model.RoleKepalaSekolah, model.RoleWakilKepsek, model.RoleTataUsaha))
+	ujian.Get("/", func(c *fiber.Ctx) error {
+		return port.Ujian().GetUjianList(c)
+	})
+	ujian.Post("/", func(c *fiber.Ctx) error {
+		return port.Ujian().CreateUjian(c)
+	})
+	ujian.Get("/:id", func(c *fiber.Ctx) error {
+		return port.Ujian().GetUjianDetail(c)
+	})
+	ujian.Put("/:id", func(c *fiber.Ctx) error {
+		return port.Ujian().UpdateUjian(c)
+	})
+	ujian.Post("/:id/jadwal", func(c *fiber.Ctx) error {
+		return port.Ujian().AddJadwal(c)
+	})
+	ujian.Delete("/:id/jadwal/:jadwal_id", func(c *fiber.Ctx) error {
+		return port.Ujian().DeleteJadwal(c)
+	})
+	ujian.Get("/:id/jadwal/:jadwal_id/pengawas", func(c *fiber.Ctx) error {
+		return port.Ujian().GetPengawas(c)
+	})
+	ujian.Post("/:id/jadwal/:jadwal_id/pengawas", func(c *fiber.Ctx) error {
+		return port.Ujian().AssignPengawas(c)
+	})
+	ujian.Delete("/:id/jadwal/:jadwal_id/pengawas/:pengawas_id", func(c *fiber.Ctx) error {
+		return port.Ujian().DeletePengawas(c)
+	})
+	ujian.Post("/:id/ruang", func(c *fiber.Ctx) error {
+		return port.Ujian().AddRuang(c)
+	})
+	ujian.Delete("/:id/ruang/:ruang_id", func(c *fiber.Ctx) error {
+		return port.Ujian().DeleteRuang(c)
+	})
+	ujian.Get("/:id/ruang/:ruang_id/daftar-hadir", func(c *fiber.Ctx) error {
+		return port.Ujian().CetakDaftarHadir(c)
+	})
+	ujian.Post("/:id/tempat-duduk", func(c *fiber.Ctx) error {
+		return port.Ujian().AturTempatDuduk(c)
+	})
+	ujian.Get("/:id/peserta", func(c *fiber.Ctx) error {
+		return port.Ujian().GetPeserta(c)
+	})
+	ujian.Get("/:id/kartu", func(c *fiber.Ctx) error {
+		return port.Ujian().CetakKartu(c)
+	})
+
+	// Dapodik Routes (template-compatible XLSX export/import)
+	dapodik := sekolah.Group("/dapodik", RequireRole(model.RoleAdmin, model.RoleAdminSekolah, model.RoleKepalaSekolah, model.RoleTataUsaha))
+	dapodik.Get("/export", func(c *fiber.Ctx) error {
+		return port.Dapodik().Export(c)
+	})
+	dapodik.Post("/import/preview", func(c *fiber.Ctx) error {
+		return port.Dapodik().PreviewImport(c)
+	})
+	dapodik.Post("/import/apply", func(c *fiber.Ctx) error {
+		return port.Dapodik().ApplyImport(c)
+	})
+
+	// Alumni Routes (registry, kelulusan, tracer study)
+	alumni := sekolah.Group("/alumni", RequireRole(model.RoleAdmin, model.RoleAdminSekolah, model.RoleKepalaSekolah, model.RoleTataUsaha))
+	alumni.Get("/", func(c *fiber.Ctx) error {
+		return port.Alumni().GetAlumniList(c)
+	})
+	alumni.Post("/", func(c *fiber.Ctx) error {
+		return port.Alumni().CreateAlumni(c)
+	})
+	alumni.Post("/kelulusan", func(c *fiber.Ctx) error {
+		return port.Alumni().Kelulusan(c)
+	})
+	alumni.Get("/statistik", func(c *fiber.Ctx) error {
+		return port.Alumni().GetStatistik(c)
+	})
+	alumni.Get("/tracer", func(c *fiber.Ctx) error {
+		return port.Alumni().GetSurveyList(c)
+	})
+	alumni.Post("/tracer", func(c *fiber.Ctx) error {
+		return port.Alumni().CreateSurvey(c)
+	})
+	alumni.Put("/tracer/:id", func(c *fiber.Ctx) error {
+		return port.Alumni().UpdateSurvey(c)
+	})
+	alumni.Get("/tracer/:id/hasil", func(c *fiber.Ctx) error {
+		return port.Alumni().GetSurveyHasil(c)
+	})
+	alumni.Post("/tracer/:id/broadcast", func(c *fiber.Ctx) error {
+		return port.Alumni().BroadcastSurvey(c)
+	})
+	alumni.Get("/:id", func(c *fiber.Ctx) error {
+		return port.Alumni().GetAlumniDetail(c)
+	})
+	alumni.Put("/:id", func(c *fiber.Ctx) error {
+		return port.Alumni().UpdateAlumni(c)
+	})
+	alumni.Post("/:id/kirim-link", func(c *fiber.Ctx) error {
+		return port.Alumni().KirimLinkProfil(c)
+	})
+
 	// Subscription & Billing Routes
 	sub := api.Group("/subscription")
 	sub.Use(func(c *fiber.Ctx) error {
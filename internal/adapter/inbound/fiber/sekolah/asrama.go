@@ -1,11 +1,41 @@
 package sekolah
 
 import (
+	"errors"
+	"time"
+
+	"prabogo/internal/domain/sekolah"
 	"prabogo/internal/model"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// asramaError maps asrama and room placement errors to a response
+func asramaError(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, sekolah.ErrKamarNotFound), errors.Is(err, sekolah.ErrSantriNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, sekolah.ErrKamarPenuh), errors.Is(err, sekolah.ErrKamarPerbaikan),
+		errors.Is(err, sekolah.ErrSudahDitempatkan), errors.Is(err, sekolah.ErrBelumDitempatkan),
+		errors.Is(err, sekolah.ErrKamarSama):
+		status = fiber.StatusConflict
+	case errors.Is(err, sekolah.ErrAsramaTidakSah), errors.Is(err, sekolah.ErrKamarTidakSah),
+		errors.Is(err, sekolah.ErrJenisKelaminKosong), errors.Is(err, sekolah.ErrJenisAsramaTidakSesuai),
+		errors.Is(err, sekolah.ErrUrutanAlokasiTidakSah):
+		status = fiber.StatusBadRequest
+	}
+	return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+}
+
+// parseTanggal reads an optional YYYY-MM-DD date; empty means today
+func parseTanggal(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
+
 // Asrama Handlers
 func (h *akademikHandler) GetAsramaList(c *fiber.Ctx) error {
 	tenantID := c.Locals("tenant_id").(string)
@@ -24,7 +54,7 @@ func (h *akademikHandler) CreateAsrama(c *fiber.Ctx) error {
 	}
 
 	if err := h.service.CreateAsrama(c.Context(), tenantID, &req); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return asramaError(c, err)
 	}
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "Asrama created", "data": req})
 }
@@ -48,7 +78,7 @@ func (h *akademikHandler) CreateKamar(c *fiber.Ctx) error {
 	}
 
 	if err := h.service.CreateKamar(c.Context(), tenantID, &req); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return asramaError(c, err)
 	}
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "Kamar created", "data": req})
 }
@@ -71,7 +101,99 @@ func (h *akademikHandler) CreatePenempatan(c *fiber.Ctx) error {
 	}
 
 	if err := h.service.CreatePenempatan(c.Context(), tenantID, &req); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return asramaError(c, err)
 	}
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "Penempatan created", "data": req})
 }
+
+// pindahKamarInput is the body of pindah kamar and keluar asrama; keluar ignores kamar_id
+type pindahKamarInput struct {
+	SantriID   string `json:"santri_id"`
+	KamarID    string `json:"kamar_id"`
+	Tanggal    string `json:"tanggal"` // YYYY-MM-DD, default today
+	Keterangan string `json:"keterangan"`
+}
+
+func (h *akademikHandler) PindahKamar(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	var req pindahKamarInput
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
+	}
+	tanggal, err := parseTanggal(req.Tanggal)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format tanggal harus YYYY-MM-DD"})
+	}
+	data, err := h.service.PindahKamar(c.Context(), tenantID, req.SantriID, req.KamarID, tanggal, req.Keterangan)
+	if err != nil {
+		return asramaError(c, err)
+	}
+	return c.JSON(fiber.Map{"message": "Santri moved", "data": data})
+}
+
+func (h *akademikHandler) KeluarAsrama(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	var req pindahKamarInput
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
+	}
+	tanggal, err := parseTanggal(req.Tanggal)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format tanggal harus YYYY-MM-DD"})
+	}
+	data, err := h.service.KeluarAsrama(c.Context(), tenantID, req.SantriID, tanggal, req.Keterangan)
+	if err != nil {
+		return asramaError(c, err)
+	}
+	return c.JSON(fiber.Map{"message": "Penempatan closed", "data": data})
+}
+
+func (h *akademikHandler) GetRiwayatPenempatan(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	data, err := h.service.GetRiwayatPenempatan(c.Context(), tenantID, c.Params("santri_id"))
+	if err != nil {
+		return asramaError(c, err)
+	}
+	return c.JSON(fiber.Map{"data": data})
+}
+
+// AlokasiPenempatan auto-fills rooms: {"urutan": "kelas", "kelas_id": "...", "simulasi": true}
+func (h *akademikHandler) AlokasiPenempatan(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	var req struct {
+		SantriIDs    []string `json:"santri_ids"`
+		KelasID      string   `json:"kelas_id"`
+		AsramaID     string   `json:"asrama_id"`
+		Urutan       string   `json:"urutan"`
+		TanggalMasuk string   `json:"tanggal_masuk"` // YYYY-MM-DD, default today
+		Simulasi     bool     `json:"simulasi"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
+	}
+	tanggal, err := parseTanggal(req.TanggalMasuk)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format tanggal harus YYYY-MM-DD"})
+	}
+	data, err := h.service.AlokasiPenempatan(c.Context(), tenantID, model.AlokasiInput{
+		SantriIDs:    req.SantriIDs,
+		KelasID:      req.KelasID,
+		AsramaID:     req.AsramaID,
+		Urutan:       req.Urutan,
+		TanggalMasuk: tanggal,
+		Simulasi:     req.Simulasi,
+	})
+	if err != nil {
+		return asramaError(c, err)
+	}
+	return c.JSON(fiber.Map{"data": data})
+}
+
+func (h *akademikHandler) GetOkupansiAsrama(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	data, err := h.service.GetOkupansiAsrama(c.Context(), tenantID)
+	if err != nil {
+		return asramaError(c, err)
+	}
+	return c.JSON(fiber.Map{"data": data})
+}
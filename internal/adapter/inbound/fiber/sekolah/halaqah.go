@@ -0,0 +1,225 @@
+package sekolah
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"prabogo/internal/domain/sekolah"
+	"prabogo/internal/model"
+	"prabogo/utils/semester"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// halaqahError maps halaqah, target and murajaah errors to a response
+func halaqahError(c *fiber.Ctx, err error) error {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, sekolah.ErrHalaqahNotFound), errors.Is(err, sekolah.ErrSantriNotFound),
+		errors.Is(err, sekolah.ErrTargetNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, sekolah.ErrHalaqahPenuh):
+		status = http.StatusConflict
+	case errors.Is(err, sekolah.ErrUstadzNotFound), errors.Is(err, sekolah.ErrTargetTidakSah),
+		errors.Is(err, sekolah.ErrSiklusTidakSah), errors.Is(err, semester.ErrTidakSah):
+		status = http.StatusBadRequest
+	}
+	return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+}
+
+// ------ Halaqah ------
+
+func (h *akademikHandler) GetHalaqahList(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	data, err := h.service.GetHalaqahList(c.Context(), tenantID)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	if data == nil {
+		data = []model.Halaqah{}
+	}
+	return c.JSON(fiber.Map{"data": data})
+}
+
+func (h *akademikHandler) GetHalaqah(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	data, err := h.service.GetHalaqah(c.Context(), tenantID, c.Params("id"))
+	if err != nil {
+		return halaqahError(c, err)
+	}
+	return c.JSON(fiber.Map{"data": data})
+}
+
+func (h *akademikHandler) CreateHalaqah(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	var m model.Halaqah
+	if err := c.BodyParser(&m); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if err := h.service.CreateHalaqah(c.Context(), tenantID, &m); err != nil {
+		if errors.Is(err, sekolah.ErrUstadzNotFound) {
+			return halaqahError(c, err)
+		}
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(http.StatusCreated).JSON(fiber.Map{"message": "Halaqah created", "data": m})
+}
+
+func (h *akademikHandler) UpdateHalaqah(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	var m model.Halaqah
+	if err := c.BodyParser(&m); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	m.ID = c.Params("id")
+	if err := h.service.UpdateHalaqah(c.Context(), tenantID, &m); err != nil {
+		return halaqahError(c, err)
+	}
+	return c.JSON(fiber.Map{"message": "Halaqah updated", "data": m})
+}
+
+func (h *akademikHandler) DeleteHalaqah(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	if err := h.service.DeleteHalaqah(c.Context(), tenantID, c.Params("id")); err != nil {
+		return halaqahError(c, err)
+	}
+	return c.JSON(fiber.Map{"message": "Halaqah deleted"})
+}
+
+func (h *akademikHandler) AddHalaqahAnggota(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	var in struct {
+		SantriID string `json:"santri_id"`
+	}
+	if err := c.BodyParser(&in); err != nil || in.SantriID == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "santri_id is required"})
+	}
+	if err := h.service.AddHalaqahAnggota(c.Context(), tenantID, c.Params("id"), in.SantriID); err != nil {
+		return halaqahError(c, err)
+	}
+	return c.Status(http.StatusCreated).JSON(fiber.Map{"message": "Santri added to halaqah"})
+}
+
+func (h *akademikHandler) RemoveHalaqahAnggota(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	if err := h.service.RemoveHalaqahAnggota(c.Context(), tenantID, c.Params("id"), c.Params("santri_id")); err != nil {
+		return halaqahError(c, err)
+	}
+	return c.JSON(fiber.Map{"message": "Santri removed from halaqah"})
+}
+
+// ------ Target hafalan ------
+
+// targetHafalanInput is the payload of a hafalan target; the dates default to the semester's
+type targetHafalanInput struct {
+	SantriID   string  `json:"santri_id"`
+	SemesterID string  `json:"semester_id"`
+	TargetJuz  float64 `json:"target_juz"`
+	Mulai      string  `json:"mulai"`   // YYYY-MM-DD
+	Selesai    string  `json:"selesai"` // YYYY-MM-DD
+	Catatan    string  `json:"catatan"`
+}
+
+func (in targetHafalanInput) toModel() (*model.TargetHafalan, error) {
+	t := &model.TargetHafalan{
+		SantriID:   in.SantriID,
+		SemesterID: in.SemesterID,
+		TargetJuz:  in.TargetJuz,
+		Catatan:    in.Catatan,
+	}
+	var err error
+	if in.Mulai != "" {
+		if t.Mulai, err = time.Parse("2006-01-02", in.Mulai); err != nil {
+			return nil, err
+		}
+	}
+	if in.Selesai != "" {
+		if t.Selesai, err = time.Parse("2006-01-02", in.Selesai); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+func (h *akademikHandler) GetTahfidzTargets(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	data, err := h.service.GetTahfidzTargets(c.Context(), tenantID, c.Query("semester_id"))
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	if data == nil {
+		data = []model.TargetHafalan{}
+	}
+	return c.JSON(fiber.Map{"data": data})
+}
+
+func (h *akademikHandler) SaveTahfidzTarget(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	var in targetHafalanInput
+	if err := c.BodyParser(&in); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	m, err := in.toModel()
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "mulai and selesai must be YYYY-MM-DD"})
+	}
+	if err := h.service.SaveTahfidzTarget(c.Context(), tenantID, m); err != nil {
+		return halaqahError(c, err)
+	}
+	return c.JSON(fiber.Map{"message": "Target saved", "data": m})
+}
+
+func (h *akademikHandler) DeleteTahfidzTarget(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	if err := h.service.DeleteTahfidzTarget(c.Context(), tenantID, c.Params("id")); err != nil {
+		return halaqahError(c, err)
+	}
+	return c.JSON(fiber.Map{"message": "Target deleted"})
+}
+
+func (h *akademikHandler) GetTahfidzTargetStatus(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	data, err := h.service.GetTahfidzTargetStatus(c.Context(), tenantID, c.Query("semester_id"))
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"data": data})
+}
+
+// ------ Murajaah & alerts ------
+
+// GetMurajaahPlan serves the murajaah rotation of a santri: ?siklus=7&mulai=YYYY-MM-DD
+func (h *akademikHandler) GetMurajaahPlan(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	var mulai time.Time
+	if q := c.Query("mulai"); q != "" {
+		var err error
+		if mulai, err = time.Parse("2006-01-02", q); err != nil {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "mulai must be YYYY-MM-DD"})
+		}
+	}
+	data, err := h.service.GetMurajaahPlan(c.Context(), tenantID, c.Params("santri_id"), c.QueryInt("siklus"), mulai)
+	if err != nil {
+		return halaqahError(c, err)
+	}
+	return c.JSON(fiber.Map{"data": data})
+}
+
+func (h *akademikHandler) GetTahfidzAlerts(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	data, err := h.service.GetTahfidzAlerts(c.Context(), tenantID)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"data": data})
+}
+
+func (h *akademikHandler) KirimTahfidzAlerts(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	sent, err := h.service.KirimTahfidzAlerts(c.Context(), tenantID)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"message": "Tahfidz alerts sent", "sent": sent})
+}
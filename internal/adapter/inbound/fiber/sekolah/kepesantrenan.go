@@ -59,10 +59,11 @@ func (h *akademikHandler) CreatePelanggaranSiswa(c *fiber.Ctx) error {
 	if err := c.BodyParser(&m); err != nil {
 		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
 	}
-	if err := h.service.CreatePelanggaranSiswa(c.Context(), tenantID, &m); err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	sanksi, err := h.service.CreatePelanggaranSiswa(c.Context(), tenantID, &m)
+	if err != nil {
+		return poinError(c, err)
 	}
-	return c.Status(http.StatusCreated).JSON(fiber.Map{"message": "Pelanggaran recorded", "data": m})
+	return c.Status(http.StatusCreated).JSON(fiber.Map{"message": "Pelanggaran recorded", "data": m, "sanksi": sanksi})
 }
 
 func (h *akademikHandler) GetPerizinanList(c *fiber.Ctx) error {
@@ -81,7 +82,7 @@ func (h *akademikHandler) CreatePerizinan(c *fiber.Ctx) error {
 		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
 	}
 	if err := h.service.CreatePerizinan(c.Context(), tenantID, &m); err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return perizinanError(c, err)
 	}
 	return c.Status(http.StatusCreated).JSON(fiber.Map{"message": "Perizinan created", "data": m})
 }
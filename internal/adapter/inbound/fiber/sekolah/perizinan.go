@@ -0,0 +1,135 @@
+package sekolah
+
+import (
+	"errors"
+	"net/http"
+
+	"prabogo/internal/domain/sekolah"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// perizinanError maps leave workflow errors to a response
+func perizinanError(c *fiber.Ctx, err error) error {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, sekolah.ErrPerizinanNotFound), errors.Is(err, sekolah.ErrGatePassTidakSah),
+		errors.Is(err, sekolah.ErrAturanNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, sekolah.ErrStatusPerizinanTidakSah), errors.Is(err, sekolah.ErrIzinBerakhir):
+		status = http.StatusConflict
+	case errors.Is(err, sekolah.ErrPerizinanTidakSah), errors.Is(err, sekolah.ErrAlasanPenolakanKosong):
+		status = http.StatusBadRequest
+	}
+	return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+}
+
+func (h *akademikHandler) GetPerizinan(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	data, err := h.service.GetPerizinan(c.Context(), tenantID, c.Params("id"))
+	if err != nil {
+		return perizinanError(c, err)
+	}
+	return c.JSON(fiber.Map{"data": data})
+}
+
+// perizinanKeputusan is the body of reject; the penyetuju is always the logged-in user
+type perizinanKeputusan struct {
+	Catatan string `json:"catatan"`
+}
+
+func (h *akademikHandler) ApprovePerizinan(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID, _ := c.Locals("user_id").(string)
+	data, err := h.service.ApprovePerizinan(c.Context(), tenantID, c.Params("id"), userID)
+	if err != nil {
+		return perizinanError(c, err)
+	}
+	return c.JSON(fiber.Map{"message": "Perizinan approved", "data": data})
+}
+
+func (h *akademikHandler) RejectPerizinan(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID, _ := c.Locals("user_id").(string)
+	var in perizinanKeputusan
+	if err := c.BodyParser(&in); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	data, err := h.service.RejectPerizinan(c.Context(), tenantID, c.Params("id"), userID, in.Catatan)
+	if err != nil {
+		return perizinanError(c, err)
+	}
+	return c.JSON(fiber.Map{"message": "Perizinan rejected", "data": data})
+}
+
+// GetGatePass returns the pass whose kode the client renders as a QR code
+func (h *akademikHandler) GetGatePass(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	data, err := h.service.GetGatePass(c.Context(), tenantID, c.Params("id"))
+	if err != nil {
+		return perizinanError(c, err)
+	}
+	return c.JSON(fiber.Map{"data": data})
+}
+
+func (h *akademikHandler) CheckOutPerizinan(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	data, err := h.service.CheckOutPerizinan(c.Context(), tenantID, c.Params("id"))
+	if err != nil {
+		return perizinanError(c, err)
+	}
+	return c.JSON(fiber.Map{"message": "Santri checked out", "data": data})
+}
+
+// CheckInPerizinan accepts an optional {"aturan_id": "..."} to record a late return as a violation
+func (h *akademikHandler) CheckInPerizinan(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	var in struct {
+		AturanID string `json:"aturan_id"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&in); err != nil {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+	}
+	data, err := h.service.CheckInPerizinan(c.Context(), tenantID, c.Params("id"), in.AturanID)
+	if err != nil {
+		return perizinanError(c, err)
+	}
+	return c.JSON(fiber.Map{"message": "Santri checked in", "data": data})
+}
+
+// ScanGatePass handles a scan at the gate: {"kode": "...", "aturan_id": "..."}
+func (h *akademikHandler) ScanGatePass(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	var in struct {
+		Kode     string `json:"kode"`
+		AturanID string `json:"aturan_id"`
+	}
+	if err := c.BodyParser(&in); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	data, err := h.service.ScanGatePass(c.Context(), tenantID, in.Kode, in.AturanID)
+	if err != nil {
+		return perizinanError(c, err)
+	}
+	return c.JSON(fiber.Map{"message": "Santri " + data.Status, "data": data})
+}
+
+func (h *akademikHandler) GetPerizinanTerlambat(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	data, err := h.service.GetPerizinanTerlambat(c.Context(), tenantID)
+	if err != nil {
+		return perizinanError(c, err)
+	}
+	return c.JSON(fiber.Map{"data": data})
+}
+
+func (h *akademikHandler) KirimPerizinanTerlambat(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	sent, err := h.service.KirimPerizinanTerlambat(c.Context(), tenantID)
+	if err != nil {
+		return perizinanError(c, err)
+	}
+	return c.JSON(fiber.Map{"message": "Overdue notices sent", "sent": sent})
+}
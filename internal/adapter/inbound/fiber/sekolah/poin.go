@@ -0,0 +1,126 @@
+package sekolah
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"prabogo/internal/domain/sekolah"
+	"prabogo/internal/model"
+	"prabogo/utils/semester"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// poinError maps violation point and sanction errors to a response
+func poinError(c *fiber.Ctx, err error) error {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, sekolah.ErrSanksiNotFound), errors.Is(err, sekolah.ErrSantriNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, sekolah.ErrStatusSanksiTidakSah), errors.Is(err, sekolah.ErrAmbangTidakSah),
+		errors.Is(err, sekolah.ErrKreditTidakSah), errors.Is(err, sekolah.ErrPoinPelanggaranNegatif),
+		errors.Is(err, semester.ErrTidakSah):
+		status = http.StatusBadRequest
+	}
+	return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+}
+
+func (h *akademikHandler) GetSanksiAmbang(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	data, err := h.service.GetSanksiAmbang(c.Context(), tenantID)
+	if err != nil {
+		return poinError(c, err)
+	}
+	return c.JSON(fiber.Map{"data": data})
+}
+
+// SaveSanksiAmbang replaces the threshold ladder: {"ambang": [{"poin": 25, "jenis": "Teguran"}]}
+func (h *akademikHandler) SaveSanksiAmbang(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	var in struct {
+		Ambang []model.SanksiAmbang `json:"ambang"`
+	}
+	if err := c.BodyParser(&in); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	data, err := h.service.SaveSanksiAmbang(c.Context(), tenantID, in.Ambang)
+	if err != nil {
+		return poinError(c, err)
+	}
+	return c.JSON(fiber.Map{"message": "Ambang sanksi saved", "data": data})
+}
+
+func (h *akademikHandler) GetRekapPoin(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	data, err := h.service.GetRekapPoin(c.Context(), tenantID, c.Query("semester_id"))
+	if err != nil {
+		return poinError(c, err)
+	}
+	return c.JSON(fiber.Map{"data": data})
+}
+
+func (h *akademikHandler) GetPoinSantri(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	data, err := h.service.GetPoinSantri(c.Context(), tenantID, c.Params("santri_id"), c.Query("semester_id"))
+	if err != nil {
+		return poinError(c, err)
+	}
+	return c.JSON(fiber.Map{"data": data})
+}
+
+func (h *akademikHandler) CreatePoinKredit(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	var m model.PoinKredit
+	if err := c.BodyParser(&m); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if err := h.service.CreatePoinKredit(c.Context(), tenantID, &m); err != nil {
+		return poinError(c, err)
+	}
+	return c.Status(http.StatusCreated).JSON(fiber.Map{"message": "Kredit poin recorded", "data": m})
+}
+
+func (h *akademikHandler) GetSanksiList(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	data, err := h.service.GetSanksiList(c.Context(), tenantID, c.Query("semester_id"), c.Query("status"))
+	if err != nil {
+		return poinError(c, err)
+	}
+	return c.JSON(fiber.Map{"data": data})
+}
+
+func (h *akademikHandler) UpdateSanksiStatus(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	var in struct {
+		Status  string `json:"status"`
+		Catatan string `json:"catatan"`
+	}
+	if err := c.BodyParser(&in); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	data, err := h.service.UpdateSanksiStatus(c.Context(), tenantID, c.Params("id"), in.Status, in.Catatan)
+	if err != nil {
+		return poinError(c, err)
+	}
+	return c.JSON(fiber.Map{"message": "Sanksi updated", "data": data})
+}
+
+func (h *akademikHandler) KirimSanksiWali(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	if err := h.service.KirimSanksiWali(c.Context(), tenantID, c.Params("id")); err != nil {
+		return poinError(c, err)
+	}
+	return c.JSON(fiber.Map{"message": "Wali notified"})
+}
+
+func (h *akademikHandler) CetakSuratPeringatan(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	pdfBytes, err := h.service.CetakSuratPeringatan(c.Context(), tenantID, c.Params("id"))
+	if err != nil {
+		return poinError(c, err)
+	}
+	c.Set("Content-Type", "application/pdf")
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"surat-peringatan-%s.pdf\"", c.Params("id")))
+	return c.Send(pdfBytes)
+}
@@ -23,10 +23,8 @@ func (h *akademikHandler) CreateRapor(c *fiber.Ctx) error {
 		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
 	}
 
-	// Default status if not provided
-	if m.Status == "" {
-		m.Status = "Draft"
-	}
+	// New rapor always start as Draft, the status only moves through the approval workflow
+	m.Status = model.RaporStatusDraft
 
 	if err := h.service.CreateRapor(c.Context(), tenantID, &m); err != nil {
 		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
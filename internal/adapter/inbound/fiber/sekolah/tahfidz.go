@@ -1,7 +1,9 @@
 package sekolah
 
 import (
+	"errors"
 	"net/http"
+	"prabogo/internal/domain/sekolah"
 	"prabogo/internal/model"
 
 	"github.com/gofiber/fiber/v2"
@@ -23,7 +25,37 @@ func (h *akademikHandler) CreateTahfidzSetoran(c *fiber.Ctx) error {
 		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
 	}
 	if err := h.service.CreateTahfidzSetoran(c.Context(), tenantID, &m); err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		status := http.StatusInternalServerError
+		if errors.Is(err, sekolah.ErrSetoranTidakSah) {
+			status = http.StatusBadRequest
+		}
+		return c.Status(status).JSON(fiber.Map{"error": err.Error()})
 	}
 	return c.Status(http.StatusCreated).JSON(fiber.Map{"message": "Setoran recorded", "data": m})
 }
+
+func (h *akademikHandler) GetTahfidzProgress(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	data, err := h.service.GetTahfidzProgress(c.Context(), tenantID, c.Params("santri_id"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, sekolah.ErrSantriNotFound) {
+			status = http.StatusNotFound
+		}
+		return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"data": data})
+}
+
+func (h *akademikHandler) GetTahfidzLeaderboard(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	data, err := h.service.GetTahfidzLeaderboard(c.Context(), tenantID, c.Query("halaqah_id"))
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"data": data})
+}
+
+func (h *akademikHandler) GetDaftarSurah(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"data": h.service.GetDaftarSurah()})
+}
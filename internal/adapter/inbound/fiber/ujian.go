@@ -0,0 +1,407 @@
+package fiber_inbound_adapter
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"prabogo/internal/domain"
+	ujian_domain "prabogo/internal/domain/ujian"
+	"prabogo/internal/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ujianAdapter struct {
+	domain domain.Domain
+}
+
+func NewUjianAdapter(d domain.Domain) *ujianAdapter {
+	return &ujianAdapter{domain: d}
+}
+
+// ujianError maps domain errors to HTTP status codes
+func ujianError(c *fiber.Ctx, err error, message string) error {
+	status := fiber.StatusBadRequest
+	switch {
+	case errors.Is(err, ujian_domain.ErrUjianNotFound),
+		errors.Is(err, ujian_domain.ErrJadwalNotFound),
+		errors.Is(err, ujian_domain.ErrRuangNotFound):
+		status = fiber.StatusNotFound
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"status":  "error",
+		"message": message + ": " + err.Error(),
+	})
+}
+
+// ==========================================
+// UJIAN HANDLERS
+// ==========================================
+
+// ujianInput is the payload for an exam event
+type ujianInput struct {
+	Nama           string `json:"nama"`
+	Jenis          string `json:"jenis"`
+	SemesterID     string `json:"semester_id"`
+	TanggalMulai   string `json:"tanggal_mulai"`   // YYYY-MM-DD
+	TanggalSelesai string `json:"tanggal_selesai"` // YYYY-MM-DD
+	CekSPP         bool   `json:"cek_spp"`
+}
+
+func (in ujianInput) toModel() (*model.Ujian, error) {
+	mulai, err := time.Parse("2006-01-02", in.TanggalMulai)
+	if err != nil {
+		return nil, err
+	}
+	selesai, err := time.Parse("2006-01-02", in.TanggalSelesai)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Ujian{
+		Nama:           in.Nama,
+		Jenis:          in.Jenis,
+		SemesterID:     in.SemesterID,
+		TanggalMulai:   mulai,
+		TanggalSelesai: selesai,
+		CekSPP:         in.CekSPP,
+	}, nil
+}
+
+// GET /api/v1/sekolah/ujian?semester_id=
+func (h *ujianAdapter) GetUjianList(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	list, err := h.domain.Ujian().GetUjianList(c.Context(), tenantID, c.Query("semester_id"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data ujian",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   list,
+	})
+}
+
+// GET /api/v1/sekolah/ujian/:id
+func (h *ujianAdapter) GetUjianDetail(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	ujian, jadwal, ruang, err := h.domain.Ujian().GetUjianDetail(c.Context(), tenantID, c.Params("id"))
+	if err != nil {
+		return ujianError(c, err, "Gagal mengambil detail ujian")
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data": fiber.Map{
+			"ujian":  ujian,
+			"jadwal": jadwal,
+			"ruang":  ruang,
+		},
+	})
+}
+
+// POST /api/v1/sekolah/ujian
+func (h *ujianAdapter) CreateUjian(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input ujianInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+	ujian, err := input.toModel()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format tanggal tidak valid (YYYY-MM-DD)",
+		})
+	}
+	ujian.TenantID = tenantID
+
+	if err := h.domain.Ujian().CreateUjian(c.Context(), ujian); err != nil {
+		return ujianError(c, err, "Gagal membuat ujian")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Ujian berhasil dibuat",
+		"data":    ujian,
+	})
+}
+
+// PUT /api/v1/sekolah/ujian/:id
+func (h *ujianAdapter) UpdateUjian(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input ujianInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+	ujian, err := input.toModel()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format tanggal tidak valid (YYYY-MM-DD)",
+		})
+	}
+	ujian.ID = c.Params("id")
+	ujian.TenantID = tenantID
+
+	if err := h.domain.Ujian().UpdateUjian(c.Context(), ujian); err != nil {
+		return ujianError(c, err, "Gagal mengupdate ujian")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Ujian berhasil diupdate",
+		"data":    ujian,
+	})
+}
+
+// ==========================================
+// JADWAL & RUANG HANDLERS
+// ==========================================
+
+// POST /api/v1/sekolah/ujian/:id/jadwal
+func (h *ujianAdapter) AddJadwal(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input struct {
+		SubjectID  string `json:"subject_id"`
+		Tingkat    string `json:"tingkat"`
+		Tanggal    string `json:"tanggal"`     // YYYY-MM-DD
+		JamMulai   string `json:"jam_mulai"`   // HH:MM
+		JamSelesai string `json:"jam_selesai"` // HH:MM
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+
+	mulai, errMulai := time.ParseInLocation("2006-01-02 15:04", input.Tanggal+" "+input.JamMulai, time.Local)
+	selesai, errSelesai := time.ParseInLocation("2006-01-02 15:04", input.Tanggal+" "+input.JamSelesai, time.Local)
+	if errMulai != nil || errSelesai != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format tanggal/jam tidak valid (YYYY-MM-DD, HH:MM)",
+		})
+	}
+
+	jadwal := model.UjianJadwal{
+		UjianID:      c.Params("id"),
+		SubjectID:    input.SubjectID,
+		Tingkat:      input.Tingkat,
+		WaktuMulai:   mulai,
+		WaktuSelesai: selesai,
+	}
+	if err := h.domain.Ujian().AddJadwal(c.Context(), tenantID, &jadwal); err != nil {
+		return ujianError(c, err, "Gagal menambahkan jadwal")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Jadwal berhasil ditambahkan",
+		"data":    jadwal,
+	})
+}
+
+// DELETE /api/v1/sekolah/ujian/:id/jadwal/:jadwal_id
+func (h *ujianAdapter) DeleteJadwal(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	if err := h.domain.Ujian().DeleteJadwal(c.Context(), tenantID, c.Params("id"), c.Params("jadwal_id")); err != nil {
+		return ujianError(c, err, "Gagal menghapus jadwal")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Jadwal berhasil dihapus",
+	})
+}
+
+// POST /api/v1/sekolah/ujian/:id/ruang
+func (h *ujianAdapter) AddRuang(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input model.UjianRuang
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+	input.UjianID = c.Params("id")
+
+	if err := h.domain.Ujian().AddRuang(c.Context(), tenantID, &input); err != nil {
+		return ujianError(c, err, "Gagal menambahkan ruang")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Ruang berhasil ditambahkan",
+		"data":    input,
+	})
+}
+
+// DELETE /api/v1/sekolah/ujian/:id/ruang/:ruang_id
+func (h *ujianAdapter) DeleteRuang(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	if err := h.domain.Ujian().DeleteRuang(c.Context(), tenantID, c.Params("id"), c.Params("ruang_id")); err != nil {
+		return ujianError(c, err, "Gagal menghapus ruang")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Ruang berhasil dihapus",
+	})
+}
+
+// ==========================================
+// TEMPAT DUDUK HANDLERS
+// ==========================================
+
+// POST /api/v1/sekolah/ujian/:id/tempat-duduk
+func (h *ujianAdapter) AturTempatDuduk(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input struct {
+		Tingkat []string `json:"tingkat"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&input); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Format data tidak valid",
+			})
+		}
+	}
+
+	peserta, err := h.domain.Ujian().AturTempatDuduk(c.Context(), tenantID, c.Params("id"), input.Tingkat)
+	if err != nil {
+		return ujianError(c, err, "Gagal mengatur tempat duduk")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": fmt.Sprintf("Tempat duduk %d peserta berhasil diatur", len(peserta)),
+		"data":    peserta,
+	})
+}
+
+// GET /api/v1/sekolah/ujian/:id/peserta?ruang_id=&kelas_id=
+func (h *ujianAdapter) GetPeserta(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	peserta, err := h.domain.Ujian().GetPeserta(c.Context(), tenantID, c.Params("id"), c.Query("ruang_id"), c.Query("kelas_id"))
+	if err != nil {
+		return ujianError(c, err, "Gagal mengambil peserta ujian")
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   peserta,
+	})
+}
+
+// ==========================================
+// PENGAWAS HANDLERS
+// ==========================================
+
+// GET /api/v1/sekolah/ujian/:id/jadwal/:jadwal_id/pengawas
+func (h *ujianAdapter) GetPengawas(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	list, err := h.domain.Ujian().GetPengawas(c.Context(), tenantID, c.Params("id"), c.Params("jadwal_id"))
+	if err != nil {
+		return ujianError(c, err, "Gagal mengambil pengawas")
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   list,
+	})
+}
+
+// POST /api/v1/sekolah/ujian/:id/jadwal/:jadwal_id/pengawas
+func (h *ujianAdapter) AssignPengawas(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var input model.UjianPengawas
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format data tidak valid",
+		})
+	}
+	input.JadwalID = c.Params("jadwal_id")
+
+	if err := h.domain.Ujian().AssignPengawas(c.Context(), tenantID, c.Params("id"), &input); err != nil {
+		return ujianError(c, err, "Gagal menugaskan pengawas")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Pengawas berhasil ditugaskan",
+		"data":    input,
+	})
+}
+
+// DELETE /api/v1/sekolah/ujian/:id/jadwal/:jadwal_id/pengawas/:pengawas_id
+func (h *ujianAdapter) DeletePengawas(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	err := h.domain.Ujian().DeletePengawas(c.Context(), tenantID, c.Params("id"), c.Params("jadwal_id"), c.Params("pengawas_id"))
+	if err != nil {
+		return ujianError(c, err, "Gagal menghapus pengawas")
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Pengawas berhasil dihapus",
+	})
+}
+
+// ==========================================
+// CETAK HANDLERS
+// ==========================================
+
+// GET /api/v1/sekolah/ujian/:id/kartu?ruang_id=&kelas_id=
+func (h *ujianAdapter) CetakKartu(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	pdfBytes, err := h.domain.Ujian().CetakKartu(c.Context(), tenantID, c.Params("id"), c.Query("ruang_id"), c.Query("kelas_id"))
+	if err != nil {
+		return ujianError(c, err, "Gagal generate PDF kartu ujian")
+	}
+
+	c.Set("Content-Type", "application/pdf")
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"kartu-ujian-%s.pdf\"", c.Params("id")))
+	return c.Send(pdfBytes)
+}
+
+// GET /api/v1/sekolah/ujian/:id/ruang/:ruang_id/daftar-hadir?jadwal_id=
+func (h *ujianAdapter) CetakDaftarHadir(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	pdfBytes, err := h.domain.Ujian().CetakDaftarHadir(c.Context(), tenantID, c.Params("id"), c.Params("ruang_id"), c.Query("jadwal_id"))
+	if err != nil {
+		return ujianError(c, err, "Gagal generate PDF daftar hadir")
+	}
+
+	c.Set("Content-Type", "application/pdf")
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"daftar-hadir-%s.pdf\"", c.Params("ruang_id")))
+	return c.Send(pdfBytes)
+}
@@ -0,0 +1,46 @@
+package rabbitmq_inbound_adapter
+
+import (
+	"context"
+	"encoding/json"
+
+	"prabogo/internal/domain"
+	"prabogo/internal/model"
+	inbound_port "prabogo/internal/port/inbound"
+	"prabogo/utils/activity"
+	"prabogo/utils/log"
+)
+
+type raporAdapter struct {
+	domain domain.Domain
+}
+
+func NewRaporAdapter(
+	domain domain.Domain,
+) inbound_port.RaporMessagePort {
+	return &raporAdapter{
+		domain: domain,
+	}
+}
+
+func (h *raporAdapter) GenerateBatch(a any) bool {
+	msg := a.([]byte)
+	ctx := activity.NewContext("message_rapor_generate_batch")
+	var payload model.RaporBatchMessage
+	err := json.Unmarshal(msg, &payload)
+	if err != nil {
+		log.WithContext(ctx).Errorf("rapor generate batch error %s: %s", err.Error(), string(msg))
+		return true
+	}
+	ctx = context.WithValue(ctx, activity.Payload, payload)
+
+	batch, err := h.domain.ERapor().ProcessBatch(ctx, payload.TenantID, payload.BatchID)
+	if err != nil {
+		log.WithContext(ctx).Errorf("rapor generate batch error %s: %s", err.Error(), string(msg))
+		return true
+	}
+	ctx = context.WithValue(ctx, activity.Result, batch)
+
+	log.WithContext(ctx).Info("rapor generate batch success")
+	return true
+}
@@ -20,3 +20,7 @@ func NewAdapter(
 func (a *adapter) Client() inbound_port.ClientMessagePort {
 	return NewClientAdapter(a.domain)
 }
+
+func (a *adapter) Rapor() inbound_port.RaporMessagePort {
+	return NewRaporAdapter(a.domain)
+}
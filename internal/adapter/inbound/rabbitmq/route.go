@@ -36,6 +36,29 @@ func InitRoute(
 				close(done)
 			}()
 			<-done
+		case "generate_rapor_batch":
+			log.WithContext(ctx).Info("message subscribe generate rapor batch started")
+			queue := os.Getenv("GENERATE_RAPOR_BATCH_MESSAGE_SUBSCRIBE")
+			if queue == "" {
+				queue = model.GenerateRaporBatchMessage
+			}
+			done := make(chan struct{})
+			go func() {
+				err := rabbitmq.Subscriber(
+					model.GenerateRaporBatchMessage,
+					rabbitmq.KindFanOut,
+					queue,
+					"",
+					func(msg []byte) bool {
+						return port.Rapor().GenerateBatch(msg)
+					},
+				)
+				if err != nil {
+					log.WithContext(ctx).Errorf("failed to subscribe to %s: %s", model.GenerateRaporBatchMessage, err)
+				}
+				close(done)
+			}()
+			<-done
 		default:
 			log.WithContext(ctx).Info("message subscribe not found")
 		}
@@ -0,0 +1,439 @@
+package postgres_outbound_adapter
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"prabogo/internal/model"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/google/uuid"
+)
+
+type alumniAdapter struct {
+	db *goqu.Database
+}
+
+func NewAlumniAdapter(sqlDB *sql.DB) *alumniAdapter {
+	return &alumniAdapter{db: goqu.New("postgres", sqlDB)}
+}
+
+// ==========================================
+// KELULUSAN
+// ==========================================
+
+func (a *alumniAdapter) GetCalonAlumni(ctx context.Context, tenantID, kelasID string, siswaIDs []string) ([]model.Alumni, error) {
+	ds := a.db.From(goqu.T("sekolah_siswa").As("s")).
+		LeftJoin(goqu.T("sekolah_kelas").As("k"), goqu.On(goqu.I("k.id").Eq(goqu.I("s.kelas_id")))).
+		Select(
+			goqu.I("s.id").As("siswa_id"),
+			goqu.I("s.tenant_id"),
+			goqu.I("s.nama"),
+			goqu.COALESCE(goqu.I("s.nis"), "").As("nis"),
+			goqu.COALESCE(goqu.I("s.nisn"), "").As("nisn"),
+			goqu.COALESCE(goqu.I("k.nama"), "").As("kelas_terakhir"),
+			goqu.COALESCE(goqu.I("s.no_hp_wali"), "").As("no_hp"),
+			goqu.COALESCE(goqu.I("s.alamat"), "").As("alamat"),
+		).
+		Where(goqu.I("s.tenant_id").Eq(tenantID))
+	if kelasID != "" {
+		ds = ds.Where(goqu.I("s.kelas_id").Eq(kelasID), goqu.I("s.status").Eq("Aktif"))
+	}
+	if len(siswaIDs) > 0 {
+		ds = ds.Where(goqu.I("s.id").In(siswaIDs))
+	}
+
+	var list []model.Alumni
+	if err := ds.Order(goqu.I("s.nama").Asc()).ScanStructsContext(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (a *alumniAdapter) CreateAlumniFromSiswa(ctx context.Context, alumni []model.Alumni) (int, error) {
+	inserted := 0
+	err := a.db.WithTx(func(tx *goqu.TxDatabase) error {
+		now := time.Now()
+		for i := range alumni {
+			alumni[i].ID = uuid.New().String()
+			res, err := tx.Insert("sekolah_alumni").Rows(a.alumniRecord(&alumni[i], now)).
+				OnConflict(goqu.DoNothing()).
+				Executor().ExecContext(ctx)
+			if err != nil {
+				return err
+			}
+			if n, _ := res.RowsAffected(); n > 0 {
+				inserted++
+			}
+
+			_, err = tx.Update("sekolah_siswa").
+				Set(goqu.Record{"status": "Lulus"}).
+				Where(goqu.C("id").Eq(alumni[i].SiswaID), goqu.C("tenant_id").Eq(alumni[i].TenantID)).
+				Executor().ExecContext(ctx)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return inserted, err
+}
+
+// ==========================================
+// ALUMNI
+// ==========================================
+
+func (a *alumniAdapter) alumniRecord(m *model.Alumni, now time.Time) goqu.Record {
+	m.CreatedAt = now
+	m.UpdatedAt = now
+	return goqu.Record{
+		"id":             m.ID,
+		"tenant_id":      m.TenantID,
+		"siswa_id":       nullableUUID(m.SiswaID),
+		"nama":           m.Nama,
+		"nis":            m.NIS,
+		"nisn":           m.NISN,
+		"tahun_lulus":    m.TahunLulus,
+		"kelas_terakhir": m.KelasTerakhir,
+		"no_hp":          m.NoHP,
+		"email":          m.Email,
+		"alamat":         m.Alamat,
+		"kegiatan":       m.Kegiatan,
+		"institusi":      m.Institusi,
+		"jurusan":        m.Jurusan,
+		"token":          m.Token,
+		"created_at":     now,
+		"updated_at":     now,
+	}
+}
+
+func (a *alumniAdapter) alumniDataset() *goqu.SelectDataset {
+	return a.db.From("sekolah_alumni").Select(
+		goqu.C("id"),
+		goqu.C("tenant_id"),
+		goqu.COALESCE(goqu.L("siswa_id::text"), "").As("siswa_id"),
+		goqu.C("nama"),
+		goqu.COALESCE(goqu.C("nis"), "").As("nis"),
+		goqu.COALESCE(goqu.C("nisn"), "").As("nisn"),
+		goqu.C("tahun_lulus"),
+		goqu.COALESCE(goqu.C("kelas_terakhir"), "").As("kelas_terakhir"),
+		goqu.COALESCE(goqu.C("no_hp"), "").As("no_hp"),
+		goqu.COALESCE(goqu.C("email"), "").As("email"),
+		goqu.COALESCE(goqu.C("alamat"), "").As("alamat"),
+		goqu.COALESCE(goqu.C("kegiatan"), "").As("kegiatan"),
+		goqu.COALESCE(goqu.C("institusi"), "").As("institusi"),
+		goqu.COALESCE(goqu.C("jurusan"), "").As("jurusan"),
+		goqu.C("token"),
+		goqu.C("terakhir_diperbarui"),
+		goqu.C("created_at"),
+		goqu.C("updated_at"),
+	)
+}
+
+func (a *alumniAdapter) scanAlumni(ctx context.Context, ds *goqu.SelectDataset) (*model.Alumni, error) {
+	var m model.Alumni
+	found, err := ds.ScanStructContext(ctx, &m)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return &m, nil
+}
+
+func (a *alumniAdapter) CreateAlumni(ctx context.Context, m *model.Alumni) error {
+	m.ID = uuid.New().String()
+	_, err := a.db.Insert("sekolah_alumni").Rows(a.alumniRecord(m, time.Now())).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *alumniAdapter) UpdateAlumni(ctx context.Context, m *model.Alumni) error {
+	_, err := a.db.Update("sekolah_alumni").Set(
+		goqu.Record{
+			"nama":           m.Nama,
+			"nis":            m.NIS,
+			"nisn":           m.NISN,
+			"tahun_lulus":    m.TahunLulus,
+			"kelas_terakhir": m.KelasTerakhir,
+			"no_hp":          m.NoHP,
+			"email":          m.Email,
+			"alamat":         m.Alamat,
+			"kegiatan":       m.Kegiatan,
+			"institusi":      m.Institusi,
+			"jurusan":        m.Jurusan,
+			"token":          m.Token,
+		},
+	).Where(
+		goqu.C("id").Eq(m.ID),
+		goqu.C("tenant_id").Eq(m.TenantID),
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *alumniAdapter) UpdateProfil(ctx context.Context, id string, in model.AlumniProfilInput) error {
+	_, err := a.db.Update("sekolah_alumni").Set(
+		goqu.Record{
+			"no_hp":               in.NoHP,
+			"email":               in.Email,
+			"alamat":              in.Alamat,
+			"kegiatan":            in.Kegiatan,
+			"institusi":           in.Institusi,
+			"jurusan":             in.Jurusan,
+			"terakhir_diperbarui": time.Now(),
+		},
+	).Where(goqu.C("id").Eq(id)).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *alumniAdapter) GetAlumniByID(ctx context.Context, tenantID, id string) (*model.Alumni, error) {
+	return a.scanAlumni(ctx, a.alumniDataset().
+		Where(goqu.C("id").Eq(id), goqu.C("tenant_id").Eq(tenantID)))
+}
+
+func (a *alumniAdapter) GetAlumniByToken(ctx context.Context, token string) (*model.Alumni, error) {
+	return a.scanAlumni(ctx, a.alumniDataset().Where(goqu.C("token").Eq(token)))
+}
+
+func (a *alumniAdapter) GetAlumniList(ctx context.Context, tenantID string, filter model.AlumniFilter) ([]model.Alumni, error) {
+	ds := a.alumniDataset().Where(goqu.C("tenant_id").Eq(tenantID))
+	if filter.TahunLulus > 0 {
+		ds = ds.Where(goqu.C("tahun_lulus").Eq(filter.TahunLulus))
+	}
+	if filter.Kegiatan != "" {
+		ds = ds.Where(goqu.C("kegiatan").Eq(filter.Kegiatan))
+	}
+	if filter.Search != "" {
+		pattern := "%" + filter.Search + "%"
+		ds = ds.Where(goqu.Or(
+			goqu.C("nama").ILike(pattern),
+			goqu.C("nis").ILike(pattern),
+			goqu.C("nisn").ILike(pattern),
+			goqu.C("institusi").ILike(pattern),
+		))
+	}
+
+	var list []model.Alumni
+	if err := ds.Order(goqu.C("tahun_lulus").Desc(), goqu.C("nama").Asc()).ScanStructsContext(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (a *alumniAdapter) GetAlumniTarget(ctx context.Context, tenantID string, dari, sampai int) ([]model.Alumni, error) {
+	ds := a.alumniDataset().Where(goqu.C("tenant_id").Eq(tenantID))
+	if dari > 0 {
+		ds = ds.Where(goqu.C("tahun_lulus").Gte(dari))
+	}
+	if sampai > 0 {
+		ds = ds.Where(goqu.C("tahun_lulus").Lte(sampai))
+	}
+
+	var list []model.Alumni
+	if err := ds.Order(goqu.C("nama").Asc()).ScanStructsContext(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (a *alumniAdapter) GetStatistik(ctx context.Context, tenantID string) ([]model.AlumniStatistik, error) {
+	var list []model.AlumniStatistik
+	err := a.db.From("sekolah_alumni").
+		Select(
+			goqu.C("tahun_lulus"),
+			goqu.COUNT("*").As("total"),
+			goqu.L("COUNT(*) FILTER (WHERE kegiatan = ?)", model.AlumniKegiatanKuliah).As("kuliah"),
+			goqu.L("COUNT(*) FILTER (WHERE kegiatan = ?)", model.AlumniKegiatanBekerja).As("bekerja"),
+			goqu.L("COUNT(*) FILTER (WHERE kegiatan = ?)", model.AlumniKegiatanWirausaha).As("wirausaha"),
+			goqu.L("COUNT(*) FILTER (WHERE kegiatan = ?)", model.AlumniKegiatanMondok).As("mondok"),
+			goqu.L("COUNT(*) FILTER (WHERE kegiatan = ?)", model.AlumniKegiatanBelumBekerja).As("belum_bekerja"),
+			goqu.L("COUNT(*) FILTER (WHERE COALESCE(kegiatan, '') = '')").As("tidak_diketahui"),
+			goqu.L("COUNT(*) FILTER (WHERE terakhir_diperbarui IS NOT NULL)").As("terlacak"),
+		).
+		Where(goqu.C("tenant_id").Eq(tenantID)).
+		GroupBy(goqu.C("tahun_lulus")).
+		Order(goqu.C("tahun_lulus").Desc()).
+		ScanStructsContext(ctx, &list)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// ==========================================
+// TRACER STUDY
+// ==========================================
+
+// tracerSurveyRow carries the JSONB question list alongside the survey columns
+type tracerSurveyRow struct {
+	model.TracerSurvey
+	PertanyaanJSON []byte `db:"pertanyaan"`
+}
+
+func (a *alumniAdapter) surveyDataset() *goqu.SelectDataset {
+	return a.db.From(goqu.T("sekolah_tracer_survey").As("s")).Select(
+		goqu.I("s.id"),
+		goqu.I("s.tenant_id"),
+		goqu.I("s.judul"),
+		goqu.COALESCE(goqu.I("s.deskripsi"), "").As("deskripsi"),
+		goqu.COALESCE(goqu.I("s.tahun_lulus_dari"), 0).As("tahun_lulus_dari"),
+		goqu.COALESCE(goqu.I("s.tahun_lulus_sampai"), 0).As("tahun_lulus_sampai"),
+		goqu.I("s.pertanyaan"),
+		goqu.I("s.status"),
+		goqu.I("s.tanggal_mulai"),
+		goqu.I("s.tanggal_selesai"),
+		goqu.L("(SELECT COUNT(*) FROM sekolah_tracer_jawaban j WHERE j.survey_id = s.id)").As("jumlah_respon"),
+		goqu.I("s.created_at"),
+		goqu.I("s.updated_at"),
+	)
+}
+
+func (a *alumniAdapter) CreateSurvey(ctx context.Context, s *model.TracerSurvey) error {
+	now := time.Now()
+	s.ID = uuid.New().String()
+	s.CreatedAt = now
+	s.UpdatedAt = now
+	pertanyaan, _ := json.Marshal(s.Pertanyaan)
+
+	_, err := a.db.Insert("sekolah_tracer_survey").Rows(
+		goqu.Record{
+			"id":                 s.ID,
+			"tenant_id":          s.TenantID,
+			"judul":              s.Judul,
+			"deskripsi":          s.Deskripsi,
+			"tahun_lulus_dari":   s.TahunLulusDari,
+			"tahun_lulus_sampai": s.TahunLulusSampai,
+			"pertanyaan":         pertanyaan,
+			"status":             s.Status,
+			"tanggal_mulai":      s.TanggalMulai,
+			"tanggal_selesai":    s.TanggalSelesai,
+			"created_at":         now,
+			"updated_at":         now,
+		},
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *alumniAdapter) UpdateSurvey(ctx context.Context, s *model.TracerSurvey) error {
+	pertanyaan, _ := json.Marshal(s.Pertanyaan)
+	_, err := a.db.Update("sekolah_tracer_survey").Set(
+		goqu.Record{
+			"judul":              s.Judul,
+			"deskripsi":          s.Deskripsi,
+			"tahun_lulus_dari":   s.TahunLulusDari,
+			"tahun_lulus_sampai": s.TahunLulusSampai,
+			"pertanyaan":         pertanyaan,
+			"status":             s.Status,
+			"tanggal_mulai":      s.TanggalMulai,
+			"tanggal_selesai":    s.TanggalSelesai,
+		},
+	).Where(
+		goqu.C("id").Eq(s.ID),
+		goqu.C("tenant_id").Eq(s.TenantID),
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *alumniAdapter) GetSurveyByID(ctx context.Context, tenantID, id string) (*model.TracerSurvey, error) {
+	var row tracerSurveyRow
+	found, err := a.surveyDataset().
+		Where(goqu.I("s.id").Eq(id), goqu.I("s.tenant_id").Eq(tenantID)).
+		ScanStructContext(ctx, &row)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	json.Unmarshal(row.PertanyaanJSON, &row.Pertanyaan)
+	return &row.TracerSurvey, nil
+}
+
+func (a *alumniAdapter) GetSurveyList(ctx context.Context, tenantID, status string) ([]model.TracerSurvey, error) {
+	ds := a.surveyDataset().Where(goqu.I("s.tenant_id").Eq(tenantID))
+	if status != "" {
+		ds = ds.Where(goqu.I("s.status").Eq(status))
+	}
+
+	var rows []tracerSurveyRow
+	if err := ds.Order(goqu.I("s.tanggal_mulai").Desc()).ScanStructsContext(ctx, &rows); err != nil {
+		return nil, err
+	}
+	list := make([]model.TracerSurvey, len(rows))
+	for i := range rows {
+		json.Unmarshal(rows[i].PertanyaanJSON, &rows[i].Pertanyaan)
+		list[i] = rows[i].TracerSurvey
+	}
+	return list, nil
+}
+
+func (a *alumniAdapter) SaveJawaban(ctx context.Context, j *model.TracerJawaban) error {
+	j.ID = uuid.New().String()
+	j.CreatedAt = time.Now()
+	jawaban, _ := json.Marshal(j.Jawaban)
+
+	_, err := a.db.Insert("sekolah_tracer_jawaban").Rows(
+		goqu.Record{
+			"id":         j.ID,
+			"survey_id":  j.SurveyID,
+			"alumni_id":  j.AlumniID,
+			"jawaban":    jawaban,
+			"created_at": j.CreatedAt,
+		},
+	).OnConflict(
+		goqu.DoUpdate("survey_id, alumni_id", goqu.Record{"jawaban": jawaban, "created_at": j.CreatedAt}),
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *alumniAdapter) GetJawabanBySurvey(ctx context.Context, surveyID string) ([]model.TracerJawaban, error) {
+	type jawabanRow struct {
+		model.TracerJawaban
+		JawabanJSON []byte `db:"jawaban"`
+	}
+
+	var rows []jawabanRow
+	err := a.db.From(goqu.T("sekolah_tracer_jawaban").As("j")).
+		Join(goqu.T("sekolah_alumni").As("a"), goqu.On(goqu.I("a.id").Eq(goqu.I("j.alumni_id")))).
+		Select(
+			goqu.I("j.id"),
+			goqu.I("j.survey_id"),
+			goqu.I("j.alumni_id"),
+			goqu.I("a.nama").As("alumni_nama"),
+			goqu.I("a.tahun_lulus"),
+			goqu.I("j.jawaban"),
+			goqu.I("j.created_at"),
+		).
+		Where(goqu.I("j.survey_id").Eq(surveyID)).
+		Order(goqu.I("a.tahun_lulus").Desc(), goqu.I("a.nama").Asc()).
+		ScanStructsContext(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]model.TracerJawaban, len(rows))
+	for i := range rows {
+		json.Unmarshal(rows[i].JawabanJSON, &rows[i].Jawaban)
+		list[i] = rows[i].TracerJawaban
+	}
+	return list, nil
+}
+
+func (a *alumniAdapter) GetSurveyDijawab(ctx context.Context, alumniID string) (map[string]bool, error) {
+	var ids []string
+	err := a.db.From("sekolah_tracer_jawaban").
+		Select("survey_id").
+		Where(goqu.C("alumni_id").Eq(alumniID)).
+		ScanValsContext(ctx, &ids)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		result[id] = true
+	}
+	return result, nil
+}
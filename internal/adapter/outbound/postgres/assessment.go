@@ -0,0 +1,114 @@
+package postgres_outbound_adapter
+
+import (
+	"context"
+	"time"
+
+	"prabogo/internal/model"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/google/uuid"
+)
+
+// ==========================================
+// ASSESSMENT ENTRIES
+// ==========================================
+
+func (a *eraporAdapter) CreateAssessmentEntry(ctx context.Context, e *model.AssessmentEntry) error {
+	now := time.Now()
+	e.ID = uuid.New().String()
+	e.CreatedAt = now
+	e.UpdatedAt = now
+
+	_, err := a.db.Insert("assessment_entries").Rows(
+		goqu.Record{
+			"id":          e.ID,
+			"tenant_id":   e.TenantID,
+			"student_id":  e.StudentID,
+			"subject_id":  e.SubjectID,
+			"semester_id": e.SemesterID,
+			"component":   e.Component,
+			"title":       e.Title,
+			"topic":       e.Topic,
+			"date":        e.Date,
+			"score":       e.Score,
+			"created_at":  now,
+			"updated_at":  now,
+		},
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *eraporAdapter) UpdateAssessmentEntry(ctx context.Context, e *model.AssessmentEntry) error {
+	e.UpdatedAt = time.Now()
+	_, err := a.db.Update("assessment_entries").Set(
+		goqu.Record{
+			"component":  e.Component,
+			"title":      e.Title,
+			"topic":      e.Topic,
+			"date":       e.Date,
+			"score":      e.Score,
+			"updated_at": e.UpdatedAt,
+		},
+	).Where(
+		goqu.C("id").Eq(e.ID),
+		goqu.C("tenant_id").Eq(e.TenantID),
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *eraporAdapter) DeleteAssessmentEntry(ctx context.Context, tenantID, id string) error {
+	_, err := a.db.Delete("assessment_entries").
+		Where(
+			goqu.C("id").Eq(id),
+			goqu.C("tenant_id").Eq(tenantID),
+		).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *eraporAdapter) GetAssessmentEntry(ctx context.Context, tenantID, id string) (*model.AssessmentEntry, error) {
+	var e model.AssessmentEntry
+	found, err := a.db.From("assessment_entries").
+		Where(
+			goqu.C("id").Eq(id),
+			goqu.C("tenant_id").Eq(tenantID),
+		).
+		ScanStructContext(ctx, &e)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (a *eraporAdapter) GetAssessmentEntries(ctx context.Context, tenantID, studentID, subjectID, semesterID string) ([]model.AssessmentEntry, error) {
+	ds := a.db.From("assessment_entries").
+		Where(
+			goqu.C("tenant_id").Eq(tenantID),
+			goqu.C("semester_id").Eq(semesterID),
+		)
+	if studentID != "" {
+		ds = ds.Where(goqu.C("student_id").Eq(studentID))
+	}
+	if subjectID != "" {
+		ds = ds.Where(goqu.C("subject_id").Eq(subjectID))
+	}
+
+	var entries []model.AssessmentEntry
+	err := ds.Order(goqu.C("date").Asc(), goqu.C("created_at").Asc()).
+		ScanStructsContext(ctx, &entries)
+	return entries, err
+}
+
+func (a *eraporAdapter) GetAssessedStudents(ctx context.Context, tenantID, subjectID string) ([]model.AssessmentEntry, error) {
+	var pairs []model.AssessmentEntry
+	err := a.db.From("assessment_entries").
+		Select("student_id", "semester_id").
+		Distinct().
+		Where(
+			goqu.C("tenant_id").Eq(tenantID),
+			goqu.C("subject_id").Eq(subjectID),
+		).
+		ScanStructsContext(ctx, &pairs)
+	return pairs, err
+}
@@ -0,0 +1,231 @@
+package postgres_outbound_adapter
+
+import (
+	"context"
+	"time"
+
+	"prabogo/internal/model"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/google/uuid"
+)
+
+// ==========================================
+// KURIKULUM MERDEKA CP/TP
+// ==========================================
+
+func (a *eraporAdapter) GetCapaianList(ctx context.Context, tenantID, subjectID, fase string) ([]model.CapaianPembelajaran, error) {
+	ds := a.db.From("capaian_pembelajaran").
+		Where(
+			goqu.C("tenant_id").Eq(tenantID),
+			goqu.C("subject_id").Eq(subjectID),
+		)
+	if fase != "" {
+		ds = ds.Where(goqu.C("fase").Eq(fase))
+	}
+
+	var list []model.CapaianPembelajaran
+	if err := ds.Order(goqu.C("fase").Asc(), goqu.C("created_at").Asc()).ScanStructsContext(ctx, &list); err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return list, nil
+	}
+
+	ids := make([]string, len(list))
+	for i := range list {
+		ids[i] = list[i].ID
+	}
+	var tujuan []model.TujuanPembelajaran
+	err := a.db.From("tujuan_pembelajaran").
+		Where(goqu.C("cp_id").In(ids)).
+		Order(goqu.C("urutan").Asc(), goqu.C("kode").Asc()).
+		ScanStructsContext(ctx, &tujuan)
+	if err != nil {
+		return nil, err
+	}
+	byCP := make(map[string][]model.TujuanPembelajaran)
+	for _, tp := range tujuan {
+		byCP[tp.CPID] = append(byCP[tp.CPID], tp)
+	}
+	for i := range list {
+		list[i].TujuanList = byCP[list[i].ID]
+	}
+	return list, nil
+}
+
+func (a *eraporAdapter) GetCapaian(ctx context.Context, tenantID, id string) (*model.CapaianPembelajaran, error) {
+	var cp model.CapaianPembelajaran
+	found, err := a.db.From("capaian_pembelajaran").
+		Where(
+			goqu.C("id").Eq(id),
+			goqu.C("tenant_id").Eq(tenantID),
+		).
+		ScanStructContext(ctx, &cp)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &cp, nil
+}
+
+func (a *eraporAdapter) SaveCapaian(ctx context.Context, cp *model.CapaianPembelajaran) error {
+	now := time.Now()
+	cp.UpdatedAt = now
+	if cp.ID == "" {
+		cp.ID = uuid.New().String()
+		cp.CreatedAt = now
+		_, err := a.db.Insert("capaian_pembelajaran").Rows(
+			goqu.Record{
+				"id":         cp.ID,
+				"tenant_id":  cp.TenantID,
+				"subject_id": cp.SubjectID,
+				"fase":       cp.Fase,
+				"elemen":     cp.Elemen,
+				"deskripsi":  cp.Deskripsi,
+				"created_at": now,
+				"updated_at": now,
+			},
+		).Executor().ExecContext(ctx)
+		return err
+	}
+
+	_, err := a.db.Update("capaian_pembelajaran").Set(
+		goqu.Record{
+			"fase":       cp.Fase,
+			"elemen":     cp.Elemen,
+			"deskripsi":  cp.Deskripsi,
+			"updated_at": now,
+		},
+	).Where(
+		goqu.C("id").Eq(cp.ID),
+		goqu.C("tenant_id").Eq(cp.TenantID),
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *eraporAdapter) DeleteCapaian(ctx context.Context, tenantID, id string) error {
+	_, err := a.db.Delete("capaian_pembelajaran").
+		Where(
+			goqu.C("id").Eq(id),
+			goqu.C("tenant_id").Eq(tenantID),
+		).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *eraporAdapter) GetTujuan(ctx context.Context, tenantID, id string) (*model.TujuanPembelajaran, error) {
+	var tp model.TujuanPembelajaran
+	found, err := a.db.From("tujuan_pembelajaran").
+		Where(
+			goqu.C("id").Eq(id),
+			goqu.C("tenant_id").Eq(tenantID),
+		).
+		ScanStructContext(ctx, &tp)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &tp, nil
+}
+
+func (a *eraporAdapter) SaveTujuan(ctx context.Context, tp *model.TujuanPembelajaran) error {
+	now := time.Now()
+	tp.UpdatedAt = now
+	if tp.ID == "" {
+		tp.ID = uuid.New().String()
+		tp.CreatedAt = now
+		_, err := a.db.Insert("tujuan_pembelajaran").Rows(
+			goqu.Record{
+				"id":         tp.ID,
+				"tenant_id":  tp.TenantID,
+				"cp_id":      tp.CPID,
+				"subject_id": tp.SubjectID,
+				"kode":       tp.Kode,
+				"deskripsi":  tp.Deskripsi,
+				"urutan":     tp.Urutan,
+				"created_at": now,
+				"updated_at": now,
+			},
+		).Executor().ExecContext(ctx)
+		return err
+	}
+
+	_, err := a.db.Update("tujuan_pembelajaran").Set(
+		goqu.Record{
+			"kode":       tp.Kode,
+			"deskripsi":  tp.Deskripsi,
+			"urutan":     tp.Urutan,
+			"updated_at": now,
+		},
+	).Where(
+		goqu.C("id").Eq(tp.ID),
+		goqu.C("tenant_id").Eq(tp.TenantID),
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *eraporAdapter) DeleteTujuan(ctx context.Context, tenantID, id string) error {
+	_, err := a.db.Delete("tujuan_pembelajaran").
+		Where(
+			goqu.C("id").Eq(id),
+			goqu.C("tenant_id").Eq(tenantID),
+		).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *eraporAdapter) SaveTPAchievements(ctx context.Context, tenantID, semesterID string, marks []model.TPMark) error {
+	if len(marks) == 0 {
+		return nil
+	}
+	now := time.Now()
+	rows := make([]interface{}, len(marks))
+	for i, m := range marks {
+		rows[i] = goqu.Record{
+			"tenant_id":   tenantID,
+			"student_id":  m.StudentID,
+			"tp_id":       m.TPID,
+			"semester_id": semesterID,
+			"score":       m.Score,
+			"created_at":  now,
+			"updated_at":  now,
+		}
+	}
+	_, err := a.db.Insert("tp_achievements").Rows(rows...).
+		OnConflict(goqu.DoUpdate("student_id, tp_id, semester_id", goqu.Record{
+			"score":      goqu.L("EXCLUDED.score"),
+			"updated_at": now,
+		})).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *eraporAdapter) GetTPAchievements(ctx context.Context, tenantID, subjectID, semesterID, studentID string) ([]model.TPAchievement, error) {
+	ds := a.db.From(goqu.T("tp_achievements").As("a")).
+		Join(goqu.T("tujuan_pembelajaran").As("t"), goqu.On(goqu.I("t.id").Eq(goqu.I("a.tp_id")))).
+		Select(
+			goqu.I("a.id"),
+			goqu.I("a.tenant_id"),
+			goqu.I("a.student_id"),
+			goqu.I("a.tp_id"),
+			goqu.I("a.semester_id"),
+			goqu.I("a.score"),
+			goqu.I("a.updated_at"),
+			goqu.I("t.subject_id"),
+			goqu.I("t.kode"),
+			goqu.I("t.deskripsi").As("tp_deskripsi"),
+			goqu.I("t.urutan"),
+		).
+		Where(
+			goqu.I("a.tenant_id").Eq(tenantID),
+			goqu.I("a.semester_id").Eq(semesterID),
+			goqu.I("t.subject_id").Eq(subjectID),
+		)
+	if studentID != "" {
+		ds = ds.Where(goqu.I("a.student_id").Eq(studentID))
+	}
+
+	var list []model.TPAchievement
+	err := ds.Order(goqu.I("a.student_id").Asc(), goqu.I("t.urutan").Asc()).
+		ScanStructsContext(ctx, &list)
+	return list, err
+}
@@ -0,0 +1,178 @@
+package postgres_outbound_adapter
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"prabogo/internal/model"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/google/uuid"
+)
+
+type dapodikAdapter struct {
+	db *goqu.Database
+}
+
+func NewDapodikAdapter(sqlDB *sql.DB) *dapodikAdapter {
+	return &dapodikAdapter{db: goqu.New("postgres", sqlDB)}
+}
+
+func (a *dapodikAdapter) GetSiswa(ctx context.Context, tenantID string) ([]model.Siswa, error) {
+	var list []model.Siswa
+	err := a.db.From(goqu.T("sekolah_siswa").As("s")).
+		LeftJoin(goqu.T("sekolah_kelas").As("k"), goqu.On(goqu.I("k.id").Eq(goqu.I("s.kelas_id")))).
+		Select(
+			goqu.I("s.id"),
+			goqu.I("s.tenant_id"),
+			goqu.COALESCE(goqu.I("s.nis"), "").As("nis"),
+			goqu.I("s.nama"),
+			goqu.COALESCE(goqu.L("s.kelas_id::text"), "").As("kelas_id"),
+			goqu.COALESCE(goqu.I("k.nama"), "").As("kelas_nama"),
+			goqu.COALESCE(goqu.I("s.alamat"), "").As("alamat"),
+			goqu.COALESCE(goqu.I("s.nama_wali"), "").As("nama_wali"),
+			goqu.COALESCE(goqu.I("s.no_hp_wali"), "").As("no_hp_wali"),
+			goqu.COALESCE(goqu.I("s.status"), "").As("status"),
+			goqu.COALESCE(goqu.I("s.nisn"), "").As("nisn"),
+			goqu.COALESCE(goqu.I("s.nik"), "").As("nik"),
+			goqu.COALESCE(goqu.I("s.jenis_kelamin"), "").As("jenis_kelamin"),
+			goqu.COALESCE(goqu.I("s.tempat_lahir"), "").As("tempat_lahir"),
+			goqu.I("s.tanggal_lahir"),
+			goqu.COALESCE(goqu.I("s.agama"), "").As("agama"),
+			goqu.COALESCE(goqu.I("s.nama_ayah"), "").As("nama_ayah"),
+			goqu.COALESCE(goqu.I("s.pekerjaan_ayah"), "").As("pekerjaan_ayah"),
+			goqu.COALESCE(goqu.I("s.nama_ibu"), "").As("nama_ibu"),
+			goqu.COALESCE(goqu.I("s.pekerjaan_ibu"), "").As("pekerjaan_ibu"),
+		).
+		Where(goqu.I("s.tenant_id").Eq(tenantID)).
+		Order(goqu.I("s.nama").Asc()).
+		ScanStructsContext(ctx, &list)
+	return list, err
+}
+
+func (a *dapodikAdapter) GetGuru(ctx context.Context, tenantID string) ([]model.Guru, error) {
+	var list []model.Guru
+	err := a.db.From("sekolah_guru").
+		Select(
+			goqu.C("id"),
+			goqu.C("tenant_id"),
+			goqu.COALESCE(goqu.C("nip"), "").As("nip"),
+			goqu.C("nama"),
+			goqu.COALESCE(goqu.C("jenis"), "").As("jenis"),
+			goqu.COALESCE(goqu.C("status"), "").As("status"),
+			goqu.COALESCE(goqu.C("nuptk"), "").As("nuptk"),
+			goqu.COALESCE(goqu.C("nik"), "").As("nik"),
+			goqu.COALESCE(goqu.C("jenis_kelamin"), "").As("jenis_kelamin"),
+			goqu.COALESCE(goqu.C("tempat_lahir"), "").As("tempat_lahir"),
+			goqu.C("tanggal_lahir"),
+		).
+		Where(goqu.C("tenant_id").Eq(tenantID)).
+		Order(goqu.C("nama").Asc()).
+		ScanStructsContext(ctx, &list)
+	return list, err
+}
+
+func (a *dapodikAdapter) GetRombel(ctx context.Context, tenantID string) ([]model.Kelas, error) {
+	var list []model.Kelas
+	err := a.db.From(goqu.T("sekolah_kelas").As("k")).
+		LeftJoin(goqu.T("sekolah_guru").As("g"), goqu.On(goqu.I("g.id").Eq(goqu.I("k.wali_kelas_id")))).
+		Select(
+			goqu.I("k.id"),
+			goqu.I("k.tenant_id"),
+			goqu.I("k.nama"),
+			goqu.I("k.tingkat"),
+			goqu.COALESCE(goqu.I("k.urutan"), 0).As("urutan"),
+			goqu.COALESCE(goqu.I("k.status"), "").As("status"),
+			goqu.COALESCE(goqu.L("k.wali_kelas_id::text"), "").As("wali_kelas_id"),
+			goqu.COALESCE(goqu.I("g.nama"), "").As("wali_kelas_nama"),
+			goqu.COALESCE(goqu.I("k.kurikulum"), "").As("kurikulum"),
+		).
+		Where(goqu.I("k.tenant_id").Eq(tenantID)).
+		Order(goqu.I("k.urutan").Asc(), goqu.I("k.nama").Asc()).
+		ScanStructsContext(ctx, &list)
+	return list, err
+}
+
+func (a *dapodikAdapter) ApplyImport(ctx context.Context, tenantID string, data model.DapodikImport) error {
+	now := time.Now()
+	return a.db.WithTx(func(tx *goqu.TxDatabase) error {
+		for _, k := range data.Rombel {
+			rec := goqu.Record{
+				"nama":          k.Nama,
+				"tingkat":       k.Tingkat,
+				"wali_kelas_id": nullableUUID(k.WaliKelasID),
+				"kurikulum":     k.Kurikulum,
+				"updated_at":    now,
+			}
+			if err := upsertDapodik(ctx, tx, "sekolah_kelas", tenantID, k.ID, rec, goqu.Record{
+				"urutan": k.Urutan,
+				"status": k.Status,
+			}); err != nil {
+				return err
+			}
+		}
+
+		for _, g := range data.Guru {
+			rec := goqu.Record{
+				"nama":          g.Nama,
+				"nip":           g.NIP,
+				"nuptk":         g.NUPTK,
+				"nik":           g.NIK,
+				"jenis":         g.Jenis,
+				"status":        g.Status,
+				"jenis_kelamin": g.JenisKelamin,
+				"tempat_lahir":  g.TempatLahir,
+				"tanggal_lahir": g.TanggalLahir,
+			}
+			if err := upsertDapodik(ctx, tx, "sekolah_guru", tenantID, g.ID, rec, nil); err != nil {
+				return err
+			}
+		}
+
+		for _, s := range data.Siswa {
+			rec := goqu.Record{
+				"nama":           s.Nama,
+				"nis":            s.NIS,
+				"nisn":           s.NISN,
+				"nik":            s.NIK,
+				"kelas_id":       nullableUUID(s.KelasID),
+				"alamat":         s.Alamat,
+				"nama_wali":      s.NamaWali,
+				"no_hp_wali":     s.NoHPWali,
+				"jenis_kelamin":  s.JenisKelamin,
+				"tempat_lahir":   s.TempatLahir,
+				"tanggal_lahir":  s.TanggalLahir,
+				"agama":          s.Agama,
+				"nama_ayah":      s.NamaAyah,
+				"pekerjaan_ayah": s.PekerjaanAyah,
+				"nama_ibu":       s.NamaIbu,
+				"pekerjaan_ibu":  s.PekerjaanIbu,
+			}
+			if err := upsertDapodik(ctx, tx, "sekolah_siswa", tenantID, s.ID, rec, goqu.Record{
+				"status": s.Status,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// upsertDapodik updates a matched row or inserts a new one with the extra insert-only columns
+func upsertDapodik(ctx context.Context, tx *goqu.TxDatabase, table, tenantID, id string, rec, insertOnly goqu.Record) error {
+	if id != "" {
+		_, err := tx.Update(table).Set(rec).
+			Where(goqu.C("id").Eq(id), goqu.C("tenant_id").Eq(tenantID)).
+			Executor().ExecContext(ctx)
+		return err
+	}
+
+	rec["id"] = uuid.New().String()
+	rec["tenant_id"] = tenantID
+	for k, v := range insertOnly {
+		rec[k] = v
+	}
+	_, err := tx.Insert(table).Rows(rec).Executor().ExecContext(ctx)
+	return err
+}
@@ -0,0 +1,314 @@
+package postgres_outbound_adapter
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"prabogo/internal/model"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/google/uuid"
+)
+
+type ekskulAdapter struct {
+	db goquQuerier
+}
+
+func NewEkskulAdapter(sqlDB *sql.DB) *ekskulAdapter {
+	return &ekskulAdapter{db: goqu.New("postgres", sqlDB)}
+}
+
+// NewEkskulTxAdapter binds the adapter to an open transaction
+func NewEkskulTxAdapter(tx *sql.Tx) *ekskulAdapter {
+	return &ekskulAdapter{db: goqu.NewTx("postgres", tx)}
+}
+
+// ==========================================
+// EKSKUL MASTER
+// ==========================================
+
+func (a *ekskulAdapter) ekskulDataset() *goqu.SelectDataset {
+	return a.db.From(goqu.T("sekolah_ekskul").As("e")).
+		LeftJoin(goqu.T("sekolah_guru").As("g"), goqu.On(goqu.I("g.id").Eq(goqu.I("e.pembina_id")))).
+		Select(
+			goqu.I("e.id"),
+			goqu.I("e.tenant_id"),
+			goqu.I("e.nama"),
+			goqu.COALESCE(goqu.I("e.deskripsi"), "").As("deskripsi"),
+			goqu.I("e.pembina_id"),
+			goqu.COALESCE(goqu.I("g.nama"), "").As("pembina_nama"),
+			goqu.I("e.is_wajib"),
+			goqu.I("e.is_active"),
+			goqu.I("e.created_at"),
+			goqu.I("e.updated_at"),
+		)
+}
+
+func (a *ekskulAdapter) CreateEkskul(ctx context.Context, ekskul *model.Ekskul) error {
+	now := time.Now()
+	ekskul.ID = uuid.New().String()
+	ekskul.IsActive = true
+	ekskul.CreatedAt = now
+	ekskul.UpdatedAt = now
+
+	_, err := a.db.Insert("sekolah_ekskul").Rows(
+		goqu.Record{
+			"id":         ekskul.ID,
+			"tenant_id":  ekskul.TenantID,
+			"nama":       ekskul.Nama,
+			"deskripsi":  ekskul.Deskripsi,
+			"pembina_id": ekskul.PembinaID,
+			"is_wajib":   ekskul.IsWajib,
+			"is_active":  ekskul.IsActive,
+			"created_at": now,
+			"updated_at": now,
+		},
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *ekskulAdapter) UpdateEkskul(ctx context.Context, ekskul *model.Ekskul) error {
+	_, err := a.db.Update("sekolah_ekskul").Set(
+		goqu.Record{
+			"nama":       ekskul.Nama,
+			"deskripsi":  ekskul.Deskripsi,
+			"pembina_id": ekskul.PembinaID,
+			"is_wajib":   ekskul.IsWajib,
+			"is_active":  ekskul.IsActive,
+			"updated_at": time.Now(),
+		},
+	).Where(
+		goqu.C("id").Eq(ekskul.ID),
+		goqu.C("tenant_id").Eq(ekskul.TenantID),
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *ekskulAdapter) GetEkskulByID(ctx context.Context, tenantID, id string) (*model.Ekskul, error) {
+	var ekskul model.Ekskul
+	found, err := a.ekskulDataset().
+		Where(goqu.I("e.id").Eq(id), goqu.I("e.tenant_id").Eq(tenantID)).
+		ScanStructContext(ctx, &ekskul)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return &ekskul, nil
+}
+
+func (a *ekskulAdapter) GetEkskulByTenant(ctx context.Context, tenantID string) ([]model.Ekskul, error) {
+	var list []model.Ekskul
+	err := a.ekskulDataset().
+		Where(goqu.I("e.tenant_id").Eq(tenantID)).
+		Order(goqu.I("e.nama").Asc()).
+		ScanStructsContext(ctx, &list)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (a *ekskulAdapter) DeleteEkskul(ctx context.Context, tenantID, id string) error {
+	_, err := a.db.Delete("sekolah_ekskul").
+		Where(goqu.C("id").Eq(id), goqu.C("tenant_id").Eq(tenantID)).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *ekskulAdapter) SantriExists(ctx context.Context, tenantID, santriID string) (bool, error) {
+	var id string
+	return a.db.From(tableSiswa).
+		Select(goqu.L("id::text")).
+		Where(goqu.C("id").Eq(santriID), goqu.C("tenant_id").Eq(tenantID)).
+		ScanValContext(ctx, &id)
+}
+
+func (a *ekskulAdapter) GuruExists(ctx context.Context, tenantID, guruID string) (bool, error) {
+	var id string
+	return a.db.From(tableGuru).
+		Select(goqu.L("id::text")).
+		Where(goqu.C("id").Eq(guruID), goqu.C("tenant_id").Eq(tenantID)).
+		ScanValContext(ctx, &id)
+}
+
+// ==========================================
+// ANGGOTA
+// ==========================================
+
+// anggotaDataset selects members with joined names and attendance counts
+func (a *ekskulAdapter) anggotaDataset() *goqu.SelectDataset {
+	return a.db.From(goqu.T("sekolah_ekskul_anggota").As("a")).
+		Join(goqu.T("sekolah_ekskul").As("e"), goqu.On(goqu.I("e.id").Eq(goqu.I("a.ekskul_id")))).
+		Join(goqu.T("sekolah_siswa").As("s"), goqu.On(goqu.I("s.id").Eq(goqu.I("a.santri_id")))).
+		Select(
+			goqu.I("a.id"),
+			goqu.I("a.tenant_id"),
+			goqu.I("a.ekskul_id"),
+			goqu.I("e.nama").As("ekskul_nama"),
+			goqu.I("a.santri_id"),
+			goqu.I("s.nama").As("santri_nama"),
+			goqu.I("a.semester_id"),
+			goqu.COALESCE(goqu.I("a.predikat"), "").As("predikat"),
+			goqu.COALESCE(goqu.I("a.deskripsi"), "").As("deskripsi"),
+			goqu.L(`(SELECT COUNT(*) FROM sekolah_ekskul_presensi p WHERE p.anggota_id = a.id AND p.status = ?)`, model.EkskulPresensiHadir).As("jumlah_hadir"),
+			goqu.L(`(SELECT COUNT(*) FROM sekolah_ekskul_sesi ss WHERE ss.ekskul_id = a.ekskul_id AND ss.semester_id = a.semester_id)`).As("jumlah_sesi"),
+			goqu.I("a.created_at"),
+			goqu.I("a.updated_at"),
+		)
+}
+
+func (a *ekskulAdapter) AddAnggota(ctx context.Context, anggota *model.EkskulAnggota) error {
+	now := time.Now()
+	anggota.ID = uuid.New().String()
+	anggota.CreatedAt = now
+	anggota.UpdatedAt = now
+
+	_, err := a.db.Insert("sekolah_ekskul_anggota").Rows(
+		goqu.Record{
+			"id":          anggota.ID,
+			"tenant_id":   anggota.TenantID,
+			"ekskul_id":   anggota.EkskulID,
+			"santri_id":   anggota.SantriID,
+			"semester_id": anggota.SemesterID,
+			"predikat":    "",
+			"deskripsi":   "",
+			"created_at":  now,
+			"updated_at":  now,
+		},
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *ekskulAdapter) RemoveAnggota(ctx context.Context, tenantID, id string) error {
+	_, err := a.db.Delete("sekolah_ekskul_anggota").
+		Where(goqu.C("id").Eq(id), goqu.C("tenant_id").Eq(tenantID)).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *ekskulAdapter) GetAnggotaByID(ctx context.Context, tenantID, id string) (*model.EkskulAnggota, error) {
+	var anggota model.EkskulAnggota
+	found, err := a.anggotaDataset().
+		Where(goqu.I("a.id").Eq(id), goqu.I("a.tenant_id").Eq(tenantID)).
+		ScanStructContext(ctx, &anggota)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return &anggota, nil
+}
+
+func (a *ekskulAdapter) GetAnggotaByEkskul(ctx context.Context, tenantID, ekskulID, semesterID string) ([]model.EkskulAnggota, error) {
+	var list []model.EkskulAnggota
+	err := a.anggotaDataset().
+		Where(
+			goqu.I("a.tenant_id").Eq(tenantID),
+			goqu.I("a.ekskul_id").Eq(ekskulID),
+			goqu.I("a.semester_id").Eq(semesterID),
+		).
+		Order(goqu.I("s.nama").Asc()).
+		ScanStructsContext(ctx, &list)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (a *ekskulAdapter) GetAnggotaBySantri(ctx context.Context, tenantID, santriID, semesterID string) ([]model.EkskulAnggota, error) {
+	var list []model.EkskulAnggota
+	err := a.anggotaDataset().
+		Where(
+			goqu.I("a.tenant_id").Eq(tenantID),
+			goqu.I("a.santri_id").Eq(santriID),
+			goqu.I("a.semester_id").Eq(semesterID),
+		).
+		Order(goqu.I("e.nama").Asc()).
+		ScanStructsContext(ctx, &list)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (a *ekskulAdapter) UpdatePenilaian(ctx context.Context, tenantID, anggotaID, predikat, deskripsi string) error {
+	_, err := a.db.Update("sekolah_ekskul_anggota").Set(
+		goqu.Record{
+			"predikat":   predikat,
+			"deskripsi":  deskripsi,
+			"updated_at": time.Now(),
+		},
+	).Where(
+		goqu.C("id").Eq(anggotaID),
+		goqu.C("tenant_id").Eq(tenantID),
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+// ==========================================
+// SESI & PRESENSI
+// ==========================================
+
+func (a *ekskulAdapter) CreateSesi(ctx context.Context, sesi *model.EkskulSesi) error {
+	now := time.Now()
+	sesi.ID = uuid.New().String()
+	sesi.CreatedAt = now
+	sesi.UpdatedAt = now
+
+	_, err := a.db.Insert("sekolah_ekskul_sesi").Rows(
+		goqu.Record{
+			"id":          sesi.ID,
+			"tenant_id":   sesi.TenantID,
+			"ekskul_id":   sesi.EkskulID,
+			"semester_id": sesi.SemesterID,
+			"tanggal":     sesi.Tanggal,
+			"materi":      sesi.Materi,
+			"created_at":  now,
+			"updated_at":  now,
+		},
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *ekskulAdapter) GetSesiByEkskul(ctx context.Context, tenantID, ekskulID, semesterID string) ([]model.EkskulSesi, error) {
+	var list []model.EkskulSesi
+	err := a.db.From("sekolah_ekskul_sesi").
+		Select("id", "tenant_id", "ekskul_id", "semester_id", "tanggal", goqu.COALESCE(goqu.C("materi"), "").As("materi"), "created_at", "updated_at").
+		Where(
+			goqu.C("tenant_id").Eq(tenantID),
+			goqu.C("ekskul_id").Eq(ekskulID),
+			goqu.C("semester_id").Eq(semesterID),
+		).
+		Order(goqu.C("tanggal").Desc()).
+		ScanStructsContext(ctx, &list)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// SavePresensi upserts attendance rows for a session
+func (a *ekskulAdapter) SavePresensi(ctx context.Context, sesiID string, presensi []model.EkskulPresensi) error {
+	for _, p := range presensi {
+		_, err := a.db.Insert("sekolah_ekskul_presensi").Rows(
+			goqu.Record{
+				"id":         uuid.New().String(),
+				"sesi_id":    sesiID,
+				"anggota_id": p.AnggotaID,
+				"status":     p.Status,
+				"catatan":    p.Catatan,
+			},
+		).OnConflict(goqu.DoUpdate("sesi_id, anggota_id", goqu.Record{
+			"status":  goqu.L("EXCLUDED.status"),
+			"catatan": goqu.L("EXCLUDED.catatan"),
+		})).Executor().ExecContext(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -9,17 +9,23 @@ import (
 	"prabogo/internal/model"
 
 	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
 	"github.com/google/uuid"
 )
 
 type eraporAdapter struct {
-	db *goqu.Database
+	db goquQuerier
 }
 
 func NewERaporAdapter(sqlDB *sql.DB) *eraporAdapter {
 	return &eraporAdapter{db: goqu.New("postgres", sqlDB)}
 }
 
+// NewERaporTxAdapter binds the adapter to an open transaction
+func NewERaporTxAdapter(tx *sql.Tx) *eraporAdapter {
+	return &eraporAdapter{db: goqu.NewTx("postgres", tx)}
+}
+
 // ==========================================
 // SUBJECT OPERATIONS
 // ==========================================
@@ -191,28 +197,30 @@ func (a *eraporAdapter) SaveGrade(ctx context.Context, input *model.StudentGrade
 
 	_, err := a.db.Insert("student_grades").Rows(
 		goqu.Record{
-			"id":               id,
-			"tenant_id":        input.TenantID,
-			"student_id":       input.StudentID,
-			"subject_id":       input.SubjectID,
-			"semester_id":      input.SemesterID,
-			"score_numeric":    input.ScoreNumeric,
-			"score_predicate":  input.ScorePredicate,
-			"description_high": input.DescriptionHigh,
-			"description_low":  input.DescriptionLow,
-			"component_scores": componentScoresJSON,
-			"created_at":       now,
-			"updated_at":       now,
+			"id":                 id,
+			"tenant_id":          input.TenantID,
+			"student_id":         input.StudentID,
+			"subject_id":         input.SubjectID,
+			"semester_id":        input.SemesterID,
+			"score_numeric":      input.ScoreNumeric,
+			"score_predicate":    input.ScorePredicate,
+			"description_high":   input.DescriptionHigh,
+			"description_low":    input.DescriptionLow,
+			"description_manual": input.DescriptionManual,
+			"component_scores":   componentScoresJSON,
+			"created_at":         now,
+			"updated_at":         now,
 		},
 	).OnConflict(
 		goqu.DoUpdate("student_id, subject_id, semester_id",
 			goqu.Record{
-				"score_numeric":    input.ScoreNumeric,
-				"score_predicate":  input.ScorePredicate,
-				"description_high": input.DescriptionHigh,
-				"description_low":  input.DescriptionLow,
-				"component_scores": componentScoresJSON,
-				"updated_at":       now,
+				"score_numeric":      input.ScoreNumeric,
+				"score_predicate":    input.ScorePredicate,
+				"description_high":   input.DescriptionHigh,
+				"description_low":    input.DescriptionLow,
+				"description_manual": input.DescriptionManual,
+				"component_scores":   componentScoresJSON,
+				"updated_at":         now,
 			}),
 	).Executor().ExecContext(ctx)
 	if err != nil {
@@ -220,18 +228,19 @@ func (a *eraporAdapter) SaveGrade(ctx context.Context, input *model.StudentGrade
 	}
 
 	return &model.StudentGrade{
-		ID:              id,
-		TenantID:        input.TenantID,
-		StudentID:       input.StudentID,
-		SubjectID:       input.SubjectID,
-		SemesterID:      input.SemesterID,
-		ScoreNumeric:    input.ScoreNumeric,
-		ScorePredicate:  input.ScorePredicate,
-		DescriptionHigh: input.DescriptionHigh,
-		DescriptionLow:  input.DescriptionLow,
-		ComponentScores: input.ComponentScores,
-		CreatedAt:       now,
-		UpdatedAt:       now,
+		ID:                id,
+		TenantID:          input.TenantID,
+		StudentID:         input.StudentID,
+		SubjectID:         input.SubjectID,
+		SemesterID:        input.SemesterID,
+		ScoreNumeric:      input.ScoreNumeric,
+		ScorePredicate:    input.ScorePredicate,
+		DescriptionHigh:   input.DescriptionHigh,
+		DescriptionLow:    input.DescriptionLow,
+		DescriptionManual: input.DescriptionManual,
+		ComponentScores:   input.ComponentScores,
+		CreatedAt:         now,
+		UpdatedAt:         now,
 	}, nil
 }
 
@@ -263,19 +272,20 @@ func (a *eraporAdapter) BatchSaveGrades(ctx context.Context, input *model.BatchG
 
 func (a *eraporAdapter) GetGradesByStudent(ctx context.Context, studentID, semesterID string) ([]model.StudentGrade, error) {
 	var rows []struct {
-		ID              string    `db:"id"`
-		TenantID        string    `db:"tenant_id"`
-		StudentID       string    `db:"student_id"`
-		SubjectID       string    `db:"subject_id"`
-		SubjectName     string    `db:"subject_name"`
-		SemesterID      string    `db:"semester_id"`
-		ScoreNumeric    float64   `db:"score_numeric"`
-		ScorePredicate  string    `db:"score_predicate"`
-		DescriptionHigh string    `db:"description_high"`
-		DescriptionLow  string    `db:"description_low"`
-		ComponentScores []byte    `db:"component_scores"`
-		CreatedAt       time.Time `db:"created_at"`
-		UpdatedAt       time.Time `db:"updated_at"`
+		ID                string    `db:"id"`
+		TenantID          string    `db:"tenant_id"`
+		StudentID         string    `db:"student_id"`
+		SubjectID         string    `db:"subject_id"`
+		SubjectName       string    `db:"subject_name"`
+		SemesterID        string    `db:"semester_id"`
+		ScoreNumeric      float64   `db:"score_numeric"`
+		ScorePredicate    string    `db:"score_predicate"`
+		DescriptionHigh   string    `db:"description_high"`
+		DescriptionLow    string    `db:"description_low"`
+		DescriptionManual bool      `db:"description_manual"`
+		ComponentScores   []byte    `db:"component_scores"`
+		CreatedAt         time.Time `db:"created_at"`
+		UpdatedAt         time.Time `db:"updated_at"`
 	}
 
 	err := a.db.From("student_grades").
@@ -295,19 +305,20 @@ func (a *eraporAdapter) GetGradesByStudent(ctx context.Context, studentID, semes
 			json.Unmarshal(row.ComponentScores, &scores)
 		}
 		grades[i] = model.StudentGrade{
-			ID:              row.ID,
-			TenantID:        row.TenantID,
-			StudentID:       row.StudentID,
-			SubjectID:       row.SubjectID,
-			SubjectName:     row.SubjectName,
-			SemesterID:      row.SemesterID,
-			ScoreNumeric:    row.ScoreNumeric,
-			ScorePredicate:  row.ScorePredicate,
-			DescriptionHigh: row.DescriptionHigh,
-			DescriptionLow:  row.DescriptionLow,
-			ComponentScores: scores,
-			CreatedAt:       row.CreatedAt,
-			UpdatedAt:       row.UpdatedAt,
+			ID:                row.ID,
+			TenantID:          row.TenantID,
+			StudentID:         row.StudentID,
+			SubjectID:         row.SubjectID,
+			SubjectName:       row.SubjectName,
+			SemesterID:        row.SemesterID,
+			ScoreNumeric:      row.ScoreNumeric,
+			ScorePredicate:    row.ScorePredicate,
+			DescriptionHigh:   row.DescriptionHigh,
+			DescriptionLow:    row.DescriptionLow,
+			DescriptionManual: row.DescriptionManual,
+			ComponentScores:   scores,
+			CreatedAt:         row.CreatedAt,
+			UpdatedAt:         row.UpdatedAt,
 		}
 	}
 
@@ -316,18 +327,19 @@ func (a *eraporAdapter) GetGradesByStudent(ctx context.Context, studentID, semes
 
 func (a *eraporAdapter) GetGradesBySubject(ctx context.Context, subjectID, semesterID string) ([]model.StudentGrade, error) {
 	var rows []struct {
-		ID              string    `db:"id"`
-		TenantID        string    `db:"tenant_id"`
-		StudentID       string    `db:"student_id"`
-		SubjectID       string    `db:"subject_id"`
-		SemesterID      string    `db:"semester_id"`
-		ScoreNumeric    float64   `db:"score_numeric"`
-		ScorePredicate  string    `db:"score_predicate"`
-		DescriptionHigh string    `db:"description_high"`
-		DescriptionLow  string    `db:"description_low"`
-		ComponentScores []byte    `db:"component_scores"`
-		CreatedAt       time.Time `db:"created_at"`
-		UpdatedAt       time.Time `db:"updated_at"`
+		ID                string    `db:"id"`
+		TenantID          string    `db:"tenant_id"`
+		StudentID         string    `db:"student_id"`
+		SubjectID         string    `db:"subject_id"`
+		SemesterID        string    `db:"semester_id"`
+		ScoreNumeric      float64   `db:"score_numeric"`
+		ScorePredicate    string    `db:"score_predicate"`
+		DescriptionHigh   string    `db:"description_high"`
+		DescriptionLow    string    `db:"description_low"`
+		DescriptionManual bool      `db:"description_manual"`
+		ComponentScores   []byte    `db:"component_scores"`
+		CreatedAt         time.Time `db:"created_at"`
+		UpdatedAt         time.Time `db:"updated_at"`
 	}
 
 	err := a.db.From("student_grades").
@@ -345,77 +357,96 @@ func (a *eraporAdapter) GetGradesBySubject(ctx context.Context, subjectID, semes
 			json.Unmarshal(row.ComponentScores, &scores)
 		}
 		grades[i] = model.StudentGrade{
-			ID:              row.ID,
-			TenantID:        row.TenantID,
-			StudentID:       row.StudentID,
-			SubjectID:       row.SubjectID,
-			SemesterID:      row.SemesterID,
-			ScoreNumeric:    row.ScoreNumeric,
-			ScorePredicate:  row.ScorePredicate,
-			DescriptionHigh: row.DescriptionHigh,
-			DescriptionLow:  row.DescriptionLow,
-			ComponentScores: scores,
-			CreatedAt:       row.CreatedAt,
-			UpdatedAt:       row.UpdatedAt,
+			ID:                row.ID,
+			TenantID:          row.TenantID,
+			StudentID:         row.StudentID,
+			SubjectID:         row.SubjectID,
+			SemesterID:        row.SemesterID,
+			ScoreNumeric:      row.ScoreNumeric,
+			ScorePredicate:    row.ScorePredicate,
+			DescriptionHigh:   row.DescriptionHigh,
+			DescriptionLow:    row.DescriptionLow,
+			DescriptionManual: row.DescriptionManual,
+			ComponentScores:   scores,
+			CreatedAt:         row.CreatedAt,
+			UpdatedAt:         row.UpdatedAt,
 		}
 	}
 
 	return grades, nil
 }
 
-func (a *eraporAdapter) GetStudentRapor(ctx context.Context, studentID, semesterID string) (*model.RaporData, error) {
+func (a *eraporAdapter) GetStudentRapor(ctx context.Context, tenantID, studentID, semesterID string) (*model.RaporData, error) {
 	// Get student grades
 	grades, err := a.GetGradesByStudent(ctx, studentID, semesterID)
 	if err != nil {
 		return nil, err
 	}
 
-	// TODO: Get student info, attendance, extracurricular from their respective tables
-	// For now, return basic rapor data
+	extracurricular, err := a.getStudentEkskul(ctx, tenantID, studentID, semesterID)
+	if err != nil {
+		return nil, err
+	}
+
+	var sikap []model.SikapSiswa
+	err = a.sikapSiswaQuery().
+		Where(
+			goqu.I("k.student_id").Eq(studentID),
+			goqu.I("k.semester_id").Eq(semesterID),
+		).
+		Order(goqu.I("k.aspek").Desc()).
+		ScanStructsContext(ctx, &sikap)
+	if err != nil {
+		return nil, err
+	}
+
+	// TODO: Get student info, attendance from their respective tables
 	return &model.RaporData{
 		StudentID:       studentID,
 		SemesterID:      semesterID,
 		Grades:          grades,
 		Attendance:      model.AttendanceData{},
-		Extracurricular: []model.ExtracurricularData{},
+		Extracurricular: extracurricular,
+		Sikap:           sikap,
 	}, nil
 }
 
-func (a *eraporAdapter) GetGradeStats(ctx context.Context, tenantID, semesterID string) (map[string]interface{}, error) {
-	// Count total grades
-	var totalGrades int64
-	_, err := a.db.From("student_grades").
-		Select(goqu.COUNT("*")).
-		Where(goqu.C("tenant_id").Eq(tenantID)).
-		Where(goqu.C("semester_id").Eq(semesterID)).
-		ScanValContext(ctx, &totalGrades)
+// getStudentEkskul reads assessed ekskul memberships for the rapor
+func (a *eraporAdapter) getStudentEkskul(ctx context.Context, tenantID, studentID, semesterID string) ([]model.ExtracurricularData, error) {
+	var rows []struct {
+		Name        string `db:"name"`
+		Predicate   string `db:"predicate"`
+		Description string `db:"description"`
+	}
+	err := a.db.From(goqu.T("sekolah_ekskul_anggota").As("a")).
+		Join(goqu.T("sekolah_ekskul").As("e"), goqu.On(goqu.I("e.id").Eq(goqu.I("a.ekskul_id")))).
+		Select(
+			goqu.I("e.nama").As("name"),
+			goqu.COALESCE(goqu.I("a.predikat"), "").As("predicate"),
+			goqu.COALESCE(goqu.I("a.deskripsi"), "").As("description"),
+		).
+		Where(
+			goqu.I("a.tenant_id").Eq(tenantID),
+			goqu.I("e.tenant_id").Eq(tenantID),
+			goqu.I("a.santri_id").Eq(studentID),
+			goqu.I("a.semester_id").Eq(semesterID),
+			goqu.COALESCE(goqu.I("a.predikat"), "").Neq(""),
+		).
+		Order(goqu.I("e.nama").Asc()).
+		ScanStructsContext(ctx, &rows)
 	if err != nil {
 		return nil, err
 	}
 
-	// Count by predicate
-	type predicateCount struct {
-		Predicate string `db:"score_predicate"`
-		Count     int64  `db:"count"`
+	result := make([]model.ExtracurricularData, 0, len(rows))
+	for _, r := range rows {
+		result = append(result, model.ExtracurricularData{
+			Name:        r.Name,
+			Predicate:   r.Predicate,
+			Description: r.Description,
+		})
 	}
-	var predicates []predicateCount
-	a.db.From("student_grades").
-		Select(goqu.C("score_predicate"), goqu.COUNT("*").As("count")).
-		Where(goqu.C("tenant_id").Eq(tenantID)).
-		Where(goqu.C("semester_id").Eq(semesterID)).
-		GroupBy("score_predicate").
-		ScanStructsContext(ctx, &predicates)
-
-	predicateMap := make(map[string]int64)
-	for _, p := range predicates {
-		predicateMap[p.Predicate] = p.Count
-	}
-
-	return map[string]interface{}{
-		"total_grades":        totalGrades,
-		"grades_by_predicate": predicateMap,
-		"semester_id":         semesterID,
-	}, nil
+	return result, nil
 }
 
 // ==========================================
@@ -459,30 +490,272 @@ func (a *eraporAdapter) GetOrCreateRaporPeriode(tenantID, name string) (*model.R
 	return &newPeriode, nil
 }
 
-func (a *eraporAdapter) CreateRapor(m *model.Rapor) error {
-	ctx := context.Background()
-	_, err := a.db.Insert("sekolah_rapor").Rows(goqu.Record{
+// SaveRaporSnapshot writes the rapor header and all of its nilai rows in one transaction.
+// A Draft rapor of the same periode and santri is replaced in place, keeping its ID;
+// it returns false without writing when that rapor is already past Draft.
+func (a *eraporAdapter) SaveRaporSnapshot(ctx context.Context, m *model.Rapor) (bool, error) {
+	record := goqu.Record{
 		"tenant_id":          m.TenantID,
 		"periode_id":         m.PeriodeID,
 		"santri_id":          m.SantriID,
 		"status":             m.Status,
 		"catatan_wali_kelas": m.CatatanWaliKelas,
-	}).Returning("id", "created_at", "updated_at").
-		Executor().ScanStructContext(ctx, m)
+		"header":             nil,
+		"p5":                 nil,
+	}
+	if m.Header != nil {
+		header, err := json.Marshal(m.Header)
+		if err != nil {
+			return false, err
+		}
+		record["header"] = header
+	}
+	if len(m.P5) > 0 {
+		p5, err := json.Marshal(m.P5)
+		if err != nil {
+			return false, err
+		}
+		record["p5"] = p5
+	}
 
-	return err
+	saved := false
+	err := inGoquTx(a.db, func(tx goquQuerier) error {
+		var existing struct {
+			ID     string `db:"id"`
+			Status string `db:"status"`
+		}
+		found, err := tx.From("sekolah_rapor").
+			Select("id", goqu.COALESCE(goqu.C("status"), model.RaporStatusDraft).As("status")).
+			Where(
+				goqu.C("tenant_id").Eq(m.TenantID),
+				goqu.C("periode_id").Eq(m.PeriodeID),
+				goqu.C("santri_id").Eq(m.SantriID),
+			).
+			ForUpdate(exp.Wait).
+			ScanStructContext(ctx, &existing)
+		if err != nil {
+			return err
+		}
+
+		if found {
+			if existing.Status != model.RaporStatusDraft {
+				return nil
+			}
+			record["updated_at"] = time.Now()
+			_, err = tx.Update("sekolah_rapor").Set(record).
+				Where(goqu.C("id").Eq(existing.ID)).
+				Returning("id", "created_at", "updated_at").
+				Executor().ScanStructContext(ctx, m)
+			if err != nil {
+				return err
+			}
+			_, err = tx.Delete("sekolah_rapor_nilai").
+				Where(goqu.C("rapor_id").Eq(m.ID)).
+				Executor().ExecContext(ctx)
+		} else {
+			_, err = tx.Insert("sekolah_rapor").Rows(record).Returning("id", "created_at", "updated_at").
+				Executor().ScanStructContext(ctx, m)
+		}
+		if err != nil {
+			return err
+		}
+
+		for i := range m.NilaiList {
+			m.NilaiList[i].RaporID = m.ID
+			if err := insertRaporNilai(ctx, tx, &m.NilaiList[i]); err != nil {
+				return err
+			}
+		}
+		saved = true
+		return nil
+	})
+	return saved, err
 }
 
-func (a *eraporAdapter) CreateRaporNilai(m *model.RaporNilai) error {
-	ctx := context.Background()
-	_, err := a.db.Insert("sekolah_rapor_nilai").Rows(goqu.Record{
+func insertRaporNilai(ctx context.Context, tx goquQuerier, m *model.RaporNilai) error {
+	record := goqu.Record{
 		"rapor_id":   m.RaporID,
 		"kategori":   m.Kategori,
 		"jenis":      m.Jenis,
 		"nilai":      m.Nilai,
 		"keterangan": m.Keterangan,
-	}).Returning("id", "created_at", "updated_at").
+		"urutan":     m.Urutan,
+	}
+	if m.Detail != nil {
+		detail, err := json.Marshal(m.Detail)
+		if err != nil {
+			return err
+		}
+		record["detail"] = detail
+	}
+	_, err := tx.Insert("sekolah_rapor_nilai").Rows(record).Returning("id", "created_at", "updated_at").
 		Executor().ScanStructContext(ctx, m)
+	return err
+}
 
+// SaveRaporLogo stores the logo once per tenant and content hash
+func (a *eraporAdapter) SaveRaporLogo(ctx context.Context, tenantID, hash string, logo []byte) error {
+	// Bind the logo as a parameter, bytea must not be interpolated as text
+	_, err := a.db.ExecContext(ctx,
+		`INSERT INTO sekolah_rapor_logo (tenant_id, hash, logo) VALUES ($1, $2, $3) ON CONFLICT (tenant_id, hash) DO NOTHING`,
+		tenantID, hash, logo,
+	)
 	return err
 }
+
+// GetRaporLogo returns nil when no logo with the hash was stored for the tenant
+func (a *eraporAdapter) GetRaporLogo(ctx context.Context, tenantID, hash string) ([]byte, error) {
+	var logo []byte
+	_, err := a.db.From("sekolah_rapor_logo").
+		Select("logo").
+		Where(goqu.C("tenant_id").Eq(tenantID), goqu.C("hash").Eq(hash)).
+		ScanValContext(ctx, &logo)
+	return logo, err
+}
+
+// GetRaporHeader collects the identity data that gets frozen into a new rapor.
+// Returns nil when the siswa does not belong to the tenant.
+func (a *eraporAdapter) GetRaporHeader(ctx context.Context, tenantID, studentID string) (*model.RaporHeader, error) {
+	var row struct {
+		Kurikulum     string `db:"kurikulum"`
+		NamaSekolah   string `db:"nama_sekolah"`
+		AlamatSekolah string `db:"alamat_sekolah"`
+		LogoURL       string `db:"logo_url"`
+		NamaSiswa     string `db:"nama_siswa"`
+		NIS           string `db:"nis"`
+		NISN          string `db:"nisn"`
+		Kelas         string `db:"kelas"`
+		Tingkat       string `db:"tingkat"`
+		WaliKelas     string `db:"wali_kelas"`
+		NIPWaliKelas  string `db:"nip_wali_kelas"`
+		KepalaSekolah string `db:"kepala_sekolah"`
+	}
+
+	found, err := a.db.From(goqu.T("sekolah_siswa").As("s")).
+		Join(goqu.T("tenants").As("t"), goqu.On(goqu.I("t.id").Eq(goqu.I("s.tenant_id")))).
+		LeftJoin(goqu.T("sekolah_profil").As("p"), goqu.On(goqu.I("p.tenant_id").Eq(goqu.I("s.tenant_id")))).
+		LeftJoin(goqu.T("sekolah_kelas").As("k"), goqu.On(goqu.I("k.id").Eq(goqu.I("s.kelas_id")))).
+		LeftJoin(goqu.T("sekolah_guru").As("g"), goqu.On(goqu.I("g.id").Eq(goqu.I("k.wali_kelas_id")))).
+		Select(
+			goqu.COALESCE(goqu.I("p.curriculum"), "").As("kurikulum"),
+			goqu.I("t.name").As("nama_sekolah"),
+			goqu.COALESCE(goqu.I("t.address"), "").As("alamat_sekolah"),
+			goqu.COALESCE(goqu.I("p.logo_url"), "").As("logo_url"),
+			goqu.I("s.nama").As("nama_siswa"),
+			goqu.COALESCE(goqu.I("s.nis"), "").As("nis"),
+			goqu.COALESCE(goqu.I("s.nisn"), "").As("nisn"),
+			goqu.COALESCE(goqu.I("k.nama"), "").As("kelas"),
+			goqu.COALESCE(goqu.I("k.tingkat"), "").As("tingkat"),
+			goqu.COALESCE(goqu.I("g.nama"), "").As("wali_kelas"),
+			goqu.COALESCE(goqu.I("g.nip"), "").As("nip_wali_kelas"),
+			goqu.COALESCE(goqu.L(
+				`(SELECT u.name FROM users u WHERE u.tenant_id = s.tenant_id AND u.role = ? ORDER BY u.created_at LIMIT 1)`,
+				model.RoleKepalaSekolah,
+			), "").As("kepala_sekolah"),
+		).
+		Where(
+			goqu.I("s.id").Eq(studentID),
+			goqu.I("s.tenant_id").Eq(tenantID),
+		).
+		ScanStructContext(ctx, &row)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	return &model.RaporHeader{
+		Kurikulum:     row.Kurikulum,
+		NamaSekolah:   row.NamaSekolah,
+		AlamatSekolah: row.AlamatSekolah,
+		LogoURL:       row.LogoURL,
+		NamaSiswa:     row.NamaSiswa,
+		NIS:           row.NIS,
+		NISN:          row.NISN,
+		Kelas:         row.Kelas,
+		Tingkat:       row.Tingkat,
+		WaliKelas:     row.WaliKelas,
+		NIPWaliKelas:  row.NIPWaliKelas,
+		KepalaSekolah: row.KepalaSekolah,
+	}, nil
+}
+
+// GetRaporByID returns a rapor snapshot with its nilai in print order
+func (a *eraporAdapter) GetRaporByID(ctx context.Context, tenantID, id string) (*model.Rapor, error) {
+	var row struct {
+		model.Rapor
+		HeaderJSON []byte `db:"header"`
+		P5JSON     []byte `db:"p5"`
+	}
+	found, err := a.db.From(goqu.T("sekolah_rapor").As("r")).
+		LeftJoin(goqu.T("sekolah_siswa").As("s"), goqu.On(goqu.I("s.id").Eq(goqu.I("r.santri_id")))).
+		LeftJoin(goqu.T("sekolah_rapor_periode").As("p"), goqu.On(goqu.I("p.id").Eq(goqu.I("r.periode_id")))).
+		Select(
+			goqu.I("r.id"),
+			goqu.I("r.tenant_id"),
+			goqu.I("r.periode_id"),
+			goqu.I("r.santri_id"),
+			goqu.COALESCE(goqu.I("r.status"), "").As("status"),
+			goqu.COALESCE(goqu.I("r.catatan_wali_kelas"), "").As("catatan_wali_kelas"),
+			goqu.I("r.created_at"),
+			goqu.I("r.updated_at"),
+			goqu.COALESCE(goqu.I("s.nama"), "").As("nama_santri"),
+			goqu.COALESCE(goqu.I("p.nama"), "").As("nama_periode"),
+			goqu.I("r.header"),
+			goqu.I("r.p5"),
+		).
+		Where(
+			goqu.I("r.id").Eq(id),
+			goqu.I("r.tenant_id").Eq(tenantID),
+		).
+		ScanStructContext(ctx, &row)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	rapor := row.Rapor
+	if len(row.HeaderJSON) > 0 {
+		var header model.RaporHeader
+		if err := json.Unmarshal(row.HeaderJSON, &header); err != nil {
+			return nil, err
+		}
+		rapor.Header = &header
+	}
+	if len(row.P5JSON) > 0 {
+		if err := json.Unmarshal(row.P5JSON, &rapor.P5); err != nil {
+			return nil, err
+		}
+	}
+
+	var nilaiRows []struct {
+		model.RaporNilai
+		DetailJSON []byte `db:"detail"`
+	}
+	err = a.db.From("sekolah_rapor_nilai").
+		Select(
+			"id", "rapor_id", "kategori",
+			goqu.COALESCE(goqu.C("jenis"), "").As("jenis"),
+			goqu.COALESCE(goqu.C("nilai"), "").As("nilai"),
+			goqu.COALESCE(goqu.C("keterangan"), "").As("keterangan"),
+			goqu.COALESCE(goqu.C("urutan"), 0).As("urutan"),
+			"created_at", "updated_at", "detail",
+		).
+		Where(goqu.C("rapor_id").Eq(rapor.ID)).
+		Order(goqu.C("urutan").Asc(), goqu.C("created_at").Asc()).
+		ScanStructsContext(ctx, &nilaiRows)
+	if err != nil {
+		return nil, err
+	}
+
+	rapor.NilaiList = make([]model.RaporNilai, len(nilaiRows))
+	for i, r := range nilaiRows {
+		rapor.NilaiList[i] = r.RaporNilai
+		if len(r.DetailJSON) > 0 {
+			var detail model.RaporNilaiDetail
+			if err := json.Unmarshal(r.DetailJSON, &detail); err != nil {
+				return nil, err
+			}
+			rapor.NilaiList[i].Detail = &detail
+		}
+	}
+
+	return &rapor, nil
+}
@@ -0,0 +1,401 @@
+package postgres_outbound_adapter
+
+import (
+	"database/sql"
+	"time"
+
+	"prabogo/internal/model"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+var (
+	tableHalaqah        = goqu.T("sekolah_halaqah")
+	tableHalaqahAnggota = goqu.T("sekolah_halaqah_anggota")
+	tableTahfidzTarget  = goqu.T("sekolah_tahfidz_target")
+	tableTahfidzAlert   = goqu.T("sekolah_tahfidz_alert")
+)
+
+// ------ Halaqah ------
+
+func (a *sekolahAdapter) halaqahDataset() *goqu.SelectDataset {
+	jumlah := goqu.Dialect("postgres").From(tableHalaqahAnggota).
+		Select(goqu.COUNT("*")).
+		Where(tableHalaqahAnggota.Col("halaqah_id").Eq(tableHalaqah.Col("id")))
+
+	return goqu.Dialect("postgres").From(tableHalaqah).
+		LeftJoin(tableGuru, goqu.On(tableHalaqah.Col("ustadz_id").Eq(tableGuru.Col("id")))).
+		Select(
+			tableHalaqah.Col("id"),
+			tableHalaqah.Col("tenant_id"),
+			tableHalaqah.Col("nama"),
+			tableHalaqah.Col("ustadz_id"),
+			goqu.COALESCE(tableGuru.Col("nama"), "").As("ustadz_nama"),
+			tableHalaqah.Col("kapasitas"),
+			tableHalaqah.Col("batas_hari_tanpa_setoran"),
+			tableHalaqah.Col("is_active"),
+			jumlah.As("jumlah_anggota"),
+			tableHalaqah.Col("created_at"),
+			tableHalaqah.Col("updated_at"),
+		)
+}
+
+func scanHalaqah(scan func(dest ...interface{}) error) (model.Halaqah, error) {
+	var h model.Halaqah
+	var ustadzID sql.NullString
+	err := scan(&h.ID, &h.TenantID, &h.Nama, &ustadzID, &h.UstadzNama, &h.Kapasitas,
+		&h.BatasHariTanpaSetoran, &h.IsActive, &h.JumlahAnggota, &h.CreatedAt, &h.UpdatedAt)
+	if ustadzID.Valid {
+		id := ustadzID.String
+		h.UstadzID = &id
+	}
+	return h, err
+}
+
+func (a *sekolahAdapter) GetHalaqahByTenant(tenantID string) ([]model.Halaqah, error) {
+	query, _, err := a.halaqahDataset().
+		Where(tableHalaqah.Col("tenant_id").Eq(tenantID)).
+		Order(tableHalaqah.Col("nama").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := a.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []model.Halaqah
+	for rows.Next() {
+		h, err := scanHalaqah(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, h)
+	}
+	return list, rows.Err()
+}
+
+// GetHalaqahByID locks the halaqah when called inside a transaction so concurrent additions
+// cannot pass its kapasitas; count the members with a separate query after the lock
+func (a *sekolahAdapter) GetHalaqahByID(tenantID, id string) (*model.Halaqah, error) {
+	query, _, err := a.halaqahDataset().
+		Where(
+			tableHalaqah.Col("tenant_id").Eq(tenantID),
+			tableHalaqah.Col("id").Eq(id),
+		).
+		ForUpdate(exp.Wait, tableHalaqah).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := scanHalaqah(a.db.QueryRow(query).Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+func (a *sekolahAdapter) CreateHalaqah(h *model.Halaqah) error {
+	query, _, err := goqu.Dialect("postgres").Insert(tableHalaqah).Rows(goqu.Record{
+		"tenant_id":                h.TenantID,
+		"nama":                     h.Nama,
+		"ustadz_id":                h.UstadzID,
+		"kapasitas":                h.Kapasitas,
+		"batas_hari_tanpa_setoran": h.BatasHariTanpaSetoran,
+		"is_active":                h.IsActive,
+	}).Returning("id", "created_at", "updated_at").ToSQL()
+	if err != nil {
+		return err
+	}
+	return a.db.QueryRow(query).Scan(&h.ID, &h.CreatedAt, &h.UpdatedAt)
+}
+
+func (a *sekolahAdapter) UpdateHalaqah(h *model.Halaqah) error {
+	query, _, err := goqu.Dialect("postgres").Update(tableHalaqah).Set(goqu.Record{
+		"nama":                     h.Nama,
+		"ustadz_id":                h.UstadzID,
+		"kapasitas":                h.Kapasitas,
+		"batas_hari_tanpa_setoran": h.BatasHariTanpaSetoran,
+		"is_active":                h.IsActive,
+	}).Where(
+		tableHalaqah.Col("tenant_id").Eq(h.TenantID),
+		tableHalaqah.Col("id").Eq(h.ID),
+	).ToSQL()
+	if err != nil {
+		return err
+	}
+	_, err = a.db.Exec(query)
+	return err
+}
+
+func (a *sekolahAdapter) DeleteHalaqah(tenantID, id string) error {
+	query, _, err := goqu.Dialect("postgres").Delete(tableHalaqah).Where(
+		tableHalaqah.Col("tenant_id").Eq(tenantID),
+		tableHalaqah.Col("id").Eq(id),
+	).ToSQL()
+	if err != nil {
+		return err
+	}
+	_, err = a.db.Exec(query)
+	return err
+}
+
+func (a *sekolahAdapter) GetHalaqahAnggota(tenantID, halaqahID string) ([]model.HalaqahAnggota, error) {
+	dataset := goqu.Dialect("postgres").From(tableHalaqahAnggota).
+		Join(tableHalaqah, goqu.On(tableHalaqah.Col("id").Eq(tableHalaqahAnggota.Col("halaqah_id")))).
+		Join(tableSiswa, goqu.On(tableSiswa.Col("id").Eq(tableHalaqahAnggota.Col("santri_id")))).
+		LeftJoin(tableGuru, goqu.On(tableGuru.Col("id").Eq(tableHalaqah.Col("ustadz_id")))).
+		Select(
+			tableHalaqahAnggota.Col("tenant_id"),
+			tableHalaqahAnggota.Col("halaqah_id"),
+			tableHalaqah.Col("nama"),
+			tableHalaqahAnggota.Col("santri_id"),
+			tableSiswa.Col("nama"),
+			goqu.COALESCE(tableSiswa.Col("cached_kelas_nama"), ""),
+			tableHalaqahAnggota.Col("created_at"),
+			goqu.COALESCE(tableGuru.Col("nama"), ""),
+			goqu.COALESCE(tableGuru.Col("no_hp"), ""),
+			goqu.COALESCE(tableSiswa.Col("no_hp_wali"), ""),
+			tableHalaqah.Col("batas_hari_tanpa_setoran"),
+		).
+		Order(tableHalaqah.Col("nama").Asc(), tableSiswa.Col("nama").Asc())
+	if tenantID != "" {
+		dataset = dataset.Where(tableHalaqahAnggota.Col("tenant_id").Eq(tenantID))
+	}
+	if halaqahID != "" {
+		dataset = dataset.Where(tableHalaqahAnggota.Col("halaqah_id").Eq(halaqahID))
+	} else {
+		dataset = dataset.Where(tableHalaqah.Col("is_active").IsTrue())
+	}
+
+	query, _, err := dataset.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := a.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []model.HalaqahAnggota
+	for rows.Next() {
+		var m model.HalaqahAnggota
+		if err := rows.Scan(&m.TenantID, &m.HalaqahID, &m.HalaqahNama, &m.SantriID, &m.SantriNama, &m.KelasNama,
+			&m.BergabungAt, &m.UstadzNama, &m.UstadzNoHP, &m.NoHPWali, &m.BatasHariTanpaSetoran); err != nil {
+			return nil, err
+		}
+		list = append(list, m)
+	}
+	return list, rows.Err()
+}
+
+func (a *sekolahAdapter) AddHalaqahAnggota(tenantID, halaqahID, santriID string) error {
+	query, _, err := goqu.Dialect("postgres").Insert(tableHalaqahAnggota).Rows(goqu.Record{
+		"halaqah_id": halaqahID,
+		"tenant_id":  tenantID,
+		"santri_id":  santriID,
+	}).OnConflict(goqu.DoUpdate("tenant_id, santri_id", goqu.Record{
+		"halaqah_id": goqu.L("EXCLUDED.halaqah_id"),
+		"created_at": goqu.L("NOW()"),
+	})).ToSQL()
+	if err != nil {
+		return err
+	}
+	_, err = a.db.Exec(query)
+	return err
+}
+
+func (a *sekolahAdapter) RemoveHalaqahAnggota(tenantID, halaqahID, santriID string) error {
+	query, _, err := goqu.Dialect("postgres").Delete(tableHalaqahAnggota).Where(
+		tableHalaqahAnggota.Col("tenant_id").Eq(tenantID),
+		tableHalaqahAnggota.Col("halaqah_id").Eq(halaqahID),
+		tableHalaqahAnggota.Col("santri_id").Eq(santriID),
+	).ToSQL()
+	if err != nil {
+		return err
+	}
+	_, err = a.db.Exec(query)
+	return err
+}
+
+// ------ Targets ------
+
+func (a *sekolahAdapter) getTahfidzTargets(where ...goqu.Expression) ([]model.TargetHafalan, error) {
+	query, _, err := goqu.Dialect("postgres").From(tableTahfidzTarget).
+		Join(tableSiswa, goqu.On(tableSiswa.Col("id").Eq(tableTahfidzTarget.Col("santri_id")))).
+		Select(
+			tableTahfidzTarget.Col("id"),
+			tableTahfidzTarget.Col("tenant_id"),
+			tableTahfidzTarget.Col("santri_id"),
+			tableSiswa.Col("nama"),
+			tableTahfidzTarget.Col("semester_id"),
+			tableTahfidzTarget.Col("target_juz"),
+			tableTahfidzTarget.Col("mulai"),
+			tableTahfidzTarget.Col("selesai"),
+			goqu.COALESCE(tableTahfidzTarget.Col("catatan"), ""),
+			tableTahfidzTarget.Col("created_at"),
+			tableTahfidzTarget.Col("updated_at"),
+		).
+		Where(where...).
+		Order(tableSiswa.Col("nama").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := a.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []model.TargetHafalan
+	for rows.Next() {
+		var t model.TargetHafalan
+		if err := rows.Scan(&t.ID, &t.TenantID, &t.SantriID, &t.SantriNama, &t.SemesterID, &t.TargetJuz,
+			&t.Mulai, &t.Selesai, &t.Catatan, &t.CreatedAt, &t.UpdatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, t)
+	}
+	return list, rows.Err()
+}
+
+func (a *sekolahAdapter) GetTahfidzTargets(tenantID, semesterID string) ([]model.TargetHafalan, error) {
+	where := []goqu.Expression{tableTahfidzTarget.Col("tenant_id").Eq(tenantID)}
+	if semesterID != "" {
+		where = append(where, tableTahfidzTarget.Col("semester_id").Eq(semesterID))
+	}
+	return a.getTahfidzTargets(where...)
+}
+
+func (a *sekolahAdapter) GetTahfidzTargetAktif(tenantID string, on time.Time) ([]model.TargetHafalan, error) {
+	day := on.Format("2006-01-02")
+	where := []goqu.Expression{
+		tableTahfidzTarget.Col("mulai").Lte(day),
+		tableTahfidzTarget.Col("selesai").Gte(day),
+	}
+	if tenantID != "" {
+		where = append(where, tableTahfidzTarget.Col("tenant_id").Eq(tenantID))
+	}
+	return a.getTahfidzTargets(where...)
+}
+
+func (a *sekolahAdapter) SaveTahfidzTarget(t *model.TargetHafalan) error {
+	record := goqu.Record{
+		"tenant_id":   t.TenantID,
+		"santri_id":   t.SantriID,
+		"semester_id": t.SemesterID,
+		"target_juz":  t.TargetJuz,
+		"mulai":       t.Mulai.Format("2006-01-02"),
+		"selesai":     t.Selesai.Format("2006-01-02"),
+		"catatan":     t.Catatan,
+	}
+	query, _, err := goqu.Dialect("postgres").Insert(tableTahfidzTarget).Rows(record).
+		OnConflict(goqu.DoUpdate("tenant_id, santri_id, semester_id", goqu.Record{
+			"target_juz": goqu.L("EXCLUDED.target_juz"),
+			"mulai":      goqu.L("EXCLUDED.mulai"),
+			"selesai":    goqu.L("EXCLUDED.selesai"),
+			"catatan":    goqu.L("EXCLUDED.catatan"),
+		})).
+		Returning("id", "created_at", "updated_at").
+		ToSQL()
+	if err != nil {
+		return err
+	}
+	return a.db.QueryRow(query).Scan(&t.ID, &t.CreatedAt, &t.UpdatedAt)
+}
+
+func (a *sekolahAdapter) DeleteTahfidzTarget(tenantID, id string) error {
+	query, _, err := goqu.Dialect("postgres").Delete(tableTahfidzTarget).Where(
+		tableTahfidzTarget.Col("tenant_id").Eq(tenantID),
+		tableTahfidzTarget.Col("id").Eq(id),
+	).ToSQL()
+	if err != nil {
+		return err
+	}
+	_, err = a.db.Exec(query)
+	return err
+}
+
+// ------ Alerts ------
+
+func (a *sekolahAdapter) GetTahfidzSetoranTerakhir(tenantID string) (map[string]time.Time, error) {
+	dataset := goqu.Dialect("postgres").From(tableTahfidzSetoran).
+		Select(tableTahfidzSetoran.Col("santri_id"), goqu.MAX(tableTahfidzSetoran.Col("tanggal"))).
+		GroupBy(tableTahfidzSetoran.Col("santri_id"))
+	if tenantID != "" {
+		dataset = dataset.Where(tableTahfidzSetoran.Col("tenant_id").Eq(tenantID))
+	}
+	query, _, err := dataset.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := a.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string]time.Time)
+	for rows.Next() {
+		var santriID string
+		var tanggal time.Time
+		if err := rows.Scan(&santriID, &tanggal); err != nil {
+			return nil, err
+		}
+		result[santriID] = tanggal
+	}
+	return result, rows.Err()
+}
+
+func (a *sekolahAdapter) GetTahfidzAlertTerkirim(tenantID string, since time.Time) (map[string]bool, error) {
+	dataset := goqu.Dialect("postgres").From(tableTahfidzAlert).
+		Select(tableTahfidzAlert.Col("santri_id"), tableTahfidzAlert.Col("jenis")).
+		Distinct().
+		Where(tableTahfidzAlert.Col("dikirim_at").Gte(since))
+	if tenantID != "" {
+		dataset = dataset.Where(tableTahfidzAlert.Col("tenant_id").Eq(tenantID))
+	}
+	query, _, err := dataset.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := a.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string]bool)
+	for rows.Next() {
+		var santriID, jenis string
+		if err := rows.Scan(&santriID, &jenis); err != nil {
+			return nil, err
+		}
+		result[santriID+"|"+jenis] = true
+	}
+	return result, rows.Err()
+}
+
+func (a *sekolahAdapter) SaveTahfidzAlert(alert *model.TahfidzAlert) error {
+	query, _, err := goqu.Dialect("postgres").Insert(tableTahfidzAlert).Rows(goqu.Record{
+		"tenant_id": alert.TenantID,
+		"santri_id": alert.SantriID,
+		"jenis":     alert.Jenis,
+		"pesan":     alert.Pesan,
+	}).ToSQL()
+	if err != nil {
+		return err
+	}
+	_, err = a.db.Exec(query)
+	return err
+}
@@ -2,6 +2,8 @@ package postgres_outbound_adapter
 
 import (
 	"database/sql"
+	"time"
+
 	"prabogo/internal/model"
 
 	"github.com/doug-martin/goqu/v9"
@@ -55,6 +57,19 @@ func (a *sekolahAdapter) CreatePelanggaranAturan(m *model.PelanggaranAturan) err
 
 // Violations
 func (a *sekolahAdapter) GetPelanggaranSiswa(tenantID string) ([]model.PelanggaranSiswa, error) {
+	return a.getPelanggaranSiswa(tablePelanggaranSiswa.Col("tenant_id").Eq(tenantID))
+}
+
+// GetPelanggaranSantri lists a santri's violations dated within [from, to]
+func (a *sekolahAdapter) GetPelanggaranSantri(tenantID, santriID string, from, to time.Time) ([]model.PelanggaranSiswa, error) {
+	return a.getPelanggaranSiswa(
+		tablePelanggaranSiswa.Col("tenant_id").Eq(tenantID),
+		tablePelanggaranSiswa.Col("santri_id").Eq(santriID),
+		tablePelanggaranSiswa.Col("tanggal").Between(goqu.Range(from, to)),
+	)
+}
+
+func (a *sekolahAdapter) getPelanggaranSiswa(where ...goqu.Expression) ([]model.PelanggaranSiswa, error) {
 	dialect := goqu.Dialect("postgres")
 	dataset := dialect.From(tablePelanggaranSiswa).
 		Join(tableSiswa, goqu.On(tablePelanggaranSiswa.Col("santri_id").Eq(tableSiswa.Col("id")))).
@@ -73,7 +88,7 @@ func (a *sekolahAdapter) GetPelanggaranSiswa(tenantID string) ([]model.Pelanggar
 			goqu.COALESCE(tablePelanggaranSiswa.Col("sanksi"), "").As("sanksi"),
 			tablePelanggaranSiswa.Col("created_at"),
 			tablePelanggaranSiswa.Col("updated_at"),
-		).Where(tablePelanggaranSiswa.Col("tenant_id").Eq(tenantID)).
+		).Where(where...).
 		Order(tablePelanggaranSiswa.Col("tanggal").Desc())
 
 	query, _, err := dataset.ToSQL()
@@ -129,6 +144,47 @@ func (a *sekolahAdapter) CreatePelanggaranSiswa(m *model.PelanggaranSiswa) error
 
 // Permissions
 func (a *sekolahAdapter) GetPerizinan(tenantID string) ([]model.Perizinan, error) {
+	return a.getPerizinan(tablePerizinan.Col("tenant_id").Eq(tenantID))
+}
+
+// GetPerizinanByID returns nil when the leave does not belong to the tenant
+func (a *sekolahAdapter) GetPerizinanByID(tenantID, id string) (*model.Perizinan, error) {
+	list, err := a.getPerizinan(
+		tablePerizinan.Col("tenant_id").Eq(tenantID),
+		tablePerizinan.Col("id").Eq(id),
+	)
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+	return &list[0], nil
+}
+
+// GetPerizinanByKode finds the leave a scanned gate pass belongs to, nil when none matches
+func (a *sekolahAdapter) GetPerizinanByKode(tenantID, kode string) (*model.Perizinan, error) {
+	list, err := a.getPerizinan(
+		tablePerizinan.Col("tenant_id").Eq(tenantID),
+		tablePerizinan.Col("kode_gate_pass").Eq(kode),
+	)
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+	return &list[0], nil
+}
+
+// GetPerizinanTerlambat lists santri still out after their leave ended, of every tenant when
+// tenantID is empty
+func (a *sekolahAdapter) GetPerizinanTerlambat(tenantID string, now time.Time) ([]model.Perizinan, error) {
+	where := []goqu.Expression{
+		tablePerizinan.Col("status").Eq(model.PerizinanKeluar),
+		tablePerizinan.Col("sampai").Lt(now),
+	}
+	if tenantID != "" {
+		where = append(where, tablePerizinan.Col("tenant_id").Eq(tenantID))
+	}
+	return a.getPerizinan(where...)
+}
+
+func (a *sekolahAdapter) getPerizinan(where ...goqu.Expression) ([]model.Perizinan, error) {
 	dialect := goqu.Dialect("postgres")
 	dataset := dialect.From(tablePerizinan).
 		Join(tableSiswa, goqu.On(tablePerizinan.Col("santri_id").Eq(tableSiswa.Col("id")))).
@@ -147,7 +203,17 @@ func (a *sekolahAdapter) GetPerizinan(tenantID string) ([]model.Perizinan, error
 			goqu.COALESCE(tableGuru.Col("nama"), "").As("penyetuju_nama"),
 			tablePerizinan.Col("created_at"),
 			tablePerizinan.Col("updated_at"),
-		).Where(tablePerizinan.Col("tenant_id").Eq(tenantID)).
+			goqu.COALESCE(tablePerizinan.Col("catatan_penolakan"), "").As("catatan_penolakan"),
+			tablePerizinan.Col("disetujui_at"),
+			goqu.COALESCE(tablePerizinan.Col("kode_gate_pass"), "").As("kode_gate_pass"),
+			tablePerizinan.Col("keluar_at"),
+			tablePerizinan.Col("kembali_at"),
+			tablePerizinan.Col("terlambat_dikabari_at"),
+			tablePerizinan.Col("pelanggaran_id"),
+			goqu.COALESCE(tableSiswa.Col("cached_kelas_nama"), "").As("kelas_nama"),
+			goqu.COALESCE(tableSiswa.Col("nama_wali"), "").As("nama_wali"),
+			goqu.COALESCE(tableSiswa.Col("no_hp_wali"), "").As("no_hp_wali"),
+		).Where(where...).
 		Order(tablePerizinan.Col("created_at").Desc())
 
 	query, _, err := dataset.ToSQL()
@@ -164,10 +230,13 @@ func (a *sekolahAdapter) GetPerizinan(tenantID string) ([]model.Perizinan, error
 	var list []model.Perizinan
 	for rows.Next() {
 		var m model.Perizinan
-		var penyetujuID sql.NullString
+		var penyetujuID, pelanggaranID sql.NullString
+		var disetujuiAt, keluarAt, kembaliAt, dikabariAt sql.NullTime
 		if err := rows.Scan(
 			&m.ID, &m.TenantID, &m.SantriID, &m.SantriNama, &m.Tipe, &m.Alasan, &m.Dari, &m.Sampai, &m.Status,
 			&penyetujuID, &m.PenyetujuNama, &m.CreatedAt, &m.UpdatedAt,
+			&m.CatatanPenolakan, &disetujuiAt, &m.KodeGatePass, &keluarAt, &kembaliAt, &dikabariAt, &pelanggaranID,
+			&m.KelasNama, &m.NamaWali, &m.NoHPWali,
 		); err != nil {
 			return nil, err
 		}
@@ -175,9 +244,25 @@ func (a *sekolahAdapter) GetPerizinan(tenantID string) ([]model.Perizinan, error
 			id := penyetujuID.String
 			m.PenyetujuID = &id
 		}
+		if pelanggaranID.Valid {
+			id := pelanggaranID.String
+			m.PelanggaranID = &id
+		}
+		if disetujuiAt.Valid {
+			m.DisetujuiAt = &disetujuiAt.Time
+		}
+		if keluarAt.Valid {
+			m.KeluarAt = &keluarAt.Time
+		}
+		if kembaliAt.Valid {
+			m.KembaliAt = &kembaliAt.Time
+		}
+		if dikabariAt.Valid {
+			m.TerlambatDikabariAt = &dikabariAt.Time
+		}
 		list = append(list, m)
 	}
-	return list, nil
+	return list, rows.Err()
 }
 
 func (a *sekolahAdapter) CreatePerizinan(m *model.Perizinan) error {
@@ -199,3 +284,35 @@ func (a *sekolahAdapter) CreatePerizinan(m *model.Perizinan) error {
 	}
 	return a.db.QueryRow(query).Scan(&m.ID, &m.CreatedAt, &m.UpdatedAt)
 }
+
+// UpdatePerizinan saves the workflow fields of a leave, only while it is still in statusLama
+func (a *sekolahAdapter) UpdatePerizinan(m *model.Perizinan, statusLama string) (bool, error) {
+	var kode interface{}
+	if m.KodeGatePass != "" {
+		kode = m.KodeGatePass
+	}
+	query, _, err := goqu.Dialect("postgres").Update(tablePerizinan).Set(goqu.Record{
+		"status":                m.Status,
+		"penyetuju_id":          m.PenyetujuID,
+		"catatan_penolakan":     m.CatatanPenolakan,
+		"disetujui_at":          m.DisetujuiAt,
+		"kode_gate_pass":        kode,
+		"keluar_at":             m.KeluarAt,
+		"kembali_at":            m.KembaliAt,
+		"terlambat_dikabari_at": m.TerlambatDikabariAt,
+		"pelanggaran_id":        m.PelanggaranID,
+	}).Where(
+		tablePerizinan.Col("tenant_id").Eq(m.TenantID),
+		tablePerizinan.Col("id").Eq(m.ID),
+		tablePerizinan.Col("status").Eq(statusLama),
+	).ToSQL()
+	if err != nil {
+		return false, err
+	}
+	res, err := a.db.Exec(query)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
@@ -0,0 +1,81 @@
+package postgres_outbound_adapter
+
+import (
+	"context"
+	"time"
+
+	"prabogo/internal/model"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// ==========================================
+// KKM HISTORY
+// ==========================================
+
+func (a *eraporAdapter) GetKKMHistory(ctx context.Context, tenantID, subjectID string) ([]model.KKMHistory, error) {
+	history := []model.KKMHistory{}
+	err := a.db.From("kkm_history").
+		Select("id", "tenant_id", "subject_id", "semester_id", "kkm_value", "created_at",
+			goqu.COALESCE(goqu.C("updated_at"), goqu.C("created_at")).As("updated_at")).
+		Where(
+			goqu.C("tenant_id").Eq(tenantID),
+			goqu.C("subject_id").Eq(subjectID),
+		).
+		Order(goqu.C("semester_id").Asc()).
+		ScanStructsContext(ctx, &history)
+	return history, err
+}
+
+func (a *eraporAdapter) GetSemesterKKM(ctx context.Context, tenantID, semesterID string) (map[string]int, error) {
+	var rows []struct {
+		SubjectID string `db:"subject_id"`
+		KKMValue  int    `db:"kkm_value"`
+	}
+	err := a.db.From("kkm_history").
+		Select("subject_id", "kkm_value").
+		Where(
+			goqu.C("tenant_id").Eq(tenantID),
+			goqu.C("semester_id").Eq(semesterID),
+		).
+		ScanStructsContext(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	kkm := make(map[string]int, len(rows))
+	for _, r := range rows {
+		kkm[r.SubjectID] = r.KKMValue
+	}
+	return kkm, nil
+}
+
+func (a *eraporAdapter) SaveKKMHistory(ctx context.Context, h *model.KKMHistory) error {
+	now := time.Now()
+	h.UpdatedAt = now
+	_, err := a.db.Insert("kkm_history").Rows(
+		goqu.Record{
+			"tenant_id":   h.TenantID,
+			"subject_id":  h.SubjectID,
+			"semester_id": h.SemesterID,
+			"kkm_value":   h.KKMValue,
+			"created_at":  now,
+			"updated_at":  now,
+		},
+	).OnConflict(goqu.DoUpdate("subject_id, semester_id", goqu.Record{
+		"kkm_value":  goqu.L("EXCLUDED.kkm_value"),
+		"updated_at": now,
+	})).Returning("id", "created_at", "updated_at").Executor().ScanStructContext(ctx, h)
+	return err
+}
+
+func (a *eraporAdapter) GetGradedSemesters(ctx context.Context, subjectID string) ([]string, error) {
+	semesters := []string{}
+	err := a.db.From("student_grades").
+		Select("semester_id").
+		Distinct().
+		Where(goqu.C("subject_id").Eq(subjectID)).
+		Order(goqu.C("semester_id").Asc()).
+		ScanValsContext(ctx, &semesters)
+	return semesters, err
+}
@@ -0,0 +1,335 @@
+package postgres_outbound_adapter
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"prabogo/internal/model"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/google/uuid"
+)
+
+type konselingAdapter struct {
+	db goquQuerier
+}
+
+func NewKonselingAdapter(sqlDB *sql.DB) *konselingAdapter {
+	return &konselingAdapter{db: goqu.New("postgres", sqlDB)}
+}
+
+// NewKonselingTxAdapter binds the adapter to an open transaction
+func NewKonselingTxAdapter(tx *sql.Tx) *konselingAdapter {
+	return &konselingAdapter{db: goqu.NewTx("postgres", tx)}
+}
+
+// ==========================================
+// KASUS
+// ==========================================
+
+func (a *konselingAdapter) kasusDataset() *goqu.SelectDataset {
+	return a.db.From(goqu.T("sekolah_konseling_kasus").As("k")).
+		Join(goqu.T("sekolah_siswa").As("s"), goqu.On(goqu.I("s.id").Eq(goqu.I("k.santri_id")))).
+		LeftJoin(goqu.T("sekolah_kelas").As("kl"), goqu.On(goqu.I("kl.id").Eq(goqu.I("s.kelas_id")))).
+		Select(
+			goqu.I("k.id"),
+			goqu.I("k.tenant_id"),
+			goqu.I("k.santri_id"),
+			goqu.I("s.nama").As("santri_nama"),
+			goqu.COALESCE(goqu.I("kl.nama"), "").As("kelas_nama"),
+			goqu.I("k.kategori"),
+			goqu.I("k.judul"),
+			goqu.COALESCE(goqu.I("k.catatan"), "").As("catatan"),
+			goqu.I("k.status"),
+			goqu.I("k.rujuk_orang_tua"),
+			goqu.I("k.tanggal_rujukan"),
+			goqu.COALESCE(goqu.L("k.konselor_id::text"), "").As("konselor_id"),
+			goqu.I("k.created_at"),
+			goqu.I("k.updated_at"),
+		)
+}
+
+func (a *konselingAdapter) CreateKasus(ctx context.Context, kasus *model.KonselingKasus) error {
+	now := time.Now()
+	kasus.ID = uuid.New().String()
+	kasus.CreatedAt = now
+	kasus.UpdatedAt = now
+
+	var konselorID interface{}
+	if kasus.KonselorID != "" {
+		konselorID = kasus.KonselorID
+	}
+
+	_, err := a.db.Insert("sekolah_konseling_kasus").Rows(
+		goqu.Record{
+			"id":              kasus.ID,
+			"tenant_id":       kasus.TenantID,
+			"santri_id":       kasus.SantriID,
+			"kategori":        kasus.Kategori,
+			"judul":           kasus.Judul,
+			"catatan":         kasus.Catatan,
+			"status":          kasus.Status,
+			"rujuk_orang_tua": kasus.RujukOrangTua,
+			"tanggal_rujukan": kasus.TanggalRujukan,
+			"konselor_id":     konselorID,
+			"created_at":      now,
+			"updated_at":      now,
+		},
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *konselingAdapter) UpdateKasus(ctx context.Context, kasus *model.KonselingKasus) error {
+	_, err := a.db.Update("sekolah_konseling_kasus").Set(
+		goqu.Record{
+			"kategori":        kasus.Kategori,
+			"judul":           kasus.Judul,
+			"catatan":         kasus.Catatan,
+			"status":          kasus.Status,
+			"rujuk_orang_tua": kasus.RujukOrangTua,
+			"tanggal_rujukan": kasus.TanggalRujukan,
+			"updated_at":      time.Now(),
+		},
+	).Where(
+		goqu.C("id").Eq(kasus.ID),
+		goqu.C("tenant_id").Eq(kasus.TenantID),
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *konselingAdapter) GetKasusByID(ctx context.Context, tenantID, id string) (*model.KonselingKasus, error) {
+	var kasus model.KonselingKasus
+	found, err := a.kasusDataset().
+		Where(goqu.I("k.id").Eq(id), goqu.I("k.tenant_id").Eq(tenantID)).
+		ScanStructContext(ctx, &kasus)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return &kasus, nil
+}
+
+func (a *konselingAdapter) GetKasusList(ctx context.Context, tenantID, santriID string) ([]model.KonselingKasus, error) {
+	ds := a.kasusDataset().Where(goqu.I("k.tenant_id").Eq(tenantID))
+	if santriID != "" {
+		ds = ds.Where(goqu.I("k.santri_id").Eq(santriID))
+	}
+
+	var list []model.KonselingKasus
+	if err := ds.Order(goqu.I("k.created_at").Desc()).ScanStructsContext(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (a *konselingAdapter) SantriExists(ctx context.Context, tenantID, santriID string) (bool, error) {
+	var id string
+	return a.db.From(tableSiswa).
+		Select(goqu.L("id::text")).
+		Where(goqu.C("id").Eq(santriID), goqu.C("tenant_id").Eq(tenantID)).
+		ScanValContext(ctx, &id)
+}
+
+// ==========================================
+// SESI
+// ==========================================
+
+func (a *konselingAdapter) CreateSesi(ctx context.Context, sesi *model.KonselingSesi) error {
+	sesi.ID = uuid.New().String()
+	sesi.CreatedAt = time.Now()
+
+	var konselorID interface{}
+	if sesi.KonselorID != "" {
+		konselorID = sesi.KonselorID
+	}
+
+	_, err := a.db.Insert("sekolah_konseling_sesi").Rows(
+		goqu.Record{
+			"id":                    sesi.ID,
+			"kasus_id":              sesi.KasusID,
+			"tanggal":               sesi.Tanggal,
+			"catatan":               sesi.Catatan,
+			"tindak_lanjut":         sesi.TindakLanjut,
+			"tanggal_tindak_lanjut": sesi.TanggalTindakLanjut,
+			"tindak_lanjut_selesai": false,
+			"konselor_id":           konselorID,
+			"created_at":            sesi.CreatedAt,
+		},
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *konselingAdapter) GetSesiByKasus(ctx context.Context, kasusID string) ([]model.KonselingSesi, error) {
+	var list []model.KonselingSesi
+	err := a.db.From("sekolah_konseling_sesi").
+		Select(
+			"id",
+			"kasus_id",
+			"tanggal",
+			goqu.COALESCE(goqu.C("catatan"), "").As("catatan"),
+			goqu.COALESCE(goqu.C("tindak_lanjut"), "").As("tindak_lanjut"),
+			"tanggal_tindak_lanjut",
+			"tindak_lanjut_selesai",
+			goqu.COALESCE(goqu.L("konselor_id::text"), "").As("konselor_id"),
+			"created_at",
+		).
+		Where(goqu.C("kasus_id").Eq(kasusID)).
+		Order(goqu.C("tanggal").Asc()).
+		ScanStructsContext(ctx, &list)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (a *konselingAdapter) SetTindakLanjutSelesai(ctx context.Context, kasusID, sesiID string, selesai bool) error {
+	_, err := a.db.Update("sekolah_konseling_sesi").
+		Set(goqu.Record{"tindak_lanjut_selesai": selesai}).
+		Where(goqu.C("id").Eq(sesiID), goqu.C("kasus_id").Eq(kasusID)).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+// ==========================================
+// PELANGGARAN LINKS
+// ==========================================
+
+func (a *konselingAdapter) LinkPelanggaran(ctx context.Context, kasusID string, pelanggaranIDs []string) error {
+	if len(pelanggaranIDs) == 0 {
+		return nil
+	}
+	rows := make([]interface{}, 0, len(pelanggaranIDs))
+	for _, id := range pelanggaranIDs {
+		rows = append(rows, goqu.Record{"kasus_id": kasusID, "pelanggaran_id": id})
+	}
+	_, err := a.db.Insert("sekolah_konseling_pelanggaran").
+		Rows(rows...).
+		OnConflict(goqu.DoNothing()).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *konselingAdapter) GetPelanggaranIDsBySantri(ctx context.Context, tenantID, santriID string, ids []string) ([]string, error) {
+	found := []string{}
+	if len(ids) == 0 {
+		return found, nil
+	}
+	err := a.db.From("sekolah_pelanggaran_siswa").
+		Select(goqu.L("id::text")).
+		Where(
+			goqu.C("tenant_id").Eq(tenantID),
+			goqu.C("santri_id").Eq(santriID),
+			goqu.C("id").In(ids),
+		).
+		ScanValsContext(ctx, &found)
+	if err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+func (a *konselingAdapter) GetPelanggaranByKasus(ctx context.Context, tenantID, kasusID string) ([]model.PelanggaranSiswa, error) {
+	var rows []struct {
+		ID          string    `db:"id"`
+		TenantID    string    `db:"tenant_id"`
+		SantriID    string    `db:"santri_id"`
+		AturanJudul string    `db:"aturan_judul"`
+		Tanggal     time.Time `db:"tanggal"`
+		Poin        int       `db:"poin"`
+		Keterangan  string    `db:"keterangan"`
+		Status      string    `db:"status"`
+		Sanksi      string    `db:"sanksi"`
+	}
+	err := a.db.From(goqu.T("sekolah_konseling_pelanggaran").As("kp")).
+		Join(goqu.T("sekolah_pelanggaran_siswa").As("p"), goqu.On(goqu.I("p.id").Eq(goqu.I("kp.pelanggaran_id")))).
+		LeftJoin(goqu.T("sekolah_pelanggaran_aturan").As("r"), goqu.On(goqu.I("r.id").Eq(goqu.I("p.aturan_id")))).
+		Select(
+			goqu.I("p.id"),
+			goqu.I("p.tenant_id"),
+			goqu.I("p.santri_id"),
+			goqu.COALESCE(goqu.I("r.judul"), "").As("aturan_judul"),
+			goqu.I("p.tanggal"),
+			goqu.I("p.poin"),
+			goqu.COALESCE(goqu.I("p.keterangan"), "").As("keterangan"),
+			goqu.I("p.status"),
+			goqu.COALESCE(goqu.I("p.sanksi"), "").As("sanksi"),
+		).
+		Where(
+			goqu.I("kp.kasus_id").Eq(kasusID),
+			goqu.I("p.tenant_id").Eq(tenantID),
+		).
+		Order(goqu.I("p.tanggal").Desc()).
+		ScanStructsContext(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]model.PelanggaranSiswa, 0, len(rows))
+	for _, r := range rows {
+		list = append(list, model.PelanggaranSiswa{
+			ID:          r.ID,
+			TenantID:    r.TenantID,
+			SantriID:    r.SantriID,
+			AturanJudul: r.AturanJudul,
+			Tanggal:     r.Tanggal,
+			Poin:        r.Poin,
+			Keterangan:  r.Keterangan,
+			Status:      r.Status,
+			Sanksi:      r.Sanksi,
+		})
+	}
+	return list, nil
+}
+
+// ==========================================
+// RINGKASAN
+// ==========================================
+
+func (a *konselingAdapter) GetRingkasanByKelas(ctx context.Context, tenantID, kelasID string) ([]model.KonselingRingkasan, error) {
+	var list []model.KonselingRingkasan
+	err := a.db.From(goqu.T("sekolah_konseling_kasus").As("k")).
+		Join(goqu.T("sekolah_siswa").As("s"), goqu.On(goqu.I("s.id").Eq(goqu.I("k.santri_id")))).
+		Select(
+			goqu.I("k.id").As("kasus_id"),
+			goqu.I("k.santri_id"),
+			goqu.I("s.nama").As("santri_nama"),
+			goqu.I("k.kategori"),
+			goqu.I("k.status"),
+			goqu.I("k.rujuk_orang_tua"),
+			goqu.L(`(SELECT COUNT(*) FROM sekolah_konseling_sesi ss WHERE ss.kasus_id = k.id)`).As("jumlah_sesi"),
+			goqu.L(`(SELECT MAX(ss.tanggal) FROM sekolah_konseling_sesi ss WHERE ss.kasus_id = k.id)`).As("sesi_terakhir"),
+			goqu.I("k.created_at"),
+		).
+		Where(
+			goqu.I("k.tenant_id").Eq(tenantID),
+			goqu.I("s.kelas_id").Eq(kelasID),
+		).
+		Order(goqu.I("k.created_at").Desc()).
+		ScanStructsContext(ctx, &list)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// IsWaliKelas links the user to a guru by WhatsApp number, the same way
+// parent accounts are matched to santri
+func (a *konselingAdapter) IsWaliKelas(ctx context.Context, tenantID, kelasID, userID string) (bool, error) {
+	var id string
+	return a.db.From(goqu.T("sekolah_kelas").As("k")).
+		Join(goqu.T("sekolah_guru").As("g"), goqu.On(goqu.I("g.id").Eq(goqu.I("k.wali_kelas_id")))).
+		Join(goqu.T("users").As("u"), goqu.On(goqu.I("u.id").Eq(userID))).
+		Select(goqu.L("k.id::text")).
+		Where(
+			goqu.I("k.id").Eq(kelasID),
+			goqu.I("k.tenant_id").Eq(tenantID),
+			goqu.I("g.tenant_id").Eq(tenantID),
+			goqu.I("u.tenant_id").Eq(tenantID),
+			goqu.COALESCE(goqu.I("u.whatsapp"), "").Neq(""),
+			normalizedPhone(goqu.I("u.whatsapp")).Eq(normalizedPhone(goqu.I("g.no_hp"))),
+		).
+		ScanValContext(ctx, &id)
+}
@@ -0,0 +1,262 @@
+package postgres_outbound_adapter
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"prabogo/internal/model"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/google/uuid"
+)
+
+// ==========================================
+// CURRICULUM PROFILES
+// ==========================================
+
+type curriculumProfileRow struct {
+	ID            string    `db:"id"`
+	TenantID      string    `db:"tenant_id"`
+	Code          string    `db:"code"`
+	Name          string    `db:"name"`
+	Description   string    `db:"description"`
+	Mode          string    `db:"mode"`
+	GradingConfig []byte    `db:"grading_config"`
+	RaporTemplate string    `db:"rapor_template"`
+	IsActive      bool      `db:"is_active"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
+
+func (r curriculumProfileRow) toModel() (model.CurriculumProfile, error) {
+	p := model.CurriculumProfile{
+		ID:            r.ID,
+		TenantID:      r.TenantID,
+		Code:          r.Code,
+		Name:          r.Name,
+		Description:   r.Description,
+		Mode:          r.Mode,
+		RaporTemplate: r.RaporTemplate,
+		IsActive:      r.IsActive,
+		IsSystem:      r.TenantID == "",
+		CreatedAt:     r.CreatedAt,
+		UpdatedAt:     r.UpdatedAt,
+	}
+	if len(r.GradingConfig) > 0 {
+		if err := json.Unmarshal(r.GradingConfig, &p.GradingConfig); err != nil {
+			return p, err
+		}
+	}
+	return p, nil
+}
+
+// curriculumProfileQuery selects the system profiles and those of the tenant
+func (a *eraporAdapter) curriculumProfileQuery(tenantID string) *goqu.SelectDataset {
+	return a.db.From("curriculum_references").
+		Select(
+			goqu.L("id::text").As("id"),
+			goqu.COALESCE(goqu.L("tenant_id::text"), "").As("tenant_id"),
+			"code",
+			"name",
+			goqu.COALESCE(goqu.C("description"), "").As("description"),
+			"mode",
+			"grading_config",
+			"rapor_template",
+			goqu.COALESCE(goqu.C("is_active"), true).As("is_active"),
+			"created_at",
+			"updated_at",
+		).
+		Where(goqu.Or(
+			goqu.C("tenant_id").IsNull(),
+			goqu.C("tenant_id").Eq(tenantID),
+		))
+}
+
+func (a *eraporAdapter) GetCurriculumProfiles(ctx context.Context, tenantID string) ([]model.CurriculumProfile, error) {
+	var rows []curriculumProfileRow
+	err := a.curriculumProfileQuery(tenantID).
+		Order(goqu.L("tenant_id IS NOT NULL").Asc(), goqu.C("name").Asc()).
+		ScanStructsContext(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := make([]model.CurriculumProfile, len(rows))
+	for i, r := range rows {
+		if profiles[i], err = r.toModel(); err != nil {
+			return nil, err
+		}
+	}
+	return profiles, nil
+}
+
+func (a *eraporAdapter) GetCurriculumProfile(ctx context.Context, tenantID, code string) (*model.CurriculumProfile, error) {
+	var row curriculumProfileRow
+	found, err := a.curriculumProfileQuery(tenantID).
+		Where(goqu.C("code").Eq(code)).
+		Order(goqu.L("tenant_id IS NULL").Asc()).
+		Limit(1).
+		ScanStructContext(ctx, &row)
+	if err != nil || !found {
+		return nil, err
+	}
+	p, err := row.toModel()
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (a *eraporAdapter) GetCurriculumProfileByID(ctx context.Context, tenantID, id string) (*model.CurriculumProfile, error) {
+	var row curriculumProfileRow
+	found, err := a.curriculumProfileQuery(tenantID).
+		Where(goqu.C("id").Eq(id)).
+		ScanStructContext(ctx, &row)
+	if err != nil || !found {
+		return nil, err
+	}
+	p, err := row.toModel()
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// SaveCurriculumProfile inserts or updates a tenant profile; system profiles are never written
+func (a *eraporAdapter) SaveCurriculumProfile(ctx context.Context, p *model.CurriculumProfile) error {
+	config, err := json.Marshal(p.GradingConfig)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	p.UpdatedAt = now
+	if p.ID == "" {
+		p.ID = uuid.New().String()
+		p.CreatedAt = now
+		_, err := a.db.Insert("curriculum_references").Rows(
+			goqu.Record{
+				"id":             p.ID,
+				"tenant_id":      p.TenantID,
+				"code":           p.Code,
+				"name":           p.Name,
+				"description":    p.Description,
+				"mode":           p.Mode,
+				"grading_config": config,
+				"rapor_template": p.RaporTemplate,
+				"is_active":      p.IsActive,
+				"created_at":     now,
+				"updated_at":     now,
+			},
+		).Executor().ExecContext(ctx)
+		return err
+	}
+
+	_, err = a.db.Update("curriculum_references").Set(
+		goqu.Record{
+			"name":           p.Name,
+			"description":    p.Description,
+			"mode":           p.Mode,
+			"grading_config": config,
+			"rapor_template": p.RaporTemplate,
+			"is_active":      p.IsActive,
+			"updated_at":     now,
+		},
+	).Where(
+		goqu.C("id").Eq(p.ID),
+		goqu.C("tenant_id").Eq(p.TenantID),
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *eraporAdapter) DeleteCurriculumProfile(ctx context.Context, tenantID, id string) error {
+	_, err := a.db.Delete("curriculum_references").
+		Where(
+			goqu.C("id").Eq(id),
+			goqu.C("tenant_id").Eq(tenantID),
+		).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *eraporAdapter) GetTenantCurricula(ctx context.Context, tenantID string) ([]model.TenantCurriculum, error) {
+	var rows []struct {
+		Jenjang        string    `db:"jenjang"`
+		CurriculumCode string    `db:"curriculum_code"`
+		UpdatedAt      time.Time `db:"updated_at"`
+		CurriculumName string    `db:"curriculum_name"`
+		RaporTemplate  string    `db:"rapor_template"`
+	}
+	// A tenant profile shadows a system profile with the same code
+	err := a.db.From(goqu.T("tenant_curricula").As("tc")).
+		Select(
+			goqu.I("tc.jenjang"),
+			goqu.I("tc.curriculum_code"),
+			goqu.I("tc.updated_at"),
+			goqu.COALESCE(goqu.L(`(SELECT c.name FROM curriculum_references c
+				WHERE c.code = tc.curriculum_code AND (c.tenant_id IS NULL OR c.tenant_id = tc.tenant_id)
+				ORDER BY c.tenant_id IS NULL LIMIT 1)`), "").As("curriculum_name"),
+			goqu.COALESCE(goqu.L(`(SELECT c.rapor_template FROM curriculum_references c
+				WHERE c.code = tc.curriculum_code AND (c.tenant_id IS NULL OR c.tenant_id = tc.tenant_id)
+				ORDER BY c.tenant_id IS NULL LIMIT 1)`), "").As("rapor_template"),
+		).
+		Where(goqu.I("tc.tenant_id").Eq(tenantID)).
+		Order(goqu.I("tc.jenjang").Asc()).
+		ScanStructsContext(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.TenantCurriculum, len(rows))
+	for i, r := range rows {
+		result[i] = model.TenantCurriculum{
+			TenantID:       tenantID,
+			Jenjang:        r.Jenjang,
+			CurriculumCode: r.CurriculumCode,
+			UpdatedAt:      r.UpdatedAt,
+			CurriculumName: r.CurriculumName,
+			RaporTemplate:  r.RaporTemplate,
+		}
+	}
+	return result, nil
+}
+
+func (a *eraporAdapter) SaveTenantCurriculum(ctx context.Context, tc *model.TenantCurriculum) error {
+	tc.UpdatedAt = time.Now()
+	_, err := a.db.Insert("tenant_curricula").Rows(
+		goqu.Record{
+			"tenant_id":       tc.TenantID,
+			"jenjang":         tc.Jenjang,
+			"curriculum_code": tc.CurriculumCode,
+			"updated_at":      tc.UpdatedAt,
+		},
+	).OnConflict(goqu.DoUpdate("tenant_id, jenjang", goqu.Record{
+		"curriculum_code": goqu.L("EXCLUDED.curriculum_code"),
+		"updated_at":      tc.UpdatedAt,
+	})).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *eraporAdapter) DeleteTenantCurriculum(ctx context.Context, tenantID, jenjang string) error {
+	_, err := a.db.Delete("tenant_curricula").
+		Where(
+			goqu.C("tenant_id").Eq(tenantID),
+			goqu.C("jenjang").Eq(jenjang),
+		).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *eraporAdapter) GetTenantJenjangs(ctx context.Context, tenantID string) ([]string, error) {
+	var joined string
+	_, err := a.db.From("tenants").
+		Select(goqu.COALESCE(goqu.L("array_to_string(school_jenjangs, ',')"), "")).
+		Where(goqu.C("id").Eq(tenantID)).
+		ScanValContext(ctx, &joined)
+	if err != nil || joined == "" {
+		return []string{}, err
+	}
+	return strings.Split(joined, ","), nil
+}
@@ -0,0 +1,143 @@
+package postgres_outbound_adapter
+
+import (
+	"context"
+	"encoding/json"
+
+	"prabogo/internal/model"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// ==========================================
+// LEGER NILAI
+// ==========================================
+
+func (a *eraporAdapter) GetLegerKelas(ctx context.Context, tenantID, kelasID string) (*model.Leger, error) {
+	var kelas struct {
+		Nama        string `db:"nama"`
+		WaliKelas   string `db:"wali_kelas"`
+		NamaSekolah string `db:"nama_sekolah"`
+	}
+	found, err := a.db.From(goqu.T("sekolah_kelas").As("k")).
+		Join(goqu.T("tenants").As("t"), goqu.On(goqu.I("t.id").Eq(goqu.I("k.tenant_id")))).
+		LeftJoin(goqu.T("sekolah_guru").As("g"), goqu.On(goqu.I("g.id").Eq(goqu.I("k.wali_kelas_id")))).
+		Select(
+			goqu.I("k.nama"),
+			goqu.COALESCE(goqu.I("g.nama"), "").As("wali_kelas"),
+			goqu.I("t.name").As("nama_sekolah"),
+		).
+		Where(
+			goqu.I("k.id").Eq(kelasID),
+			goqu.I("k.tenant_id").Eq(tenantID),
+		).
+		ScanStructContext(ctx, &kelas)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	var siswa []struct {
+		ID   string `db:"id"`
+		Nama string `db:"nama"`
+		NIS  string `db:"nis"`
+	}
+	err = a.db.From("sekolah_siswa").
+		Select("id", "nama", goqu.COALESCE(goqu.C("nis"), "").As("nis")).
+		Where(
+			goqu.C("tenant_id").Eq(tenantID),
+			goqu.C("kelas_id").Eq(kelasID),
+			goqu.C("status").Eq("Aktif"),
+		).
+		Order(goqu.C("nama").Asc()).
+		ScanStructsContext(ctx, &siswa)
+	if err != nil {
+		return nil, err
+	}
+
+	leger := &model.Leger{
+		KelasID:     kelasID,
+		NamaKelas:   kelas.Nama,
+		WaliKelas:   kelas.WaliKelas,
+		NamaSekolah: kelas.NamaSekolah,
+		Rows:        make([]model.LegerRow, len(siswa)),
+	}
+	for i, s := range siswa {
+		leger.Rows[i] = model.LegerRow{StudentID: s.ID, Nama: s.Nama, NIS: s.NIS, Scores: map[string]float64{}}
+	}
+	return leger, nil
+}
+
+func (a *eraporAdapter) GetGradesByKelas(ctx context.Context, tenantID, kelasID, semesterID string) ([]model.StudentGrade, error) {
+	var rows []struct {
+		StudentID      string  `db:"student_id"`
+		SubjectID      string  `db:"subject_id"`
+		ScoreNumeric   float64 `db:"score_numeric"`
+		ScorePredicate string  `db:"score_predicate"`
+	}
+	err := a.db.From(goqu.T("student_grades").As("g")).
+		Join(goqu.T("sekolah_siswa").As("s"), goqu.On(goqu.I("s.id").Eq(goqu.I("g.student_id")))).
+		Select(
+			goqu.I("g.student_id"),
+			goqu.I("g.subject_id"),
+			goqu.COALESCE(goqu.I("g.score_numeric"), 0).As("score_numeric"),
+			goqu.COALESCE(goqu.I("g.score_predicate"), "").As("score_predicate"),
+		).
+		Where(
+			goqu.I("g.tenant_id").Eq(tenantID),
+			goqu.I("g.semester_id").Eq(semesterID),
+			goqu.I("s.kelas_id").Eq(kelasID),
+		).
+		ScanStructsContext(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	grades := make([]model.StudentGrade, len(rows))
+	for i, r := range rows {
+		grades[i] = model.StudentGrade{
+			TenantID:       tenantID,
+			StudentID:      r.StudentID,
+			SubjectID:      r.SubjectID,
+			SemesterID:     semesterID,
+			ScoreNumeric:   r.ScoreNumeric,
+			ScorePredicate: r.ScorePredicate,
+		}
+	}
+	return grades, nil
+}
+
+func (a *eraporAdapter) GetRaporKehadiran(ctx context.Context, tenantID, semesterID string, siswaIDs []string) (map[string]model.AttendanceData, error) {
+	result := make(map[string]model.AttendanceData, len(siswaIDs))
+	if len(siswaIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		SiswaID string `db:"santri_id"`
+		Header  []byte `db:"header"`
+	}
+	err := a.db.From(goqu.T("sekolah_rapor").As("r")).
+		Join(goqu.T("sekolah_rapor_periode").As("p"), goqu.On(goqu.I("p.id").Eq(goqu.I("r.periode_id")))).
+		Select(
+			goqu.L("r.santri_id::text").As("santri_id"),
+			goqu.I("r.header"),
+		).
+		Where(
+			goqu.I("r.tenant_id").Eq(tenantID),
+			goqu.I("p.nama").Eq(semesterID),
+			goqu.I("r.santri_id").In(siswaIDs),
+			goqu.I("r.header").IsNotNull(),
+		).
+		ScanStructsContext(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		var header model.RaporHeader
+		if err := json.Unmarshal(r.Header, &header); err != nil {
+			return nil, err
+		}
+		result[r.SiswaID] = header.Kehadiran
+	}
+	return result, nil
+}
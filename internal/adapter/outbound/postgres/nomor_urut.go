@@ -0,0 +1,40 @@
+package postgres_outbound_adapter
+
+import (
+	"context"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+// Jenis values of sekolah_nomor_urut
+const (
+	nomorUrutPPDB   = "ppdb"
+	nomorUrutSanksi = "sanksi"
+)
+
+// nomorUrutInsert builds the upsert that hands out the next running number for a tenant,
+// kind and period. The counter row is created on first use from seed (the number of rows
+// already numbered before the counter existed); the upsert serialises concurrent callers.
+func nomorUrutInsert(ins *goqu.InsertDataset, tenantID, jenis, periode string, seed exp.AppendableExpression) *goqu.InsertDataset {
+	return ins.Rows(
+		goqu.Record{
+			"tenant_id":  tenantID,
+			"jenis":      jenis,
+			"periode":    periode,
+			"nilai":      goqu.L("(?) + 1", seed),
+			"updated_at": time.Now(),
+		},
+	).OnConflict(goqu.DoUpdate("tenant_id, jenis, periode", goqu.Record{
+		"nilai":      goqu.L("sekolah_nomor_urut.nilai + 1"),
+		"updated_at": goqu.L("EXCLUDED.updated_at"),
+	})).Returning("nilai")
+}
+
+func nextNomorUrut(ctx context.Context, db goquQuerier, tenantID, jenis, periode string, seed exp.AppendableExpression) (int, error) {
+	var nilai int
+	_, err := nomorUrutInsert(db.Insert("sekolah_nomor_urut"), tenantID, jenis, periode, seed).
+		Executor().ScanValContext(ctx, &nilai)
+	return nilai, err
+}
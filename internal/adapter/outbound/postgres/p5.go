@@ -0,0 +1,220 @@
+package postgres_outbound_adapter
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"prabogo/internal/model"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/google/uuid"
+)
+
+// ==========================================
+// P5 (PROJEK PENGUATAN PROFIL PELAJAR PANCASILA)
+// ==========================================
+
+type p5ProjekRow struct {
+	model.P5Projek
+	TargetsJSON []byte `db:"targets"`
+}
+
+func (a *eraporAdapter) p5ProjekQuery(tenantID string) *goqu.SelectDataset {
+	return a.db.From(goqu.T("p5_projek").As("p")).
+		LeftJoin(goqu.T("sekolah_kelas").As("k"), goqu.On(goqu.I("k.id").Eq(goqu.I("p.kelas_id")))).
+		LeftJoin(goqu.T("sekolah_guru").As("g"), goqu.On(goqu.I("g.id").Eq(goqu.I("p.fasilitator_id")))).
+		Select(
+			goqu.I("p.id"),
+			goqu.I("p.tenant_id"),
+			goqu.I("p.semester_id"),
+			goqu.I("p.kelas_id"),
+			goqu.I("p.tema"),
+			goqu.I("p.judul"),
+			goqu.I("p.deskripsi"),
+			goqu.COALESCE(goqu.L("p.fasilitator_id::text"), "").As("fasilitator_id"),
+			goqu.I("p.targets"),
+			goqu.I("p.created_at"),
+			goqu.I("p.updated_at"),
+			goqu.COALESCE(goqu.I("k.nama"), "").As("nama_kelas"),
+			goqu.COALESCE(goqu.I("g.nama"), "").As("nama_fasilitator"),
+		).
+		Where(goqu.I("p.tenant_id").Eq(tenantID))
+}
+
+func (r p5ProjekRow) decode() (model.P5Projek, error) {
+	p := r.P5Projek
+	p.Targets = []model.P5Target{}
+	if len(r.TargetsJSON) > 0 {
+		if err := json.Unmarshal(r.TargetsJSON, &p.Targets); err != nil {
+			return p, err
+		}
+	}
+	return p, nil
+}
+
+func (a *eraporAdapter) GetP5ProjekList(ctx context.Context, tenantID, semesterID, kelasID string) ([]model.P5Projek, error) {
+	ds := a.p5ProjekQuery(tenantID).Where(goqu.I("p.semester_id").Eq(semesterID))
+	if kelasID != "" {
+		ds = ds.Where(goqu.I("p.kelas_id").Eq(kelasID))
+	}
+
+	var rows []p5ProjekRow
+	if err := ds.Order(goqu.I("k.nama").Asc(), goqu.I("p.created_at").Asc()).ScanStructsContext(ctx, &rows); err != nil {
+		return nil, err
+	}
+	list := make([]model.P5Projek, len(rows))
+	for i, r := range rows {
+		p, err := r.decode()
+		if err != nil {
+			return nil, err
+		}
+		list[i] = p
+	}
+	return list, nil
+}
+
+func (a *eraporAdapter) GetP5Projek(ctx context.Context, tenantID, id string) (*model.P5Projek, error) {
+	var row p5ProjekRow
+	found, err := a.p5ProjekQuery(tenantID).
+		Where(goqu.I("p.id").Eq(id)).
+		ScanStructContext(ctx, &row)
+	if err != nil || !found {
+		return nil, err
+	}
+	p, err := row.decode()
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (a *eraporAdapter) SaveP5Projek(ctx context.Context, p *model.P5Projek) error {
+	targets, err := json.Marshal(p.Targets)
+	if err != nil {
+		return err
+	}
+	var fasilitator interface{}
+	if p.FasilitatorID != "" {
+		fasilitator = p.FasilitatorID
+	}
+
+	now := time.Now()
+	p.UpdatedAt = now
+	if p.ID == "" {
+		p.ID = uuid.New().String()
+		p.CreatedAt = now
+		_, err := a.db.Insert("p5_projek").Rows(
+			goqu.Record{
+				"id":             p.ID,
+				"tenant_id":      p.TenantID,
+				"semester_id":    p.SemesterID,
+				"kelas_id":       p.KelasID,
+				"tema":           p.Tema,
+				"judul":          p.Judul,
+				"deskripsi":      p.Deskripsi,
+				"fasilitator_id": fasilitator,
+				"targets":        targets,
+				"created_at":     now,
+				"updated_at":     now,
+			},
+		).Executor().ExecContext(ctx)
+		return err
+	}
+
+	_, err = a.db.Update("p5_projek").Set(
+		goqu.Record{
+			"tema":           p.Tema,
+			"judul":          p.Judul,
+			"deskripsi":      p.Deskripsi,
+			"fasilitator_id": fasilitator,
+			"targets":        targets,
+			"updated_at":     now,
+		},
+	).Where(
+		goqu.C("id").Eq(p.ID),
+		goqu.C("tenant_id").Eq(p.TenantID),
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *eraporAdapter) DeleteP5Projek(ctx context.Context, tenantID, id string) error {
+	_, err := a.db.Delete("p5_projek").
+		Where(
+			goqu.C("id").Eq(id),
+			goqu.C("tenant_id").Eq(tenantID),
+		).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *eraporAdapter) SaveP5Penilaian(ctx context.Context, tenantID, projekID string, list []model.P5Penilaian) error {
+	if len(list) == 0 {
+		return nil
+	}
+	now := time.Now()
+	rows := make([]interface{}, len(list))
+	for i, p := range list {
+		capaian, err := json.Marshal(p.Capaian)
+		if err != nil {
+			return err
+		}
+		rows[i] = goqu.Record{
+			"tenant_id":  tenantID,
+			"projek_id":  projekID,
+			"student_id": p.StudentID,
+			"capaian":    capaian,
+			"catatan":    p.Catatan,
+			"created_at": now,
+			"updated_at": now,
+		}
+	}
+	_, err := a.db.Insert("p5_penilaian").Rows(rows...).
+		OnConflict(goqu.DoUpdate("projek_id, student_id", goqu.Record{
+			"capaian":    goqu.L("EXCLUDED.capaian"),
+			"catatan":    goqu.L("EXCLUDED.catatan"),
+			"updated_at": now,
+		})).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *eraporAdapter) GetP5Penilaian(ctx context.Context, tenantID, projekID, studentID string) ([]model.P5Penilaian, error) {
+	ds := a.db.From(goqu.T("p5_penilaian").As("n")).
+		LeftJoin(goqu.T("sekolah_siswa").As("s"), goqu.On(goqu.I("s.id").Eq(goqu.I("n.student_id")))).
+		Select(
+			goqu.I("n.id"),
+			goqu.I("n.tenant_id"),
+			goqu.I("n.projek_id"),
+			goqu.I("n.student_id"),
+			goqu.I("n.capaian"),
+			goqu.I("n.catatan"),
+			goqu.I("n.updated_at"),
+			goqu.COALESCE(goqu.I("s.nama"), "").As("student_name"),
+		).
+		Where(goqu.I("n.tenant_id").Eq(tenantID))
+	if projekID != "" {
+		ds = ds.Where(goqu.I("n.projek_id").Eq(projekID))
+	}
+	if studentID != "" {
+		ds = ds.Where(goqu.I("n.student_id").Eq(studentID))
+	}
+
+	var rows []struct {
+		model.P5Penilaian
+		CapaianJSON []byte `db:"capaian"`
+	}
+	if err := ds.Order(goqu.I("s.nama").Asc()).ScanStructsContext(ctx, &rows); err != nil {
+		return nil, err
+	}
+	list := make([]model.P5Penilaian, len(rows))
+	for i, r := range rows {
+		list[i] = r.P5Penilaian
+		if len(r.CapaianJSON) > 0 {
+			if err := json.Unmarshal(r.CapaianJSON, &list[i].Capaian); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return list, nil
+}
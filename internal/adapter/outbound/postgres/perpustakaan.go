@@ -0,0 +1,531 @@
+package postgres_outbound_adapter
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"prabogo/internal/model"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/google/uuid"
+)
+
+type perpustakaanAdapter struct {
+	db goquQuerier
+}
+
+func NewPerpustakaanAdapter(sqlDB *sql.DB) *perpustakaanAdapter {
+	return &perpustakaanAdapter{db: goqu.New("postgres", sqlDB)}
+}
+
+// NewPerpustakaanTxAdapter binds the adapter to an open transaction
+func NewPerpustakaanTxAdapter(tx *sql.Tx) *perpustakaanAdapter {
+	return &perpustakaanAdapter{db: goqu.NewTx("postgres", tx)}
+}
+
+// ==========================================
+// KATALOG
+// ==========================================
+
+func (a *perpustakaanAdapter) bukuDataset() *goqu.SelectDataset {
+	return a.db.From(goqu.T("sekolah_buku").As("b")).
+		Select(
+			goqu.I("b.id"),
+			goqu.I("b.tenant_id"),
+			goqu.COALESCE(goqu.I("b.isbn"), "").As("isbn"),
+			goqu.I("b.judul"),
+			goqu.COALESCE(goqu.I("b.penulis"), "").As("penulis"),
+			goqu.COALESCE(goqu.I("b.penerbit"), "").As("penerbit"),
+			goqu.COALESCE(goqu.I("b.tahun_terbit"), 0).As("tahun_terbit"),
+			goqu.COALESCE(goqu.I("b.kategori"), "").As("kategori"),
+			goqu.COALESCE(goqu.I("b.rak"), "").As("rak"),
+			goqu.L(`(SELECT COUNT(*) FROM sekolah_buku_eksemplar e WHERE e.buku_id = b.id AND e.status <> ?)`, model.EksemplarHilang).As("jumlah_eksemplar"),
+			goqu.L(`(SELECT COUNT(*) FROM sekolah_buku_eksemplar e WHERE e.buku_id = b.id AND e.status = ?)`, model.EksemplarTersedia).As("tersedia"),
+			goqu.I("b.created_at"),
+			goqu.I("b.updated_at"),
+		)
+}
+
+func (a *perpustakaanAdapter) CreateBuku(ctx context.Context, buku *model.Buku) error {
+	now := time.Now()
+	buku.ID = uuid.New().String()
+	buku.CreatedAt = now
+	buku.UpdatedAt = now
+
+	_, err := a.db.Insert("sekolah_buku").Rows(
+		goqu.Record{
+			"id":           buku.ID,
+			"tenant_id":    buku.TenantID,
+			"isbn":         buku.ISBN,
+			"judul":        buku.Judul,
+			"penulis":      buku.Penulis,
+			"penerbit":     buku.Penerbit,
+			"tahun_terbit": buku.TahunTerbit,
+			"kategori":     buku.Kategori,
+			"rak":          buku.Rak,
+			"created_at":   now,
+			"updated_at":   now,
+		},
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *perpustakaanAdapter) UpdateBuku(ctx context.Context, buku *model.Buku) error {
+	_, err := a.db.Update("sekolah_buku").Set(
+		goqu.Record{
+			"isbn":         buku.ISBN,
+			"judul":        buku.Judul,
+			"penulis":      buku.Penulis,
+			"penerbit":     buku.Penerbit,
+			"tahun_terbit": buku.TahunTerbit,
+			"kategori":     buku.Kategori,
+			"rak":          buku.Rak,
+			"updated_at":   time.Now(),
+		},
+	).Where(
+		goqu.C("id").Eq(buku.ID),
+		goqu.C("tenant_id").Eq(buku.TenantID),
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *perpustakaanAdapter) GetBukuByID(ctx context.Context, tenantID, id string) (*model.Buku, error) {
+	var buku model.Buku
+	found, err := a.bukuDataset().
+		Where(goqu.I("b.id").Eq(id), goqu.I("b.tenant_id").Eq(tenantID)).
+		ScanStructContext(ctx, &buku)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return &buku, nil
+}
+
+func (a *perpustakaanAdapter) GetBukuList(ctx context.Context, tenantID, search string) ([]model.Buku, error) {
+	ds := a.bukuDataset().Where(goqu.I("b.tenant_id").Eq(tenantID))
+	if search != "" {
+		pattern := "%" + search + "%"
+		ds = ds.Where(goqu.Or(
+			goqu.I("b.judul").ILike(pattern),
+			goqu.I("b.penulis").ILike(pattern),
+			goqu.I("b.isbn").ILike(pattern),
+		))
+	}
+
+	var list []model.Buku
+	if err := ds.Order(goqu.I("b.judul").Asc()).ScanStructsContext(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (a *perpustakaanAdapter) DeleteBuku(ctx context.Context, tenantID, id string) error {
+	_, err := a.db.Delete("sekolah_buku").
+		Where(goqu.C("id").Eq(id), goqu.C("tenant_id").Eq(tenantID)).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+// ==========================================
+// EKSEMPLAR
+// ==========================================
+
+func (a *perpustakaanAdapter) eksemplarDataset() *goqu.SelectDataset {
+	return a.db.From(goqu.T("sekolah_buku_eksemplar").As("e")).
+		Join(goqu.T("sekolah_buku").As("b"), goqu.On(goqu.I("b.id").Eq(goqu.I("e.buku_id")))).
+		Select(
+			goqu.I("e.id"),
+			goqu.I("e.tenant_id"),
+			goqu.I("e.buku_id"),
+			goqu.I("b.judul").As("buku_judul"),
+			goqu.I("e.barcode"),
+			goqu.COALESCE(goqu.I("e.kondisi"), "").As("kondisi"),
+			goqu.I("e.status"),
+			goqu.I("e.created_at"),
+			goqu.I("e.updated_at"),
+		)
+}
+
+func (a *perpustakaanAdapter) CreateEksemplar(ctx context.Context, eksemplar *model.BukuEksemplar) error {
+	now := time.Now()
+	eksemplar.ID = uuid.New().String()
+	eksemplar.CreatedAt = now
+	eksemplar.UpdatedAt = now
+
+	_, err := a.db.Insert("sekolah_buku_eksemplar").Rows(
+		goqu.Record{
+			"id":         eksemplar.ID,
+			"tenant_id":  eksemplar.TenantID,
+			"buku_id":    eksemplar.BukuID,
+			"barcode":    eksemplar.Barcode,
+			"kondisi":    eksemplar.Kondisi,
+			"status":     eksemplar.Status,
+			"created_at": now,
+			"updated_at": now,
+		},
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *perpustakaanAdapter) GetEksemplarByBuku(ctx context.Context, tenantID, bukuID string) ([]model.BukuEksemplar, error) {
+	var list []model.BukuEksemplar
+	err := a.eksemplarDataset().
+		Where(goqu.I("e.tenant_id").Eq(tenantID), goqu.I("e.buku_id").Eq(bukuID)).
+		Order(goqu.I("e.barcode").Asc()).
+		ScanStructsContext(ctx, &list)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetEksemplarByBarcode locks the copy when called inside a transaction so two
+// desks cannot lend it at the same time
+func (a *perpustakaanAdapter) GetEksemplarByBarcode(ctx context.Context, tenantID, barcode string) (*model.BukuEksemplar, error) {
+	var eksemplar model.BukuEksemplar
+	found, err := a.eksemplarDataset().
+		Where(goqu.I("e.tenant_id").Eq(tenantID), goqu.I("e.barcode").Eq(barcode)).
+		ForUpdate(exp.Wait, goqu.T("e")).
+		ScanStructContext(ctx, &eksemplar)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return &eksemplar, nil
+}
+
+func (a *perpustakaanAdapter) UpdateEksemplarStatus(ctx context.Context, id, status string) error {
+	_, err := a.db.Update("sekolah_buku_eksemplar").
+		Set(goqu.Record{"status": status, "updated_at": time.Now()}).
+		Where(goqu.C("id").Eq(id)).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+// ==========================================
+// ANGGOTA
+// ==========================================
+
+func (a *perpustakaanAdapter) siswaAnggotaDataset() *goqu.SelectDataset {
+	return a.db.From(goqu.T("sekolah_siswa").As("s")).
+		LeftJoin(goqu.T("sekolah_kelas").As("k"), goqu.On(goqu.I("k.id").Eq(goqu.I("s.kelas_id")))).
+		Select(
+			goqu.V(model.AnggotaTipeSiswa).As("tipe"),
+			goqu.L("s.id::text").As("id"),
+			goqu.I("s.nama"),
+			goqu.COALESCE(goqu.I("s.nis"), "").As("identitas"),
+			goqu.COALESCE(goqu.I("k.nama"), "").As("kelas"),
+			goqu.COALESCE(goqu.I("s.no_hp_wali"), "").As("no_hp"),
+		)
+}
+
+func (a *perpustakaanAdapter) pegawaiAnggotaDataset() *goqu.SelectDataset {
+	return a.db.From(goqu.T("employees").As("p")).
+		Select(
+			goqu.V(model.AnggotaTipePegawai).As("tipe"),
+			goqu.L("p.id::text").As("id"),
+			goqu.I("p.name").As("nama"),
+			goqu.COALESCE(goqu.I("p.nip"), "").As("identitas"),
+			goqu.COALESCE(goqu.I("p.department"), "").As("kelas"),
+			goqu.COALESCE(goqu.I("p.phone"), "").As("no_hp"),
+		).
+		Where(goqu.I("p.is_active").Eq(true))
+}
+
+func (a *perpustakaanAdapter) SearchAnggota(ctx context.Context, tenantID, search string) ([]model.PerpustakaanAnggota, error) {
+	pattern := "%" + search + "%"
+
+	var siswa []model.PerpustakaanAnggota
+	err := a.siswaAnggotaDataset().
+		Where(
+			goqu.I("s.tenant_id").Eq(tenantID),
+			goqu.Or(goqu.I("s.nama").ILike(pattern), goqu.I("s.nis").ILike(pattern)),
+		).
+		Order(goqu.I("s.nama").Asc()).
+		Limit(20).
+		ScanStructsContext(ctx, &siswa)
+	if err != nil {
+		return nil, err
+	}
+
+	var pegawai []model.PerpustakaanAnggota
+	err = a.pegawaiAnggotaDataset().
+		Where(
+			goqu.I("p.tenant_id").Eq(tenantID),
+			goqu.Or(goqu.I("p.name").ILike(pattern), goqu.I("p.nip").ILike(pattern)),
+		).
+		Order(goqu.I("p.name").Asc()).
+		Limit(20).
+		ScanStructsContext(ctx, &pegawai)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(siswa, pegawai...), nil
+}
+
+// GetAnggota locks the member row when called inside a transaction, serialising
+// loans for the same member so the MaksPinjam count holds
+func (a *perpustakaanAdapter) GetAnggota(ctx context.Context, tenantID, tipe, id string) (*model.PerpustakaanAnggota, error) {
+	var ds *goqu.SelectDataset
+	switch tipe {
+	case model.AnggotaTipeSiswa:
+		ds = a.siswaAnggotaDataset().
+			Where(goqu.I("s.tenant_id").Eq(tenantID), goqu.I("s.id").Eq(id)).
+			ForUpdate(exp.Wait, goqu.T("s"))
+	case model.AnggotaTipePegawai:
+		ds = a.pegawaiAnggotaDataset().
+			Where(goqu.I("p.tenant_id").Eq(tenantID), goqu.I("p.id").Eq(id)).
+			ForUpdate(exp.Wait, goqu.T("p"))
+	default:
+		return nil, nil
+	}
+
+	var anggota model.PerpustakaanAnggota
+	found, err := ds.ScanStructContext(ctx, &anggota)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return &anggota, nil
+}
+
+// ==========================================
+// PEMINJAMAN
+// ==========================================
+
+func (a *perpustakaanAdapter) peminjamanDataset() *goqu.SelectDataset {
+	return a.db.From(goqu.T("sekolah_peminjaman").As("p")).
+		Join(goqu.T("sekolah_buku_eksemplar").As("e"), goqu.On(goqu.I("e.id").Eq(goqu.I("p.eksemplar_id")))).
+		Join(goqu.T("sekolah_buku").As("b"), goqu.On(goqu.I("b.id").Eq(goqu.I("e.buku_id")))).
+		Select(
+			goqu.I("p.id"),
+			goqu.I("p.tenant_id"),
+			goqu.I("p.eksemplar_id"),
+			goqu.I("e.barcode"),
+			goqu.I("b.judul").As("buku_judul"),
+			goqu.I("p.anggota_tipe"),
+			goqu.I("p.anggota_id"),
+			goqu.I("p.anggota_nama"),
+			goqu.COALESCE(goqu.I("p.anggota_no_hp"), "").As("anggota_no_hp"),
+			goqu.I("p.tanggal_pinjam"),
+			goqu.I("p.jatuh_tempo"),
+			goqu.I("p.tanggal_kembali"),
+			goqu.I("p.jumlah_perpanjang"),
+			goqu.I("p.denda"),
+			goqu.I("p.denda_lunas"),
+			goqu.I("p.status"),
+			goqu.I("p.created_at"),
+			goqu.I("p.updated_at"),
+		)
+}
+
+func (a *perpustakaanAdapter) CreatePeminjaman(ctx context.Context, p *model.Peminjaman) error {
+	now := time.Now()
+	p.ID = uuid.New().String()
+	p.CreatedAt = now
+	p.UpdatedAt = now
+
+	_, err := a.db.Insert("sekolah_peminjaman").Rows(
+		goqu.Record{
+			"id":                p.ID,
+			"tenant_id":         p.TenantID,
+			"eksemplar_id":      p.EksemplarID,
+			"anggota_tipe":      p.AnggotaTipe,
+			"anggota_id":        p.AnggotaID,
+			"anggota_nama":      p.AnggotaNama,
+			"anggota_no_hp":     p.AnggotaNoHP,
+			"tanggal_pinjam":    p.TanggalPinjam,
+			"jatuh_tempo":       p.JatuhTempo,
+			"jumlah_perpanjang": 0,
+			"denda":             0,
+			"denda_lunas":       false,
+			"status":            p.Status,
+			"created_at":        now,
+			"updated_at":        now,
+		},
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *perpustakaanAdapter) UpdatePeminjaman(ctx context.Context, p *model.Peminjaman, statusLama string) (bool, error) {
+	res, err := a.db.Update("sekolah_peminjaman").Set(
+		goqu.Record{
+			"jatuh_tempo":       p.JatuhTempo,
+			"tanggal_kembali":   p.TanggalKembali,
+			"jumlah_perpanjang": p.JumlahPerpanjang,
+			"denda":             p.Denda,
+			"denda_lunas":       p.DendaLunas,
+			"status":            p.Status,
+			"updated_at":        time.Now(),
+		},
+	).Where(
+		goqu.C("id").Eq(p.ID),
+		goqu.C("tenant_id").Eq(p.TenantID),
+		goqu.C("status").Eq(statusLama),
+	).Executor().ExecContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+	return affected > 0, nil
+}
+
+func (a *perpustakaanAdapter) LunasiDenda(ctx context.Context, tenantID, id string) (bool, error) {
+	res, err := a.db.Update("sekolah_peminjaman").
+		Set(goqu.Record{"denda_lunas": true, "updated_at": time.Now()}).
+		Where(
+			goqu.C("id").Eq(id),
+			goqu.C("tenant_id").Eq(tenantID),
+			goqu.C("status").Neq(model.PeminjamanDipinjam),
+			goqu.C("denda_lunas").IsFalse(),
+		).Executor().ExecContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+	return affected > 0, nil
+}
+
+// GetPeminjamanByID locks the loan and its copy when called inside a
+// transaction, so a return and a payment on the same loan run one at a time
+func (a *perpustakaanAdapter) GetPeminjamanByID(ctx context.Context, tenantID, id string) (*model.Peminjaman, error) {
+	var p model.Peminjaman
+	found, err := a.peminjamanDataset().
+		Where(goqu.I("p.id").Eq(id), goqu.I("p.tenant_id").Eq(tenantID)).
+		ForUpdate(exp.Wait, goqu.T("p"), goqu.T("e")).
+		ScanStructContext(ctx, &p)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return &p, nil
+}
+
+func (a *perpustakaanAdapter) GetPeminjamanList(ctx context.Context, filter model.PeminjamanFilter) ([]model.Peminjaman, error) {
+	ds := a.peminjamanDataset().Where(goqu.I("p.tenant_id").Eq(filter.TenantID))
+	if filter.Status != "" {
+		ds = ds.Where(goqu.I("p.status").Eq(filter.Status))
+	}
+	if filter.AnggotaTipe != "" {
+		ds = ds.Where(goqu.I("p.anggota_tipe").Eq(filter.AnggotaTipe))
+	}
+	if filter.AnggotaID != "" {
+		ds = ds.Where(goqu.I("p.anggota_id").Eq(filter.AnggotaID))
+	}
+
+	var list []model.Peminjaman
+	if err := ds.Order(goqu.I("p.tanggal_pinjam").Desc()).ScanStructsContext(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (a *perpustakaanAdapter) CountActivePeminjaman(ctx context.Context, tenantID, anggotaTipe, anggotaID string) (int, error) {
+	var count int
+	_, err := a.db.From("sekolah_peminjaman").
+		Select(goqu.COUNT("*")).
+		Where(
+			goqu.C("tenant_id").Eq(tenantID),
+			goqu.C("anggota_tipe").Eq(anggotaTipe),
+			goqu.C("anggota_id").Eq(anggotaID),
+			goqu.C("status").Eq(model.PeminjamanDipinjam),
+		).
+		ScanValContext(ctx, &count)
+	return count, err
+}
+
+func (a *perpustakaanAdapter) GetOverduePeminjaman(ctx context.Context, tenantID string, asOf time.Time) ([]model.Peminjaman, error) {
+	ds := a.peminjamanDataset().Where(
+		goqu.I("p.status").Eq(model.PeminjamanDipinjam),
+		goqu.I("p.jatuh_tempo").Lt(asOf.Format("2006-01-02")),
+	)
+	if tenantID != "" {
+		ds = ds.Where(goqu.I("p.tenant_id").Eq(tenantID))
+	}
+
+	var list []model.Peminjaman
+	if err := ds.Order(goqu.I("p.jatuh_tempo").Asc()).ScanStructsContext(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// ==========================================
+// LAPORAN
+// ==========================================
+
+func (a *perpustakaanAdapter) GetBukuPopuler(ctx context.Context, tenantID string, from, to time.Time, limit int) ([]model.BukuPopuler, error) {
+	var list []model.BukuPopuler
+	err := a.db.From(goqu.T("sekolah_peminjaman").As("p")).
+		Join(goqu.T("sekolah_buku_eksemplar").As("e"), goqu.On(goqu.I("e.id").Eq(goqu.I("p.eksemplar_id")))).
+		Join(goqu.T("sekolah_buku").As("b"), goqu.On(goqu.I("b.id").Eq(goqu.I("e.buku_id")))).
+		Select(
+			goqu.I("b.id").As("buku_id"),
+			goqu.I("b.judul"),
+			goqu.COALESCE(goqu.I("b.penulis"), "").As("penulis"),
+			goqu.COUNT(goqu.I("p.id")).As("jumlah_pinjam"),
+		).
+		Where(
+			goqu.I("p.tenant_id").Eq(tenantID),
+			goqu.I("p.tanggal_pinjam").Gte(from.Format("2006-01-02")),
+			goqu.I("p.tanggal_pinjam").Lte(to.Format("2006-01-02")),
+		).
+		GroupBy(goqu.I("b.id"), goqu.I("b.judul"), goqu.I("b.penulis")).
+		Order(goqu.I("jumlah_pinjam").Desc(), goqu.I("b.judul").Asc()).
+		Limit(uint(limit)).
+		ScanStructsContext(ctx, &list)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// ==========================================
+// CONFIG
+// ==========================================
+
+func (a *perpustakaanAdapter) GetConfig(ctx context.Context, tenantID string) (*model.PerpustakaanConfig, error) {
+	var config model.PerpustakaanConfig
+	found, err := a.db.From("sekolah_perpustakaan_config").
+		Select("tenant_id", "lama_pinjam_hari", "maks_perpanjang", "maks_pinjam", "denda_per_hari").
+		Where(goqu.C("tenant_id").Eq(tenantID)).
+		ScanStructContext(ctx, &config)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return &config, nil
+}
+
+func (a *perpustakaanAdapter) SaveConfig(ctx context.Context, config *model.PerpustakaanConfig) error {
+	_, err := a.db.Insert("sekolah_perpustakaan_config").Rows(
+		goqu.Record{
+			"tenant_id":        config.TenantID,
+			"lama_pinjam_hari": config.LamaPinjamHari,
+			"maks_perpanjang":  config.MaksPerpanjang,
+			"maks_pinjam":      config.MaksPinjam,
+			"denda_per_hari":   config.DendaPerHari,
+			"updated_at":       time.Now(),
+		},
+	).OnConflict(goqu.DoUpdate("tenant_id", goqu.Record{
+		"lama_pinjam_hari": goqu.L("EXCLUDED.lama_pinjam_hari"),
+		"maks_perpanjang":  goqu.L("EXCLUDED.maks_perpanjang"),
+		"maks_pinjam":      goqu.L("EXCLUDED.maks_pinjam"),
+		"denda_per_hari":   goqu.L("EXCLUDED.denda_per_hari"),
+		"updated_at":       goqu.L("EXCLUDED.updated_at"),
+	})).Executor().ExecContext(ctx)
+	return err
+}
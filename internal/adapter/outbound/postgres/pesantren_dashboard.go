@@ -96,9 +96,9 @@ func (a *pesantrenDashboardAdapter) GetDashboardStats(ctx context.Context, tenan
 		stats.ActiveViolations = 0
 	}
 
-	// 7. Perizinan Berjalan (approved but not returned)
-	queryPerizinan, _, _ := dialect.From("sekolah_perizinan_siswa").
-		Where(goqu.Ex{"tenant_id": tenantID, "status": "approved"}).
+	// 7. Perizinan Berjalan (checked out but not returned)
+	queryPerizinan, _, _ := dialect.From("sekolah_perizinan").
+		Where(goqu.Ex{"tenant_id": tenantID, "status": model.PerizinanKeluar}).
 		Select(goqu.COUNT("*")).ToSQL()
 
 	if err := a.db.QueryRow(queryPerizinan).Scan(&stats.ActivePerizinan); err != nil && err != sql.ErrNoRows {
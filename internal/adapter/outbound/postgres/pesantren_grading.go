@@ -0,0 +1,140 @@
+package postgres_outbound_adapter
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"prabogo/internal/model"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// ==========================================
+// PESANTREN GRADING
+// ==========================================
+
+func (a *eraporAdapter) GetPredicateSets(ctx context.Context, tenantID string) ([]model.PredicateSet, error) {
+	var rows []struct {
+		SubjectType string    `db:"subject_type"`
+		Rules       []byte    `db:"rules"`
+		UpdatedAt   time.Time `db:"updated_at"`
+	}
+	err := a.db.From("erapor_predicate_sets").
+		Select("subject_type", "rules", "updated_at").
+		Where(goqu.C("tenant_id").Eq(tenantID)).
+		Order(goqu.C("subject_type").Asc()).
+		ScanStructsContext(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	sets := make([]model.PredicateSet, len(rows))
+	for i, r := range rows {
+		sets[i] = model.PredicateSet{TenantID: tenantID, SubjectType: r.SubjectType, UpdatedAt: r.UpdatedAt}
+		if err := json.Unmarshal(r.Rules, &sets[i].Rules); err != nil {
+			return nil, err
+		}
+	}
+	return sets, nil
+}
+
+func (a *eraporAdapter) SavePredicateSet(ctx context.Context, set *model.PredicateSet) error {
+	rules, err := json.Marshal(set.Rules)
+	if err != nil {
+		return err
+	}
+	set.UpdatedAt = time.Now()
+	_, err = a.db.Insert("erapor_predicate_sets").Rows(
+		goqu.Record{
+			"tenant_id":    set.TenantID,
+			"subject_type": set.SubjectType,
+			"rules":        rules,
+			"updated_at":   set.UpdatedAt,
+		},
+	).OnConflict(goqu.DoUpdate("tenant_id, subject_type", goqu.Record{
+		"rules":      goqu.L("EXCLUDED.rules"),
+		"updated_at": set.UpdatedAt,
+	})).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *eraporAdapter) DeletePredicateSet(ctx context.Context, tenantID, subjectType string) error {
+	_, err := a.db.Delete("erapor_predicate_sets").
+		Where(
+			goqu.C("tenant_id").Eq(tenantID),
+			goqu.C("subject_type").Eq(subjectType),
+		).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *eraporAdapter) GetTahfidzSetoranPeriode(ctx context.Context, tenantID string, santriIDs []string, start, end time.Time) ([]model.TahfidzSetoran, error) {
+	ds := a.db.From(goqu.T("sekolah_tahfidz_setoran").As("t")).
+		Join(goqu.T("sekolah_siswa").As("s"), goqu.On(goqu.I("s.id").Eq(goqu.I("t.santri_id")))).
+		Select(
+			goqu.I("t.id"),
+			goqu.I("t.tenant_id"),
+			goqu.I("t.santri_id"),
+			goqu.I("s.nama").As("santri_nama"),
+			goqu.I("t.tanggal"),
+			goqu.COALESCE(goqu.I("t.juz"), 0).As("juz"),
+			goqu.COALESCE(goqu.I("t.surah"), "").As("surah"),
+			goqu.COALESCE(goqu.I("t.ayat_awal"), 0).As("ayat_awal"),
+			goqu.COALESCE(goqu.I("t.ayat_akhir"), 0).As("ayat_akhir"),
+			goqu.I("t.tipe"),
+			goqu.COALESCE(goqu.I("t.kualitas"), "").As("kualitas"),
+			goqu.COALESCE(goqu.I("t.catatan"), "").As("catatan"),
+			goqu.I("t.created_at"),
+			goqu.I("t.updated_at"),
+		).
+		Where(
+			goqu.I("t.tenant_id").Eq(tenantID),
+			goqu.I("t.tanggal").Gte(start),
+			goqu.I("t.tanggal").Lte(end),
+		)
+	if len(santriIDs) > 0 {
+		ds = ds.Where(goqu.I("t.santri_id").In(santriIDs))
+	}
+
+	var rows []struct {
+		ID         string    `db:"id"`
+		TenantID   string    `db:"tenant_id"`
+		SantriID   string    `db:"santri_id"`
+		SantriNama string    `db:"santri_nama"`
+		Tanggal    time.Time `db:"tanggal"`
+		Juz        int       `db:"juz"`
+		Surah      string    `db:"surah"`
+		AyatAwal   int       `db:"ayat_awal"`
+		AyatAkhir  int       `db:"ayat_akhir"`
+		Tipe       string    `db:"tipe"`
+		Kualitas   string    `db:"kualitas"`
+		Catatan    string    `db:"catatan"`
+		CreatedAt  time.Time `db:"created_at"`
+		UpdatedAt  time.Time `db:"updated_at"`
+	}
+	if err := ds.Order(goqu.I("t.tanggal").Asc()).ScanStructsContext(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	list := make([]model.TahfidzSetoran, len(rows))
+	for i, r := range rows {
+		list[i] = model.TahfidzSetoran{
+			ID:         r.ID,
+			TenantID:   r.TenantID,
+			SantriID:   r.SantriID,
+			SantriNama: r.SantriNama,
+			Tanggal:    r.Tanggal,
+			Juz:        r.Juz,
+			Surah:      r.Surah,
+			AyatAwal:   r.AyatAwal,
+			AyatAkhir:  r.AyatAkhir,
+			Tipe:       r.Tipe,
+			Kualitas:   r.Kualitas,
+			Catatan:    r.Catatan,
+			CreatedAt:  r.CreatedAt,
+			UpdatedAt:  r.UpdatedAt,
+		}
+	}
+	return list, nil
+}
@@ -0,0 +1,395 @@
+package postgres_outbound_adapter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"prabogo/internal/model"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/google/uuid"
+)
+
+type ppdbAdapter struct {
+	db goquQuerier
+}
+
+func NewPPDBAdapter(sqlDB *sql.DB) *ppdbAdapter {
+	return &ppdbAdapter{db: goqu.New("postgres", sqlDB)}
+}
+
+// NewPPDBTxAdapter binds the adapter to an open transaction
+func NewPPDBTxAdapter(tx *sql.Tx) *ppdbAdapter {
+	return &ppdbAdapter{db: goqu.NewTx("postgres", tx)}
+}
+
+// ==========================================
+// GELOMBANG
+// ==========================================
+
+func (a *ppdbAdapter) gelombangDataset() *goqu.SelectDataset {
+	return a.db.From(goqu.T("sekolah_ppdb_gelombang").As("g")).
+		Select(
+			goqu.I("g.id"),
+			goqu.I("g.tenant_id"),
+			goqu.I("g.tahun_ajaran"),
+			goqu.I("g.nama"),
+			goqu.I("g.tanggal_buka"),
+			goqu.I("g.tanggal_tutup"),
+			goqu.I("g.kuota"),
+			goqu.I("g.biaya_pendaftaran"),
+			goqu.COALESCE(goqu.I("g.is_active"), false).As("is_active"),
+			goqu.L(`(SELECT COUNT(*) FROM sekolah_ppdb_pendaftar p WHERE p.gelombang_id = g.id)`).As("jumlah_pendaftar"),
+			goqu.L(`(SELECT COUNT(*) FROM sekolah_ppdb_pendaftar p WHERE p.gelombang_id = g.id AND p.status = ?)`, model.PPDBStatusDiterima).As("jumlah_diterima"),
+			goqu.I("g.created_at"),
+			goqu.I("g.updated_at"),
+		)
+}
+
+func (a *ppdbAdapter) CreateGelombang(ctx context.Context, g *model.PPDBGelombang) error {
+	now := time.Now()
+	g.ID = uuid.New().String()
+	g.CreatedAt = now
+	g.UpdatedAt = now
+
+	_, err := a.db.Insert("sekolah_ppdb_gelombang").Rows(
+		goqu.Record{
+			"id":                g.ID,
+			"tenant_id":         g.TenantID,
+			"tahun_ajaran":      g.TahunAjaran,
+			"nama":              g.Nama,
+			"tanggal_buka":      g.TanggalBuka,
+			"tanggal_tutup":     g.TanggalTutup,
+			"kuota":             g.Kuota,
+			"biaya_pendaftaran": g.BiayaPendaftaran,
+			"is_active":         g.IsActive,
+			"created_at":        now,
+			"updated_at":        now,
+		},
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *ppdbAdapter) UpdateGelombang(ctx context.Context, g *model.PPDBGelombang) error {
+	_, err := a.db.Update("sekolah_ppdb_gelombang").Set(
+		goqu.Record{
+			"tahun_ajaran":      g.TahunAjaran,
+			"nama":              g.Nama,
+			"tanggal_buka":      g.TanggalBuka,
+			"tanggal_tutup":     g.TanggalTutup,
+			"kuota":             g.Kuota,
+			"biaya_pendaftaran": g.BiayaPendaftaran,
+			"is_active":         g.IsActive,
+			"updated_at":        time.Now(),
+		},
+	).Where(
+		goqu.C("id").Eq(g.ID),
+		goqu.C("tenant_id").Eq(g.TenantID),
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+// GetGelombangByID locks the wave when called inside a transaction, so
+// acceptances against the same quota run one at a time
+func (a *ppdbAdapter) GetGelombangByID(ctx context.Context, tenantID, id string) (*model.PPDBGelombang, error) {
+	var g model.PPDBGelombang
+	found, err := a.gelombangDataset().
+		Where(goqu.I("g.id").Eq(id), goqu.I("g.tenant_id").Eq(tenantID)).
+		ForUpdate(exp.Wait, goqu.T("g")).
+		ScanStructContext(ctx, &g)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return &g, nil
+}
+
+func (a *ppdbAdapter) GetGelombangList(ctx context.Context, tenantID string, activeOnly bool) ([]model.PPDBGelombang, error) {
+	ds := a.gelombangDataset().Where(goqu.I("g.tenant_id").Eq(tenantID))
+	if activeOnly {
+		ds = ds.Where(goqu.I("g.is_active").IsTrue())
+	}
+
+	var list []model.PPDBGelombang
+	if err := ds.Order(goqu.I("g.tanggal_buka").Desc()).ScanStructsContext(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// ==========================================
+// PENDAFTAR
+// ==========================================
+
+func (a *ppdbAdapter) pendaftarDataset() *goqu.SelectDataset {
+	return a.db.From(goqu.T("sekolah_ppdb_pendaftar").As("p")).
+		Join(goqu.T("sekolah_ppdb_gelombang").As("g"), goqu.On(goqu.I("g.id").Eq(goqu.I("p.gelombang_id")))).
+		Select(
+			goqu.I("p.id"),
+			goqu.I("p.tenant_id"),
+			goqu.I("p.gelombang_id"),
+			goqu.I("g.nama").As("gelombang_nama"),
+			goqu.I("p.no_pendaftaran"),
+			goqu.I("p.nama"),
+			goqu.COALESCE(goqu.I("p.nisn"), "").As("nisn"),
+			goqu.COALESCE(goqu.I("p.jenis_kelamin"), "").As("jenis_kelamin"),
+			goqu.COALESCE(goqu.I("p.tempat_lahir"), "").As("tempat_lahir"),
+			goqu.I("p.tanggal_lahir"),
+			goqu.COALESCE(goqu.I("p.alamat"), "").As("alamat"),
+			goqu.COALESCE(goqu.I("p.asal_sekolah"), "").As("asal_sekolah"),
+			goqu.COALESCE(goqu.I("p.nama_wali"), "").As("nama_wali"),
+			goqu.I("p.no_hp_wali"),
+			goqu.COALESCE(goqu.I("p.email_wali"), "").As("email_wali"),
+			goqu.I("p.status"),
+			goqu.L("p.nilai_tes::float8").As("nilai_tes"),
+			goqu.COALESCE(goqu.I("p.catatan"), "").As("catatan"),
+			goqu.I("p.status_bayar"),
+			goqu.COALESCE(goqu.I("p.payment_order_id"), "").As("payment_order_id"),
+			goqu.COALESCE(goqu.L("p.siswa_id::text"), "").As("siswa_id"),
+			goqu.I("p.created_at"),
+			goqu.I("p.updated_at"),
+		)
+}
+
+func (a *ppdbAdapter) CreatePendaftar(ctx context.Context, p *model.PPDBPendaftar) error {
+	now := time.Now()
+	p.ID = uuid.New().String()
+	p.CreatedAt = now
+	p.UpdatedAt = now
+
+	_, err := a.db.Insert("sekolah_ppdb_pendaftar").Rows(
+		goqu.Record{
+			"id":             p.ID,
+			"tenant_id":      p.TenantID,
+			"gelombang_id":   p.GelombangID,
+			"no_pendaftaran": p.NoPendaftaran,
+			"nama":           p.Nama,
+			"nisn":           p.NISN,
+			"jenis_kelamin":  p.JenisKelamin,
+			"tempat_lahir":   p.TempatLahir,
+			"tanggal_lahir":  p.TanggalLahir,
+			"alamat":         p.Alamat,
+			"asal_sekolah":   p.AsalSekolah,
+			"nama_wali":      p.NamaWali,
+			"no_hp_wali":     p.NoHPWali,
+			"email_wali":     p.EmailWali,
+			"status":         p.Status,
+			"status_bayar":   p.StatusBayar,
+			"created_at":     now,
+			"updated_at":     now,
+		},
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *ppdbAdapter) UpdatePendaftarStatus(ctx context.Context, p *model.PPDBPendaftar, statusLama string) (bool, error) {
+	res, err := a.db.Update("sekolah_ppdb_pendaftar").Set(
+		goqu.Record{
+			"status":     p.Status,
+			"nilai_tes":  p.NilaiTes,
+			"catatan":    p.Catatan,
+			"updated_at": time.Now(),
+		},
+	).Where(
+		goqu.C("id").Eq(p.ID),
+		goqu.C("tenant_id").Eq(p.TenantID),
+		goqu.C("status").Eq(statusLama),
+	).Executor().ExecContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+	return affected > 0, nil
+}
+
+func (a *ppdbAdapter) UpdateStatusBayar(ctx context.Context, tenantID, id, statusBayar, orderID string) error {
+	record := goqu.Record{
+		"status_bayar": statusBayar,
+		"updated_at":   time.Now(),
+	}
+	if orderID != "" {
+		record["payment_order_id"] = orderID
+	}
+
+	_, err := a.db.Update("sekolah_ppdb_pendaftar").Set(record).Where(
+		goqu.C("id").Eq(id),
+		goqu.C("tenant_id").Eq(tenantID),
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *ppdbAdapter) scanPendaftar(ctx context.Context, ds *goqu.SelectDataset) (*model.PPDBPendaftar, error) {
+	var p model.PPDBPendaftar
+	found, err := ds.ScanStructContext(ctx, &p)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return &p, nil
+}
+
+func (a *ppdbAdapter) GetPendaftarByID(ctx context.Context, tenantID, id string) (*model.PPDBPendaftar, error) {
+	return a.scanPendaftar(ctx, a.pendaftarDataset().
+		Where(goqu.I("p.id").Eq(id), goqu.I("p.tenant_id").Eq(tenantID)))
+}
+
+func (a *ppdbAdapter) GetPendaftarByNo(ctx context.Context, tenantID, noPendaftaran string) (*model.PPDBPendaftar, error) {
+	return a.scanPendaftar(ctx, a.pendaftarDataset().
+		Where(goqu.I("p.no_pendaftaran").Eq(noPendaftaran), goqu.I("p.tenant_id").Eq(tenantID)))
+}
+
+func (a *ppdbAdapter) GetPendaftarForPayment(ctx context.Context, id string) (*model.PPDBPendaftar, error) {
+	return a.scanPendaftar(ctx, a.pendaftarDataset().
+		Where(goqu.I("p.id").Eq(id)))
+}
+
+func (a *ppdbAdapter) GetPendaftarList(ctx context.Context, filter model.PPDBPendaftarFilter) ([]model.PPDBPendaftar, error) {
+	ds := a.pendaftarDataset().Where(goqu.I("p.tenant_id").Eq(filter.TenantID))
+	if filter.GelombangID != "" {
+		ds = ds.Where(goqu.I("p.gelombang_id").Eq(filter.GelombangID))
+	}
+	if filter.Status != "" {
+		ds = ds.Where(goqu.I("p.status").Eq(filter.Status))
+	}
+	if filter.Search != "" {
+		pattern := "%" + filter.Search + "%"
+		ds = ds.Where(goqu.Or(
+			goqu.I("p.nama").ILike(pattern),
+			goqu.I("p.no_pendaftaran").ILike(pattern),
+			goqu.I("p.nisn").ILike(pattern),
+		))
+	}
+
+	var list []model.PPDBPendaftar
+	if err := ds.Order(goqu.I("p.created_at").Asc()).ScanStructsContext(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (a *ppdbAdapter) NextNoUrut(ctx context.Context, tenantID, tahunAjaran string) (int, error) {
+	return nextNomorUrut(ctx, a.db, tenantID, nomorUrutPPDB, tahunAjaran, a.pendaftarByTahun(tenantID, tahunAjaran))
+}
+
+// pendaftarByTahun counts applicants numbered before the counter row existed
+func (a *ppdbAdapter) pendaftarByTahun(tenantID, tahunAjaran string) *goqu.SelectDataset {
+	return a.db.From(goqu.T("sekolah_ppdb_pendaftar").As("p")).
+		Join(goqu.T("sekolah_ppdb_gelombang").As("g"), goqu.On(goqu.I("g.id").Eq(goqu.I("p.gelombang_id")))).
+		Select(goqu.COUNT("*")).
+		Where(
+			goqu.I("p.tenant_id").Eq(tenantID),
+			goqu.I("g.tahun_ajaran").Eq(tahunAjaran),
+		)
+}
+
+func (a *ppdbAdapter) KelasExists(ctx context.Context, tenantID, kelasID string) (bool, error) {
+	var id string
+	return a.db.From("sekolah_kelas").
+		Select(goqu.L("id::text")).
+		Where(goqu.C("id").Eq(kelasID), goqu.C("tenant_id").Eq(tenantID)).
+		ScanValContext(ctx, &id)
+}
+
+func (a *ppdbAdapter) CountDiterima(ctx context.Context, gelombangID string) (int, error) {
+	var count int
+	_, err := a.db.From("sekolah_ppdb_pendaftar").
+		Select(goqu.COUNT("*")).
+		Where(
+			goqu.C("gelombang_id").Eq(gelombangID),
+			goqu.C("status").Eq(model.PPDBStatusDiterima),
+		).
+		ScanValContext(ctx, &count)
+	return count, err
+}
+
+// ==========================================
+// DOKUMEN
+// ==========================================
+
+func (a *ppdbAdapter) AddDokumen(ctx context.Context, d *model.PPDBDokumen) error {
+	d.ID = uuid.New().String()
+	d.CreatedAt = time.Now()
+
+	_, err := a.db.Insert("sekolah_ppdb_dokumen").Rows(
+		goqu.Record{
+			"id":           d.ID,
+			"pendaftar_id": d.PendaftarID,
+			"jenis":        d.Jenis,
+			"url":          d.URL,
+			"verified":     d.Verified,
+			"created_at":   d.CreatedAt,
+		},
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *ppdbAdapter) GetDokumenByPendaftar(ctx context.Context, pendaftarID string) ([]model.PPDBDokumen, error) {
+	var list []model.PPDBDokumen
+	err := a.db.From("sekolah_ppdb_dokumen").
+		Select("id", "pendaftar_id", "jenis", "url", goqu.COALESCE(goqu.C("verified"), false).As("verified"), "created_at").
+		Where(goqu.C("pendaftar_id").Eq(pendaftarID)).
+		Order(goqu.C("created_at").Asc()).
+		ScanStructsContext(ctx, &list)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// ==========================================
+// KONVERSI
+// ==========================================
+
+func (a *ppdbAdapter) KonversiSiswa(ctx context.Context, p *model.PPDBPendaftar, kelasID, nis string) (string, error) {
+	siswaID := uuid.New().String()
+
+	err := inGoquTx(a.db, func(tx goquQuerier) error {
+		_, err := tx.Insert("sekolah_siswa").Rows(
+			goqu.Record{
+				"id":         siswaID,
+				"tenant_id":  p.TenantID,
+				"nis":        nis,
+				"nisn":       p.NISN,
+				"nama":       p.Nama,
+				"kelas_id":   kelasID,
+				"alamat":     p.Alamat,
+				"nama_wali":  p.NamaWali,
+				"no_hp_wali": p.NoHPWali,
+				"status":     "Aktif",
+			},
+		).Executor().ExecContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		res, err := tx.Update("sekolah_ppdb_pendaftar").Set(
+			goqu.Record{
+				"siswa_id":   siswaID,
+				"updated_at": time.Now(),
+			},
+		).Where(
+			goqu.C("id").Eq(p.ID),
+			goqu.C("tenant_id").Eq(p.TenantID),
+			goqu.C("siswa_id").IsNull(),
+		).Executor().ExecContext(ctx)
+		if err != nil {
+			return err
+		}
+		// Another request converted this applicant first; roll back the duplicate siswa
+		if affected, _ := res.RowsAffected(); affected == 0 {
+			return errors.New("pendaftar sudah dikonversi menjadi siswa")
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return siswaID, nil
+}
@@ -0,0 +1,204 @@
+package postgres_outbound_adapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"prabogo/internal/model"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/google/uuid"
+)
+
+// raporBatchRow scans a batch with its JSONB columns
+type raporBatchRow struct {
+	model.RaporBatch
+	SiswaJSON  []byte `db:"siswa"`
+	ErrorsJSON []byte `db:"errors"`
+}
+
+func (r raporBatchRow) toModel() (*model.RaporBatch, error) {
+	b := r.RaporBatch
+	if len(r.SiswaJSON) > 0 {
+		if err := json.Unmarshal(r.SiswaJSON, &b.Siswa); err != nil {
+			return nil, err
+		}
+	}
+	b.Errors = []model.RaporBatchError{}
+	if len(r.ErrorsJSON) > 0 {
+		if err := json.Unmarshal(r.ErrorsJSON, &b.Errors); err != nil {
+			return nil, err
+		}
+	}
+	b.Progress = b.HitungProgress()
+	return &b, nil
+}
+
+func (a *eraporAdapter) raporBatchDataset() *goqu.SelectDataset {
+	return a.db.From(goqu.T("sekolah_rapor_batch").As("b")).
+		LeftJoin(goqu.T("sekolah_kelas").As("k"), goqu.On(goqu.I("k.id").Eq(goqu.I("b.kelas_id")))).
+		Select(
+			goqu.I("b.id"),
+			goqu.I("b.tenant_id"),
+			goqu.I("b.kelas_id"),
+			goqu.COALESCE(goqu.I("k.nama"), "").As("kelas_nama"),
+			goqu.I("b.semester_id"),
+			goqu.I("b.status"),
+			goqu.I("b.total"),
+			goqu.I("b.selesai"),
+			goqu.I("b.gagal"),
+			goqu.I("b.siswa"),
+			goqu.I("b.errors"),
+			goqu.I("b.started_at"),
+			goqu.I("b.finished_at"),
+			goqu.I("b.created_at"),
+			goqu.I("b.updated_at"),
+		)
+}
+
+func (a *eraporAdapter) GetRaporIDBySiswa(ctx context.Context, periodeID, siswaID string) (string, error) {
+	var id string
+	_, err := a.db.From("sekolah_rapor").
+		Select("id").
+		Where(
+			goqu.C("periode_id").Eq(periodeID),
+			goqu.C("santri_id").Eq(siswaID),
+		).
+		ScanValContext(ctx, &id)
+	return id, err
+}
+
+func (a *eraporAdapter) GetSiswaByKelas(ctx context.Context, tenantID, kelasID string) ([]model.RaporBatchSiswa, error) {
+	var rows []struct {
+		ID   string `db:"id"`
+		Nama string `db:"nama"`
+	}
+	err := a.db.From("sekolah_siswa").
+		Select("id", "nama").
+		Where(
+			goqu.C("tenant_id").Eq(tenantID),
+			goqu.C("kelas_id").Eq(kelasID),
+			goqu.C("status").Eq("Aktif"),
+		).
+		Order(goqu.C("nama").Asc()).
+		ScanStructsContext(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.RaporBatchSiswa, len(rows))
+	for i, r := range rows {
+		result[i] = model.RaporBatchSiswa{SiswaID: r.ID, Nama: r.Nama}
+	}
+	return result, nil
+}
+
+func (a *eraporAdapter) CreateRaporBatch(ctx context.Context, b *model.RaporBatch) error {
+	now := time.Now()
+	b.ID = uuid.New().String()
+	b.CreatedAt = now
+	b.UpdatedAt = now
+	siswa, _ := json.Marshal(b.Siswa)
+	errs, _ := json.Marshal(b.Errors)
+
+	_, err := a.db.Insert("sekolah_rapor_batch").Rows(
+		goqu.Record{
+			"id":          b.ID,
+			"tenant_id":   b.TenantID,
+			"kelas_id":    b.KelasID,
+			"semester_id": b.SemesterID,
+			"status":      b.Status,
+			"total":       b.Total,
+			"siswa":       siswa,
+			"errors":      errs,
+			"created_at":  now,
+			"updated_at":  now,
+		},
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *eraporAdapter) GetRaporBatch(ctx context.Context, tenantID, id string) (*model.RaporBatch, error) {
+	var row raporBatchRow
+	found, err := a.raporBatchDataset().
+		Where(
+			goqu.I("b.id").Eq(id),
+			goqu.I("b.tenant_id").Eq(tenantID),
+		).
+		ScanStructContext(ctx, &row)
+	if err != nil || !found {
+		return nil, err
+	}
+	return row.toModel()
+}
+
+func (a *eraporAdapter) GetRaporBatchList(ctx context.Context, tenantID string) ([]model.RaporBatch, error) {
+	var rows []raporBatchRow
+	err := a.raporBatchDataset().
+		Where(goqu.I("b.tenant_id").Eq(tenantID)).
+		Order(goqu.I("b.created_at").Desc()).
+		Limit(50).
+		ScanStructsContext(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.RaporBatch, 0, len(rows))
+	for _, r := range rows {
+		b, err := r.toModel()
+		if err != nil {
+			return nil, err
+		}
+		// The roster is only needed by the worker
+		b.Siswa = nil
+		result = append(result, *b)
+	}
+	return result, nil
+}
+
+func (a *eraporAdapter) UpdateRaporBatchProgress(ctx context.Context, b *model.RaporBatch) error {
+	errs, _ := json.Marshal(b.Errors)
+	_, err := a.db.Update("sekolah_rapor_batch").
+		Set(goqu.Record{
+			"status":      b.Status,
+			"selesai":     b.Selesai,
+			"gagal":       b.Gagal,
+			"errors":      errs,
+			"started_at":  b.StartedAt,
+			"finished_at": b.FinishedAt,
+		}).
+		Where(goqu.C("id").Eq(b.ID)).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *eraporAdapter) SaveRaporBatchFiles(ctx context.Context, id string, zipFile, pdfFile []byte) error {
+	// Bind the files as parameters, bytea must not be interpolated as text
+	_, err := a.db.ExecContext(ctx,
+		`UPDATE sekolah_rapor_batch SET zip_file = $1, pdf_file = $2 WHERE id = $3`,
+		zipFile, pdfFile, id,
+	)
+	return err
+}
+
+func (a *eraporAdapter) GetRaporBatchFile(ctx context.Context, tenantID, id, jenis string) ([]byte, error) {
+	column := map[string]string{
+		model.RaporBatchFileZip: "zip_file",
+		model.RaporBatchFilePDF: "pdf_file",
+	}[jenis]
+	if column == "" {
+		return nil, fmt.Errorf("unknown rapor batch file %q", jenis)
+	}
+
+	var file []byte
+	_, err := a.db.From("sekolah_rapor_batch").
+		Select(goqu.C(column)).
+		Where(
+			goqu.C("id").Eq(id),
+			goqu.C("tenant_id").Eq(tenantID),
+		).
+		ScanValContext(ctx, &file)
+	return file, err
+}
@@ -0,0 +1,162 @@
+package postgres_outbound_adapter
+
+import (
+	"context"
+	"time"
+
+	"prabogo/internal/model"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+// normalizedPhone strips formatting and the 0/62 prefix so "0812-..." and "+62812..." compare equal
+func normalizedPhone(col exp.IdentifierExpression) exp.LiteralExpression {
+	return goqu.L(`regexp_replace(regexp_replace(COALESCE(?, ''), '\D', '', 'g'), '^(62|0)', '')`, col)
+}
+
+func (a *eraporAdapter) UpdateRaporStatus(ctx context.Context, tenantID string, log *model.RaporStatusLog) (bool, error) {
+	updated := false
+	err := inGoquTx(a.db, func(tx goquQuerier) error {
+		record := goqu.Record{
+			"status":     log.KeStatus,
+			"updated_at": time.Now(),
+		}
+		switch log.KeStatus {
+		case model.RaporStatusPublished:
+			record["published_at"] = time.Now()
+		case model.RaporStatusDraft:
+			record["published_at"] = nil
+		}
+
+		res, err := tx.Update("sekolah_rapor").Set(record).
+			Where(
+				goqu.C("id").Eq(log.RaporID),
+				goqu.C("tenant_id").Eq(tenantID),
+				goqu.COALESCE(goqu.C("status"), model.RaporStatusDraft).Eq(log.DariStatus),
+			).
+			Executor().ExecContext(ctx)
+		if err != nil {
+			return err
+		}
+		if affected, _ := res.RowsAffected(); affected == 0 {
+			return nil
+		}
+
+		entry := goqu.Record{
+			"rapor_id":    log.RaporID,
+			"dari_status": log.DariStatus,
+			"ke_status":   log.KeStatus,
+			"alasan":      log.Alasan,
+		}
+		if log.UserID != "" {
+			entry["user_id"] = log.UserID
+		}
+		_, err = tx.Insert("sekolah_rapor_status_log").Rows(entry).
+			Returning("id", "created_at").
+			Executor().ScanStructContext(ctx, log)
+		if err != nil {
+			return err
+		}
+		updated = true
+		return nil
+	})
+	return updated, err
+}
+
+func (a *eraporAdapter) GetRaporStatusLog(ctx context.Context, raporID string) ([]model.RaporStatusLog, error) {
+	var logs []model.RaporStatusLog
+	err := a.db.From(goqu.T("sekolah_rapor_status_log").As("l")).
+		LeftJoin(goqu.T("users").As("u"), goqu.On(goqu.I("u.id").Eq(goqu.I("l.user_id")))).
+		Select(
+			goqu.I("l.id"),
+			goqu.I("l.rapor_id"),
+			goqu.I("l.dari_status"),
+			goqu.I("l.ke_status"),
+			goqu.COALESCE(goqu.L("l.user_id::text"), "").As("user_id"),
+			goqu.COALESCE(goqu.I("u.name"), "").As("nama_user"),
+			goqu.I("l.alasan"),
+			goqu.I("l.created_at"),
+		).
+		Where(goqu.I("l.rapor_id").Eq(raporID)).
+		Order(goqu.I("l.created_at").Asc()).
+		ScanStructsContext(ctx, &logs)
+	return logs, err
+}
+
+func (a *eraporAdapter) GetRaporStatusBySiswa(ctx context.Context, tenantID, semesterID string, siswaIDs []string) (map[string]string, error) {
+	result := make(map[string]string, len(siswaIDs))
+	if len(siswaIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		SiswaID string `db:"santri_id"`
+		Status  string `db:"status"`
+	}
+	err := a.db.From(goqu.T("sekolah_rapor").As("r")).
+		Join(goqu.T("sekolah_rapor_periode").As("p"), goqu.On(goqu.I("p.id").Eq(goqu.I("r.periode_id")))).
+		Select(
+			goqu.L("r.santri_id::text").As("santri_id"),
+			goqu.COALESCE(goqu.I("r.status"), model.RaporStatusDraft).As("status"),
+		).
+		Where(
+			goqu.I("r.tenant_id").Eq(tenantID),
+			goqu.I("p.nama").Eq(semesterID),
+			goqu.I("r.santri_id").In(siswaIDs),
+		).
+		ScanStructsContext(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		result[r.SiswaID] = r.Status
+	}
+	return result, nil
+}
+
+func (a *eraporAdapter) GetSiswaWali(ctx context.Context, tenantID, siswaID string) (*model.Siswa, error) {
+	var siswa model.Siswa
+	found, err := a.db.From("sekolah_siswa").
+		Select(
+			"id",
+			"tenant_id",
+			"nama",
+			goqu.COALESCE(goqu.C("nama_wali"), "").As("nama_wali"),
+			goqu.COALESCE(goqu.C("no_hp_wali"), "").As("no_hp_wali"),
+		).
+		Where(
+			goqu.C("id").Eq(siswaID),
+			goqu.C("tenant_id").Eq(tenantID),
+		).
+		ScanStructContext(ctx, &siswa)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &siswa, nil
+}
+
+func (a *eraporAdapter) GetRaporWaliList(ctx context.Context, tenantID, userID string) ([]model.RaporWali, error) {
+	var list []model.RaporWali
+	err := a.db.From(goqu.T("sekolah_rapor").As("r")).
+		Join(goqu.T("sekolah_siswa").As("s"), goqu.On(goqu.I("s.id").Eq(goqu.I("r.santri_id")))).
+		Join(goqu.T("sekolah_rapor_periode").As("p"), goqu.On(goqu.I("p.id").Eq(goqu.I("r.periode_id")))).
+		Join(goqu.T("users").As("u"), goqu.On(goqu.I("u.id").Eq(userID))).
+		Select(
+			goqu.I("r.id"),
+			goqu.I("s.id").As("siswa_id"),
+			goqu.I("s.nama").As("nama_siswa"),
+			goqu.I("p.nama").As("semester"),
+			goqu.I("r.published_at"),
+		).
+		Where(
+			goqu.I("r.tenant_id").Eq(tenantID),
+			goqu.I("r.status").Eq(model.RaporStatusPublished),
+			goqu.I("u.tenant_id").Eq(tenantID),
+			goqu.COALESCE(goqu.I("u.whatsapp"), "").Neq(""),
+			normalizedPhone(goqu.I("u.whatsapp")).Eq(normalizedPhone(goqu.I("s.no_hp_wali"))),
+		).
+		Order(goqu.I("r.published_at").Desc()).
+		ScanStructsContext(ctx, &list)
+	return list, err
+}
@@ -22,7 +22,7 @@ func (a *sekolahAdapter) GetRaporList(tenantID string) ([]model.Rapor, error) {
 			goqu.COALESCE(tableRapor.Col("catatan_wali_kelas"), "").As("catatan_wali_kelas"),
 			tableRapor.Col("created_at"),
 			tableRapor.Col("updated_at"),
-			goqu.COALESCE(tableSiswa.Col("nama_lengkap"), "").As("nama_santri"),
+			goqu.COALESCE(tableSiswa.Col("nama"), "").As("nama_santri"),
 			goqu.COALESCE(tableRaporPeriode.Col("nama"), "").As("nama_periode"),
 		).
 		LeftJoin(tableSiswa, goqu.On(tableRapor.Col("santri_id").Eq(tableSiswa.Col("id")))).
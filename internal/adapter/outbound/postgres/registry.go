@@ -1,8 +1,10 @@
 package postgres_outbound_adapter
 
 import (
+	"context"
 	"database/sql"
 
+	"github.com/doug-martin/goqu/v9"
 	"github.com/pkg/errors"
 
 	outbound_port "prabogo/internal/port/outbound"
@@ -19,6 +21,25 @@ func NewAdapter(db *sql.DB) outbound_port.DatabasePort {
 	}
 }
 
+// goquQuerier is implemented by both *goqu.Database and *goqu.TxDatabase,
+// letting goqu-based adapters run inside a registry transaction
+type goquQuerier interface {
+	From(from ...interface{}) *goqu.SelectDataset
+	Insert(table interface{}) *goqu.InsertDataset
+	Update(table interface{}) *goqu.UpdateDataset
+	Delete(table interface{}) *goqu.DeleteDataset
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// inGoquTx runs fn in a new transaction, or directly when the adapter is
+// already bound to a registry transaction
+func inGoquTx(db goquQuerier, fn func(tx goquQuerier) error) error {
+	if database, ok := db.(*goqu.Database); ok {
+		return database.WithTx(func(tx *goqu.TxDatabase) error { return fn(tx) })
+	}
+	return fn(db)
+}
+
 func (s *adapter) DoInTransaction(txFunc outbound_port.InTransaction) (out interface{}, err error) {
 	var tx *sql.Tx
 	reg := s
@@ -125,6 +146,9 @@ func (s *adapter) AuditLog() outbound_port.AuditLogDatabasePort {
 }
 
 func (s *adapter) ERapor() outbound_port.ERaporDatabasePort {
+	if tx, ok := s.dbexecutor.(*sql.Tx); ok {
+		return NewERaporTxAdapter(tx)
+	}
 	return NewERaporAdapter(s.db)
 }
 
@@ -139,3 +163,43 @@ func (s *adapter) Subscription() outbound_port.SubscriptionDatabasePort {
 func (s *adapter) PesantrenDashboard() outbound_port.PesantrenDashboardPort {
 	return NewPesantrenDashboardAdapter(s.db)
 }
+
+func (s *adapter) Ekskul() outbound_port.EkskulDatabasePort {
+	if tx, ok := s.dbexecutor.(*sql.Tx); ok {
+		return NewEkskulTxAdapter(tx)
+	}
+	return NewEkskulAdapter(s.db)
+}
+
+func (s *adapter) Konseling() outbound_port.KonselingDatabasePort {
+	if tx, ok := s.dbexecutor.(*sql.Tx); ok {
+		return NewKonselingTxAdapter(tx)
+	}
+	return NewKonselingAdapter(s.db)
+}
+
+func (s *adapter) Perpustakaan() outbound_port.PerpustakaanDatabasePort {
+	if tx, ok := s.dbexecutor.(*sql.Tx); ok {
+		return NewPerpustakaanTxAdapter(tx)
+	}
+	return NewPerpustakaanAdapter(s.db)
+}
+
+func (s *adapter) PPDB() outbound_port.PPDBDatabasePort {
+	if tx, ok := s.dbexecutor.(*sql.Tx); ok {
+		return NewPPDBTxAdapter(tx)
+	}
+	return NewPPDBAdapter(s.db)
+}
+
+func (s *adapter) Ujian() outbound_port.UjianDatabasePort {
+	return NewUjianAdapter(s.db)
+}
+
+func (s *adapter) Dapodik() outbound_port.DapodikDatabasePort {
+	return NewDapodikAdapter(s.db)
+}
+
+func (s *adapter) Alumni() outbound_port.AlumniDatabasePort {
+	return NewAlumniAdapter(s.db)
+}
@@ -0,0 +1,80 @@
+package postgres_outbound_adapter
+
+import (
+	"context"
+	"time"
+
+	"prabogo/internal/model"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/google/uuid"
+)
+
+// ==========================================
+// REMEDIAL & ENRICHMENT
+// ==========================================
+
+func (a *eraporAdapter) CreateRemedialSession(ctx context.Context, r *model.RemedialSession) error {
+	r.ID = uuid.New().String()
+	r.CreatedAt = time.Now()
+
+	record := goqu.Record{
+		"id":             r.ID,
+		"tenant_id":      r.TenantID,
+		"student_id":     r.StudentID,
+		"subject_id":     r.SubjectID,
+		"semester_id":    r.SemesterID,
+		"type":           r.Type,
+		"date":           r.Date,
+		"topic":          r.Topic,
+		"kkm":            r.KKM,
+		"original_score": r.OriginalScore,
+		"remedial_score": r.RemedialScore,
+		"final_score":    r.FinalScore,
+		"capped":         r.Capped,
+		"notes":          r.Notes,
+		"created_at":     r.CreatedAt,
+	}
+	if r.CreatedBy != "" {
+		record["created_by"] = r.CreatedBy
+	}
+	_, err := a.db.Insert("remedial_sessions").Rows(record).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *eraporAdapter) GetRemedialSessions(ctx context.Context, tenantID, subjectID, semesterID, studentID string) ([]model.RemedialSession, error) {
+	ds := a.db.From(goqu.T("remedial_sessions").As("r")).
+		LeftJoin(goqu.T("sekolah_siswa").As("s"), goqu.On(goqu.I("s.id").Eq(goqu.I("r.student_id")))).
+		Select(
+			goqu.I("r.id"),
+			goqu.I("r.tenant_id"),
+			goqu.I("r.student_id"),
+			goqu.COALESCE(goqu.I("s.nama"), "").As("student_name"),
+			goqu.I("r.subject_id"),
+			goqu.I("r.semester_id"),
+			goqu.I("r.type"),
+			goqu.I("r.date"),
+			goqu.I("r.topic"),
+			goqu.I("r.kkm"),
+			goqu.I("r.original_score"),
+			goqu.I("r.remedial_score"),
+			goqu.I("r.final_score"),
+			goqu.I("r.capped"),
+			goqu.I("r.notes"),
+			goqu.COALESCE(goqu.L("r.created_by::text"), "").As("created_by"),
+			goqu.I("r.created_at"),
+		).
+		Where(
+			goqu.I("r.tenant_id").Eq(tenantID),
+			goqu.I("r.subject_id").Eq(subjectID),
+			goqu.I("r.semester_id").Eq(semesterID),
+		)
+	if studentID != "" {
+		ds = ds.Where(goqu.I("r.student_id").Eq(studentID))
+	}
+
+	var sessions []model.RemedialSession
+	err := ds.Order(goqu.I("r.date").Asc(), goqu.I("r.created_at").Asc()).
+		ScanStructsContext(ctx, &sessions)
+	return sessions, err
+}
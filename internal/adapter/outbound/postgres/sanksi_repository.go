@@ -0,0 +1,323 @@
+package postgres_outbound_adapter
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+
+	"prabogo/internal/model"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+var (
+	tableSanksiAmbang = goqu.T("sekolah_sanksi_ambang")
+	tablePoinKredit   = goqu.T("sekolah_poin_kredit")
+	tableSanksi       = goqu.T("sekolah_sanksi")
+)
+
+// ------ Thresholds ------
+
+func (a *sekolahAdapter) GetSanksiAmbang(tenantID string) ([]model.SanksiAmbang, error) {
+	query, _, err := goqu.Dialect("postgres").From(tableSanksiAmbang).
+		Select("id", "tenant_id", "poin", "jenis", goqu.COALESCE(goqu.C("keterangan"), ""), "created_at", "updated_at").
+		Where(goqu.Ex{"tenant_id": tenantID}).
+		Order(goqu.I("poin").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := a.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []model.SanksiAmbang
+	for rows.Next() {
+		var m model.SanksiAmbang
+		if err := rows.Scan(&m.ID, &m.TenantID, &m.Poin, &m.Jenis, &m.Keterangan, &m.CreatedAt, &m.UpdatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, m)
+	}
+	return list, rows.Err()
+}
+
+// ReplaceSanksiAmbang swaps the tenant's whole threshold ladder; an empty list returns the tenant
+// to the built-in one
+func (a *sekolahAdapter) ReplaceSanksiAmbang(tenantID string, list []model.SanksiAmbang) error {
+	dialect := goqu.Dialect("postgres")
+	query, _, err := dialect.Delete(tableSanksiAmbang).Where(goqu.Ex{"tenant_id": tenantID}).ToSQL()
+	if err != nil {
+		return err
+	}
+	if _, err := a.db.Exec(query); err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		return nil
+	}
+
+	rows := make([]interface{}, len(list))
+	for i, m := range list {
+		rows[i] = goqu.Record{
+			"tenant_id":  tenantID,
+			"poin":       m.Poin,
+			"jenis":      m.Jenis,
+			"keterangan": m.Keterangan,
+		}
+	}
+	query, _, err = dialect.Insert(tableSanksiAmbang).Rows(rows...).ToSQL()
+	if err != nil {
+		return err
+	}
+	_, err = a.db.Exec(query)
+	return err
+}
+
+// ------ Credits ------
+
+// GetPoinKredit lists the credits dated within [from, to], of one santri or of every santri when
+// santriID is empty
+func (a *sekolahAdapter) GetPoinKredit(tenantID, santriID string, from, to time.Time) ([]model.PoinKredit, error) {
+	dataset := goqu.Dialect("postgres").From(tablePoinKredit).
+		Join(tableSiswa, goqu.On(tablePoinKredit.Col("santri_id").Eq(tableSiswa.Col("id")))).
+		Select(
+			tablePoinKredit.Col("id"),
+			tablePoinKredit.Col("tenant_id"),
+			tablePoinKredit.Col("santri_id"),
+			tableSiswa.Col("nama"),
+			tablePoinKredit.Col("tanggal"),
+			tablePoinKredit.Col("poin"),
+			tablePoinKredit.Col("keterangan"),
+			tablePoinKredit.Col("created_at"),
+		).
+		Where(
+			tablePoinKredit.Col("tenant_id").Eq(tenantID),
+			tablePoinKredit.Col("tanggal").Between(goqu.Range(from, to)),
+		).
+		Order(tablePoinKredit.Col("tanggal").Desc())
+	if santriID != "" {
+		dataset = dataset.Where(tablePoinKredit.Col("santri_id").Eq(santriID))
+	}
+
+	query, _, err := dataset.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := a.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []model.PoinKredit
+	for rows.Next() {
+		var m model.PoinKredit
+		if err := rows.Scan(&m.ID, &m.TenantID, &m.SantriID, &m.SantriNama, &m.Tanggal, &m.Poin, &m.Keterangan, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, m)
+	}
+	return list, rows.Err()
+}
+
+func (a *sekolahAdapter) CreatePoinKredit(m *model.PoinKredit) error {
+	query, _, err := goqu.Dialect("postgres").Insert(tablePoinKredit).Rows(goqu.Record{
+		"tenant_id":  m.TenantID,
+		"santri_id":  m.SantriID,
+		"tanggal":    m.Tanggal,
+		"poin":       m.Poin,
+		"keterangan": m.Keterangan,
+	}).Returning("id", "created_at").ToSQL()
+	if err != nil {
+		return err
+	}
+	return a.db.QueryRow(query).Scan(&m.ID, &m.CreatedAt)
+}
+
+// GetRekapPoin sums the violation and credit points of every santri with either in [from, to]
+func (a *sekolahAdapter) GetRekapPoin(tenantID string, from, to time.Time) ([]model.RekapPoin, error) {
+	dialect := goqu.Dialect("postgres")
+	pelanggaran := dialect.From(tablePelanggaranSiswa).
+		Select(goqu.C("santri_id"), goqu.SUM("poin").As("poin")).
+		Where(goqu.C("tenant_id").Eq(tenantID), goqu.C("tanggal").Between(goqu.Range(from, to))).
+		GroupBy("santri_id")
+	kredit := dialect.From(tablePoinKredit).
+		Select(goqu.C("santri_id"), goqu.SUM("poin").As("poin")).
+		Where(goqu.C("tenant_id").Eq(tenantID), goqu.C("tanggal").Between(goqu.Range(from, to))).
+		GroupBy("santri_id")
+
+	query, _, err := dialect.From(tableSiswa).
+		LeftJoin(pelanggaran.As("p"), goqu.On(goqu.I("p.santri_id").Eq(tableSiswa.Col("id")))).
+		LeftJoin(kredit.As("k"), goqu.On(goqu.I("k.santri_id").Eq(tableSiswa.Col("id")))).
+		Select(
+			tableSiswa.Col("id"),
+			tableSiswa.Col("nama"),
+			goqu.COALESCE(tableSiswa.Col("cached_kelas_nama"), ""),
+			goqu.COALESCE(goqu.I("p.poin"), 0),
+			goqu.COALESCE(goqu.I("k.poin"), 0),
+		).
+		Where(
+			tableSiswa.Col("tenant_id").Eq(tenantID),
+			goqu.Or(goqu.I("p.poin").IsNotNull(), goqu.I("k.poin").IsNotNull()),
+		).
+		Order(tableSiswa.Col("nama").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := a.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []model.RekapPoin
+	for rows.Next() {
+		var m model.RekapPoin
+		if err := rows.Scan(&m.SantriID, &m.SantriNama, &m.KelasNama, &m.PoinPelanggaran, &m.PoinKredit); err != nil {
+			return nil, err
+		}
+		list = append(list, m)
+	}
+	return list, rows.Err()
+}
+
+// ------ Sanctions ------
+
+func (a *sekolahAdapter) getSanksi(where ...goqu.Expression) ([]model.Sanksi, error) {
+	query, _, err := goqu.Dialect("postgres").From(tableSanksi).
+		Join(tableSiswa, goqu.On(tableSanksi.Col("santri_id").Eq(tableSiswa.Col("id")))).
+		Select(
+			tableSanksi.Col("id"),
+			tableSanksi.Col("tenant_id"),
+			tableSanksi.Col("santri_id"),
+			tableSiswa.Col("nama"),
+			goqu.COALESCE(tableSiswa.Col("cached_kelas_nama"), ""),
+			tableSanksi.Col("semester_id"),
+			tableSanksi.Col("ambang_poin"),
+			tableSanksi.Col("jenis"),
+			tableSanksi.Col("total_poin"),
+			tableSanksi.Col("nomor_surat"),
+			tableSanksi.Col("status"),
+			goqu.COALESCE(tableSanksi.Col("catatan"), ""),
+			tableSanksi.Col("wali_dikabari_at"),
+			tableSanksi.Col("selesai_at"),
+			tableSanksi.Col("created_at"),
+			tableSanksi.Col("updated_at"),
+		).
+		Where(where...).
+		Order(tableSanksi.Col("created_at").Desc()).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := a.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []model.Sanksi
+	for rows.Next() {
+		var m model.Sanksi
+		var dikabari, selesai sql.NullTime
+		if err := rows.Scan(&m.ID, &m.TenantID, &m.SantriID, &m.SantriNama, &m.KelasNama, &m.SemesterID,
+			&m.AmbangPoin, &m.Jenis, &m.TotalPoin, &m.NomorSurat, &m.Status, &m.Catatan,
+			&dikabari, &selesai, &m.CreatedAt, &m.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if dikabari.Valid {
+			m.WaliDikabariAt = &dikabari.Time
+		}
+		if selesai.Valid {
+			m.SelesaiAt = &selesai.Time
+		}
+		list = append(list, m)
+	}
+	return list, rows.Err()
+}
+
+// GetSanksi lists the tenant's sanctions; empty filters match everything
+func (a *sekolahAdapter) GetSanksi(tenantID, santriID, semesterID, status string) ([]model.Sanksi, error) {
+	where := []goqu.Expression{tableSanksi.Col("tenant_id").Eq(tenantID)}
+	if santriID != "" {
+		where = append(where, tableSanksi.Col("santri_id").Eq(santriID))
+	}
+	if semesterID != "" {
+		where = append(where, tableSanksi.Col("semester_id").Eq(semesterID))
+	}
+	if status != "" {
+		where = append(where, tableSanksi.Col("status").Eq(status))
+	}
+	return a.getSanksi(where...)
+}
+
+func (a *sekolahAdapter) GetSanksiByID(tenantID, id string) (*model.Sanksi, error) {
+	list, err := a.getSanksi(tableSanksi.Col("tenant_id").Eq(tenantID), tableSanksi.Col("id").Eq(id))
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+	return &list[0], nil
+}
+
+// CreateSanksi stores a sanction unless the santri already has one for the threshold in the
+// semester; created reports which happened
+func (a *sekolahAdapter) CreateSanksi(m *model.Sanksi) (bool, error) {
+	query, _, err := goqu.Dialect("postgres").Insert(tableSanksi).Rows(goqu.Record{
+		"tenant_id":   m.TenantID,
+		"santri_id":   m.SantriID,
+		"semester_id": m.SemesterID,
+		"ambang_poin": m.AmbangPoin,
+		"jenis":       m.Jenis,
+		"total_poin":  m.TotalPoin,
+		"nomor_surat": m.NomorSurat,
+		"status":      m.Status,
+		"catatan":     m.Catatan,
+	}).OnConflict(goqu.DoNothing()).Returning("id", "created_at", "updated_at").ToSQL()
+	if err != nil {
+		return false, err
+	}
+	err = a.db.QueryRow(query).Scan(&m.ID, &m.CreatedAt, &m.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (a *sekolahAdapter) UpdateSanksi(m *model.Sanksi) error {
+	query, _, err := goqu.Dialect("postgres").Update(tableSanksi).Set(goqu.Record{
+		"status":           m.Status,
+		"catatan":          m.Catatan,
+		"wali_dikabari_at": m.WaliDikabariAt,
+		"selesai_at":       m.SelesaiAt,
+	}).Where(
+		tableSanksi.Col("tenant_id").Eq(m.TenantID),
+		tableSanksi.Col("id").Eq(m.ID),
+	).ToSQL()
+	if err != nil {
+		return err
+	}
+	_, err = a.db.Exec(query)
+	return err
+}
+
+// NextNomorSanksi reserves the next warning letter number of the tenant in a calendar year
+func (a *sekolahAdapter) NextNomorSanksi(tenantID string, year int) (int, error) {
+	dialect := goqu.Dialect("postgres")
+	seed := dialect.From(tableSanksi).
+		Select(goqu.COUNT("*")).
+		Where(
+			goqu.C("tenant_id").Eq(tenantID),
+			goqu.L("EXTRACT(YEAR FROM created_at)").Eq(year),
+		)
+	query, _, err := nomorUrutInsert(dialect.Insert("sekolah_nomor_urut"), tenantID, nomorUrutSanksi, strconv.Itoa(year), seed).ToSQL()
+	if err != nil {
+		return 0, err
+	}
+	var urut int
+	err = a.db.QueryRow(query).Scan(&urut)
+	return urut, err
+}
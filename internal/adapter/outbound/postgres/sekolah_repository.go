@@ -6,6 +6,7 @@ import (
 	outbound_port "prabogo/internal/port/outbound"
 
 	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
 )
 
 var (
@@ -37,10 +38,28 @@ func NewSekolahAdapter(
 
 func (a *sekolahAdapter) GetSiswaByTenant(tenantID string) ([]model.Siswa, error) {
 	dialect := goqu.Dialect("postgres")
-	// Explicit select to ensure order and handle NULLs if needed via COALESCE or just matching types
+	// Explicit select with COALESCE so nullable columns scan into plain strings
 	dataset := dialect.From(tableSiswa).Select(
-		"id", "tenant_id", "nis", "nama", "kelas_id", "kelas_nama", "alamat", "nama_wali", "no_hp_wali", "status",
-	).Where(goqu.Ex{"tenant_id": tenantID})
+		"id", "tenant_id",
+		goqu.COALESCE(goqu.C("nis"), ""),
+		"nama",
+		goqu.COALESCE(goqu.L("kelas_id::text"), ""),
+		goqu.COALESCE(goqu.C("cached_kelas_nama"), ""),
+		goqu.COALESCE(goqu.C("alamat"), ""),
+		goqu.COALESCE(goqu.C("nama_wali"), ""),
+		goqu.COALESCE(goqu.C("no_hp_wali"), ""),
+		goqu.COALESCE(goqu.C("status"), ""),
+		goqu.COALESCE(goqu.C("nisn"), ""),
+		goqu.COALESCE(goqu.C("nik"), ""),
+		goqu.COALESCE(goqu.C("jenis_kelamin"), ""),
+		goqu.COALESCE(goqu.C("tempat_lahir"), ""),
+		"tanggal_lahir",
+		goqu.COALESCE(goqu.C("agama"), ""),
+		goqu.COALESCE(goqu.C("nama_ayah"), ""),
+		goqu.COALESCE(goqu.C("pekerjaan_ayah"), ""),
+		goqu.COALESCE(goqu.C("nama_ibu"), ""),
+		goqu.COALESCE(goqu.C("pekerjaan_ibu"), ""),
+	).Where(goqu.Ex{"tenant_id": tenantID}).Order(goqu.C("nama").Asc())
 
 	query, _, err := dataset.ToSQL()
 	if err != nil {
@@ -56,32 +75,80 @@ func (a *sekolahAdapter) GetSiswaByTenant(tenantID string) ([]model.Siswa, error
 	var siswaList []model.Siswa
 	for rows.Next() {
 		var s model.Siswa
-		// Scan into sql.NullString for nullable columns to be safe, then assign to struct
-		var namaWali, noHpWali sql.NullString
-		err := rows.Scan(&s.ID, &s.TenantID, &s.NIS, &s.Nama, &s.KelasID, &s.KelasNama, &s.Alamat, &namaWali, &noHpWali, &s.Status)
+		var tanggalLahir sql.NullTime
+		err := rows.Scan(&s.ID, &s.TenantID, &s.NIS, &s.Nama, &s.KelasID, &s.KelasNama, &s.Alamat, &s.NamaWali, &s.NoHPWali, &s.Status,
+			&s.NISN, &s.NIK, &s.JenisKelamin, &s.TempatLahir, &tanggalLahir, &s.Agama, &s.NamaAyah, &s.PekerjaanAyah, &s.NamaIbu, &s.PekerjaanIbu)
 		if err != nil {
 			return nil, err
 		}
-		s.NamaWali = namaWali.String
-		s.NoHPWali = noHpWali.String
+		if tanggalLahir.Valid {
+			s.TanggalLahir = &tanggalLahir.Time
+		}
 		siswaList = append(siswaList, s)
 	}
 
 	return siswaList, nil
 }
 
+// GetSiswaByID returns the siswa with the fields workflows need (kelas, gender, wali contact);
+// nil when the siswa does not belong to the tenant
+func (a *sekolahAdapter) GetSiswaByID(tenantID, id string) (*model.Siswa, error) {
+	dialect := goqu.Dialect("postgres")
+	dataset := dialect.From(tableSiswa).Select(
+		"id", "tenant_id",
+		goqu.COALESCE(goqu.C("nis"), ""),
+		"nama",
+		goqu.COALESCE(goqu.L("kelas_id::text"), ""),
+		goqu.COALESCE(goqu.C("cached_kelas_nama"), ""),
+		goqu.COALESCE(goqu.C("nama_wali"), ""),
+		goqu.COALESCE(goqu.C("no_hp_wali"), ""),
+		goqu.COALESCE(goqu.C("status"), ""),
+		goqu.COALESCE(goqu.C("jenis_kelamin"), ""),
+		"tanggal_lahir",
+	).Where(goqu.Ex{"tenant_id": tenantID, "id": id})
+
+	query, _, err := dataset.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	var s model.Siswa
+	var tanggalLahir sql.NullTime
+	err = a.db.QueryRow(query).Scan(&s.ID, &s.TenantID, &s.NIS, &s.Nama, &s.KelasID, &s.KelasNama,
+		&s.NamaWali, &s.NoHPWali, &s.Status, &s.JenisKelamin, &tanggalLahir)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if tanggalLahir.Valid {
+		s.TanggalLahir = &tanggalLahir.Time
+	}
+	return &s, nil
+}
+
 func (a *sekolahAdapter) CreateSiswa(siswa model.Siswa) error {
 	dialect := goqu.Dialect("postgres")
 	dataset := dialect.Insert(tableSiswa).Rows(goqu.Record{
-		"tenant_id":  siswa.TenantID,
-		"nis":        siswa.NIS,
-		"nama":       siswa.Nama,
-		"kelas_id":   siswa.KelasID,
-		"kelas_nama": siswa.KelasNama,
-		"alamat":     siswa.Alamat,
-		"nama_wali":  siswa.NamaWali,
-		"no_hp_wali": siswa.NoHPWali,
-		"status":     siswa.Status,
+		"tenant_id":      siswa.TenantID,
+		"nis":            siswa.NIS,
+		"nama":           siswa.Nama,
+		"kelas_id":       nullableUUID(siswa.KelasID),
+		"alamat":         siswa.Alamat,
+		"nama_wali":      siswa.NamaWali,
+		"no_hp_wali":     siswa.NoHPWali,
+		"status":         siswa.Status,
+		"nisn":           siswa.NISN,
+		"nik":            siswa.NIK,
+		"jenis_kelamin":  siswa.JenisKelamin,
+		"tempat_lahir":   siswa.TempatLahir,
+		"tanggal_lahir":  siswa.TanggalLahir,
+		"agama":          siswa.Agama,
+		"nama_ayah":      siswa.NamaAyah,
+		"pekerjaan_ayah": siswa.PekerjaanAyah,
+		"nama_ibu":       siswa.NamaIbu,
+		"pekerjaan_ibu":  siswa.PekerjaanIbu,
 	}).Returning("id")
 
 	query, _, err := dataset.ToSQL()
@@ -96,7 +163,19 @@ func (a *sekolahAdapter) CreateSiswa(siswa model.Siswa) error {
 
 func (a *sekolahAdapter) GetGuruByTenant(tenantID string) ([]model.Guru, error) {
 	dialect := goqu.Dialect("postgres")
-	dataset := dialect.From(tableGuru).Where(goqu.Ex{"tenant_id": tenantID})
+	dataset := dialect.From(tableGuru).Select(
+		"id", "tenant_id",
+		goqu.COALESCE(goqu.C("nip"), ""),
+		"nama",
+		goqu.COALESCE(goqu.C("jenis"), ""),
+		goqu.COALESCE(goqu.C("status"), ""),
+		goqu.COALESCE(goqu.C("nuptk"), ""),
+		goqu.COALESCE(goqu.C("nik"), ""),
+		goqu.COALESCE(goqu.C("jenis_kelamin"), ""),
+		goqu.COALESCE(goqu.C("tempat_lahir"), ""),
+		"tanggal_lahir",
+		goqu.COALESCE(goqu.C("no_hp"), ""),
+	).Where(goqu.Ex{"tenant_id": tenantID}).Order(goqu.C("nama").Asc())
 
 	query, _, err := dataset.ToSQL()
 	if err != nil {
@@ -112,23 +191,64 @@ func (a *sekolahAdapter) GetGuruByTenant(tenantID string) ([]model.Guru, error)
 	var guruList []model.Guru
 	for rows.Next() {
 		var g model.Guru
-		err := rows.Scan(&g.ID, &g.TenantID, &g.NIP, &g.Nama, &g.Jenis, &g.Status)
+		var tanggalLahir sql.NullTime
+		err := rows.Scan(&g.ID, &g.TenantID, &g.NIP, &g.Nama, &g.Jenis, &g.Status,
+			&g.NUPTK, &g.NIK, &g.JenisKelamin, &g.TempatLahir, &tanggalLahir, &g.NoHP)
 		if err != nil {
 			return nil, err
 		}
+		if tanggalLahir.Valid {
+			g.TanggalLahir = &tanggalLahir.Time
+		}
 		guruList = append(guruList, g)
 	}
 	return guruList, nil
 }
 
+// GetGuruByUser links the login to a guru by WhatsApp number, the same way wali kelas are
+// recognised
+func (a *sekolahAdapter) GetGuruByUser(tenantID, userID string) (*model.Guru, error) {
+	query, _, err := goqu.Dialect("postgres").From(tableGuru).
+		Join(goqu.T("users").As("u"), goqu.On(goqu.I("u.id").Eq(userID))).
+		Select(tableGuru.Col("id"), tableGuru.Col("tenant_id"), tableGuru.Col("nama")).
+		Where(
+			tableGuru.Col("tenant_id").Eq(tenantID),
+			goqu.I("u.tenant_id").Eq(tenantID),
+			goqu.COALESCE(goqu.I("u.whatsapp"), "").Neq(""),
+			normalizedPhone(goqu.I("u.whatsapp")).Eq(normalizedPhone(tableGuru.Col("no_hp"))),
+		).
+		Order(tableGuru.Col("nama").Asc()).
+		Limit(1).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	var g model.Guru
+	err = a.db.QueryRow(query).Scan(&g.ID, &g.TenantID, &g.Nama)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
 func (a *sekolahAdapter) CreateGuru(guru model.Guru) error {
 	dialect := goqu.Dialect("postgres")
 	dataset := dialect.Insert(tableGuru).Rows(goqu.Record{
-		"tenant_id": guru.TenantID,
-		"nip":       guru.NIP,
-		"nama":      guru.Nama,
-		"jenis":     guru.Jenis,
-		"status":    guru.Status,
+		"tenant_id":     guru.TenantID,
+		"nip":           guru.NIP,
+		"nama":          guru.Nama,
+		"jenis":         guru.Jenis,
+		"status":        guru.Status,
+		"nuptk":         guru.NUPTK,
+		"nik":           guru.NIK,
+		"jenis_kelamin": guru.JenisKelamin,
+		"tempat_lahir":  guru.TempatLahir,
+		"tanggal_lahir": guru.TanggalLahir,
+		"no_hp":         guru.NoHP,
 	}).Returning("id")
 
 	query, _, err := dataset.ToSQL()
@@ -172,7 +292,22 @@ func (a *sekolahAdapter) GetMapelByTenant(tenantID string) ([]model.Mapel, error
 
 func (a *sekolahAdapter) GetKelasByTenant(tenantID string) ([]model.Kelas, error) {
 	dialect := goqu.Dialect("postgres")
-	dataset := dialect.From("sekolah_kelas").Where(goqu.Ex{"tenant_id": tenantID}).Order(goqu.I("urutan").Asc())
+	tableKelas := goqu.T("sekolah_kelas")
+	dataset := dialect.From(tableKelas).
+		LeftJoin(tableGuru, goqu.On(tableKelas.Col("wali_kelas_id").Eq(tableGuru.Col("id")))).
+		Select(
+			tableKelas.Col("id"),
+			tableKelas.Col("tenant_id"),
+			tableKelas.Col("nama"),
+			tableKelas.Col("tingkat"),
+			goqu.COALESCE(tableKelas.Col("urutan"), 0),
+			goqu.COALESCE(tableKelas.Col("status"), ""),
+			goqu.COALESCE(goqu.L("sekolah_kelas.wali_kelas_id::text"), ""),
+			goqu.COALESCE(tableGuru.Col("nama"), ""),
+			goqu.COALESCE(tableKelas.Col("kurikulum"), ""),
+		).
+		Where(tableKelas.Col("tenant_id").Eq(tenantID)).
+		Order(tableKelas.Col("urutan").Asc())
 
 	query, _, err := dataset.ToSQL()
 	if err != nil {
@@ -188,13 +323,7 @@ func (a *sekolahAdapter) GetKelasByTenant(tenantID string) ([]model.Kelas, error
 	var kelasList []model.Kelas
 	for rows.Next() {
 		var k model.Kelas
-		// Columns: id, tenant_id, nama, tingkat, urutan, status, created_at, updated_at
-		// Need to scan properly. Assuming created_at/updated_at are ignored for now in struct,
-		// but Scan must match table columns or we select specific columns.
-		// Safer to assume SELECT * returns all, so we must scan all or change query to SELECT cols.
-		// Let's change query to select explicit columns to be safe.
-		// Re-writing query logic below.
-		err := rows.Scan(&k.ID, &k.TenantID, &k.Nama, &k.Tingkat, &k.Urutan, &k.Status, &sql.NullTime{}, &sql.NullTime{})
+		err := rows.Scan(&k.ID, &k.TenantID, &k.Nama, &k.Tingkat, &k.Urutan, &k.Status, &k.WaliKelasID, &k.WaliKelasNama, &k.Kurikulum)
 		if err != nil {
 			return nil, err
 		}
@@ -206,11 +335,13 @@ func (a *sekolahAdapter) GetKelasByTenant(tenantID string) ([]model.Kelas, error
 func (a *sekolahAdapter) CreateKelas(kelas *model.Kelas) error {
 	dialect := goqu.Dialect("postgres")
 	dataset := dialect.Insert("sekolah_kelas").Rows(goqu.Record{
-		"tenant_id": kelas.TenantID,
-		"nama":      kelas.Nama,
-		"tingkat":   kelas.Tingkat,
-		"urutan":    kelas.Urutan,
-		"status":    kelas.Status,
+		"tenant_id":     kelas.TenantID,
+		"nama":          kelas.Nama,
+		"tingkat":       kelas.Tingkat,
+		"urutan":        kelas.Urutan,
+		"status":        kelas.Status,
+		"wali_kelas_id": nullableUUID(kelas.WaliKelasID),
+		"kurikulum":     kelas.Kurikulum,
 	}).Returning("id")
 
 	query, _, err := dataset.ToSQL()
@@ -289,6 +420,43 @@ func (a *sekolahAdapter) CreateAsrama(m *model.Asrama) error {
 }
 
 func (a *sekolahAdapter) GetKamarByAsrama(tenantID, asramaID string) ([]model.Kamar, error) {
+	where := []goqu.Expression{tableKamar.Col("tenant_id").Eq(tenantID)}
+	if asramaID != "" {
+		where = append(where, tableKamar.Col("asrama_id").Eq(asramaID))
+	}
+	return a.getKamar(where...)
+}
+
+// GetKamarByID returns nil when the room does not belong to the tenant. Inside a transaction
+// it locks the room so concurrent placements cannot over-fill it. Terisi is counted by a
+// statement of its own after the lock, so it includes placements committed while waiting.
+func (a *sekolahAdapter) GetKamarByID(tenantID, id string) (*model.Kamar, error) {
+	where := []goqu.Expression{tableKamar.Col("tenant_id").Eq(tenantID), tableKamar.Col("id").Eq(id)}
+	query, _, err := goqu.Dialect("postgres").From(tableKamar).
+		Select(tableKamar.Col("id")).
+		Where(where...).
+		ForUpdate(exp.Wait).
+		ToSQL()
+	if err != nil {
+		return nil, err
+	}
+	var locked string
+	err = a.db.QueryRow(query).Scan(&locked)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := a.getKamar(where...)
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+	return &list[0], nil
+}
+
+func (a *sekolahAdapter) getKamar(where ...goqu.Expression) ([]model.Kamar, error) {
 	dialect := goqu.Dialect("postgres")
 	dataset := dialect.From(tableKamar).
 		Join(tableAsrama, goqu.On(tableKamar.Col("asrama_id").Eq(tableAsrama.Col("id")))).
@@ -297,25 +465,21 @@ func (a *sekolahAdapter) GetKamarByAsrama(tenantID, asramaID string) ([]model.Ka
 			tableKamar.Col("tenant_id"),
 			tableKamar.Col("asrama_id"),
 			tableAsrama.Col("nama").As("asrama_nama"),
+			tableAsrama.Col("jenis").As("asrama_jenis"),
 			tableKamar.Col("nomor"),
 			tableKamar.Col("kapasitas"),
-			tableKamar.Col("status"),
+			goqu.COALESCE(tableKamar.Col("status"), "").As("status"),
 			// Subquery for occupied count
 			dialect.From(tablePenempatan).
 				Select(goqu.COUNT("*")).
 				Where(
 					tablePenempatan.Col("kamar_id").Eq(tableKamar.Col("id")),
-					tablePenempatan.Col("status").Eq("Aktif"),
+					tablePenempatan.Col("status").Eq(model.PenempatanAktif),
 				).As("terisi"),
 			tableKamar.Col("created_at"),
 			tableKamar.Col("updated_at"),
-		).Where(
-		tableKamar.Col("tenant_id").Eq(tenantID),
-		goqu.Or(
-			goqu.L("? = ''", asramaID), // If asramaID empty, return all
-			tableKamar.Col("asrama_id").Eq(asramaID),
-		),
-	).Order(tableKamar.Col("nomor").Asc())
+		).Where(where...).
+		Order(tableAsrama.Col("nama").Asc(), tableKamar.Col("nomor").Asc())
 
 	query, _, err := dataset.ToSQL()
 	if err != nil {
@@ -331,12 +495,12 @@ func (a *sekolahAdapter) GetKamarByAsrama(tenantID, asramaID string) ([]model.Ka
 	var list []model.Kamar
 	for rows.Next() {
 		var m model.Kamar
-		if err := rows.Scan(&m.ID, &m.TenantID, &m.AsramaID, &m.AsramaNama, &m.Nomor, &m.Kapasitas, &m.Status, &m.Terisi, &m.CreatedAt, &m.UpdatedAt); err != nil {
+		if err := rows.Scan(&m.ID, &m.TenantID, &m.AsramaID, &m.AsramaNama, &m.AsramaJenis, &m.Nomor, &m.Kapasitas, &m.Status, &m.Terisi, &m.CreatedAt, &m.UpdatedAt); err != nil {
 			return nil, err
 		}
 		list = append(list, m)
 	}
-	return list, nil
+	return list, rows.Err()
 }
 
 func (a *sekolahAdapter) CreateKamar(m *model.Kamar) error {
@@ -357,6 +521,31 @@ func (a *sekolahAdapter) CreateKamar(m *model.Kamar) error {
 }
 
 func (a *sekolahAdapter) GetPenempatanByTenant(tenantID string) ([]model.Penempatan, error) {
+	return a.getPenempatan(tablePenempatan.Col("tenant_id").Eq(tenantID))
+}
+
+// GetPenempatanBySantri lists every placement of a santri, the move history, newest first
+func (a *sekolahAdapter) GetPenempatanBySantri(tenantID, santriID string) ([]model.Penempatan, error) {
+	return a.getPenempatan(
+		tablePenempatan.Col("tenant_id").Eq(tenantID),
+		tablePenempatan.Col("santri_id").Eq(santriID),
+	)
+}
+
+// GetPenempatanAktif returns the santri's current placement, nil when they have no room
+func (a *sekolahAdapter) GetPenempatanAktif(tenantID, santriID string) (*model.Penempatan, error) {
+	list, err := a.getPenempatan(
+		tablePenempatan.Col("tenant_id").Eq(tenantID),
+		tablePenempatan.Col("santri_id").Eq(santriID),
+		tablePenempatan.Col("status").Eq(model.PenempatanAktif),
+	)
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+	return &list[0], nil
+}
+
+func (a *sekolahAdapter) getPenempatan(where ...goqu.Expression) ([]model.Penempatan, error) {
 	dialect := goqu.Dialect("postgres")
 	dataset := dialect.From(tablePenempatan).
 		Join(tableSiswa, goqu.On(tablePenempatan.Col("santri_id").Eq(tableSiswa.Col("id")))).
@@ -369,14 +558,16 @@ func (a *sekolahAdapter) GetPenempatanByTenant(tenantID string) ([]model.Penempa
 			tableSiswa.Col("nama").As("santri_nama"),
 			tablePenempatan.Col("kamar_id"),
 			tableKamar.Col("nomor").As("kamar_nomor"),
+			tableAsrama.Col("id").As("asrama_id"),
 			tableAsrama.Col("nama").As("asrama_nama"),
 			tablePenempatan.Col("tanggal_masuk"),
+			tablePenempatan.Col("tanggal_keluar"),
 			tablePenempatan.Col("status"),
 			goqu.COALESCE(tablePenempatan.Col("keterangan"), "").As("keterangan"),
 			tablePenempatan.Col("created_at"),
 			tablePenempatan.Col("updated_at"),
-		).Where(tablePenempatan.Col("tenant_id").Eq(tenantID)).
-		Order(tablePenempatan.Col("created_at").Desc())
+		).Where(where...).
+		Order(tablePenempatan.Col("tanggal_masuk").Desc(), tablePenempatan.Col("created_at").Desc())
 
 	query, _, err := dataset.ToSQL()
 	if err != nil {
@@ -392,12 +583,17 @@ func (a *sekolahAdapter) GetPenempatanByTenant(tenantID string) ([]model.Penempa
 	var list []model.Penempatan
 	for rows.Next() {
 		var m model.Penempatan
-		if err := rows.Scan(&m.ID, &m.TenantID, &m.SantriID, &m.SantriNama, &m.KamarID, &m.KamarNomor, &m.AsramaNama, &m.TanggalMasuk, &m.Status, &m.Keterangan, &m.CreatedAt, &m.UpdatedAt); err != nil {
+		var tanggalKeluar sql.NullTime
+		if err := rows.Scan(&m.ID, &m.TenantID, &m.SantriID, &m.SantriNama, &m.KamarID, &m.KamarNomor, &m.AsramaID, &m.AsramaNama,
+			&m.TanggalMasuk, &tanggalKeluar, &m.Status, &m.Keterangan, &m.CreatedAt, &m.UpdatedAt); err != nil {
 			return nil, err
 		}
+		if tanggalKeluar.Valid {
+			m.TanggalKeluar = &tanggalKeluar.Time
+		}
 		list = append(list, m)
 	}
-	return list, nil
+	return list, rows.Err()
 }
 
 func (a *sekolahAdapter) CreatePenempatan(m *model.Penempatan) error {
@@ -417,3 +613,28 @@ func (a *sekolahAdapter) CreatePenempatan(m *model.Penempatan) error {
 	}
 	return a.db.QueryRow(query).Scan(&m.ID, &m.CreatedAt, &m.UpdatedAt)
 }
+
+// TutupPenempatan closes a placement when the santri moves room or leaves the asrama
+func (a *sekolahAdapter) TutupPenempatan(m *model.Penempatan) error {
+	query, _, err := goqu.Dialect("postgres").Update(tablePenempatan).Set(goqu.Record{
+		"status":         m.Status,
+		"tanggal_keluar": m.TanggalKeluar,
+		"keterangan":     m.Keterangan,
+	}).Where(
+		tablePenempatan.Col("tenant_id").Eq(m.TenantID),
+		tablePenempatan.Col("id").Eq(m.ID),
+	).ToSQL()
+	if err != nil {
+		return err
+	}
+	_, err = a.db.Exec(query)
+	return err
+}
+
+// nullableUUID maps an empty reference to NULL (avoiding invalid UUID error)
+func nullableUUID(id string) interface{} {
+	if id == "" {
+		return nil
+	}
+	return id
+}
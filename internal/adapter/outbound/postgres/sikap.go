@@ -0,0 +1,172 @@
+package postgres_outbound_adapter
+
+import (
+	"context"
+	"time"
+
+	"prabogo/internal/model"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/google/uuid"
+)
+
+// ==========================================
+// K13 SIKAP
+// ==========================================
+
+func (a *eraporAdapter) CreateJurnalSikap(ctx context.Context, j *model.JurnalSikap) error {
+	j.ID = uuid.New().String()
+	j.CreatedAt = time.Now()
+
+	record := goqu.Record{
+		"id":            j.ID,
+		"tenant_id":     j.TenantID,
+		"student_id":    j.StudentID,
+		"semester_id":   j.SemesterID,
+		"aspek":         j.Aspek,
+		"tanggal":       j.Tanggal,
+		"butir":         j.Butir,
+		"jenis":         j.Jenis,
+		"catatan":       j.Catatan,
+		"observer_role": j.ObserverRole,
+		"created_at":    j.CreatedAt,
+	}
+	if j.ObserverID != "" {
+		record["observer_id"] = j.ObserverID
+	}
+	_, err := a.db.Insert("jurnal_sikap").Rows(record).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *eraporAdapter) jurnalSikapQuery(tenantID string) *goqu.SelectDataset {
+	return a.db.From(goqu.T("jurnal_sikap").As("j")).
+		LeftJoin(goqu.T("sekolah_siswa").As("s"), goqu.On(goqu.I("s.id").Eq(goqu.I("j.student_id")))).
+		LeftJoin(goqu.T("users").As("u"), goqu.On(goqu.I("u.id").Eq(goqu.I("j.observer_id")))).
+		Select(
+			goqu.I("j.id"),
+			goqu.I("j.tenant_id"),
+			goqu.I("j.student_id"),
+			goqu.I("j.semester_id"),
+			goqu.I("j.aspek"),
+			goqu.I("j.tanggal"),
+			goqu.I("j.butir"),
+			goqu.I("j.jenis"),
+			goqu.I("j.catatan"),
+			goqu.COALESCE(goqu.L("j.observer_id::text"), "").As("observer_id"),
+			goqu.I("j.observer_role"),
+			goqu.I("j.created_at"),
+			goqu.COALESCE(goqu.I("s.nama"), "").As("student_name"),
+			goqu.COALESCE(goqu.I("u.name"), "").As("nama_observer"),
+		).
+		Where(goqu.I("j.tenant_id").Eq(tenantID))
+}
+
+func (a *eraporAdapter) GetJurnalSikapByID(ctx context.Context, tenantID, id string) (*model.JurnalSikap, error) {
+	var j model.JurnalSikap
+	found, err := a.jurnalSikapQuery(tenantID).
+		Where(goqu.I("j.id").Eq(id)).
+		ScanStructContext(ctx, &j)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &j, nil
+}
+
+func (a *eraporAdapter) DeleteJurnalSikap(ctx context.Context, tenantID, id string) error {
+	_, err := a.db.Delete("jurnal_sikap").
+		Where(
+			goqu.C("id").Eq(id),
+			goqu.C("tenant_id").Eq(tenantID),
+		).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *eraporAdapter) GetJurnalSikap(ctx context.Context, tenantID, semesterID string, studentIDs []string) ([]model.JurnalSikap, error) {
+	ds := a.jurnalSikapQuery(tenantID).Where(goqu.I("j.semester_id").Eq(semesterID))
+	if len(studentIDs) > 0 {
+		ds = ds.Where(goqu.I("j.student_id").In(studentIDs))
+	}
+
+	var list []model.JurnalSikap
+	err := ds.Order(goqu.I("j.tanggal").Desc(), goqu.I("j.created_at").Desc()).
+		ScanStructsContext(ctx, &list)
+	return list, err
+}
+
+func (a *eraporAdapter) sikapSiswaQuery() *goqu.SelectDataset {
+	return a.db.From(goqu.T("sikap_siswa").As("k")).
+		LeftJoin(goqu.T("sekolah_siswa").As("s"), goqu.On(goqu.I("s.id").Eq(goqu.I("k.student_id")))).
+		Select(
+			goqu.I("k.id"),
+			goqu.I("k.tenant_id"),
+			goqu.I("k.student_id"),
+			goqu.I("k.semester_id"),
+			goqu.I("k.aspek"),
+			goqu.I("k.predikat"),
+			goqu.I("k.deskripsi"),
+			goqu.I("k.edited"),
+			goqu.I("k.updated_at"),
+			goqu.COALESCE(goqu.I("s.nama"), "").As("student_name"),
+		)
+}
+
+func (a *eraporAdapter) GetSikapSiswa(ctx context.Context, tenantID, semesterID string, studentIDs []string) ([]model.SikapSiswa, error) {
+	ds := a.sikapSiswaQuery().
+		Where(
+			goqu.I("k.tenant_id").Eq(tenantID),
+			goqu.I("k.semester_id").Eq(semesterID),
+		)
+	if len(studentIDs) > 0 {
+		ds = ds.Where(goqu.I("k.student_id").In(studentIDs))
+	}
+
+	var list []model.SikapSiswa
+	err := ds.Order(goqu.I("s.nama").Asc(), goqu.I("k.aspek").Desc()).
+		ScanStructsContext(ctx, &list)
+	return list, err
+}
+
+func (a *eraporAdapter) GetSikapSiswaByID(ctx context.Context, tenantID, id string) (*model.SikapSiswa, error) {
+	var k model.SikapSiswa
+	found, err := a.sikapSiswaQuery().
+		Where(
+			goqu.I("k.id").Eq(id),
+			goqu.I("k.tenant_id").Eq(tenantID),
+		).
+		ScanStructContext(ctx, &k)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &k, nil
+}
+
+func (a *eraporAdapter) SaveSikapSiswa(ctx context.Context, list []model.SikapSiswa) error {
+	if len(list) == 0 {
+		return nil
+	}
+	now := time.Now()
+	rows := make([]interface{}, len(list))
+	for i, k := range list {
+		rows[i] = goqu.Record{
+			"tenant_id":   k.TenantID,
+			"student_id":  k.StudentID,
+			"semester_id": k.SemesterID,
+			"aspek":       k.Aspek,
+			"predikat":    k.Predikat,
+			"deskripsi":   k.Deskripsi,
+			"edited":      k.Edited,
+			"created_at":  now,
+			"updated_at":  now,
+		}
+	}
+	_, err := a.db.Insert("sikap_siswa").Rows(rows...).
+		OnConflict(goqu.DoUpdate("student_id, semester_id, aspek", goqu.Record{
+			"predikat":   goqu.L("EXCLUDED.predikat"),
+			"deskripsi":  goqu.L("EXCLUDED.deskripsi"),
+			"edited":     goqu.L("EXCLUDED.edited"),
+			"updated_at": now,
+		})).
+		Executor().ExecContext(ctx)
+	return err
+}
@@ -0,0 +1,212 @@
+package postgres_outbound_adapter
+
+import (
+	"context"
+
+	"prabogo/internal/model"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+// ==========================================
+// GRADE STATISTICS
+// ==========================================
+
+// gradeKKM is the KKM a grade is measured against: the semester's pinned KKM, else the subject's
+// current one. NULL for subjects without a KKM.
+var gradeKKM = goqu.L(`CASE WHEN COALESCE((sub.grading_config->>'use_kkm')::boolean, false)
+	THEN COALESCE(h.kkm_value, NULLIF((sub.grading_config->>'kkm_value')::int, 0)) END`)
+
+// gradeStatsGroups maps a grouping to its key, label and tingkat expressions
+var gradeStatsGroups = map[string][3]exp.Expression{
+	model.GradeStatsBySubject:  {goqu.L("g.subject_id::text"), goqu.I("sub.name"), goqu.L("''")},
+	model.GradeStatsByKelas:    {goqu.L("COALESCE(k.id::text, '')"), goqu.L("COALESCE(k.nama, '')"), goqu.L("COALESCE(k.tingkat, '')")},
+	model.GradeStatsBySemester: {goqu.I("g.semester_id"), goqu.I("g.semester_id"), goqu.L("''")},
+}
+
+// gradeStatsOverall is the single row over every grade; Postgres rejects the constant
+// expressions in GROUP BY and ORDER BY, so the overall queries leave both out
+var gradeStatsOverall = [3]exp.Expression{goqu.L("''"), goqu.L("''"), goqu.L("''")}
+
+// gradesForStats selects the final grades of the tenant matching the filter, joined with what
+// the statistics group and measure by
+func (a *eraporAdapter) gradesForStats(tenantID string, filter model.GradeStatsFilter) *goqu.SelectDataset {
+	ds := a.db.From(goqu.T("student_grades").As("g")).
+		Join(goqu.T("subjects").As("sub"), goqu.On(goqu.I("sub.id").Eq(goqu.I("g.subject_id")))).
+		LeftJoin(goqu.T("sekolah_siswa").As("s"), goqu.On(goqu.I("s.id").Eq(goqu.I("g.student_id")))).
+		LeftJoin(goqu.T("sekolah_kelas").As("k"), goqu.On(goqu.I("k.id").Eq(goqu.I("s.kelas_id")))).
+		LeftJoin(goqu.T("kkm_history").As("h"), goqu.On(
+			goqu.I("h.subject_id").Eq(goqu.I("g.subject_id")),
+			goqu.I("h.semester_id").Eq(goqu.I("g.semester_id")),
+		)).
+		Where(
+			goqu.I("g.tenant_id").Eq(tenantID),
+			goqu.I("g.score_numeric").IsNotNull(),
+		)
+	if len(filter.SemesterIDs) > 0 {
+		ds = ds.Where(goqu.I("g.semester_id").In(filter.SemesterIDs))
+	}
+	if filter.SubjectID != "" {
+		ds = ds.Where(goqu.I("g.subject_id").Eq(filter.SubjectID))
+	}
+	if filter.KelasID != "" {
+		ds = ds.Where(goqu.I("s.kelas_id").Eq(filter.KelasID))
+	}
+	if filter.Tingkat != "" {
+		ds = ds.Where(goqu.I("k.tingkat").Eq(filter.Tingkat))
+	}
+	return ds
+}
+
+func (a *eraporAdapter) GetGradeStatsGrouped(ctx context.Context, tenantID, groupBy string, filter model.GradeStatsFilter) ([]model.GradeStats, error) {
+	group, grouped := gradeStatsGroups[groupBy]
+	if !grouped {
+		group = gradeStatsOverall
+	}
+	key, label, tingkat := group[0], group[1], group[2]
+
+	var rows []struct {
+		Key      string  `db:"key"`
+		Label    string  `db:"label"`
+		Tingkat  string  `db:"tingkat"`
+		KKM      int     `db:"kkm"`
+		Count    int     `db:"count"`
+		Mean     float64 `db:"mean"`
+		Median   float64 `db:"median"`
+		StdDev   float64 `db:"std_dev"`
+		Min      float64 `db:"min"`
+		Max      float64 `db:"max"`
+		WithKKM  int     `db:"with_kkm"`
+		BelowKKM int     `db:"below_kkm"`
+	}
+	ds := a.gradesForStats(tenantID, filter).
+		Select(
+			goqu.L("?", key).As("key"),
+			goqu.L("?", label).As("label"),
+			goqu.L("?", tingkat).As("tingkat"),
+			goqu.L("COALESCE(MAX(?), 0)", gradeKKM).As("kkm"),
+			goqu.COUNT("*").As("count"),
+			goqu.L("AVG(g.score_numeric)::float8").As("mean"),
+			goqu.L("PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY g.score_numeric)::float8").As("median"),
+			goqu.L("COALESCE(STDDEV_POP(g.score_numeric), 0)::float8").As("std_dev"),
+			goqu.L("MIN(g.score_numeric)::float8").As("min"),
+			goqu.L("MAX(g.score_numeric)::float8").As("max"),
+			goqu.L("COUNT(?)", gradeKKM).As("with_kkm"),
+			goqu.L("COUNT(*) FILTER (WHERE g.score_numeric < ?)", gradeKKM).As("below_kkm"),
+		)
+	if grouped {
+		ds = ds.GroupBy(key, label, tingkat).Order(goqu.L("?", label).Asc())
+	}
+	if err := ds.ScanStructsContext(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	stats := make([]model.GradeStats, len(rows))
+	index := make(map[string]int, len(rows))
+	for i, r := range rows {
+		stats[i] = model.GradeStats{
+			Key:        r.Key,
+			Label:      r.Label,
+			Tingkat:    r.Tingkat,
+			Count:      r.Count,
+			Mean:       r.Mean,
+			Median:     r.Median,
+			StdDev:     r.StdDev,
+			Min:        r.Min,
+			Max:        r.Max,
+			WithKKM:    r.WithKKM,
+			BelowKKM:   r.BelowKKM,
+			Predicates: map[string]int{},
+		}
+		if groupBy == model.GradeStatsBySubject {
+			stats[i].KKM = r.KKM
+		}
+		index[r.Key] = i
+	}
+
+	var predicates []struct {
+		Key       string `db:"key"`
+		Predicate string `db:"predicate"`
+		Count     int    `db:"count"`
+	}
+	predicate := goqu.COALESCE(goqu.I("g.score_predicate"), "")
+	groupPredicate := []interface{}{predicate}
+	if grouped {
+		groupPredicate = []interface{}{key, predicate}
+	}
+	err := a.gradesForStats(tenantID, filter).
+		Select(
+			goqu.L("?", key).As("key"),
+			predicate.As("predicate"),
+			goqu.COUNT("*").As("count"),
+		).
+		GroupBy(groupPredicate...).
+		ScanStructsContext(ctx, &predicates)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range predicates {
+		if i, ok := index[p.Key]; ok && p.Predicate != "" {
+			stats[i].Predicates[p.Predicate] = p.Count
+		}
+	}
+	return stats, nil
+}
+
+func (a *eraporAdapter) GetGradeHistogram(ctx context.Context, tenantID string, filter model.GradeStatsFilter, binWidth int) (map[int]int, error) {
+	// 100 falls in the last bin instead of a bin of its own
+	bin := goqu.L("LEAST(FLOOR(g.score_numeric / ?)::int * ?, ?)", binWidth, binWidth, 100-binWidth)
+
+	var rows []struct {
+		From  int `db:"bin_from"`
+		Count int `db:"count"`
+	}
+	err := a.gradesForStats(tenantID, filter).
+		Select(bin.As("bin_from"), goqu.COUNT("*").As("count")).
+		GroupBy(bin).
+		ScanStructsContext(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int]int, len(rows))
+	for _, r := range rows {
+		counts[r.From] = r.Count
+	}
+	return counts, nil
+}
+
+func (a *eraporAdapter) GetComponentStats(ctx context.Context, tenantID string, filter model.GradeStatsFilter) (map[int]model.ComponentStats, error) {
+	// component_scores holds the component scores in the order of the subject's components
+	scores := goqu.L(`LATERAL jsonb_array_elements_text(CASE WHEN jsonb_typeof(g.component_scores) = 'array'
+		THEN g.component_scores ELSE '[]'::jsonb END) WITH ORDINALITY AS c(score, pos)`)
+
+	var rows []struct {
+		Position int     `db:"position"`
+		Count    int     `db:"count"`
+		Mean     float64 `db:"mean"`
+		Min      float64 `db:"min"`
+		Max      float64 `db:"max"`
+	}
+	err := a.gradesForStats(tenantID, filter).
+		CrossJoin(scores).
+		Select(
+			goqu.L("(c.pos - 1)::int").As("position"),
+			goqu.COUNT("*").As("count"),
+			goqu.L("AVG(c.score::float8)").As("mean"),
+			goqu.L("MIN(c.score::float8)").As("min"),
+			goqu.L("MAX(c.score::float8)").As("max"),
+		).
+		GroupBy(goqu.L("c.pos")).
+		ScanStructsContext(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[int]model.ComponentStats, len(rows))
+	for _, r := range rows {
+		result[r.Position] = model.ComponentStats{Count: r.Count, Mean: r.Mean, Min: r.Min, Max: r.Max}
+	}
+	return result, nil
+}
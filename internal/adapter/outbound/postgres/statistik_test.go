@@ -0,0 +1,75 @@
+package postgres_outbound_adapter_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	postgres_outbound_adapter "prabogo/internal/adapter/outbound/postgres"
+	"prabogo/internal/model"
+)
+
+func TestGradeStatsGrouped(t *testing.T) {
+	Convey("Test Grade Stats Grouped", t, func() {
+		var queries []string
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherFunc(
+			func(expectedSQL, actualSQL string) error {
+				queries = append(queries, actualSQL)
+				return nil
+			})))
+		So(err, ShouldBeNil)
+		defer db.Close()
+
+		adapter := postgres_outbound_adapter.NewERaporAdapter(db)
+		filter := model.GradeStatsFilter{SemesterIDs: []string{"2025-2026-1"}}
+
+		expectQueries := func(key, label string) {
+			mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{
+				"key", "label", "tingkat", "kkm", "count", "mean", "median", "std_dev", "min", "max", "with_kkm", "below_kkm",
+			}).AddRow(key, label, "", 75, 2, 80.0, 80.0, 5.0, 75.0, 85.0, 2, 0))
+			mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"key", "predicate", "count"}).
+				AddRow(key, "B", 2))
+		}
+
+		Convey("Groups and orders by the grouping expressions", func() {
+			groupings := map[string]string{
+				model.GradeStatsBySubject:  `GROUP BY g.subject_id::text, "sub"."name", ''`,
+				model.GradeStatsByKelas:    `GROUP BY COALESCE(k.id::text, ''), COALESCE(k.nama, ''), COALESCE(k.tingkat, '')`,
+				model.GradeStatsBySemester: `GROUP BY "g"."semester_id", "g"."semester_id", ''`,
+			}
+			for groupBy, groupSQL := range groupings {
+				queries = nil
+				expectQueries("k1", "Label")
+
+				stats, err := adapter.GetGradeStatsGrouped(context.Background(), "t1", groupBy, filter)
+				So(err, ShouldBeNil)
+				So(stats, ShouldHaveLength, 1)
+				So(stats[0].Predicates, ShouldResemble, map[string]int{"B": 2})
+				So(queries, ShouldHaveLength, 2)
+				So(queries[0], ShouldContainSubstring, groupSQL)
+				So(queries[0], ShouldEndWith, " ASC")
+				So(queries[1], ShouldContainSubstring, "GROUP BY")
+			}
+			So(mock.ExpectationsWereMet(), ShouldBeNil)
+		})
+
+		Convey("Leaves the constant key out of GROUP BY and ORDER BY for the overall row", func() {
+			expectQueries("", "")
+
+			stats, err := adapter.GetGradeStatsGrouped(context.Background(), "t1", "", filter)
+			So(err, ShouldBeNil)
+			So(stats, ShouldHaveLength, 1)
+			So(queries, ShouldHaveLength, 2)
+			So(queries[0], ShouldNotContainSubstring, "GROUP BY")
+			So(queries[0], ShouldNotEndWith, " ASC")
+			So(queries[1], ShouldContainSubstring, "GROUP BY COALESCE")
+			for _, query := range queries {
+				So(strings.Contains(query, "GROUP BY ''") || strings.Contains(query, "ORDER BY ''"), ShouldBeFalse)
+			}
+			So(mock.ExpectationsWereMet(), ShouldBeNil)
+		})
+	})
+}
@@ -87,3 +87,60 @@ func (a *sekolahAdapter) CreateTahfidzSetoran(m *model.TahfidzSetoran) error {
 	}
 	return a.db.QueryRow(query).Scan(&m.ID, &m.CreatedAt, &m.UpdatedAt)
 }
+
+func (a *sekolahAdapter) GetTahfidzZiyadah(tenantID, santriID string) ([]model.TahfidzSetoran, error) {
+	dialect := goqu.Dialect("postgres")
+	dataset := dialect.From(tableTahfidzSetoran).
+		Join(tableSiswa, goqu.On(tableTahfidzSetoran.Col("santri_id").Eq(tableSiswa.Col("id")))).
+		LeftJoin(tableGuru, goqu.On(tableTahfidzSetoran.Col("ustadz_id").Eq(tableGuru.Col("id")))).
+		Select(
+			tableTahfidzSetoran.Col("santri_id"),
+			tableSiswa.Col("nama").As("santri_nama"),
+			tableTahfidzSetoran.Col("ustadz_id"),
+			goqu.COALESCE(tableGuru.Col("nama"), "").As("ustadz_nama"),
+			tableTahfidzSetoran.Col("tanggal"),
+			tableTahfidzSetoran.Col("juz"),
+			goqu.COALESCE(tableTahfidzSetoran.Col("surah"), "").As("surah"),
+			tableTahfidzSetoran.Col("ayat_awal"),
+			tableTahfidzSetoran.Col("ayat_akhir"),
+			tableTahfidzSetoran.Col("tipe"),
+			goqu.COALESCE(tableTahfidzSetoran.Col("kualitas"), "").As("kualitas"),
+		).
+		Where(
+			tableTahfidzSetoran.Col("tenant_id").Eq(tenantID),
+			goqu.L("LOWER(?)", tableTahfidzSetoran.Col("tipe")).Eq("ziyadah"),
+		).
+		Order(tableTahfidzSetoran.Col("tanggal").Asc())
+	if santriID != "" {
+		dataset = dataset.Where(tableTahfidzSetoran.Col("santri_id").Eq(santriID))
+	}
+
+	query, _, err := dataset.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := a.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []model.TahfidzSetoran
+	for rows.Next() {
+		m := model.TahfidzSetoran{TenantID: tenantID}
+		var ustadzID sql.NullString
+		if err := rows.Scan(
+			&m.SantriID, &m.SantriNama, &ustadzID, &m.UstadzNama,
+			&m.Tanggal, &m.Juz, &m.Surah, &m.AyatAwal, &m.AyatAkhir, &m.Tipe, &m.Kualitas,
+		); err != nil {
+			return nil, err
+		}
+		if ustadzID.Valid {
+			id := ustadzID.String
+			m.UstadzID = &id
+		}
+		list = append(list, m)
+	}
+	return list, rows.Err()
+}
@@ -0,0 +1,437 @@
+package postgres_outbound_adapter
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"prabogo/internal/model"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/google/uuid"
+)
+
+type ujianAdapter struct {
+	db *goqu.Database
+}
+
+func NewUjianAdapter(sqlDB *sql.DB) *ujianAdapter {
+	return &ujianAdapter{db: goqu.New("postgres", sqlDB)}
+}
+
+// ==========================================
+// UJIAN
+// ==========================================
+
+func (a *ujianAdapter) ujianDataset() *goqu.SelectDataset {
+	return a.db.From(goqu.T("sekolah_ujian").As("u")).
+		LeftJoin(goqu.T("tenants").As("t"), goqu.On(goqu.I("t.id").Eq(goqu.I("u.tenant_id")))).
+		Select(
+			goqu.I("u.id"),
+			goqu.I("u.tenant_id"),
+			goqu.L(`COALESCE(NULLIF(t.school_name, ''), t.name, '')`).As("nama_sekolah"),
+			goqu.I("u.nama"),
+			goqu.I("u.jenis"),
+			goqu.I("u.semester_id"),
+			goqu.I("u.tanggal_mulai"),
+			goqu.I("u.tanggal_selesai"),
+			goqu.COALESCE(goqu.I("u.cek_spp"), false).As("cek_spp"),
+			goqu.I("u.created_at"),
+			goqu.I("u.updated_at"),
+		)
+}
+
+func (a *ujianAdapter) CreateUjian(ctx context.Context, u *model.Ujian) error {
+	now := time.Now()
+	u.ID = uuid.New().String()
+	u.CreatedAt = now
+	u.UpdatedAt = now
+
+	_, err := a.db.Insert("sekolah_ujian").Rows(
+		goqu.Record{
+			"id":              u.ID,
+			"tenant_id":       u.TenantID,
+			"nama":            u.Nama,
+			"jenis":           u.Jenis,
+			"semester_id":     u.SemesterID,
+			"tanggal_mulai":   u.TanggalMulai,
+			"tanggal_selesai": u.TanggalSelesai,
+			"cek_spp":         u.CekSPP,
+			"created_at":      now,
+			"updated_at":      now,
+		},
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *ujianAdapter) UpdateUjian(ctx context.Context, u *model.Ujian) error {
+	_, err := a.db.Update("sekolah_ujian").Set(
+		goqu.Record{
+			"nama":            u.Nama,
+			"jenis":           u.Jenis,
+			"semester_id":     u.SemesterID,
+			"tanggal_mulai":   u.TanggalMulai,
+			"tanggal_selesai": u.TanggalSelesai,
+			"cek_spp":         u.CekSPP,
+			"updated_at":      time.Now(),
+		},
+	).Where(
+		goqu.C("id").Eq(u.ID),
+		goqu.C("tenant_id").Eq(u.TenantID),
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *ujianAdapter) GetUjianByID(ctx context.Context, tenantID, id string) (*model.Ujian, error) {
+	var u model.Ujian
+	found, err := a.ujianDataset().
+		Where(goqu.I("u.id").Eq(id), goqu.I("u.tenant_id").Eq(tenantID)).
+		ScanStructContext(ctx, &u)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return &u, nil
+}
+
+func (a *ujianAdapter) GetUjianList(ctx context.Context, tenantID, semesterID string) ([]model.Ujian, error) {
+	ds := a.ujianDataset().Where(goqu.I("u.tenant_id").Eq(tenantID))
+	if semesterID != "" {
+		ds = ds.Where(goqu.I("u.semester_id").Eq(semesterID))
+	}
+
+	var list []model.Ujian
+	if err := ds.Order(goqu.I("u.tanggal_mulai").Desc()).ScanStructsContext(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// ==========================================
+// JADWAL
+// ==========================================
+
+func (a *ujianAdapter) jadwalDataset() *goqu.SelectDataset {
+	return a.db.From(goqu.T("sekolah_ujian_jadwal").As("j")).
+		Join(goqu.T("subjects").As("s"), goqu.On(goqu.I("s.id").Eq(goqu.I("j.subject_id")))).
+		Select(
+			goqu.I("j.id"),
+			goqu.I("j.ujian_id"),
+			goqu.I("j.subject_id"),
+			goqu.I("s.name").As("subject_nama"),
+			goqu.I("j.tingkat"),
+			goqu.I("j.waktu_mulai"),
+			goqu.I("j.waktu_selesai"),
+			goqu.I("j.created_at"),
+		)
+}
+
+func (a *ujianAdapter) CreateJadwal(ctx context.Context, j *model.UjianJadwal) error {
+	j.ID = uuid.New().String()
+	j.CreatedAt = time.Now()
+
+	_, err := a.db.Insert("sekolah_ujian_jadwal").Rows(
+		goqu.Record{
+			"id":            j.ID,
+			"ujian_id":      j.UjianID,
+			"subject_id":    j.SubjectID,
+			"tingkat":       j.Tingkat,
+			"waktu_mulai":   j.WaktuMulai,
+			"waktu_selesai": j.WaktuSelesai,
+			"created_at":    j.CreatedAt,
+		},
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *ujianAdapter) DeleteJadwal(ctx context.Context, ujianID, id string) error {
+	_, err := a.db.Delete("sekolah_ujian_jadwal").
+		Where(goqu.C("id").Eq(id), goqu.C("ujian_id").Eq(ujianID)).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *ujianAdapter) GetJadwalByID(ctx context.Context, ujianID, id string) (*model.UjianJadwal, error) {
+	var j model.UjianJadwal
+	found, err := a.jadwalDataset().
+		Where(goqu.I("j.id").Eq(id), goqu.I("j.ujian_id").Eq(ujianID)).
+		ScanStructContext(ctx, &j)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return &j, nil
+}
+
+func (a *ujianAdapter) GetJadwalByUjian(ctx context.Context, ujianID string) ([]model.UjianJadwal, error) {
+	var list []model.UjianJadwal
+	err := a.jadwalDataset().
+		Where(goqu.I("j.ujian_id").Eq(ujianID)).
+		Order(goqu.I("j.waktu_mulai").Asc(), goqu.I("j.tingkat").Asc()).
+		ScanStructsContext(ctx, &list)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// ==========================================
+// RUANG
+// ==========================================
+
+func (a *ujianAdapter) ruangDataset() *goqu.SelectDataset {
+	return a.db.From(goqu.T("sekolah_ujian_ruang").As("r")).
+		Select(
+			goqu.I("r.id"),
+			goqu.I("r.ujian_id"),
+			goqu.I("r.nama"),
+			goqu.I("r.kapasitas"),
+			goqu.L(`(SELECT COUNT(*) FROM sekolah_ujian_peserta p WHERE p.ruang_id = r.id)`).As("jumlah_peserta"),
+		)
+}
+
+func (a *ujianAdapter) CreateRuang(ctx context.Context, r *model.UjianRuang) error {
+	r.ID = uuid.New().String()
+
+	_, err := a.db.Insert("sekolah_ujian_ruang").Rows(
+		goqu.Record{
+			"id":        r.ID,
+			"ujian_id":  r.UjianID,
+			"nama":      r.Nama,
+			"kapasitas": r.Kapasitas,
+		},
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *ujianAdapter) DeleteRuang(ctx context.Context, ujianID, id string) error {
+	_, err := a.db.Delete("sekolah_ujian_ruang").
+		Where(goqu.C("id").Eq(id), goqu.C("ujian_id").Eq(ujianID)).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *ujianAdapter) GetRuangByID(ctx context.Context, ujianID, id string) (*model.UjianRuang, error) {
+	var r model.UjianRuang
+	found, err := a.ruangDataset().
+		Where(goqu.I("r.id").Eq(id), goqu.I("r.ujian_id").Eq(ujianID)).
+		ScanStructContext(ctx, &r)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return &r, nil
+}
+
+func (a *ujianAdapter) GetRuangByUjian(ctx context.Context, ujianID string) ([]model.UjianRuang, error) {
+	var list []model.UjianRuang
+	err := a.ruangDataset().
+		Where(goqu.I("r.ujian_id").Eq(ujianID)).
+		Order(goqu.I("r.nama").Asc()).
+		ScanStructsContext(ctx, &list)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// ==========================================
+// PESERTA
+// ==========================================
+
+func (a *ujianAdapter) GetSiswaByTingkat(ctx context.Context, tenantID string, tingkat []string) ([]model.UjianSiswa, error) {
+	var list []model.UjianSiswa
+	err := a.db.From(goqu.T("sekolah_siswa").As("s")).
+		Join(goqu.T("sekolah_kelas").As("k"), goqu.On(goqu.I("k.id").Eq(goqu.I("s.kelas_id")))).
+		Select(
+			goqu.I("s.id"),
+			goqu.L("s.kelas_id::text").As("kelas_id"),
+			goqu.I("k.tingkat"),
+		).
+		Where(
+			goqu.I("s.tenant_id").Eq(tenantID),
+			goqu.I("s.status").Eq("Aktif"),
+			goqu.I("k.tingkat").In(tingkat),
+		).
+		Order(goqu.I("k.tingkat").Asc(), goqu.I("k.nama").Asc(), goqu.I("s.nama").Asc()).
+		ScanStructsContext(ctx, &list)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (a *ujianAdapter) ReplacePeserta(ctx context.Context, ujianID string, peserta []model.UjianPeserta) error {
+	return a.db.WithTx(func(tx *goqu.TxDatabase) error {
+		_, err := tx.Delete("sekolah_ujian_peserta").
+			Where(goqu.C("ujian_id").Eq(ujianID)).
+			Executor().ExecContext(ctx)
+		if err != nil || len(peserta) == 0 {
+			return err
+		}
+
+		rows := make([]interface{}, 0, len(peserta))
+		for i := range peserta {
+			peserta[i].ID = uuid.New().String()
+			rows = append(rows, goqu.Record{
+				"id":            peserta[i].ID,
+				"ujian_id":      ujianID,
+				"ruang_id":      peserta[i].RuangID,
+				"santri_id":     peserta[i].SantriID,
+				"nomor_kursi":   peserta[i].NomorKursi,
+				"nomor_peserta": peserta[i].NomorPeserta,
+			})
+		}
+		_, err = tx.Insert("sekolah_ujian_peserta").Rows(rows...).Executor().ExecContext(ctx)
+		return err
+	})
+}
+
+func (a *ujianAdapter) GetPeserta(ctx context.Context, ujianID, ruangID, kelasID string) ([]model.UjianPeserta, error) {
+	ds := a.db.From(goqu.T("sekolah_ujian_peserta").As("p")).
+		Join(goqu.T("sekolah_ujian_ruang").As("r"), goqu.On(goqu.I("r.id").Eq(goqu.I("p.ruang_id")))).
+		Join(goqu.T("sekolah_siswa").As("s"), goqu.On(goqu.I("s.id").Eq(goqu.I("p.santri_id")))).
+		LeftJoin(goqu.T("sekolah_kelas").As("k"), goqu.On(goqu.I("k.id").Eq(goqu.I("s.kelas_id")))).
+		Select(
+			goqu.I("p.id"),
+			goqu.I("p.ujian_id"),
+			goqu.I("p.ruang_id"),
+			goqu.I("r.nama").As("ruang_nama"),
+			goqu.I("p.santri_id"),
+			goqu.I("s.nama").As("santri_nama"),
+			goqu.COALESCE(goqu.I("s.nis"), "").As("nis"),
+			goqu.COALESCE(goqu.L("s.kelas_id::text"), "").As("kelas_id"),
+			goqu.COALESCE(goqu.I("k.nama"), "").As("kelas_nama"),
+			goqu.COALESCE(goqu.I("k.tingkat"), "").As("tingkat"),
+			goqu.I("p.nomor_kursi"),
+			goqu.I("p.nomor_peserta"),
+		).
+		Where(goqu.I("p.ujian_id").Eq(ujianID))
+	if ruangID != "" {
+		ds = ds.Where(goqu.I("p.ruang_id").Eq(ruangID))
+	}
+	if kelasID != "" {
+		ds = ds.Where(goqu.I("s.kelas_id").Eq(kelasID))
+	}
+
+	var list []model.UjianPeserta
+	if err := ds.Order(goqu.I("r.nama").Asc(), goqu.I("p.nomor_kursi").Asc()).ScanStructsContext(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (a *ujianAdapter) GetSiswaBelumLunasSPP(ctx context.Context, tenantID string, siswaIDs []string) (map[string]bool, error) {
+	result := make(map[string]bool)
+	if len(siswaIDs) == 0 {
+		return result, nil
+	}
+
+	var ids []string
+	err := a.db.From("spp_transactions").
+		SelectDistinct(goqu.L("student_id::text")).
+		Where(
+			goqu.C("tenant_id").Eq(tenantID),
+			goqu.C("student_id").In(siswaIDs),
+			goqu.C("status").In(string(model.SPPStatusPending), string(model.SPPStatusOverdue)),
+		).
+		ScanValsContext(ctx, &ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		result[id] = true
+	}
+	return result, nil
+}
+
+// ==========================================
+// PENGAWAS
+// ==========================================
+
+func (a *ujianAdapter) GuruExists(ctx context.Context, tenantID, guruID string) (bool, error) {
+	var id string
+	return a.db.From(tableGuru).
+		Select(goqu.L("id::text")).
+		Where(goqu.C("id").Eq(guruID), goqu.C("tenant_id").Eq(tenantID)).
+		ScanValContext(ctx, &id)
+}
+
+func (a *ujianAdapter) CreatePengawas(ctx context.Context, p *model.UjianPengawas) error {
+	p.ID = uuid.New().String()
+
+	_, err := a.db.Insert("sekolah_ujian_pengawas").Rows(
+		goqu.Record{
+			"id":        p.ID,
+			"jadwal_id": p.JadwalID,
+			"ruang_id":  p.RuangID,
+			"guru_id":   p.GuruID,
+		},
+	).Executor().ExecContext(ctx)
+	return err
+}
+
+// jadwalOfUjian selects the session ids of an exam, scoping pengawas rows to it
+func (a *ujianAdapter) jadwalOfUjian(ujianID string) *goqu.SelectDataset {
+	return a.db.From("sekolah_ujian_jadwal").
+		Select("id").
+		Where(goqu.C("ujian_id").Eq(ujianID))
+}
+
+func (a *ujianAdapter) DeletePengawas(ctx context.Context, ujianID, jadwalID, id string) error {
+	_, err := a.db.Delete("sekolah_ujian_pengawas").
+		Where(
+			goqu.C("id").Eq(id),
+			goqu.C("jadwal_id").Eq(jadwalID),
+			goqu.C("jadwal_id").In(a.jadwalOfUjian(ujianID)),
+		).
+		Executor().ExecContext(ctx)
+	return err
+}
+
+func (a *ujianAdapter) GetPengawasByJadwal(ctx context.Context, ujianID, jadwalID string) ([]model.UjianPengawas, error) {
+	var list []model.UjianPengawas
+	err := a.db.From(goqu.T("sekolah_ujian_pengawas").As("p")).
+		Join(goqu.T("sekolah_ujian_jadwal").As("j"), goqu.On(goqu.I("j.id").Eq(goqu.I("p.jadwal_id")))).
+		Join(goqu.T("sekolah_ujian_ruang").As("r"), goqu.On(goqu.I("r.id").Eq(goqu.I("p.ruang_id")))).
+		Join(goqu.T("sekolah_guru").As("g"), goqu.On(goqu.I("g.id").Eq(goqu.I("p.guru_id")))).
+		Select(
+			goqu.I("p.id"),
+			goqu.I("p.jadwal_id"),
+			goqu.I("p.ruang_id"),
+			goqu.I("r.nama").As("ruang_nama"),
+			goqu.I("p.guru_id"),
+			goqu.I("g.nama").As("guru_nama"),
+		).
+		Where(goqu.I("p.jadwal_id").Eq(jadwalID), goqu.I("j.ujian_id").Eq(ujianID)).
+		Order(goqu.I("r.nama").Asc()).
+		ScanStructsContext(ctx, &list)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (a *ujianAdapter) GetBentrokPengawas(ctx context.Context, tenantID, guruID string, mulai, selesai time.Time) ([]model.UjianJadwal, error) {
+	var list []model.UjianJadwal
+	err := a.jadwalDataset().
+		Join(goqu.T("sekolah_ujian_pengawas").As("p"), goqu.On(goqu.I("p.jadwal_id").Eq(goqu.I("j.id")))).
+		Join(goqu.T("sekolah_ujian").As("u"), goqu.On(goqu.I("u.id").Eq(goqu.I("j.ujian_id")))).
+		Where(
+			goqu.I("u.tenant_id").Eq(tenantID),
+			goqu.I("p.guru_id").Eq(guruID),
+			goqu.I("j.waktu_mulai").Lt(selesai),
+			goqu.I("j.waktu_selesai").Gt(mulai),
+		).
+		ScanStructsContext(ctx, &list)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
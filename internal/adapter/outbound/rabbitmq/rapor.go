@@ -0,0 +1,19 @@
+package rabbitmq_outbound_adapter
+
+import (
+	"context"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/rabbitmq"
+)
+
+type raporAdapter struct{}
+
+func NewRaporAdapter() outbound_port.RaporMessagePort {
+	return &raporAdapter{}
+}
+
+func (adapter *raporAdapter) PublishGenerateBatch(msg model.RaporBatchMessage) error {
+	return rabbitmq.Publish(context.Background(), model.GenerateRaporBatchMessage, rabbitmq.KindFanOut, "", msg)
+}
@@ -18,3 +18,7 @@ func (s *adapter) Client() outbound_port.ClientMessagePort {
 func (s *adapter) WhatsApp() outbound_port.WhatsAppMessagePort {
 	return nil
 }
+
+func (s *adapter) Rapor() outbound_port.RaporMessagePort {
+	return NewRaporAdapter()
+}
@@ -19,3 +19,8 @@ func (s *adapter) Client() outbound_port.ClientMessagePort {
 func (s *adapter) WhatsApp() outbound_port.WhatsAppMessagePort {
 	return NewFonnteAdapter()
 }
+
+func (s *adapter) Rapor() outbound_port.RaporMessagePort {
+	// Not implemented for this adapter
+	return nil
+}
@@ -0,0 +1,440 @@
+package alumni
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/log"
+)
+
+// AlumniDomain interface
+type AlumniDomain interface {
+	// Registry
+	Kelulusan(ctx context.Context, tenantID string, input model.KelulusanInput) (int, error)
+	GetAlumniList(ctx context.Context, tenantID string, filter model.AlumniFilter) ([]model.Alumni, error)
+	GetAlumniDetail(ctx context.Context, tenantID, id string) (*model.Alumni, error)
+	CreateAlumni(ctx context.Context, a *model.Alumni) error
+	UpdateAlumni(ctx context.Context, a *model.Alumni) error
+	KirimLinkProfil(ctx context.Context, tenantID, id string) (string, error)
+	GetStatistik(ctx context.Context, tenantID string) ([]model.AlumniStatistik, error)
+
+	// Self-service (token link)
+	GetProfil(ctx context.Context, token string) (*model.Alumni, []model.TracerSurvey, error)
+	UpdateProfil(ctx context.Context, token string, input model.AlumniProfilInput) (*model.Alumni, error)
+	IsiSurvey(ctx context.Context, token, surveyID string, jawaban map[string]string) error
+
+	// Tracer study
+	GetSurveyList(ctx context.Context, tenantID, status string) ([]model.TracerSurvey, error)
+	CreateSurvey(ctx context.Context, s *model.TracerSurvey) error
+	UpdateSurvey(ctx context.Context, s *model.TracerSurvey) error
+	GetSurveyHasil(ctx context.Context, tenantID, id string) (*model.TracerSurvey, []model.TracerJawaban, error)
+	BroadcastSurvey(ctx context.Context, tenantID, id string) (int, error)
+}
+
+type alumniDomain struct {
+	db          outbound_port.AlumniDatabasePort
+	messagePort outbound_port.MessagePort
+}
+
+func NewAlumniDomain(db outbound_port.AlumniDatabasePort, messagePort outbound_port.MessagePort) AlumniDomain {
+	return &alumniDomain{db: db, messagePort: messagePort}
+}
+
+var (
+	ErrAlumniNotFound = errors.New("alumni tidak ditemukan")
+	ErrSurveyNotFound = errors.New("survey tracer study tidak ditemukan")
+)
+
+// ValidateJawaban checks answers against the survey questions
+func ValidateJawaban(s *model.TracerSurvey, jawaban map[string]string) error {
+	known := make(map[string]bool, len(s.Pertanyaan))
+	for _, p := range s.Pertanyaan {
+		known[p.Kode] = true
+		v := strings.TrimSpace(jawaban[p.Kode])
+		if v == "" {
+			if p.Wajib {
+				return fmt.Errorf("pertanyaan %q wajib dijawab", p.Pertanyaan)
+			}
+			continue
+		}
+		if p.Tipe == model.TracerTipePilihan && !containsString(p.Pilihan, v) {
+			return fmt.Errorf("jawaban %q tidak tersedia untuk pertanyaan %q", v, p.Pertanyaan)
+		}
+	}
+	for kode := range jawaban {
+		if !known[kode] {
+			return fmt.Errorf("pertanyaan tidak dikenal: %s", kode)
+		}
+	}
+	return nil
+}
+
+func containsString(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func profilLink(token string) string {
+	baseURL := os.Getenv("FRONTEND_URL")
+	if baseURL == "" {
+		baseURL = "https://eduvera.ve-lora.my.id"
+	}
+	return fmt.Sprintf("%s/alumni/%s", baseURL, token)
+}
+
+// ==========================================
+// REGISTRY
+// ==========================================
+
+func (d *alumniDomain) Kelulusan(ctx context.Context, tenantID string, input model.KelulusanInput) (int, error) {
+	if input.TahunLulus < 1900 {
+		return 0, errors.New("tahun_lulus tidak valid")
+	}
+	if input.KelasID == "" && len(input.SiswaIDs) == 0 {
+		return 0, errors.New("kelas_id atau siswa_ids wajib diisi")
+	}
+
+	calon, err := d.db.GetCalonAlumni(ctx, tenantID, input.KelasID, input.SiswaIDs)
+	if err != nil {
+		return 0, err
+	}
+	if len(calon) == 0 {
+		return 0, errors.New("tidak ada siswa yang dapat diluluskan")
+	}
+
+	for i := range calon {
+		calon[i].TahunLulus = input.TahunLulus
+		if calon[i].Token, err = newToken(); err != nil {
+			return 0, err
+		}
+	}
+	return d.db.CreateAlumniFromSiswa(ctx, calon)
+}
+
+func (d *alumniDomain) GetAlumniList(ctx context.Context, tenantID string, filter model.AlumniFilter) ([]model.Alumni, error) {
+	return d.db.GetAlumniList(ctx, tenantID, filter)
+}
+
+func (d *alumniDomain) GetAlumniDetail(ctx context.Context, tenantID, id string) (*model.Alumni, error) {
+	a, err := d.db.GetAlumniByID(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		return nil, ErrAlumniNotFound
+	}
+	return a, nil
+}
+
+func validateAlumni(a *model.Alumni) error {
+	a.Nama = strings.TrimSpace(a.Nama)
+	if a.Nama == "" || a.TahunLulus < 1900 {
+		return errors.New("nama dan tahun_lulus wajib diisi")
+	}
+	if !model.IsValidAlumniKegiatan(a.Kegiatan) {
+		return fmt.Errorf("kegiatan tidak valid: %s", a.Kegiatan)
+	}
+	return nil
+}
+
+// CreateAlumni registers a graduate from before the siswa data was kept in the system
+func (d *alumniDomain) CreateAlumni(ctx context.Context, a *model.Alumni) error {
+	if err := validateAlumni(a); err != nil {
+		return err
+	}
+	a.SiswaID = ""
+	token, err := newToken()
+	if err != nil {
+		return err
+	}
+	a.Token = token
+	return d.db.CreateAlumni(ctx, a)
+}
+
+func (d *alumniDomain) UpdateAlumni(ctx context.Context, a *model.Alumni) error {
+	existing, err := d.GetAlumniDetail(ctx, a.TenantID, a.ID)
+	if err != nil {
+		return err
+	}
+	if err := validateAlumni(a); err != nil {
+		return err
+	}
+	a.SiswaID = existing.SiswaID
+	a.Token = existing.Token
+	return d.db.UpdateAlumni(ctx, a)
+}
+
+// KirimLinkProfil sends the self-service link to the alumni via WhatsApp and returns it
+func (d *alumniDomain) KirimLinkProfil(ctx context.Context, tenantID, id string) (string, error) {
+	a, err := d.GetAlumniDetail(ctx, tenantID, id)
+	if err != nil {
+		return "", err
+	}
+
+	link := profilLink(a.Token)
+	if a.NoHP == "" || d.messagePort == nil || d.messagePort.WhatsApp() == nil {
+		return link, nil
+	}
+	message := fmt.Sprintf(
+		"Assalamu'alaikum, %s.\n\nSekolah mengundang Anda memperbarui data alumni (kontak, studi lanjut atau pekerjaan) melalui tautan berikut:\n%s\n\nTerima kasih.",
+		a.Nama, link,
+	)
+	if err := d.messagePort.WhatsApp().Send(a.NoHP, message); err != nil {
+		return "", fmt.Errorf("gagal mengirim link: %w", err)
+	}
+	return link, nil
+}
+
+func (d *alumniDomain) GetStatistik(ctx context.Context, tenantID string) ([]model.AlumniStatistik, error) {
+	list, err := d.db.GetStatistik(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		if list[i].Total > 0 {
+			list[i].PersenTerlacak = float64(list[i].Terlacak) * 100 / float64(list[i].Total)
+		}
+	}
+	return list, nil
+}
+
+// ==========================================
+// SELF-SERVICE
+// ==========================================
+
+func (d *alumniDomain) alumniByToken(ctx context.Context, token string) (*model.Alumni, error) {
+	if token == "" {
+		return nil, ErrAlumniNotFound
+	}
+	a, err := d.db.GetAlumniByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		return nil, ErrAlumniNotFound
+	}
+	return a, nil
+}
+
+// GetProfil returns the alumni data and the open surveys they have not answered yet
+func (d *alumniDomain) GetProfil(ctx context.Context, token string) (*model.Alumni, []model.TracerSurvey, error) {
+	a, err := d.alumniByToken(ctx, token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	list, err := d.db.GetSurveyList(ctx, a.TenantID, model.TracerStatusAktif)
+	if err != nil {
+		return nil, nil, err
+	}
+	dijawab, err := d.db.GetSurveyDijawab(ctx, a.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	surveys := make([]model.TracerSurvey, 0, len(list))
+	for _, s := range list {
+		if s.IsOpen(now) && s.Targets(a.TahunLulus) && !dijawab[s.ID] {
+			s.JumlahRespon = 0 // Do not expose campaign figures publicly
+			surveys = append(surveys, s)
+		}
+	}
+	return a, surveys, nil
+}
+
+func (d *alumniDomain) UpdateProfil(ctx context.Context, token string, input model.AlumniProfilInput) (*model.Alumni, error) {
+	a, err := d.alumniByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if !model.IsValidAlumniKegiatan(input.Kegiatan) {
+		return nil, fmt.Errorf("kegiatan tidak valid: %s", input.Kegiatan)
+	}
+	if err := d.db.UpdateProfil(ctx, a.ID, input); err != nil {
+		return nil, err
+	}
+	return d.db.GetAlumniByID(ctx, a.TenantID, a.ID)
+}
+
+func (d *alumniDomain) IsiSurvey(ctx context.Context, token, surveyID string, jawaban map[string]string) error {
+	a, err := d.alumniByToken(ctx, token)
+	if err != nil {
+		return err
+	}
+	s, err := d.db.GetSurveyByID(ctx, a.TenantID, surveyID)
+	if err != nil {
+		return err
+	}
+	if s == nil || !s.Targets(a.TahunLulus) {
+		return ErrSurveyNotFound
+	}
+	if !s.IsOpen(time.Now()) {
+		return errors.New("survey sudah ditutup")
+	}
+	if err := ValidateJawaban(s, jawaban); err != nil {
+		return err
+	}
+
+	return d.db.SaveJawaban(ctx, &model.TracerJawaban{
+		SurveyID: s.ID,
+		AlumniID: a.ID,
+		Jawaban:  jawaban,
+	})
+}
+
+// ==========================================
+// TRACER STUDY
+// ==========================================
+
+func validateSurvey(s *model.TracerSurvey) error {
+	if strings.TrimSpace(s.Judul) == "" {
+		return errors.New("judul wajib diisi")
+	}
+	if s.TanggalSelesai.Before(s.TanggalMulai) {
+		return errors.New("tanggal selesai harus setelah tanggal mulai")
+	}
+	if s.TahunLulusDari > 0 && s.TahunLulusSampai > 0 && s.TahunLulusSampai < s.TahunLulusDari {
+		return errors.New("rentang tahun lulus tidak valid")
+	}
+	switch s.Status {
+	case "":
+		s.Status = model.TracerStatusDraft
+	case model.TracerStatusDraft, model.TracerStatusAktif, model.TracerStatusSelesai:
+	default:
+		return fmt.Errorf("status tidak valid: %s", s.Status)
+	}
+	if len(s.Pertanyaan) == 0 {
+		return errors.New("minimal satu pertanyaan")
+	}
+
+	kode := map[string]bool{}
+	for i := range s.Pertanyaan {
+		p := &s.Pertanyaan[i]
+		if p.Kode == "" {
+			p.Kode = fmt.Sprintf("q%d", i+1)
+		}
+		if kode[p.Kode] {
+			return fmt.Errorf("kode pertanyaan duplikat: %s", p.Kode)
+		}
+		kode[p.Kode] = true
+		if strings.TrimSpace(p.Pertanyaan) == "" {
+			return fmt.Errorf("pertanyaan %s kosong", p.Kode)
+		}
+		switch p.Tipe {
+		case "":
+			p.Tipe = model.TracerTipeTeks
+		case model.TracerTipeTeks:
+		case model.TracerTipePilihan:
+			if len(p.Pilihan) < 2 {
+				return fmt.Errorf("pertanyaan %s membutuhkan minimal dua pilihan", p.Kode)
+			}
+		default:
+			return fmt.Errorf("tipe pertanyaan tidak valid: %s", p.Tipe)
+		}
+	}
+	return nil
+}
+
+func (d *alumniDomain) GetSurveyList(ctx context.Context, tenantID, status string) ([]model.TracerSurvey, error) {
+	return d.db.GetSurveyList(ctx, tenantID, status)
+}
+
+func (d *alumniDomain) CreateSurvey(ctx context.Context, s *model.TracerSurvey) error {
+	if err := validateSurvey(s); err != nil {
+		return err
+	}
+	return d.db.CreateSurvey(ctx, s)
+}
+
+func (d *alumniDomain) UpdateSurvey(ctx context.Context, s *model.TracerSurvey) error {
+	existing, err := d.db.GetSurveyByID(ctx, s.TenantID, s.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return ErrSurveyNotFound
+	}
+	if err := validateSurvey(s); err != nil {
+		return err
+	}
+	if existing.JumlahRespon > 0 && !reflect.DeepEqual(s.Pertanyaan, existing.Pertanyaan) {
+		return errors.New("pertanyaan tidak dapat diubah setelah ada responden")
+	}
+	return d.db.UpdateSurvey(ctx, s)
+}
+
+func (d *alumniDomain) GetSurveyHasil(ctx context.Context, tenantID, id string) (*model.TracerSurvey, []model.TracerJawaban, error) {
+	s, err := d.db.GetSurveyByID(ctx, tenantID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if s == nil {
+		return nil, nil, ErrSurveyNotFound
+	}
+	jawaban, err := d.db.GetJawabanBySurvey(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s, jawaban, nil
+}
+
+// BroadcastSurvey sends the survey invitation to every targeted alumni with a phone number
+func (d *alumniDomain) BroadcastSurvey(ctx context.Context, tenantID, id string) (int, error) {
+	s, err := d.db.GetSurveyByID(ctx, tenantID, id)
+	if err != nil {
+		return 0, err
+	}
+	if s == nil {
+		return 0, ErrSurveyNotFound
+	}
+	if !s.IsOpen(time.Now()) {
+		return 0, errors.New("survey belum aktif atau sudah ditutup")
+	}
+	if d.messagePort == nil || d.messagePort.WhatsApp() == nil {
+		return 0, errors.New("layanan WhatsApp tidak tersedia")
+	}
+
+	target, err := d.db.GetAlumniTarget(ctx, tenantID, s.TahunLulusDari, s.TahunLulusSampai)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, a := range target {
+		if a.NoHP == "" {
+			continue
+		}
+		message := fmt.Sprintf(
+			"Assalamu'alaikum, %s.\n\nMohon kesediaan Anda mengisi tracer study *%s* hingga %s melalui tautan berikut:\n%s\n\nTerima kasih.",
+			a.Nama, s.Judul, s.TanggalSelesai.Format("02-01-2006"), profilLink(a.Token),
+		)
+		if err := d.messagePort.WhatsApp().Send(a.NoHP, message); err != nil {
+			log.WithContext(ctx).WithError(err).Errorf("Failed to send tracer study invitation to alumni %s", a.ID)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
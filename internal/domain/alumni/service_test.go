@@ -0,0 +1,49 @@
+package alumni_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain/alumni"
+	"prabogo/internal/model"
+)
+
+func TestValidateJawaban(t *testing.T) {
+	Convey("Test ValidateJawaban", t, func() {
+		survey := &model.TracerSurvey{
+			Pertanyaan: []model.TracerPertanyaan{
+				{Kode: "kegiatan", Pertanyaan: "Kegiatan saat ini", Tipe: model.TracerTipePilihan, Pilihan: []string{"Kuliah", "Bekerja"}, Wajib: true},
+				{Kode: "saran", Pertanyaan: "Saran untuk sekolah", Tipe: model.TracerTipeTeks},
+			},
+		}
+
+		Convey("Accepts valid answers and skips optional ones", func() {
+			So(alumni.ValidateJawaban(survey, map[string]string{"kegiatan": "Kuliah"}), ShouldBeNil)
+		})
+
+		Convey("Rejects missing required answers", func() {
+			So(alumni.ValidateJawaban(survey, map[string]string{"saran": "Tambah lab"}), ShouldNotBeNil)
+		})
+
+		Convey("Rejects options outside the list and unknown questions", func() {
+			So(alumni.ValidateJawaban(survey, map[string]string{"kegiatan": "Mondok"}), ShouldNotBeNil)
+			So(alumni.ValidateJawaban(survey, map[string]string{"kegiatan": "Kuliah", "x": "y"}), ShouldNotBeNil)
+		})
+	})
+
+	Convey("Test TracerSurvey targeting and window", t, func() {
+		s := model.TracerSurvey{
+			Status:           model.TracerStatusAktif,
+			TahunLulusDari:   2020,
+			TahunLulusSampai: 2022,
+			TanggalMulai:     time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC),
+			TanggalSelesai:   time.Date(2026, 7, 31, 0, 0, 0, 0, time.UTC),
+		}
+		So(s.Targets(2019), ShouldBeFalse)
+		So(s.Targets(2021), ShouldBeTrue)
+		So(s.IsOpen(time.Date(2026, 7, 31, 15, 0, 0, 0, time.UTC)), ShouldBeTrue)
+		So(s.IsOpen(time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC)), ShouldBeFalse)
+	})
+}
@@ -0,0 +1,102 @@
+package dapodik
+
+import (
+	"fmt"
+	"strings"
+
+	"prabogo/internal/model"
+)
+
+// diffHasil is the outcome of comparing incoming rows with local records
+type diffHasil struct {
+	Items  []model.DapodikDiffItem
+	Errors []model.DapodikBarisError
+	// Merged holds, per item, the local values overlaid with the non-empty incoming cells
+	Merged []map[string]string
+}
+
+// diffNilai matches each incoming row to a local record using the layout keys in priority
+// order and lists the changed columns. Empty incoming cells never clear local values.
+func diffNilai(l layout, local []map[string]string, localIDs []string, incoming []barisData) diffHasil {
+	index := map[string]map[string]int{}
+	for _, k := range l.Kunci {
+		index[k] = map[string]int{}
+	}
+	for i, nilai := range local {
+		for _, k := range l.Kunci {
+			if v := normalizeKunci(nilai[k]); v != "" {
+				if _, dup := index[k][v]; !dup {
+					index[k][v] = i
+				}
+			}
+		}
+	}
+
+	var hasil diffHasil
+	dipakai := map[int]int{} // local index -> row that claimed it
+	for _, row := range incoming {
+		if row.Nilai[l.Nama] == "" {
+			hasil.Errors = append(hasil.Errors, model.DapodikBarisError{Baris: row.Baris, Pesan: l.Nama + " wajib diisi"})
+			continue
+		}
+
+		match, kunci := -1, ""
+		for _, k := range l.Kunci {
+			v := normalizeKunci(row.Nilai[k])
+			if v == "" {
+				continue
+			}
+			if kunci == "" {
+				kunci = strings.ToLower(k) + ":" + row.Nilai[k]
+			}
+			if i, ok := index[k][v]; ok {
+				match, kunci = i, strings.ToLower(k)+":"+row.Nilai[k]
+				break
+			}
+		}
+
+		item := model.DapodikDiffItem{Baris: row.Baris, Kunci: kunci, Nama: row.Nilai[l.Nama], Aksi: model.DapodikAksiBaru}
+		merged := map[string]string{}
+		if match >= 0 {
+			if prev, ok := dipakai[match]; ok {
+				hasil.Errors = append(hasil.Errors, model.DapodikBarisError{
+					Baris: row.Baris,
+					Pesan: fmt.Sprintf("Data sama dengan baris %d", prev),
+				})
+				continue
+			}
+			dipakai[match] = row.Baris
+			item.LocalID = localIDs[match]
+			for k, v := range local[match] {
+				merged[k] = v
+			}
+		}
+
+		for _, h := range l.Kolom {
+			baru, ok := row.Nilai[h]
+			if !ok || baru == "" || h == "No" {
+				continue
+			}
+			lama := merged[h]
+			// Case-only edits (e.g. a corrected name spelling) are real changes
+			if match >= 0 && strings.TrimSpace(lama) != baru {
+				item.Perubahan = append(item.Perubahan, model.DapodikPerubahan{Field: h, Lama: lama, Baru: baru})
+			}
+			merged[h] = baru
+		}
+
+		if match >= 0 {
+			item.Aksi = model.DapodikAksiUbah
+			if len(item.Perubahan) == 0 {
+				item.Aksi = model.DapodikAksiSama
+			}
+		}
+		hasil.Items = append(hasil.Items, item)
+		hasil.Merged = append(hasil.Merged, merged)
+	}
+	return hasil
+}
+
+func normalizeKunci(v string) string {
+	return strings.ToLower(strings.TrimSpace(v))
+}
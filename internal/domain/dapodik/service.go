@@ -0,0 +1,362 @@
+package dapodik
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/xuri/excelize/v2"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+)
+
+var (
+	ErrJenisInvalid   = errors.New("jenis harus siswa, guru atau rombel")
+	ErrFileInvalid    = errors.New("file bukan XLSX yang valid")
+	ErrSheetNotFound  = errors.New("sheet tidak ditemukan")
+	ErrHeaderNotFound = errors.New("baris judul kolom Dapodik tidak ditemukan")
+)
+
+// DapodikDomain interface
+type DapodikDomain interface {
+	// Export renders local data using the Dapodik template column layout
+	Export(ctx context.Context, tenantID, jenis string) ([]byte, error)
+	// Preview shows which local records an import file would create or change
+	Preview(ctx context.Context, tenantID, jenis string, file io.Reader) (*model.DapodikPreview, error)
+	// Apply writes the changes shown by Preview
+	Apply(ctx context.Context, tenantID, jenis string, file io.Reader) (*model.DapodikPreview, error)
+}
+
+type dapodikDomain struct {
+	db       outbound_port.DapodikDatabasePort
+	tenantDB outbound_port.TenantDatabasePort
+}
+
+// NewDapodikDomain creates a new Dapodik sync domain
+func NewDapodikDomain(db outbound_port.DapodikDatabasePort, tenantDB outbound_port.TenantDatabasePort) DapodikDomain {
+	return &dapodikDomain{db: db, tenantDB: tenantDB}
+}
+
+// ==========================================
+// EXPORT
+// ==========================================
+
+func (d *dapodikDomain) Export(ctx context.Context, tenantID, jenis string) ([]byte, error) {
+	l, ok := layouts[jenis]
+	if !ok {
+		return nil, ErrJenisInvalid
+	}
+
+	var rows []map[string]string
+	switch jenis {
+	case model.DapodikJenisSiswa:
+		list, err := d.db.GetSiswa(ctx, tenantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get siswa: %w", err)
+		}
+		for _, s := range list {
+			rows = append(rows, siswaNilai(s))
+		}
+	case model.DapodikJenisGuru:
+		list, err := d.db.GetGuru(ctx, tenantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get guru: %w", err)
+		}
+		for _, g := range list {
+			rows = append(rows, guruNilai(g))
+		}
+	case model.DapodikJenisRombel:
+		list, err := d.db.GetRombel(ctx, tenantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rombel: %w", err)
+		}
+		for _, k := range list {
+			rows = append(rows, rombelNilai(k))
+		}
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+	if err := writeSheet(f, l, d.namaSekolah(tenantID), rows); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (d *dapodikDomain) namaSekolah(tenantID string) string {
+	if d.tenantDB == nil {
+		return ""
+	}
+	tenant, err := d.tenantDB.FindByID(tenantID)
+	if err != nil || tenant == nil {
+		return ""
+	}
+	if tenant.SchoolName != "" {
+		return tenant.SchoolName
+	}
+	return tenant.Name
+}
+
+// ==========================================
+// IMPORT
+// ==========================================
+
+func (d *dapodikDomain) Preview(ctx context.Context, tenantID, jenis string, file io.Reader) (*model.DapodikPreview, error) {
+	preview, _, err := d.plan(ctx, tenantID, jenis, file)
+	return preview, err
+}
+
+func (d *dapodikDomain) Apply(ctx context.Context, tenantID, jenis string, file io.Reader) (*model.DapodikPreview, error) {
+	preview, data, err := d.plan(ctx, tenantID, jenis, file)
+	if err != nil {
+		return nil, err
+	}
+	if len(data.Siswa)+len(data.Guru)+len(data.Rombel) > 0 {
+		if err := d.db.ApplyImport(ctx, tenantID, *data); err != nil {
+			return nil, fmt.Errorf("failed to apply dapodik import: %w", err)
+		}
+	}
+	preview.Applied = true
+	return preview, nil
+}
+
+// plan reads the file, diffs it against local data and builds the records to write
+func (d *dapodikDomain) plan(ctx context.Context, tenantID, jenis string, file io.Reader) (*model.DapodikPreview, *model.DapodikImport, error) {
+	l, ok := layouts[jenis]
+	if !ok {
+		return nil, nil, ErrJenisInvalid
+	}
+	f, err := excelize.OpenReader(file)
+	if err != nil {
+		return nil, nil, ErrFileInvalid
+	}
+	defer f.Close()
+
+	rows, err := readSheet(f, l)
+	if err != nil {
+		return nil, nil, err
+	}
+	rows, rowErrors := normalisasiBaris(rows)
+
+	preview := &model.DapodikPreview{Jenis: jenis}
+	data := &model.DapodikImport{}
+
+	switch jenis {
+	case model.DapodikJenisSiswa:
+		err = d.planSiswa(ctx, tenantID, l, rows, preview, data)
+	case model.DapodikJenisGuru:
+		err = d.planGuru(ctx, tenantID, l, rows, preview, data)
+	case model.DapodikJenisRombel:
+		err = d.planRombel(ctx, tenantID, l, rows, preview, data)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	preview.Errors = append(rowErrors, preview.Errors...)
+	preview.Total = len(preview.Items) + len(preview.Errors)
+	for _, item := range preview.Items {
+		switch item.Aksi {
+		case model.DapodikAksiBaru:
+			preview.Baru++
+		case model.DapodikAksiUbah:
+			preview.Ubah++
+		case model.DapodikAksiSama:
+			preview.Sama++
+		}
+	}
+	return preview, data, nil
+}
+
+// normalisasiBaris canonicalises dates and gender so they compare cleanly with local data
+func normalisasiBaris(rows []barisData) ([]barisData, []model.DapodikBarisError) {
+	var valid []barisData
+	var errs []model.DapodikBarisError
+	for _, row := range rows {
+		if v, ok := row.Nilai["Tanggal Lahir"]; ok {
+			tgl, err := parseTanggal(v)
+			if err != nil {
+				errs = append(errs, model.DapodikBarisError{Baris: row.Baris, Pesan: err.Error()})
+				continue
+			}
+			row.Nilai["Tanggal Lahir"] = formatTanggal(tgl)
+		}
+		if v, ok := row.Nilai["JK"]; ok {
+			row.Nilai["JK"] = normalizeJK(v)
+		}
+		valid = append(valid, row)
+	}
+	return valid, errs
+}
+
+// applyHasil copies diff items into the preview and returns the indexes that need writing
+func applyHasil(hasil diffHasil, preview *model.DapodikPreview) []int {
+	preview.Items = hasil.Items
+	preview.Errors = hasil.Errors
+	var tulis []int
+	for i, item := range hasil.Items {
+		if item.Aksi != model.DapodikAksiSama {
+			tulis = append(tulis, i)
+		}
+	}
+	return tulis
+}
+
+func (d *dapodikDomain) planSiswa(ctx context.Context, tenantID string, l layout, rows []barisData, preview *model.DapodikPreview, data *model.DapodikImport) error {
+	local, err := d.db.GetSiswa(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to get siswa: %w", err)
+	}
+	rombel, err := d.db.GetRombel(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to get rombel: %w", err)
+	}
+	kelasID := map[string]string{}
+	for _, k := range rombel {
+		kelasID[normalizeKunci(k.Nama)] = k.ID
+	}
+
+	byID := map[string]model.Siswa{}
+	localNilai := make([]map[string]string, len(local))
+	ids := make([]string, len(local))
+	for i, s := range local {
+		localNilai[i] = siswaNilai(s)
+		ids[i] = s.ID
+		byID[s.ID] = s
+	}
+
+	hasil := diffNilai(l, localNilai, ids, rows)
+	for _, i := range applyHasil(hasil, preview) {
+		item := hasil.Items[i]
+		s, err := siswaDariNilai(hasil.Merged[i])
+		if err != nil {
+			preview.Errors = append(preview.Errors, model.DapodikBarisError{Baris: item.Baris, Pesan: err.Error()})
+			continue
+		}
+		if s.KelasNama != "" {
+			id, ok := kelasID[normalizeKunci(s.KelasNama)]
+			if !ok {
+				preview.Errors = append(preview.Errors, model.DapodikBarisError{
+					Baris: item.Baris,
+					Pesan: fmt.Sprintf("Rombel %s belum ada, impor rombel terlebih dahulu", s.KelasNama),
+				})
+				continue
+			}
+			s.KelasID = id
+		}
+		if existing, ok := byID[item.LocalID]; ok {
+			s.ID = existing.ID
+			s.Status = existing.Status
+		} else {
+			s.Status = "Aktif"
+		}
+		data.Siswa = append(data.Siswa, s)
+	}
+	preview.Items = withoutErrors(preview.Items, preview.Errors)
+	return nil
+}
+
+func (d *dapodikDomain) planGuru(ctx context.Context, tenantID string, l layout, rows []barisData, preview *model.DapodikPreview, data *model.DapodikImport) error {
+	local, err := d.db.GetGuru(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to get guru: %w", err)
+	}
+
+	localNilai := make([]map[string]string, len(local))
+	ids := make([]string, len(local))
+	for i, g := range local {
+		localNilai[i] = guruNilai(g)
+		ids[i] = g.ID
+	}
+
+	hasil := diffNilai(l, localNilai, ids, rows)
+	for _, i := range applyHasil(hasil, preview) {
+		g, err := guruDariNilai(hasil.Merged[i])
+		if err != nil {
+			preview.Errors = append(preview.Errors, model.DapodikBarisError{Baris: hasil.Items[i].Baris, Pesan: err.Error()})
+			continue
+		}
+		g.ID = hasil.Items[i].LocalID
+		data.Guru = append(data.Guru, g)
+	}
+	preview.Items = withoutErrors(preview.Items, preview.Errors)
+	return nil
+}
+
+func (d *dapodikDomain) planRombel(ctx context.Context, tenantID string, l layout, rows []barisData, preview *model.DapodikPreview, data *model.DapodikImport) error {
+	local, err := d.db.GetRombel(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to get rombel: %w", err)
+	}
+	guru, err := d.db.GetGuru(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to get guru: %w", err)
+	}
+	guruID := map[string]string{}
+	for _, g := range guru {
+		guruID[normalizeKunci(g.Nama)] = g.ID
+	}
+
+	byID := map[string]model.Kelas{}
+	localNilai := make([]map[string]string, len(local))
+	ids := make([]string, len(local))
+	for i, k := range local {
+		localNilai[i] = rombelNilai(k)
+		ids[i] = k.ID
+		byID[k.ID] = k
+	}
+
+	hasil := diffNilai(l, localNilai, ids, rows)
+	for _, i := range applyHasil(hasil, preview) {
+		item := hasil.Items[i]
+		k := rombelDariNilai(hasil.Merged[i])
+		if k.Tingkat == "" {
+			preview.Errors = append(preview.Errors, model.DapodikBarisError{Baris: item.Baris, Pesan: "Tingkat Pendidikan wajib diisi"})
+			continue
+		}
+		if k.WaliKelasNama != "" {
+			id, ok := guruID[normalizeKunci(k.WaliKelasNama)]
+			if !ok {
+				preview.Errors = append(preview.Errors, model.DapodikBarisError{
+					Baris: item.Baris,
+					Pesan: fmt.Sprintf("Wali kelas %s tidak ditemukan di data GTK", k.WaliKelasNama),
+				})
+				continue
+			}
+			k.WaliKelasID = id
+		}
+		if existing, ok := byID[item.LocalID]; ok {
+			k.ID = existing.ID
+			k.Urutan = existing.Urutan
+			k.Status = existing.Status
+		} else {
+			k.Status = "active"
+		}
+		data.Rombel = append(data.Rombel, k)
+	}
+	preview.Items = withoutErrors(preview.Items, preview.Errors)
+	return nil
+}
+
+// withoutErrors drops preview items whose row was rejected while building records
+func withoutErrors(items []model.DapodikDiffItem, errs []model.DapodikBarisError) []model.DapodikDiffItem {
+	gagal := map[int]bool{}
+	for _, e := range errs {
+		gagal[e.Baris] = true
+	}
+	var list []model.DapodikDiffItem
+	for _, item := range items {
+		if !gagal[item.Baris] {
+			list = append(list, item)
+		}
+	}
+	return list
+}
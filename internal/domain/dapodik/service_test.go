@@ -0,0 +1,107 @@
+package dapodik_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain/dapodik"
+	"prabogo/internal/model"
+)
+
+// fakeDapodikDB serves a fixed student list and records what an import writes
+type fakeDapodikDB struct {
+	siswa   []model.Siswa
+	applied *model.DapodikImport
+}
+
+func (f *fakeDapodikDB) GetSiswa(ctx context.Context, tenantID string) ([]model.Siswa, error) {
+	return f.siswa, nil
+}
+
+func (f *fakeDapodikDB) GetGuru(ctx context.Context, tenantID string) ([]model.Guru, error) {
+	return nil, nil
+}
+
+func (f *fakeDapodikDB) GetRombel(ctx context.Context, tenantID string) ([]model.Kelas, error) {
+	return nil, nil
+}
+
+func (f *fakeDapodikDB) ApplyImport(ctx context.Context, tenantID string, data model.DapodikImport) error {
+	f.applied = &data
+	return nil
+}
+
+// siswaXLSX renders incoming rows with the export template, as an operator would upload them
+func siswaXLSX(incoming []model.Siswa) *bytes.Reader {
+	file, err := dapodik.NewDapodikDomain(&fakeDapodikDB{siswa: incoming}, nil).
+		Export(context.Background(), "t1", model.DapodikJenisSiswa)
+	So(err, ShouldBeNil)
+	return bytes.NewReader(file)
+}
+
+func TestImportSiswa(t *testing.T) {
+	Convey("Test Dapodik siswa import", t, func() {
+		ctx := context.Background()
+		db := &fakeDapodikDB{siswa: []model.Siswa{
+			{ID: "s1", Nama: "Ahmad", NIS: "101", NISN: "0012345678", Alamat: "Jl. Mawar", Status: "Aktif"},
+			{ID: "s2", Nama: "Budi", NIS: "102", NIK: "3201010101010001", Status: "Aktif"},
+		}}
+		domain := dapodik.NewDapodikDomain(db, nil)
+
+		Convey("Matches by NISN and lists changed fields only", func() {
+			preview, err := domain.Preview(ctx, "t1", model.DapodikJenisSiswa, siswaXLSX([]model.Siswa{
+				{Nama: "Ahmad", NIS: "101", NISN: "0012345678", Alamat: "Jl. Melati", NamaIbu: "Siti"},
+			}))
+			So(err, ShouldBeNil)
+			So(preview.Errors, ShouldBeEmpty)
+			So(preview.Items, ShouldHaveLength, 1)
+			So(preview.Items[0].Aksi, ShouldEqual, model.DapodikAksiUbah)
+			So(preview.Items[0].LocalID, ShouldEqual, "s1")
+			So(preview.Items[0].Kunci, ShouldEqual, "nisn:0012345678")
+			So(preview.Items[0].Perubahan, ShouldHaveLength, 2)
+			So(preview.Items[0].Perubahan[0], ShouldResemble, model.DapodikPerubahan{Field: "Alamat", Lama: "Jl. Mawar", Baru: "Jl. Melati"})
+		})
+
+		Convey("Treats case-only edits as changes and ignores surrounding spaces", func() {
+			preview, err := domain.Preview(ctx, "t1", model.DapodikJenisSiswa, siswaXLSX([]model.Siswa{
+				{Nama: "AHMAD", NISN: "0012345678"},
+				{Nama: " Budi ", NIK: "3201010101010001"},
+			}))
+			So(err, ShouldBeNil)
+			So(preview.Items[0].Aksi, ShouldEqual, model.DapodikAksiUbah)
+			So(preview.Items[0].Perubahan, ShouldResemble, []model.DapodikPerubahan{{Field: "Nama", Lama: "Ahmad", Baru: "AHMAD"}})
+			So(preview.Items[1].Aksi, ShouldEqual, model.DapodikAksiSama)
+		})
+
+		Convey("Falls back to NIK, ignores empty cells and writes only changed rows on apply", func() {
+			preview, err := domain.Apply(ctx, "t1", model.DapodikJenisSiswa, siswaXLSX([]model.Siswa{
+				{Nama: "Budi", NIK: "3201010101010001"},
+				{Nama: "Citra", NISN: "0099999999"},
+			}))
+			So(err, ShouldBeNil)
+			So(preview.Applied, ShouldBeTrue)
+			So(preview.Sama, ShouldEqual, 1)
+			So(preview.Baru, ShouldEqual, 1)
+			So(preview.Items[0].LocalID, ShouldEqual, "s2")
+			So(db.applied, ShouldNotBeNil)
+			So(db.applied.Siswa, ShouldHaveLength, 1)
+			So(db.applied.Siswa[0].Nama, ShouldEqual, "Citra")
+			So(db.applied.Siswa[0].Status, ShouldEqual, "Aktif")
+		})
+
+		Convey("Rejects rows without a name or matching an already claimed record", func() {
+			preview, err := domain.Preview(ctx, "t1", model.DapodikJenisSiswa, siswaXLSX([]model.Siswa{
+				{NISN: "0011111111"},
+				{Nama: "Ahmad", NISN: "0012345678"},
+				{Nama: "Ahmad", NIS: "101"},
+			}))
+			So(err, ShouldBeNil)
+			So(preview.Items, ShouldHaveLength, 1)
+			So(preview.Errors, ShouldHaveLength, 2)
+			So(preview.Errors[0].Baris, ShouldEqual, 5)
+		})
+	})
+}
@@ -0,0 +1,315 @@
+package dapodik
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+
+	"prabogo/internal/model"
+)
+
+// layout mirrors one sheet of the Dapodik export template
+type layout struct {
+	Sheet string
+	Judul string
+	Kolom []string // Headers in template order, starting with "No"
+	Kunci []string // Matching key headers, highest priority first
+	Nama  string   // Header holding the display name
+}
+
+var layouts = map[string]layout{
+	model.DapodikJenisSiswa: {
+		Sheet: "Peserta Didik",
+		Judul: "Daftar Peserta Didik",
+		Kolom: []string{"No", "Nama", "NIPD", "JK", "NISN", "Tempat Lahir", "Tanggal Lahir", "NIK", "Agama", "Alamat",
+			"Nama Ayah", "Pekerjaan Ayah", "Nama Ibu", "Pekerjaan Ibu", "Nama Wali", "HP", "Rombel Saat Ini"},
+		Kunci: []string{"NISN", "NIK", "NIPD"},
+		Nama:  "Nama",
+	},
+	model.DapodikJenisGuru: {
+		Sheet: "GTK",
+		Judul: "Daftar Guru dan Tenaga Kependidikan",
+		Kolom: []string{"No", "Nama", "NUPTK", "JK", "Tempat Lahir", "Tanggal Lahir", "NIP", "Status Kepegawaian", "Jenis PTK", "NIK"},
+		Kunci: []string{"NUPTK", "NIK", "NIP"},
+		Nama:  "Nama",
+	},
+	model.DapodikJenisRombel: {
+		Sheet: "Rombel",
+		Judul: "Daftar Rombongan Belajar",
+		Kolom: []string{"No", "Nama Rombel", "Tingkat Pendidikan", "Wali Kelas", "Kurikulum"},
+		Kunci: []string{"Nama Rombel"},
+		Nama:  "Nama Rombel",
+	},
+}
+
+// headerRow is the 1-based row of the column headers in exported files
+const headerRow = 4
+
+// barisData is one data row read from a sheet, keyed by header
+type barisData struct {
+	Baris int
+	Nilai map[string]string
+}
+
+// ==========================================
+// MODEL <-> CELL VALUES
+// ==========================================
+
+func formatTanggal(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+// parseTanggal accepts YYYY-MM-DD, DD/MM/YYYY, DD-MM-YYYY or an Excel date serial
+func parseTanggal(v string) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{"2006-01-02", "02/01/2006", "02-01-2006"} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return &t, nil
+		}
+	}
+	if serial, err := strconv.ParseFloat(v, 64); err == nil {
+		if t, err := excelize.ExcelDateToTime(serial, false); err == nil {
+			d := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+			return &d, nil
+		}
+	}
+	return nil, fmt.Errorf("format tanggal tidak dikenali: %s", v)
+}
+
+// normalizeJK maps Dapodik gender labels to L/P
+func normalizeJK(v string) string {
+	switch strings.ToUpper(strings.TrimSpace(v)) {
+	case "L", "LAKI-LAKI", "LAKI LAKI":
+		return "L"
+	case "P", "PEREMPUAN":
+		return "P"
+	}
+	return strings.TrimSpace(v)
+}
+
+func siswaNilai(s model.Siswa) map[string]string {
+	return map[string]string{
+		"Nama":            s.Nama,
+		"NIPD":            s.NIS,
+		"JK":              s.JenisKelamin,
+		"NISN":            s.NISN,
+		"Tempat Lahir":    s.TempatLahir,
+		"Tanggal Lahir":   formatTanggal(s.TanggalLahir),
+		"NIK":             s.NIK,
+		"Agama":           s.Agama,
+		"Alamat":          s.Alamat,
+		"Nama Ayah":       s.NamaAyah,
+		"Pekerjaan Ayah":  s.PekerjaanAyah,
+		"Nama Ibu":        s.NamaIbu,
+		"Pekerjaan Ibu":   s.PekerjaanIbu,
+		"Nama Wali":       s.NamaWali,
+		"HP":              s.NoHPWali,
+		"Rombel Saat Ini": s.KelasNama,
+	}
+}
+
+func siswaDariNilai(v map[string]string) (model.Siswa, error) {
+	tgl, err := parseTanggal(v["Tanggal Lahir"])
+	if err != nil {
+		return model.Siswa{}, err
+	}
+	return model.Siswa{
+		Nama:          v["Nama"],
+		NIS:           v["NIPD"],
+		JenisKelamin:  normalizeJK(v["JK"]),
+		NISN:          v["NISN"],
+		TempatLahir:   v["Tempat Lahir"],
+		TanggalLahir:  tgl,
+		NIK:           v["NIK"],
+		Agama:         v["Agama"],
+		Alamat:        v["Alamat"],
+		NamaAyah:      v["Nama Ayah"],
+		PekerjaanAyah: v["Pekerjaan Ayah"],
+		NamaIbu:       v["Nama Ibu"],
+		PekerjaanIbu:  v["Pekerjaan Ibu"],
+		NamaWali:      v["Nama Wali"],
+		NoHPWali:      v["HP"],
+		KelasNama:     v["Rombel Saat Ini"],
+	}, nil
+}
+
+func guruNilai(g model.Guru) map[string]string {
+	return map[string]string{
+		"Nama":               g.Nama,
+		"NUPTK":              g.NUPTK,
+		"JK":                 g.JenisKelamin,
+		"Tempat Lahir":       g.TempatLahir,
+		"Tanggal Lahir":      formatTanggal(g.TanggalLahir),
+		"NIP":                g.NIP,
+		"Status Kepegawaian": g.Status,
+		"Jenis PTK":          g.Jenis,
+		"NIK":                g.NIK,
+	}
+}
+
+func guruDariNilai(v map[string]string) (model.Guru, error) {
+	tgl, err := parseTanggal(v["Tanggal Lahir"])
+	if err != nil {
+		return model.Guru{}, err
+	}
+	return model.Guru{
+		Nama:         v["Nama"],
+		NUPTK:        v["NUPTK"],
+		JenisKelamin: normalizeJK(v["JK"]),
+		TempatLahir:  v["Tempat Lahir"],
+		TanggalLahir: tgl,
+		NIP:          v["NIP"],
+		Status:       v["Status Kepegawaian"],
+		Jenis:        v["Jenis PTK"],
+		NIK:          v["NIK"],
+	}, nil
+}
+
+func rombelNilai(k model.Kelas) map[string]string {
+	return map[string]string{
+		"Nama Rombel":        k.Nama,
+		"Tingkat Pendidikan": k.Tingkat,
+		"Wali Kelas":         k.WaliKelasNama,
+		"Kurikulum":          k.Kurikulum,
+	}
+}
+
+func rombelDariNilai(v map[string]string) model.Kelas {
+	return model.Kelas{
+		Nama:          v["Nama Rombel"],
+		Tingkat:       v["Tingkat Pendidikan"],
+		WaliKelasNama: v["Wali Kelas"],
+		Kurikulum:     v["Kurikulum"],
+	}
+}
+
+// ==========================================
+// XLSX READ / WRITE
+// ==========================================
+
+// writeSheet renders rows using the template layout: title, school name, then headers at headerRow
+func writeSheet(f *excelize.File, l layout, namaSekolah string, rows []map[string]string) error {
+	index, err := f.NewSheet(l.Sheet)
+	if err != nil {
+		return err
+	}
+	f.SetActiveSheet(index)
+	f.DeleteSheet("Sheet1")
+
+	titleStyle, _ := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true, Size: 14}})
+	headerStyle, _ := f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true},
+		Fill:      excelize.Fill{Type: "pattern", Color: []string{"D9D9D9"}, Pattern: 1},
+		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center"},
+		Border: []excelize.Border{
+			{Type: "left", Color: "000000", Style: 1},
+			{Type: "top", Color: "000000", Style: 1},
+			{Type: "bottom", Color: "000000", Style: 1},
+			{Type: "right", Color: "000000", Style: 1},
+		},
+	})
+
+	f.SetCellValue(l.Sheet, "A1", l.Judul)
+	f.SetCellStyle(l.Sheet, "A1", "A1", titleStyle)
+	f.SetCellValue(l.Sheet, "A2", namaSekolah)
+	f.SetCellValue(l.Sheet, "A3", fmt.Sprintf("Tanggal Unduh: %s", time.Now().Format("2006-01-02 15:04")))
+
+	for i, h := range l.Kolom {
+		cell, _ := excelize.CoordinatesToCellName(i+1, headerRow)
+		f.SetCellValue(l.Sheet, cell, h)
+		f.SetCellStyle(l.Sheet, cell, cell, headerStyle)
+		col, _ := excelize.ColumnNumberToName(i + 1)
+		width := 18.0
+		if h == "No" {
+			width = 5
+		}
+		f.SetColWidth(l.Sheet, col, col, width)
+	}
+
+	for r, nilai := range rows {
+		row := headerRow + 1 + r
+		for i, h := range l.Kolom {
+			cell, _ := excelize.CoordinatesToCellName(i+1, row)
+			if h == "No" {
+				f.SetCellValue(l.Sheet, cell, r+1)
+				continue
+			}
+			// Keep NISN, NIK etc. as text so leading zeros survive
+			f.SetCellStr(l.Sheet, cell, nilai[h])
+		}
+	}
+	return nil
+}
+
+// readSheet locates the header row by name (tolerating title rows above it) and returns the data rows
+func readSheet(f *excelize.File, l layout) ([]barisData, error) {
+	sheet := l.Sheet
+	if idx, _ := f.GetSheetIndex(sheet); idx < 0 {
+		// Fall back to the first sheet for files renamed by the operator
+		sheet = f.GetSheetName(0)
+	}
+	rows, err := f.GetRows(sheet)
+	if err != nil {
+		return nil, ErrSheetNotFound
+	}
+
+	header := -1
+	kolom := map[int]string{}
+	for i, row := range rows {
+		found := map[int]string{}
+		for j, cell := range row {
+			name := strings.TrimSpace(cell)
+			for _, h := range l.Kolom {
+				if strings.EqualFold(name, h) {
+					found[j] = h
+				}
+			}
+		}
+		if hasHeader(found, l.Nama) && hasHeader(found, l.Kunci[0]) {
+			header, kolom = i, found
+			break
+		}
+	}
+	if header < 0 {
+		return nil, ErrHeaderNotFound
+	}
+
+	var list []barisData
+	for i := header + 1; i < len(rows); i++ {
+		nilai := map[string]string{}
+		kosong := true
+		for j, cell := range rows[i] {
+			h, ok := kolom[j]
+			if !ok || h == "No" {
+				continue
+			}
+			v := strings.TrimSpace(cell)
+			if v != "" {
+				kosong = false
+			}
+			nilai[h] = v
+		}
+		if kosong {
+			continue
+		}
+		list = append(list, barisData{Baris: i + 1, Nilai: nilai})
+	}
+	return list, nil
+}
+
+func hasHeader(found map[int]string, h string) bool {
+	for _, v := range found {
+		if v == h {
+			return true
+		}
+	}
+	return false
+}
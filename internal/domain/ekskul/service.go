@@ -0,0 +1,258 @@
+package ekskul
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+)
+
+// EkskulDomain interface
+type EkskulDomain interface {
+	// Ekskul master
+	GetEkskulList(ctx context.Context, tenantID string) ([]model.Ekskul, error)
+	CreateEkskul(ctx context.Context, ekskul *model.Ekskul) error
+	UpdateEkskul(ctx context.Context, ekskul *model.Ekskul) error
+	DeleteEkskul(ctx context.Context, tenantID, id string) error
+
+	// Anggota
+	GetAnggota(ctx context.Context, tenantID, ekskulID, semesterID string) ([]model.EkskulAnggota, error)
+	AddAnggota(ctx context.Context, anggota *model.EkskulAnggota) error
+	RemoveAnggota(ctx context.Context, tenantID, id string) error
+
+	// Sesi & presensi
+	GetSesi(ctx context.Context, tenantID, ekskulID, semesterID string) ([]model.EkskulSesi, error)
+	CreateSesi(ctx context.Context, sesi *model.EkskulSesi) error
+
+	// Penilaian
+	SavePenilaian(ctx context.Context, tenantID string, inputs []model.EkskulPenilaianInput) error
+}
+
+type ekskulDomain struct {
+	databasePort outbound_port.DatabasePort
+	db           outbound_port.EkskulDatabasePort
+}
+
+func NewEkskulDomain(databasePort outbound_port.DatabasePort) EkskulDomain {
+	return &ekskulDomain{databasePort: databasePort, db: databasePort.Ekskul()}
+}
+
+// ==========================================
+// EKSKUL MASTER
+// ==========================================
+
+func (d *ekskulDomain) GetEkskulList(ctx context.Context, tenantID string) ([]model.Ekskul, error) {
+	return d.db.GetEkskulByTenant(ctx, tenantID)
+}
+
+func (d *ekskulDomain) CreateEkskul(ctx context.Context, ekskul *model.Ekskul) error {
+	if strings.TrimSpace(ekskul.Nama) == "" {
+		return errors.New("nama ekskul wajib diisi")
+	}
+	if err := d.cekPembina(ctx, ekskul); err != nil {
+		return err
+	}
+	return d.db.CreateEkskul(ctx, ekskul)
+}
+
+func (d *ekskulDomain) UpdateEkskul(ctx context.Context, ekskul *model.Ekskul) error {
+	existing, err := d.db.GetEkskulByID(ctx, ekskul.TenantID, ekskul.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return errors.New("ekskul tidak ditemukan")
+	}
+	if err := d.cekPembina(ctx, ekskul); err != nil {
+		return err
+	}
+	return d.db.UpdateEkskul(ctx, ekskul)
+}
+
+// cekPembina memastikan pembina yang dipilih adalah guru tenant yang sama
+func (d *ekskulDomain) cekPembina(ctx context.Context, ekskul *model.Ekskul) error {
+	if ekskul.PembinaID == nil || *ekskul.PembinaID == "" {
+		ekskul.PembinaID = nil
+		return nil
+	}
+	ok, err := d.db.GuruExists(ctx, ekskul.TenantID, *ekskul.PembinaID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("pembina tidak ditemukan")
+	}
+	return nil
+}
+
+func (d *ekskulDomain) DeleteEkskul(ctx context.Context, tenantID, id string) error {
+	return d.db.DeleteEkskul(ctx, tenantID, id)
+}
+
+// ==========================================
+// ANGGOTA
+// ==========================================
+
+func (d *ekskulDomain) GetAnggota(ctx context.Context, tenantID, ekskulID, semesterID string) ([]model.EkskulAnggota, error) {
+	return d.db.GetAnggotaByEkskul(ctx, tenantID, ekskulID, semesterID)
+}
+
+func (d *ekskulDomain) AddAnggota(ctx context.Context, anggota *model.EkskulAnggota) error {
+	if anggota.SantriID == "" || anggota.SemesterID == "" {
+		return errors.New("santri_id dan semester_id wajib diisi")
+	}
+	ekskul, err := d.db.GetEkskulByID(ctx, anggota.TenantID, anggota.EkskulID)
+	if err != nil {
+		return err
+	}
+	if ekskul == nil {
+		return errors.New("ekskul tidak ditemukan")
+	}
+	ok, err := d.db.SantriExists(ctx, anggota.TenantID, anggota.SantriID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("santri tidak ditemukan")
+	}
+	return d.db.AddAnggota(ctx, anggota)
+}
+
+func (d *ekskulDomain) RemoveAnggota(ctx context.Context, tenantID, id string) error {
+	return d.db.RemoveAnggota(ctx, tenantID, id)
+}
+
+// ==========================================
+// SESI & PRESENSI
+// ==========================================
+
+func (d *ekskulDomain) GetSesi(ctx context.Context, tenantID, ekskulID, semesterID string) ([]model.EkskulSesi, error) {
+	return d.db.GetSesiByEkskul(ctx, tenantID, ekskulID, semesterID)
+}
+
+// CreateSesi records a meeting together with its attendance list
+func (d *ekskulDomain) CreateSesi(ctx context.Context, sesi *model.EkskulSesi) error {
+	if sesi.SemesterID == "" {
+		return errors.New("semester_id wajib diisi")
+	}
+	ekskul, err := d.db.GetEkskulByID(ctx, sesi.TenantID, sesi.EkskulID)
+	if err != nil {
+		return err
+	}
+	if ekskul == nil {
+		return errors.New("ekskul tidak ditemukan")
+	}
+
+	_, err = d.databasePort.DoInTransaction(func(tx outbound_port.DatabasePort) (interface{}, error) {
+		anggota, err := tx.Ekskul().GetAnggotaByEkskul(ctx, sesi.TenantID, sesi.EkskulID, sesi.SemesterID)
+		if err != nil {
+			return nil, err
+		}
+		if err := ValidasiPresensi(sesi.Presensi, anggota); err != nil {
+			return nil, err
+		}
+		if err := tx.Ekskul().CreateSesi(ctx, sesi); err != nil {
+			return nil, err
+		}
+		if len(sesi.Presensi) == 0 {
+			return nil, nil
+		}
+		return nil, tx.Ekskul().SavePresensi(ctx, sesi.ID, sesi.Presensi)
+	})
+	return err
+}
+
+// ValidasiPresensi memastikan setiap baris presensi milik anggota ekskul
+// pada semester sesi dan berstatus sah. Status kosong dianggap Hadir.
+func ValidasiPresensi(presensi []model.EkskulPresensi, anggota []model.EkskulAnggota) error {
+	terdaftar := make(map[string]bool, len(anggota))
+	for _, a := range anggota {
+		terdaftar[a.ID] = true
+	}
+	dicatat := make(map[string]bool, len(presensi))
+	for i, p := range presensi {
+		if !terdaftar[p.AnggotaID] {
+			return fmt.Errorf("anggota %s bukan anggota ekskul pada semester ini", p.AnggotaID)
+		}
+		if dicatat[p.AnggotaID] {
+			return fmt.Errorf("presensi anggota %s tercatat lebih dari sekali", p.AnggotaID)
+		}
+		dicatat[p.AnggotaID] = true
+
+		switch p.Status {
+		case "":
+			presensi[i].Status = model.EkskulPresensiHadir
+		case model.EkskulPresensiHadir, model.EkskulPresensiIzin, model.EkskulPresensiSakit, model.EkskulPresensiAlpha:
+		default:
+			return fmt.Errorf("status presensi tidak valid: %s", p.Status)
+		}
+	}
+	return nil
+}
+
+// ==========================================
+// PENILAIAN
+// ==========================================
+
+// SavePenilaian menyimpan predikat akhir semester. Jika predikat kosong,
+// predikat disarankan dari persentase kehadiran anggota.
+func (d *ekskulDomain) SavePenilaian(ctx context.Context, tenantID string, inputs []model.EkskulPenilaianInput) error {
+	for _, input := range inputs {
+		anggota, err := d.db.GetAnggotaByID(ctx, tenantID, input.AnggotaID)
+		if err != nil {
+			return err
+		}
+		if anggota == nil {
+			return fmt.Errorf("anggota %s tidak ditemukan", input.AnggotaID)
+		}
+
+		predikat := strings.ToUpper(strings.TrimSpace(input.Predikat))
+		if predikat == "" {
+			predikat = SuggestPredikat(anggota.JumlahHadir, anggota.JumlahSesi)
+		}
+		if predikat != "A" && predikat != "B" && predikat != "C" {
+			return fmt.Errorf("predikat tidak valid: %s", input.Predikat)
+		}
+
+		deskripsi := input.Deskripsi
+		if deskripsi == "" {
+			deskripsi = DefaultDeskripsi(predikat, anggota.EkskulNama)
+		}
+
+		if err := d.db.UpdatePenilaian(ctx, tenantID, anggota.ID, predikat, deskripsi); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SuggestPredikat menentukan predikat dari rasio kehadiran
+func SuggestPredikat(hadir, sesi int) string {
+	if sesi == 0 {
+		return "B"
+	}
+	ratio := float64(hadir) / float64(sesi)
+	switch {
+	case ratio >= 0.9:
+		return "A"
+	case ratio >= 0.75:
+		return "B"
+	default:
+		return "C"
+	}
+}
+
+// DefaultDeskripsi menghasilkan deskripsi rapor berdasarkan predikat
+func DefaultDeskripsi(predikat, ekskulNama string) string {
+	switch predikat {
+	case "A":
+		return "Sangat aktif dan menunjukkan prestasi dalam kegiatan " + ekskulNama
+	case "B":
+		return "Aktif mengikuti kegiatan " + ekskulNama
+	default:
+		return "Perlu meningkatkan keaktifan dalam kegiatan " + ekskulNama
+	}
+}
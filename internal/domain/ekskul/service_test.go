@@ -0,0 +1,37 @@
+package ekskul_test
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain/ekskul"
+	"prabogo/internal/model"
+)
+
+func TestValidasiPresensi(t *testing.T) {
+	Convey("Test ValidasiPresensi", t, func() {
+		anggota := []model.EkskulAnggota{{ID: "a1"}, {ID: "a2"}}
+
+		Convey("Accepts members of the session and defaults empty status to Hadir", func() {
+			presensi := []model.EkskulPresensi{
+				{AnggotaID: "a1"},
+				{AnggotaID: "a2", Status: model.EkskulPresensiSakit},
+			}
+			So(ekskul.ValidasiPresensi(presensi, anggota), ShouldBeNil)
+			So(presensi[0].Status, ShouldEqual, model.EkskulPresensiHadir)
+			So(presensi[1].Status, ShouldEqual, model.EkskulPresensiSakit)
+		})
+
+		Convey("Rejects anggota outside the ekskul semester", func() {
+			presensi := []model.EkskulPresensi{{AnggotaID: "lain", Status: model.EkskulPresensiHadir}}
+			So(ekskul.ValidasiPresensi(presensi, anggota), ShouldNotBeNil)
+			So(ekskul.ValidasiPresensi(presensi, nil), ShouldNotBeNil)
+		})
+
+		Convey("Rejects duplicate rows and unknown statuses", func() {
+			So(ekskul.ValidasiPresensi([]model.EkskulPresensi{{AnggotaID: "a1"}, {AnggotaID: "a1"}}, anggota), ShouldNotBeNil)
+			So(ekskul.ValidasiPresensi([]model.EkskulPresensi{{AnggotaID: "a1", Status: "Bolos"}}, anggota), ShouldNotBeNil)
+		})
+	})
+}
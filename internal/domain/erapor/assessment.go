@@ -0,0 +1,324 @@
+package erapor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"prabogo/internal/domain/erapor/engine"
+	"prabogo/internal/model"
+	"prabogo/utils/log"
+)
+
+var (
+	ErrSubjectNotFound    = errors.New("mata pelajaran tidak ditemukan")
+	ErrAssessmentNotFound = errors.New("penilaian tidak ditemukan")
+)
+
+// AggregateComponents combines the assessment entries of every configured component into one
+// score using the component's aggregation rule. Components without entries are left out so the
+// validator only weighs what has actually been assessed.
+func AggregateComponents(entries []model.AssessmentEntry, config model.GradingConfig) map[string]float64 {
+	byComponent := make(map[string][]model.AssessmentEntry)
+	for _, e := range entries {
+		byComponent[e.Component] = append(byComponent[e.Component], e)
+	}
+
+	result := make(map[string]float64)
+	for _, comp := range config.Components {
+		list := byComponent[comp.Name]
+		if len(list) == 0 {
+			continue
+		}
+		result[comp.Name] = aggregate(list, comp.Aggregation)
+	}
+	return result
+}
+
+func aggregate(entries []model.AssessmentEntry, rule string) float64 {
+	switch rule {
+	case model.AggregationLatest:
+		sorted := append([]model.AssessmentEntry(nil), entries...)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			if !sorted[i].Date.Equal(sorted[j].Date) {
+				return sorted[i].Date.Before(sorted[j].Date)
+			}
+			return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+		})
+		return sorted[len(sorted)-1].Score
+	case model.AggregationBest:
+		best := entries[0].Score
+		for _, e := range entries[1:] {
+			if e.Score > best {
+				best = e.Score
+			}
+		}
+		return best
+	default:
+		var total float64
+		for _, e := range entries {
+			total += e.Score
+		}
+		return total / float64(len(entries))
+	}
+}
+
+// GetAssessmentEntries lists raw assessments; studentID or subjectID may be empty to list a whole class/subject
+func (s *Service) GetAssessmentEntries(ctx context.Context, tenantID, studentID, subjectID, semesterID string) ([]model.AssessmentEntry, error) {
+	return s.db.GetAssessmentEntries(ctx, tenantID, studentID, subjectID, semesterID)
+}
+
+// AddAssessmentEntry records an assessment and recalculates the student's final grade for the subject
+func (s *Service) AddAssessmentEntry(ctx context.Context, input *model.AssessmentEntryInput) (*model.AssessmentEntry, *model.StudentGrade, error) {
+	if input.StudentID == "" || input.SubjectID == "" || input.SemesterID == "" {
+		return nil, nil, errors.New("student_id, subject_id dan semester_id wajib diisi")
+	}
+	subject, err := s.subjectForTenant(ctx, input.TenantID, input.SubjectID)
+	if err != nil {
+		return nil, nil, err
+	}
+	siswa, err := s.db.GetSiswaWali(ctx, input.TenantID, input.StudentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if siswa == nil {
+		return nil, nil, ErrSiswaNotFound
+	}
+
+	entry := &model.AssessmentEntry{
+		TenantID:   input.TenantID,
+		StudentID:  input.StudentID,
+		SubjectID:  input.SubjectID,
+		SemesterID: input.SemesterID,
+	}
+	if err := applyAssessmentInput(entry, input, subject.GradingConfig); err != nil {
+		return nil, nil, err
+	}
+	if err := s.ensureNilaiTerbuka(ctx, entry.TenantID, entry.SemesterID, entry.StudentID); err != nil {
+		return nil, nil, err
+	}
+
+	// The entry is rolled back when the recalculated grade fails validation
+	var grade *model.StudentGrade
+	err = s.inTransaction(func(tx *Service) error {
+		if err := tx.db.CreateAssessmentEntry(ctx, entry); err != nil {
+			return err
+		}
+		grade, err = tx.recalculateGrade(ctx, subject, entry.StudentID, entry.SemesterID)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return entry, grade, nil
+}
+
+// UpdateAssessmentEntry corrects an assessment; the student, subject and semester stay fixed
+func (s *Service) UpdateAssessmentEntry(ctx context.Context, tenantID, id string, input *model.AssessmentEntryInput) (*model.AssessmentEntry, *model.StudentGrade, error) {
+	entry, err := s.db.GetAssessmentEntry(ctx, tenantID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if entry == nil {
+		return nil, nil, ErrAssessmentNotFound
+	}
+	subject, err := s.subjectForTenant(ctx, tenantID, entry.SubjectID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := applyAssessmentInput(entry, input, subject.GradingConfig); err != nil {
+		return nil, nil, err
+	}
+	if err := s.ensureNilaiTerbuka(ctx, tenantID, entry.SemesterID, entry.StudentID); err != nil {
+		return nil, nil, err
+	}
+
+	var grade *model.StudentGrade
+	err = s.inTransaction(func(tx *Service) error {
+		if err := tx.db.UpdateAssessmentEntry(ctx, entry); err != nil {
+			return err
+		}
+		grade, err = tx.recalculateGrade(ctx, subject, entry.StudentID, entry.SemesterID)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return entry, grade, nil
+}
+
+// DeleteAssessmentEntry removes an assessment and recalculates the final grade from the rest
+func (s *Service) DeleteAssessmentEntry(ctx context.Context, tenantID, id string) (*model.StudentGrade, error) {
+	entry, err := s.db.GetAssessmentEntry(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, ErrAssessmentNotFound
+	}
+	subject, err := s.subjectForTenant(ctx, tenantID, entry.SubjectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureNilaiTerbuka(ctx, tenantID, entry.SemesterID, entry.StudentID); err != nil {
+		return nil, err
+	}
+
+	var grade *model.StudentGrade
+	err = s.inTransaction(func(tx *Service) error {
+		if err := tx.db.DeleteAssessmentEntry(ctx, tenantID, id); err != nil {
+			return err
+		}
+		grade, err = tx.recalculateGrade(ctx, subject, entry.StudentID, entry.SemesterID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return grade, nil
+}
+
+func (s *Service) subjectForTenant(ctx context.Context, tenantID, subjectID string) (*model.Subject, error) {
+	subject, err := s.db.GetSubjectByID(ctx, subjectID)
+	if err != nil {
+		return nil, err
+	}
+	if subject == nil || subject.TenantID != tenantID {
+		return nil, ErrSubjectNotFound
+	}
+	if err := s.resolveGrading(ctx, subject); err != nil {
+		return nil, err
+	}
+	return subject, nil
+}
+
+func applyAssessmentInput(entry *model.AssessmentEntry, input *model.AssessmentEntryInput, config model.GradingConfig) error {
+	known := false
+	for _, comp := range config.Components {
+		if comp.Name == input.Component {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return fmt.Errorf("komponen %q tidak ada di konfigurasi penilaian mata pelajaran", input.Component)
+	}
+	if strings.TrimSpace(input.Title) == "" {
+		return errors.New("judul penilaian wajib diisi")
+	}
+	if input.Score < 0 || input.Score > 100 {
+		return errors.New("nilai harus di antara 0 dan 100")
+	}
+
+	now := time.Now()
+	date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if input.Date != "" {
+		parsed, err := time.Parse("2006-01-02", input.Date)
+		if err != nil {
+			return errors.New("format tanggal harus YYYY-MM-DD")
+		}
+		date = parsed
+	}
+
+	entry.Component = input.Component
+	entry.Title = strings.TrimSpace(input.Title)
+	entry.Topic = strings.TrimSpace(input.Topic)
+	entry.Date = date
+	entry.Score = input.Score
+	return nil
+}
+
+// recalculateGrade rebuilds the final grade of one student from their assessment entries through
+// the subject's curriculum validator. Without any entries the stored grade is left untouched.
+func (s *Service) recalculateGrade(ctx context.Context, subject *model.Subject, studentID, semesterID string) (*model.StudentGrade, error) {
+	subject, err := s.subjectForSemester(ctx, subject, semesterID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.db.GetAssessmentEntries(ctx, subject.TenantID, studentID, subject.ID, semesterID)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	components := AggregateComponents(entries, subject.GradingConfig)
+	validator := engine.ValidatorFor(subject)
+	if err := validator.ValidateComponents(components, subject.GradingConfig); err != nil {
+		return nil, err
+	}
+	result := validator.CalculateGrade(components, subject.GradingConfig)
+	floor, err := s.remedialFloor(ctx, subject, studentID, semesterID, result.ScoreNumeric)
+	if err != nil {
+		return nil, err
+	}
+	if floor > result.ScoreNumeric {
+		result = scoreResult(subject, floor)
+	}
+	// Merdeka capaian come from the TP marks when there are any
+	if high, low, ok, err := s.tpDescriptions(ctx, subject, studentID, semesterID); err != nil {
+		return nil, err
+	} else if ok {
+		result.DescriptionHigh, result.DescriptionLow = high, low
+	}
+
+	// ComponentScores stays positional to GradingConfig.Components for existing readers
+	scores := make([]float64, len(subject.GradingConfig.Components))
+	for i, comp := range subject.GradingConfig.Components {
+		scores[i] = components[comp.Name]
+	}
+
+	input := &model.StudentGradeInput{
+		TenantID:        subject.TenantID,
+		StudentID:       studentID,
+		SubjectID:       subject.ID,
+		SemesterID:      semesterID,
+		ScoreNumeric:    result.ScoreNumeric,
+		ScorePredicate:  result.ScorePredicate,
+		DescriptionHigh: result.DescriptionHigh,
+		DescriptionLow:  result.DescriptionLow,
+		ComponentScores: scores,
+	}
+	if err := s.keepManualDescription(ctx, input); err != nil {
+		return nil, err
+	}
+	return s.db.SaveGrade(ctx, input)
+}
+
+// recalculateSubject refreshes every assessed grade of a subject after its grading config changed.
+// Grades locked by a submitted rapor are skipped; they follow once the rapor is unlocked and edited.
+func (s *Service) recalculateSubject(ctx context.Context, subject *model.Subject) {
+	if err := s.resolveGrading(ctx, subject); err != nil {
+		log.WithContext(ctx).WithError(err).Errorf("failed to resolve grading of subject %s", subject.ID)
+		return
+	}
+	pairs, err := s.db.GetAssessedStudents(ctx, subject.TenantID, subject.ID)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Errorf("failed to list assessed students of subject %s", subject.ID)
+		return
+	}
+
+	bySemester := make(map[string][]string)
+	for _, p := range pairs {
+		bySemester[p.SemesterID] = append(bySemester[p.SemesterID], p.StudentID)
+	}
+	for semesterID, studentIDs := range bySemester {
+		statuses, err := s.db.GetRaporStatusBySiswa(ctx, subject.TenantID, semesterID, studentIDs)
+		if err != nil {
+			log.WithContext(ctx).WithError(err).Errorf("failed to check rapor locks of subject %s", subject.ID)
+			continue
+		}
+		for _, studentID := range studentIDs {
+			if IsNilaiTerkunci(statuses[studentID]) {
+				continue
+			}
+			if _, err := s.recalculateGrade(ctx, subject, studentID, semesterID); err != nil {
+				log.WithContext(ctx).WithError(err).Errorf("failed to recalculate grade of student %s subject %s", studentID, subject.ID)
+			}
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package erapor_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain/erapor"
+	"prabogo/internal/model"
+)
+
+func TestAggregateComponents(t *testing.T) {
+	Convey("Test AggregateComponents", t, func() {
+		day := func(d int) time.Time { return time.Date(2025, 8, d, 0, 0, 0, 0, time.UTC) }
+		entries := []model.AssessmentEntry{
+			{Component: "Sumatif", Score: 90, Date: day(20)},
+			{Component: "Sumatif", Score: 70, Date: day(1)},
+			{Component: "Formatif", Score: 60, Date: day(5)},
+			{Component: "Formatif", Score: 80, Date: day(10)},
+			{Component: "Praktik", Score: 95, Date: day(3)},
+		}
+
+		Convey("Uses the component aggregation rule", func() {
+			config := model.GradingConfig{Components: []model.GradingComponent{
+				{Name: "Sumatif", Weight: 60, Aggregation: model.AggregationLatest},
+				{Name: "Formatif", Weight: 40},
+				{Name: "Praktik", Weight: 0, Aggregation: model.AggregationBest},
+			}}
+
+			result := erapor.AggregateComponents(entries, config)
+			So(result["Sumatif"], ShouldEqual, 90)
+			So(result["Formatif"], ShouldEqual, 70)
+			So(result["Praktik"], ShouldEqual, 95)
+		})
+
+		Convey("Leaves out components without entries or outside the config", func() {
+			config := model.GradingConfig{Components: []model.GradingComponent{
+				{Name: "Sumatif", Weight: 50, Aggregation: model.AggregationBest},
+				{Name: "Proyek", Weight: 50},
+			}}
+
+			result := erapor.AggregateComponents(entries, config)
+			So(result, ShouldResemble, map[string]float64{"Sumatif": 90})
+		})
+	})
+}
+
+func TestApplyRemedial(t *testing.T) {
+	Convey("Test ApplyRemedial", t, func() {
+		Convey("Caps the remedial result at KKM when configured", func() {
+			final, capped := erapor.ApplyRemedial(60, 90, 75, true)
+			So(final, ShouldEqual, 75)
+			So(capped, ShouldBeTrue)
+
+			final, capped = erapor.ApplyRemedial(60, 90, 75, false)
+			So(final, ShouldEqual, 90)
+			So(capped, ShouldBeFalse)
+		})
+
+		Convey("Never lowers the original score", func() {
+			final, capped := erapor.ApplyRemedial(70, 65, 75, true)
+			So(final, ShouldEqual, 70)
+			So(capped, ShouldBeFalse)
+		})
+
+		Convey("Falls back to the default KKM", func() {
+			So(erapor.KKMOf(model.GradingConfig{}), ShouldEqual, 75)
+			So(erapor.KKMOf(model.GradingConfig{KKMValue: 70}), ShouldEqual, 70)
+		})
+	})
+}
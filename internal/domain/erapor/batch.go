@@ -0,0 +1,258 @@
+package erapor
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"prabogo/internal/model"
+	"prabogo/utils/log"
+	pdf_utils "prabogo/utils/pdf"
+)
+
+var (
+	ErrBatchNotFound       = errors.New("batch rapor tidak ditemukan")
+	ErrBatchBelumSelesai   = errors.New("batch rapor belum selesai diproses")
+	ErrWorkerTidakTersedia = errors.New("worker antrian tidak tersedia")
+)
+
+// CreateBatch queues rapor generation for every active siswa of a kelas. The roster and the
+// per-student catatan/kehadiran are stored with the batch; the work itself runs on the message worker.
+func (s *Service) CreateBatch(ctx context.Context, tenantID string, in model.RaporBatchInput) (*model.RaporBatch, error) {
+	if in.KelasID == "" || in.SemesterID == "" {
+		return nil, errors.New("kelas_id dan semester_id wajib diisi")
+	}
+	if s.messagePort == nil || s.messagePort.Rapor() == nil {
+		return nil, ErrWorkerTidakTersedia
+	}
+
+	roster, err := s.db.GetSiswaByKelas(ctx, tenantID, in.KelasID)
+	if err != nil {
+		return nil, err
+	}
+	if len(roster) == 0 {
+		return nil, errors.New("tidak ada siswa aktif di kelas ini")
+	}
+	overrides := make(map[string]model.RaporBatchSiswa, len(in.Siswa))
+	for _, o := range in.Siswa {
+		overrides[o.SiswaID] = o
+	}
+	for i := range roster {
+		if o, ok := overrides[roster[i].SiswaID]; ok {
+			roster[i].CatatanWali = o.CatatanWali
+			roster[i].Kehadiran = o.Kehadiran
+		}
+	}
+
+	batch := &model.RaporBatch{
+		TenantID:   tenantID,
+		KelasID:    in.KelasID,
+		SemesterID: in.SemesterID,
+		Status:     model.RaporBatchStatusQueued,
+		Total:      len(roster),
+		Siswa:      roster,
+		Errors:     []model.RaporBatchError{},
+	}
+	if err := s.db.CreateRaporBatch(ctx, batch); err != nil {
+		return nil, err
+	}
+
+	msg := model.RaporBatchMessage{TenantID: tenantID, BatchID: batch.ID}
+	if err := s.messagePort.Rapor().PublishGenerateBatch(msg); err != nil {
+		now := time.Now()
+		batch.Status = model.RaporBatchStatusFailed
+		batch.FinishedAt = &now
+		batch.Errors = append(batch.Errors, model.RaporBatchError{Pesan: "gagal mengirim ke antrian: " + err.Error()})
+		if errUpdate := s.db.UpdateRaporBatchProgress(ctx, batch); errUpdate != nil {
+			log.WithContext(ctx).WithError(errUpdate).Errorf("failed to mark rapor batch %s failed", batch.ID)
+		}
+		return nil, err
+	}
+
+	return batch, nil
+}
+
+// GetBatch returns a batch with its progress and per-student errors
+func (s *Service) GetBatch(ctx context.Context, tenantID, id string) (*model.RaporBatch, error) {
+	batch, err := s.db.GetRaporBatch(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if batch == nil {
+		return nil, ErrBatchNotFound
+	}
+	return batch, nil
+}
+
+// GetBatchList returns the tenant's batches, newest first
+func (s *Service) GetBatchList(ctx context.Context, tenantID string) ([]model.RaporBatch, error) {
+	return s.db.GetRaporBatchList(ctx, tenantID)
+}
+
+// DownloadBatch returns the ZIP or merged PDF of a finished batch
+func (s *Service) DownloadBatch(ctx context.Context, tenantID, id, jenis string) ([]byte, *model.RaporBatch, error) {
+	if jenis != model.RaporBatchFileZip && jenis != model.RaporBatchFilePDF {
+		return nil, nil, fmt.Errorf("jenis file harus %s atau %s", model.RaporBatchFileZip, model.RaporBatchFilePDF)
+	}
+	batch, err := s.GetBatch(ctx, tenantID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if batch.Status != model.RaporBatchStatusDone {
+		return nil, nil, ErrBatchBelumSelesai
+	}
+	file, err := s.db.GetRaporBatchFile(ctx, tenantID, id, jenis)
+	if err != nil {
+		return nil, nil, err
+	}
+	return file, batch, nil
+}
+
+// ProcessBatch is run by the message worker: it generates (or reuses) the rapor snapshot of
+// every student, renders the PDFs and stores them as one ZIP plus one merged PDF. Progress and
+// per-student errors are saved after each student so the HTTP side can poll them.
+func (s *Service) ProcessBatch(ctx context.Context, tenantID, batchID string) (*model.RaporBatch, error) {
+	batch, err := s.GetBatch(ctx, tenantID, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if batch.Status == model.RaporBatchStatusDone || batch.Status == model.RaporBatchStatusFailed {
+		// Redelivered message, nothing left to do
+		return batch, nil
+	}
+
+	now := time.Now()
+	batch.Status = model.RaporBatchStatusProcessing
+	batch.StartedAt = &now
+	batch.Selesai, batch.Gagal = 0, 0
+	batch.Errors = []model.RaporBatchError{}
+	if err := s.db.UpdateRaporBatchProgress(ctx, batch); err != nil {
+		return nil, err
+	}
+
+	periode, err := s.db.GetOrCreateRaporPeriode(tenantID, batch.SemesterID)
+	if err != nil {
+		return s.failBatch(ctx, batch, err)
+	}
+
+	var (
+		zipBuf      bytes.Buffer
+		rapors      []*model.Rapor
+		logo        []byte
+		logoFetched bool
+	)
+	zw := zip.NewWriter(&zipBuf)
+
+	for i, siswa := range batch.Siswa {
+		rapor, err := s.raporForBatch(ctx, tenantID, periode.ID, batch.SemesterID, siswa)
+		if err == nil && !logoFetched {
+			logoFetched = true
+			logo = s.raporLogo(ctx, rapor)
+		}
+
+		var out []byte
+		if err == nil {
+			out, err = pdf_utils.GenerateRaporPDF(rapor, logo)
+		}
+		if err == nil {
+			err = addZipFile(zw, raporFileName(i+1, siswa.Nama), out)
+		}
+
+		if err != nil {
+			batch.Gagal++
+			batch.Errors = append(batch.Errors, model.RaporBatchError{SiswaID: siswa.SiswaID, Nama: siswa.Nama, Pesan: err.Error()})
+		} else {
+			batch.Selesai++
+			rapors = append(rapors, rapor)
+		}
+		if err := s.db.UpdateRaporBatchProgress(ctx, batch); err != nil {
+			log.WithContext(ctx).WithError(err).Errorf("failed to save progress of rapor batch %s", batch.ID)
+		}
+	}
+
+	if len(rapors) == 0 {
+		return s.failBatch(ctx, batch, errors.New("tidak ada rapor yang berhasil dibuat"))
+	}
+	if err := zw.Close(); err != nil {
+		return s.failBatch(ctx, batch, err)
+	}
+	merged, err := pdf_utils.GenerateRaporBundlePDF(rapors, logo)
+	if err != nil {
+		return s.failBatch(ctx, batch, err)
+	}
+	if err := s.db.SaveRaporBatchFiles(ctx, batch.ID, zipBuf.Bytes(), merged); err != nil {
+		return s.failBatch(ctx, batch, err)
+	}
+
+	finished := time.Now()
+	batch.Status = model.RaporBatchStatusDone
+	batch.FinishedAt = &finished
+	if err := s.db.UpdateRaporBatchProgress(ctx, batch); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// raporForBatch reuses the student's snapshot once it is submitted so reprints stay
+// identical; a missing or Draft rapor is (re)generated from the current grades
+func (s *Service) raporForBatch(ctx context.Context, tenantID, periodeID, semesterID string, siswa model.RaporBatchSiswa) (*model.Rapor, error) {
+	raporID, err := s.db.GetRaporIDBySiswa(ctx, periodeID, siswa.SiswaID)
+	if err != nil {
+		return nil, err
+	}
+	if raporID != "" {
+		rapor, err := s.GetRapor(ctx, tenantID, raporID)
+		if err != nil {
+			return nil, err
+		}
+		if IsNilaiTerkunci(rapor.Status) {
+			return rapor, nil
+		}
+	}
+	generated, err := s.GenerateRapor(ctx, tenantID, siswa.SiswaID, semesterID, siswa.CatatanWali, siswa.Kehadiran)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetRapor(ctx, tenantID, generated.ID)
+}
+
+func (s *Service) failBatch(ctx context.Context, batch *model.RaporBatch, cause error) (*model.RaporBatch, error) {
+	now := time.Now()
+	batch.Status = model.RaporBatchStatusFailed
+	batch.FinishedAt = &now
+	batch.Errors = append(batch.Errors, model.RaporBatchError{Pesan: cause.Error()})
+	if err := s.db.UpdateRaporBatchProgress(ctx, batch); err != nil {
+		log.WithContext(ctx).WithError(err).Errorf("failed to mark rapor batch %s failed", batch.ID)
+	}
+	return batch, cause
+}
+
+func addZipFile(zw *zip.Writer, name string, content []byte) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(content)
+	return err
+}
+
+// raporFileName builds a ZIP entry name such as "03-ahmad-fauzi.pdf"
+func raporFileName(no int, nama string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(nama) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return fmt.Sprintf("%02d-%s.pdf", no, strings.TrimSuffix(b.String(), "-"))
+}
@@ -0,0 +1,56 @@
+package erapor_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain/erapor"
+	"prabogo/internal/model"
+	mock_outbound_port "prabogo/tests/mocks/port"
+	pdf_utils "prabogo/utils/pdf"
+)
+
+func TestCreateBatch(t *testing.T) {
+	Convey("Test CreateBatch", t, func() {
+		ctx := context.Background()
+		input := model.RaporBatchInput{KelasID: "kelas-1", SemesterID: "2025-2026-1"}
+
+		Convey("Requires kelas and semester", func() {
+			_, err := erapor.NewService(nil, nil).CreateBatch(ctx, "tenant-1", model.RaporBatchInput{KelasID: "kelas-1"})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Refuses to run without a message worker", func() {
+			_, err := erapor.NewService(nil, nil).CreateBatch(ctx, "tenant-1", input)
+			So(err, ShouldEqual, erapor.ErrWorkerTidakTersedia)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			messagePort := mock_outbound_port.NewMockMessagePort(ctrl)
+			messagePort.EXPECT().Rapor().Return(nil)
+
+			_, err = erapor.NewService(nil, messagePort).CreateBatch(ctx, "tenant-1", input)
+			So(err, ShouldEqual, erapor.ErrWorkerTidakTersedia)
+		})
+	})
+}
+
+func TestGenerateRaporBundlePDF(t *testing.T) {
+	Convey("Test GenerateRaporBundlePDF puts every rapor on its own pages", t, func() {
+		rapor := func(nama string) *model.Rapor {
+			return &model.Rapor{Header: &model.RaporHeader{Kurikulum: model.KurikulumMerdeka, NamaSiswa: nama}}
+		}
+
+		single, err := pdf_utils.GenerateRaporBundlePDF([]*model.Rapor{rapor("Ahmad")}, nil)
+		So(err, ShouldBeNil)
+		merged, err := pdf_utils.GenerateRaporBundlePDF([]*model.Rapor{rapor("Ahmad"), rapor("Budi")}, nil)
+		So(err, ShouldBeNil)
+
+		countPages := func(doc []byte) int { return bytes.Count(doc, []byte("/Type /Page\n")) }
+		So(countPages(merged), ShouldEqual, 2*countPages(single))
+	})
+}
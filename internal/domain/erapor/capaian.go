@@ -0,0 +1,331 @@
+package erapor
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"prabogo/internal/model"
+)
+
+var (
+	ErrCapaianNotFound = errors.New("capaian pembelajaran tidak ditemukan")
+	ErrTujuanNotFound  = errors.New("tujuan pembelajaran tidak ditemukan")
+	ErrFaseTidakSah    = errors.New("fase harus salah satu dari A, B, C, D, E, F")
+)
+
+// DescribeTP builds the capaian sentences of a student from their TP marks: the highest TPs fill
+// the High template and the lowest (strictly below every highlighted TP) fill the Low template.
+func DescribeTP(marks []model.TPAchievement, nama string, tmpl model.DescriptionTemplate) (high, low string) {
+	if len(marks) == 0 {
+		return "", ""
+	}
+	defaults := model.DefaultDescriptionTemplate()
+	if tmpl.High == "" {
+		tmpl.High = defaults.High
+	}
+	if tmpl.Low == "" {
+		tmpl.Low = defaults.Low
+	}
+	if tmpl.MaxTP <= 0 {
+		tmpl.MaxTP = defaults.MaxTP
+	}
+
+	sorted := append([]model.TPAchievement(nil), marks...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Score != sorted[j].Score {
+			return sorted[i].Score > sorted[j].Score
+		}
+		return sorted[i].Urutan < sorted[j].Urutan
+	})
+
+	top := sorted[:min(tmpl.MaxTP, len(sorted))]
+	lowest := top[len(top)-1].Score
+	var bottom []model.TPAchievement
+	for i := len(sorted) - 1; i >= len(top) && len(bottom) < tmpl.MaxTP; i-- {
+		if sorted[i].Score < lowest {
+			bottom = append(bottom, sorted[i])
+		}
+	}
+
+	high = fillTemplate(tmpl.High, nama, top)
+	if len(bottom) > 0 {
+		low = fillTemplate(tmpl.Low, nama, bottom)
+	}
+	return high, low
+}
+
+func fillTemplate(tmpl, nama string, marks []model.TPAchievement) string {
+	tps := make([]string, len(marks))
+	for i, m := range marks {
+		tps[i] = strings.TrimSuffix(strings.TrimSpace(m.TPDeskripsi), ".")
+	}
+	return strings.NewReplacer("{nama}", nama, "{tp}", joinDan(tps)).Replace(tmpl)
+}
+
+// GetCapaianList returns the CPs of a subject (optionally one fase) with their TPs
+func (s *Service) GetCapaianList(ctx context.Context, tenantID, subjectID, fase string) ([]model.CapaianPembelajaran, error) {
+	if _, err := s.subjectForTenant(ctx, tenantID, subjectID); err != nil {
+		return nil, err
+	}
+	return s.db.GetCapaianList(ctx, tenantID, subjectID, fase)
+}
+
+// SaveCapaian creates or updates a CP; the subject cannot be changed afterwards
+func (s *Service) SaveCapaian(ctx context.Context, cp *model.CapaianPembelajaran) error {
+	cp.Fase = strings.ToUpper(strings.TrimSpace(cp.Fase))
+	if !containsString(model.FaseMerdeka, cp.Fase) {
+		return ErrFaseTidakSah
+	}
+	if strings.TrimSpace(cp.Deskripsi) == "" {
+		return errors.New("deskripsi capaian wajib diisi")
+	}
+	if cp.ID != "" {
+		existing, err := s.db.GetCapaian(ctx, cp.TenantID, cp.ID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return ErrCapaianNotFound
+		}
+		cp.SubjectID = existing.SubjectID
+	} else if _, err := s.subjectForTenant(ctx, cp.TenantID, cp.SubjectID); err != nil {
+		return err
+	}
+	return s.db.SaveCapaian(ctx, cp)
+}
+
+// DeleteCapaian removes a CP together with its TPs and their marks
+func (s *Service) DeleteCapaian(ctx context.Context, tenantID, id string) error {
+	return s.db.DeleteCapaian(ctx, tenantID, id)
+}
+
+// SaveTujuan creates or updates a TP under an existing CP
+func (s *Service) SaveTujuan(ctx context.Context, tp *model.TujuanPembelajaran) error {
+	if strings.TrimSpace(tp.Deskripsi) == "" {
+		return errors.New("deskripsi tujuan pembelajaran wajib diisi")
+	}
+	if tp.ID != "" {
+		existing, err := s.db.GetTujuan(ctx, tp.TenantID, tp.ID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return ErrTujuanNotFound
+		}
+		tp.CPID = existing.CPID
+	}
+	cp, err := s.db.GetCapaian(ctx, tp.TenantID, tp.CPID)
+	if err != nil {
+		return err
+	}
+	if cp == nil {
+		return ErrCapaianNotFound
+	}
+	tp.SubjectID = cp.SubjectID
+	return s.db.SaveTujuan(ctx, tp)
+}
+
+// DeleteTujuan removes a TP and its marks
+func (s *Service) DeleteTujuan(ctx context.Context, tenantID, id string) error {
+	return s.db.DeleteTujuan(ctx, tenantID, id)
+}
+
+// GetTPAchievements lists the TP marks of a subject/semester, optionally for one student
+func (s *Service) GetTPAchievements(ctx context.Context, tenantID, subjectID, semesterID, studentID string) ([]model.TPAchievement, error) {
+	return s.db.GetTPAchievements(ctx, tenantID, subjectID, semesterID, studentID)
+}
+
+// SaveTPAchievements stores TP marks and regenerates the capaian descriptions of the affected
+// students. Students without a final grade yet get their description once the grade is saved.
+func (s *Service) SaveTPAchievements(ctx context.Context, input *model.TPAchievementInput) ([]model.StudentGrade, error) {
+	if input.SubjectID == "" || input.SemesterID == "" {
+		return nil, errors.New("subject_id dan semester_id wajib diisi")
+	}
+	if _, err := s.subjectForTenant(ctx, input.TenantID, input.SubjectID); err != nil {
+		return nil, err
+	}
+	capaian, err := s.db.GetCapaianList(ctx, input.TenantID, input.SubjectID, "")
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool)
+	for _, cp := range capaian {
+		for _, tp := range cp.TujuanList {
+			known[tp.ID] = true
+		}
+	}
+
+	var studentIDs []string
+	seen := make(map[string]bool)
+	for _, m := range input.Marks {
+		if !known[m.TPID] {
+			return nil, ErrTujuanNotFound
+		}
+		if m.Score < 0 || m.Score > 100 {
+			return nil, ErrNilaiTidakSah
+		}
+		if !seen[m.StudentID] {
+			seen[m.StudentID] = true
+			studentIDs = append(studentIDs, m.StudentID)
+		}
+	}
+	if err := s.ensureNilaiTerbuka(ctx, input.TenantID, input.SemesterID, studentIDs...); err != nil {
+		return nil, err
+	}
+	if err := s.db.SaveTPAchievements(ctx, input.TenantID, input.SemesterID, input.Marks); err != nil {
+		return nil, err
+	}
+	return s.RegenerateDescriptions(ctx, input.TenantID, input.SubjectID, input.SemesterID, studentIDs...)
+}
+
+// RegenerateDescriptions rewrites DescriptionHigh/DescriptionLow from the TP marks, e.g. after the
+// subject template changed. Without studentIDs every student with marks is refreshed; locked
+// grades and capaian reworded by hand are left alone.
+func (s *Service) RegenerateDescriptions(ctx context.Context, tenantID, subjectID, semesterID string, studentIDs ...string) ([]model.StudentGrade, error) {
+	subject, err := s.subjectForTenant(ctx, tenantID, subjectID)
+	if err != nil {
+		return nil, err
+	}
+	marks, err := s.db.GetTPAchievements(ctx, tenantID, subjectID, semesterID, "")
+	if err != nil {
+		return nil, err
+	}
+	byStudent := make(map[string][]model.TPAchievement)
+	for _, m := range marks {
+		byStudent[m.StudentID] = append(byStudent[m.StudentID], m)
+	}
+	if len(studentIDs) == 0 {
+		for id := range byStudent {
+			studentIDs = append(studentIDs, id)
+		}
+		sort.Strings(studentIDs)
+	}
+	statuses, err := s.db.GetRaporStatusBySiswa(ctx, tenantID, semesterID, studentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := []model.StudentGrade{}
+	for _, studentID := range studentIDs {
+		if IsNilaiTerkunci(statuses[studentID]) || len(byStudent[studentID]) == 0 {
+			continue
+		}
+		grade, err := s.gradeOf(ctx, studentID, subjectID, semesterID)
+		if errors.Is(err, ErrNilaiBelumAda) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if grade.DescriptionManual {
+			continue
+		}
+		high, low, err := s.describeStudent(ctx, subject, studentID, byStudent[studentID])
+		if err != nil {
+			return nil, err
+		}
+		saved, err := s.saveDescription(ctx, tenantID, grade, high, low, false)
+		if err != nil {
+			return nil, err
+		}
+		updated = append(updated, *saved)
+	}
+	return updated, nil
+}
+
+// UpdateGradeDescription lets the teacher reword the generated capaian before the rapor is locked.
+// The wording is kept through later recalculations; sending both texts empty hands the capaian
+// back to the generator.
+func (s *Service) UpdateGradeDescription(ctx context.Context, input *model.GradeDescriptionInput) (*model.StudentGrade, error) {
+	subject, err := s.subjectForTenant(ctx, input.TenantID, input.SubjectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureNilaiTerbuka(ctx, input.TenantID, input.SemesterID, input.StudentID); err != nil {
+		return nil, err
+	}
+	grade, err := s.gradeOf(ctx, input.StudentID, input.SubjectID, input.SemesterID)
+	if err != nil {
+		return nil, err
+	}
+	high, low := strings.TrimSpace(input.DescriptionHigh), strings.TrimSpace(input.DescriptionLow)
+	manual := high != "" || low != ""
+	if !manual {
+		generated, generatedLow, ok, err := s.tpDescriptions(ctx, subject, input.StudentID, input.SemesterID)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			high, low = generated, generatedLow
+		}
+	}
+	return s.saveDescription(ctx, input.TenantID, grade, high, low, manual)
+}
+
+// tpDescriptions returns the generated capaian of a student, ok is false when there are no TP marks
+func (s *Service) tpDescriptions(ctx context.Context, subject *model.Subject, studentID, semesterID string) (high, low string, ok bool, err error) {
+	marks, err := s.db.GetTPAchievements(ctx, subject.TenantID, subject.ID, semesterID, studentID)
+	if err != nil || len(marks) == 0 {
+		return "", "", false, err
+	}
+	high, low, err = s.describeStudent(ctx, subject, studentID, marks)
+	return high, low, err == nil, err
+}
+
+func (s *Service) describeStudent(ctx context.Context, subject *model.Subject, studentID string, marks []model.TPAchievement) (string, string, error) {
+	nama := "Ananda"
+	siswa, err := s.db.GetSiswaWali(ctx, subject.TenantID, studentID)
+	if err != nil {
+		return "", "", err
+	}
+	if siswa != nil && siswa.Nama != "" {
+		nama = siswa.Nama
+	}
+	tmpl := model.DefaultDescriptionTemplate()
+	if subject.GradingConfig.DescriptionTemplate != nil {
+		tmpl = *subject.GradingConfig.DescriptionTemplate
+	}
+	high, low := DescribeTP(marks, nama, tmpl)
+	return high, low, nil
+}
+
+func (s *Service) saveDescription(ctx context.Context, tenantID string, grade *model.StudentGrade, high, low string, manual bool) (*model.StudentGrade, error) {
+	return s.db.SaveGrade(ctx, &model.StudentGradeInput{
+		TenantID:          tenantID,
+		StudentID:         grade.StudentID,
+		SubjectID:         grade.SubjectID,
+		SemesterID:        grade.SemesterID,
+		ScoreNumeric:      grade.ScoreNumeric,
+		ScorePredicate:    grade.ScorePredicate,
+		DescriptionHigh:   high,
+		DescriptionLow:    low,
+		DescriptionManual: manual,
+		ComponentScores:   grade.ComponentScores,
+	})
+}
+
+// keepManualDescription carries a capaian reworded by hand over a recalculated grade
+func (s *Service) keepManualDescription(ctx context.Context, input *model.StudentGradeInput) error {
+	grade, err := s.gradeOf(ctx, input.StudentID, input.SubjectID, input.SemesterID)
+	if errors.Is(err, ErrNilaiBelumAda) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if grade.DescriptionManual {
+		input.DescriptionHigh, input.DescriptionLow, input.DescriptionManual = grade.DescriptionHigh, grade.DescriptionLow, true
+	}
+	return nil
+}
+
+func containsString(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
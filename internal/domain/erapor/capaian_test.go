@@ -0,0 +1,39 @@
+package erapor_test
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain/erapor"
+	"prabogo/internal/model"
+)
+
+func TestDescribeTP(t *testing.T) {
+	Convey("Test DescribeTP", t, func() {
+		marks := []model.TPAchievement{
+			{TPDeskripsi: "menjelaskan siklus air", Score: 92, Urutan: 1},
+			{TPDeskripsi: "mengidentifikasi sumber energi", Score: 85, Urutan: 2},
+			{TPDeskripsi: "membuat model tata surya.", Score: 70, Urutan: 3},
+			{TPDeskripsi: "menghitung gaya gesek", Score: 60, Urutan: 4},
+		}
+
+		Convey("Uses the highest and lowest TPs with the default template", func() {
+			high, low := erapor.DescribeTP(marks, "Aisyah", model.DescriptionTemplate{})
+			So(high, ShouldEqual, "Ananda Aisyah menunjukkan penguasaan yang baik dalam menjelaskan siklus air dan mengidentifikasi sumber energi.")
+			So(low, ShouldEqual, "Ananda Aisyah perlu bimbingan dalam menghitung gaya gesek dan membuat model tata surya.")
+		})
+
+		Convey("Honours a custom template", func() {
+			high, low := erapor.DescribeTP(marks, "Aisyah", model.DescriptionTemplate{High: "Mahir {tp}.", Low: "Perlu latihan {tp}.", MaxTP: 1})
+			So(high, ShouldEqual, "Mahir menjelaskan siklus air.")
+			So(low, ShouldEqual, "Perlu latihan menghitung gaya gesek.")
+		})
+
+		Convey("Leaves the low sentence empty when no TP is below the highlighted ones", func() {
+			high, low := erapor.DescribeTP(marks[:1], "Aisyah", model.DescriptionTemplate{})
+			So(high, ShouldNotBeEmpty)
+			So(low, ShouldBeEmpty)
+		})
+	})
+}
@@ -0,0 +1,185 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"prabogo/internal/model"
+)
+
+// nilaiKualitas scores the kualitas of one setoran
+var nilaiKualitas = map[string]float64{
+	"lancar": 100,
+	"kurang": 70,
+	"ulang":  40,
+}
+
+// KitabValidator grades diniyah kitab subjects (qiro'ah, tarjamah, hafalan matan, ...) on the
+// Arabic predicate scale and names the strongest and weakest component in the description
+type KitabValidator struct{}
+
+func (v *KitabValidator) CalculateGrade(components map[string]float64, config model.GradingConfig) ValidationResult {
+	result := ValidationResult{ScoreNumeric: weightedAverage(components, config), IsValid: true}
+	rule := pesantrenPredicate(result.ScoreNumeric, config.PredicateRules)
+	result.ScorePredicate = rule.Predicate
+
+	var best, worst string
+	for _, comp := range config.Components {
+		score, exists := components[comp.Name]
+		if !exists {
+			continue
+		}
+		if best == "" || score > components[best] {
+			best = comp.Name
+		}
+		if worst == "" || score < components[worst] {
+			worst = comp.Name
+		}
+	}
+	result.DescriptionHigh = strings.TrimSpace(fmt.Sprintf("%s (%s)", rule.Predicate, rule.Label))
+	if best != "" {
+		result.DescriptionHigh += ", menonjol dalam " + strings.ToLower(best)
+	}
+	if worst != "" && worst != best && components[worst] < 70 {
+		result.DescriptionLow = "Perlu penguatan dalam " + strings.ToLower(worst)
+	}
+	return result
+}
+
+func (v *KitabValidator) ValidateComponents(components map[string]float64, config model.GradingConfig) error {
+	return validateRange(components)
+}
+
+// TahfidzValidator grades tahfidz from the components computed by TahfidzComponents
+type TahfidzValidator struct{}
+
+func (v *TahfidzValidator) CalculateGrade(components map[string]float64, config model.GradingConfig) ValidationResult {
+	result := ValidationResult{ScoreNumeric: weightedAverage(components, config), IsValid: true}
+	rule := pesantrenPredicate(result.ScoreNumeric, config.PredicateRules)
+	result.ScorePredicate = rule.Predicate
+	result.DescriptionHigh = strings.TrimSpace(fmt.Sprintf("%s (%s)", rule.Predicate, rule.Label))
+	if score, exists := components[model.TahfidzKomponenMurajaah]; exists && score < 50 {
+		result.DescriptionLow = "Perlu meningkatkan konsistensi murajaah"
+	} else if score, exists := components[model.TahfidzKomponenKualitas]; exists && score < 70 {
+		result.DescriptionLow = "Perlu memperbaiki kelancaran hafalan"
+	}
+	return result
+}
+
+func (v *TahfidzValidator) ValidateComponents(components map[string]float64, config model.GradingConfig) error {
+	return validateRange(components)
+}
+
+// TahfidzSummary describes the setoran of one santri over a grading period
+type TahfidzSummary struct {
+	JuzTercapai   []int // juz with accepted ziyadah, ascending
+	Setoran       int
+	Lancar        int
+	PekanMurajaah int // weeks meeting the murajaah target
+	TotalPekan    int
+}
+
+// TahfidzComponents turns the setoran of one santri between start and end into the tahfidz
+// components: juz attained against the target, the average kualitas and murajaah consistency
+// (share of weeks with enough murajaah). Ziyadah marked "Ulang" does not count as attained.
+func TahfidzComponents(setoran []model.TahfidzSetoran, config model.GradingConfig, start, end time.Time) (map[string]float64, TahfidzSummary) {
+	target := model.TahfidzTarget{TargetJuz: 1, MurajaahPerPekan: 1}
+	if config.Tahfidz != nil {
+		if config.Tahfidz.TargetJuz > 0 {
+			target.TargetJuz = config.Tahfidz.TargetJuz
+		}
+		if config.Tahfidz.MurajaahPerPekan > 0 {
+			target.MurajaahPerPekan = config.Tahfidz.MurajaahPerPekan
+		}
+	}
+
+	var summary TahfidzSummary
+	juz := make(map[int]bool)
+	murajaahPerPekan := make(map[int]int)
+	var totalKualitas float64
+	var dinilai int
+	for _, s := range setoran {
+		if s.Tanggal.Before(start) || s.Tanggal.After(end) {
+			continue
+		}
+		summary.Setoran++
+		kualitas := strings.ToLower(strings.TrimSpace(s.Kualitas))
+		if nilai, ok := nilaiKualitas[kualitas]; ok {
+			totalKualitas += nilai
+			dinilai++
+		}
+		if kualitas == "lancar" {
+			summary.Lancar++
+		}
+		switch strings.ToLower(s.Tipe) {
+		case "ziyadah":
+			if s.Juz > 0 && kualitas != "ulang" {
+				juz[s.Juz] = true
+			}
+		case "murajaah":
+			murajaahPerPekan[int(s.Tanggal.Sub(start).Hours()/24/7)]++
+		}
+	}
+
+	for j := range juz {
+		summary.JuzTercapai = append(summary.JuzTercapai, j)
+	}
+	sort.Ints(summary.JuzTercapai)
+	summary.TotalPekan = int(end.Sub(start).Hours()/24/7) + 1
+	for _, n := range murajaahPerPekan {
+		if n >= target.MurajaahPerPekan {
+			summary.PekanMurajaah++
+		}
+	}
+
+	components := map[string]float64{
+		model.TahfidzKomponenJuz:      min(100, float64(len(juz))/float64(target.TargetJuz)*100),
+		model.TahfidzKomponenMurajaah: float64(summary.PekanMurajaah) / float64(summary.TotalPekan) * 100,
+	}
+	if dinilai > 0 {
+		components[model.TahfidzKomponenKualitas] = totalKualitas / float64(dinilai)
+	}
+	return components, summary
+}
+
+func weightedAverage(components map[string]float64, config model.GradingConfig) float64 {
+	var totalScore float64
+	var totalWeight int
+	for _, comp := range config.Components {
+		if score, exists := components[comp.Name]; exists {
+			totalScore += score * float64(comp.Weight)
+			totalWeight += comp.Weight
+		}
+	}
+	if totalWeight == 0 {
+		return 0
+	}
+	return totalScore / float64(totalWeight)
+}
+
+// pesantrenPredicate picks the highest rule whose minimum the score reaches, so fractional
+// scores between two integer ranges (e.g. 89.5) still get a predicate
+func pesantrenPredicate(score float64, rules []model.PredicateRule) model.PredicateRule {
+	if len(rules) == 0 {
+		rules = model.DefaultPesantrenPredicateRules()
+	}
+	sorted := append([]model.PredicateRule(nil), rules...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].MinScore > sorted[j].MinScore })
+	for _, rule := range sorted {
+		if score >= float64(rule.MinScore) {
+			return rule
+		}
+	}
+	return sorted[len(sorted)-1]
+}
+
+func validateRange(components map[string]float64) error {
+	for name, score := range components {
+		if score < 0 || score > 100 {
+			return fmt.Errorf("nilai %s harus di antara 0 dan 100", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package engine
+
+import (
+	"sort"
+
+	"prabogo/internal/model"
+)
+
+// ProfileValidator grades subjects of a tenant-defined curriculum. KKM mode scales A-D from the
+// KKM like K13 unless the profile sets its own predicate rules; descriptive mode maps the score
+// through the predicate rules and uses the rule label as the description.
+type ProfileValidator struct {
+	Mode string
+}
+
+func (v *ProfileValidator) CalculateGrade(components map[string]float64, config model.GradingConfig) ValidationResult {
+	if len(config.PredicateRules) == 0 {
+		if v.Mode == model.CurriculumModeKKM {
+			return (&K13Validator{}).CalculateGrade(components, config)
+		}
+		return (&GenericValidator{}).CalculateGrade(components, config)
+	}
+
+	result := ValidationResult{ScoreNumeric: weightedAverage(components, config), IsValid: true}
+	rule := matchRule(result.ScoreNumeric, config.PredicateRules)
+	result.ScorePredicate = rule.Predicate
+
+	if v.Mode == model.CurriculumModeKKM && config.KKMValue > 0 && result.ScoreNumeric < float64(config.KKMValue) {
+		result.DescriptionLow = "Belum mencapai KKM, perlu bimbingan dan remedial"
+	} else {
+		result.DescriptionHigh = rule.Label
+	}
+	return result
+}
+
+func (v *ProfileValidator) ValidateComponents(components map[string]float64, config model.GradingConfig) error {
+	return validateRange(components)
+}
+
+// matchRule picks the highest rule whose minimum the score reaches
+func matchRule(score float64, rules []model.PredicateRule) model.PredicateRule {
+	sorted := append([]model.PredicateRule(nil), rules...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].MinScore > sorted[j].MinScore })
+	for _, rule := range sorted {
+		if score >= float64(rule.MinScore) {
+			return rule
+		}
+	}
+	return sorted[len(sorted)-1]
+}
@@ -30,8 +30,34 @@ func GetValidator(curriculumCode string) CurriculumValidator {
 		return &K13Validator{}
 	case model.SubjectTypeFormalMerdeka:
 		return &MerdekaValidator{}
+	case model.SubjectTypePesantrenKitab:
+		return &KitabValidator{}
+	case model.SubjectTypePesantrenTahfidz:
+		return &TahfidzValidator{}
 	default:
 		// Default validation (generic) or fallback
 		return &GenericValidator{}
 	}
 }
+
+// ValidatorFor resolves the validator of a subject at runtime: built-in subject types keep their
+// own strategy, other types are graded by the tenant curriculum profile they name
+func ValidatorFor(subject *model.Subject) CurriculumValidator {
+	if IsBuiltinType(subject.Type) {
+		return GetValidator(subject.Type)
+	}
+	if subject.Curriculum != nil {
+		return &ProfileValidator{Mode: subject.Curriculum.Mode}
+	}
+	return &GenericValidator{}
+}
+
+// IsBuiltinType reports whether a subject type has a validator of its own
+func IsBuiltinType(subjectType string) bool {
+	switch subjectType {
+	case model.SubjectTypeFormalK13, model.SubjectTypeFormalMerdeka,
+		model.SubjectTypePesantrenKitab, model.SubjectTypePesantrenTahfidz:
+		return true
+	}
+	return false
+}
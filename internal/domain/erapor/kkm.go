@@ -0,0 +1,102 @@
+package erapor
+
+import (
+	"context"
+	"errors"
+
+	"prabogo/internal/model"
+	"prabogo/utils/semester"
+)
+
+var ErrKKMTidakSah = errors.New("KKM harus di antara 1 dan 100")
+
+// SemesterKKM returns the KKM in effect for a semester: the value pinned in kkm_history, or the
+// subject's current KKM when the semester has none
+func SemesterKKM(config model.GradingConfig, pinned map[string]int, subjectID string) int {
+	if kkm, ok := pinned[subjectID]; ok && kkm > 0 {
+		return kkm
+	}
+	return KKMOf(config)
+}
+
+// subjectForSemester returns a copy of the subject grading with the KKM of the given semester,
+// so recalculating an old semester keeps the KKM it was graded with
+func (s *Service) subjectForSemester(ctx context.Context, subject *model.Subject, semesterID string) (*model.Subject, error) {
+	if !subject.GradingConfig.UseKKM {
+		return subject, nil
+	}
+	pinned, err := s.db.GetSemesterKKM(ctx, subject.TenantID, semesterID)
+	if err != nil {
+		return nil, err
+	}
+	copied := *subject
+	copied.GradingConfig.KKMValue = SemesterKKM(subject.GradingConfig, pinned, subject.ID)
+	return &copied, nil
+}
+
+// GetKKMHistory lists the KKM pinned per semester for a subject
+func (s *Service) GetKKMHistory(ctx context.Context, tenantID, subjectID string) ([]model.KKMHistory, error) {
+	if _, err := s.subjectForTenant(ctx, tenantID, subjectID); err != nil {
+		return nil, err
+	}
+	return s.db.GetKKMHistory(ctx, tenantID, subjectID)
+}
+
+// SetSemesterKKM pins the KKM of a subject for one semester, typically the next one ahead of
+// time. Unlocked grades already given in that semester are recalculated with it.
+func (s *Service) SetSemesterKKM(ctx context.Context, tenantID, subjectID, semesterID string, kkm int) (*model.KKMHistory, error) {
+	subject, err := s.subjectForTenant(ctx, tenantID, subjectID)
+	if err != nil {
+		return nil, err
+	}
+	if !subject.GradingConfig.UseKKM {
+		return nil, ErrTanpaKKM
+	}
+	if _, _, ok := semester.Range(semesterID); !ok {
+		return nil, ErrSemesterTidakSah
+	}
+	if kkm < 1 || kkm > 100 {
+		return nil, ErrKKMTidakSah
+	}
+
+	history := &model.KKMHistory{TenantID: tenantID, SubjectID: subjectID, SemesterID: semesterID, KKMValue: kkm}
+	if err := s.db.SaveKKMHistory(ctx, history); err != nil {
+		return nil, err
+	}
+	s.recalculateSubject(ctx, subject)
+	return history, nil
+}
+
+// pinGradedKKM records the old KKM for every graded semester that has none pinned yet, before a
+// subject's KKM changes, so those semesters are not re-graded against the new value
+func (s *Service) pinGradedKKM(ctx context.Context, old *model.Subject, newConfig model.GradingConfig) error {
+	if !old.GradingConfig.UseKKM || KKMOf(old.GradingConfig) == KKMOf(newConfig) {
+		return nil
+	}
+	semesters, err := s.db.GetGradedSemesters(ctx, old.ID)
+	if err != nil {
+		return err
+	}
+	history, err := s.db.GetKKMHistory(ctx, old.TenantID, old.ID)
+	if err != nil {
+		return err
+	}
+	pinned := make(map[string]bool, len(history))
+	for _, h := range history {
+		pinned[h.SemesterID] = true
+	}
+	for _, semesterID := range semesters {
+		if pinned[semesterID] {
+			continue
+		}
+		if err := s.db.SaveKKMHistory(ctx, &model.KKMHistory{
+			TenantID:   old.TenantID,
+			SubjectID:  old.ID,
+			SemesterID: semesterID,
+			KKMValue:   KKMOf(old.GradingConfig),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
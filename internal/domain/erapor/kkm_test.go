@@ -0,0 +1,29 @@
+package erapor_test
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain/erapor"
+	"prabogo/internal/model"
+)
+
+func TestSemesterKKM(t *testing.T) {
+	Convey("Test SemesterKKM", t, func() {
+		config := model.GradingConfig{UseKKM: true, KKMValue: 80}
+		pinned := map[string]int{"math": 75}
+
+		Convey("A pinned semester keeps its own KKM", func() {
+			So(erapor.SemesterKKM(config, pinned, "math"), ShouldEqual, 75)
+		})
+
+		Convey("Other subjects use their current KKM", func() {
+			So(erapor.SemesterKKM(config, pinned, "ipa"), ShouldEqual, 80)
+		})
+
+		Convey("Falls back to the default KKM when the subject has none", func() {
+			So(erapor.SemesterKKM(model.GradingConfig{UseKKM: true}, nil, "ipa"), ShouldEqual, 75)
+		})
+	})
+}
@@ -0,0 +1,319 @@
+package erapor
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"prabogo/internal/domain/erapor/engine"
+	"prabogo/internal/model"
+)
+
+var (
+	ErrKurikulumNotFound     = errors.New("profil kurikulum tidak ditemukan")
+	ErrKurikulumSistem       = errors.New("profil kurikulum bawaan tidak dapat diubah atau dihapus")
+	ErrKurikulumDipakai      = errors.New("profil kurikulum masih dipakai jenjang atau mata pelajaran")
+	ErrKodeKurikulumTidakSah = errors.New("kode kurikulum hanya boleh huruf besar, angka dan garis bawah, dan tidak boleh memakai kode bawaan")
+	ErrJenjangTidakTerdaftar = errors.New("jenjang tidak terdaftar pada lembaga")
+)
+
+var (
+	kodeKurikulum  = regexp.MustCompile(`^[A-Z][A-Z0-9_]{1,49}$`)
+	raporTemplates = []string{model.KurikulumK13, model.KurikulumMerdeka, model.KurikulumPesantren}
+
+	// The jenjang a class of each band of tingkat may belong to
+	jenjangSD  = []string{"SD", "MI"}
+	jenjangSMP = []string{"SMP", "MTs"}
+	jenjangSMA = []string{"SMA", "MA", "SMK"}
+	romawi     = map[string]int{"I": 1, "II": 2, "III": 3, "IV": 4, "V": 5, "VI": 6, "VII": 7, "VIII": 8, "IX": 9, "X": 10, "XI": 11, "XII": 12}
+)
+
+// JenjangForTingkat picks which of the tenant's jenjang a class tingkat belongs to: 1-6 SD/MI,
+// 7-9 SMP/MTs, 10-12 SMA/MA/SMK and anything else TK. A single-jenjang tenant always gets that
+// jenjang; no match returns "".
+func JenjangForTingkat(tingkat string, jenjangs []string) string {
+	if len(jenjangs) == 1 {
+		return jenjangs[0]
+	}
+	t := strings.TrimSpace(strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(tingkat)), "KELAS"))
+	n, err := strconv.Atoi(t)
+	if err != nil {
+		n = romawi[t]
+	}
+
+	var band []string
+	switch {
+	case n >= 1 && n <= 6:
+		band = jenjangSD
+	case n >= 7 && n <= 9:
+		band = jenjangSMP
+	case n >= 10 && n <= 12:
+		band = jenjangSMA
+	default:
+		band = []string{"TK"}
+	}
+	for _, j := range jenjangs {
+		if containsFold(band, j) {
+			return j
+		}
+	}
+	return ""
+}
+
+// ResolveTenantCurriculum returns the curriculum a class follows: the one selected for its
+// jenjang, else the tenant-wide default
+func ResolveTenantCurriculum(selected []model.TenantCurriculum, jenjangs []string, tingkat string) (model.TenantCurriculum, bool) {
+	jenjang := JenjangForTingkat(tingkat, jenjangs)
+	var fallback *model.TenantCurriculum
+	for i := range selected {
+		switch {
+		case jenjang != "" && strings.EqualFold(selected[i].Jenjang, jenjang):
+			return selected[i], true
+		case selected[i].Jenjang == "":
+			fallback = &selected[i]
+		}
+	}
+	if fallback != nil {
+		return *fallback, true
+	}
+	return model.TenantCurriculum{}, false
+}
+
+// applyCurriculumProfile attaches a curriculum profile to a subject typed with its code. The
+// profile's predicate rules and KKM fill in what the subject's own grading config leaves open.
+func applyCurriculumProfile(subject *model.Subject, profile *model.CurriculumProfile) {
+	if profile == nil {
+		return
+	}
+	subject.Curriculum = profile
+	config := &subject.GradingConfig
+	if len(config.PredicateRules) == 0 {
+		config.PredicateRules = profile.GradingConfig.PredicateRules
+	}
+	if profile.Mode == model.CurriculumModeKKM {
+		config.UseKKM = true
+		if config.KKMValue == 0 {
+			config.KKMValue = profile.GradingConfig.KKMValue
+		}
+	}
+}
+
+func (s *Service) curriculumProfilesByCode(ctx context.Context, tenantID string) (map[string]*model.CurriculumProfile, error) {
+	profiles, err := s.db.GetCurriculumProfiles(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	byCode := make(map[string]*model.CurriculumProfile, len(profiles))
+	for i := range profiles {
+		// The tenant's own profile shadows a system one with the same code
+		if existing, ok := byCode[profiles[i].Code]; ok && !existing.IsSystem {
+			continue
+		}
+		byCode[profiles[i].Code] = &profiles[i]
+	}
+	return byCode, nil
+}
+
+// raporHeader loads the identity frozen into a rapor. The kurikulum (and with it the rapor
+// template) comes from the curriculum selected for the student's jenjang when the tenant has one,
+// otherwise from the school profil.
+func (s *Service) raporHeader(ctx context.Context, tenantID, studentID string) (*model.RaporHeader, error) {
+	header, err := s.db.GetRaporHeader(ctx, tenantID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, ErrSiswaNotFound
+	}
+
+	selected, err := s.db.GetTenantCurricula(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(selected) > 0 {
+		jenjangs, err := s.db.GetTenantJenjangs(ctx, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		header.Jenjang = JenjangForTingkat(header.Tingkat, jenjangs)
+		if tc, ok := ResolveTenantCurriculum(selected, jenjangs, header.Tingkat); ok && tc.RaporTemplate != "" {
+			header.Kurikulum = tc.RaporTemplate
+		}
+	}
+	if header.Kurikulum == "" {
+		header.Kurikulum = model.KurikulumK13
+	}
+	return header, nil
+}
+
+// GetCurriculumProfiles lists the system curriculum profiles and the tenant's own
+func (s *Service) GetCurriculumProfiles(ctx context.Context, tenantID string) ([]model.CurriculumProfile, error) {
+	return s.db.GetCurriculumProfiles(ctx, tenantID)
+}
+
+// SaveCurriculumProfile creates or updates a tenant curriculum profile. The code is fixed once
+// created since subjects and jenjang refer to it.
+func (s *Service) SaveCurriculumProfile(ctx context.Context, profile *model.CurriculumProfile) error {
+	if profile.ID != "" {
+		existing, err := s.db.GetCurriculumProfileByID(ctx, profile.TenantID, profile.ID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return ErrKurikulumNotFound
+		}
+		if existing.IsSystem {
+			return ErrKurikulumSistem
+		}
+		profile.Code = existing.Code
+	} else {
+		profile.Code = strings.ToUpper(strings.TrimSpace(profile.Code))
+		if !kodeKurikulum.MatchString(profile.Code) || engine.IsBuiltinType(profile.Code) {
+			return ErrKodeKurikulumTidakSah
+		}
+		existing, err := s.db.GetCurriculumProfile(ctx, profile.TenantID, profile.Code)
+		if err != nil {
+			return err
+		}
+		if existing != nil && !existing.IsSystem {
+			return errors.New("kode kurikulum sudah dipakai")
+		}
+		profile.IsActive = true
+	}
+
+	profile.Name = strings.TrimSpace(profile.Name)
+	if profile.Name == "" {
+		return errors.New("nama kurikulum wajib diisi")
+	}
+	if profile.RaporTemplate == "" {
+		profile.RaporTemplate = model.KurikulumMerdeka
+	}
+	if !containsString(raporTemplates, profile.RaporTemplate) {
+		return errors.New("template rapor harus K13, MERDEKA atau PESANTREN")
+	}
+
+	config := &profile.GradingConfig
+	switch profile.Mode {
+	case model.CurriculumModeKKM:
+		if config.KKMValue < 1 || config.KKMValue > 100 {
+			return ErrKKMTidakSah
+		}
+		config.UseKKM, config.UseDescriptive = true, false
+	case model.CurriculumModeDeskriptif:
+		if len(config.PredicateRules) == 0 {
+			return ErrPredikatKosong
+		}
+		config.UseKKM, config.UseDescriptive, config.KKMValue = false, true, 0
+	default:
+		return errors.New("mode kurikulum harus KKM atau DESKRIPTIF")
+	}
+	if len(config.Components) == 0 {
+		return errors.New("komponen penilaian wajib diisi")
+	}
+	for i, comp := range config.Components {
+		config.Components[i].Name = strings.TrimSpace(comp.Name)
+		if config.Components[i].Name == "" || comp.Weight <= 0 {
+			return errors.New("komponen penilaian membutuhkan nama dan bobot lebih dari 0")
+		}
+	}
+	if err := validatePredicateRules(config.PredicateRules); err != nil {
+		return err
+	}
+	return s.db.SaveCurriculumProfile(ctx, profile)
+}
+
+// DeleteCurriculumProfile removes a tenant profile no jenjang or subject uses anymore
+func (s *Service) DeleteCurriculumProfile(ctx context.Context, tenantID, id string) error {
+	profile, err := s.db.GetCurriculumProfileByID(ctx, tenantID, id)
+	if err != nil {
+		return err
+	}
+	if profile == nil {
+		return ErrKurikulumNotFound
+	}
+	if profile.IsSystem {
+		return ErrKurikulumSistem
+	}
+
+	selected, err := s.db.GetTenantCurricula(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	for _, tc := range selected {
+		if tc.CurriculumCode == profile.Code {
+			return ErrKurikulumDipakai
+		}
+	}
+	subjects, err := s.db.GetSubjectsByTenant(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	for _, subject := range subjects {
+		if subject.Type == profile.Code {
+			return ErrKurikulumDipakai
+		}
+	}
+	return s.db.DeleteCurriculumProfile(ctx, tenantID, id)
+}
+
+// GetTenantCurricula returns the curriculum selected per jenjang with the tenant's jenjang list
+func (s *Service) GetTenantCurricula(ctx context.Context, tenantID string) ([]model.TenantCurriculum, []string, error) {
+	selected, err := s.db.GetTenantCurricula(ctx, tenantID)
+	if err != nil {
+		return nil, nil, err
+	}
+	jenjangs, err := s.db.GetTenantJenjangs(ctx, tenantID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return selected, jenjangs, nil
+}
+
+// SetTenantCurriculum selects the curriculum of one jenjang, or the tenant-wide default when
+// jenjang is empty
+func (s *Service) SetTenantCurriculum(ctx context.Context, tenantID, jenjang, code string) (*model.TenantCurriculum, error) {
+	jenjang = strings.TrimSpace(jenjang)
+	if jenjang != "" {
+		jenjangs, err := s.db.GetTenantJenjangs(ctx, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		found := false
+		for _, j := range jenjangs {
+			if strings.EqualFold(j, jenjang) {
+				jenjang, found = j, true
+				break
+			}
+		}
+		if !found {
+			return nil, ErrJenjangTidakTerdaftar
+		}
+	}
+
+	profile, err := s.db.GetCurriculumProfile(ctx, tenantID, strings.ToUpper(strings.TrimSpace(code)))
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil || !profile.IsActive {
+		return nil, ErrKurikulumNotFound
+	}
+
+	tc := &model.TenantCurriculum{
+		TenantID:       tenantID,
+		Jenjang:        jenjang,
+		CurriculumCode: profile.Code,
+		CurriculumName: profile.Name,
+		RaporTemplate:  profile.RaporTemplate,
+	}
+	if err := s.db.SaveTenantCurriculum(ctx, tc); err != nil {
+		return nil, err
+	}
+	return tc, nil
+}
+
+// DeleteTenantCurriculum clears the selection of a jenjang so it follows the default again
+func (s *Service) DeleteTenantCurriculum(ctx context.Context, tenantID, jenjang string) error {
+	return s.db.DeleteTenantCurriculum(ctx, tenantID, strings.TrimSpace(jenjang))
+}
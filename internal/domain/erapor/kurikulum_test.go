@@ -0,0 +1,75 @@
+package erapor_test
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain/erapor"
+	"prabogo/internal/domain/erapor/engine"
+	"prabogo/internal/model"
+)
+
+func TestTenantCurriculum(t *testing.T) {
+	Convey("Test JenjangForTingkat", t, func() {
+		hybrid := []string{"MI", "MTs", "MA"}
+
+		So(erapor.JenjangForTingkat("4", hybrid), ShouldEqual, "MI")
+		So(erapor.JenjangForTingkat("VIII", hybrid), ShouldEqual, "MTs")
+		So(erapor.JenjangForTingkat("Kelas 11", hybrid), ShouldEqual, "MA")
+		So(erapor.JenjangForTingkat("A", hybrid), ShouldEqual, "")
+		So(erapor.JenjangForTingkat("10", []string{"SMK"}), ShouldEqual, "SMK")
+	})
+
+	Convey("Test ResolveTenantCurriculum", t, func() {
+		jenjangs := []string{"SD", "SMP"}
+		selected := []model.TenantCurriculum{
+			{Jenjang: "", CurriculumCode: "K13_REVISI"},
+			{Jenjang: "SMP", CurriculumCode: "MERDEKA"},
+		}
+
+		Convey("Uses the curriculum of the class jenjang", func() {
+			tc, ok := erapor.ResolveTenantCurriculum(selected, jenjangs, "7")
+			So(ok, ShouldBeTrue)
+			So(tc.CurriculumCode, ShouldEqual, "MERDEKA")
+		})
+
+		Convey("Falls back to the tenant default", func() {
+			tc, ok := erapor.ResolveTenantCurriculum(selected, jenjangs, "3")
+			So(ok, ShouldBeTrue)
+			So(tc.CurriculumCode, ShouldEqual, "K13_REVISI")
+		})
+
+		Convey("Reports nothing without a selection", func() {
+			_, ok := erapor.ResolveTenantCurriculum(selected[1:], jenjangs, "3")
+			So(ok, ShouldBeFalse)
+		})
+	})
+
+	Convey("Test ValidatorFor a tenant curriculum", t, func() {
+		config := model.GradingConfig{
+			Components: []model.GradingComponent{{Name: "Praktik", Weight: 60}, {Name: "Teori", Weight: 40}},
+			PredicateRules: []model.PredicateRule{
+				{MinScore: 0, MaxScore: 69, Predicate: "Berkembang", Label: "Mulai berkembang"},
+				{MinScore: 70, MaxScore: 100, Predicate: "Cakap", Label: "Cakap menerapkan"},
+			},
+			KKMValue: 75,
+		}
+		components := map[string]float64{"Praktik": 80, "Teori": 60}
+
+		Convey("Descriptive mode describes with the rule label", func() {
+			subject := &model.Subject{Type: "VOKASI", Curriculum: &model.CurriculumProfile{Mode: model.CurriculumModeDeskriptif}}
+			result := engine.ValidatorFor(subject).CalculateGrade(components, config)
+			So(result.ScoreNumeric, ShouldEqual, 72)
+			So(result.ScorePredicate, ShouldEqual, "Cakap")
+			So(result.DescriptionHigh, ShouldEqual, "Cakap menerapkan")
+		})
+
+		Convey("KKM mode flags scores below the KKM", func() {
+			subject := &model.Subject{Type: "VOKASI", Curriculum: &model.CurriculumProfile{Mode: model.CurriculumModeKKM}}
+			result := engine.ValidatorFor(subject).CalculateGrade(components, config)
+			So(result.ScorePredicate, ShouldEqual, "Cakap")
+			So(result.DescriptionLow, ShouldStartWith, "Belum mencapai KKM")
+		})
+	})
+}
@@ -0,0 +1,365 @@
+package erapor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+
+	"prabogo/internal/model"
+	pdf_utils "prabogo/utils/pdf"
+	"prabogo/utils/semester"
+)
+
+var (
+	ErrKelasNotFound      = errors.New("kelas tidak ditemukan")
+	ErrTieRuleTidakSah    = errors.New("aturan peringkat harus standar, padat atau kehadiran")
+	ErrFormatTidakDikenal = errors.New("format ekspor harus xlsx atau pdf")
+)
+
+// BuildLeger fills the subject columns, totals, averages and ranks of a leger whose rows hold
+// the class roster. Only subjects graded in the class become columns. Subjects of an excluded
+// group stay in the leger but do not count toward total, average or rank.
+func BuildLeger(leger *model.Leger, subjects []model.Subject, grades []model.StudentGrade, opts model.LegerOptions) {
+	rowOf := make(map[string]*model.LegerRow, len(leger.Rows))
+	for i := range leger.Rows {
+		if leger.Rows[i].Scores == nil {
+			leger.Rows[i].Scores = map[string]float64{}
+		}
+		rowOf[leger.Rows[i].StudentID] = &leger.Rows[i]
+	}
+	graded := make(map[string]bool)
+	for _, g := range grades {
+		if row := rowOf[g.StudentID]; row != nil {
+			row.Scores[g.SubjectID] = g.ScoreNumeric
+			graded[g.SubjectID] = true
+		}
+	}
+
+	leger.TieRule = opts.TieRule
+	leger.ExcludeGroups = opts.ExcludeGroups
+	leger.Subjects = []model.LegerSubject{}
+	for _, subject := range subjects {
+		if !graded[subject.ID] {
+			continue
+		}
+		kelompok := kategoriForSubject(subject.Type)
+		leger.Subjects = append(leger.Subjects, model.LegerSubject{
+			ID:       subject.ID,
+			Code:     subject.Code,
+			Name:     subject.Name,
+			Kelompok: kelompok,
+			Excluded: containsFold(opts.ExcludeGroups, kelompok),
+		})
+	}
+	sort.SliceStable(leger.Subjects, func(i, j int) bool {
+		ki, kj := kategoriOrder[leger.Subjects[i].Kelompok], kategoriOrder[leger.Subjects[j].Kelompok]
+		if ki != kj {
+			return ki < kj
+		}
+		return leger.Subjects[i].Name < leger.Subjects[j].Name
+	})
+
+	for i := range leger.Subjects {
+		var sum float64
+		var n int
+		for _, row := range leger.Rows {
+			if score, ok := row.Scores[leger.Subjects[i].ID]; ok {
+				sum += score
+				n++
+			}
+		}
+		if n > 0 {
+			leger.Subjects[i].Average = sum / float64(n)
+		}
+	}
+
+	ranked := make([]bool, len(leger.Rows))
+	var classSum float64
+	var classN int
+	for i := range leger.Rows {
+		row := &leger.Rows[i]
+		row.Total, row.Average, row.Rank = 0, 0, 0
+		var n int
+		for _, subject := range leger.Subjects {
+			if score, ok := row.Scores[subject.ID]; ok && !subject.Excluded {
+				row.Total += score
+				n++
+			}
+		}
+		if n > 0 {
+			row.Average = row.Total / float64(n)
+			ranked[i] = true
+			classSum += row.Average
+			classN++
+		}
+	}
+	leger.ClassAverage = 0
+	if classN > 0 {
+		leger.ClassAverage = classSum / float64(classN)
+	}
+
+	rankLeger(leger.Rows, ranked, opts.TieRule)
+}
+
+// rankLeger ranks rows by average. Students without a ranked grade keep rank 0.
+func rankLeger(rows []model.LegerRow, ranked []bool, tieRule string) {
+	order := make([]int, 0, len(rows))
+	for i := range rows {
+		if ranked[i] {
+			order = append(order, i)
+		}
+	}
+
+	byKehadiran := tieRule == model.LegerTieKehadiran
+	same := func(a, b model.LegerRow) bool {
+		if roundNilai(a.Average) != roundNilai(b.Average) {
+			return false
+		}
+		return !byKehadiran ||
+			a.Kehadiran.Alpha == b.Kehadiran.Alpha &&
+				a.Kehadiran.Sakit+a.Kehadiran.Izin == b.Kehadiran.Sakit+b.Kehadiran.Izin
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := rows[order[i]], rows[order[j]]
+		if ra, rb := roundNilai(a.Average), roundNilai(b.Average); ra != rb {
+			return ra > rb
+		}
+		if byKehadiran {
+			if a.Kehadiran.Alpha != b.Kehadiran.Alpha {
+				return a.Kehadiran.Alpha < b.Kehadiran.Alpha
+			}
+			return a.Kehadiran.Sakit+a.Kehadiran.Izin < b.Kehadiran.Sakit+b.Kehadiran.Izin
+		}
+		return false
+	})
+
+	rank := 0
+	for pos, i := range order {
+		switch {
+		case pos > 0 && same(rows[order[pos-1]], rows[i]):
+			// shares the rank of the previous student
+		case tieRule == model.LegerTiePadat:
+			rank++
+		default:
+			rank = pos + 1
+		}
+		rows[i].Rank = rank
+	}
+}
+
+// roundNilai compares averages at two decimals, the precision printed on the leger
+func roundNilai(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(strings.TrimSpace(item), s) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetLeger builds the leger nilai of a kelas for a semester from student_grades, with the
+// attendance frozen in the students' rapor
+func (s *Service) GetLeger(ctx context.Context, tenantID, kelasID, semesterID string, opts model.LegerOptions) (*model.Leger, error) {
+	if opts.TieRule == "" {
+		opts.TieRule = model.LegerTieStandar
+	}
+	switch opts.TieRule {
+	case model.LegerTieStandar, model.LegerTiePadat, model.LegerTieKehadiran:
+	default:
+		return nil, ErrTieRuleTidakSah
+	}
+	if _, _, ok := semester.Range(semesterID); !ok {
+		return nil, ErrSemesterTidakSah
+	}
+
+	leger, err := s.db.GetLegerKelas(ctx, tenantID, kelasID)
+	if err != nil {
+		return nil, err
+	}
+	if leger == nil {
+		return nil, ErrKelasNotFound
+	}
+	leger.SemesterID = semesterID
+	leger.Semester = semester.Label(semesterID)
+
+	subjects, err := s.db.GetSubjectsByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	grades, err := s.db.GetGradesByKelas(ctx, tenantID, kelasID, semesterID)
+	if err != nil {
+		return nil, err
+	}
+	studentIDs := make([]string, len(leger.Rows))
+	for i, row := range leger.Rows {
+		studentIDs[i] = row.StudentID
+	}
+	kehadiran, err := s.db.GetRaporKehadiran(ctx, tenantID, semesterID, studentIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range leger.Rows {
+		leger.Rows[i].Kehadiran = kehadiran[leger.Rows[i].StudentID]
+	}
+
+	BuildLeger(leger, subjects, grades, opts)
+	return leger, nil
+}
+
+// ExportLeger renders the leger as xlsx or pdf and returns the file with its name
+func (s *Service) ExportLeger(ctx context.Context, tenantID, kelasID, semesterID string, opts model.LegerOptions, format string) ([]byte, string, error) {
+	if format != "xlsx" && format != "pdf" {
+		return nil, "", ErrFormatTidakDikenal
+	}
+	leger, err := s.GetLeger(ctx, tenantID, kelasID, semesterID, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	filename := fmt.Sprintf("leger-%s-%s.%s", strings.ReplaceAll(strings.ToLower(leger.NamaKelas), " ", "-"), semesterID, format)
+	if format == "pdf" {
+		content, err := pdf_utils.GenerateLegerPDF(leger, legerNote(leger))
+		return content, filename, err
+	}
+	content, err := legerExcel(leger)
+	return content, filename, err
+}
+
+func legerExcel(leger *model.Leger) ([]byte, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+	sheet := "Leger"
+	index, _ := f.NewSheet(sheet)
+	f.SetActiveSheet(index)
+	f.DeleteSheet("Sheet1")
+
+	border := []excelize.Border{
+		{Type: "left", Color: "000000", Style: 1},
+		{Type: "top", Color: "000000", Style: 1},
+		{Type: "bottom", Color: "000000", Style: 1},
+		{Type: "right", Color: "000000", Style: 1},
+	}
+	headerStyle, _ := f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true, Color: "FFFFFF"},
+		Fill:      excelize.Fill{Type: "pattern", Color: []string{"4472C4"}, Pattern: 1},
+		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center", WrapText: true},
+		Border:    border,
+	})
+	cellStyle, _ := f.NewStyle(&excelize.Style{Border: border})
+	decimalStyle, _ := f.NewStyle(&excelize.Style{Border: border, CustomNumFmt: stringPtr("0.00")})
+	titleStyle, _ := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true, Size: 14}})
+
+	f.SetCellValue(sheet, "A1", "LEGER NILAI")
+	f.SetCellStyle(sheet, "A1", "A1", titleStyle)
+	f.SetCellValue(sheet, "A2", fmt.Sprintf("Kelas: %s", leger.NamaKelas))
+	f.SetCellValue(sheet, "A3", fmt.Sprintf("Semester: %s", leger.Semester))
+	f.SetCellValue(sheet, "A4", fmt.Sprintf("Wali Kelas: %s", leger.WaliKelas))
+
+	headers := []string{"No", "NIS", "Nama"}
+	for _, subject := range leger.Subjects {
+		headers = append(headers, legerSubjectLabel(subject))
+	}
+	headers = append(headers, "Jumlah", "Rata-rata", "Peringkat", "S", "I", "A")
+	const headerRow = 6
+	for i, h := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, headerRow)
+		f.SetCellValue(sheet, cell, h)
+		f.SetCellStyle(sheet, cell, cell, headerStyle)
+	}
+
+	setRow := func(rowNum int, values []interface{}, decimals map[int]bool) {
+		for i, v := range values {
+			cell, _ := excelize.CoordinatesToCellName(i+1, rowNum)
+			f.SetCellValue(sheet, cell, v)
+			style := cellStyle
+			if decimals[i] {
+				style = decimalStyle
+			}
+			f.SetCellStyle(sheet, cell, cell, style)
+		}
+	}
+
+	subjectCol := 3
+	totalCol := subjectCol + len(leger.Subjects)
+	for i, row := range leger.Rows {
+		values := []interface{}{i + 1, row.NIS, row.Nama}
+		for _, subject := range leger.Subjects {
+			if score, ok := row.Scores[subject.ID]; ok {
+				values = append(values, roundNilai(score))
+			} else {
+				values = append(values, "-")
+			}
+		}
+		var rank interface{} = "-"
+		if row.Rank > 0 {
+			rank = row.Rank
+		}
+		values = append(values, roundNilai(row.Total), roundNilai(row.Average), rank,
+			row.Kehadiran.Sakit, row.Kehadiran.Izin, row.Kehadiran.Alpha)
+		setRow(headerRow+1+i, values, map[int]bool{totalCol + 1: true})
+	}
+
+	averages := []interface{}{"", "", "Rata-rata kelas"}
+	decimals := map[int]bool{totalCol + 1: true}
+	for i, subject := range leger.Subjects {
+		averages = append(averages, roundNilai(subject.Average))
+		decimals[subjectCol+i] = true
+	}
+	averages = append(averages, "", roundNilai(leger.ClassAverage), "", "", "", "")
+	setRow(headerRow+1+len(leger.Rows), averages, decimals)
+
+	if note := legerNote(leger); note != "" {
+		cell, _ := excelize.CoordinatesToCellName(1, headerRow+3+len(leger.Rows))
+		f.SetCellValue(sheet, cell, note)
+	}
+
+	f.SetColWidth(sheet, "A", "A", 5)
+	f.SetColWidth(sheet, "B", "B", 14)
+	f.SetColWidth(sheet, "C", "C", 30)
+	first, _ := excelize.ColumnNumberToName(subjectCol + 1)
+	last, _ := excelize.ColumnNumberToName(len(headers))
+	f.SetColWidth(sheet, first, last, 11)
+	f.SetPanes(sheet, &excelize.Panes{Freeze: true, XSplit: 3, YSplit: headerRow, TopLeftCell: "D7", ActivePane: "bottomRight"})
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func legerSubjectLabel(subject model.LegerSubject) string {
+	label := subject.Name
+	if subject.Excluded {
+		label += " *"
+	}
+	return label
+}
+
+// legerNote explains the ranking options printed under the leger
+func legerNote(leger *model.Leger) string {
+	parts := []string{}
+	switch leger.TieRule {
+	case model.LegerTiePadat:
+		parts = append(parts, "Peringkat sama tanpa melompati urutan")
+	case model.LegerTieKehadiran:
+		parts = append(parts, "Rata-rata sama diurutkan menurut kehadiran")
+	}
+	if len(leger.ExcludeGroups) > 0 {
+		parts = append(parts, "* tidak dihitung dalam jumlah, rata-rata dan peringkat ("+strings.Join(leger.ExcludeGroups, ", ")+")")
+	}
+	return strings.Join(parts, ". ")
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
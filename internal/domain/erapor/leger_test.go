@@ -0,0 +1,75 @@
+package erapor_test
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain/erapor"
+	"prabogo/internal/model"
+)
+
+func TestBuildLeger(t *testing.T) {
+	Convey("Test BuildLeger", t, func() {
+		subjects := []model.Subject{
+			{ID: "mtk", Name: "Matematika", Type: model.SubjectTypeFormalK13},
+			{ID: "ipa", Name: "IPA", Type: model.SubjectTypeFormalK13},
+			{ID: "fiqh", Name: "Fathul Qarib", Type: model.SubjectTypePesantrenKitab},
+			{ID: "seni", Name: "Seni Budaya", Type: model.SubjectTypeFormalK13},
+		}
+		grade := func(student, subject string, score float64) model.StudentGrade {
+			return model.StudentGrade{StudentID: student, SubjectID: subject, ScoreNumeric: score}
+		}
+		grades := []model.StudentGrade{
+			grade("ani", "mtk", 90), grade("ani", "ipa", 80), grade("ani", "fiqh", 60),
+			grade("budi", "mtk", 80), grade("budi", "ipa", 90), grade("budi", "fiqh", 100),
+			grade("citra", "mtk", 70), grade("citra", "ipa", 80), grade("citra", "fiqh", 90),
+		}
+		newLeger := func() *model.Leger {
+			return &model.Leger{Rows: []model.LegerRow{
+				{StudentID: "ani", Nama: "Ani", Kehadiran: model.AttendanceData{Alpha: 2}},
+				{StudentID: "budi", Nama: "Budi"},
+				{StudentID: "citra", Nama: "Citra"},
+				{StudentID: "dedi", Nama: "Dedi"},
+			}}
+		}
+		ranks := func(l *model.Leger) []int {
+			r := make([]int, len(l.Rows))
+			for i, row := range l.Rows {
+				r[i] = row.Rank
+			}
+			return r
+		}
+
+		Convey("Only graded subjects become columns, akademik before diniyah", func() {
+			leger := newLeger()
+			erapor.BuildLeger(leger, subjects, grades, model.LegerOptions{})
+			So(len(leger.Subjects), ShouldEqual, 3)
+			So(leger.Subjects[0].Name, ShouldEqual, "IPA")
+			So(leger.Subjects[2].Kelompok, ShouldEqual, model.RaporKategoriDiniyah)
+			So(leger.Subjects[0].Average, ShouldAlmostEqual, 83.333, 0.001)
+			So(leger.Rows[1].Total, ShouldEqual, 270)
+			So(ranks(leger), ShouldResemble, []int{3, 1, 2, 0})
+		})
+
+		Convey("Excluded groups do not count toward the rank", func() {
+			leger := newLeger()
+			erapor.BuildLeger(leger, subjects, grades, model.LegerOptions{ExcludeGroups: []string{"diniyah"}})
+			So(leger.Subjects[2].Excluded, ShouldBeTrue)
+			So(leger.Rows[0].Average, ShouldEqual, 85)
+			So(ranks(leger), ShouldResemble, []int{1, 1, 3, 0})
+		})
+
+		Convey("Dense ranking leaves no gaps", func() {
+			leger := newLeger()
+			erapor.BuildLeger(leger, subjects, grades, model.LegerOptions{TieRule: model.LegerTiePadat, ExcludeGroups: []string{"Diniyah"}})
+			So(ranks(leger), ShouldResemble, []int{1, 1, 2, 0})
+		})
+
+		Convey("Attendance splits equal averages", func() {
+			leger := newLeger()
+			erapor.BuildLeger(leger, subjects, grades, model.LegerOptions{TieRule: model.LegerTieKehadiran, ExcludeGroups: []string{"Diniyah"}})
+			So(ranks(leger), ShouldResemble, []int{2, 1, 3, 0})
+		})
+	})
+}
@@ -0,0 +1,196 @@
+package erapor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"prabogo/internal/model"
+	"prabogo/utils/log"
+	"prabogo/utils/semester"
+)
+
+var (
+	ErrTransisiTidakValid = errors.New("perubahan status rapor tidak diizinkan")
+	ErrAlasanWajib        = errors.New("alasan membuka kunci wajib diisi")
+	ErrNilaiTerkunci      = errors.New("nilai terkunci karena rapor sudah diajukan")
+)
+
+// raporTransitions lists the statuses a rapor may move to from each status.
+// Moving back to Draft is only possible through UnlockRapor.
+var raporTransitions = map[string][]string{
+	model.RaporStatusDraft:     {model.RaporStatusSubmitted},
+	model.RaporStatusSubmitted: {model.RaporStatusApproved},
+	model.RaporStatusApproved:  {model.RaporStatusPublished},
+}
+
+// CanTransition reports whether a rapor in status from may move to status to
+func CanTransition(from, to string) bool {
+	if from == "" {
+		from = model.RaporStatusDraft
+	}
+	if to == model.RaporStatusDraft {
+		return from != model.RaporStatusDraft
+	}
+	for _, next := range raporTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNilaiTerkunci reports whether grades behind a rapor in this status may no longer be edited
+func IsNilaiTerkunci(status string) bool {
+	return status != "" && status != model.RaporStatusDraft
+}
+
+// SubmitRapor is done by the wali kelas once the grades are final; it locks them
+func (s *Service) SubmitRapor(ctx context.Context, tenantID, id, userID string) (*model.Rapor, error) {
+	return s.ubahStatus(ctx, tenantID, id, userID, model.RaporStatusSubmitted, "")
+}
+
+// ApproveRapor is done by the kepala sekolah or pengasuh
+func (s *Service) ApproveRapor(ctx context.Context, tenantID, id, userID string) (*model.Rapor, error) {
+	return s.ubahStatus(ctx, tenantID, id, userID, model.RaporStatusApproved, "")
+}
+
+// PublishRapor makes an approved rapor visible in the parent portal and notifies the guardian
+func (s *Service) PublishRapor(ctx context.Context, tenantID, id, userID string) (*model.Rapor, error) {
+	rapor, err := s.ubahStatus(ctx, tenantID, id, userID, model.RaporStatusPublished, "")
+	if err != nil {
+		return nil, err
+	}
+	s.notifyPublished(ctx, rapor)
+	return rapor, nil
+}
+
+// UnlockRapor returns a submitted, approved or published rapor to Draft so its grades can be
+// corrected. The reason is kept in the status log.
+func (s *Service) UnlockRapor(ctx context.Context, tenantID, id, userID, alasan string) (*model.Rapor, error) {
+	alasan = strings.TrimSpace(alasan)
+	if alasan == "" {
+		return nil, ErrAlasanWajib
+	}
+	return s.ubahStatus(ctx, tenantID, id, userID, model.RaporStatusDraft, alasan)
+}
+
+// GetRaporRiwayat returns the status transitions of a rapor, oldest first
+func (s *Service) GetRaporRiwayat(ctx context.Context, tenantID, id string) ([]model.RaporStatusLog, error) {
+	if _, err := s.GetRapor(ctx, tenantID, id); err != nil {
+		return nil, err
+	}
+	return s.db.GetRaporStatusLog(ctx, id)
+}
+
+func (s *Service) ubahStatus(ctx context.Context, tenantID, id, userID, status, alasan string) (*model.Rapor, error) {
+	rapor, err := s.GetRapor(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	dari := rapor.Status
+	if dari == "" {
+		dari = model.RaporStatusDraft
+	}
+	if !CanTransition(dari, status) {
+		return nil, fmt.Errorf("%w: %s ke %s", ErrTransisiTidakValid, dari, status)
+	}
+
+	entry := &model.RaporStatusLog{
+		RaporID:    id,
+		DariStatus: dari,
+		KeStatus:   status,
+		UserID:     userID,
+		Alasan:     alasan,
+	}
+	updated, err := s.db.UpdateRaporStatus(ctx, tenantID, entry)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		// Someone else moved the rapor in the meantime
+		return nil, fmt.Errorf("%w: status rapor sudah berubah", ErrTransisiTidakValid)
+	}
+	rapor.Status = status
+	return rapor, nil
+}
+
+// ensureNilaiTerbuka rejects grade edits for students whose rapor of the semester is past Draft
+func (s *Service) ensureNilaiTerbuka(ctx context.Context, tenantID, semesterID string, siswaIDs ...string) error {
+	statuses, err := s.db.GetRaporStatusBySiswa(ctx, tenantID, semesterID, siswaIDs)
+	if err != nil {
+		return err
+	}
+	locked := 0
+	for _, status := range statuses {
+		if IsNilaiTerkunci(status) {
+			locked++
+		}
+	}
+	if locked > 0 {
+		return fmt.Errorf("%w (%d siswa), buka kunci rapor terlebih dahulu", ErrNilaiTerkunci, locked)
+	}
+	return nil
+}
+
+// GetRaporWaliList lists the published rapor of the children linked to a wali siswa/santri account
+func (s *Service) GetRaporWaliList(ctx context.Context, tenantID, userID string) ([]model.RaporWali, error) {
+	list, err := s.db.GetRaporWaliList(ctx, tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		list[i].Semester = semester.Label(list[i].Semester)
+	}
+	return list, nil
+}
+
+// CetakRaporWali renders a rapor for the parent portal; only published rapor of their own children
+func (s *Service) CetakRaporWali(ctx context.Context, tenantID, userID, id string) ([]byte, error) {
+	list, err := s.db.GetRaporWaliList(ctx, tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range list {
+		if r.ID == id {
+			return s.CetakRapor(ctx, tenantID, id)
+		}
+	}
+	return nil, ErrRaporNotFound
+}
+
+func (s *Service) notifyPublished(ctx context.Context, rapor *model.Rapor) {
+	if s.messagePort == nil || s.messagePort.WhatsApp() == nil {
+		return
+	}
+	siswa, err := s.db.GetSiswaWali(ctx, rapor.TenantID, rapor.SantriID)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Errorf("failed to load wali for rapor %s", rapor.ID)
+		return
+	}
+	if siswa == nil || siswa.NoHPWali == "" {
+		return
+	}
+
+	label := semester.Label(rapor.NamaPeriode)
+	if rapor.Header != nil && rapor.Header.Semester != "" {
+		label = rapor.Header.Semester
+	}
+	message := fmt.Sprintf(
+		"Assalamu'alaikum, %s.\n\nRapor %s semester %s telah terbit dan dapat dilihat melalui portal wali:\n%s\n\nTerima kasih.",
+		siswa.NamaWali, siswa.Nama, label, portalRaporLink(),
+	)
+	if err := s.messagePort.WhatsApp().Send(siswa.NoHPWali, message); err != nil {
+		log.WithContext(ctx).WithError(err).Errorf("failed to send rapor notice for %s", rapor.ID)
+	}
+}
+
+func portalRaporLink() string {
+	baseURL := os.Getenv("FRONTEND_URL")
+	if baseURL == "" {
+		baseURL = "https://eduvera.ve-lora.my.id"
+	}
+	return baseURL + "/portal/rapor"
+}
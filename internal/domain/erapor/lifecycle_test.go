@@ -0,0 +1,44 @@
+package erapor_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain/erapor"
+	"prabogo/internal/model"
+)
+
+func TestRaporLifecycle(t *testing.T) {
+	Convey("Test rapor lifecycle transitions", t, func() {
+		Convey("Moves forward one step at a time", func() {
+			So(erapor.CanTransition(model.RaporStatusDraft, model.RaporStatusSubmitted), ShouldBeTrue)
+			So(erapor.CanTransition("", model.RaporStatusSubmitted), ShouldBeTrue)
+			So(erapor.CanTransition(model.RaporStatusSubmitted, model.RaporStatusApproved), ShouldBeTrue)
+			So(erapor.CanTransition(model.RaporStatusApproved, model.RaporStatusPublished), ShouldBeTrue)
+
+			So(erapor.CanTransition(model.RaporStatusDraft, model.RaporStatusApproved), ShouldBeFalse)
+			So(erapor.CanTransition(model.RaporStatusSubmitted, model.RaporStatusPublished), ShouldBeFalse)
+			So(erapor.CanTransition(model.RaporStatusPublished, model.RaporStatusApproved), ShouldBeFalse)
+		})
+
+		Convey("Unlocking returns any non-draft rapor to Draft", func() {
+			So(erapor.CanTransition(model.RaporStatusSubmitted, model.RaporStatusDraft), ShouldBeTrue)
+			So(erapor.CanTransition(model.RaporStatusPublished, model.RaporStatusDraft), ShouldBeTrue)
+			So(erapor.CanTransition(model.RaporStatusDraft, model.RaporStatusDraft), ShouldBeFalse)
+		})
+
+		Convey("Grades are locked from Submitted on", func() {
+			So(erapor.IsNilaiTerkunci(""), ShouldBeFalse)
+			So(erapor.IsNilaiTerkunci(model.RaporStatusDraft), ShouldBeFalse)
+			So(erapor.IsNilaiTerkunci(model.RaporStatusSubmitted), ShouldBeTrue)
+			So(erapor.IsNilaiTerkunci(model.RaporStatusPublished), ShouldBeTrue)
+		})
+
+		Convey("Unlock requires a reason", func() {
+			_, err := erapor.NewService(nil, nil).UnlockRapor(context.Background(), "tenant-1", "rapor-1", "user-1", "  ")
+			So(err, ShouldEqual, erapor.ErrAlasanWajib)
+		})
+	})
+}
@@ -0,0 +1,188 @@
+package erapor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"prabogo/internal/model"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrP5NotFound      = errors.New("projek P5 tidak ditemukan")
+	ErrP5TanpaTarget   = errors.New("projek P5 harus memiliki minimal satu sub-elemen")
+	ErrP5LevelTidakSah = errors.New("capaian P5 harus salah satu dari MB, SB, BSH, SAB")
+)
+
+// SnapshotP5 freezes the P5 assessment of one student into rapor sections, in project order.
+// Projects the student was not assessed on are left out; targets without a level are skipped.
+func SnapshotP5(projects []model.P5Projek, penilaian []model.P5Penilaian) []model.RaporP5 {
+	byProjek := make(map[string]model.P5Penilaian, len(penilaian))
+	for _, p := range penilaian {
+		byProjek[p.ProjekID] = p
+	}
+
+	var sections []model.RaporP5
+	for _, projek := range projects {
+		nilai, ok := byProjek[projek.ID]
+		if !ok {
+			continue
+		}
+		levels := make(map[string]string, len(nilai.Capaian))
+		for _, c := range nilai.Capaian {
+			levels[c.TargetID] = c.Level
+		}
+
+		section := model.RaporP5{
+			Judul:       projek.Judul,
+			Tema:        projek.Tema,
+			Deskripsi:   projek.Deskripsi,
+			Fasilitator: projek.NamaFasilitator,
+			Catatan:     nilai.Catatan,
+		}
+		for _, t := range projek.Targets {
+			if levels[t.ID] == "" {
+				continue
+			}
+			section.Capaian = append(section.Capaian, model.RaporP5Capaian{
+				Dimensi:   t.Dimensi,
+				Elemen:    t.Elemen,
+				SubElemen: t.SubElemen,
+				Level:     levels[t.ID],
+			})
+		}
+		sections = append(sections, section)
+	}
+	return sections
+}
+
+// GetP5ProjekList lists the P5 projects of a semester, optionally for one kelas
+func (s *Service) GetP5ProjekList(ctx context.Context, tenantID, semesterID, kelasID string) ([]model.P5Projek, error) {
+	return s.db.GetP5ProjekList(ctx, tenantID, semesterID, kelasID)
+}
+
+// GetP5Projek returns a project with the assessment of its students
+func (s *Service) GetP5Projek(ctx context.Context, tenantID, id string) (*model.P5Projek, []model.P5Penilaian, error) {
+	projek, err := s.db.GetP5Projek(ctx, tenantID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if projek == nil {
+		return nil, nil, ErrP5NotFound
+	}
+	penilaian, err := s.db.GetP5Penilaian(ctx, tenantID, id, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	return projek, penilaian, nil
+}
+
+// SaveP5Projek creates or updates a project; the semester and kelas cannot be changed afterwards.
+// Targets keep their ID so existing assessments stay attached when the list is edited.
+func (s *Service) SaveP5Projek(ctx context.Context, p *model.P5Projek) error {
+	p.Tema = strings.TrimSpace(p.Tema)
+	p.Judul = strings.TrimSpace(p.Judul)
+	if p.Tema == "" || p.Judul == "" {
+		return errors.New("tema dan judul projek wajib diisi")
+	}
+	if len(p.Targets) == 0 {
+		return ErrP5TanpaTarget
+	}
+	for i := range p.Targets {
+		t := &p.Targets[i]
+		if !containsString(model.DimensiP5, t.Dimensi) {
+			return fmt.Errorf("dimensi %q bukan dimensi Profil Pelajar Pancasila", t.Dimensi)
+		}
+		if strings.TrimSpace(t.SubElemen) == "" {
+			return errors.New("sub-elemen wajib diisi")
+		}
+		if t.ID == "" {
+			t.ID = uuid.New().String()
+		}
+	}
+
+	if p.ID != "" {
+		existing, err := s.db.GetP5Projek(ctx, p.TenantID, p.ID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return ErrP5NotFound
+		}
+		p.SemesterID = existing.SemesterID
+		p.KelasID = existing.KelasID
+	} else if p.SemesterID == "" || p.KelasID == "" {
+		return errors.New("semester_id dan kelas_id wajib diisi")
+	}
+	return s.db.SaveP5Projek(ctx, p)
+}
+
+// DeleteP5Projek removes a project together with its assessments
+func (s *Service) DeleteP5Projek(ctx context.Context, tenantID, id string) error {
+	return s.db.DeleteP5Projek(ctx, tenantID, id)
+}
+
+// SaveP5Penilaian records the levels and notes of students on a project. Only students of the
+// project's kelas can be assessed, and not once their rapor for the semester is locked.
+func (s *Service) SaveP5Penilaian(ctx context.Context, input *model.P5PenilaianInput) ([]model.P5Penilaian, error) {
+	projek, err := s.db.GetP5Projek(ctx, input.TenantID, input.ProjekID)
+	if err != nil {
+		return nil, err
+	}
+	if projek == nil {
+		return nil, ErrP5NotFound
+	}
+	targets := make(map[string]bool, len(projek.Targets))
+	for _, t := range projek.Targets {
+		targets[t.ID] = true
+	}
+	roster, err := s.db.GetSiswaByKelas(ctx, input.TenantID, projek.KelasID)
+	if err != nil {
+		return nil, err
+	}
+	inKelas := make(map[string]bool, len(roster))
+	for _, siswa := range roster {
+		inKelas[siswa.SiswaID] = true
+	}
+
+	studentIDs := make([]string, 0, len(input.Siswa))
+	for i := range input.Siswa {
+		p := &input.Siswa[i]
+		if !inKelas[p.StudentID] {
+			return nil, ErrSiswaNotFound
+		}
+		for _, c := range p.Capaian {
+			if !targets[c.TargetID] {
+				return nil, fmt.Errorf("sub-elemen %q bukan bagian dari projek", c.TargetID)
+			}
+			if !containsString(model.P5Levels, c.Level) {
+				return nil, ErrP5LevelTidakSah
+			}
+		}
+		p.Catatan = strings.TrimSpace(p.Catatan)
+		studentIDs = append(studentIDs, p.StudentID)
+	}
+	if err := s.ensureNilaiTerbuka(ctx, input.TenantID, projek.SemesterID, studentIDs...); err != nil {
+		return nil, err
+	}
+	if err := s.db.SaveP5Penilaian(ctx, input.TenantID, projek.ID, input.Siswa); err != nil {
+		return nil, err
+	}
+	return s.db.GetP5Penilaian(ctx, input.TenantID, projek.ID, "")
+}
+
+// p5Snapshot collects the P5 sections of a student for the rapor being generated
+func (s *Service) p5Snapshot(ctx context.Context, tenantID, studentID, semesterID string) ([]model.RaporP5, error) {
+	penilaian, err := s.db.GetP5Penilaian(ctx, tenantID, "", studentID)
+	if err != nil || len(penilaian) == 0 {
+		return nil, err
+	}
+	projects, err := s.db.GetP5ProjekList(ctx, tenantID, semesterID, "")
+	if err != nil {
+		return nil, err
+	}
+	return SnapshotP5(projects, penilaian), nil
+}
@@ -0,0 +1,59 @@
+package erapor_test
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain/erapor"
+	"prabogo/internal/model"
+	pdf_utils "prabogo/utils/pdf"
+)
+
+func TestSnapshotP5(t *testing.T) {
+	Convey("Test SnapshotP5", t, func() {
+		projects := []model.P5Projek{
+			{
+				ID:              "p1",
+				Tema:            "Gaya Hidup Berkelanjutan",
+				Judul:           "Bank Sampah Sekolah",
+				NamaFasilitator: "Bu Rina",
+				Targets: []model.P5Target{
+					{ID: "t1", Dimensi: model.DimensiP5[2], Elemen: "Kolaborasi", SubElemen: "Kerja sama"},
+					{ID: "t2", Dimensi: model.DimensiP5[4], Elemen: "Refleksi", SubElemen: "Refleksi pemikiran"},
+				},
+			},
+			{ID: "p2", Tema: "Kearifan Lokal", Judul: "Batik Daerah"},
+		}
+		penilaian := []model.P5Penilaian{
+			{ProjekID: "p1", Capaian: []model.P5Capaian{{TargetID: "t2", Level: model.P5LevelBSH}}, Catatan: "Aktif berdiskusi."},
+		}
+
+		sections := erapor.SnapshotP5(projects, penilaian)
+
+		Convey("Only keeps projects the student was assessed on", func() {
+			So(sections, ShouldHaveLength, 1)
+			So(sections[0].Judul, ShouldEqual, "Bank Sampah Sekolah")
+			So(sections[0].Fasilitator, ShouldEqual, "Bu Rina")
+			So(sections[0].Catatan, ShouldEqual, "Aktif berdiskusi.")
+		})
+
+		Convey("Skips targets without a level", func() {
+			So(sections[0].Capaian, ShouldHaveLength, 1)
+			So(sections[0].Capaian[0].SubElemen, ShouldEqual, "Refleksi pemikiran")
+			So(sections[0].Capaian[0].Level, ShouldEqual, model.P5LevelBSH)
+		})
+
+		Convey("Renders a standalone P5 PDF", func() {
+			rapor := &model.Rapor{
+				ID:     "r1",
+				Header: &model.RaporHeader{Kurikulum: model.KurikulumMerdeka, NamaSekolah: "SMP Harapan", NamaSiswa: "Aisyah"},
+				P5:     sections,
+			}
+			out, err := pdf_utils.GenerateRaporP5PDF(rapor, nil)
+			So(err, ShouldBeNil)
+			So(bytes.HasPrefix(out, []byte("%PDF")), ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,256 @@
+package erapor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"prabogo/internal/domain/erapor/engine"
+	"prabogo/internal/model"
+	"prabogo/utils/semester"
+)
+
+var (
+	ErrBukanTahfidz       = errors.New("mata pelajaran bukan tahfidz")
+	ErrSemesterTidakSah   = semester.ErrTidakSah
+	ErrJenisMapelTidakSah = errors.New("jenis mata pelajaran tidak dikenal")
+	ErrPredikatKosong     = errors.New("aturan predikat tidak boleh kosong")
+)
+
+var subjectTypes = []string{
+	model.SubjectTypeFormalK13,
+	model.SubjectTypeFormalMerdeka,
+	model.SubjectTypePesantrenKitab,
+	model.SubjectTypePesantrenTahfidz,
+}
+
+// TahfidzDeskripsi summarises a semester of setoran for the rapor
+func TahfidzDeskripsi(summary engine.TahfidzSummary) string {
+	parts := make([]string, 0, 3)
+	if len(summary.JuzTercapai) > 0 {
+		juz := make([]string, len(summary.JuzTercapai))
+		for i, j := range summary.JuzTercapai {
+			juz[i] = fmt.Sprintf("%d", j)
+		}
+		parts = append(parts, "hafalan baru juz "+joinDan(juz))
+	} else {
+		parts = append(parts, "belum ada hafalan baru yang diterima")
+	}
+	if summary.Setoran > 0 {
+		parts = append(parts, fmt.Sprintf("%d dari %d setoran lancar", summary.Lancar, summary.Setoran))
+	}
+	parts = append(parts, fmt.Sprintf("murajaah rutin %d dari %d pekan", summary.PekanMurajaah, summary.TotalPekan))
+	text := strings.Join(parts, "; ")
+	return strings.ToUpper(text[:1]) + text[1:] + "."
+}
+
+// GetPredicateSets returns the predicate scale of every subject type: the tenant's own set
+// where there is one, otherwise the built-in default (Arabic for pesantren, A-D otherwise)
+func (s *Service) GetPredicateSets(ctx context.Context, tenantID string) ([]model.PredicateSet, error) {
+	sets, err := s.db.GetPredicateSets(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	byType := make(map[string]model.PredicateSet, len(sets))
+	for _, set := range sets {
+		byType[set.SubjectType] = set
+	}
+
+	result := make([]model.PredicateSet, 0, len(subjectTypes))
+	for _, subjectType := range subjectTypes {
+		set, ok := byType[subjectType]
+		if !ok {
+			set = model.PredicateSet{TenantID: tenantID, SubjectType: subjectType, Rules: defaultPredicateRules(subjectType)}
+		}
+		result = append(result, set)
+	}
+	return result, nil
+}
+
+// SavePredicateSet replaces the tenant's predicate scale of a subject type. Ranges must stay
+// within 0-100 and may not overlap.
+func (s *Service) SavePredicateSet(ctx context.Context, set *model.PredicateSet) error {
+	if !containsString(subjectTypes, set.SubjectType) {
+		return ErrJenisMapelTidakSah
+	}
+	if len(set.Rules) == 0 {
+		return ErrPredikatKosong
+	}
+	if err := validatePredicateRules(set.Rules); err != nil {
+		return err
+	}
+	return s.db.SavePredicateSet(ctx, set)
+}
+
+// validatePredicateRules trims the predicates and checks the ranges stay within 0-100 without
+// overlapping
+func validatePredicateRules(rules []model.PredicateRule) error {
+	for i, rule := range rules {
+		rules[i].Predicate = strings.TrimSpace(rule.Predicate)
+		if rules[i].Predicate == "" {
+			return errors.New("predikat wajib diisi")
+		}
+		if rule.MinScore < 0 || rule.MaxScore > 100 || rule.MinScore > rule.MaxScore {
+			return fmt.Errorf("rentang nilai predikat %s tidak sah", rule.Predicate)
+		}
+		for _, other := range rules[:i] {
+			if rule.MinScore <= other.MaxScore && other.MinScore <= rule.MaxScore {
+				return fmt.Errorf("rentang predikat %s dan %s bertumpuk", other.Predicate, rule.Predicate)
+			}
+		}
+	}
+	return nil
+}
+
+// DeletePredicateSet returns a subject type to the built-in predicate scale
+func (s *Service) DeletePredicateSet(ctx context.Context, tenantID, subjectType string) error {
+	return s.db.DeletePredicateSet(ctx, tenantID, subjectType)
+}
+
+// HitungTahfidz computes the tahfidz grades of a semester from the santri's setoran: juz
+// attained, kualitas and murajaah consistency. It covers one santri, one kelas or, without
+// either, every santri with setoran in the semester. Santri with a locked rapor are skipped.
+func (s *Service) HitungTahfidz(ctx context.Context, tenantID, subjectID, semesterID, kelasID, studentID string) ([]model.StudentGrade, error) {
+	subject, err := s.subjectForTenant(ctx, tenantID, subjectID)
+	if err != nil {
+		return nil, err
+	}
+	if subject.Type != model.SubjectTypePesantrenTahfidz {
+		return nil, ErrBukanTahfidz
+	}
+	start, end, ok := semester.Range(semesterID)
+	if !ok {
+		return nil, ErrSemesterTidakSah
+	}
+	if now := time.Now(); now.Before(end) {
+		end = now
+	}
+
+	studentIDs, _, err := s.sikapStudents(ctx, tenantID, kelasID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if kelasID != "" && len(studentIDs) == 0 {
+		return []model.StudentGrade{}, nil
+	}
+	setoran, err := s.db.GetTahfidzSetoranPeriode(ctx, tenantID, studentIDs, start, end)
+	if err != nil {
+		return nil, err
+	}
+	// Without kelas or santri the roster is whoever deposited setoran in the semester
+	roster := studentIDs == nil
+	bySantri := make(map[string][]model.TahfidzSetoran)
+	for _, st := range setoran {
+		if _, seen := bySantri[st.SantriID]; !seen && roster {
+			studentIDs = append(studentIDs, st.SantriID)
+		}
+		bySantri[st.SantriID] = append(bySantri[st.SantriID], st)
+	}
+	if len(studentIDs) == 0 {
+		return []model.StudentGrade{}, nil
+	}
+
+	statuses, err := s.db.GetRaporStatusBySiswa(ctx, tenantID, semesterID, studentIDs)
+	if err != nil {
+		return nil, err
+	}
+	validator := engine.ValidatorFor(subject)
+	grades := []model.StudentGrade{}
+	for _, id := range studentIDs {
+		if IsNilaiTerkunci(statuses[id]) {
+			continue
+		}
+		components, summary := engine.TahfidzComponents(bySantri[id], subject.GradingConfig, start, end)
+		result := validator.CalculateGrade(components, subject.GradingConfig)
+
+		scores := make([]float64, len(subject.GradingConfig.Components))
+		for i, comp := range subject.GradingConfig.Components {
+			scores[i] = components[comp.Name]
+		}
+		input := &model.StudentGradeInput{
+			TenantID:        tenantID,
+			StudentID:       id,
+			SubjectID:       subject.ID,
+			SemesterID:      semesterID,
+			ScoreNumeric:    result.ScoreNumeric,
+			ScorePredicate:  result.ScorePredicate,
+			DescriptionHigh: TahfidzDeskripsi(summary),
+			DescriptionLow:  result.DescriptionLow,
+			ComponentScores: scores,
+		}
+		if err := s.keepManualDescription(ctx, input); err != nil {
+			return nil, err
+		}
+		grade, err := s.db.SaveGrade(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		grades = append(grades, *grade)
+	}
+	return grades, nil
+}
+
+// resolveGrading completes the grading of subjects at runtime. A subject without predicate
+// rules of its own gets the tenant's scale for its type; pesantren subjects otherwise fall back
+// to the Arabic scale inside their validator. Subjects typed with a curriculum profile code get
+// the profile attached, with its rules and KKM filling what the subject leaves open.
+func (s *Service) resolveGrading(ctx context.Context, subjects ...*model.Subject) error {
+	var tenantID string
+	for _, subject := range subjects {
+		if subject != nil {
+			tenantID = subject.TenantID
+			break
+		}
+	}
+	if tenantID == "" {
+		return nil
+	}
+	sets, err := s.db.GetPredicateSets(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	byType := make(map[string][]model.PredicateRule, len(sets))
+	for _, set := range sets {
+		byType[set.SubjectType] = set.Rules
+	}
+
+	var profiles map[string]*model.CurriculumProfile
+	for _, subject := range subjects {
+		if subject == nil {
+			continue
+		}
+		if !engine.IsBuiltinType(subject.Type) {
+			if profiles == nil {
+				if profiles, err = s.curriculumProfilesByCode(ctx, tenantID); err != nil {
+					return err
+				}
+			}
+			applyCurriculumProfile(subject, profiles[subject.Type])
+		}
+		if len(subject.GradingConfig.PredicateRules) > 0 {
+			continue
+		}
+		if rules, ok := byType[subject.Type]; ok {
+			subject.GradingConfig.PredicateRules = rules
+		} else if isPesantren(subject.Type) {
+			subject.GradingConfig.PredicateRules = model.DefaultPesantrenPredicateRules()
+		}
+	}
+	return nil
+}
+
+func defaultPredicateRules(subjectType string) []model.PredicateRule {
+	switch subjectType {
+	case model.SubjectTypeFormalK13:
+		return model.DefaultK13GradingConfig().PredicateRules
+	case model.SubjectTypeFormalMerdeka:
+		return model.DefaultMerdekaGradingConfig().PredicateRules
+	}
+	return model.DefaultPesantrenPredicateRules()
+}
+
+func isPesantren(subjectType string) bool {
+	return subjectType == model.SubjectTypePesantrenKitab || subjectType == model.SubjectTypePesantrenTahfidz
+}
@@ -0,0 +1,80 @@
+package erapor_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain/erapor"
+	"prabogo/internal/domain/erapor/engine"
+	"prabogo/internal/model"
+	"prabogo/utils/semester"
+)
+
+func TestKitabValidator(t *testing.T) {
+	Convey("Test KitabValidator", t, func() {
+		config := model.DefaultKitabGradingConfig()
+		validator := engine.GetValidator(model.SubjectTypePesantrenKitab)
+
+		Convey("Uses the Arabic scale and names strong and weak components", func() {
+			result := validator.CalculateGrade(map[string]float64{
+				"Qiro'ah":       95,
+				"Tarjamah":      90,
+				"Hafalan Matan": 60,
+			}, config)
+			So(result.ScoreNumeric, ShouldAlmostEqual, 83, 0.001)
+			So(result.ScorePredicate, ShouldEqual, "Jayyid Jiddan")
+			So(result.DescriptionHigh, ShouldContainSubstring, "menonjol dalam qiro'ah")
+			So(result.DescriptionLow, ShouldEqual, "Perlu penguatan dalam hafalan matan")
+		})
+
+		Convey("Fractional scores between ranges take the lower predicate", func() {
+			result := validator.CalculateGrade(map[string]float64{
+				"Qiro'ah": 89.5, "Tarjamah": 89.5, "Hafalan Matan": 89.5,
+			}, config)
+			So(result.ScorePredicate, ShouldEqual, "Jayyid Jiddan")
+		})
+
+		Convey("Tenant rules replace the default scale", func() {
+			config.PredicateRules = []model.PredicateRule{
+				{MinScore: 75, MaxScore: 100, Predicate: "Najih"},
+				{MinScore: 0, MaxScore: 74, Predicate: "Rasib"},
+			}
+			result := validator.CalculateGrade(map[string]float64{
+				"Qiro'ah": 70, "Tarjamah": 70, "Hafalan Matan": 70,
+			}, config)
+			So(result.ScorePredicate, ShouldEqual, "Rasib")
+		})
+	})
+}
+
+func TestTahfidzComponents(t *testing.T) {
+	Convey("Test TahfidzComponents", t, func() {
+		start, end, ok := semester.Range("2025-2026-2")
+		So(ok, ShouldBeTrue)
+		So(start, ShouldEqual, time.Date(2026, time.January, 1, 0, 0, 0, 0, time.Local))
+		So(end.Month(), ShouldEqual, time.June)
+
+		config := model.DefaultTahfidzGradingConfig()
+		config.Tahfidz = &model.TahfidzTarget{TargetJuz: 2, MurajaahPerPekan: 1}
+		day := func(d int) time.Time { return start.AddDate(0, 0, d) }
+		setoran := []model.TahfidzSetoran{
+			{Tanggal: day(0), Tipe: "Ziyadah", Juz: 30, Kualitas: "Lancar"},
+			{Tanggal: day(1), Tipe: "Ziyadah", Juz: 29, Kualitas: "Ulang"},
+			{Tanggal: day(2), Tipe: "Murajaah", Kualitas: "Kurang"},
+			{Tanggal: day(9), Tipe: "Murajaah", Kualitas: "Lancar"},
+			{Tanggal: day(400), Tipe: "Ziyadah", Juz: 28, Kualitas: "Lancar"},
+		}
+
+		components, summary := engine.TahfidzComponents(setoran, config, start, end)
+		So(summary.JuzTercapai, ShouldResemble, []int{30})
+		So(summary.Setoran, ShouldEqual, 4)
+		So(summary.Lancar, ShouldEqual, 2)
+		So(summary.PekanMurajaah, ShouldEqual, 2)
+		So(components[model.TahfidzKomponenJuz], ShouldEqual, 50)
+		So(components[model.TahfidzKomponenKualitas], ShouldAlmostEqual, 77.5, 0.001)
+
+		So(erapor.TahfidzDeskripsi(summary), ShouldStartWith, "Hafalan baru juz 30; 2 dari 4 setoran lancar; murajaah rutin 2 dari")
+	})
+}
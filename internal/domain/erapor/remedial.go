@@ -0,0 +1,251 @@
+package erapor
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"prabogo/internal/domain/erapor/engine"
+	"prabogo/internal/model"
+)
+
+const defaultKKM = 75
+
+var (
+	ErrTanpaKKM        = errors.New("mata pelajaran tidak menggunakan KKM")
+	ErrNilaiBelumAda   = errors.New("nilai akhir siswa untuk mata pelajaran ini belum ada")
+	ErrJenisRemedial   = errors.New("jenis sesi harus remedial atau enrichment")
+	ErrSudahTuntas     = errors.New("nilai sudah mencapai KKM, gunakan pengayaan (enrichment)")
+	ErrBelumTuntas     = errors.New("nilai masih di bawah KKM, gunakan remedial")
+	ErrNilaiTidakSah   = errors.New("nilai harus di antara 0 dan 100")
+	ErrTanggalTidakSah = errors.New("format tanggal harus YYYY-MM-DD")
+)
+
+// KKMOf returns the subject KKM, falling back to the K13 default of 75
+func KKMOf(config model.GradingConfig) int {
+	if config.KKMValue > 0 {
+		return config.KKMValue
+	}
+	return defaultKKM
+}
+
+// ApplyRemedial returns the final score after a remedial attempt. With capping on the remedial
+// result counts at most as the KKM, and a remedial never lowers the original score.
+func ApplyRemedial(original, remedialScore float64, kkm int, capAtKKM bool) (final float64, capped bool) {
+	result := remedialScore
+	if capAtKKM && result > float64(kkm) {
+		result = float64(kkm)
+		capped = true
+	}
+	if result < original {
+		return original, false
+	}
+	return result, capped
+}
+
+// RecordRemedial stores a remedial or enrichment session. A remedial that improves the score
+// updates the student's final grade; enrichment is recorded only.
+func (s *Service) RecordRemedial(ctx context.Context, input *model.RemedialInput) (*model.RemedialSession, *model.StudentGrade, error) {
+	if input.StudentID == "" || input.SubjectID == "" || input.SemesterID == "" {
+		return nil, nil, errors.New("student_id, subject_id dan semester_id wajib diisi")
+	}
+	if input.Type == "" {
+		input.Type = model.RemedialTypeRemedial
+	}
+	if input.Type != model.RemedialTypeRemedial && input.Type != model.RemedialTypeEnrichment {
+		return nil, nil, ErrJenisRemedial
+	}
+	if input.Score < 0 || input.Score > 100 {
+		return nil, nil, ErrNilaiTidakSah
+	}
+	date := time.Now()
+	if input.Date != "" {
+		parsed, err := time.Parse("2006-01-02", input.Date)
+		if err != nil {
+			return nil, nil, ErrTanggalTidakSah
+		}
+		date = parsed
+	}
+
+	subject, err := s.subjectForTenant(ctx, input.TenantID, input.SubjectID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if subject, err = s.subjectForSemester(ctx, subject, input.SemesterID); err != nil {
+		return nil, nil, err
+	}
+	if !subject.GradingConfig.UseKKM {
+		return nil, nil, ErrTanpaKKM
+	}
+	if err := s.ensureNilaiTerbuka(ctx, input.TenantID, input.SemesterID, input.StudentID); err != nil {
+		return nil, nil, err
+	}
+	grade, err := s.gradeOf(ctx, input.StudentID, input.SubjectID, input.SemesterID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	kkm := KKMOf(subject.GradingConfig)
+	below := grade.ScoreNumeric < float64(kkm)
+	if input.Type == model.RemedialTypeRemedial && !below {
+		return nil, nil, ErrSudahTuntas
+	}
+	if input.Type == model.RemedialTypeEnrichment && below {
+		return nil, nil, ErrBelumTuntas
+	}
+
+	session := &model.RemedialSession{
+		TenantID:      input.TenantID,
+		StudentID:     input.StudentID,
+		SubjectID:     input.SubjectID,
+		SemesterID:    input.SemesterID,
+		Type:          input.Type,
+		Date:          date,
+		Topic:         strings.TrimSpace(input.Topic),
+		KKM:           kkm,
+		OriginalScore: grade.ScoreNumeric,
+		RemedialScore: input.Score,
+		FinalScore:    grade.ScoreNumeric,
+		Notes:         strings.TrimSpace(input.Notes),
+		CreatedBy:     input.CreatedBy,
+	}
+	if input.Type == model.RemedialTypeRemedial {
+		session.FinalScore, session.Capped = ApplyRemedial(grade.ScoreNumeric, input.Score, kkm, subject.GradingConfig.CapRemedial)
+	}
+	// The session is rolled back when the raised grade cannot be saved
+	err = s.inTransaction(func(tx *Service) error {
+		if err := tx.db.CreateRemedialSession(ctx, session); err != nil {
+			return err
+		}
+		if session.FinalScore <= grade.ScoreNumeric {
+			return nil
+		}
+		result := scoreResult(subject, session.FinalScore)
+		if grade.DescriptionManual {
+			result.DescriptionHigh, result.DescriptionLow = grade.DescriptionHigh, grade.DescriptionLow
+		}
+		grade, err = tx.db.SaveGrade(ctx, &model.StudentGradeInput{
+			TenantID:          input.TenantID,
+			StudentID:         grade.StudentID,
+			SubjectID:         grade.SubjectID,
+			SemesterID:        grade.SemesterID,
+			ScoreNumeric:      session.FinalScore,
+			ScorePredicate:    result.ScorePredicate,
+			DescriptionHigh:   result.DescriptionHigh,
+			DescriptionLow:    result.DescriptionLow,
+			DescriptionManual: grade.DescriptionManual,
+			ComponentScores:   grade.ComponentScores,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return session, grade, nil
+}
+
+// GetRemedialHistory returns every session of a subject/semester, optionally for one student
+func (s *Service) GetRemedialHistory(ctx context.Context, tenantID, subjectID, semesterID, studentID string) ([]model.RemedialSession, error) {
+	if _, err := s.subjectForTenant(ctx, tenantID, subjectID); err != nil {
+		return nil, err
+	}
+	return s.db.GetRemedialSessions(ctx, tenantID, subjectID, semesterID, studentID)
+}
+
+// GetRemedialCandidates lists the students still below KKM for a subject, lowest score first,
+// optionally limited to one kelas so the wali kelas can clear them before the rapor is locked
+func (s *Service) GetRemedialCandidates(ctx context.Context, tenantID, subjectID, semesterID, kelasID string) ([]model.RemedialCandidate, error) {
+	subject, err := s.subjectForTenant(ctx, tenantID, subjectID)
+	if err != nil {
+		return nil, err
+	}
+	if !subject.GradingConfig.UseKKM {
+		return nil, ErrTanpaKKM
+	}
+	if subject, err = s.subjectForSemester(ctx, subject, semesterID); err != nil {
+		return nil, err
+	}
+	kkm := KKMOf(subject.GradingConfig)
+
+	grades, err := s.db.GetGradesBySubject(ctx, subjectID, semesterID)
+	if err != nil {
+		return nil, err
+	}
+	var inKelas map[string]bool
+	if kelasID != "" {
+		roster, err := s.db.GetSiswaByKelas(ctx, tenantID, kelasID)
+		if err != nil {
+			return nil, err
+		}
+		inKelas = make(map[string]bool, len(roster))
+		for _, siswa := range roster {
+			inKelas[siswa.SiswaID] = true
+		}
+	}
+	sessions, err := s.db.GetRemedialSessions(ctx, tenantID, subjectID, semesterID, "")
+	if err != nil {
+		return nil, err
+	}
+	attempts := make(map[string]int)
+	for _, session := range sessions {
+		if session.Type == model.RemedialTypeRemedial {
+			attempts[session.StudentID]++
+		}
+	}
+
+	candidates := []model.RemedialCandidate{}
+	for _, g := range grades {
+		if g.ScoreNumeric >= float64(kkm) || (inKelas != nil && !inKelas[g.StudentID]) {
+			continue
+		}
+		candidates = append(candidates, model.RemedialCandidate{
+			StudentID:   g.StudentID,
+			StudentName: g.StudentName,
+			Score:       g.ScoreNumeric,
+			KKM:         kkm,
+			Sessions:    attempts[g.StudentID],
+		})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Score < candidates[j].Score })
+	return candidates, nil
+}
+
+func (s *Service) gradeOf(ctx context.Context, studentID, subjectID, semesterID string) (*model.StudentGrade, error) {
+	grades, err := s.db.GetGradesByStudent(ctx, studentID, semesterID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range grades {
+		if grades[i].SubjectID == subjectID {
+			return &grades[i], nil
+		}
+	}
+	return nil, ErrNilaiBelumAda
+}
+
+// remedialFloor keeps the best remedial result when a grade is recalculated from its entries
+func (s *Service) remedialFloor(ctx context.Context, subject *model.Subject, studentID, semesterID string, score float64) (float64, error) {
+	if !subject.GradingConfig.UseKKM {
+		return score, nil
+	}
+	sessions, err := s.db.GetRemedialSessions(ctx, subject.TenantID, subject.ID, semesterID, studentID)
+	if err != nil {
+		return 0, err
+	}
+	kkm := KKMOf(subject.GradingConfig)
+	for _, session := range sessions {
+		if session.Type == model.RemedialTypeRemedial {
+			score, _ = ApplyRemedial(score, session.RemedialScore, kkm, subject.GradingConfig.CapRemedial)
+		}
+	}
+	return score, nil
+}
+
+// scoreResult runs a single final score through the subject's validator to get its predicate
+func scoreResult(subject *model.Subject, score float64) engine.ValidationResult {
+	config := subject.GradingConfig
+	config.Components = []model.GradingComponent{{Name: "nilai", Weight: 1}}
+	return engine.ValidatorFor(subject).CalculateGrade(map[string]float64{"nilai": score}, config)
+}
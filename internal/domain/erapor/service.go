@@ -2,20 +2,58 @@ package erapor
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
 
 	"prabogo/internal/domain/erapor/engine"
 	"prabogo/internal/model"
 	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/log"
+	pdf_utils "prabogo/utils/pdf"
+	"prabogo/utils/semester"
+)
+
+const (
+	logoFetchTimeout = 5 * time.Second
+	maxLogoSize      = 2 << 20
+)
+
+var (
+	ErrSiswaNotFound = errors.New("siswa tidak ditemukan")
+	ErrRaporNotFound = errors.New("rapor tidak ditemukan")
+	ErrRaporTerkunci = errors.New("rapor sudah diajukan, buka kunci ke Draft untuk membuat ulang")
 )
 
 // Service adalah domain service untuk E-Rapor
 type Service struct {
-	db outbound_port.ERaporDatabasePort
+	databasePort outbound_port.DatabasePort
+	db           outbound_port.ERaporDatabasePort
+	messagePort  outbound_port.MessagePort
 }
 
 // NewService membuat instance baru Service
-func NewService(db outbound_port.ERaporDatabasePort) *Service {
-	return &Service{db: db}
+func NewService(databasePort outbound_port.DatabasePort, messagePort outbound_port.MessagePort) *Service {
+	s := &Service{databasePort: databasePort, messagePort: messagePort}
+	if databasePort != nil {
+		s.db = databasePort.ERapor()
+	}
+	return s
+}
+
+// inTransaction runs fn with a Service whose database calls share one transaction
+func (s *Service) inTransaction(fn func(tx *Service) error) error {
+	_, err := s.databasePort.DoInTransaction(func(tx outbound_port.DatabasePort) (interface{}, error) {
+		return nil, fn(&Service{databasePort: tx, db: tx.ERapor(), messagePort: s.messagePort})
+	})
+	return err
 }
 
 // ==========================================
@@ -26,18 +64,49 @@ func NewService(db outbound_port.ERaporDatabasePort) *Service {
 func (s *Service) CreateSubject(ctx context.Context, input *model.SubjectInput) (*model.Subject, error) {
 	// Apply default grading config if empty
 	if len(input.GradingConfig.Components) == 0 {
-		if input.Type == model.SubjectTypeFormalK13 {
+		switch input.Type {
+		case model.SubjectTypeFormalK13:
 			input.GradingConfig = model.DefaultK13GradingConfig()
-		} else {
+		case model.SubjectTypePesantrenKitab:
+			input.GradingConfig = model.DefaultKitabGradingConfig()
+		case model.SubjectTypePesantrenTahfidz:
+			input.GradingConfig = model.DefaultTahfidzGradingConfig()
+		case model.SubjectTypeFormalMerdeka:
 			input.GradingConfig = model.DefaultMerdekaGradingConfig()
+		default:
+			// Subjects typed with a curriculum profile code start from the profile's components
+			profile, err := s.db.GetCurriculumProfile(ctx, input.TenantID, input.Type)
+			if err != nil {
+				return nil, err
+			}
+			if profile == nil {
+				input.GradingConfig = model.DefaultMerdekaGradingConfig()
+			} else {
+				input.GradingConfig = profile.GradingConfig
+			}
 		}
 	}
 	return s.db.CreateSubject(ctx, input)
 }
 
-// UpdateSubject mengupdate mata pelajaran
+// UpdateSubject mengupdate mata pelajaran dan menghitung ulang nilai akhir dari penilaian yang ada
 func (s *Service) UpdateSubject(ctx context.Context, id string, input *model.SubjectInput) (*model.Subject, error) {
-	return s.db.UpdateSubject(ctx, id, input)
+	old, err := s.db.GetSubjectByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if old != nil {
+		if err := s.pinGradedKKM(ctx, old, input.GradingConfig); err != nil {
+			return nil, err
+		}
+	}
+
+	subject, err := s.db.UpdateSubject(ctx, id, input)
+	if err != nil {
+		return nil, err
+	}
+	s.recalculateSubject(ctx, subject)
+	return subject, nil
 }
 
 // GetSubjectByID mengambil mata pelajaran berdasarkan ID
@@ -61,15 +130,25 @@ func (s *Service) DeleteSubject(ctx context.Context, id string) error {
 
 // SaveGrade menyimpan nilai siswa dengan kalkulasi predicate otomatis
 func (s *Service) SaveGrade(ctx context.Context, input *model.StudentGradeInput) (*model.StudentGrade, error) {
+	if err := s.ensureNilaiTerbuka(ctx, input.TenantID, input.SemesterID, input.StudentID); err != nil {
+		return nil, err
+	}
+
 	// Get subject to apply grading rules
 	subject, err := s.db.GetSubjectByID(ctx, input.SubjectID)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.resolveGrading(ctx, subject); err != nil {
+		return nil, err
+	}
+	if subject, err = s.subjectForSemester(ctx, subject, input.SemesterID); err != nil {
+		return nil, err
+	}
 
 	// Auto-calculate using Validator Engine
 	// Use Subject Type (e.g., FORMAL_MERDEKA) to determining validation strategy
-	validator := engine.GetValidator(subject.Type)
+	validator := engine.ValidatorFor(subject)
 
 	// Map component slice to map for validator
 	compMap := make(map[string]float64)
@@ -88,6 +167,11 @@ func (s *Service) SaveGrade(ctx context.Context, input *model.StudentGradeInput)
 	if input.ScorePredicate == "" {
 		input.ScorePredicate = result.ScorePredicate
 	}
+	if input.DescriptionHigh == "" && input.DescriptionLow == "" {
+		if err := s.keepManualDescription(ctx, input); err != nil {
+			return nil, err
+		}
+	}
 	if input.DescriptionHigh == "" {
 		input.DescriptionHigh = result.DescriptionHigh
 	}
@@ -100,14 +184,28 @@ func (s *Service) SaveGrade(ctx context.Context, input *model.StudentGradeInput)
 
 // BatchSaveGrades menyimpan banyak nilai sekaligus
 func (s *Service) BatchSaveGrades(ctx context.Context, input *model.BatchGradeInput) ([]model.StudentGrade, error) {
+	siswaIDs := make([]string, len(input.Grades))
+	for i, g := range input.Grades {
+		siswaIDs[i] = g.StudentID
+	}
+	if err := s.ensureNilaiTerbuka(ctx, input.TenantID, input.SemesterID, siswaIDs...); err != nil {
+		return nil, err
+	}
+
 	// Get subject for predicate calculation
 	subject, err := s.db.GetSubjectByID(ctx, input.SubjectID)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.resolveGrading(ctx, subject); err != nil {
+		return nil, err
+	}
+	if subject, err = s.subjectForSemester(ctx, subject, input.SemesterID); err != nil {
+		return nil, err
+	}
 
 	// Auto-calculate predicates using Validator Engine
-	validator := engine.GetValidator(subject.Type)
+	validator := engine.ValidatorFor(subject)
 
 	for i := range input.Grades {
 		// Map component slice to map
@@ -141,14 +239,20 @@ func (s *Service) GetGradesBySubject(ctx context.Context, subjectID, semesterID
 	return s.db.GetGradesBySubject(ctx, subjectID, semesterID)
 }
 
-// GetStudentRapor mengambil data rapor lengkap untuk siswa
-func (s *Service) GetStudentRapor(ctx context.Context, studentID, semesterID string) (*model.RaporData, error) {
-	return s.db.GetStudentRapor(ctx, studentID, semesterID)
-}
-
-// GetGradeStats mengambil statistik nilai
-func (s *Service) GetGradeStats(ctx context.Context, tenantID, semesterID string) (map[string]interface{}, error) {
-	return s.db.GetGradeStats(ctx, tenantID, semesterID)
+// GetStudentRapor mengambil data rapor lengkap untuk siswa; sikap hanya disertakan untuk kurikulum K13
+func (s *Service) GetStudentRapor(ctx context.Context, tenantID, studentID, semesterID string) (*model.RaporData, error) {
+	header, err := s.raporHeader(ctx, tenantID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	rapor, err := s.db.GetStudentRapor(ctx, tenantID, studentID, semesterID)
+	if err != nil {
+		return nil, err
+	}
+	if header.Kurikulum != model.KurikulumK13 {
+		rapor.Sikap = nil
+	}
+	return rapor, nil
 }
 
 // ==========================================
@@ -157,21 +261,7 @@ func (s *Service) GetGradeStats(ctx context.Context, tenantID, semesterID string
 
 // calculatePredicate menghitung predikat berdasarkan nilai dan config
 func (s *Service) calculatePredicate(score float64, config model.GradingConfig) string {
-	scoreInt := int(score)
-	for _, rule := range config.PredicateRules {
-		if scoreInt >= rule.MinScore && scoreInt <= rule.MaxScore {
-			return rule.Predicate
-		}
-	}
-	// Default fallback
-	if scoreInt >= 90 {
-		return "A"
-	} else if scoreInt >= 80 {
-		return "B"
-	} else if scoreInt >= 70 {
-		return "C"
-	}
-	return "D"
+	return predicateFor(score, config)
 }
 
 // CalculateFinalScore menghitung nilai akhir dari component scores
@@ -215,8 +305,18 @@ func (s *Service) GenerateDescription(score float64, subjectName string) (high,
 	return high, low
 }
 
-// GenerateRapor generates a persistent snapshot of the rapor
-func (s *Service) GenerateRapor(ctx context.Context, tenantID, studentID, semesterID string, catatanWali string) (*model.Rapor, error) {
+// GenerateRapor generates a persistent snapshot of the rapor. Besides the grades it freezes the
+// school, student, class and signatory data so later prints match the original.
+func (s *Service) GenerateRapor(ctx context.Context, tenantID, studentID, semesterID string, catatanWali string, kehadiran model.AttendanceData) (*model.Rapor, error) {
+	header, err := s.raporHeader(ctx, tenantID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	header.Semester = semester.Label(semesterID)
+	header.TanggalRapor = time.Now()
+	header.Kehadiran = kehadiran
+	header.LogoHash = s.snapshotLogo(ctx, tenantID, header.LogoURL)
+
 	// 1. Get or Create Rapor Periode
 	periode, err := s.db.GetOrCreateRaporPeriode(tenantID, semesterID)
 	if err != nil {
@@ -224,39 +324,216 @@ func (s *Service) GenerateRapor(ctx context.Context, tenantID, studentID, semest
 	}
 
 	// 2. Fetch Calculated Grades (Dynamic)
-	raporData, err := s.db.GetStudentRapor(ctx, studentID, semesterID)
+	raporData, err := s.db.GetStudentRapor(ctx, tenantID, studentID, semesterID)
+	if err != nil {
+		return nil, err
+	}
+	subjects, err := s.db.GetSubjectsByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	subjectByID := make(map[string]*model.Subject, len(subjects))
+	subjectPtrs := make([]*model.Subject, len(subjects))
+	for i := range subjects {
+		subjectByID[subjects[i].ID] = &subjects[i]
+		subjectPtrs[i] = &subjects[i]
+	}
+	if err := s.resolveGrading(ctx, subjectPtrs...); err != nil {
+		return nil, err
+	}
+	pinnedKKM, err := s.db.GetSemesterKKM(ctx, tenantID, semesterID)
 	if err != nil {
 		return nil, err
 	}
+	for _, subject := range subjectPtrs {
+		if subject.GradingConfig.UseKKM {
+			subject.GradingConfig.KKMValue = SemesterKKM(subject.GradingConfig, pinnedKKM, subject.ID)
+		}
+	}
+
+	nilaiList := make([]model.RaporNilai, 0, len(raporData.Grades)+len(raporData.Extracurricular))
+	for _, grade := range raporData.Grades {
+		nilaiList = append(nilaiList, SnapshotNilai(grade, subjectByID[grade.SubjectID]))
+	}
+	if header.Kurikulum == model.KurikulumK13 {
+		nilaiList = append(nilaiList, SnapshotSikap(raporData.Sikap)...)
+	}
+	for _, ekskul := range raporData.Extracurricular {
+		nilaiList = append(nilaiList, model.RaporNilai{
+			Kategori:   model.RaporKategoriEkstrakurikuler,
+			Jenis:      ekskul.Name,
+			Nilai:      ekskul.Predicate,
+			Keterangan: ekskul.Description,
+		})
+	}
+	sortRaporNilai(nilaiList)
+
+	var p5 []model.RaporP5
+	if header.Kurikulum == model.KurikulumMerdeka {
+		if p5, err = s.p5Snapshot(ctx, tenantID, studentID, semesterID); err != nil {
+			return nil, err
+		}
+	}
 
 	// 3. Create Rapor Header
 	raporHeader := &model.Rapor{
 		TenantID:         tenantID,
 		PeriodeID:        periode.ID,
 		SantriID:         studentID,
-		Status:           "Draft",
+		Status:           model.RaporStatusDraft,
 		CatatanWaliKelas: catatanWali,
+		Header:           header,
+		P5:               p5,
+		NilaiList:        nilaiList,
 	}
-	if err := s.db.CreateRapor(raporHeader); err != nil {
+	// 4. Save the header with its nilai rows; a failed row leaves no partial rapor.
+	// A Draft rapor (new or unlocked for correction) is replaced by this snapshot.
+	saved, err := s.db.SaveRaporSnapshot(ctx, raporHeader)
+	if err != nil {
 		return nil, err
 	}
+	if !saved {
+		return nil, ErrRaporTerkunci
+	}
 
-	// 4. Create Rapor Details (Nilai)
-	for _, grade := range raporData.Grades {
-		nilai := &model.RaporNilai{
-			RaporID:    raporHeader.ID,
-			Kategori:   "Akademik", // Default category
-			Jenis:      grade.SubjectName,
-			Nilai:      grade.ScorePredicate, // Use predicate (A, B, C) for rapor
-			Keterangan: grade.DescriptionHigh + ". " + grade.DescriptionLow,
-		}
-		// If subject has specific category requirement, can be adjusted here.
-		if err := s.db.CreateRaporNilai(nilai); err != nil {
-			// Continue or fail? Ideally transaction.
-			// For now log error but continue
-			continue
+	return raporHeader, nil
+}
+
+// GetRapor mengambil snapshot rapor beserta nilainya
+func (s *Service) GetRapor(ctx context.Context, tenantID, id string) (*model.Rapor, error) {
+	rapor, err := s.db.GetRaporByID(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if rapor == nil {
+		return nil, ErrRaporNotFound
+	}
+	return rapor, nil
+}
+
+// CetakRapor renders the printable rapor PDF from the frozen snapshot
+func (s *Service) CetakRapor(ctx context.Context, tenantID, id string) ([]byte, error) {
+	rapor, err := s.GetRapor(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return pdf_utils.GenerateRaporPDF(rapor, s.raporLogo(ctx, rapor))
+}
+
+// CetakRaporP5 renders the standalone P5 report of a Merdeka rapor from its snapshot
+func (s *Service) CetakRaporP5(ctx context.Context, tenantID, id string) ([]byte, error) {
+	rapor, err := s.GetRapor(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(rapor.P5) == 0 {
+		return nil, ErrP5NotFound
+	}
+
+	return pdf_utils.GenerateRaporP5PDF(rapor, s.raporLogo(ctx, rapor))
+}
+
+// snapshotLogo fetches the tenant logo once at generation and stores it by content
+// hash, so prints use the logo of that moment. A failure only drops the logo.
+func (s *Service) snapshotLogo(ctx context.Context, tenantID, url string) string {
+	if url == "" {
+		return ""
+	}
+	logo, err := fetchLogo(ctx, url)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Warnf("failed to fetch logo for tenant %s", tenantID)
+		return ""
+	}
+	sum := sha256.Sum256(logo)
+	hash := hex.EncodeToString(sum[:])
+	if err := s.db.SaveRaporLogo(ctx, tenantID, hash, logo); err != nil {
+		log.WithContext(ctx).WithError(err).Warnf("failed to store logo for tenant %s", tenantID)
+		return ""
+	}
+	return hash
+}
+
+// raporLogo loads the logo frozen into the snapshot. Snapshots generated before
+// logos were stored still fetch their URL. A missing logo should not block printing.
+func (s *Service) raporLogo(ctx context.Context, rapor *model.Rapor) []byte {
+	if rapor.Header == nil {
+		return nil
+	}
+	var logo []byte
+	var err error
+	switch {
+	case rapor.Header.LogoHash != "":
+		logo, err = s.db.GetRaporLogo(ctx, rapor.TenantID, rapor.Header.LogoHash)
+	case rapor.Header.LogoURL != "":
+		logo, err = fetchLogo(ctx, rapor.Header.LogoURL)
+	}
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Warnf("failed to load logo for rapor %s", rapor.ID)
+		return nil
+	}
+	return logo
+}
+
+// logoClient only dials public addresses. The logo URL is tenant-controlled, so the
+// check runs on the resolved IP of every connection, including redirects.
+var logoClient = &http.Client{
+	Timeout: logoFetchTimeout,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: logoFetchTimeout,
+			Control: dialPublicOnly,
+		}).DialContext,
+		TLSHandshakeTimeout:   logoFetchTimeout,
+		ResponseHeaderTimeout: logoFetchTimeout,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 3 {
+			return errors.New("too many logo redirects")
 		}
+		return nil
+	},
+}
+
+func dialPublicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+		return fmt.Errorf("logo host %s is not a public address", host)
 	}
+	return nil
+}
 
-	return raporHeader, nil
+// fetchLogo downloads the tenant logo referenced by the snapshot
+func fetchLogo(ctx context.Context, url string) ([]byte, error) {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("unsupported logo url %q", url)
+	}
+	ctx, cancel := context.WithTimeout(ctx, logoFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := logoClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("logo request returned %s", resp.Status)
+	}
+	logo, err := io.ReadAll(io.LimitReader(resp.Body, maxLogoSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(logo) > maxLogoSize {
+		return nil, fmt.Errorf("logo exceeds %d bytes", maxLogoSize)
+	}
+	return logo, nil
 }
@@ -0,0 +1,316 @@
+package erapor
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"prabogo/internal/model"
+)
+
+// maxButirSikap is how many journal items are named per sentence of a sikap description
+const maxButirSikap = 3
+
+var (
+	ErrAspekSikap      = errors.New("aspek sikap harus spiritual atau sosial")
+	ErrJenisSikap      = errors.New("jenis jurnal harus positif atau negatif")
+	ErrPredikatSikap   = errors.New("predikat sikap harus salah satu dari SB, B, C, K")
+	ErrJurnalNotFound  = errors.New("jurnal sikap tidak ditemukan")
+	ErrSikapNotFound   = errors.New("sikap siswa tidak ditemukan")
+	ErrBukanPencatat   = errors.New("jurnal hanya dapat dihapus oleh pencatatnya")
+	ErrSikapTanpaSiswa = errors.New("kelas_id atau student_id wajib diisi")
+)
+
+// AggregateSikap compiles the journal entries of one aspect into a K13 predicate and description.
+// Without negative notes the student is Baik, or Sangat Baik from three positive notes on; negative
+// notes outweighing the positive ones lower it to Cukup, and to Kurang when they exceed them by three.
+func AggregateSikap(entries []model.JurnalSikap, aspek, nama string) (predikat, deskripsi string) {
+	var positif, negatif []model.JurnalSikap
+	for _, e := range entries {
+		if e.Aspek != aspek {
+			continue
+		}
+		if e.Jenis == model.JurnalSikapNegatif {
+			negatif = append(negatif, e)
+		} else {
+			positif = append(positif, e)
+		}
+	}
+
+	switch selisih := len(negatif) - len(positif); {
+	case len(negatif) == 0 && len(positif) >= 3:
+		predikat = model.SikapPredikatSB
+	case selisih <= 0:
+		predikat = model.SikapPredikatB
+	case selisih < 3:
+		predikat = model.SikapPredikatC
+	default:
+		predikat = model.SikapPredikatK
+	}
+
+	deskripsi = "Ananda " + nama + " menunjukkan sikap " + aspek + " yang " + strings.ToLower(model.SikapPredikatLabel[predikat])
+	if butir := topButir(positif); len(butir) > 0 {
+		deskripsi += ", terutama dalam " + joinDan(butir)
+	}
+	if butir := topButir(negatif); len(butir) > 0 {
+		deskripsi += ", dan perlu bimbingan dalam " + joinDan(butir)
+	}
+	return predikat, deskripsi + "."
+}
+
+// topButir returns the most frequently observed journal items, first seen first on ties
+func topButir(entries []model.JurnalSikap) []string {
+	count := make(map[string]int)
+	var order []string
+	for _, e := range entries {
+		butir := strings.ToLower(strings.TrimSpace(e.Butir))
+		if count[butir] == 0 {
+			order = append(order, butir)
+		}
+		count[butir]++
+	}
+	sort.SliceStable(order, func(i, j int) bool { return count[order[i]] > count[order[j]] })
+	return order[:min(maxButirSikap, len(order))]
+}
+
+// joinDan joins items the Indonesian way: "a, b dan c"
+func joinDan(items []string) string {
+	if len(items) < 2 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " dan " + items[len(items)-1]
+}
+
+// SnapshotSikap freezes the sikap predicates into rapor rows, spiritual before sosial
+func SnapshotSikap(list []model.SikapSiswa) []model.RaporNilai {
+	var rows []model.RaporNilai
+	for _, aspek := range []string{model.SikapSpiritual, model.SikapSosial} {
+		for _, k := range list {
+			if k.Aspek != aspek {
+				continue
+			}
+			rows = append(rows, model.RaporNilai{
+				Kategori:   model.RaporKategoriSikap,
+				Jenis:      "Sikap " + strings.ToUpper(aspek[:1]) + aspek[1:],
+				Nilai:      model.SikapPredikatLabel[k.Predikat],
+				Keterangan: k.Deskripsi,
+				Detail:     &model.RaporNilaiDetail{Predikat: k.Predikat, Deskripsi: k.Deskripsi},
+			})
+		}
+	}
+	return rows
+}
+
+// CreateJurnalSikap records an attitude observation of a student
+func (s *Service) CreateJurnalSikap(ctx context.Context, input *model.JurnalSikapInput) (*model.JurnalSikap, error) {
+	if input.StudentID == "" || input.SemesterID == "" {
+		return nil, errors.New("student_id dan semester_id wajib diisi")
+	}
+	if input.Aspek != model.SikapSpiritual && input.Aspek != model.SikapSosial {
+		return nil, ErrAspekSikap
+	}
+	if input.Jenis == "" {
+		input.Jenis = model.JurnalSikapPositif
+	}
+	if input.Jenis != model.JurnalSikapPositif && input.Jenis != model.JurnalSikapNegatif {
+		return nil, ErrJenisSikap
+	}
+	if strings.TrimSpace(input.Butir) == "" {
+		return nil, errors.New("butir sikap wajib diisi")
+	}
+	now := time.Now()
+	tanggal := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if input.Tanggal != "" {
+		parsed, err := time.Parse("2006-01-02", input.Tanggal)
+		if err != nil {
+			return nil, ErrTanggalTidakSah
+		}
+		tanggal = parsed
+	}
+	siswa, err := s.db.GetSiswaWali(ctx, input.TenantID, input.StudentID)
+	if err != nil {
+		return nil, err
+	}
+	if siswa == nil {
+		return nil, ErrSiswaNotFound
+	}
+
+	jurnal := &model.JurnalSikap{
+		TenantID:     input.TenantID,
+		StudentID:    input.StudentID,
+		SemesterID:   input.SemesterID,
+		Aspek:        input.Aspek,
+		Tanggal:      tanggal,
+		Butir:        strings.TrimSpace(input.Butir),
+		Jenis:        input.Jenis,
+		Catatan:      strings.TrimSpace(input.Catatan),
+		ObserverID:   input.ObserverID,
+		ObserverRole: input.ObserverRole,
+		StudentName:  siswa.Nama,
+	}
+	if err := s.db.CreateJurnalSikap(ctx, jurnal); err != nil {
+		return nil, err
+	}
+	return jurnal, nil
+}
+
+// DeleteJurnalSikap removes an observation; only its author or the school admin may do so
+func (s *Service) DeleteJurnalSikap(ctx context.Context, tenantID, id, userID, role string) error {
+	jurnal, err := s.db.GetJurnalSikapByID(ctx, tenantID, id)
+	if err != nil {
+		return err
+	}
+	if jurnal == nil {
+		return ErrJurnalNotFound
+	}
+	if jurnal.ObserverID != userID && role != model.RoleAdmin && role != model.RoleAdminSekolah && role != model.RoleKepalaSekolah {
+		return ErrBukanPencatat
+	}
+	return s.db.DeleteJurnalSikap(ctx, tenantID, id)
+}
+
+// GetJurnalSikap lists the journal of a semester for one student, one kelas or the whole school
+func (s *Service) GetJurnalSikap(ctx context.Context, tenantID, semesterID, kelasID, studentID string) ([]model.JurnalSikap, error) {
+	studentIDs, _, err := s.sikapStudents(ctx, tenantID, kelasID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if kelasID != "" && len(studentIDs) == 0 {
+		return []model.JurnalSikap{}, nil
+	}
+	return s.db.GetJurnalSikap(ctx, tenantID, semesterID, studentIDs)
+}
+
+// GetSikapSiswa lists the compiled sikap of a kelas or student for a semester
+func (s *Service) GetSikapSiswa(ctx context.Context, tenantID, semesterID, kelasID, studentID string) ([]model.SikapSiswa, error) {
+	studentIDs, _, err := s.sikapStudents(ctx, tenantID, kelasID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if kelasID != "" && len(studentIDs) == 0 {
+		return []model.SikapSiswa{}, nil
+	}
+	return s.db.GetSikapSiswa(ctx, tenantID, semesterID, studentIDs)
+}
+
+// RekapSikap compiles the semester journal into spiritual and sosial predicates. Descriptions
+// edited by the wali kelas are kept unless Overwrite is set; locked rapor cannot be changed.
+func (s *Service) RekapSikap(ctx context.Context, input *model.SikapRekapInput) ([]model.SikapSiswa, error) {
+	if input.SemesterID == "" {
+		return nil, errors.New("semester_id wajib diisi")
+	}
+	if input.KelasID == "" && input.StudentID == "" {
+		return nil, ErrSikapTanpaSiswa
+	}
+	studentIDs, names, err := s.sikapStudents(ctx, input.TenantID, input.KelasID, input.StudentID)
+	if err != nil {
+		return nil, err
+	}
+	if len(studentIDs) == 0 {
+		return []model.SikapSiswa{}, nil
+	}
+	if err := s.ensureNilaiTerbuka(ctx, input.TenantID, input.SemesterID, studentIDs...); err != nil {
+		return nil, err
+	}
+
+	jurnal, err := s.db.GetJurnalSikap(ctx, input.TenantID, input.SemesterID, studentIDs)
+	if err != nil {
+		return nil, err
+	}
+	byStudent := make(map[string][]model.JurnalSikap)
+	for _, j := range jurnal {
+		byStudent[j.StudentID] = append(byStudent[j.StudentID], j)
+	}
+	existing, err := s.db.GetSikapSiswa(ctx, input.TenantID, input.SemesterID, studentIDs)
+	if err != nil {
+		return nil, err
+	}
+	edited := make(map[string]bool)
+	for _, k := range existing {
+		if k.Edited {
+			edited[k.StudentID+"/"+k.Aspek] = true
+		}
+	}
+
+	var list []model.SikapSiswa
+	for _, studentID := range studentIDs {
+		for _, aspek := range []string{model.SikapSpiritual, model.SikapSosial} {
+			if edited[studentID+"/"+aspek] && !input.Overwrite {
+				continue
+			}
+			predikat, deskripsi := AggregateSikap(byStudent[studentID], aspek, names[studentID])
+			list = append(list, model.SikapSiswa{
+				TenantID:   input.TenantID,
+				StudentID:  studentID,
+				SemesterID: input.SemesterID,
+				Aspek:      aspek,
+				Predikat:   predikat,
+				Deskripsi:  deskripsi,
+			})
+		}
+	}
+	if err := s.db.SaveSikapSiswa(ctx, list); err != nil {
+		return nil, err
+	}
+	return s.db.GetSikapSiswa(ctx, input.TenantID, input.SemesterID, studentIDs)
+}
+
+// UpdateSikapSiswa lets the wali kelas adjust a compiled predicate or reword its description
+func (s *Service) UpdateSikapSiswa(ctx context.Context, tenantID, id, predikat, deskripsi string) (*model.SikapSiswa, error) {
+	sikap, err := s.db.GetSikapSiswaByID(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if sikap == nil {
+		return nil, ErrSikapNotFound
+	}
+	if predikat != "" {
+		if _, ok := model.SikapPredikatLabel[predikat]; !ok {
+			return nil, ErrPredikatSikap
+		}
+		sikap.Predikat = predikat
+	}
+	if strings.TrimSpace(deskripsi) != "" {
+		sikap.Deskripsi = strings.TrimSpace(deskripsi)
+	}
+	if err := s.ensureNilaiTerbuka(ctx, tenantID, sikap.SemesterID, sikap.StudentID); err != nil {
+		return nil, err
+	}
+	sikap.Edited = true
+	if err := s.db.SaveSikapSiswa(ctx, []model.SikapSiswa{*sikap}); err != nil {
+		return nil, err
+	}
+	return sikap, nil
+}
+
+// sikapStudents resolves the students of a kelas or the single student, with their names.
+// Both empty means no filter.
+func (s *Service) sikapStudents(ctx context.Context, tenantID, kelasID, studentID string) ([]string, map[string]string, error) {
+	names := make(map[string]string)
+	if studentID != "" {
+		siswa, err := s.db.GetSiswaWali(ctx, tenantID, studentID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if siswa == nil {
+			return nil, nil, ErrSiswaNotFound
+		}
+		names[studentID] = siswa.Nama
+		return []string{studentID}, names, nil
+	}
+	if kelasID == "" {
+		return nil, names, nil
+	}
+	roster, err := s.db.GetSiswaByKelas(ctx, tenantID, kelasID)
+	if err != nil {
+		return nil, nil, err
+	}
+	ids := make([]string, len(roster))
+	for i, siswa := range roster {
+		ids[i] = siswa.SiswaID
+		names[siswa.SiswaID] = siswa.Nama
+	}
+	return ids, names, nil
+}
@@ -0,0 +1,65 @@
+package erapor_test
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain/erapor"
+	"prabogo/internal/model"
+)
+
+func TestAggregateSikap(t *testing.T) {
+	Convey("Test AggregateSikap", t, func() {
+		jurnal := func(aspek, jenis, butir string) model.JurnalSikap {
+			return model.JurnalSikap{Aspek: aspek, Jenis: jenis, Butir: butir}
+		}
+
+		Convey("Defaults to Baik without observations", func() {
+			predikat, deskripsi := erapor.AggregateSikap(nil, model.SikapSosial, "Budi")
+			So(predikat, ShouldEqual, model.SikapPredikatB)
+			So(deskripsi, ShouldEqual, "Ananda Budi menunjukkan sikap sosial yang baik.")
+		})
+
+		Convey("Three positive notes without negatives make Sangat Baik", func() {
+			entries := []model.JurnalSikap{
+				jurnal(model.SikapSosial, model.JurnalSikapPositif, "Disiplin"),
+				jurnal(model.SikapSosial, model.JurnalSikapPositif, "jujur"),
+				jurnal(model.SikapSosial, model.JurnalSikapPositif, "jujur"),
+				jurnal(model.SikapSpiritual, model.JurnalSikapNegatif, "ketaatan beribadah"),
+			}
+			predikat, deskripsi := erapor.AggregateSikap(entries, model.SikapSosial, "Budi")
+			So(predikat, ShouldEqual, model.SikapPredikatSB)
+			So(deskripsi, ShouldEqual, "Ananda Budi menunjukkan sikap sosial yang sangat baik, terutama dalam jujur dan disiplin.")
+		})
+
+		Convey("Negative notes outweighing positive ones lower the predicate", func() {
+			entries := []model.JurnalSikap{
+				jurnal(model.SikapSpiritual, model.JurnalSikapPositif, "berdoa"),
+				jurnal(model.SikapSpiritual, model.JurnalSikapNegatif, "ketaatan beribadah"),
+				jurnal(model.SikapSpiritual, model.JurnalSikapNegatif, "ketaatan beribadah"),
+			}
+			predikat, deskripsi := erapor.AggregateSikap(entries, model.SikapSpiritual, "Budi")
+			So(predikat, ShouldEqual, model.SikapPredikatC)
+			So(deskripsi, ShouldEqual, "Ananda Budi menunjukkan sikap spiritual yang cukup, terutama dalam berdoa, dan perlu bimbingan dalam ketaatan beribadah.")
+
+			for i := 0; i < 2; i++ {
+				entries = append(entries, jurnal(model.SikapSpiritual, model.JurnalSikapNegatif, "toleransi"))
+			}
+			predikat, _ = erapor.AggregateSikap(entries, model.SikapSpiritual, "Budi")
+			So(predikat, ShouldEqual, model.SikapPredikatK)
+		})
+	})
+
+	Convey("Test SnapshotSikap puts spiritual before sosial", t, func() {
+		rows := erapor.SnapshotSikap([]model.SikapSiswa{
+			{Aspek: model.SikapSosial, Predikat: model.SikapPredikatB, Deskripsi: "sosial"},
+			{Aspek: model.SikapSpiritual, Predikat: model.SikapPredikatSB, Deskripsi: "spiritual"},
+		})
+		So(rows, ShouldHaveLength, 2)
+		So(rows[0].Jenis, ShouldEqual, "Sikap Spiritual")
+		So(rows[0].Nilai, ShouldEqual, "Sangat Baik")
+		So(rows[1].Jenis, ShouldEqual, "Sikap Sosial")
+		So(rows[1].Kategori, ShouldEqual, model.RaporKategoriSikap)
+	})
+}
@@ -0,0 +1,140 @@
+package erapor
+
+import (
+	"sort"
+	"strings"
+
+	"prabogo/internal/model"
+)
+
+// kategoriOrder is the print order of rapor sections
+var kategoriOrder = map[string]int{
+	model.RaporKategoriSikap:           -1,
+	model.RaporKategoriAkademik:        0,
+	model.RaporKategoriDiniyah:         1,
+	model.RaporKategoriTahfidz:         2,
+	model.RaporKategoriEkstrakurikuler: 3,
+}
+
+// kategoriForSubject maps a subject type to the rapor section it is printed in
+func kategoriForSubject(subjectType string) string {
+	switch subjectType {
+	case model.SubjectTypePesantrenKitab:
+		return model.RaporKategoriDiniyah
+	case model.SubjectTypePesantrenTahfidz:
+		return model.RaporKategoriTahfidz
+	default:
+		return model.RaporKategoriAkademik
+	}
+}
+
+// SnapshotNilai freezes one student grade into a rapor nilai row. subject may be nil when the
+// subject was removed after grading; the grade is then kept as a plain akademik entry.
+func SnapshotNilai(grade model.StudentGrade, subject *model.Subject) model.RaporNilai {
+	config := model.GradingConfig{}
+	subjectType := ""
+	if subject != nil {
+		config = subject.GradingConfig
+		subjectType = subject.Type
+	}
+
+	deskripsi := joinDeskripsi(grade.DescriptionHigh, grade.DescriptionLow)
+	detail := &model.RaporNilaiDetail{
+		NilaiAngka: grade.ScoreNumeric,
+		Predikat:   grade.ScorePredicate,
+		Deskripsi:  deskripsi,
+	}
+	if config.UseKKM {
+		detail.KKM = config.KKMValue
+	}
+
+	// K13 prints pengetahuan and keterampilan separately, each with its own predicate
+	if subjectType == model.SubjectTypeFormalK13 {
+		if v, ok := componentScore(grade, config, "Pengetahuan"); ok {
+			detail.NilaiAngka = v
+			detail.Predikat = predicateFor(v, config)
+		}
+		if v, ok := componentScore(grade, config, "Keterampilan"); ok {
+			detail.NilaiKeterampilan = v
+			detail.PredikatKeterampilan = predicateFor(v, config)
+			detail.DeskripsiKeterampilan = predicateLabel(detail.PredikatKeterampilan, config)
+		}
+	}
+
+	return model.RaporNilai{
+		Kategori:   kategoriForSubject(subjectType),
+		Jenis:      grade.SubjectName,
+		Nilai:      grade.ScorePredicate,
+		Keterangan: deskripsi,
+		Detail:     detail,
+	}
+}
+
+// sortRaporNilai orders nilai by section then subject name and numbers them for printing
+func sortRaporNilai(list []model.RaporNilai) {
+	sort.SliceStable(list, func(i, j int) bool {
+		ki, kj := kategoriOrder[list[i].Kategori], kategoriOrder[list[j].Kategori]
+		if ki != kj {
+			return ki < kj
+		}
+		// Sikap keeps the K13 order (spiritual, sosial) it was snapshotted in
+		if list[i].Kategori == model.RaporKategoriSikap {
+			return false
+		}
+		return list[i].Jenis < list[j].Jenis
+	})
+	for i := range list {
+		list[i].Urutan = i + 1
+	}
+}
+
+// componentScore looks up a grading component by name in the grade's component scores
+func componentScore(grade model.StudentGrade, config model.GradingConfig, name string) (float64, bool) {
+	for i, c := range config.Components {
+		if strings.EqualFold(c.Name, name) && i < len(grade.ComponentScores) {
+			return grade.ComponentScores[i], true
+		}
+	}
+	return 0, false
+}
+
+// predicateFor converts a score with the subject's predicate rules, falling back to the K13 interval
+func predicateFor(score float64, config model.GradingConfig) string {
+	s := int(score)
+	for _, rule := range config.PredicateRules {
+		if s >= rule.MinScore && s <= rule.MaxScore {
+			return rule.Predicate
+		}
+	}
+	switch {
+	case s >= 90:
+		return "A"
+	case s >= 80:
+		return "B"
+	case s >= 70:
+		return "C"
+	}
+	return "D"
+}
+
+func predicateLabel(predicate string, config model.GradingConfig) string {
+	for _, rule := range config.PredicateRules {
+		if rule.Predicate == predicate {
+			return rule.Label
+		}
+	}
+	return ""
+}
+
+func joinDeskripsi(high, low string) string {
+	parts := make([]string, 0, 2)
+	for _, p := range []string{high, low} {
+		if p = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(p), ".")); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return strings.Join(parts, ". ") + "."
+}
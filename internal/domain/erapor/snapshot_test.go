@@ -0,0 +1,90 @@
+package erapor_test
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain/erapor"
+	"prabogo/internal/model"
+	pdf_utils "prabogo/utils/pdf"
+)
+
+func TestSnapshotNilai(t *testing.T) {
+	Convey("Test SnapshotNilai", t, func() {
+		Convey("K13 splits pengetahuan and keterampilan with KKM", func() {
+			subject := &model.Subject{Type: model.SubjectTypeFormalK13, GradingConfig: model.DefaultK13GradingConfig()}
+			grade := model.StudentGrade{
+				SubjectName:     "Matematika",
+				ScoreNumeric:    84,
+				ScorePredicate:  "B",
+				DescriptionHigh: "Menguasai persamaan linear.",
+				ComponentScores: []float64{92, 76},
+			}
+
+			n := erapor.SnapshotNilai(grade, subject)
+			So(n.Kategori, ShouldEqual, model.RaporKategoriAkademik)
+			So(n.Nilai, ShouldEqual, "B")
+			So(n.Detail.KKM, ShouldEqual, 75)
+			So(n.Detail.NilaiAngka, ShouldEqual, 92)
+			So(n.Detail.Predikat, ShouldEqual, "A")
+			So(n.Detail.NilaiKeterampilan, ShouldEqual, 76)
+			So(n.Detail.PredikatKeterampilan, ShouldEqual, "C")
+			So(n.Detail.Deskripsi, ShouldEqual, "Menguasai persamaan linear.")
+		})
+
+		Convey("Merdeka keeps nilai akhir and capaian kompetensi", func() {
+			subject := &model.Subject{Type: model.SubjectTypeFormalMerdeka, GradingConfig: model.DefaultMerdekaGradingConfig()}
+			grade := model.StudentGrade{
+				SubjectName:     "IPAS",
+				ScoreNumeric:    88,
+				ScorePredicate:  "B",
+				DescriptionHigh: "Menguasai siklus air",
+				DescriptionLow:  "Perlu bimbingan pada rantai makanan",
+			}
+
+			n := erapor.SnapshotNilai(grade, subject)
+			So(n.Detail.KKM, ShouldEqual, 0)
+			So(n.Detail.NilaiAngka, ShouldEqual, 88)
+			So(n.Detail.Deskripsi, ShouldEqual, "Menguasai siklus air. Perlu bimbingan pada rantai makanan.")
+		})
+
+		Convey("Pesantren subjects go to their own sections", func() {
+			kitab := erapor.SnapshotNilai(model.StudentGrade{SubjectName: "Fathul Qarib"}, &model.Subject{Type: model.SubjectTypePesantrenKitab})
+			tahfidz := erapor.SnapshotNilai(model.StudentGrade{SubjectName: "Juz 30"}, &model.Subject{Type: model.SubjectTypePesantrenTahfidz})
+			deleted := erapor.SnapshotNilai(model.StudentGrade{SubjectName: "Lama"}, nil)
+			So(kitab.Kategori, ShouldEqual, model.RaporKategoriDiniyah)
+			So(tahfidz.Kategori, ShouldEqual, model.RaporKategoriTahfidz)
+			So(deleted.Kategori, ShouldEqual, model.RaporKategoriAkademik)
+		})
+	})
+}
+
+func TestGenerateRaporPDF(t *testing.T) {
+	Convey("Test GenerateRaporPDF renders every kurikulum layout", t, func() {
+		nilai := []model.RaporNilai{
+			{Kategori: model.RaporKategoriAkademik, Jenis: "Matematika", Nilai: "A", Detail: &model.RaporNilaiDetail{KKM: 75, NilaiAngka: 90, Predikat: "A", NilaiKeterampilan: 85, PredikatKeterampilan: "B"}},
+			{Kategori: model.RaporKategoriDiniyah, Jenis: "Safinatun Najah", Nilai: "Mumtaz", Keterangan: "Lancar"},
+			{Kategori: model.RaporKategoriTahfidz, Jenis: "Juz 30", Nilai: "Jayyid"},
+			{Kategori: model.RaporKategoriEkstrakurikuler, Jenis: "Pramuka", Nilai: "A"},
+		}
+
+		for _, kurikulum := range []string{model.KurikulumK13, model.KurikulumMerdeka, model.KurikulumPesantren} {
+			rapor := &model.Rapor{
+				CatatanWaliKelas: "Pertahankan prestasimu.",
+				NilaiList:        nilai,
+				Header: &model.RaporHeader{
+					Kurikulum:   kurikulum,
+					NamaSekolah: "SMP Nusantara",
+					NamaSiswa:   "Ahmad",
+					WaliKelas:   "Bu Siti",
+					Kehadiran:   model.AttendanceData{Sakit: 1},
+				},
+			}
+			out, err := pdf_utils.GenerateRaporPDF(rapor, []byte("not an image"))
+			So(err, ShouldBeNil)
+			So(bytes.HasPrefix(out, []byte("%PDF")), ShouldBeTrue)
+		}
+	})
+}
@@ -0,0 +1,151 @@
+package erapor
+
+import (
+	"context"
+	"errors"
+
+	"prabogo/internal/model"
+	"prabogo/utils/semester"
+)
+
+var ErrLebarKelasTidakSah = errors.New("lebar interval histogram harus 5, 10, 20 atau 25")
+
+// FinishGradeStats rounds the statistics for display and derives the share below the KKM
+func FinishGradeStats(stats *model.GradeStats) {
+	stats.Mean = roundNilai(stats.Mean)
+	stats.Median = roundNilai(stats.Median)
+	stats.StdDev = roundNilai(stats.StdDev)
+	stats.Min = roundNilai(stats.Min)
+	stats.Max = roundNilai(stats.Max)
+	stats.BelowKKMPct = 0
+	if stats.WithKKM > 0 {
+		stats.BelowKKMPct = roundNilai(float64(stats.BelowKKM) / float64(stats.WithKKM) * 100)
+	}
+	if stats.Predicates == nil {
+		stats.Predicates = map[string]int{}
+	}
+}
+
+// HistogramBins lays the counts per lower bound out over 0-100, empty bins included
+func HistogramBins(counts map[int]int, width int) []model.HistogramBin {
+	bins := make([]model.HistogramBin, 0, 100/width)
+	for from := 0; from < 100; from += width {
+		bins = append(bins, model.HistogramBin{From: from, To: from + width, Count: counts[from]})
+	}
+	return bins
+}
+
+// GetGradeStats returns the statistics of a semester overall, per subject and per kelas. The
+// filter may narrow it to a subject, kelas or tingkat; the aggregation runs in the database.
+func (s *Service) GetGradeStats(ctx context.Context, tenantID, semesterID string, filter model.GradeStatsFilter) (*model.GradeStatsReport, error) {
+	filter.SemesterIDs = []string{semesterID}
+
+	report := &model.GradeStatsReport{SemesterID: semesterID, Semester: semester.Label(semesterID), Filter: filter}
+	overall, err := s.gradeStats(ctx, tenantID, "", filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(overall) > 0 {
+		report.Overall = overall[0]
+	} else {
+		FinishGradeStats(&report.Overall)
+	}
+	if report.Subjects, err = s.gradeStats(ctx, tenantID, model.GradeStatsBySubject, filter); err != nil {
+		return nil, err
+	}
+	if report.Kelas, err = s.gradeStats(ctx, tenantID, model.GradeStatsByKelas, filter); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// CompareKelas compares the kelas of one tingkat in a semester, optionally for one subject
+func (s *Service) CompareKelas(ctx context.Context, tenantID, semesterID, tingkat, subjectID string) ([]model.GradeStats, error) {
+	if tingkat == "" {
+		return nil, errors.New("tingkat wajib diisi")
+	}
+	return s.gradeStats(ctx, tenantID, model.GradeStatsByKelas, model.GradeStatsFilter{
+		SemesterIDs: []string{semesterID},
+		SubjectID:   subjectID,
+		Tingkat:     tingkat,
+	})
+}
+
+// CompareSemesters follows the statistics across semesters, all graded semesters when none are
+// given. The filter's subject, kelas and tingkat narrow the grades compared.
+func (s *Service) CompareSemesters(ctx context.Context, tenantID string, filter model.GradeStatsFilter) ([]model.GradeStats, error) {
+	stats, err := s.gradeStats(ctx, tenantID, model.GradeStatsBySemester, filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range stats {
+		stats[i].Label = semester.Label(stats[i].Key)
+	}
+	return stats, nil
+}
+
+// GetGradeHistogram returns the score distribution of a subject in a semester, optionally for
+// one kelas, with how each grading component fared
+func (s *Service) GetGradeHistogram(ctx context.Context, tenantID, subjectID, semesterID, kelasID string, binWidth int) (*model.GradeHistogram, error) {
+	if binWidth == 0 {
+		binWidth = 10
+	}
+	if binWidth != 5 && binWidth != 10 && binWidth != 20 && binWidth != 25 {
+		return nil, ErrLebarKelasTidakSah
+	}
+	subject, err := s.subjectForTenant(ctx, tenantID, subjectID)
+	if err != nil {
+		return nil, err
+	}
+	filter := model.GradeStatsFilter{SemesterIDs: []string{semesterID}, SubjectID: subject.ID, KelasID: kelasID}
+
+	histogram := &model.GradeHistogram{
+		SubjectID:   subject.ID,
+		SubjectName: subject.Name,
+		SemesterID:  semesterID,
+		KelasID:     kelasID,
+		BinWidth:    binWidth,
+		Components:  []model.ComponentStats{},
+	}
+	stats, err := s.gradeStats(ctx, tenantID, model.GradeStatsBySubject, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(stats) > 0 {
+		histogram.Stats = stats[0]
+	} else {
+		FinishGradeStats(&histogram.Stats)
+	}
+
+	counts, err := s.db.GetGradeHistogram(ctx, tenantID, filter, binWidth)
+	if err != nil {
+		return nil, err
+	}
+	histogram.Bins = HistogramBins(counts, binWidth)
+
+	components, err := s.db.GetComponentStats(ctx, tenantID, filter)
+	if err != nil {
+		return nil, err
+	}
+	for i, comp := range subject.GradingConfig.Components {
+		cs := components[i]
+		cs.Name, cs.Weight = comp.Name, comp.Weight
+		cs.Mean, cs.Min, cs.Max = roundNilai(cs.Mean), roundNilai(cs.Min), roundNilai(cs.Max)
+		histogram.Components = append(histogram.Components, cs)
+	}
+	return histogram, nil
+}
+
+func (s *Service) gradeStats(ctx context.Context, tenantID, groupBy string, filter model.GradeStatsFilter) ([]model.GradeStats, error) {
+	stats, err := s.db.GetGradeStatsGrouped(ctx, tenantID, groupBy, filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range stats {
+		FinishGradeStats(&stats[i])
+	}
+	if stats == nil {
+		stats = []model.GradeStats{}
+	}
+	return stats, nil
+}
@@ -0,0 +1,40 @@
+package erapor_test
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain/erapor"
+	"prabogo/internal/model"
+)
+
+func TestGradeStatistics(t *testing.T) {
+	Convey("Test FinishGradeStats", t, func() {
+		Convey("Rounds and derives the share below the KKM", func() {
+			stats := model.GradeStats{Count: 3, Mean: 78.3333333, StdDev: 4.714045, WithKKM: 3, BelowKKM: 1}
+			erapor.FinishGradeStats(&stats)
+			So(stats.Mean, ShouldEqual, 78.33)
+			So(stats.StdDev, ShouldEqual, 4.71)
+			So(stats.BelowKKMPct, ShouldEqual, 33.33)
+			So(stats.Predicates, ShouldNotBeNil)
+		})
+
+		Convey("Leaves the share at zero without a KKM", func() {
+			stats := model.GradeStats{Count: 2, BelowKKM: 0}
+			erapor.FinishGradeStats(&stats)
+			So(stats.BelowKKMPct, ShouldEqual, 0)
+		})
+	})
+
+	Convey("Test HistogramBins includes empty bins", t, func() {
+		bins := erapor.HistogramBins(map[int]int{60: 2, 80: 5}, 20)
+		So(bins, ShouldResemble, []model.HistogramBin{
+			{From: 0, To: 20},
+			{From: 20, To: 40},
+			{From: 40, To: 60},
+			{From: 60, To: 80, Count: 2},
+			{From: 80, To: 100, Count: 5},
+		})
+	})
+}
@@ -0,0 +1,326 @@
+package konseling
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/crypto"
+)
+
+// KonselingDomain interface
+type KonselingDomain interface {
+	// Kasus (BK & kepala sekolah only)
+	GetKasusList(ctx context.Context, tenantID, santriID string) ([]model.KonselingKasus, error)
+	GetKasusDetail(ctx context.Context, tenantID, id string) (*model.KonselingDetail, error)
+	CreateKasus(ctx context.Context, kasus *model.KonselingKasus) error
+	UpdateKasus(ctx context.Context, kasus *model.KonselingKasus) error
+
+	// Sesi & tindak lanjut
+	AddSesi(ctx context.Context, tenantID string, sesi *model.KonselingSesi) error
+	SetTindakLanjutSelesai(ctx context.Context, tenantID, kasusID, sesiID string, selesai bool) error
+
+	// Pelanggaran
+	LinkPelanggaran(ctx context.Context, tenantID, kasusID string, pelanggaranIDs []string) error
+
+	// Ringkasan untuk wali kelas (tanpa catatan rahasia)
+	GetRingkasanKelas(ctx context.Context, tenantID, kelasID, userID, role string) ([]model.KonselingRingkasan, error)
+}
+
+type konselingDomain struct {
+	databasePort outbound_port.DatabasePort
+	db           outbound_port.KonselingDatabasePort
+}
+
+func NewKonselingDomain(databasePort outbound_port.DatabasePort) KonselingDomain {
+	return &konselingDomain{databasePort: databasePort, db: databasePort.Konseling()}
+}
+
+var (
+	ErrKasusNotFound  = errors.New("kasus konseling tidak ditemukan")
+	ErrSantriNotFound = errors.New("santri tidak ditemukan")
+	ErrBukanWaliKelas = errors.New("ringkasan hanya dapat dilihat wali kelas dari kelas tersebut")
+)
+
+// ==========================================
+// KASUS
+// ==========================================
+
+// GetKasusList returns cases without notes; notes are only decrypted in the detail view
+func (d *konselingDomain) GetKasusList(ctx context.Context, tenantID, santriID string) ([]model.KonselingKasus, error) {
+	list, err := d.db.GetKasusList(ctx, tenantID, santriID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		list[i].Catatan = ""
+	}
+	return list, nil
+}
+
+func (d *konselingDomain) GetKasusDetail(ctx context.Context, tenantID, id string) (*model.KonselingDetail, error) {
+	kasus, err := d.db.GetKasusByID(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if kasus == nil {
+		return nil, ErrKasusNotFound
+	}
+
+	cipher, err := crypto.NewCipherFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if kasus.Catatan, err = cipher.Decrypt(kasus.Catatan); err != nil {
+		return nil, fmt.Errorf("gagal membuka catatan kasus: %w", err)
+	}
+
+	sesi, err := d.db.GetSesiByKasus(ctx, kasus.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sesi {
+		if sesi[i].Catatan, err = cipher.Decrypt(sesi[i].Catatan); err != nil {
+			return nil, fmt.Errorf("gagal membuka catatan sesi: %w", err)
+		}
+		// Tindak lanjut yang tersimpan sebelum dienkripsi tetap dibaca apa adanya
+		if crypto.IsEncrypted(sesi[i].TindakLanjut) {
+			if sesi[i].TindakLanjut, err = cipher.Decrypt(sesi[i].TindakLanjut); err != nil {
+				return nil, fmt.Errorf("gagal membuka tindak lanjut sesi: %w", err)
+			}
+		}
+	}
+
+	pelanggaran, err := d.db.GetPelanggaranByKasus(ctx, tenantID, kasus.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.KonselingDetail{
+		Kasus:       *kasus,
+		Sesi:        sesi,
+		Pelanggaran: pelanggaran,
+	}, nil
+}
+
+func (d *konselingDomain) CreateKasus(ctx context.Context, kasus *model.KonselingKasus) error {
+	if kasus.SantriID == "" || strings.TrimSpace(kasus.Judul) == "" {
+		return errors.New("santri_id dan judul wajib diisi")
+	}
+	if !validKategori(kasus.Kategori) {
+		return fmt.Errorf("kategori tidak valid: %s", kasus.Kategori)
+	}
+	if kasus.Status == "" {
+		kasus.Status = model.KonselingStatusTerbuka
+	}
+	setRujukan(kasus)
+	ok, err := d.db.SantriExists(ctx, kasus.TenantID, kasus.SantriID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrSantriNotFound
+	}
+	if err := d.cekPelanggaran(ctx, kasus.TenantID, kasus.SantriID, kasus.PelanggaranIDs); err != nil {
+		return err
+	}
+
+	plain := kasus.Catatan
+	if err := encryptCatatan(&kasus.Catatan); err != nil {
+		return err
+	}
+	_, err = d.databasePort.DoInTransaction(func(tx outbound_port.DatabasePort) (interface{}, error) {
+		if err := tx.Konseling().CreateKasus(ctx, kasus); err != nil {
+			return nil, err
+		}
+		return nil, tx.Konseling().LinkPelanggaran(ctx, kasus.ID, kasus.PelanggaranIDs)
+	})
+	kasus.Catatan = plain
+	return err
+}
+
+func (d *konselingDomain) UpdateKasus(ctx context.Context, kasus *model.KonselingKasus) error {
+	existing, err := d.db.GetKasusByID(ctx, kasus.TenantID, kasus.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return ErrKasusNotFound
+	}
+	if !validKategori(kasus.Kategori) {
+		return fmt.Errorf("kategori tidak valid: %s", kasus.Kategori)
+	}
+	switch kasus.Status {
+	case model.KonselingStatusTerbuka, model.KonselingStatusProses, model.KonselingStatusSelesai:
+	default:
+		return fmt.Errorf("status tidak valid: %s", kasus.Status)
+	}
+	if existing.RujukOrangTua && kasus.TanggalRujukan == nil {
+		kasus.TanggalRujukan = existing.TanggalRujukan
+	}
+	setRujukan(kasus)
+	if err := d.cekPelanggaran(ctx, kasus.TenantID, existing.SantriID, kasus.PelanggaranIDs); err != nil {
+		return err
+	}
+
+	// Catatan yang tidak dikirim mempertahankan ciphertext yang tersimpan
+	plain := kasus.Catatan
+	if plain == "" {
+		kasus.Catatan = existing.Catatan
+	} else if err := encryptCatatan(&kasus.Catatan); err != nil {
+		return err
+	}
+	_, err = d.databasePort.DoInTransaction(func(tx outbound_port.DatabasePort) (interface{}, error) {
+		if err := tx.Konseling().UpdateKasus(ctx, kasus); err != nil {
+			return nil, err
+		}
+		return nil, tx.Konseling().LinkPelanggaran(ctx, kasus.ID, kasus.PelanggaranIDs)
+	})
+	kasus.Catatan = plain
+	return err
+}
+
+// ==========================================
+// SESI
+// ==========================================
+
+func (d *konselingDomain) AddSesi(ctx context.Context, tenantID string, sesi *model.KonselingSesi) error {
+	kasus, err := d.db.GetKasusByID(ctx, tenantID, sesi.KasusID)
+	if err != nil {
+		return err
+	}
+	if kasus == nil {
+		return ErrKasusNotFound
+	}
+	if sesi.Tanggal.IsZero() {
+		sesi.Tanggal = time.Now()
+	}
+
+	plain, plainTindakLanjut := sesi.Catatan, sesi.TindakLanjut
+	if err := encryptCatatan(&sesi.Catatan); err != nil {
+		return err
+	}
+	if err := encryptCatatan(&sesi.TindakLanjut); err != nil {
+		return err
+	}
+	_, err = d.databasePort.DoInTransaction(func(tx outbound_port.DatabasePort) (interface{}, error) {
+		if err := tx.Konseling().CreateSesi(ctx, sesi); err != nil {
+			return nil, err
+		}
+		// Kasus yang baru dibuka otomatis berpindah ke status Proses
+		if kasus.Status == model.KonselingStatusTerbuka {
+			kasus.Status = model.KonselingStatusProses
+			return nil, tx.Konseling().UpdateKasus(ctx, kasus)
+		}
+		return nil, nil
+	})
+	sesi.Catatan, sesi.TindakLanjut = plain, plainTindakLanjut
+	return err
+}
+
+func (d *konselingDomain) SetTindakLanjutSelesai(ctx context.Context, tenantID, kasusID, sesiID string, selesai bool) error {
+	kasus, err := d.db.GetKasusByID(ctx, tenantID, kasusID)
+	if err != nil {
+		return err
+	}
+	if kasus == nil {
+		return ErrKasusNotFound
+	}
+	return d.db.SetTindakLanjutSelesai(ctx, kasusID, sesiID, selesai)
+}
+
+func (d *konselingDomain) LinkPelanggaran(ctx context.Context, tenantID, kasusID string, pelanggaranIDs []string) error {
+	kasus, err := d.db.GetKasusByID(ctx, tenantID, kasusID)
+	if err != nil {
+		return err
+	}
+	if kasus == nil {
+		return ErrKasusNotFound
+	}
+	if err := d.cekPelanggaran(ctx, tenantID, kasus.SantriID, pelanggaranIDs); err != nil {
+		return err
+	}
+	return d.db.LinkPelanggaran(ctx, kasusID, pelanggaranIDs)
+}
+
+// GetRingkasanKelas membatasi wali kelas pada kelas yang diampunya;
+// BK dan kepala sekolah dapat melihat semua kelas
+func (d *konselingDomain) GetRingkasanKelas(ctx context.Context, tenantID, kelasID, userID, role string) ([]model.KonselingRingkasan, error) {
+	if kelasID == "" {
+		return nil, errors.New("kelas_id wajib diisi")
+	}
+	if role == model.RoleWaliKelas {
+		wali, err := d.db.IsWaliKelas(ctx, tenantID, kelasID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !wali {
+			return nil, ErrBukanWaliKelas
+		}
+	}
+	return d.db.GetRingkasanByKelas(ctx, tenantID, kelasID)
+}
+
+// ==========================================
+// HELPERS
+// ==========================================
+
+// cekPelanggaran memastikan setiap pelanggaran yang ditautkan tercatat
+// untuk santri kasus di tenant yang sama
+func (d *konselingDomain) cekPelanggaran(ctx context.Context, tenantID, santriID string, pelanggaranIDs []string) error {
+	if len(pelanggaranIDs) == 0 {
+		return nil
+	}
+	found, err := d.db.GetPelanggaranIDsBySantri(ctx, tenantID, santriID, pelanggaranIDs)
+	if err != nil {
+		return err
+	}
+	sah := make(map[string]bool, len(found))
+	for _, id := range found {
+		sah[id] = true
+	}
+	for _, id := range pelanggaranIDs {
+		if !sah[id] {
+			return fmt.Errorf("pelanggaran %s bukan milik santri kasus ini", id)
+		}
+	}
+	return nil
+}
+
+func validKategori(kategori string) bool {
+	for _, k := range model.KonselingKategori {
+		if k == kategori {
+			return true
+		}
+	}
+	return false
+}
+
+// setRujukan stamps the referral date the first time a case is referred to parents
+func setRujukan(kasus *model.KonselingKasus) {
+	if !kasus.RujukOrangTua {
+		kasus.TanggalRujukan = nil
+		return
+	}
+	if kasus.TanggalRujukan == nil {
+		now := time.Now()
+		kasus.TanggalRujukan = &now
+	}
+}
+
+func encryptCatatan(catatan *string) error {
+	cipher, err := crypto.NewCipherFromEnv()
+	if err != nil {
+		return err
+	}
+	enc, err := cipher.Encrypt(*catatan)
+	if err != nil {
+		return err
+	}
+	*catatan = enc
+	return nil
+}
@@ -0,0 +1,210 @@
+package konseling_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain/konseling"
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	mock_outbound_port "prabogo/tests/mocks/port"
+	"prabogo/utils/crypto"
+)
+
+// fakeKonselingDB keeps cases in memory, scoped by tenant like the postgres adapter
+type fakeKonselingDB struct {
+	santri      map[string]string // santri id -> tenant id
+	pelanggaran map[string]string // pelanggaran id -> santri id
+	kasus       map[string]*model.KonselingKasus
+	sesi        []model.KonselingSesi
+	links       map[string][]string
+	wali        map[string]string // kelas id -> user id
+}
+
+func newFakeKonselingDB() *fakeKonselingDB {
+	return &fakeKonselingDB{
+		santri:      map[string]string{"s1": "t1", "s2": "t2"},
+		pelanggaran: map[string]string{"p1": "s1", "p2": "s2"},
+		kasus:       map[string]*model.KonselingKasus{},
+		links:       map[string][]string{},
+		wali:        map[string]string{"k1": "u-wali"},
+	}
+}
+
+func (f *fakeKonselingDB) CreateKasus(ctx context.Context, kasus *model.KonselingKasus) error {
+	kasus.ID = "kasus-1"
+	stored := *kasus
+	f.kasus[kasus.ID] = &stored
+	return nil
+}
+
+func (f *fakeKonselingDB) UpdateKasus(ctx context.Context, kasus *model.KonselingKasus) error {
+	stored := *kasus
+	f.kasus[kasus.ID] = &stored
+	return nil
+}
+
+func (f *fakeKonselingDB) GetKasusByID(ctx context.Context, tenantID, id string) (*model.KonselingKasus, error) {
+	kasus, ok := f.kasus[id]
+	if !ok || kasus.TenantID != tenantID {
+		return nil, nil
+	}
+	copied := *kasus
+	return &copied, nil
+}
+
+func (f *fakeKonselingDB) GetKasusList(ctx context.Context, tenantID, santriID string) ([]model.KonselingKasus, error) {
+	return nil, nil
+}
+
+func (f *fakeKonselingDB) SantriExists(ctx context.Context, tenantID, santriID string) (bool, error) {
+	return f.santri[santriID] == tenantID, nil
+}
+
+func (f *fakeKonselingDB) CreateSesi(ctx context.Context, sesi *model.KonselingSesi) error {
+	f.sesi = append(f.sesi, *sesi)
+	return nil
+}
+
+func (f *fakeKonselingDB) GetSesiByKasus(ctx context.Context, kasusID string) ([]model.KonselingSesi, error) {
+	return append([]model.KonselingSesi(nil), f.sesi...), nil
+}
+
+func (f *fakeKonselingDB) SetTindakLanjutSelesai(ctx context.Context, kasusID, sesiID string, selesai bool) error {
+	return nil
+}
+
+func (f *fakeKonselingDB) LinkPelanggaran(ctx context.Context, kasusID string, pelanggaranIDs []string) error {
+	f.links[kasusID] = append(f.links[kasusID], pelanggaranIDs...)
+	return nil
+}
+
+func (f *fakeKonselingDB) GetPelanggaranByKasus(ctx context.Context, tenantID, kasusID string) ([]model.PelanggaranSiswa, error) {
+	return nil, nil
+}
+
+func (f *fakeKonselingDB) GetPelanggaranIDsBySantri(ctx context.Context, tenantID, santriID string, ids []string) ([]string, error) {
+	var found []string
+	for _, id := range ids {
+		if f.pelanggaran[id] == santriID && f.santri[santriID] == tenantID {
+			found = append(found, id)
+		}
+	}
+	return found, nil
+}
+
+func (f *fakeKonselingDB) GetRingkasanByKelas(ctx context.Context, tenantID, kelasID string) ([]model.KonselingRingkasan, error) {
+	return []model.KonselingRingkasan{}, nil
+}
+
+func (f *fakeKonselingDB) IsWaliKelas(ctx context.Context, tenantID, kelasID, userID string) (bool, error) {
+	return f.wali[kelasID] == userID, nil
+}
+
+func TestKonseling(t *testing.T) {
+	t.Setenv("DATA_ENCRYPTION_KEY", "test-secret")
+
+	Convey("Test Konseling", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		ctx := context.Background()
+		db := newFakeKonselingDB()
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockDatabasePort.EXPECT().Konseling().Return(db).AnyTimes()
+		mockDatabasePort.EXPECT().DoInTransaction(gomock.Any()).DoAndReturn(
+			func(txFunc outbound_port.InTransaction) (interface{}, error) {
+				return txFunc(mockDatabasePort)
+			}).AnyTimes()
+		domain := konseling.NewKonselingDomain(mockDatabasePort)
+
+		kasus := &model.KonselingKasus{
+			TenantID: "t1",
+			SantriID: "s1",
+			Kategori: model.KonselingKategori[0],
+			Judul:    "Sering terlambat",
+			Catatan:  "catatan rahasia",
+		}
+
+		Convey("Stores catatan and tindak lanjut encrypted and decrypts them in the detail", func() {
+			So(domain.CreateKasus(ctx, kasus), ShouldBeNil)
+			So(kasus.Catatan, ShouldEqual, "catatan rahasia")
+			So(crypto.IsEncrypted(db.kasus[kasus.ID].Catatan), ShouldBeTrue)
+
+			sesi := &model.KonselingSesi{KasusID: kasus.ID, Catatan: "sesi pertama", TindakLanjut: "panggil orang tua"}
+			So(domain.AddSesi(ctx, "t1", sesi), ShouldBeNil)
+			So(sesi.TindakLanjut, ShouldEqual, "panggil orang tua")
+			So(crypto.IsEncrypted(db.sesi[0].Catatan), ShouldBeTrue)
+			So(crypto.IsEncrypted(db.sesi[0].TindakLanjut), ShouldBeTrue)
+			So(db.kasus[kasus.ID].Status, ShouldEqual, model.KonselingStatusProses)
+
+			db.sesi = append(db.sesi, model.KonselingSesi{KasusID: kasus.ID, TindakLanjut: "catatan lama"})
+			detail, err := domain.GetKasusDetail(ctx, "t1", kasus.ID)
+			So(err, ShouldBeNil)
+			So(detail.Kasus.Catatan, ShouldEqual, "catatan rahasia")
+			So(detail.Sesi[0].Catatan, ShouldEqual, "sesi pertama")
+			So(detail.Sesi[0].TindakLanjut, ShouldEqual, "panggil orang tua")
+			So(detail.Sesi[1].TindakLanjut, ShouldEqual, "catatan lama")
+		})
+
+		Convey("Keeps the stored catatan when an update omits it", func() {
+			So(domain.CreateKasus(ctx, kasus), ShouldBeNil)
+			stored := db.kasus[kasus.ID].Catatan
+
+			update := *kasus
+			update.Catatan = ""
+			update.Status = model.KonselingStatusProses
+			So(domain.UpdateKasus(ctx, &update), ShouldBeNil)
+			So(db.kasus[kasus.ID].Catatan, ShouldEqual, stored)
+		})
+
+		Convey("Scopes cases, santri and pelanggaran to the tenant", func() {
+			other := *kasus
+			other.SantriID = "s2"
+			So(domain.CreateKasus(ctx, &other), ShouldEqual, konseling.ErrSantriNotFound)
+
+			kasus.PelanggaranIDs = []string{"p2"}
+			So(domain.CreateKasus(ctx, kasus), ShouldNotBeNil)
+			So(db.kasus, ShouldBeEmpty)
+
+			kasus.PelanggaranIDs = []string{"p1"}
+			So(domain.CreateKasus(ctx, kasus), ShouldBeNil)
+			So(db.links[kasus.ID], ShouldResemble, []string{"p1"})
+
+			_, err := domain.GetKasusDetail(ctx, "t2", kasus.ID)
+			So(err, ShouldEqual, konseling.ErrKasusNotFound)
+			So(domain.LinkPelanggaran(ctx, "t2", kasus.ID, []string{"p2"}), ShouldEqual, konseling.ErrKasusNotFound)
+		})
+
+		Convey("Limits the class summary to its wali kelas", func() {
+			_, err := domain.GetRingkasanKelas(ctx, "t1", "k1", "u-lain", model.RoleWaliKelas)
+			So(err, ShouldEqual, konseling.ErrBukanWaliKelas)
+
+			_, err = domain.GetRingkasanKelas(ctx, "t1", "k1", "u-wali", model.RoleWaliKelas)
+			So(err, ShouldBeNil)
+
+			_, err = domain.GetRingkasanKelas(ctx, "t1", "k1", "u-bk", model.RoleBK)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Stamps the referral date once and clears it when the referral is withdrawn", func() {
+			kasus.RujukOrangTua = true
+			So(domain.CreateKasus(ctx, kasus), ShouldBeNil)
+			So(kasus.TanggalRujukan, ShouldNotBeNil)
+			dirujuk := *kasus.TanggalRujukan
+
+			update := *kasus
+			update.TanggalRujukan = nil
+			update.Status = model.KonselingStatusProses
+			So(domain.UpdateKasus(ctx, &update), ShouldBeNil)
+			So(*update.TanggalRujukan, ShouldEqual, dirujuk)
+
+			update.RujukOrangTua = false
+			So(domain.UpdateKasus(ctx, &update), ShouldBeNil)
+			So(update.TanggalRujukan, ShouldBeNil)
+		})
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/midtrans/midtrans-go"
 	"github.com/midtrans/midtrans-go/snap"
@@ -22,6 +23,8 @@ type PaymentDomain interface {
 	CreateSPPSnapTransaction(ctx context.Context, sppID, tenantID string, amount int64, studentName, parentEmail string) (*model.Payment, *model.SnapTransactionResponse, error)
 	HandleWebhook(ctx context.Context, notification *model.MidtransNotification) error
 	HandleSPPWebhook(ctx context.Context, notification *model.MidtransNotification) error
+	CreatePPDBSnapTransaction(ctx context.Context, tenantID, pendaftarID, noPendaftaran string, amount int64, applicantName, parentEmail string) (*model.Payment, *model.SnapTransactionResponse, error)
+	HandlePPDBWebhook(ctx context.Context, notification *model.MidtransNotification) error
 	GetPaymentByOrderID(ctx context.Context, orderID string) (*model.Payment, error)
 }
 
@@ -186,6 +189,11 @@ func (d *paymentDomain) HandleSPPWebhook(ctx context.Context, notification *mode
 
 	// Extract SPP ID from order ID (format: SPP-{sppID}-{timestamp})
 	// We'll update payment record and then the SPP status
+	return d.updateFeePaymentStatus(notification, "SPP")
+}
+
+// updateFeePaymentStatus applies a verified Midtrans notification to a school fee payment (SPP, PPDB)
+func (d *paymentDomain) updateFeePaymentStatus(notification *model.MidtransNotification, label string) error {
 	switch notification.TransactionStatus {
 	case "capture", "settlement":
 		if notification.FraudStatus == "accept" || notification.FraudStatus == "" {
@@ -196,11 +204,11 @@ func (d *paymentDomain) HandleSPPWebhook(ctx context.Context, notification *mode
 				notification.TransactionID,
 			)
 			if err != nil {
-				return stacktrace.Propagate(err, "failed to mark SPP payment as paid")
+				return stacktrace.Propagate(err, "failed to mark %s payment as paid", label)
 			}
 
-			// Note: SPP status update should be done via SPP domain
-			// The caller should handle updating SPP transaction status after this returns
+			// Note: SPP/PPDB status update should be done via their own domain
+			// The caller should handle updating the fee status after this returns
 			return nil
 		}
 	case "pending":
@@ -229,6 +237,72 @@ func (d *paymentDomain) HandleSPPWebhook(ctx context.Context, notification *mode
 	return nil
 }
 
+// CreatePPDBSnapTransaction creates Midtrans Snap for a PPDB registration fee
+func (d *paymentDomain) CreatePPDBSnapTransaction(ctx context.Context, tenantID, pendaftarID, noPendaftaran string, amount int64, applicantName, parentEmail string) (*model.Payment, *model.SnapTransactionResponse, error) {
+	// PPDB order ID format: PPDB-{pendaftarID}-{timestamp}; the applicant stores it for lookup on webhook
+	orderID := "PPDB-" + pendaftarID + "-" + model.GenerateTimestamp()
+
+	snapReq := &snap.Request{
+		TransactionDetails: midtrans.TransactionDetails{
+			OrderID:  orderID,
+			GrossAmt: amount,
+		},
+		CustomerDetail: &midtrans.CustomerDetails{
+			FName: applicantName,
+			Email: parentEmail,
+		},
+		Items: &[]midtrans.ItemDetails{
+			{
+				ID:    noPendaftaran,
+				Name:  "Biaya Pendaftaran PPDB - " + noPendaftaran,
+				Price: amount,
+				Qty:   1,
+			},
+		},
+	}
+
+	snapResp, midtransErr := d.snapClient.CreateTransaction(snapReq)
+	if midtransErr != nil {
+		return nil, nil, stacktrace.Propagate(midtransErr, "failed to create PPDB snap transaction")
+	}
+
+	payment := &model.Payment{
+		TenantID:  tenantID,
+		OrderID:   orderID,
+		Amount:    amount,
+		Status:    model.PaymentStatusPending,
+		SnapToken: snapResp.Token,
+	}
+
+	err := d.databasePort.Payment().Create(payment)
+	if err != nil {
+		return nil, nil, stacktrace.Propagate(err, "failed to save PPDB payment")
+	}
+
+	return payment, &model.SnapTransactionResponse{
+		Token:       snapResp.Token,
+		RedirectURL: snapResp.RedirectURL,
+	}, nil
+}
+
+// HandlePPDBWebhook handles Midtrans callback for PPDB registration fees
+func (d *paymentDomain) HandlePPDBWebhook(ctx context.Context, notification *model.MidtransNotification) error {
+	serverKey := os.Getenv("MIDTRANS_SERVER_KEY")
+	signatureInput := notification.OrderID + notification.StatusCode + notification.GrossAmount + serverKey
+	hash := sha512.Sum512([]byte(signatureInput))
+	expectedSignature := hex.EncodeToString(hash[:])
+
+	if notification.SignatureKey != expectedSignature {
+		return stacktrace.NewError("invalid signature for PPDB payment")
+	}
+
+	if !strings.HasPrefix(notification.OrderID, "PPDB-") {
+		return stacktrace.NewError("not a PPDB payment order")
+	}
+
+	return d.updateFeePaymentStatus(notification, "PPDB")
+}
+
 func (d *paymentDomain) HandleWebhook(ctx context.Context, notification *model.MidtransNotification) error {
 	// Verify signature
 	serverKey := os.Getenv("MIDTRANS_SERVER_KEY")
@@ -0,0 +1,462 @@
+package perpustakaan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/log"
+
+	"github.com/google/uuid"
+)
+
+// PerpustakaanDomain interface
+type PerpustakaanDomain interface {
+	// Katalog
+	GetBukuList(ctx context.Context, tenantID, search string) ([]model.Buku, error)
+	GetBukuDetail(ctx context.Context, tenantID, id string) (*model.Buku, []model.BukuEksemplar, error)
+	CreateBuku(ctx context.Context, buku *model.Buku) error
+	UpdateBuku(ctx context.Context, buku *model.Buku) error
+	DeleteBuku(ctx context.Context, tenantID, id string) error
+	AddEksemplar(ctx context.Context, eksemplar *model.BukuEksemplar) error
+
+	// Anggota
+	SearchAnggota(ctx context.Context, tenantID, search string) ([]model.PerpustakaanAnggota, error)
+
+	// Sirkulasi
+	GetPeminjamanList(ctx context.Context, filter model.PeminjamanFilter) ([]model.Peminjaman, error)
+	Pinjam(ctx context.Context, input *model.PeminjamanInput) (*model.Peminjaman, error)
+	Kembalikan(ctx context.Context, tenantID, peminjamanID string, hilang bool) (*model.Peminjaman, error)
+	Perpanjang(ctx context.Context, tenantID, peminjamanID string) (*model.Peminjaman, error)
+	BayarDenda(ctx context.Context, tenantID, peminjamanID string) error
+
+	// Laporan
+	GetOverdue(ctx context.Context, tenantID string) ([]model.Peminjaman, error)
+	GetBukuPopuler(ctx context.Context, tenantID string, from, to time.Time, limit int) ([]model.BukuPopuler, error)
+	SendOverdueNotices(ctx context.Context, tenantID string) (int, error)
+
+	// Config
+	GetConfig(ctx context.Context, tenantID string) (*model.PerpustakaanConfig, error)
+	SaveConfig(ctx context.Context, config *model.PerpustakaanConfig) error
+}
+
+type perpustakaanDomain struct {
+	databasePort outbound_port.DatabasePort
+	db           outbound_port.PerpustakaanDatabasePort
+	messagePort  outbound_port.MessagePort
+}
+
+func NewPerpustakaanDomain(databasePort outbound_port.DatabasePort, messagePort outbound_port.MessagePort) PerpustakaanDomain {
+	return &perpustakaanDomain{databasePort: databasePort, db: databasePort.Perpustakaan(), messagePort: messagePort}
+}
+
+var (
+	ErrBukuNotFound       = errors.New("buku tidak ditemukan")
+	ErrEksemplarNotFound  = errors.New("eksemplar dengan barcode tersebut tidak ditemukan")
+	ErrPeminjamanNotFound = errors.New("data peminjaman tidak ditemukan")
+	ErrPeminjamanDitutup  = errors.New("peminjaman sudah ditutup")
+)
+
+// ==========================================
+// KATALOG
+// ==========================================
+
+func (d *perpustakaanDomain) GetBukuList(ctx context.Context, tenantID, search string) ([]model.Buku, error) {
+	return d.db.GetBukuList(ctx, tenantID, strings.TrimSpace(search))
+}
+
+func (d *perpustakaanDomain) GetBukuDetail(ctx context.Context, tenantID, id string) (*model.Buku, []model.BukuEksemplar, error) {
+	buku, err := d.db.GetBukuByID(ctx, tenantID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if buku == nil {
+		return nil, nil, ErrBukuNotFound
+	}
+	eksemplar, err := d.db.GetEksemplarByBuku(ctx, tenantID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return buku, eksemplar, nil
+}
+
+func (d *perpustakaanDomain) CreateBuku(ctx context.Context, buku *model.Buku) error {
+	if strings.TrimSpace(buku.Judul) == "" {
+		return errors.New("judul buku wajib diisi")
+	}
+	return d.db.CreateBuku(ctx, buku)
+}
+
+func (d *perpustakaanDomain) UpdateBuku(ctx context.Context, buku *model.Buku) error {
+	existing, err := d.db.GetBukuByID(ctx, buku.TenantID, buku.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return ErrBukuNotFound
+	}
+	return d.db.UpdateBuku(ctx, buku)
+}
+
+func (d *perpustakaanDomain) DeleteBuku(ctx context.Context, tenantID, id string) error {
+	return d.db.DeleteBuku(ctx, tenantID, id)
+}
+
+// AddEksemplar registers a physical copy; a barcode is generated when none is given
+func (d *perpustakaanDomain) AddEksemplar(ctx context.Context, eksemplar *model.BukuEksemplar) error {
+	buku, err := d.db.GetBukuByID(ctx, eksemplar.TenantID, eksemplar.BukuID)
+	if err != nil {
+		return err
+	}
+	if buku == nil {
+		return ErrBukuNotFound
+	}
+
+	eksemplar.Barcode = strings.TrimSpace(eksemplar.Barcode)
+	if eksemplar.Barcode == "" {
+		eksemplar.Barcode = "PUS-" + strings.ToUpper(strings.ReplaceAll(uuid.New().String(), "-", "")[:10])
+	} else if existing, err := d.db.GetEksemplarByBarcode(ctx, eksemplar.TenantID, eksemplar.Barcode); err != nil {
+		return err
+	} else if existing != nil {
+		return fmt.Errorf("barcode %s sudah digunakan", eksemplar.Barcode)
+	}
+	if eksemplar.Kondisi == "" {
+		eksemplar.Kondisi = "Baik"
+	}
+	eksemplar.Status = model.EksemplarTersedia
+	eksemplar.BukuJudul = buku.Judul
+
+	return d.db.CreateEksemplar(ctx, eksemplar)
+}
+
+func (d *perpustakaanDomain) SearchAnggota(ctx context.Context, tenantID, search string) ([]model.PerpustakaanAnggota, error) {
+	return d.db.SearchAnggota(ctx, tenantID, strings.TrimSpace(search))
+}
+
+// ==========================================
+// SIRKULASI
+// ==========================================
+
+func (d *perpustakaanDomain) GetPeminjamanList(ctx context.Context, filter model.PeminjamanFilter) ([]model.Peminjaman, error) {
+	list, err := d.db.GetPeminjamanList(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	config, err := d.GetConfig(ctx, filter.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for i := range list {
+		applyDendaBerjalan(&list[i], config, now)
+	}
+	return list, nil
+}
+
+func (d *perpustakaanDomain) Pinjam(ctx context.Context, input *model.PeminjamanInput) (*model.Peminjaman, error) {
+	config, err := d.GetConfig(ctx, input.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The copy and the member are locked for the rest of the transaction, so a
+	// concurrent loan waits and then sees the updated status and count
+	out, err := d.databasePort.DoInTransaction(func(tx outbound_port.DatabasePort) (interface{}, error) {
+		db := tx.Perpustakaan()
+		eksemplar, err := db.GetEksemplarByBarcode(ctx, input.TenantID, strings.TrimSpace(input.Barcode))
+		if err != nil {
+			return nil, err
+		}
+		if eksemplar == nil {
+			return nil, ErrEksemplarNotFound
+		}
+		if eksemplar.Status != model.EksemplarTersedia {
+			return nil, fmt.Errorf("eksemplar sedang %s", strings.ToLower(eksemplar.Status))
+		}
+
+		anggota, err := db.GetAnggota(ctx, input.TenantID, input.AnggotaTipe, input.AnggotaID)
+		if err != nil {
+			return nil, err
+		}
+		if anggota == nil {
+			return nil, errors.New("anggota tidak ditemukan")
+		}
+		active, err := db.CountActivePeminjaman(ctx, input.TenantID, anggota.Tipe, anggota.ID)
+		if err != nil {
+			return nil, err
+		}
+		if active >= config.MaksPinjam {
+			return nil, fmt.Errorf("anggota sudah meminjam %d buku (maksimal %d)", active, config.MaksPinjam)
+		}
+
+		today := truncateDate(time.Now())
+		p := &model.Peminjaman{
+			TenantID:      input.TenantID,
+			EksemplarID:   eksemplar.ID,
+			Barcode:       eksemplar.Barcode,
+			BukuJudul:     eksemplar.BukuJudul,
+			AnggotaTipe:   anggota.Tipe,
+			AnggotaID:     anggota.ID,
+			AnggotaNama:   anggota.Nama,
+			AnggotaNoHP:   anggota.NoHP,
+			TanggalPinjam: today,
+			JatuhTempo:    today.AddDate(0, 0, config.LamaPinjamHari),
+			Status:        model.PeminjamanDipinjam,
+		}
+		if err := db.CreatePeminjaman(ctx, p); err != nil {
+			return nil, err
+		}
+		if err := db.UpdateEksemplarStatus(ctx, eksemplar.ID, model.EksemplarDipinjam); err != nil {
+			return nil, err
+		}
+		return p, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*model.Peminjaman), nil
+}
+
+// Kembalikan closes a loan and fixes the fine; a lost book keeps the copy out of circulation
+func (d *perpustakaanDomain) Kembalikan(ctx context.Context, tenantID, peminjamanID string, hilang bool) (*model.Peminjaman, error) {
+	config, err := d.GetConfig(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := d.databasePort.DoInTransaction(func(tx outbound_port.DatabasePort) (interface{}, error) {
+		db := tx.Perpustakaan()
+		p, err := getActive(ctx, db, tenantID, peminjamanID)
+		if err != nil {
+			return nil, err
+		}
+
+		now := truncateDate(time.Now())
+		p.HariTerlambat, p.Denda = HitungDenda(p.JatuhTempo, now, config.DendaPerHari)
+		p.TanggalKembali = &now
+		p.DendaLunas = p.Denda == 0
+
+		eksemplarStatus := model.EksemplarTersedia
+		p.Status = model.PeminjamanKembali
+		if hilang {
+			eksemplarStatus = model.EksemplarHilang
+			p.Status = model.PeminjamanHilang
+		}
+
+		ok, err := db.UpdatePeminjaman(ctx, p, model.PeminjamanDipinjam)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, ErrPeminjamanDitutup
+		}
+		if err := db.UpdateEksemplarStatus(ctx, p.EksemplarID, eksemplarStatus); err != nil {
+			return nil, err
+		}
+		return p, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*model.Peminjaman), nil
+}
+
+// Perpanjang extends the due date; overdue loans must be returned first
+func (d *perpustakaanDomain) Perpanjang(ctx context.Context, tenantID, peminjamanID string) (*model.Peminjaman, error) {
+	config, err := d.GetConfig(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := d.databasePort.DoInTransaction(func(tx outbound_port.DatabasePort) (interface{}, error) {
+		db := tx.Perpustakaan()
+		p, err := getActive(ctx, db, tenantID, peminjamanID)
+		if err != nil {
+			return nil, err
+		}
+		if p.JumlahPerpanjang >= config.MaksPerpanjang {
+			return nil, fmt.Errorf("batas perpanjangan (%d kali) sudah tercapai", config.MaksPerpanjang)
+		}
+		if hari, _ := HitungDenda(p.JatuhTempo, time.Now(), config.DendaPerHari); hari > 0 {
+			return nil, fmt.Errorf("peminjaman terlambat %d hari, tidak dapat diperpanjang", hari)
+		}
+
+		p.JatuhTempo = truncateDate(p.JatuhTempo).AddDate(0, 0, config.LamaPinjamHari)
+		p.JumlahPerpanjang++
+		ok, err := db.UpdatePeminjaman(ctx, p, model.PeminjamanDipinjam)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, ErrPeminjamanDitutup
+		}
+		return p, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*model.Peminjaman), nil
+}
+
+func (d *perpustakaanDomain) BayarDenda(ctx context.Context, tenantID, peminjamanID string) error {
+	_, err := d.databasePort.DoInTransaction(func(tx outbound_port.DatabasePort) (interface{}, error) {
+		db := tx.Perpustakaan()
+		p, err := db.GetPeminjamanByID(ctx, tenantID, peminjamanID)
+		if err != nil {
+			return nil, err
+		}
+		if p == nil {
+			return nil, ErrPeminjamanNotFound
+		}
+		if p.Status == model.PeminjamanDipinjam {
+			return nil, errors.New("denda dihitung saat buku dikembalikan")
+		}
+		ok, err := db.LunasiDenda(ctx, tenantID, p.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, errors.New("denda sudah lunas")
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// ==========================================
+// LAPORAN
+// ==========================================
+
+func (d *perpustakaanDomain) GetOverdue(ctx context.Context, tenantID string) ([]model.Peminjaman, error) {
+	now := time.Now()
+	list, err := d.db.GetOverduePeminjaman(ctx, tenantID, now)
+	if err != nil {
+		return nil, err
+	}
+	config, err := d.GetConfig(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		applyDendaBerjalan(&list[i], config, now)
+	}
+	return list, nil
+}
+
+func (d *perpustakaanDomain) GetBukuPopuler(ctx context.Context, tenantID string, from, to time.Time, limit int) ([]model.BukuPopuler, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	return d.db.GetBukuPopuler(ctx, tenantID, from, to, limit)
+}
+
+// SendOverdueNotices sends a WhatsApp reminder for each overdue loan.
+// An empty tenantID processes every tenant (used by the scheduler).
+func (d *perpustakaanDomain) SendOverdueNotices(ctx context.Context, tenantID string) (int, error) {
+	if d.messagePort == nil || d.messagePort.WhatsApp() == nil {
+		return 0, errors.New("layanan WhatsApp tidak tersedia")
+	}
+
+	now := time.Now()
+	list, err := d.db.GetOverduePeminjaman(ctx, tenantID, now)
+	if err != nil {
+		return 0, err
+	}
+
+	configs := make(map[string]*model.PerpustakaanConfig)
+	sent := 0
+	for i := range list {
+		p := &list[i]
+		if p.AnggotaNoHP == "" {
+			continue
+		}
+		config, ok := configs[p.TenantID]
+		if !ok {
+			if config, err = d.GetConfig(ctx, p.TenantID); err != nil {
+				return sent, err
+			}
+			configs[p.TenantID] = config
+		}
+		applyDendaBerjalan(p, config, now)
+
+		message := fmt.Sprintf(
+			"Assalamu'alaikum, %s.\n\nBuku perpustakaan \"%s\" (%s) telah melewati jatuh tempo %s (%d hari).\nDenda sementara: Rp %d.\n\nMohon segera dikembalikan ke perpustakaan. Terima kasih.",
+			p.AnggotaNama, p.BukuJudul, p.Barcode, p.JatuhTempo.Format("02-01-2006"), p.HariTerlambat, p.Denda,
+		)
+		if err := d.messagePort.WhatsApp().Send(p.AnggotaNoHP, message); err != nil {
+			log.WithContext(ctx).WithError(err).Errorf("Failed to send overdue notice for loan %s", p.ID)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// ==========================================
+// CONFIG
+// ==========================================
+
+func (d *perpustakaanDomain) GetConfig(ctx context.Context, tenantID string) (*model.PerpustakaanConfig, error) {
+	config, err := d.db.GetConfig(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		def := model.DefaultPerpustakaanConfig(tenantID)
+		return &def, nil
+	}
+	return config, nil
+}
+
+func (d *perpustakaanDomain) SaveConfig(ctx context.Context, config *model.PerpustakaanConfig) error {
+	if config.LamaPinjamHari <= 0 || config.MaksPinjam <= 0 || config.MaksPerpanjang < 0 || config.DendaPerHari < 0 {
+		return errors.New("konfigurasi perpustakaan tidak valid")
+	}
+	return d.db.SaveConfig(ctx, config)
+}
+
+// ==========================================
+// HELPERS
+// ==========================================
+
+func getActive(ctx context.Context, db outbound_port.PerpustakaanDatabasePort, tenantID, id string) (*model.Peminjaman, error) {
+	p, err := db.GetPeminjamanByID(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, ErrPeminjamanNotFound
+	}
+	if p.Status != model.PeminjamanDipinjam {
+		return nil, ErrPeminjamanDitutup
+	}
+	return p, nil
+}
+
+// HitungDenda returns the number of late days and the fine, counted per calendar day
+func HitungDenda(jatuhTempo, tanggalKembali time.Time, dendaPerHari int64) (int, int64) {
+	hari := int(truncateDate(tanggalKembali).Sub(truncateDate(jatuhTempo)).Hours() / 24)
+	if hari <= 0 {
+		return 0, 0
+	}
+	return hari, int64(hari) * dendaPerHari
+}
+
+// applyDendaBerjalan fills the running fine for loans that are still out
+func applyDendaBerjalan(p *model.Peminjaman, config *model.PerpustakaanConfig, now time.Time) {
+	if p.Status != model.PeminjamanDipinjam {
+		if p.TanggalKembali != nil {
+			p.HariTerlambat, _ = HitungDenda(p.JatuhTempo, *p.TanggalKembali, 0)
+		}
+		return
+	}
+	p.HariTerlambat, p.Denda = HitungDenda(p.JatuhTempo, now, config.DendaPerHari)
+}
+
+func truncateDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,34 @@
+package perpustakaan_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain/perpustakaan"
+)
+
+func TestHitungDenda(t *testing.T) {
+	Convey("Test HitungDenda", t, func() {
+		jatuhTempo := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+
+		Convey("Returned on time", func() {
+			hari, denda := perpustakaan.HitungDenda(jatuhTempo, time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC), 500)
+			So(hari, ShouldEqual, 0)
+			So(denda, ShouldEqual, 0)
+		})
+
+		Convey("Returned early", func() {
+			hari, denda := perpustakaan.HitungDenda(jatuhTempo, time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC), 500)
+			So(hari, ShouldEqual, 0)
+			So(denda, ShouldEqual, 0)
+		})
+
+		Convey("Late counts calendar days, not hours", func() {
+			hari, denda := perpustakaan.HitungDenda(jatuhTempo, time.Date(2026, 3, 13, 1, 0, 0, 0, time.UTC), 500)
+			So(hari, ShouldEqual, 3)
+			So(denda, ShouldEqual, 1500)
+		})
+	})
+}
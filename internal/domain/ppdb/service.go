@@ -0,0 +1,483 @@
+package ppdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"prabogo/internal/domain/payment"
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/log"
+)
+
+// PPDBDomain interface
+type PPDBDomain interface {
+	// Public registration (tenant subdomain)
+	GetPublicInfo(ctx context.Context, tenant *model.Tenant) (*model.PPDBInfo, error)
+	Daftar(ctx context.Context, p *model.PPDBPendaftar) (*model.PPDBRegistrasiResult, error)
+	CekStatus(ctx context.Context, tenantID, noPendaftaran, noHPWali string) (*model.PPDBPendaftar, error)
+	BuatPembayaran(ctx context.Context, tenantID, noPendaftaran, noHPWali string) (*model.SnapTransactionResponse, error)
+	ConfirmPembayaran(ctx context.Context, orderID string) error
+
+	// Gelombang
+	GetGelombangList(ctx context.Context, tenantID string) ([]model.PPDBGelombang, error)
+	CreateGelombang(ctx context.Context, g *model.PPDBGelombang) error
+	UpdateGelombang(ctx context.Context, g *model.PPDBGelombang) error
+
+	// Seleksi
+	GetPendaftarList(ctx context.Context, filter model.PPDBPendaftarFilter) ([]model.PPDBPendaftar, error)
+	GetPendaftarDetail(ctx context.Context, tenantID, id string) (*model.PPDBPendaftar, error)
+	UpdateStatus(ctx context.Context, tenantID, id string, input model.PPDBStatusInput) (*model.PPDBPendaftar, error)
+	AddDokumen(ctx context.Context, tenantID, id string, dokumen *model.PPDBDokumen) error
+	KonfirmasiBayarManual(ctx context.Context, tenantID, id string) error
+
+	// Konversi ke Siswa
+	KonversiSiswa(ctx context.Context, tenantID, id string, input model.PPDBKonversiInput) (string, error)
+}
+
+type ppdbDomain struct {
+	databasePort outbound_port.DatabasePort
+	db           outbound_port.PPDBDatabasePort
+	payment      payment.PaymentDomain
+	messagePort  outbound_port.MessagePort
+}
+
+func NewPPDBDomain(databasePort outbound_port.DatabasePort, paymentDomain payment.PaymentDomain, messagePort outbound_port.MessagePort) PPDBDomain {
+	return &ppdbDomain{databasePort: databasePort, db: databasePort.PPDB(), payment: paymentDomain, messagePort: messagePort}
+}
+
+var (
+	ErrGelombangNotFound = errors.New("gelombang PPDB tidak ditemukan")
+	ErrPendaftarNotFound = errors.New("pendaftar tidak ditemukan")
+)
+
+// CanTransition reports whether an applicant may move from one selection status to another
+func CanTransition(from, to string) bool {
+	switch from {
+	case model.PPDBStatusVerifikasi:
+		return to == model.PPDBStatusTes || to == model.PPDBStatusDitolak
+	case model.PPDBStatusTes:
+		return to == model.PPDBStatusDiterima || to == model.PPDBStatusDitolak
+	}
+	return false
+}
+
+// ==========================================
+// PUBLIC REGISTRATION
+// ==========================================
+
+func (d *ppdbDomain) GetPublicInfo(ctx context.Context, tenant *model.Tenant) (*model.PPDBInfo, error) {
+	list, err := d.db.GetGelombangList(ctx, tenant.ID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	open := make([]model.PPDBGelombang, 0, len(list))
+	for _, g := range list {
+		if g.IsOpen(now) {
+			open = append(open, g)
+		}
+	}
+
+	nama := tenant.SchoolName
+	if nama == "" {
+		nama = tenant.Name
+	}
+	return &model.PPDBInfo{
+		NamaLembaga: nama,
+		Alamat:      tenant.Address,
+		Subdomain:   tenant.Subdomain,
+		Gelombang:   open,
+	}, nil
+}
+
+func (d *ppdbDomain) Daftar(ctx context.Context, p *model.PPDBPendaftar) (*model.PPDBRegistrasiResult, error) {
+	p.Nama = strings.TrimSpace(p.Nama)
+	p.NoHPWali = strings.TrimSpace(p.NoHPWali)
+	if p.GelombangID == "" || p.Nama == "" || p.NoHPWali == "" {
+		return nil, errors.New("gelombang_id, nama dan no_hp_wali wajib diisi")
+	}
+	if p.JenisKelamin != "" && p.JenisKelamin != "L" && p.JenisKelamin != "P" {
+		return nil, fmt.Errorf("jenis kelamin tidak valid: %s", p.JenisKelamin)
+	}
+	for _, dok := range p.Dokumen {
+		if !validDokumen(dok.Jenis) {
+			return nil, fmt.Errorf("jenis dokumen tidak valid: %s", dok.Jenis)
+		}
+	}
+
+	gelombang, err := d.db.GetGelombangByID(ctx, p.TenantID, p.GelombangID)
+	if err != nil {
+		return nil, err
+	}
+	if gelombang == nil {
+		return nil, ErrGelombangNotFound
+	}
+	if !gelombang.IsOpen(time.Now()) {
+		return nil, errors.New("pendaftaran gelombang ini sudah ditutup")
+	}
+
+	p.Status = model.PPDBStatusVerifikasi
+	p.StatusBayar = model.PPDBBayarBelum
+	if gelombang.BiayaPendaftaran == 0 {
+		p.StatusBayar = model.PPDBBayarGratis
+	}
+
+	// The number, the applicant and its documents are written together, so a
+	// failed document never leaves a half-registered applicant or a used number
+	_, err = d.databasePort.DoInTransaction(func(tx outbound_port.DatabasePort) (interface{}, error) {
+		db := tx.PPDB()
+		urut, err := db.NextNoUrut(ctx, p.TenantID, gelombang.TahunAjaran)
+		if err != nil {
+			return nil, err
+		}
+		p.NoPendaftaran = noPendaftaran(gelombang.TahunAjaran, urut)
+		if err := db.CreatePendaftar(ctx, p); err != nil {
+			return nil, err
+		}
+		for i := range p.Dokumen {
+			p.Dokumen[i].PendaftarID = p.ID
+			if err := db.AddDokumen(ctx, &p.Dokumen[i]); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.PPDBRegistrasiResult{
+		Pendaftar:   p,
+		BiayaDaftar: gelombang.BiayaPendaftaran,
+	}
+	if p.StatusBayar == model.PPDBBayarBelum {
+		// The registration stands even if Midtrans is unavailable; the applicant can retry payment later
+		snap, err := d.createPayment(ctx, p, gelombang.BiayaPendaftaran)
+		if err != nil {
+			log.WithContext(ctx).WithError(err).Errorf("Failed to create PPDB payment for %s", p.NoPendaftaran)
+		}
+		result.Pembayaran = snap
+	}
+	return result, nil
+}
+
+func (d *ppdbDomain) CekStatus(ctx context.Context, tenantID, noPendaftaran, noHPWali string) (*model.PPDBPendaftar, error) {
+	p, err := d.db.GetPendaftarByNo(ctx, tenantID, noPendaftaran)
+	if err != nil {
+		return nil, err
+	}
+	// Require the guardian phone so registration numbers cannot be enumerated
+	if p == nil || noHPWali == "" || p.NoHPWali != strings.TrimSpace(noHPWali) {
+		return nil, ErrPendaftarNotFound
+	}
+	return p, nil
+}
+
+func (d *ppdbDomain) BuatPembayaran(ctx context.Context, tenantID, noPendaftaran, noHPWali string) (*model.SnapTransactionResponse, error) {
+	p, err := d.CekStatus(ctx, tenantID, noPendaftaran, noHPWali)
+	if err != nil {
+		return nil, err
+	}
+	if p.StatusBayar != model.PPDBBayarBelum {
+		return nil, errors.New("biaya pendaftaran sudah lunas")
+	}
+
+	gelombang, err := d.db.GetGelombangByID(ctx, tenantID, p.GelombangID)
+	if err != nil {
+		return nil, err
+	}
+	if gelombang == nil {
+		return nil, ErrGelombangNotFound
+	}
+	return d.createPayment(ctx, p, gelombang.BiayaPendaftaran)
+}
+
+func (d *ppdbDomain) createPayment(ctx context.Context, p *model.PPDBPendaftar, amount int64) (*model.SnapTransactionResponse, error) {
+	if d.payment == nil {
+		return nil, errors.New("pembayaran online tidak tersedia")
+	}
+	payment, snap, err := d.payment.CreatePPDBSnapTransaction(ctx, p.TenantID, p.ID, p.NoPendaftaran, amount, p.Nama, p.EmailWali)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.db.UpdateStatusBayar(ctx, p.TenantID, p.ID, model.PPDBBayarBelum, payment.OrderID); err != nil {
+		return nil, err
+	}
+	p.PaymentOrderID = payment.OrderID
+	return snap, nil
+}
+
+// ConfirmPembayaran marks the applicant fee as paid after a successful Midtrans webhook.
+// The applicant is taken from the order ID, so an older order that settles late
+// still confirms the fee after a newer one replaced it on the applicant.
+func (d *ppdbDomain) ConfirmPembayaran(ctx context.Context, orderID string) error {
+	pendaftarID, ok := PendaftarIDFromOrder(orderID)
+	if !ok {
+		return fmt.Errorf("order ID PPDB tidak valid: %s", orderID)
+	}
+	p, err := d.db.GetPendaftarForPayment(ctx, pendaftarID)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return ErrPendaftarNotFound
+	}
+	if p.StatusBayar == model.PPDBBayarLunas {
+		return nil
+	}
+	return d.db.UpdateStatusBayar(ctx, p.TenantID, p.ID, model.PPDBBayarLunas, "")
+}
+
+// PendaftarIDFromOrder extracts the applicant ID from a PPDB-{pendaftarID}-{timestamp} order ID
+func PendaftarIDFromOrder(orderID string) (string, bool) {
+	rest, ok := strings.CutPrefix(orderID, "PPDB-")
+	if !ok {
+		return "", false
+	}
+	i := strings.LastIndex(rest, "-")
+	if i <= 0 {
+		return "", false
+	}
+	return rest[:i], true
+}
+
+// ==========================================
+// GELOMBANG
+// ==========================================
+
+func (d *ppdbDomain) GetGelombangList(ctx context.Context, tenantID string) ([]model.PPDBGelombang, error) {
+	return d.db.GetGelombangList(ctx, tenantID, false)
+}
+
+func (d *ppdbDomain) CreateGelombang(ctx context.Context, g *model.PPDBGelombang) error {
+	if err := validateGelombang(g); err != nil {
+		return err
+	}
+	return d.db.CreateGelombang(ctx, g)
+}
+
+func (d *ppdbDomain) UpdateGelombang(ctx context.Context, g *model.PPDBGelombang) error {
+	existing, err := d.db.GetGelombangByID(ctx, g.TenantID, g.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return ErrGelombangNotFound
+	}
+	if err := validateGelombang(g); err != nil {
+		return err
+	}
+	return d.db.UpdateGelombang(ctx, g)
+}
+
+func validateGelombang(g *model.PPDBGelombang) error {
+	if strings.TrimSpace(g.Nama) == "" || strings.TrimSpace(g.TahunAjaran) == "" {
+		return errors.New("nama dan tahun_ajaran wajib diisi")
+	}
+	if g.TanggalTutup.Before(g.TanggalBuka) {
+		return errors.New("tanggal_tutup tidak boleh sebelum tanggal_buka")
+	}
+	if g.Kuota < 0 || g.BiayaPendaftaran < 0 {
+		return errors.New("kuota dan biaya_pendaftaran tidak boleh negatif")
+	}
+	return nil
+}
+
+// ==========================================
+// SELEKSI
+// ==========================================
+
+func (d *ppdbDomain) GetPendaftarList(ctx context.Context, filter model.PPDBPendaftarFilter) ([]model.PPDBPendaftar, error) {
+	return d.db.GetPendaftarList(ctx, filter)
+}
+
+func (d *ppdbDomain) GetPendaftarDetail(ctx context.Context, tenantID, id string) (*model.PPDBPendaftar, error) {
+	p, err := d.db.GetPendaftarByID(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, ErrPendaftarNotFound
+	}
+	if p.Dokumen, err = d.db.GetDokumenByPendaftar(ctx, p.ID); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (d *ppdbDomain) UpdateStatus(ctx context.Context, tenantID, id string, input model.PPDBStatusInput) (*model.PPDBPendaftar, error) {
+	out, err := d.databasePort.DoInTransaction(func(tx outbound_port.DatabasePort) (interface{}, error) {
+		db := tx.PPDB()
+		p, err := db.GetPendaftarByID(ctx, tenantID, id)
+		if err != nil {
+			return nil, err
+		}
+		if p == nil {
+			return nil, ErrPendaftarNotFound
+		}
+		if !CanTransition(p.Status, input.Status) {
+			return nil, fmt.Errorf("status tidak dapat diubah dari %s ke %s", p.Status, input.Status)
+		}
+
+		switch input.Status {
+		case model.PPDBStatusTes:
+			if p.StatusBayar == model.PPDBBayarBelum {
+				return nil, errors.New("biaya pendaftaran belum lunas")
+			}
+		case model.PPDBStatusDiterima:
+			// The wave stays locked until commit, so concurrent acceptances
+			// count each other and the quota cannot be exceeded
+			gelombang, err := db.GetGelombangByID(ctx, tenantID, p.GelombangID)
+			if err != nil {
+				return nil, err
+			}
+			if gelombang == nil {
+				return nil, ErrGelombangNotFound
+			}
+			if gelombang.Kuota > 0 {
+				diterima, err := db.CountDiterima(ctx, gelombang.ID)
+				if err != nil {
+					return nil, err
+				}
+				if diterima >= gelombang.Kuota {
+					return nil, fmt.Errorf("kuota %s sudah penuh (%d)", gelombang.Nama, gelombang.Kuota)
+				}
+			}
+		}
+
+		statusLama := p.Status
+		p.Status = input.Status
+		if input.NilaiTes != nil {
+			p.NilaiTes = input.NilaiTes
+		}
+		if input.Catatan != "" {
+			p.Catatan = input.Catatan
+		}
+		ok, err := db.UpdatePendaftarStatus(ctx, p, statusLama)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, errors.New("status pendaftar sudah diubah, muat ulang data")
+		}
+		return p, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p := out.(*model.PPDBPendaftar)
+	d.notifyStatus(ctx, p)
+	return p, nil
+}
+
+func (d *ppdbDomain) notifyStatus(ctx context.Context, p *model.PPDBPendaftar) {
+	if d.messagePort == nil || d.messagePort.WhatsApp() == nil || p.NoHPWali == "" {
+		return
+	}
+
+	var detail string
+	switch p.Status {
+	case model.PPDBStatusTes:
+		detail = "Berkas telah diverifikasi. Silakan mengikuti tes seleksi sesuai jadwal dari sekolah."
+	case model.PPDBStatusDiterima:
+		detail = "Selamat, ananda dinyatakan DITERIMA. Informasi daftar ulang akan disampaikan oleh sekolah."
+	case model.PPDBStatusDitolak:
+		detail = "Mohon maaf, ananda belum dapat diterima pada seleksi ini."
+	default:
+		return
+	}
+
+	message := fmt.Sprintf(
+		"Assalamu'alaikum, %s.\n\nStatus pendaftaran PPDB %s (%s): *%s*.\n%s\n\nTerima kasih.",
+		p.NamaWali, p.Nama, p.NoPendaftaran, p.Status, detail,
+	)
+	if err := d.messagePort.WhatsApp().Send(p.NoHPWali, message); err != nil {
+		log.WithContext(ctx).WithError(err).Errorf("Failed to send PPDB status notice for %s", p.NoPendaftaran)
+	}
+}
+
+func (d *ppdbDomain) AddDokumen(ctx context.Context, tenantID, id string, dokumen *model.PPDBDokumen) error {
+	p, err := d.db.GetPendaftarByID(ctx, tenantID, id)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return ErrPendaftarNotFound
+	}
+	if !validDokumen(dokumen.Jenis) {
+		return fmt.Errorf("jenis dokumen tidak valid: %s", dokumen.Jenis)
+	}
+	dokumen.PendaftarID = p.ID
+	return d.db.AddDokumen(ctx, dokumen)
+}
+
+// KonfirmasiBayarManual records a registration fee paid in cash at the school
+func (d *ppdbDomain) KonfirmasiBayarManual(ctx context.Context, tenantID, id string) error {
+	p, err := d.db.GetPendaftarByID(ctx, tenantID, id)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return ErrPendaftarNotFound
+	}
+	return d.db.UpdateStatusBayar(ctx, tenantID, id, model.PPDBBayarLunas, "")
+}
+
+// ==========================================
+// KONVERSI
+// ==========================================
+
+// KonversiSiswa creates a Siswa from an accepted applicant and assigns the kelas
+func (d *ppdbDomain) KonversiSiswa(ctx context.Context, tenantID, id string, input model.PPDBKonversiInput) (string, error) {
+	if input.KelasID == "" {
+		return "", errors.New("kelas_id wajib diisi")
+	}
+
+	p, err := d.db.GetPendaftarByID(ctx, tenantID, id)
+	if err != nil {
+		return "", err
+	}
+	if p == nil {
+		return "", ErrPendaftarNotFound
+	}
+	if p.Status != model.PPDBStatusDiterima {
+		return "", errors.New("hanya pendaftar berstatus Diterima yang dapat dikonversi")
+	}
+	if p.SiswaID != "" {
+		return "", errors.New("pendaftar sudah dikonversi menjadi siswa")
+	}
+	exists, err := d.db.KelasExists(ctx, tenantID, input.KelasID)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", errors.New("kelas tidak ditemukan")
+	}
+
+	return d.db.KonversiSiswa(ctx, p, input.KelasID, strings.TrimSpace(input.NIS))
+}
+
+// ==========================================
+// HELPERS
+// ==========================================
+
+func validDokumen(jenis string) bool {
+	for _, j := range model.PPDBDokumenJenis {
+		if j == jenis {
+			return true
+		}
+	}
+	return false
+}
+
+// noPendaftaran formats a registration number as PPDB-{tahun awal}-{urut}, e.g. PPDB-2026-0001
+func noPendaftaran(tahunAjaran string, urut int) string {
+	tahun := strings.SplitN(tahunAjaran, "/", 2)[0]
+	return fmt.Sprintf("PPDB-%s-%04d", strings.TrimSpace(tahun), urut)
+}
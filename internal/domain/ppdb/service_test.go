@@ -0,0 +1,48 @@
+package ppdb_test
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain/ppdb"
+	"prabogo/internal/model"
+)
+
+func TestCanTransition(t *testing.T) {
+	Convey("Test CanTransition", t, func() {
+		Convey("Verifikasi moves to Tes or Ditolak", func() {
+			So(ppdb.CanTransition(model.PPDBStatusVerifikasi, model.PPDBStatusTes), ShouldBeTrue)
+			So(ppdb.CanTransition(model.PPDBStatusVerifikasi, model.PPDBStatusDitolak), ShouldBeTrue)
+			So(ppdb.CanTransition(model.PPDBStatusVerifikasi, model.PPDBStatusDiterima), ShouldBeFalse)
+		})
+
+		Convey("Tes moves to Diterima or Ditolak", func() {
+			So(ppdb.CanTransition(model.PPDBStatusTes, model.PPDBStatusDiterima), ShouldBeTrue)
+			So(ppdb.CanTransition(model.PPDBStatusTes, model.PPDBStatusDitolak), ShouldBeTrue)
+			So(ppdb.CanTransition(model.PPDBStatusTes, model.PPDBStatusVerifikasi), ShouldBeFalse)
+		})
+
+		Convey("Final statuses cannot change", func() {
+			So(ppdb.CanTransition(model.PPDBStatusDiterima, model.PPDBStatusDitolak), ShouldBeFalse)
+			So(ppdb.CanTransition(model.PPDBStatusDitolak, model.PPDBStatusTes), ShouldBeFalse)
+		})
+	})
+}
+
+func TestPendaftarIDFromOrder(t *testing.T) {
+	Convey("Test PendaftarIDFromOrder", t, func() {
+		Convey("Takes the applicant ID between the prefix and the timestamp", func() {
+			id, ok := ppdb.PendaftarIDFromOrder("PPDB-3f6c1a2e-9b1d-4c6f-8a7e-1d2c3b4a5f60-1760860800000")
+			So(ok, ShouldBeTrue)
+			So(id, ShouldEqual, "3f6c1a2e-9b1d-4c6f-8a7e-1d2c3b4a5f60")
+		})
+
+		Convey("Rejects orders of other fee types or without a timestamp", func() {
+			_, ok := ppdb.PendaftarIDFromOrder("SPP-3f6c1a2e-1760860800000")
+			So(ok, ShouldBeFalse)
+			_, ok = ppdb.PendaftarIDFromOrder("PPDB-1760860800000")
+			So(ok, ShouldBeFalse)
+		})
+	})
+}
@@ -1,22 +1,29 @@
 package domain
 
 import (
+	alumni_domain "prabogo/internal/domain/alumni"
 	analytics_domain "prabogo/internal/domain/analytics"
 	audit_log_domain "prabogo/internal/domain/audit_log"
 	"prabogo/internal/domain/auth"
 	"prabogo/internal/domain/client"
 	"prabogo/internal/domain/content"
+	dapodik_domain "prabogo/internal/domain/dapodik"
 	disbursement_domain "prabogo/internal/domain/disbursement"
+	ekskul_domain "prabogo/internal/domain/ekskul"
 	erapor_domain "prabogo/internal/domain/erapor"
 	export_domain "prabogo/internal/domain/export"
+	konseling_domain "prabogo/internal/domain/konseling"
 	notification_domain "prabogo/internal/domain/notification"
 	"prabogo/internal/domain/payment"
+	perpustakaan_domain "prabogo/internal/domain/perpustakaan"
 	dashboard "prabogo/internal/domain/pesantren/dashboard"
+	ppdb_domain "prabogo/internal/domain/ppdb"
 	sdm_domain "prabogo/internal/domain/sdm"
 	"prabogo/internal/domain/sekolah"
 	spp_domain "prabogo/internal/domain/spp"
 	"prabogo/internal/domain/subscription"
 	"prabogo/internal/domain/tenant"
+	ujian_domain "prabogo/internal/domain/ujian"
 	outbound_port "prabogo/internal/port/outbound"
 )
 
@@ -38,6 +45,13 @@ type Domain interface {
 	Subscription() subscription.SubscriptionDomain
 	Analytics() analytics_domain.AnalyticsDomain
 	Export() export_domain.ExportDomain
+	Ekskul() ekskul_domain.EkskulDomain
+	Konseling() konseling_domain.KonselingDomain
+	Perpustakaan() perpustakaan_domain.PerpustakaanDomain
+	PPDB() ppdb_domain.PPDBDomain
+	Ujian() ujian_domain.UjianDomain
+	Dapodik() dapodik_domain.DapodikDomain
+	Alumni() alumni_domain.AlumniDomain
 }
 
 type domain struct {
@@ -98,7 +112,7 @@ func (d *domain) Notification() notification_domain.Service {
 }
 
 func (d *domain) Sekolah() sekolah.AkademikDomain {
-	return sekolah.NewAkademikDomain(d.databasePort)
+	return sekolah.NewAkademikDomain(d.databasePort, d.messagePort)
 }
 
 func (d *domain) AuditLog() audit_log_domain.Service {
@@ -106,7 +120,7 @@ func (d *domain) AuditLog() audit_log_domain.Service {
 }
 
 func (d *domain) ERapor() *erapor_domain.Service {
-	return erapor_domain.NewService(d.databasePort.ERapor())
+	return erapor_domain.NewService(d.databasePort, d.messagePort)
 }
 
 func (d *domain) SDM() sdm_domain.SDMDomain {
@@ -124,3 +138,31 @@ func (d *domain) Analytics() analytics_domain.AnalyticsDomain {
 func (d *domain) Export() export_domain.ExportDomain {
 	return export_domain.NewExportDomain(d.databasePort)
 }
+
+func (d *domain) Ekskul() ekskul_domain.EkskulDomain {
+	return ekskul_domain.NewEkskulDomain(d.databasePort)
+}
+
+func (d *domain) Konseling() konseling_domain.KonselingDomain {
+	return konseling_domain.NewKonselingDomain(d.databasePort)
+}
+
+func (d *domain) Perpustakaan() perpustakaan_domain.PerpustakaanDomain {
+	return perpustakaan_domain.NewPerpustakaanDomain(d.databasePort, d.messagePort)
+}
+
+func (d *domain) PPDB() ppdb_domain.PPDBDomain {
+	return ppdb_domain.NewPPDBDomain(d.databasePort, d.Payment(), d.messagePort)
+}
+
+func (d *domain) Ujian() ujian_domain.UjianDomain {
+	return ujian_domain.NewUjianDomain(d.databasePort.Ujian())
+}
+
+func (d *domain) Dapodik() dapodik_domain.DapodikDomain {
+	return dapodik_domain.NewDapodikDomain(d.databasePort.Dapodik(), d.databasePort.Tenant())
+}
+
+func (d *domain) Alumni() alumni_domain.AlumniDomain {
+	return alumni_domain.NewAlumniDomain(d.databasePort.Alumni(), d.messagePort)
+}
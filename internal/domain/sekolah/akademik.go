@@ -2,8 +2,13 @@ package sekolah
 
 import (
 	"context"
+	"strings"
+	"time"
+
+	"prabogo/internal/domain/sekolah/quran"
 	"prabogo/internal/model"
 	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/log"
 )
 
 // Domain Interface
@@ -30,17 +35,60 @@ type AkademikDomain interface {
 	CreateKamar(ctx context.Context, tenantID string, kamar *model.Kamar) error
 	GetPenempatanList(ctx context.Context, tenantID string) ([]model.Penempatan, error)
 	CreatePenempatan(ctx context.Context, tenantID string, penempatan *model.Penempatan) error
+	PindahKamar(ctx context.Context, tenantID, santriID, kamarID string, tanggal time.Time, keterangan string) (*model.Penempatan, error)
+	KeluarAsrama(ctx context.Context, tenantID, santriID string, tanggal time.Time, keterangan string) (*model.Penempatan, error)
+	GetRiwayatPenempatan(ctx context.Context, tenantID, santriID string) ([]model.Penempatan, error)
+	AlokasiPenempatan(ctx context.Context, tenantID string, in model.AlokasiInput) (*model.AlokasiHasil, error)
+	GetOkupansiAsrama(ctx context.Context, tenantID string) ([]model.OkupansiAsrama, error)
 
 	// Kepesantrenan
 	GetPelanggaranAturanList(ctx context.Context, tenantID string) ([]model.PelanggaranAturan, error)
 	CreatePelanggaranAturan(ctx context.Context, tenantID string, m *model.PelanggaranAturan) error
 	GetPelanggaranSiswaList(ctx context.Context, tenantID string) ([]model.PelanggaranSiswa, error)
-	CreatePelanggaranSiswa(ctx context.Context, tenantID string, m *model.PelanggaranSiswa) error
+	CreatePelanggaranSiswa(ctx context.Context, tenantID string, m *model.PelanggaranSiswa) ([]model.Sanksi, error)
+	// Poin pelanggaran & sanksi
+	GetSanksiAmbang(ctx context.Context, tenantID string) ([]model.SanksiAmbang, error)
+	SaveSanksiAmbang(ctx context.Context, tenantID string, list []model.SanksiAmbang) ([]model.SanksiAmbang, error)
+	GetRekapPoin(ctx context.Context, tenantID, semesterID string) ([]model.RekapPoin, error)
+	GetPoinSantri(ctx context.Context, tenantID, santriID, semesterID string) (*model.PoinSantri, error)
+	CreatePoinKredit(ctx context.Context, tenantID string, m *model.PoinKredit) error
+	GetSanksiList(ctx context.Context, tenantID, semesterID, status string) ([]model.Sanksi, error)
+	UpdateSanksiStatus(ctx context.Context, tenantID, id, status, catatan string) (*model.Sanksi, error)
+	KirimSanksiWali(ctx context.Context, tenantID, id string) error
+	CetakSuratPeringatan(ctx context.Context, tenantID, id string) ([]byte, error)
 	GetPerizinanList(ctx context.Context, tenantID string) ([]model.Perizinan, error)
 	CreatePerizinan(ctx context.Context, tenantID string, m *model.Perizinan) error
+	GetPerizinan(ctx context.Context, tenantID, id string) (*model.Perizinan, error)
+	ApprovePerizinan(ctx context.Context, tenantID, id, userID string) (*model.Perizinan, error)
+	RejectPerizinan(ctx context.Context, tenantID, id, userID, catatan string) (*model.Perizinan, error)
+	GetGatePass(ctx context.Context, tenantID, id string) (*model.GatePass, error)
+	CheckOutPerizinan(ctx context.Context, tenantID, id string) (*model.Perizinan, error)
+	CheckInPerizinan(ctx context.Context, tenantID, id, aturanID string) (*model.Perizinan, error)
+	ScanGatePass(ctx context.Context, tenantID, kode, aturanID string) (*model.Perizinan, error)
+	GetPerizinanTerlambat(ctx context.Context, tenantID string) ([]model.Perizinan, error)
+	KirimPerizinanTerlambat(ctx context.Context, tenantID string) (int, error)
 	// Tahfidz
 	GetTahfidzSetoranList(ctx context.Context, tenantID string) ([]model.TahfidzSetoran, error)
 	CreateTahfidzSetoran(ctx context.Context, tenantID string, m *model.TahfidzSetoran) error
+	GetTahfidzProgress(ctx context.Context, tenantID, santriID string) (*model.TahfidzProgress, error)
+	GetTahfidzLeaderboard(ctx context.Context, tenantID, halaqahID string) ([]model.TahfidzLeaderboard, error)
+	GetDaftarSurah() []quran.Surah
+	GetMurajaahPlan(ctx context.Context, tenantID, santriID string, siklus int, mulai time.Time) (*model.MurajaahPlan, error)
+	GetTahfidzAlerts(ctx context.Context, tenantID string) ([]model.TahfidzAlert, error)
+	KirimTahfidzAlerts(ctx context.Context, tenantID string) (int, error)
+	// Halaqah
+	GetHalaqahList(ctx context.Context, tenantID string) ([]model.Halaqah, error)
+	GetHalaqah(ctx context.Context, tenantID, id string) (*model.Halaqah, error)
+	CreateHalaqah(ctx context.Context, tenantID string, h *model.Halaqah) error
+	UpdateHalaqah(ctx context.Context, tenantID string, h *model.Halaqah) error
+	DeleteHalaqah(ctx context.Context, tenantID, id string) error
+	AddHalaqahAnggota(ctx context.Context, tenantID, halaqahID, santriID string) error
+	RemoveHalaqahAnggota(ctx context.Context, tenantID, halaqahID, santriID string) error
+	// Target hafalan
+	GetTahfidzTargets(ctx context.Context, tenantID, semesterID string) ([]model.TargetHafalan, error)
+	SaveTahfidzTarget(ctx context.Context, tenantID string, t *model.TargetHafalan) error
+	DeleteTahfidzTarget(ctx context.Context, tenantID, id string) error
+	GetTahfidzTargetStatus(ctx context.Context, tenantID, semesterID string) ([]model.TahfidzTargetStatus, error)
 	// Diniyah
 	GetDiniyahKitabList(ctx context.Context, tenantID string) ([]model.DiniyahKitab, error)
 	CreateDiniyahKitab(ctx context.Context, tenantID string, m *model.DiniyahKitab) error
@@ -65,11 +113,13 @@ type AkademikDomain interface {
 // Implementation
 type akademikDomain struct {
 	databasePort outbound_port.DatabasePort
+	messagePort  outbound_port.MessagePort
 }
 
-func NewAkademikDomain(databasePort outbound_port.DatabasePort) AkademikDomain {
+func NewAkademikDomain(databasePort outbound_port.DatabasePort, messagePort outbound_port.MessagePort) AkademikDomain {
 	return &akademikDomain{
 		databasePort: databasePort,
+		messagePort:  messagePort,
 	}
 }
 
@@ -113,11 +163,27 @@ func (d *akademikDomain) CreateKelas(ctx context.Context, tenantID string, kelas
 // ------ Asrama Implementation ------
 
 func (d *akademikDomain) GetAsramaList(ctx context.Context, tenantID string) ([]model.Asrama, error) {
-	return d.databasePort.Sekolah().GetAsramaByTenant(tenantID)
+	list, err := d.databasePort.Sekolah().GetAsramaByTenant(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	kamar, err := d.databasePort.Sekolah().GetKamarByAsrama(tenantID, "")
+	if err != nil {
+		return nil, err
+	}
+	for i, o := range RekapOkupansi(list, kamar) {
+		list[i].Kapasitas = o.Kapasitas
+		list[i].Terisi = o.Terisi
+	}
+	return list, nil
 }
 
 func (d *akademikDomain) CreateAsrama(ctx context.Context, tenantID string, asrama *model.Asrama) error {
 	asrama.TenantID = tenantID
+	asrama.Nama = strings.TrimSpace(asrama.Nama)
+	if asrama.Nama == "" || JenisKelaminAsrama(asrama.Jenis) == "" {
+		return ErrAsramaTidakSah
+	}
 	return d.databasePort.Sekolah().CreateAsrama(asrama)
 }
 
@@ -127,6 +193,10 @@ func (d *akademikDomain) GetKamarList(ctx context.Context, tenantID, asramaID st
 
 func (d *akademikDomain) CreateKamar(ctx context.Context, tenantID string, kamar *model.Kamar) error {
 	kamar.TenantID = tenantID
+	kamar.Nomor = strings.TrimSpace(kamar.Nomor)
+	if kamar.AsramaID == "" || kamar.Nomor == "" || kamar.Kapasitas <= 0 {
+		return ErrKamarTidakSah
+	}
 	return d.databasePort.Sekolah().CreateKamar(kamar)
 }
 
@@ -134,12 +204,6 @@ func (d *akademikDomain) GetPenempatanList(ctx context.Context, tenantID string)
 	return d.databasePort.Sekolah().GetPenempatanByTenant(tenantID)
 }
 
-func (d *akademikDomain) CreatePenempatan(ctx context.Context, tenantID string, penempatan *model.Penempatan) error {
-	penempatan.TenantID = tenantID
-	// Optional: Check capacity before placing (skipping for initial version, can be added later)
-	return d.databasePort.Sekolah().CreatePenempatan(penempatan)
-}
-
 // ------ Kepesantrenan Implementation ------
 
 func (d *akademikDomain) GetPelanggaranAturanList(ctx context.Context, tenantID string) ([]model.PelanggaranAturan, error) {
@@ -155,18 +219,65 @@ func (d *akademikDomain) GetPelanggaranSiswaList(ctx context.Context, tenantID s
 	return d.databasePort.Sekolah().GetPelanggaranSiswa(tenantID)
 }
 
-func (d *akademikDomain) CreatePelanggaranSiswa(ctx context.Context, tenantID string, m *model.PelanggaranSiswa) error {
+// CreatePelanggaranSiswa records a violation and raises the sanctions the santri's semester
+// points newly reach. A violation without points takes those of its rule.
+func (d *akademikDomain) CreatePelanggaranSiswa(ctx context.Context, tenantID string, m *model.PelanggaranSiswa) ([]model.Sanksi, error) {
 	m.TenantID = tenantID
-	// Optional: Validate AturanID existence and Poin match (skipping for MVP, trusting frontend/db constraints)
-	return d.databasePort.Sekolah().CreatePelanggaranSiswa(m)
+	if m.Poin < 0 {
+		return nil, ErrPoinPelanggaranNegatif
+	}
+	if m.Tanggal.IsZero() {
+		m.Tanggal = time.Now()
+	}
+	if m.Status == "" {
+		m.Status = "Pending"
+	}
+	if m.Poin == 0 && m.AturanID != nil {
+		aturan, err := d.databasePort.Sekolah().GetPelanggaranAturan(tenantID)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range aturan {
+			if a.ID == *m.AturanID {
+				m.Poin = a.Poin
+				break
+			}
+		}
+	}
+	if err := d.databasePort.Sekolah().CreatePelanggaranSiswa(m); err != nil {
+		return nil, err
+	}
+	// The violation is already stored, so a sanction failure must not report the request as
+	// failed; thresholds still unsanctioned are picked up with the santri's next violation
+	sanksi, err := d.terapkanSanksi(ctx, tenantID, m.SantriID, m.Tanggal)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Errorf("Failed to apply sanksi for pelanggaran %s", m.ID)
+	}
+	return sanksi, nil
 }
 
 func (d *akademikDomain) GetPerizinanList(ctx context.Context, tenantID string) ([]model.Perizinan, error) {
-	return d.databasePort.Sekolah().GetPerizinan(tenantID)
+	list, err := d.databasePort.Sekolah().GetPerizinan(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for i := range list {
+		list[i].Terlambat = PerizinanTerlambat(list[i], now)
+	}
+	return list, nil
 }
 
+// CreatePerizinan files a leave request; it always starts Pending and is decided through
+// ApprovePerizinan or RejectPerizinan
 func (d *akademikDomain) CreatePerizinan(ctx context.Context, tenantID string, m *model.Perizinan) error {
 	m.TenantID = tenantID
+	m.Tipe = strings.TrimSpace(m.Tipe)
+	if m.SantriID == "" || m.Tipe == "" || m.Dari.IsZero() || !m.Sampai.After(m.Dari) {
+		return ErrPerizinanTidakSah
+	}
+	m.Status = model.PerizinanPending
+	m.PenyetujuID = nil
 	return d.databasePort.Sekolah().CreatePerizinan(m)
 }
 
@@ -178,6 +289,9 @@ func (d *akademikDomain) GetTahfidzSetoranList(ctx context.Context, tenantID str
 
 func (d *akademikDomain) CreateTahfidzSetoran(ctx context.Context, tenantID string, m *model.TahfidzSetoran) error {
 	m.TenantID = tenantID
+	if err := NormalizeSetoran(m); err != nil {
+		return err
+	}
 	return d.databasePort.Sekolah().CreateTahfidzSetoran(m)
 }
 
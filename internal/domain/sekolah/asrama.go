@@ -0,0 +1,405 @@
+package sekolah
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+)
+
+var (
+	ErrAsramaTidakSah         = errors.New("asrama membutuhkan nama dan jenis Putra atau Putri")
+	ErrKamarTidakSah          = errors.New("kamar membutuhkan asrama, nomor, dan kapasitas lebih dari 0")
+	ErrKamarNotFound          = errors.New("kamar tidak ditemukan")
+	ErrKamarPenuh             = errors.New("kamar sudah penuh")
+	ErrKamarPerbaikan         = errors.New("kamar sedang dalam perbaikan")
+	ErrJenisKelaminKosong     = errors.New("jenis kelamin santri belum diisi")
+	ErrJenisAsramaTidakSesuai = errors.New("jenis kelamin santri tidak sesuai dengan asrama")
+	ErrSudahDitempatkan       = errors.New("santri sudah menempati kamar; gunakan pindah kamar")
+	ErrBelumDitempatkan       = errors.New("santri belum menempati kamar")
+	ErrKamarSama              = errors.New("santri sudah menempati kamar ini")
+	ErrUrutanAlokasiTidakSah  = errors.New("urutan alokasi harus kelas atau usia")
+	ErrKamarTidakTersedia     = errors.New("tidak ada kamar sesuai yang masih tersisa")
+)
+
+// JenisKelaminAsrama is the jenis kelamin (L, P) an asrama of the given kind takes, empty when
+// the kind is not Putra or Putri
+func JenisKelaminAsrama(jenis string) string {
+	switch {
+	case strings.EqualFold(jenis, model.AsramaPutra):
+		return "L"
+	case strings.EqualFold(jenis, model.AsramaPutri):
+		return "P"
+	}
+	return ""
+}
+
+// CekKamar reports why the santri cannot be placed in the room, nil when they can
+func CekKamar(kamar model.Kamar, siswa model.Siswa) error {
+	if kamar.Status == model.KamarPerbaikan {
+		return ErrKamarPerbaikan
+	}
+	if kamar.Terisi >= kamar.Kapasitas {
+		return ErrKamarPenuh
+	}
+	if jk := JenisKelaminAsrama(kamar.AsramaJenis); jk != "" {
+		if siswa.JenisKelamin == "" {
+			return ErrJenisKelaminKosong
+		}
+		if !strings.EqualFold(siswa.JenisKelamin, jk) {
+			return ErrJenisAsramaTidakSesuai
+		}
+	}
+	return nil
+}
+
+// AlokasiKamar plans rooms for the santri: they are ordered by kelas or by age (oldest first)
+// and each fills the first room with space in an asrama of their gender, so santri of one kelas
+// or of similar age end up together. Santri who cannot be placed are returned with the reason.
+func AlokasiKamar(santri []model.Siswa, kamar []model.Kamar, urutan string) ([]model.Penempatan, []model.AlokasiGagal, error) {
+	urut := append([]model.Siswa(nil), santri...)
+	switch urutan {
+	case model.AlokasiPerKelas:
+		sort.SliceStable(urut, func(i, j int) bool {
+			if urut[i].KelasNama != urut[j].KelasNama {
+				return urut[i].KelasNama < urut[j].KelasNama
+			}
+			return urut[i].Nama < urut[j].Nama
+		})
+	case model.AlokasiPerUsia:
+		sort.SliceStable(urut, func(i, j int) bool {
+			a, b := urut[i].TanggalLahir, urut[j].TanggalLahir
+			switch {
+			case a == nil || b == nil:
+				if (a == nil) != (b == nil) {
+					return b == nil
+				}
+			case !a.Equal(*b):
+				return a.Before(*b)
+			}
+			return urut[i].Nama < urut[j].Nama
+		})
+	default:
+		return nil, nil, ErrUrutanAlokasiTidakSah
+	}
+
+	sisa := append([]model.Kamar(nil), kamar...)
+	sort.SliceStable(sisa, func(i, j int) bool {
+		if sisa[i].AsramaNama != sisa[j].AsramaNama {
+			return sisa[i].AsramaNama < sisa[j].AsramaNama
+		}
+		return sisa[i].Nomor < sisa[j].Nomor
+	})
+
+	rencana := []model.Penempatan{}
+	gagal := []model.AlokasiGagal{}
+	for _, s := range urut {
+		alasan := ErrKamarTidakTersedia
+		placed := false
+		for i := range sisa {
+			err := CekKamar(sisa[i], s)
+			if err == nil {
+				sisa[i].Terisi++
+				rencana = append(rencana, model.Penempatan{
+					TenantID:   s.TenantID,
+					SantriID:   s.ID,
+					SantriNama: s.Nama,
+					KamarID:    sisa[i].ID,
+					KamarNomor: sisa[i].Nomor,
+					AsramaID:   sisa[i].AsramaID,
+					AsramaNama: sisa[i].AsramaNama,
+					Status:     model.PenempatanAktif,
+				})
+				placed = true
+				break
+			}
+			if errors.Is(err, ErrJenisKelaminKosong) {
+				alasan = err
+				break
+			}
+		}
+		if !placed {
+			gagal = append(gagal, model.AlokasiGagal{SantriID: s.ID, SantriNama: s.Nama, Alasan: alasan.Error()})
+		}
+	}
+	return rencana, gagal, nil
+}
+
+// RekapOkupansi sums the rooms of each asrama, in the order of the asrama list
+func RekapOkupansi(asrama []model.Asrama, kamar []model.Kamar) []model.OkupansiAsrama {
+	byAsrama := make(map[string][]model.Kamar, len(asrama))
+	for _, k := range kamar {
+		byAsrama[k.AsramaID] = append(byAsrama[k.AsramaID], k)
+	}
+	rekap := make([]model.OkupansiAsrama, 0, len(asrama))
+	for _, a := range asrama {
+		o := model.OkupansiAsrama{
+			AsramaID:    a.ID,
+			AsramaNama:  a.Nama,
+			Jenis:       a.Jenis,
+			MusyrifNama: a.Musyrif,
+			Kamar:       byAsrama[a.ID],
+		}
+		if o.Kamar == nil {
+			o.Kamar = []model.Kamar{}
+		}
+		for _, k := range o.Kamar {
+			o.JumlahKamar++
+			o.Kapasitas += k.Kapasitas
+			o.Terisi += k.Terisi
+			switch {
+			case k.Terisi == 0:
+				o.KamarKosong++
+			case k.Terisi >= k.Kapasitas:
+				o.KamarPenuh++
+			}
+		}
+		o.Sisa = o.Kapasitas - o.Terisi
+		if o.Sisa < 0 {
+			o.Sisa = 0
+		}
+		if o.Kapasitas > 0 {
+			o.Persen = math.Round(float64(o.Terisi)/float64(o.Kapasitas)*1000) / 10
+		}
+		rekap = append(rekap, o)
+	}
+	return rekap
+}
+
+func (d *akademikDomain) GetOkupansiAsrama(ctx context.Context, tenantID string) ([]model.OkupansiAsrama, error) {
+	asrama, err := d.databasePort.Sekolah().GetAsramaByTenant(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	kamar, err := d.databasePort.Sekolah().GetKamarByAsrama(tenantID, "")
+	if err != nil {
+		return nil, err
+	}
+	return RekapOkupansi(asrama, kamar), nil
+}
+
+// tempatkan puts the santri into the room after checking it with the room locked
+func tempatkan(port outbound_port.SekolahPort, siswa *model.Siswa, kamarID string, tanggal time.Time, keterangan string) (*model.Penempatan, error) {
+	kamar, err := port.GetKamarByID(siswa.TenantID, kamarID)
+	if err != nil {
+		return nil, err
+	}
+	if kamar == nil {
+		return nil, ErrKamarNotFound
+	}
+	if err := CekKamar(*kamar, *siswa); err != nil {
+		return nil, err
+	}
+	p := &model.Penempatan{
+		TenantID:     siswa.TenantID,
+		SantriID:     siswa.ID,
+		SantriNama:   siswa.Nama,
+		KamarID:      kamar.ID,
+		KamarNomor:   kamar.Nomor,
+		AsramaID:     kamar.AsramaID,
+		AsramaNama:   kamar.AsramaNama,
+		TanggalMasuk: tanggal,
+		Status:       model.PenempatanAktif,
+		Keterangan:   keterangan,
+	}
+	if err := port.CreatePenempatan(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (d *akademikDomain) santriByID(tenantID, santriID string) (*model.Siswa, error) {
+	siswa, err := d.databasePort.Sekolah().GetSiswaByID(tenantID, santriID)
+	if err != nil {
+		return nil, err
+	}
+	if siswa == nil {
+		return nil, ErrSantriNotFound
+	}
+	return siswa, nil
+}
+
+func hariIniAtau(t time.Time) time.Time {
+	if t.IsZero() {
+		return hari(time.Now())
+	}
+	return t
+}
+
+// CreatePenempatan places a santri without a room, refusing a full or wrong-gender room
+func (d *akademikDomain) CreatePenempatan(ctx context.Context, tenantID string, penempatan *model.Penempatan) error {
+	siswa, err := d.santriByID(tenantID, penempatan.SantriID)
+	if err != nil {
+		return err
+	}
+	_, err = d.databasePort.DoInTransaction(func(tx outbound_port.DatabasePort) (interface{}, error) {
+		aktif, err := tx.Sekolah().GetPenempatanAktif(tenantID, siswa.ID)
+		if err != nil {
+			return nil, err
+		}
+		if aktif != nil {
+			return nil, ErrSudahDitempatkan
+		}
+		p, err := tempatkan(tx.Sekolah(), siswa, penempatan.KamarID, hariIniAtau(penempatan.TanggalMasuk), penempatan.Keterangan)
+		if err != nil {
+			return nil, err
+		}
+		*penempatan = *p
+		return nil, nil
+	})
+	return err
+}
+
+// PindahKamar moves a santri to another room; the old placement is closed as Pindah and kept
+// as history
+func (d *akademikDomain) PindahKamar(ctx context.Context, tenantID, santriID, kamarID string, tanggal time.Time, keterangan string) (*model.Penempatan, error) {
+	siswa, err := d.santriByID(tenantID, santriID)
+	if err != nil {
+		return nil, err
+	}
+	tanggal = hariIniAtau(tanggal)
+	out, err := d.databasePort.DoInTransaction(func(tx outbound_port.DatabasePort) (interface{}, error) {
+		lama, err := tx.Sekolah().GetPenempatanAktif(tenantID, santriID)
+		if err != nil {
+			return nil, err
+		}
+		if lama == nil {
+			return nil, ErrBelumDitempatkan
+		}
+		if lama.KamarID == kamarID {
+			return nil, ErrKamarSama
+		}
+		lama.Status = model.PenempatanPindah
+		lama.TanggalKeluar = &tanggal
+		if err := tx.Sekolah().TutupPenempatan(lama); err != nil {
+			return nil, err
+		}
+		return tempatkan(tx.Sekolah(), siswa, kamarID, tanggal, keterangan)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*model.Penempatan), nil
+}
+
+// KeluarAsrama closes the santri's placement when they leave the asrama altogether
+func (d *akademikDomain) KeluarAsrama(ctx context.Context, tenantID, santriID string, tanggal time.Time, keterangan string) (*model.Penempatan, error) {
+	p, err := d.databasePort.Sekolah().GetPenempatanAktif(tenantID, santriID)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, ErrBelumDitempatkan
+	}
+	tanggal = hariIniAtau(tanggal)
+	p.Status = model.PenempatanKeluar
+	p.TanggalKeluar = &tanggal
+	if keterangan = strings.TrimSpace(keterangan); keterangan != "" {
+		p.Keterangan = keterangan
+	}
+	if err := d.databasePort.Sekolah().TutupPenempatan(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (d *akademikDomain) GetRiwayatPenempatan(ctx context.Context, tenantID, santriID string) ([]model.Penempatan, error) {
+	if _, err := d.santriByID(tenantID, santriID); err != nil {
+		return nil, err
+	}
+	return d.databasePort.Sekolah().GetPenempatanBySantri(tenantID, santriID)
+}
+
+// AlokasiPenempatan places active santri who have no room yet, optionally only those of one
+// kelas and only into one asrama. With simulasi the plan is returned without being saved.
+func (d *akademikDomain) AlokasiPenempatan(ctx context.Context, tenantID string, in model.AlokasiInput) (*model.AlokasiHasil, error) {
+	siswa, err := d.databasePort.Sekolah().GetSiswaByTenant(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	penempatan, err := d.databasePort.Sekolah().GetPenempatanByTenant(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	kamar, err := d.databasePort.Sekolah().GetKamarByAsrama(tenantID, in.AsramaID)
+	if err != nil {
+		return nil, err
+	}
+
+	sudah := make(map[string]bool, len(penempatan))
+	for _, p := range penempatan {
+		if p.Status == model.PenempatanAktif {
+			sudah[p.SantriID] = true
+		}
+	}
+	pilih := make(map[string]bool, len(in.SantriIDs))
+	for _, id := range in.SantriIDs {
+		pilih[id] = true
+	}
+	calon := []model.Siswa{}
+	for _, s := range siswa {
+		if sudah[s.ID] || (s.Status != "" && s.Status != "Aktif") {
+			continue
+		}
+		if in.KelasID != "" && s.KelasID != in.KelasID {
+			continue
+		}
+		if len(pilih) > 0 && !pilih[s.ID] {
+			continue
+		}
+		calon = append(calon, s)
+	}
+
+	rencana, gagal, err := AlokasiKamar(calon, kamar, in.Urutan)
+	if err != nil {
+		return nil, err
+	}
+	tanggal := hariIniAtau(in.TanggalMasuk)
+	for i := range rencana {
+		rencana[i].TanggalMasuk = tanggal
+	}
+	hasil := &model.AlokasiHasil{Penempatan: rencana, Gagal: gagal}
+	if in.Simulasi || len(rencana) == 0 {
+		return hasil, nil
+	}
+
+	bySantri := make(map[string]*model.Siswa, len(calon))
+	for i := range calon {
+		bySantri[calon[i].ID] = &calon[i]
+	}
+	_, err = d.databasePort.DoInTransaction(func(tx outbound_port.DatabasePort) (interface{}, error) {
+		disimpan := make([]model.Penempatan, 0, len(rencana))
+		for _, r := range rencana {
+			// Another request may have placed the santri since the plan was made
+			aktif, err := tx.Sekolah().GetPenempatanAktif(tenantID, r.SantriID)
+			if err != nil {
+				return nil, err
+			}
+			if aktif != nil {
+				hasil.Gagal = append(hasil.Gagal, model.AlokasiGagal{
+					SantriID:   r.SantriID,
+					SantriNama: r.SantriNama,
+					Alasan:     ErrSudahDitempatkan.Error(),
+				})
+				continue
+			}
+			p, err := tempatkan(tx.Sekolah(), bySantri[r.SantriID], r.KamarID, tanggal, "Alokasi otomatis per "+in.Urutan)
+			if err != nil {
+				return nil, err
+			}
+			disimpan = append(disimpan, *p)
+		}
+		hasil.Penempatan = disimpan
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	hasil.Disimpan = true
+	return hasil, nil
+}
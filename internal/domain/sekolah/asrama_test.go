@@ -0,0 +1,94 @@
+package sekolah_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain/sekolah"
+	"prabogo/internal/model"
+)
+
+func TestAsrama(t *testing.T) {
+	putra := model.Kamar{ID: "k1", AsramaID: "a1", AsramaNama: "Al-Fatih", AsramaJenis: model.AsramaPutra, Nomor: "01", Kapasitas: 2}
+	putri := model.Kamar{ID: "k2", AsramaID: "a2", AsramaNama: "Khadijah", AsramaJenis: model.AsramaPutri, Nomor: "01", Kapasitas: 2}
+
+	Convey("Test CekKamar", t, func() {
+		So(sekolah.CekKamar(putra, model.Siswa{JenisKelamin: "L"}), ShouldBeNil)
+		So(sekolah.CekKamar(putra, model.Siswa{JenisKelamin: "P"}), ShouldEqual, sekolah.ErrJenisAsramaTidakSesuai)
+		So(sekolah.CekKamar(putri, model.Siswa{}), ShouldEqual, sekolah.ErrJenisKelaminKosong)
+
+		penuh := putra
+		penuh.Terisi = 2
+		So(sekolah.CekKamar(penuh, model.Siswa{JenisKelamin: "L"}), ShouldEqual, sekolah.ErrKamarPenuh)
+
+		perbaikan := putra
+		perbaikan.Status = model.KamarPerbaikan
+		So(sekolah.CekKamar(perbaikan, model.Siswa{JenisKelamin: "L"}), ShouldEqual, sekolah.ErrKamarPerbaikan)
+	})
+
+	Convey("Test AlokasiKamar", t, func() {
+		lahir := func(y int) *time.Time {
+			t := time.Date(y, 1, 1, 0, 0, 0, 0, time.UTC)
+			return &t
+		}
+		santri := []model.Siswa{
+			{ID: "s1", Nama: "Umar", JenisKelamin: "L", KelasNama: "8A", TanggalLahir: lahir(2011)},
+			{ID: "s2", Nama: "Ali", JenisKelamin: "L", KelasNama: "7A", TanggalLahir: lahir(2012)},
+			{ID: "s3", Nama: "Aisyah", JenisKelamin: "P", KelasNama: "7A", TanggalLahir: lahir(2012)},
+			{ID: "s4", Nama: "Hasan", JenisKelamin: "L", KelasNama: "7A", TanggalLahir: lahir(2010)},
+			{ID: "s5", Nama: "Zaid", KelasNama: "7A"},
+		}
+		kamar := []model.Kamar{putri, putra, {ID: "k3", AsramaID: "a1", AsramaNama: "Al-Fatih", AsramaJenis: model.AsramaPutra, Nomor: "02", Kapasitas: 2}}
+
+		Convey("Fills rooms of the santri's gender in kelas order", func() {
+			rencana, gagal, err := sekolah.AlokasiKamar(santri, kamar, model.AlokasiPerKelas)
+			So(err, ShouldBeNil)
+			kamarOf := map[string]string{}
+			for _, p := range rencana {
+				kamarOf[p.SantriID] = p.KamarID
+			}
+			So(kamarOf["s2"], ShouldEqual, "k1") // 7A: Ali, Hasan together
+			So(kamarOf["s4"], ShouldEqual, "k1")
+			So(kamarOf["s1"], ShouldEqual, "k3") // 8A after 7A
+			So(kamarOf["s3"], ShouldEqual, "k2")
+			So(gagal, ShouldHaveLength, 1)
+			So(gagal[0].SantriID, ShouldEqual, "s5")
+			So(gagal[0].Alasan, ShouldEqual, sekolah.ErrJenisKelaminKosong.Error())
+		})
+
+		Convey("Orders oldest first by age and reports santri left without a room", func() {
+			rencana, gagal, err := sekolah.AlokasiKamar(santri[:4], kamar[:2], model.AlokasiPerUsia)
+			So(err, ShouldBeNil)
+			So(rencana, ShouldHaveLength, 3)
+			So(rencana[0].SantriID, ShouldEqual, "s4")
+			So(rencana[1].SantriID, ShouldEqual, "s1")
+			So(gagal, ShouldHaveLength, 1)
+			So(gagal[0].SantriID, ShouldEqual, "s2")
+			So(gagal[0].Alasan, ShouldEqual, sekolah.ErrKamarTidakTersedia.Error())
+		})
+
+		Convey("Rejects an unknown order", func() {
+			_, _, err := sekolah.AlokasiKamar(santri, kamar, "nama")
+			So(err, ShouldEqual, sekolah.ErrUrutanAlokasiTidakSah)
+		})
+	})
+
+	Convey("Test RekapOkupansi", t, func() {
+		asrama := []model.Asrama{{ID: "a1", Nama: "Al-Fatih", Jenis: model.AsramaPutra}, {ID: "a3", Nama: "Baru"}}
+		a, b := putra, putra
+		a.Terisi = 2
+		b.ID, b.Nomor, b.Kapasitas = "k3", "02", 4
+		rekap := sekolah.RekapOkupansi(asrama, []model.Kamar{a, b, putri})
+		So(rekap, ShouldHaveLength, 2)
+		So(rekap[0].JumlahKamar, ShouldEqual, 2)
+		So(rekap[0].Kapasitas, ShouldEqual, 6)
+		So(rekap[0].Terisi, ShouldEqual, 2)
+		So(rekap[0].Sisa, ShouldEqual, 4)
+		So(rekap[0].KamarPenuh, ShouldEqual, 1)
+		So(rekap[0].KamarKosong, ShouldEqual, 1)
+		So(rekap[0].Persen, ShouldEqual, 33.3)
+		So(rekap[1].Kamar, ShouldBeEmpty)
+	})
+}
@@ -0,0 +1,155 @@
+package sekolah
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+)
+
+var (
+	ErrHalaqahNotFound = errors.New("halaqah tidak ditemukan")
+	ErrHalaqahPenuh    = errors.New("halaqah sudah mencapai kapasitas")
+	ErrUstadzNotFound  = errors.New("ustadz tidak ditemukan")
+)
+
+const (
+	defaultKapasitasHalaqah = 10
+	defaultBatasHari        = 7
+)
+
+func (d *akademikDomain) GetHalaqahList(ctx context.Context, tenantID string) ([]model.Halaqah, error) {
+	return d.databasePort.Sekolah().GetHalaqahByTenant(tenantID)
+}
+
+// GetHalaqah returns a halaqah with its santri
+func (d *akademikDomain) GetHalaqah(ctx context.Context, tenantID, id string) (*model.Halaqah, error) {
+	h, err := d.databasePort.Sekolah().GetHalaqahByID(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if h == nil {
+		return nil, ErrHalaqahNotFound
+	}
+	h.Anggota, err = d.databasePort.Sekolah().GetHalaqahAnggota(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if h.Anggota == nil {
+		h.Anggota = []model.HalaqahAnggota{}
+	}
+	return h, nil
+}
+
+func (d *akademikDomain) CreateHalaqah(ctx context.Context, tenantID string, h *model.Halaqah) error {
+	h.TenantID = tenantID
+	h.IsActive = true
+	if err := d.validateHalaqah(h); err != nil {
+		return err
+	}
+	return d.databasePort.Sekolah().CreateHalaqah(h)
+}
+
+// UpdateHalaqah changes a halaqah; its capacity may not drop below the santri it already has
+func (d *akademikDomain) UpdateHalaqah(ctx context.Context, tenantID string, h *model.Halaqah) error {
+	existing, err := d.databasePort.Sekolah().GetHalaqahByID(tenantID, h.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return ErrHalaqahNotFound
+	}
+	h.TenantID = tenantID
+	if err := d.validateHalaqah(h); err != nil {
+		return err
+	}
+	if h.Kapasitas < existing.JumlahAnggota {
+		return ErrHalaqahPenuh
+	}
+	return d.databasePort.Sekolah().UpdateHalaqah(h)
+}
+
+func (d *akademikDomain) validateHalaqah(h *model.Halaqah) error {
+	h.Nama = strings.TrimSpace(h.Nama)
+	if h.Nama == "" {
+		return errors.New("nama halaqah wajib diisi")
+	}
+	if h.Kapasitas == 0 {
+		h.Kapasitas = defaultKapasitasHalaqah
+	}
+	if h.BatasHariTanpaSetoran == 0 {
+		h.BatasHariTanpaSetoran = defaultBatasHari
+	}
+	if h.Kapasitas < 1 || h.BatasHariTanpaSetoran < 1 {
+		return errors.New("kapasitas dan batas hari tanpa setoran harus lebih dari 0")
+	}
+	if h.UstadzID != nil && *h.UstadzID == "" {
+		h.UstadzID = nil
+	}
+	if h.UstadzID == nil {
+		return nil
+	}
+	gurus, err := d.databasePort.Sekolah().GetGuruByTenant(h.TenantID)
+	if err != nil {
+		return err
+	}
+	for _, g := range gurus {
+		if g.ID == *h.UstadzID {
+			return nil
+		}
+	}
+	return ErrUstadzNotFound
+}
+
+func (d *akademikDomain) DeleteHalaqah(ctx context.Context, tenantID, id string) error {
+	h, err := d.databasePort.Sekolah().GetHalaqahByID(tenantID, id)
+	if err != nil {
+		return err
+	}
+	if h == nil {
+		return ErrHalaqahNotFound
+	}
+	return d.databasePort.Sekolah().DeleteHalaqah(tenantID, id)
+}
+
+// AddHalaqahAnggota places a santri in a halaqah, moving them out of their previous one. The
+// halaqah stays locked from the member count to the insert so two additions cannot both take
+// its last seat.
+func (d *akademikDomain) AddHalaqahAnggota(ctx context.Context, tenantID, halaqahID, santriID string) error {
+	_, err := d.databasePort.DoInTransaction(func(tx outbound_port.DatabasePort) (interface{}, error) {
+		h, err := tx.Sekolah().GetHalaqahByID(tenantID, halaqahID)
+		if err != nil {
+			return nil, err
+		}
+		if h == nil {
+			return nil, ErrHalaqahNotFound
+		}
+		siswa, err := tx.Sekolah().GetSiswaByID(tenantID, santriID)
+		if err != nil {
+			return nil, err
+		}
+		if siswa == nil {
+			return nil, ErrSantriNotFound
+		}
+		anggota, err := tx.Sekolah().GetHalaqahAnggota(tenantID, halaqahID)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range anggota {
+			if a.SantriID == santriID {
+				return nil, nil
+			}
+		}
+		if len(anggota) >= h.Kapasitas {
+			return nil, ErrHalaqahPenuh
+		}
+		return nil, tx.Sekolah().AddHalaqahAnggota(tenantID, halaqahID, santriID)
+	})
+	return err
+}
+
+func (d *akademikDomain) RemoveHalaqahAnggota(ctx context.Context, tenantID, halaqahID, santriID string) error {
+	return d.databasePort.Sekolah().RemoveHalaqahAnggota(tenantID, halaqahID, santriID)
+}
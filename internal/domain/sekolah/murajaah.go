@@ -0,0 +1,80 @@
+package sekolah
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"prabogo/internal/domain/sekolah/quran"
+	"prabogo/internal/model"
+)
+
+var ErrSiklusTidakSah = errors.New("siklus murajaah harus antara 1 dan 60 hari")
+
+const defaultSiklusMurajaah = 7
+
+// RencanaMurajaah rotates a santri's whole hafalan over siklus days in mushaf order, each day
+// taking about the same number of pages. The rotation repeats from mulai; the portions carry
+// the dates of the round running on now, and a hafalan shorter than siklus ayat makes a shorter
+// round.
+func RencanaMurajaah(santriID string, setoran []model.TahfidzSetoran, siklus int, mulai, now time.Time) model.MurajaahPlan {
+	hafalan := susunHafalan(santriID, setoran)
+	bagi := hafalan.Bagi(siklus)
+	plan := model.MurajaahPlan{SantriID: santriID, Mulai: hari(mulai), Siklus: len(bagi), Porsi: []model.MurajaahPorsi{}}
+	if len(bagi) == 0 {
+		return plan
+	}
+
+	berjalan := max(selisihHari(mulai, now), 0)
+	putaran := plan.Mulai.AddDate(0, 0, berjalan/plan.Siklus*plan.Siklus)
+	hariIni := -1
+	if selisihHari(mulai, now) >= 0 {
+		hariIni = berjalan % plan.Siklus
+	}
+	for i, p := range bagi {
+		porsi := model.MurajaahPorsi{
+			Hari:    i + 1,
+			Tanggal: putaran.AddDate(0, 0, i),
+			Bagian:  make([]model.MurajaahBagian, len(p.Bagian)),
+			Ayat:    p.Ayat,
+			Halaman: bulatkan(p.Halaman),
+			HariIni: i == hariIni,
+		}
+		for j, b := range p.Bagian {
+			surah, _ := quran.SurahKe(b.Surah)
+			porsi.Bagian[j] = model.MurajaahBagian{SurahNomor: b.Surah, Surah: surah.Nama, AyatAwal: b.AyatAwal, AyatAkhir: b.AyatAkhir}
+		}
+		plan.Porsi = append(plan.Porsi, porsi)
+	}
+	return plan
+}
+
+// GetMurajaahPlan builds the murajaah rotation of a santri. siklus defaults to a week and mulai
+// to the Monday of the current week.
+func (d *akademikDomain) GetMurajaahPlan(ctx context.Context, tenantID, santriID string, siklus int, mulai time.Time) (*model.MurajaahPlan, error) {
+	if siklus == 0 {
+		siklus = defaultSiklusMurajaah
+	}
+	if siklus < 1 || siklus > 60 {
+		return nil, ErrSiklusTidakSah
+	}
+	now := time.Now()
+	if mulai.IsZero() {
+		mulai = now.AddDate(0, 0, -((int(now.Weekday()) + 6) % 7))
+	}
+
+	siswa, err := d.databasePort.Sekolah().GetSiswaByID(tenantID, santriID)
+	if err != nil {
+		return nil, err
+	}
+	if siswa == nil {
+		return nil, ErrSantriNotFound
+	}
+	setoran, err := d.databasePort.Sekolah().GetTahfidzZiyadah(tenantID, santriID)
+	if err != nil {
+		return nil, err
+	}
+	plan := RencanaMurajaah(santriID, setoran, siklus, mulai, now)
+	plan.SantriNama = siswa.Nama
+	return &plan, nil
+}
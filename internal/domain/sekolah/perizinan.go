@@ -0,0 +1,385 @@
+package sekolah
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"prabogo/internal/model"
+	"prabogo/utils/log"
+)
+
+var (
+	ErrPerizinanNotFound       = errors.New("perizinan tidak ditemukan")
+	ErrPerizinanTidakSah       = errors.New("perizinan membutuhkan santri, tipe, dan waktu sampai setelah waktu dari")
+	ErrStatusPerizinanTidakSah = errors.New("perizinan tidak dapat diproses pada status ini")
+	ErrAlasanPenolakanKosong   = errors.New("alasan penolakan wajib diisi")
+	ErrGatePassTidakSah        = errors.New("gate pass tidak dikenali")
+	ErrIzinBerakhir            = errors.New("masa izin sudah berakhir, santri tidak dapat keluar")
+	ErrAturanNotFound          = errors.New("aturan pelanggaran tidak ditemukan")
+)
+
+const formatWaktuIzin = "02-01-2006 15:04"
+
+// perizinanBerikut is the workflow: Pending is decided, an approved leave is checked out at the
+// gate and a santri who is out is checked back in
+var perizinanBerikut = map[string][]string{
+	model.PerizinanPending:   {model.PerizinanDisetujui, model.PerizinanDitolak},
+	model.PerizinanDisetujui: {model.PerizinanKeluar},
+	model.PerizinanKeluar:    {model.PerizinanKembali},
+}
+
+// TransisiPerizinanSah reports whether a leave may move from status dari to ke
+func TransisiPerizinanSah(dari, ke string) bool {
+	for _, s := range perizinanBerikut[dari] {
+		if s == ke {
+			return true
+		}
+	}
+	return false
+}
+
+// PerizinanTerlambat reports whether the santri came back after Sampai, or is still out past it
+func PerizinanTerlambat(p model.Perizinan, now time.Time) bool {
+	if p.KembaliAt != nil {
+		return p.KembaliAt.After(p.Sampai)
+	}
+	return p.Status == model.PerizinanKeluar && now.After(p.Sampai)
+}
+
+// KodeGatePass is a random token for the QR gate pass
+func KodeGatePass() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base32.StdEncoding.EncodeToString(b), nil
+}
+
+func (d *akademikDomain) perizinanByID(tenantID, id string) (*model.Perizinan, error) {
+	p, err := d.databasePort.Sekolah().GetPerizinanByID(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, ErrPerizinanNotFound
+	}
+	return p, nil
+}
+
+func (d *akademikDomain) GetPerizinan(ctx context.Context, tenantID, id string) (*model.Perizinan, error) {
+	p, err := d.perizinanByID(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	p.Terlambat = PerizinanTerlambat(*p, time.Now())
+	return p, nil
+}
+
+// ApprovePerizinan approves a pending leave and issues its gate pass. userID is the login
+// deciding it, recorded as penyetuju when it belongs to a guru/ustadz.
+func (d *akademikDomain) ApprovePerizinan(ctx context.Context, tenantID, id, userID string) (*model.Perizinan, error) {
+	p, err := d.perizinanByID(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !TransisiPerizinanSah(p.Status, model.PerizinanDisetujui) {
+		return nil, ErrStatusPerizinanTidakSah
+	}
+	if err := d.setPenyetuju(p, userID); err != nil {
+		return nil, err
+	}
+	kode, err := KodeGatePass()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	statusLama := p.Status
+	p.Status = model.PerizinanDisetujui
+	p.DisetujuiAt = &now
+	p.KodeGatePass = kode
+	if err := d.simpanTransisi(p, statusLama); err != nil {
+		return nil, err
+	}
+	d.kabariWaliPerizinan(ctx, p)
+	return p, nil
+}
+
+// RejectPerizinan turns down a pending leave with the reason the wali is told
+func (d *akademikDomain) RejectPerizinan(ctx context.Context, tenantID, id, userID, catatan string) (*model.Perizinan, error) {
+	catatan = strings.TrimSpace(catatan)
+	if catatan == "" {
+		return nil, ErrAlasanPenolakanKosong
+	}
+	p, err := d.perizinanByID(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !TransisiPerizinanSah(p.Status, model.PerizinanDitolak) {
+		return nil, ErrStatusPerizinanTidakSah
+	}
+	if err := d.setPenyetuju(p, userID); err != nil {
+		return nil, err
+	}
+	statusLama := p.Status
+	p.Status = model.PerizinanDitolak
+	p.CatatanPenolakan = catatan
+	if err := d.simpanTransisi(p, statusLama); err != nil {
+		return nil, err
+	}
+	d.kabariWaliPerizinan(ctx, p)
+	return p, nil
+}
+
+// setPenyetuju records the guru behind the deciding login; admins without a guru record leave
+// the penyetuju empty
+func (d *akademikDomain) setPenyetuju(p *model.Perizinan, userID string) error {
+	if userID == "" {
+		return nil
+	}
+	g, err := d.databasePort.Sekolah().GetGuruByUser(p.TenantID, userID)
+	if err != nil || g == nil {
+		return err
+	}
+	p.PenyetujuID = &g.ID
+	p.PenyetujuNama = g.Nama
+	return nil
+}
+
+// simpanTransisi saves a workflow step only if the leave is still in statusLama, so two requests
+// racing on the same leave cannot both pass TransisiPerizinanSah
+func (d *akademikDomain) simpanTransisi(p *model.Perizinan, statusLama string) error {
+	ok, err := d.databasePort.Sekolah().UpdatePerizinan(p, statusLama)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrStatusPerizinanTidakSah
+	}
+	return nil
+}
+
+// GetGatePass returns the pass of an approved leave, or of a santri who is out
+func (d *akademikDomain) GetGatePass(ctx context.Context, tenantID, id string) (*model.GatePass, error) {
+	p, err := d.perizinanByID(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if p.Status != model.PerizinanDisetujui && p.Status != model.PerizinanKeluar {
+		return nil, ErrStatusPerizinanTidakSah
+	}
+	return &model.GatePass{
+		PerizinanID:   p.ID,
+		Kode:          p.KodeGatePass,
+		SantriID:      p.SantriID,
+		SantriNama:    p.SantriNama,
+		KelasNama:     p.KelasNama,
+		Tipe:          p.Tipe,
+		Dari:          p.Dari,
+		Sampai:        p.Sampai,
+		Status:        p.Status,
+		PenyetujuNama: p.PenyetujuNama,
+		KeluarAt:      p.KeluarAt,
+	}, nil
+}
+
+// CheckOutPerizinan records the santri leaving through the gate
+func (d *akademikDomain) CheckOutPerizinan(ctx context.Context, tenantID, id string) (*model.Perizinan, error) {
+	p, err := d.perizinanByID(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	return d.checkOut(ctx, p)
+}
+
+func (d *akademikDomain) checkOut(ctx context.Context, p *model.Perizinan) (*model.Perizinan, error) {
+	if !TransisiPerizinanSah(p.Status, model.PerizinanKeluar) {
+		return nil, ErrStatusPerizinanTidakSah
+	}
+	now := time.Now()
+	if now.After(p.Sampai) {
+		return nil, ErrIzinBerakhir
+	}
+	statusLama := p.Status
+	p.Status = model.PerizinanKeluar
+	p.KeluarAt = &now
+	if err := d.simpanTransisi(p, statusLama); err != nil {
+		return nil, err
+	}
+	d.kabariWaliPerizinan(ctx, p)
+	return p, nil
+}
+
+// CheckInPerizinan records the santri's return. A late return is recorded as a violation of the
+// rule aturanID when one is given.
+func (d *akademikDomain) CheckInPerizinan(ctx context.Context, tenantID, id, aturanID string) (*model.Perizinan, error) {
+	p, err := d.perizinanByID(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	return d.checkIn(ctx, p, aturanID)
+}
+
+func (d *akademikDomain) checkIn(ctx context.Context, p *model.Perizinan, aturanID string) (*model.Perizinan, error) {
+	if !TransisiPerizinanSah(p.Status, model.PerizinanKembali) {
+		return nil, ErrStatusPerizinanTidakSah
+	}
+	var aturan *model.PelanggaranAturan
+	if aturanID != "" {
+		list, err := d.databasePort.Sekolah().GetPelanggaranAturan(p.TenantID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range list {
+			if list[i].ID == aturanID {
+				aturan = &list[i]
+				break
+			}
+		}
+		if aturan == nil {
+			return nil, ErrAturanNotFound
+		}
+	}
+
+	now := time.Now()
+	statusLama := p.Status
+	p.Status = model.PerizinanKembali
+	p.KembaliAt = &now
+	p.Terlambat = PerizinanTerlambat(*p, now)
+	if err := d.simpanTransisi(p, statusLama); err != nil {
+		return nil, err
+	}
+	d.kabariWaliPerizinan(ctx, p)
+
+	if !p.Terlambat || aturan == nil {
+		return p, nil
+	}
+	pelanggaran := model.PelanggaranSiswa{
+		SantriID: p.SantriID,
+		AturanID: &aturan.ID,
+		Tanggal:  now,
+		Poin:     aturan.Poin,
+		Keterangan: fmt.Sprintf("Terlambat kembali dari %s; batas %s, kembali %s",
+			strings.ToLower(p.Tipe), p.Sampai.Format(formatWaktuIzin), now.Format(formatWaktuIzin)),
+	}
+	if _, err := d.CreatePelanggaranSiswa(ctx, p.TenantID, &pelanggaran); err != nil {
+		return p, fmt.Errorf("santri tercatat kembali, tetapi pelanggaran gagal dicatat: %w", err)
+	}
+	p.PelanggaranID = &pelanggaran.ID
+	if _, err := d.databasePort.Sekolah().UpdatePerizinan(p, model.PerizinanKembali); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// ScanGatePass checks a santri out on the first scan of their pass and back in on the next
+func (d *akademikDomain) ScanGatePass(ctx context.Context, tenantID, kode, aturanID string) (*model.Perizinan, error) {
+	kode = strings.ToUpper(strings.TrimSpace(kode))
+	if kode == "" {
+		return nil, ErrGatePassTidakSah
+	}
+	p, err := d.databasePort.Sekolah().GetPerizinanByKode(tenantID, kode)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, ErrGatePassTidakSah
+	}
+	if p.Status == model.PerizinanKeluar {
+		return d.checkIn(ctx, p, aturanID)
+	}
+	return d.checkOut(ctx, p)
+}
+
+// GetPerizinanTerlambat lists the tenant's santri still out after their leave ended
+func (d *akademikDomain) GetPerizinanTerlambat(ctx context.Context, tenantID string) ([]model.Perizinan, error) {
+	list, err := d.databasePort.Sekolah().GetPerizinanTerlambat(tenantID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		list[i].Terlambat = true
+	}
+	return list, nil
+}
+
+// KirimPerizinanTerlambat tells the wali, once per leave, that their santri has not come back in
+// time. An empty tenantID covers every tenant. It returns how many were sent.
+func (d *akademikDomain) KirimPerizinanTerlambat(ctx context.Context, tenantID string) (int, error) {
+	list, err := d.GetPerizinanTerlambat(ctx, tenantID)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for i := range list {
+		p := &list[i]
+		if p.TerlambatDikabariAt != nil {
+			continue
+		}
+		if err := d.kirimPesanPerizinan(p, pesanPerizinan(p)); err != nil {
+			log.WithContext(ctx).WithError(err).Errorf("Failed to notify wali of overdue perizinan %s", p.ID)
+			continue
+		}
+		now := time.Now()
+		p.TerlambatDikabariAt = &now
+		if _, err := d.databasePort.Sekolah().UpdatePerizinan(p, model.PerizinanKeluar); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// kabariWaliPerizinan tells the wali about a step of the leave. Failures are only logged so the
+// step itself still goes through.
+func (d *akademikDomain) kabariWaliPerizinan(ctx context.Context, p *model.Perizinan) {
+	if err := d.kirimPesanPerizinan(p, pesanPerizinan(p)); err != nil {
+		log.WithContext(ctx).WithError(err).Errorf("Failed to notify wali of perizinan %s", p.ID)
+	}
+}
+
+func (d *akademikDomain) kirimPesanPerizinan(p *model.Perizinan, body string) error {
+	if d.messagePort == nil || d.messagePort.WhatsApp() == nil {
+		return errors.New("layanan WhatsApp tidak tersedia")
+	}
+	if p.NoHPWali == "" {
+		return errors.New("nomor HP wali santri belum diisi")
+	}
+	wali := p.NamaWali
+	if wali == "" {
+		wali = "Bapak/Ibu wali santri"
+	}
+	message := fmt.Sprintf("Assalamu'alaikum, %s.\n\n%s\n\nTerima kasih.", wali, body)
+	return d.messagePort.WhatsApp().Send(p.NoHPWali, message)
+}
+
+// pesanPerizinan describes the leave's current step to the wali
+func pesanPerizinan(p *model.Perizinan) string {
+	izin := strings.ToLower(p.Tipe)
+	switch p.Status {
+	case model.PerizinanDisetujui:
+		return fmt.Sprintf("%s ananda %s untuk %s sampai %s telah disetujui.",
+			p.Tipe, p.SantriNama, p.Dari.Format(formatWaktuIzin), p.Sampai.Format(formatWaktuIzin))
+	case model.PerizinanDitolak:
+		return fmt.Sprintf("%s ananda %s tidak dapat disetujui. Alasan: %s",
+			p.Tipe, p.SantriNama, p.CatatanPenolakan)
+	case model.PerizinanKeluar:
+		if p.KeluarAt == nil || p.Sampai.Before(time.Now()) {
+			return fmt.Sprintf("Ananda %s belum kembali ke pondok, padahal %s berakhir %s. Mohon segera mengantar ananda kembali atau menghubungi pengurus pondok.",
+				p.SantriNama, izin, p.Sampai.Format(formatWaktuIzin))
+		}
+		return fmt.Sprintf("Ananda %s telah keluar pondok pada %s dan dijadwalkan kembali paling lambat %s.",
+			p.SantriNama, p.KeluarAt.Format(formatWaktuIzin), p.Sampai.Format(formatWaktuIzin))
+	case model.PerizinanKembali:
+		pesan := fmt.Sprintf("Ananda %s telah kembali ke pondok pada %s.", p.SantriNama, p.KembaliAt.Format(formatWaktuIzin))
+		if p.KembaliAt.After(p.Sampai) {
+			pesan += fmt.Sprintf(" Ananda terlambat dari batas %s (%s).", izin, p.Sampai.Format(formatWaktuIzin))
+		}
+		return pesan
+	}
+	return fmt.Sprintf("Status %s ananda %s: %s.", izin, p.SantriNama, p.Status)
+}
@@ -0,0 +1,60 @@
+package sekolah_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain/sekolah"
+	"prabogo/internal/model"
+)
+
+func TestPerizinan(t *testing.T) {
+	Convey("Test TransisiPerizinanSah follows the workflow forward only", t, func() {
+		So(sekolah.TransisiPerizinanSah(model.PerizinanPending, model.PerizinanDisetujui), ShouldBeTrue)
+		So(sekolah.TransisiPerizinanSah(model.PerizinanPending, model.PerizinanDitolak), ShouldBeTrue)
+		So(sekolah.TransisiPerizinanSah(model.PerizinanDisetujui, model.PerizinanKeluar), ShouldBeTrue)
+		So(sekolah.TransisiPerizinanSah(model.PerizinanKeluar, model.PerizinanKembali), ShouldBeTrue)
+
+		So(sekolah.TransisiPerizinanSah(model.PerizinanPending, model.PerizinanKeluar), ShouldBeFalse)
+		So(sekolah.TransisiPerizinanSah(model.PerizinanDitolak, model.PerizinanDisetujui), ShouldBeFalse)
+		So(sekolah.TransisiPerizinanSah(model.PerizinanKembali, model.PerizinanKeluar), ShouldBeFalse)
+	})
+
+	Convey("Test PerizinanTerlambat", t, func() {
+		sampai := time.Date(2025, 10, 12, 17, 0, 0, 0, time.UTC)
+		p := model.Perizinan{Status: model.PerizinanKeluar, Sampai: sampai}
+
+		Convey("A santri still out is late once Sampai has passed", func() {
+			So(sekolah.PerizinanTerlambat(p, sampai.Add(-time.Hour)), ShouldBeFalse)
+			So(sekolah.PerizinanTerlambat(p, sampai.Add(time.Minute)), ShouldBeTrue)
+		})
+
+		Convey("A returned santri is judged by when they came back", func() {
+			p.Status = model.PerizinanKembali
+			tepat := sampai.Add(-time.Minute)
+			p.KembaliAt = &tepat
+			So(sekolah.PerizinanTerlambat(p, sampai.Add(48*time.Hour)), ShouldBeFalse)
+
+			telat := sampai.Add(2 * time.Hour)
+			p.KembaliAt = &telat
+			So(sekolah.PerizinanTerlambat(p, sampai), ShouldBeTrue)
+		})
+
+		Convey("An approved santri who never left is not late", func() {
+			p.Status = model.PerizinanDisetujui
+			So(sekolah.PerizinanTerlambat(p, sampai.Add(time.Hour)), ShouldBeFalse)
+		})
+	})
+
+	Convey("Test KodeGatePass is a random upper-case token", t, func() {
+		a, err := sekolah.KodeGatePass()
+		So(err, ShouldBeNil)
+		b, _ := sekolah.KodeGatePass()
+		So(a, ShouldHaveLength, 16)
+		So(a, ShouldNotEqual, b)
+		So(strings.Trim(a, "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567"), ShouldBeEmpty)
+	})
+}
@@ -0,0 +1,432 @@
+package sekolah
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/log"
+	pdf_utils "prabogo/utils/pdf"
+	"prabogo/utils/semester"
+)
+
+// errSanksiSudahAda rolls back the reserved letter number when a concurrent run already
+// recorded the sanksi
+var errSanksiSudahAda = errors.New("sanksi sudah tercatat")
+
+var (
+	ErrSanksiNotFound         = errors.New("sanksi tidak ditemukan")
+	ErrStatusSanksiTidakSah   = errors.New("status sanksi hanya dapat maju dari Pending ke Diproses lalu Selesai")
+	ErrAmbangTidakSah         = errors.New("setiap ambang membutuhkan poin lebih dari 0 yang tidak berulang dan jenis sanksi")
+	ErrKreditTidakSah         = errors.New("kredit poin membutuhkan poin lebih dari 0 dan keterangan")
+	ErrPoinPelanggaranNegatif = errors.New("poin pelanggaran tidak boleh negatif")
+)
+
+var bulanRomawi = [...]string{"I", "II", "III", "IV", "V", "VI", "VII", "VIII", "IX", "X", "XI", "XII"}
+
+// TotalPoin is the semester balance: violation points less credits, never below zero
+func TotalPoin(pelanggaran, kredit int) int {
+	return max(pelanggaran-kredit, 0)
+}
+
+// SanksiTercapai returns, lowest first, the thresholds a total has reached that have not raised a
+// sanction yet. sudah holds the threshold points already sanctioned in the semester.
+func SanksiTercapai(ambang []model.SanksiAmbang, total int, sudah map[int]bool) []model.SanksiAmbang {
+	result := []model.SanksiAmbang{}
+	for _, a := range ambang {
+		if a.Poin <= total && !sudah[a.Poin] {
+			result = append(result, a)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Poin < result[j].Poin })
+	return result
+}
+
+// AmbangBerikutnya is the lowest threshold above the total, nil past the last one
+func AmbangBerikutnya(ambang []model.SanksiAmbang, total int) *int {
+	var next *int
+	for _, a := range ambang {
+		if a.Poin > total && (next == nil || a.Poin < *next) {
+			poin := a.Poin
+			next = &poin
+		}
+	}
+	return next
+}
+
+// NomorSurat numbers a warning letter as urut/KIND/month in Roman numerals/year,
+// e.g. "007/SP1/X/2025"
+func NomorSurat(urut int, jenis string, t time.Time) string {
+	kode := strings.ToUpper(strings.Join(strings.Fields(jenis), "-"))
+	return fmt.Sprintf("%03d/%s/%s/%d", urut, kode, bulanRomawi[t.Month()-1], t.Year())
+}
+
+// sanksiAmbang loads the tenant's thresholds, or the built-in ladder when it has none
+func (d *akademikDomain) sanksiAmbang(tenantID string) ([]model.SanksiAmbang, error) {
+	ambang, err := d.databasePort.Sekolah().GetSanksiAmbang(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(ambang) == 0 {
+		ambang = model.DefaultSanksiAmbang()
+		for i := range ambang {
+			ambang[i].TenantID = tenantID
+		}
+	}
+	return ambang, nil
+}
+
+func (d *akademikDomain) GetSanksiAmbang(ctx context.Context, tenantID string) ([]model.SanksiAmbang, error) {
+	return d.sanksiAmbang(tenantID)
+}
+
+// SaveSanksiAmbang replaces the tenant's thresholds; an empty list restores the built-in ladder
+func (d *akademikDomain) SaveSanksiAmbang(ctx context.Context, tenantID string, list []model.SanksiAmbang) ([]model.SanksiAmbang, error) {
+	seen := make(map[int]bool, len(list))
+	for i := range list {
+		list[i].Jenis = strings.TrimSpace(list[i].Jenis)
+		if list[i].Poin <= 0 || list[i].Jenis == "" || seen[list[i].Poin] {
+			return nil, ErrAmbangTidakSah
+		}
+		seen[list[i].Poin] = true
+	}
+	if err := d.databasePort.Sekolah().ReplaceSanksiAmbang(tenantID, list); err != nil {
+		return nil, err
+	}
+	return d.sanksiAmbang(tenantID)
+}
+
+// semesterOrCurrent validates a semester id, defaulting to the running semester
+func semesterOrCurrent(semesterID string) (string, time.Time, time.Time, error) {
+	if semesterID == "" {
+		semesterID = semester.Of(time.Now())
+	}
+	start, end, ok := semester.Range(semesterID)
+	if !ok {
+		return "", time.Time{}, time.Time{}, semester.ErrTidakSah
+	}
+	return semesterID, start, end, nil
+}
+
+// GetRekapPoin lists the point balance of every santri with violations or credits in the
+// semester, highest first
+func (d *akademikDomain) GetRekapPoin(ctx context.Context, tenantID, semesterID string) ([]model.RekapPoin, error) {
+	semesterID, start, end, err := semesterOrCurrent(semesterID)
+	if err != nil {
+		return nil, err
+	}
+	rekap, err := d.databasePort.Sekolah().GetRekapPoin(tenantID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	ambang, err := d.sanksiAmbang(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	sanksi, err := d.databasePort.Sekolah().GetSanksi(tenantID, "", semesterID, "")
+	if err != nil {
+		return nil, err
+	}
+	terakhir := make(map[string]model.Sanksi)
+	for _, s := range sanksi {
+		if t, ok := terakhir[s.SantriID]; !ok || s.AmbangPoin > t.AmbangPoin {
+			terakhir[s.SantriID] = s
+		}
+	}
+
+	for i := range rekap {
+		r := &rekap[i]
+		r.SemesterID = semesterID
+		r.TotalPoin = TotalPoin(r.PoinPelanggaran, r.PoinKredit)
+		r.SanksiTerakhir = terakhir[r.SantriID].Jenis
+		r.AmbangBerikutnya = AmbangBerikutnya(ambang, r.TotalPoin)
+	}
+	sort.SliceStable(rekap, func(i, j int) bool { return rekap[i].TotalPoin > rekap[j].TotalPoin })
+	if rekap == nil {
+		rekap = []model.RekapPoin{}
+	}
+	return rekap, nil
+}
+
+// GetPoinSantri details a santri's semester points with the violations, credits and sanctions
+func (d *akademikDomain) GetPoinSantri(ctx context.Context, tenantID, santriID, semesterID string) (*model.PoinSantri, error) {
+	semesterID, start, end, err := semesterOrCurrent(semesterID)
+	if err != nil {
+		return nil, err
+	}
+	siswa, err := d.databasePort.Sekolah().GetSiswaByID(tenantID, santriID)
+	if err != nil {
+		return nil, err
+	}
+	if siswa == nil {
+		return nil, ErrSantriNotFound
+	}
+	return d.poinSantri(siswa, semesterID, start, end)
+}
+
+func (d *akademikDomain) poinSantri(siswa *model.Siswa, semesterID string, start, end time.Time) (*model.PoinSantri, error) {
+	port := d.databasePort.Sekolah()
+	pelanggaran, err := port.GetPelanggaranSantri(siswa.TenantID, siswa.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	kredit, err := port.GetPoinKredit(siswa.TenantID, siswa.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	sanksi, err := port.GetSanksi(siswa.TenantID, siswa.ID, semesterID, "")
+	if err != nil {
+		return nil, err
+	}
+	ambang, err := d.sanksiAmbang(siswa.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	poin := &model.PoinSantri{
+		RekapPoin: model.RekapPoin{
+			SantriID:   siswa.ID,
+			SantriNama: siswa.Nama,
+			KelasNama:  siswa.KelasNama,
+			SemesterID: semesterID,
+		},
+		Pelanggaran: append([]model.PelanggaranSiswa{}, pelanggaran...),
+		Kredit:      append([]model.PoinKredit{}, kredit...),
+		Sanksi:      append([]model.Sanksi{}, sanksi...),
+	}
+	for _, p := range pelanggaran {
+		poin.PoinPelanggaran += p.Poin
+	}
+	for _, k := range kredit {
+		poin.PoinKredit += k.Poin
+	}
+	poin.TotalPoin = TotalPoin(poin.PoinPelanggaran, poin.PoinKredit)
+	poin.AmbangBerikutnya = AmbangBerikutnya(ambang, poin.TotalPoin)
+	tertinggi := 0
+	for _, s := range sanksi {
+		if s.AmbangPoin > tertinggi {
+			tertinggi, poin.SanksiTerakhir = s.AmbangPoin, s.Jenis
+		}
+	}
+	return poin, nil
+}
+
+// terapkanSanksi raises the sanctions a santri's semester total has newly reached and lets the
+// wali know. Notification failures are logged; the sanction stays Pending for a resend.
+func (d *akademikDomain) terapkanSanksi(ctx context.Context, tenantID, santriID string, tanggal time.Time) ([]model.Sanksi, error) {
+	semesterID := semester.Of(tanggal)
+	start, end, _ := semester.Range(semesterID)
+	siswa, err := d.databasePort.Sekolah().GetSiswaByID(tenantID, santriID)
+	if err != nil {
+		return nil, err
+	}
+	if siswa == nil {
+		return nil, ErrSantriNotFound
+	}
+	poin, err := d.poinSantri(siswa, semesterID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	ambang, err := d.sanksiAmbang(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	sudah := make(map[int]bool, len(poin.Sanksi))
+	for _, s := range poin.Sanksi {
+		sudah[s.AmbangPoin] = true
+	}
+
+	baru := []model.Sanksi{}
+	now := time.Now()
+	for _, a := range SanksiTercapai(ambang, poin.TotalPoin, sudah) {
+		s := model.Sanksi{
+			TenantID:   tenantID,
+			SantriID:   santriID,
+			SantriNama: siswa.Nama,
+			KelasNama:  siswa.KelasNama,
+			SemesterID: semesterID,
+			AmbangPoin: a.Poin,
+			Jenis:      a.Jenis,
+			TotalPoin:  poin.TotalPoin,
+			Status:     model.SanksiStatusPending,
+			Catatan:    a.Keterangan,
+		}
+		// The letter number is only kept when the sanksi is stored with it
+		_, err := d.databasePort.DoInTransaction(func(tx outbound_port.DatabasePort) (interface{}, error) {
+			urut, err := tx.Sekolah().NextNomorSanksi(tenantID, now.Year())
+			if err != nil {
+				return nil, err
+			}
+			s.NomorSurat = NomorSurat(urut, a.Jenis, now)
+			created, err := tx.Sekolah().CreateSanksi(&s)
+			if err != nil {
+				return nil, err
+			}
+			if !created {
+				return nil, errSanksiSudahAda
+			}
+			return nil, nil
+		})
+		if errors.Is(err, errSanksiSudahAda) {
+			continue
+		}
+		if err != nil {
+			return baru, err
+		}
+		if err := d.kabariWali(ctx, &s, siswa); err != nil {
+			log.WithContext(ctx).WithError(err).Errorf("Failed to notify wali of sanksi %s", s.ID)
+		}
+		baru = append(baru, s)
+	}
+	return baru, nil
+}
+
+// kabariWali sends the sanction to the santri's wali by WhatsApp and records when it was sent
+func (d *akademikDomain) kabariWali(ctx context.Context, s *model.Sanksi, siswa *model.Siswa) error {
+	if d.messagePort == nil || d.messagePort.WhatsApp() == nil {
+		return errors.New("layanan WhatsApp tidak tersedia")
+	}
+	if siswa.NoHPWali == "" {
+		return errors.New("nomor HP wali santri belum diisi")
+	}
+	wali := siswa.NamaWali
+	if wali == "" {
+		wali = "Bapak/Ibu wali santri"
+	}
+	message := fmt.Sprintf(
+		"Assalamu'alaikum, %s.\n\nKami sampaikan bahwa ananda %s telah mencapai %d poin pelanggaran pada semester %s dan mendapat sanksi %s (surat no. %s).\n\nMohon kerja sama Bapak/Ibu dalam pembinaan ananda. Terima kasih.",
+		wali, siswa.Nama, s.TotalPoin, semester.Label(s.SemesterID), s.Jenis, s.NomorSurat,
+	)
+	if err := d.messagePort.WhatsApp().Send(siswa.NoHPWali, message); err != nil {
+		return err
+	}
+	now := time.Now()
+	s.WaliDikabariAt = &now
+	return d.databasePort.Sekolah().UpdateSanksi(s)
+}
+
+// CreatePoinKredit records a good deed that takes points off the santri's semester total.
+// Sanctions already raised stay in place.
+func (d *akademikDomain) CreatePoinKredit(ctx context.Context, tenantID string, m *model.PoinKredit) error {
+	m.TenantID = tenantID
+	m.Keterangan = strings.TrimSpace(m.Keterangan)
+	if m.Poin <= 0 || m.Keterangan == "" {
+		return ErrKreditTidakSah
+	}
+	if m.Tanggal.IsZero() {
+		m.Tanggal = time.Now()
+	}
+	siswa, err := d.databasePort.Sekolah().GetSiswaByID(tenantID, m.SantriID)
+	if err != nil {
+		return err
+	}
+	if siswa == nil {
+		return ErrSantriNotFound
+	}
+	m.SantriNama = siswa.Nama
+	return d.databasePort.Sekolah().CreatePoinKredit(m)
+}
+
+func (d *akademikDomain) GetSanksiList(ctx context.Context, tenantID, semesterID, status string) ([]model.Sanksi, error) {
+	list, err := d.databasePort.Sekolah().GetSanksi(tenantID, "", semesterID, status)
+	if list == nil && err == nil {
+		list = []model.Sanksi{}
+	}
+	return list, err
+}
+
+func (d *akademikDomain) sanksiByID(tenantID, id string) (*model.Sanksi, error) {
+	s, err := d.databasePort.Sekolah().GetSanksiByID(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		return nil, ErrSanksiNotFound
+	}
+	return s, nil
+}
+
+// UpdateSanksiStatus moves a sanction forward: Pending, Diproses, Selesai
+func (d *akademikDomain) UpdateSanksiStatus(ctx context.Context, tenantID, id, status, catatan string) (*model.Sanksi, error) {
+	s, err := d.sanksiByID(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	urutan := map[string]int{model.SanksiStatusPending: 0, model.SanksiStatusDiproses: 1, model.SanksiStatusSelesai: 2}
+	next, ok := urutan[status]
+	if !ok || next < urutan[s.Status] {
+		return nil, ErrStatusSanksiTidakSah
+	}
+	s.Status = status
+	if catatan = strings.TrimSpace(catatan); catatan != "" {
+		s.Catatan = catatan
+	}
+	if status == model.SanksiStatusSelesai && s.SelesaiAt == nil {
+		now := time.Now()
+		s.SelesaiAt = &now
+	}
+	if err := d.databasePort.Sekolah().UpdateSanksi(s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// KirimSanksiWali resends a sanction to the wali
+func (d *akademikDomain) KirimSanksiWali(ctx context.Context, tenantID, id string) error {
+	s, err := d.sanksiByID(tenantID, id)
+	if err != nil {
+		return err
+	}
+	siswa, err := d.databasePort.Sekolah().GetSiswaByID(tenantID, s.SantriID)
+	if err != nil {
+		return err
+	}
+	if siswa == nil {
+		return ErrSantriNotFound
+	}
+	return d.kabariWali(ctx, s, siswa)
+}
+
+// CetakSuratPeringatan renders the warning letter of a sanction with the semester's violations
+func (d *akademikDomain) CetakSuratPeringatan(ctx context.Context, tenantID, id string) ([]byte, error) {
+	s, err := d.sanksiByID(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	siswa, err := d.databasePort.Sekolah().GetSiswaByID(tenantID, s.SantriID)
+	if err != nil {
+		return nil, err
+	}
+	if siswa == nil {
+		return nil, ErrSantriNotFound
+	}
+	start, end, _ := semester.Range(s.SemesterID)
+	poin, err := d.poinSantri(siswa, s.SemesterID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	profil, err := d.databasePort.Sekolah().GetProfil(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	surat := &model.SuratPeringatan{
+		Sanksi:      *s,
+		Semester:    semester.Label(s.SemesterID),
+		Keterangan:  s.Catatan,
+		NIS:         siswa.NIS,
+		NamaWali:    siswa.NamaWali,
+		Pelanggaran: poin.Pelanggaran,
+		Kredit:      poin.Kredit,
+		Tanggal:     s.CreatedAt,
+	}
+	if profil != nil {
+		surat.NamaLembaga, surat.Alamat = profil.NamaPesantren, profil.Alamat
+	}
+	return pdf_utils.GenerateSuratPeringatanPDF(surat)
+}
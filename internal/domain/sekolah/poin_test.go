@@ -0,0 +1,49 @@
+package sekolah_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain/sekolah"
+	"prabogo/internal/model"
+)
+
+func TestPoinPelanggaran(t *testing.T) {
+	ambang := model.DefaultSanksiAmbang()
+
+	Convey("Test TotalPoin never drops below zero", t, func() {
+		So(sekolah.TotalPoin(60, 15), ShouldEqual, 45)
+		So(sekolah.TotalPoin(10, 25), ShouldEqual, 0)
+	})
+
+	Convey("Test SanksiTercapai", t, func() {
+		Convey("Raises every threshold reached at once, lowest first", func() {
+			reached := sekolah.SanksiTercapai(ambang, 55, nil)
+			So(reached, ShouldHaveLength, 2)
+			So(reached[0].Jenis, ShouldEqual, model.SanksiTeguran)
+			So(reached[1].Jenis, ShouldEqual, model.SanksiSP1)
+		})
+
+		Convey("Skips thresholds already sanctioned in the semester", func() {
+			reached := sekolah.SanksiTercapai(ambang, 100, map[int]bool{25: true, 50: true})
+			So(reached, ShouldHaveLength, 2)
+			So(reached[0].Jenis, ShouldEqual, model.SanksiSP2)
+			So(reached[1].Jenis, ShouldEqual, model.SanksiPanggilanWali)
+			So(sekolah.SanksiTercapai(ambang, 24, nil), ShouldBeEmpty)
+		})
+	})
+
+	Convey("Test AmbangBerikutnya", t, func() {
+		So(*sekolah.AmbangBerikutnya(ambang, 30), ShouldEqual, 50)
+		So(*sekolah.AmbangBerikutnya(ambang, 0), ShouldEqual, 25)
+		So(sekolah.AmbangBerikutnya(ambang, 100), ShouldBeNil)
+	})
+
+	Convey("Test NomorSurat", t, func() {
+		day := time.Date(2025, time.October, 3, 0, 0, 0, 0, time.UTC)
+		So(sekolah.NomorSurat(7, model.SanksiSP1, day), ShouldEqual, "007/SP1/X/2025")
+		So(sekolah.NomorSurat(12, model.SanksiPanggilanWali, day), ShouldEqual, "012/PANGGILAN-WALI/X/2025")
+	})
+}
@@ -0,0 +1,133 @@
+package quran
+
+import "sort"
+
+// Hafalan is the set of ayat a santri has memorised, kept as merged ranges of mushaf indexes so
+// overlapping setoran count once
+type Hafalan struct {
+	rentang [][2]int // [awal, akhir] inclusive, sorted and disjoint
+}
+
+// Tambah adds ayat awal..akhir of a surah. The range must be valid.
+func (h *Hafalan) Tambah(surah, awal, akhir int) {
+	h.tambah(Indeks(surah, awal), Indeks(surah, akhir))
+}
+
+// TambahJuz adds a whole juz
+func (h *Hafalan) TambahJuz(juz int) {
+	h.tambah(offsetJuz[juz-1], offsetJuz[juz]-1)
+}
+
+func (h *Hafalan) tambah(awal, akhir int) {
+	r := [2]int{awal, akhir}
+	i := sort.Search(len(h.rentang), func(i int) bool { return h.rentang[i][1]+1 >= r[0] })
+	j := i
+	for j < len(h.rentang) && h.rentang[j][0] <= r[1]+1 {
+		r[0] = min(r[0], h.rentang[j][0])
+		r[1] = max(r[1], h.rentang[j][1])
+		j++
+	}
+	h.rentang = append(h.rentang[:i], append([][2]int{r}, h.rentang[j:]...)...)
+}
+
+// JumlahAyat is the number of distinct ayat memorised
+func (h *Hafalan) JumlahAyat() int {
+	total := 0
+	for _, r := range h.rentang {
+		total += r[1] - r[0] + 1
+	}
+	return total
+}
+
+// AyatPerJuz counts the memorised ayat of each juz; index 0 is juz 1
+func (h *Hafalan) AyatPerJuz() [30]int {
+	var perJuz [30]int
+	for _, r := range h.rentang {
+		for juz := 1; juz <= 30; juz++ {
+			awal, akhir := max(r[0], offsetJuz[juz-1]), min(r[1], offsetJuz[juz]-1)
+			if awal <= akhir {
+				perJuz[juz-1] += akhir - awal + 1
+			}
+		}
+	}
+	return perJuz
+}
+
+// Halaman estimates the memorised pages of the Madinah mushaf, counting each juz's pages in
+// proportion to the share of its ayat memorised
+func (h *Hafalan) Halaman() float64 {
+	var halaman float64
+	for i, n := range h.AyatPerJuz() {
+		halaman += float64(n) / float64(AyatJuz(i+1)) * halamanJuz(i+1)
+	}
+	return halaman
+}
+
+// Bagian is a continuous range of ayat within one surah
+type Bagian struct {
+	Surah     int
+	AyatAwal  int
+	AyatAkhir int
+}
+
+// Porsi is one share of the hafalan in mushaf order
+type Porsi struct {
+	Bagian  []Bagian
+	Ayat    int
+	Halaman float64
+}
+
+// Bagi splits the hafalan in mushaf order into at most n portions of about the same number of
+// pages, each ayat weighing its juz's share of pages. Fewer portions come back when there are
+// fewer ayat than n.
+func (h *Hafalan) Bagi(n int) []Porsi {
+	if n < 1 {
+		return nil
+	}
+	var total float64
+	for _, r := range h.rentang {
+		for idx := r[0]; idx <= r[1]; idx++ {
+			total += bobotAyat(idx)
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+
+	per := total / float64(n)
+	porsi := make([]Porsi, n)
+	var kumulatif float64
+	for _, r := range h.rentang {
+		for idx := r[0]; idx <= r[1]; idx++ {
+			w := bobotAyat(idx)
+			// An ayat goes to the portion its midpoint falls in
+			k := min(int((kumulatif+w/2)/per), n-1)
+			kumulatif += w
+
+			p := &porsi[k]
+			p.Ayat++
+			p.Halaman += w
+			letak := LetakIndeks(idx)
+			if last := len(p.Bagian) - 1; last >= 0 && p.Bagian[last].Surah == letak.Surah && p.Bagian[last].AyatAkhir+1 == letak.Ayat {
+				p.Bagian[last].AyatAkhir = letak.Ayat
+			} else {
+				p.Bagian = append(p.Bagian, Bagian{Surah: letak.Surah, AyatAwal: letak.Ayat, AyatAkhir: letak.Ayat})
+			}
+		}
+	}
+
+	result := porsi[:0]
+	for _, p := range porsi {
+		if p.Ayat > 0 {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// bobotAyat is the share of a page one ayat takes in its juz
+func bobotAyat(idx int) float64 {
+	l := LetakIndeks(idx)
+	juz := JuzDari(l.Surah, l.Ayat)
+	return halamanJuz(juz) / float64(AyatJuz(juz))
+}
@@ -0,0 +1,99 @@
+package quran
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrSurahTidakDikenal = errors.New("nama surah tidak dikenal")
+	ErrAyatTidakSah      = errors.New("rentang ayat tidak sah")
+)
+
+// Articles the Indonesian transliteration puts before surah names, longest first
+var sandang = []string{"asy", "adz", "ash", "ats", "al", "an", "ar", "as", "at", "ad", "az"}
+
+// Spelling variants folded onto the form used in daftarSurah
+var ejaan = strings.NewReplacer("sh", "sy", "th", "t", "dh", "d", "dz", "z", "ts", "s", "gh", "g", "ee", "i", "oo", "u")
+
+var indeksNama map[string]int
+
+func buildIndeksNama() {
+	indeksNama = make(map[string]int, len(daftarSurah)*2)
+	for _, s := range daftarSurah {
+		key := kunciNama(s.Nama)
+		indeksNama[key] = s.Nomor
+		if bare := tanpaSandang(key); bare != key {
+			if _, taken := indeksNama[bare]; !taken {
+				indeksNama[bare] = s.Nomor
+			}
+		}
+	}
+}
+
+// kunciNama reduces a surah name to a comparison key: letters only, lower case, common spelling
+// variants folded, doubled letters collapsed and a trailing h dropped, so "Al-Muzzammil",
+// "al muzammil" and "Al Muzammil" all match
+func kunciNama(nama string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(nama) {
+		if unicode.IsLetter(r) && r < unicode.MaxASCII {
+			b.WriteRune(r)
+		}
+	}
+	key := ejaan.Replace(b.String())
+
+	var out []byte
+	for i := 0; i < len(key); i++ {
+		if i > 0 && key[i] == key[i-1] {
+			continue
+		}
+		out = append(out, key[i])
+	}
+	return strings.TrimSuffix(string(out), "h")
+}
+
+func tanpaSandang(key string) string {
+	for _, s := range sandang {
+		if strings.HasPrefix(key, s) && len(key) > len(s)+1 {
+			return key[len(s):]
+		}
+	}
+	return key
+}
+
+// CariSurah finds a surah by its number or by its name in a common transliteration, with or
+// without the article ("Al-Baqarah", "al baqarah", "Baqarah")
+func CariSurah(nama string) (Surah, bool) {
+	nama = strings.TrimSpace(nama)
+	if nomor, err := strconv.Atoi(nama); err == nil {
+		return SurahKe(nomor)
+	}
+	key := kunciNama(nama)
+	if key == "" {
+		return Surah{}, false
+	}
+	if nomor, ok := indeksNama[key]; ok {
+		return daftarSurah[nomor-1], true
+	}
+	if nomor, ok := indeksNama[tanpaSandang(key)]; ok {
+		return daftarSurah[nomor-1], true
+	}
+	return Surah{}, false
+}
+
+// ValidasiRentang checks that ayat awal..akhir exist in the surah
+func ValidasiRentang(surah Surah, awal, akhir int) error {
+	switch {
+	case awal < 1:
+		return fmt.Errorf("%w: ayat awal minimal 1", ErrAyatTidakSah)
+	case akhir < awal:
+		return fmt.Errorf("%w: ayat akhir %d sebelum ayat awal %d", ErrAyatTidakSah, akhir, awal)
+	case akhir > surah.JumlahAyat:
+		return fmt.Errorf("%w: surah %s hanya memiliki %d ayat", ErrAyatTidakSah, surah.Nama, surah.JumlahAyat)
+	}
+	return nil
+}
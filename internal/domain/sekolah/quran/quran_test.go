@@ -0,0 +1,96 @@
+package quran_test
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain/sekolah/quran"
+)
+
+func TestQuran(t *testing.T) {
+	Convey("Test metadata", t, func() {
+		total := 0
+		for _, s := range quran.DaftarSurah() {
+			total += s.JumlahAyat
+		}
+		So(total, ShouldEqual, quran.TotalAyat)
+		So(quran.AyatJuz(1), ShouldEqual, 148)
+		So(quran.AyatJuz(30), ShouldEqual, 564)
+		So(quran.JuzDari(2, 141), ShouldEqual, 1)
+		So(quran.JuzDari(2, 142), ShouldEqual, 2)
+		So(quran.JuzDari(114, 6), ShouldEqual, 30)
+		So(quran.LetakIndeks(quran.Indeks(18, 75)), ShouldResemble, quran.Letak{Surah: 18, Ayat: 75})
+	})
+
+	Convey("Test CariSurah", t, func() {
+		for _, nama := range []string{"Al-Baqarah", "al baqarah", "Baqarah", "2"} {
+			s, ok := quran.CariSurah(nama)
+			So(ok, ShouldBeTrue)
+			So(s.Nomor, ShouldEqual, 2)
+		}
+		for nama, nomor := range map[string]int{
+			"Al Muzammil": 73, "Ash-Shams": 91, "Yaasiin": 36, "An-Nas": 114,
+			"An Nasr": 110, "al-'asr": 103, "Al-Mudatsir": 74, "Thaha": 20,
+		} {
+			s, ok := quran.CariSurah(nama)
+			So(ok, ShouldBeTrue)
+			So(s.Nomor, ShouldEqual, nomor)
+		}
+		_, ok := quran.CariSurah("Al-Kitab")
+		So(ok, ShouldBeFalse)
+	})
+
+	Convey("Test ValidasiRentang", t, func() {
+		fatihah, _ := quran.SurahKe(1)
+		So(quran.ValidasiRentang(fatihah, 1, 7), ShouldBeNil)
+		So(quran.ValidasiRentang(fatihah, 1, 8), ShouldWrap, quran.ErrAyatTidakSah)
+		So(quran.ValidasiRentang(fatihah, 5, 3), ShouldWrap, quran.ErrAyatTidakSah)
+		So(quran.ValidasiRentang(fatihah, 0, 3), ShouldWrap, quran.ErrAyatTidakSah)
+	})
+
+	Convey("Test Hafalan counts overlapping setoran once", t, func() {
+		var h quran.Hafalan
+		h.Tambah(78, 1, 20)
+		h.Tambah(78, 10, 40)
+		h.Tambah(1, 1, 7)
+		h.Tambah(79, 1, 46)
+		So(h.JumlahAyat(), ShouldEqual, 40+7+46)
+
+		perJuz := h.AyatPerJuz()
+		So(perJuz[0], ShouldEqual, 7)
+		So(perJuz[29], ShouldEqual, 86)
+
+		var juz30 quran.Hafalan
+		for nomor := 78; nomor <= 114; nomor++ {
+			s, _ := quran.SurahKe(nomor)
+			juz30.Tambah(nomor, 1, s.JumlahAyat)
+		}
+		So(juz30.JumlahAyat(), ShouldEqual, 564)
+		So(juz30.Halaman(), ShouldAlmostEqual, 23, 0.001)
+	})
+
+	Convey("Test Hafalan.Bagi splits by pages in mushaf order", t, func() {
+		var juz30 quran.Hafalan
+		juz30.TambahJuz(30)
+		porsi := juz30.Bagi(7)
+		So(porsi, ShouldHaveLength, 7)
+		So(porsi[0].Bagian[0], ShouldResemble, quran.Bagian{Surah: 78, AyatAwal: 1, AyatAkhir: 40})
+
+		ayat, halaman := 0, 0.0
+		for _, p := range porsi {
+			ayat += p.Ayat
+			halaman += p.Halaman
+			So(p.Halaman, ShouldAlmostEqual, 23.0/7, 0.1)
+		}
+		So(ayat, ShouldEqual, 564)
+		So(halaman, ShouldAlmostEqual, 23, 0.001)
+		last := porsi[6].Bagian[len(porsi[6].Bagian)-1]
+		So(last, ShouldResemble, quran.Bagian{Surah: 114, AyatAwal: 1, AyatAkhir: 6})
+
+		var pendek quran.Hafalan
+		pendek.Tambah(1, 1, 3)
+		So(pendek.Bagi(7), ShouldHaveLength, 3)
+		So(new(quran.Hafalan).Bagi(7), ShouldBeEmpty)
+	})
+}
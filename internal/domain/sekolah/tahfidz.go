@@ -0,0 +1,221 @@
+package sekolah
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"prabogo/internal/domain/sekolah/quran"
+	"prabogo/internal/model"
+)
+
+var (
+	ErrSetoranTidakSah = errors.New("setoran tidak sah")
+	ErrSantriNotFound  = errors.New("santri tidak ditemukan")
+)
+
+// NormalizeSetoran checks a setoran against the mushaf before it is stored. The surah name is
+// replaced by its standard spelling, a setoran of a whole surah may leave the ayat empty, and the
+// juz is filled from the ayat range when missing. A setoran without surah covers a whole juz.
+func NormalizeSetoran(m *model.TahfidzSetoran) error {
+	m.Surah = strings.TrimSpace(m.Surah)
+	if m.Surah == "" {
+		if m.AyatAwal != 0 || m.AyatAkhir != 0 {
+			return fmt.Errorf("%w: surah wajib diisi bila ayat diisi", ErrSetoranTidakSah)
+		}
+		if m.Juz < 1 || m.Juz > 30 {
+			return fmt.Errorf("%w: juz harus di antara 1 dan 30", ErrSetoranTidakSah)
+		}
+		return nil
+	}
+
+	surah, ok := quran.CariSurah(m.Surah)
+	if !ok {
+		return fmt.Errorf("%w: %w %q", ErrSetoranTidakSah, quran.ErrSurahTidakDikenal, m.Surah)
+	}
+	m.Surah = surah.Nama
+	if m.AyatAwal == 0 && m.AyatAkhir == 0 {
+		m.AyatAwal, m.AyatAkhir = 1, surah.JumlahAyat
+	}
+	if err := quran.ValidasiRentang(surah, m.AyatAwal, m.AyatAkhir); err != nil {
+		return fmt.Errorf("%w: %w", ErrSetoranTidakSah, err)
+	}
+
+	juzAwal, juzAkhir := quran.JuzDari(surah.Nomor, m.AyatAwal), quran.JuzDari(surah.Nomor, m.AyatAkhir)
+	if m.Juz == 0 {
+		m.Juz = juzAwal
+	} else if m.Juz < juzAwal || m.Juz > juzAkhir {
+		return fmt.Errorf("%w: %s ayat %d-%d berada di juz %d, bukan juz %d", ErrSetoranTidakSah, surah.Nama, m.AyatAwal, m.AyatAkhir, juzAwal, m.Juz)
+	}
+	return nil
+}
+
+// HitungProgress accumulates the hafalan of one santri from their ziyadah setoran. Setoran the
+// ustadz asked to repeat ("Ulang") do not count; a setoran of a whole juz counts the full juz.
+func HitungProgress(santriID string, setoran []model.TahfidzSetoran) model.TahfidzProgress {
+	progress := model.TahfidzProgress{SantriID: santriID, JuzSelesai: []int{}, PerJuz: []model.TahfidzJuzProgress{}}
+	for i := range setoran {
+		s := &setoran[i]
+		if !ziyadahSantri(s, santriID) {
+			continue
+		}
+		if s.SantriNama != "" {
+			progress.SantriNama = s.SantriNama
+		}
+		if progress.SetoranTerakhir == nil || !s.Tanggal.Before(*progress.SetoranTerakhir) {
+			tanggal := s.Tanggal
+			progress.SetoranTerakhir = &tanggal
+			progress.UstadzID, progress.UstadzNama = s.UstadzID, s.UstadzNama
+		}
+	}
+	hafalan := susunHafalan(santriID, setoran)
+
+	progress.TotalAyat = hafalan.JumlahAyat()
+	progress.Halaman = bulatkan(hafalan.Halaman())
+	progress.Persen = bulatkan(float64(progress.TotalAyat) / quran.TotalAyat * 100)
+	var juzSetara float64
+	for i, n := range hafalan.AyatPerJuz() {
+		if n == 0 {
+			continue
+		}
+		total := quran.AyatJuz(i + 1)
+		juzSetara += float64(n) / float64(total)
+		progress.PerJuz = append(progress.PerJuz, model.TahfidzJuzProgress{
+			Juz:       i + 1,
+			Ayat:      n,
+			TotalAyat: total,
+			Persen:    bulatkan(float64(n) / float64(total) * 100),
+		})
+		if n == total {
+			progress.JuzSelesai = append(progress.JuzSelesai, i+1)
+		}
+	}
+	progress.JuzSetara = bulatkan(juzSetara)
+	return progress
+}
+
+func ziyadahSantri(s *model.TahfidzSetoran, santriID string) bool {
+	return s.SantriID == santriID && strings.EqualFold(s.Tipe, "ziyadah")
+}
+
+// susunHafalan collects the ayat a santri's accepted ziyadah cover
+func susunHafalan(santriID string, setoran []model.TahfidzSetoran) quran.Hafalan {
+	var hafalan quran.Hafalan
+	for i := range setoran {
+		s := &setoran[i]
+		if !ziyadahSantri(s, santriID) || strings.EqualFold(strings.TrimSpace(s.Kualitas), "ulang") {
+			continue
+		}
+		if s.Surah == "" {
+			if s.Juz >= 1 && s.Juz <= 30 {
+				hafalan.TambahJuz(s.Juz)
+			}
+			continue
+		}
+		// Older setoran were stored as free text; skip what cannot be placed in the mushaf
+		surah, ok := quran.CariSurah(s.Surah)
+		if !ok || quran.ValidasiRentang(surah, s.AyatAwal, s.AyatAkhir) != nil {
+			continue
+		}
+		hafalan.Tambah(surah.Nomor, s.AyatAwal, s.AyatAkhir)
+	}
+	return hafalan
+}
+
+// RankProgress orders santri by memorised ayat, then by name; equal ayat share a rank
+func RankProgress(list []model.TahfidzProgress) {
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].TotalAyat != list[j].TotalAyat {
+			return list[i].TotalAyat > list[j].TotalAyat
+		}
+		return list[i].SantriNama < list[j].SantriNama
+	})
+	for i := range list {
+		if i > 0 && list[i].TotalAyat == list[i-1].TotalAyat {
+			list[i].Peringkat = list[i-1].Peringkat
+		} else {
+			list[i].Peringkat = i + 1
+		}
+	}
+}
+
+func (d *akademikDomain) GetTahfidzProgress(ctx context.Context, tenantID, santriID string) (*model.TahfidzProgress, error) {
+	siswa, err := d.databasePort.Sekolah().GetSiswaByID(tenantID, santriID)
+	if err != nil {
+		return nil, err
+	}
+	if siswa == nil {
+		return nil, ErrSantriNotFound
+	}
+	setoran, err := d.databasePort.Sekolah().GetTahfidzZiyadah(tenantID, santriID)
+	if err != nil {
+		return nil, err
+	}
+	progress := HitungProgress(santriID, setoran)
+	progress.SantriNama = siswa.Nama
+	return &progress, nil
+}
+
+// GetTahfidzLeaderboard ranks the santri of each active halaqah, or of one halaqah. Without a
+// halaqah filter, santri with ziyadah but no halaqah are ranked on a board of their own.
+func (d *akademikDomain) GetTahfidzLeaderboard(ctx context.Context, tenantID, halaqahID string) ([]model.TahfidzLeaderboard, error) {
+	anggota, err := d.databasePort.Sekolah().GetHalaqahAnggota(tenantID, halaqahID)
+	if err != nil {
+		return nil, err
+	}
+	setoran, err := d.databasePort.Sekolah().GetTahfidzZiyadah(tenantID, "")
+	if err != nil {
+		return nil, err
+	}
+	bySantri := make(map[string][]model.TahfidzSetoran)
+	var order []string
+	for _, s := range setoran {
+		if _, seen := bySantri[s.SantriID]; !seen {
+			order = append(order, s.SantriID)
+		}
+		bySantri[s.SantriID] = append(bySantri[s.SantriID], s)
+	}
+
+	boards := []model.TahfidzLeaderboard{}
+	index := make(map[string]int)
+	member := make(map[string]bool, len(anggota))
+	for _, a := range anggota {
+		i, ok := index[a.HalaqahID]
+		if !ok {
+			i = len(boards)
+			index[a.HalaqahID] = i
+			boards = append(boards, model.TahfidzLeaderboard{HalaqahID: a.HalaqahID, HalaqahNama: a.HalaqahNama, UstadzNama: a.UstadzNama})
+		}
+		progress := HitungProgress(a.SantriID, bySantri[a.SantriID])
+		progress.SantriNama = a.SantriNama
+		boards[i].Santri = append(boards[i].Santri, progress)
+		member[a.SantriID] = true
+	}
+	if halaqahID == "" {
+		var lain model.TahfidzLeaderboard
+		for _, santriID := range order {
+			if !member[santriID] {
+				lain.Santri = append(lain.Santri, HitungProgress(santriID, bySantri[santriID]))
+			}
+		}
+		if len(lain.Santri) > 0 {
+			boards = append(boards, lain)
+		}
+	}
+	for i := range boards {
+		RankProgress(boards[i].Santri)
+	}
+	return boards, nil
+}
+
+// GetDaftarSurah lists the surah with their ayat count for setoran forms
+func (d *akademikDomain) GetDaftarSurah() []quran.Surah {
+	return quran.DaftarSurah()
+}
+
+func bulatkan(v float64) float64 {
+	return math.Round(v*100) / 100
+}
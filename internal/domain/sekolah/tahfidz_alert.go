@@ -0,0 +1,150 @@
+package sekolah
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"prabogo/internal/model"
+	"prabogo/utils/log"
+)
+
+// hariUlangAlert is how long an alert of the same kind is not repeated for a santri
+const hariUlangAlert = 7
+
+// DeteksiAlert finds the halaqah santri who need attention: those without any setoran for more
+// than their halaqah's batas hari, counted from their latest setoran or from joining when that is
+// later, and those behind their target.
+func DeteksiAlert(anggota []model.HalaqahAnggota, terakhir map[string]time.Time, status map[string]model.TahfidzTargetStatus, now time.Time) []model.TahfidzAlert {
+	alerts := []model.TahfidzAlert{}
+	for _, a := range anggota {
+		alert := model.TahfidzAlert{
+			TenantID:    a.TenantID,
+			SantriID:    a.SantriID,
+			SantriNama:  a.SantriNama,
+			HalaqahID:   a.HalaqahID,
+			HalaqahNama: a.HalaqahNama,
+		}
+
+		sejak := a.BergabungAt
+		if t, ok := terakhir[a.SantriID]; ok && t.After(sejak) {
+			sejak = t
+		}
+		if n := selisihHari(sejak, now); a.BatasHariTanpaSetoran > 0 && n > a.BatasHariTanpaSetoran {
+			alert.Jenis = model.TahfidzAlertTanpaSetoran
+			alert.Pesan = fmt.Sprintf("%s (halaqah %s) belum menyetorkan hafalan selama %d hari.", a.SantriNama, a.HalaqahNama, n)
+			alerts = append(alerts, alert)
+		}
+
+		if s, ok := status[a.SantriID]; ok && s.Status == model.TahfidzTargetTertinggal {
+			alert.Jenis = model.TahfidzAlertTertinggal
+			alert.Pesan = fmt.Sprintf("%s (halaqah %s) tertinggal dari target hafalan: baru %.2f juz dari %.2f juz yang diharapkan saat ini, dengan target %.1f juz hingga %s.",
+				a.SantriNama, a.HalaqahNama, s.Tercapai, s.Diharapkan, s.TargetJuz, s.Selesai.Format("02-01-2006"))
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts
+}
+
+// GetTahfidzAlerts lists the santri of the tenant's halaqah who need attention today
+func (d *akademikDomain) GetTahfidzAlerts(ctx context.Context, tenantID string) ([]model.TahfidzAlert, error) {
+	alerts, _, err := d.tahfidzAlerts(tenantID, time.Now())
+	return alerts, err
+}
+
+// tahfidzAlerts detects the alerts of a tenant, or of every tenant when tenantID is empty, and
+// returns the memberships they came from keyed by santri
+func (d *akademikDomain) tahfidzAlerts(tenantID string, now time.Time) ([]model.TahfidzAlert, map[string]model.HalaqahAnggota, error) {
+	anggota, err := d.databasePort.Sekolah().GetHalaqahAnggota(tenantID, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(anggota) == 0 {
+		return []model.TahfidzAlert{}, nil, nil
+	}
+	terakhir, err := d.databasePort.Sekolah().GetTahfidzSetoranTerakhir(tenantID)
+	if err != nil {
+		return nil, nil, err
+	}
+	targets, err := d.databasePort.Sekolah().GetTahfidzTargetAktif(tenantID, now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byTenant := make(map[string][]model.TargetHafalan)
+	for _, t := range targets {
+		byTenant[t.TenantID] = append(byTenant[t.TenantID], t)
+	}
+	status := make(map[string]model.TahfidzTargetStatus, len(targets))
+	for tenant, list := range byTenant {
+		statuses, err := d.statusTargets(tenant, list, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, s := range statuses {
+			status[s.SantriID] = s
+		}
+	}
+
+	bySantri := make(map[string]model.HalaqahAnggota, len(anggota))
+	for _, a := range anggota {
+		bySantri[a.SantriID] = a
+	}
+	return DeteksiAlert(anggota, terakhir, status, now), bySantri, nil
+}
+
+// KirimTahfidzAlerts sends each alert by WhatsApp to the halaqah's ustadz and the santri's wali.
+// An alert already sent in the past week is not repeated. An empty tenantID processes every
+// tenant (used by the scheduler).
+func (d *akademikDomain) KirimTahfidzAlerts(ctx context.Context, tenantID string) (int, error) {
+	if d.messagePort == nil || d.messagePort.WhatsApp() == nil {
+		return 0, errors.New("layanan WhatsApp tidak tersedia")
+	}
+
+	now := time.Now()
+	alerts, anggota, err := d.tahfidzAlerts(tenantID, now)
+	if err != nil {
+		return 0, err
+	}
+	if len(alerts) == 0 {
+		return 0, nil
+	}
+	terkirim, err := d.databasePort.Sekolah().GetTahfidzAlertTerkirim(tenantID, now.AddDate(0, 0, -hariUlangAlert))
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := range alerts {
+		alert := &alerts[i]
+		if terkirim[alert.SantriID+"|"+alert.Jenis] {
+			continue
+		}
+		a := anggota[alert.SantriID]
+		recipients := []struct{ nama, noHP string }{
+			{"Ustadz/Ustadzah " + a.UstadzNama, a.UstadzNoHP},
+			{"Bapak/Ibu wali santri", a.NoHPWali},
+		}
+		delivered := false
+		for _, r := range recipients {
+			if r.noHP == "" {
+				continue
+			}
+			message := fmt.Sprintf("Assalamu'alaikum, %s.\n\n%s\n\nMohon perhatian dan pendampingannya. Terima kasih.", r.nama, alert.Pesan)
+			if err := d.messagePort.WhatsApp().Send(r.noHP, message); err != nil {
+				log.WithContext(ctx).WithError(err).Errorf("Failed to send tahfidz alert %s for santri %s", alert.Jenis, alert.SantriID)
+				continue
+			}
+			delivered = true
+		}
+		if !delivered {
+			continue
+		}
+		if err := d.databasePort.Sekolah().SaveTahfidzAlert(alert); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
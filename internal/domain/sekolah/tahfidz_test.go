@@ -0,0 +1,150 @@
+package sekolah_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain/sekolah"
+	"prabogo/internal/model"
+)
+
+func TestTahfidz(t *testing.T) {
+	Convey("Test NormalizeSetoran", t, func() {
+		Convey("Standardises the surah and fills the juz", func() {
+			m := model.TahfidzSetoran{Surah: "al baqarah", AyatAwal: 140, AyatAkhir: 145}
+			So(sekolah.NormalizeSetoran(&m), ShouldBeNil)
+			So(m.Surah, ShouldEqual, "Al-Baqarah")
+			So(m.Juz, ShouldEqual, 1)
+		})
+
+		Convey("Takes a whole surah when the ayat are empty", func() {
+			m := model.TahfidzSetoran{Surah: "An-Naba"}
+			So(sekolah.NormalizeSetoran(&m), ShouldBeNil)
+			So(m.AyatAwal, ShouldEqual, 1)
+			So(m.AyatAkhir, ShouldEqual, 40)
+			So(m.Juz, ShouldEqual, 30)
+		})
+
+		Convey("Rejects ayat beyond the surah, unknown surah and a mismatched juz", func() {
+			So(sekolah.NormalizeSetoran(&model.TahfidzSetoran{Surah: "Al-Fatihah", AyatAwal: 1, AyatAkhir: 8}), ShouldWrap, sekolah.ErrSetoranTidakSah)
+			So(sekolah.NormalizeSetoran(&model.TahfidzSetoran{Surah: "Jurumiyah", AyatAwal: 1, AyatAkhir: 2}), ShouldWrap, sekolah.ErrSetoranTidakSah)
+			So(sekolah.NormalizeSetoran(&model.TahfidzSetoran{Surah: "Yasin", AyatAwal: 1, AyatAkhir: 10, Juz: 30}), ShouldWrap, sekolah.ErrSetoranTidakSah)
+			So(sekolah.NormalizeSetoran(&model.TahfidzSetoran{Juz: 31}), ShouldWrap, sekolah.ErrSetoranTidakSah)
+		})
+	})
+
+	Convey("Test HitungProgress", t, func() {
+		day := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+		setoran := func(surah string, awal, akhir int, kualitas string, offset int) model.TahfidzSetoran {
+			return model.TahfidzSetoran{SantriID: "s1", SantriNama: "Ahmad", Tipe: "Ziyadah", Surah: surah,
+				AyatAwal: awal, AyatAkhir: akhir, Kualitas: kualitas, Tanggal: day.AddDate(0, 0, offset)}
+		}
+
+		progress := sekolah.HitungProgress("s1", []model.TahfidzSetoran{
+			setoran("An-Naba'", 1, 20, "Lancar", 0),
+			setoran("An-Naba'", 15, 40, "Lancar", 1),
+			setoran("An-Nazi'at", 1, 46, "Ulang", 2),
+			{SantriID: "s1", Tipe: "Ziyadah", Juz: 29, Kualitas: "Lancar", Tanggal: day.AddDate(0, 0, 3)},
+			{SantriID: "s1", Tipe: "Murajaah", Surah: "Al-Fatihah", AyatAwal: 1, AyatAkhir: 7, Tanggal: day},
+		})
+		So(progress.SantriNama, ShouldEqual, "Ahmad")
+		So(progress.TotalAyat, ShouldEqual, 40+431)
+		So(progress.JuzSelesai, ShouldResemble, []int{29})
+		So(progress.PerJuz, ShouldHaveLength, 2)
+		So(progress.PerJuz[1].Ayat, ShouldEqual, 40)
+		So(progress.Halaman, ShouldBeGreaterThan, 20)
+		So(*progress.SetoranTerakhir, ShouldEqual, day.AddDate(0, 0, 3))
+	})
+
+	Convey("Test RankProgress shares ranks on equal ayat", t, func() {
+		list := []model.TahfidzProgress{
+			{SantriNama: "C", TotalAyat: 10},
+			{SantriNama: "A", TotalAyat: 50},
+			{SantriNama: "B", TotalAyat: 10},
+		}
+		sekolah.RankProgress(list)
+		So(list[0].SantriNama, ShouldEqual, "A")
+		So(list[0].Peringkat, ShouldEqual, 1)
+		So(list[1].SantriNama, ShouldEqual, "B")
+		So(list[1].Peringkat, ShouldEqual, 2)
+		So(list[2].Peringkat, ShouldEqual, 2)
+	})
+}
+
+func TestTahfidzTarget(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	juz := func(n int, tanggal time.Time) model.TahfidzSetoran {
+		return model.TahfidzSetoran{SantriID: "s1", Tipe: "Ziyadah", Juz: n, Kualitas: "Lancar", Tanggal: tanggal}
+	}
+
+	Convey("Test StatusTarget", t, func() {
+		target := model.TargetHafalan{SantriID: "s1", TargetJuz: 2, Mulai: date(2025, 7, 1), Selesai: date(2025, 12, 31)}
+		setoran := []model.TahfidzSetoran{
+			juz(30, date(2025, 6, 1)), // before the target, the baseline
+			juz(29, date(2025, 8, 1)),
+			juz(28, date(2025, 11, 1)),
+		}
+
+		Convey("Counts only hafalan added since Mulai up to now", func() {
+			status := sekolah.StatusTarget(target, setoran, date(2025, 10, 1))
+			So(status.Tercapai, ShouldEqual, 1)
+			So(status.Diharapkan, ShouldEqual, 1.01)
+			So(status.Persen, ShouldEqual, 50)
+			So(status.Status, ShouldEqual, model.TahfidzTargetSesuai)
+		})
+
+		Convey("Falls behind when short of the pace by more than a week", func() {
+			status := sekolah.StatusTarget(target, setoran[:2], date(2025, 12, 1))
+			So(status.Diharapkan, ShouldEqual, 1.67)
+			So(status.Status, ShouldEqual, model.TahfidzTargetTertinggal)
+		})
+
+		Convey("Reaches the target", func() {
+			status := sekolah.StatusTarget(target, setoran, date(2025, 12, 1))
+			So(status.Tercapai, ShouldEqual, 2)
+			So(status.Status, ShouldEqual, model.TahfidzTargetTercapai)
+		})
+	})
+
+	Convey("Test DeteksiAlert", t, func() {
+		now := date(2025, 10, 1)
+		anggota := func(id string, bergabung time.Time) model.HalaqahAnggota {
+			return model.HalaqahAnggota{SantriID: id, SantriNama: id, HalaqahNama: "Abu Bakar", BergabungAt: bergabung, BatasHariTanpaSetoran: 7}
+		}
+		alerts := sekolah.DeteksiAlert(
+			[]model.HalaqahAnggota{
+				anggota("s1", date(2025, 7, 1)),
+				anggota("s2", date(2025, 9, 28)),
+				anggota("s3", date(2025, 7, 1)),
+			},
+			map[string]time.Time{"s1": date(2025, 9, 20), "s3": date(2025, 9, 24)},
+			map[string]model.TahfidzTargetStatus{
+				"s2": {TargetHafalan: model.TargetHafalan{TargetJuz: 2, Selesai: date(2025, 12, 31)}, Status: model.TahfidzTargetTertinggal},
+				"s3": {Status: model.TahfidzTargetSesuai},
+			},
+			now,
+		)
+		So(alerts, ShouldHaveLength, 2)
+		So(alerts[0].SantriID, ShouldEqual, "s1")
+		So(alerts[0].Jenis, ShouldEqual, model.TahfidzAlertTanpaSetoran)
+		So(alerts[0].Pesan, ShouldContainSubstring, "selama 11 hari")
+		So(alerts[1].SantriID, ShouldEqual, "s2")
+		So(alerts[1].Jenis, ShouldEqual, model.TahfidzAlertTertinggal)
+	})
+
+	Convey("Test RencanaMurajaah rotates the hafalan over the cycle", t, func() {
+		setoran := []model.TahfidzSetoran{juz(30, date(2025, 6, 1))}
+		plan := sekolah.RencanaMurajaah("s1", setoran, 7, date(2025, 9, 29), date(2025, 10, 8))
+		So(plan.Siklus, ShouldEqual, 7)
+		So(plan.Porsi, ShouldHaveLength, 7)
+		So(plan.Porsi[0].Tanggal, ShouldEqual, date(2025, 10, 6))
+		So(plan.Porsi[0].Bagian[0].Surah, ShouldEqual, "An-Naba'")
+		So(plan.Porsi[2].HariIni, ShouldBeTrue)
+		So(plan.Porsi[2].Tanggal, ShouldEqual, date(2025, 10, 8))
+
+		empty := sekolah.RencanaMurajaah("s2", setoran, 7, date(2025, 9, 29), date(2025, 10, 8))
+		So(empty.Porsi, ShouldBeEmpty)
+	})
+}
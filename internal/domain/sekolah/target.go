@@ -0,0 +1,142 @@
+package sekolah
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"prabogo/internal/model"
+	"prabogo/utils/semester"
+)
+
+var (
+	ErrTargetNotFound = errors.New("target hafalan tidak ditemukan")
+	ErrTargetTidakSah = errors.New("target hafalan harus antara 0,5 dan 30 juz dengan tanggal mulai sebelum tanggal selesai")
+)
+
+// hariToleransi is how many days of pace a santri may lag before counting as behind
+const hariToleransi = 7
+
+// hari truncates t to its calendar day so day differences ignore the clock and timezone
+func hari(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func selisihHari(dari, sampai time.Time) int {
+	return int(hari(sampai).Sub(hari(dari)).Hours() / 24)
+}
+
+// StatusTarget measures a santri against their target. Hafalan from before Mulai is the
+// baseline; the expected pace grows evenly to TargetJuz on Selesai. A santri counts as behind
+// only when more than a week's worth of pace short of it.
+func StatusTarget(target model.TargetHafalan, setoran []model.TahfidzSetoran, now time.Time) model.TahfidzTargetStatus {
+	status := model.TahfidzTargetStatus{TargetHafalan: target}
+
+	var sebelum, sampai []model.TahfidzSetoran
+	for _, s := range setoran {
+		if selisihHari(target.Mulai, s.Tanggal) < 0 {
+			sebelum = append(sebelum, s)
+		}
+		if selisihHari(s.Tanggal, now) >= 0 && selisihHari(s.Tanggal, target.Selesai) >= 0 {
+			sampai = append(sampai, s)
+		}
+	}
+	awal := HitungProgress(target.SantriID, sebelum).JuzSetara
+	akhir := HitungProgress(target.SantriID, sampai).JuzSetara
+	status.Tercapai = bulatkan(max(akhir-awal, 0))
+
+	totalHari := selisihHari(target.Mulai, target.Selesai) + 1
+	berjalan := min(max(selisihHari(target.Mulai, now)+1, 0), totalHari)
+	perHari := target.TargetJuz / float64(totalHari)
+	status.Diharapkan = bulatkan(perHari * float64(berjalan))
+	if target.TargetJuz > 0 {
+		status.Persen = bulatkan(status.Tercapai / target.TargetJuz * 100)
+	}
+
+	switch {
+	case status.Tercapai >= target.TargetJuz:
+		status.Status = model.TahfidzTargetTercapai
+	case status.Tercapai+perHari*float64(hariToleransi) < status.Diharapkan:
+		status.Status = model.TahfidzTargetTertinggal
+	default:
+		status.Status = model.TahfidzTargetSesuai
+	}
+	return status
+}
+
+func (d *akademikDomain) GetTahfidzTargets(ctx context.Context, tenantID, semesterID string) ([]model.TargetHafalan, error) {
+	return d.databasePort.Sekolah().GetTahfidzTargets(tenantID, semesterID)
+}
+
+// SaveTahfidzTarget sets a santri's target for a semester, replacing the one already set. The
+// period defaults to the semester's own dates.
+func (d *akademikDomain) SaveTahfidzTarget(ctx context.Context, tenantID string, t *model.TargetHafalan) error {
+	t.TenantID = tenantID
+	t.SemesterID = strings.TrimSpace(t.SemesterID)
+	start, end, ok := semester.Range(t.SemesterID)
+	if !ok {
+		return semester.ErrTidakSah
+	}
+	if t.Mulai.IsZero() {
+		t.Mulai = start
+	}
+	if t.Selesai.IsZero() {
+		t.Selesai = end
+	}
+	if t.TargetJuz < 0.5 || t.TargetJuz > 30 || selisihHari(t.Mulai, t.Selesai) < 1 {
+		return ErrTargetTidakSah
+	}
+
+	siswa, err := d.databasePort.Sekolah().GetSiswaByID(tenantID, t.SantriID)
+	if err != nil {
+		return err
+	}
+	if siswa == nil {
+		return ErrSantriNotFound
+	}
+	t.SantriNama = siswa.Nama
+	return d.databasePort.Sekolah().SaveTahfidzTarget(t)
+}
+
+func (d *akademikDomain) DeleteTahfidzTarget(ctx context.Context, tenantID, id string) error {
+	targets, err := d.databasePort.Sekolah().GetTahfidzTargets(tenantID, "")
+	if err != nil {
+		return err
+	}
+	for _, t := range targets {
+		if t.ID == id {
+			return d.databasePort.Sekolah().DeleteTahfidzTarget(tenantID, id)
+		}
+	}
+	return ErrTargetNotFound
+}
+
+// GetTahfidzTargetStatus measures every santri with a target in the semester
+func (d *akademikDomain) GetTahfidzTargetStatus(ctx context.Context, tenantID, semesterID string) ([]model.TahfidzTargetStatus, error) {
+	targets, err := d.databasePort.Sekolah().GetTahfidzTargets(tenantID, semesterID)
+	if err != nil {
+		return nil, err
+	}
+	return d.statusTargets(tenantID, targets, time.Now())
+}
+
+func (d *akademikDomain) statusTargets(tenantID string, targets []model.TargetHafalan, now time.Time) ([]model.TahfidzTargetStatus, error) {
+	result := make([]model.TahfidzTargetStatus, 0, len(targets))
+	if len(targets) == 0 {
+		return result, nil
+	}
+	setoran, err := d.databasePort.Sekolah().GetTahfidzZiyadah(tenantID, "")
+	if err != nil {
+		return nil, err
+	}
+	bySantri := make(map[string][]model.TahfidzSetoran)
+	for _, s := range setoran {
+		bySantri[s.SantriID] = append(bySantri[s.SantriID], s)
+	}
+	for _, t := range targets {
+		result = append(result, StatusTarget(t, bySantri[t.SantriID], now))
+	}
+	return result, nil
+}
@@ -0,0 +1,448 @@
+package ujian
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	pdf_utils "prabogo/utils/pdf"
+)
+
+// UjianDomain interface
+type UjianDomain interface {
+	// Ujian
+	GetUjianList(ctx context.Context, tenantID, semesterID string) ([]model.Ujian, error)
+	GetUjianDetail(ctx context.Context, tenantID, id string) (*model.Ujian, []model.UjianJadwal, []model.UjianRuang, error)
+	CreateUjian(ctx context.Context, u *model.Ujian) error
+	UpdateUjian(ctx context.Context, u *model.Ujian) error
+
+	// Jadwal & ruang
+	AddJadwal(ctx context.Context, tenantID string, j *model.UjianJadwal) error
+	DeleteJadwal(ctx context.Context, tenantID, ujianID, jadwalID string) error
+	AddRuang(ctx context.Context, tenantID string, r *model.UjianRuang) error
+	DeleteRuang(ctx context.Context, tenantID, ujianID, ruangID string) error
+
+	// Tempat duduk
+	AturTempatDuduk(ctx context.Context, tenantID, ujianID string, tingkat []string) ([]model.UjianPeserta, error)
+	GetPeserta(ctx context.Context, tenantID, ujianID, ruangID, kelasID string) ([]model.UjianPeserta, error)
+
+	// Pengawas
+	GetPengawas(ctx context.Context, tenantID, ujianID, jadwalID string) ([]model.UjianPengawas, error)
+	AssignPengawas(ctx context.Context, tenantID, ujianID string, p *model.UjianPengawas) error
+	DeletePengawas(ctx context.Context, tenantID, ujianID, jadwalID, id string) error
+
+	// Cetak
+	CetakKartu(ctx context.Context, tenantID, ujianID, ruangID, kelasID string) ([]byte, error)
+	CetakDaftarHadir(ctx context.Context, tenantID, ujianID, ruangID, jadwalID string) ([]byte, error)
+}
+
+type ujianDomain struct {
+	db outbound_port.UjianDatabasePort
+}
+
+func NewUjianDomain(db outbound_port.UjianDatabasePort) UjianDomain {
+	return &ujianDomain{db: db}
+}
+
+var (
+	ErrUjianNotFound  = errors.New("ujian tidak ditemukan")
+	ErrJadwalNotFound = errors.New("jadwal ujian tidak ditemukan")
+	ErrRuangNotFound  = errors.New("ruang ujian tidak ditemukan")
+)
+
+// AllocateSeats spreads students over rooms so that neighbouring seats come from different classes.
+// Students are interleaved round-robin by kelas, then rooms are filled in order up to capacity.
+func AllocateSeats(siswa []model.UjianSiswa, ruang []model.UjianRuang, prefix string) ([]model.UjianPeserta, error) {
+	kapasitas := 0
+	for _, r := range ruang {
+		kapasitas += r.Kapasitas
+	}
+	if kapasitas < len(siswa) {
+		return nil, fmt.Errorf("kapasitas ruang (%d) kurang dari jumlah peserta (%d)", kapasitas, len(siswa))
+	}
+
+	// Group by kelas, keeping first-seen order
+	var order []string
+	groups := make(map[string][]model.UjianSiswa)
+	for _, s := range siswa {
+		if _, ok := groups[s.KelasID]; !ok {
+			order = append(order, s.KelasID)
+		}
+		groups[s.KelasID] = append(groups[s.KelasID], s)
+	}
+
+	mixed := make([]model.UjianSiswa, 0, len(siswa))
+	for len(mixed) < len(siswa) {
+		for _, kelasID := range order {
+			if g := groups[kelasID]; len(g) > 0 {
+				mixed = append(mixed, g[0])
+				groups[kelasID] = g[1:]
+			}
+		}
+	}
+
+	peserta := make([]model.UjianPeserta, 0, len(mixed))
+	ri, kursi := 0, 0
+	for i, s := range mixed {
+		for kursi >= ruang[ri].Kapasitas {
+			ri++
+			kursi = 0
+		}
+		kursi++
+		peserta = append(peserta, model.UjianPeserta{
+			RuangID:      ruang[ri].ID,
+			RuangNama:    ruang[ri].Nama,
+			SantriID:     s.ID,
+			KelasID:      s.KelasID,
+			Tingkat:      s.Tingkat,
+			NomorKursi:   kursi,
+			NomorPeserta: fmt.Sprintf("%s-%04d", prefix, i+1),
+		})
+	}
+	return peserta, nil
+}
+
+// ==========================================
+// UJIAN
+// ==========================================
+
+func (d *ujianDomain) getUjian(ctx context.Context, tenantID, id string) (*model.Ujian, error) {
+	u, err := d.db.GetUjianByID(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, ErrUjianNotFound
+	}
+	return u, nil
+}
+
+func (d *ujianDomain) GetUjianList(ctx context.Context, tenantID, semesterID string) ([]model.Ujian, error) {
+	return d.db.GetUjianList(ctx, tenantID, semesterID)
+}
+
+func (d *ujianDomain) GetUjianDetail(ctx context.Context, tenantID, id string) (*model.Ujian, []model.UjianJadwal, []model.UjianRuang, error) {
+	u, err := d.getUjian(ctx, tenantID, id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	jadwal, err := d.db.GetJadwalByUjian(ctx, u.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	ruang, err := d.db.GetRuangByUjian(ctx, u.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return u, jadwal, ruang, nil
+}
+
+func (d *ujianDomain) CreateUjian(ctx context.Context, u *model.Ujian) error {
+	if err := validateUjian(u); err != nil {
+		return err
+	}
+	return d.db.CreateUjian(ctx, u)
+}
+
+func (d *ujianDomain) UpdateUjian(ctx context.Context, u *model.Ujian) error {
+	if _, err := d.getUjian(ctx, u.TenantID, u.ID); err != nil {
+		return err
+	}
+	if err := validateUjian(u); err != nil {
+		return err
+	}
+	return d.db.UpdateUjian(ctx, u)
+}
+
+func validateUjian(u *model.Ujian) error {
+	if strings.TrimSpace(u.Nama) == "" || u.SemesterID == "" {
+		return errors.New("nama dan semester_id wajib diisi")
+	}
+	switch u.Jenis {
+	case model.UjianJenisPTS, model.UjianJenisPAS, model.UjianJenisPAT, model.UjianJenisUS:
+	default:
+		return fmt.Errorf("jenis ujian tidak valid: %s", u.Jenis)
+	}
+	if u.TanggalSelesai.Before(u.TanggalMulai) {
+		return errors.New("tanggal_selesai tidak boleh sebelum tanggal_mulai")
+	}
+	return nil
+}
+
+// ==========================================
+// JADWAL & RUANG
+// ==========================================
+
+func (d *ujianDomain) AddJadwal(ctx context.Context, tenantID string, j *model.UjianJadwal) error {
+	u, err := d.getUjian(ctx, tenantID, j.UjianID)
+	if err != nil {
+		return err
+	}
+	if j.SubjectID == "" || strings.TrimSpace(j.Tingkat) == "" {
+		return errors.New("subject_id dan tingkat wajib diisi")
+	}
+	if !j.WaktuSelesai.After(j.WaktuMulai) {
+		return errors.New("waktu_selesai harus setelah waktu_mulai")
+	}
+	if j.WaktuMulai.Before(u.TanggalMulai) || j.WaktuMulai.After(u.TanggalSelesai.AddDate(0, 0, 1)) {
+		return errors.New("jadwal di luar rentang tanggal ujian")
+	}
+	return d.db.CreateJadwal(ctx, j)
+}
+
+func (d *ujianDomain) DeleteJadwal(ctx context.Context, tenantID, ujianID, jadwalID string) error {
+	if _, err := d.getUjian(ctx, tenantID, ujianID); err != nil {
+		return err
+	}
+	return d.db.DeleteJadwal(ctx, ujianID, jadwalID)
+}
+
+func (d *ujianDomain) AddRuang(ctx context.Context, tenantID string, r *model.UjianRuang) error {
+	if _, err := d.getUjian(ctx, tenantID, r.UjianID); err != nil {
+		return err
+	}
+	if strings.TrimSpace(r.Nama) == "" || r.Kapasitas <= 0 {
+		return errors.New("nama dan kapasitas ruang wajib diisi")
+	}
+	return d.db.CreateRuang(ctx, r)
+}
+
+func (d *ujianDomain) DeleteRuang(ctx context.Context, tenantID, ujianID, ruangID string) error {
+	if _, err := d.getUjian(ctx, tenantID, ujianID); err != nil {
+		return err
+	}
+	return d.db.DeleteRuang(ctx, ujianID, ruangID)
+}
+
+// ==========================================
+// TEMPAT DUDUK
+// ==========================================
+
+// AturTempatDuduk (re)generates the seating plan; tingkat defaults to every tingkat that has a jadwal
+func (d *ujianDomain) AturTempatDuduk(ctx context.Context, tenantID, ujianID string, tingkat []string) ([]model.UjianPeserta, error) {
+	u, err := d.getUjian(ctx, tenantID, ujianID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tingkat) == 0 {
+		jadwal, err := d.db.GetJadwalByUjian(ctx, u.ID)
+		if err != nil {
+			return nil, err
+		}
+		seen := make(map[string]bool)
+		for _, j := range jadwal {
+			if !seen[j.Tingkat] {
+				seen[j.Tingkat] = true
+				tingkat = append(tingkat, j.Tingkat)
+			}
+		}
+	}
+	if len(tingkat) == 0 {
+		return nil, errors.New("belum ada jadwal ujian; tentukan tingkat peserta")
+	}
+
+	ruang, err := d.db.GetRuangByUjian(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(ruang) == 0 {
+		return nil, errors.New("belum ada ruang ujian")
+	}
+
+	siswa, err := d.db.GetSiswaByTingkat(ctx, tenantID, tingkat)
+	if err != nil {
+		return nil, err
+	}
+
+	peserta, err := AllocateSeats(siswa, ruang, u.Jenis)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.db.ReplacePeserta(ctx, u.ID, peserta); err != nil {
+		return nil, err
+	}
+	return d.db.GetPeserta(ctx, u.ID, "", "")
+}
+
+func (d *ujianDomain) GetPeserta(ctx context.Context, tenantID, ujianID, ruangID, kelasID string) ([]model.UjianPeserta, error) {
+	if _, err := d.getUjian(ctx, tenantID, ujianID); err != nil {
+		return nil, err
+	}
+	return d.db.GetPeserta(ctx, ujianID, ruangID, kelasID)
+}
+
+// ==========================================
+// PENGAWAS
+// ==========================================
+
+func (d *ujianDomain) GetPengawas(ctx context.Context, tenantID, ujianID, jadwalID string) ([]model.UjianPengawas, error) {
+	if _, err := d.getJadwal(ctx, tenantID, ujianID, jadwalID); err != nil {
+		return nil, err
+	}
+	return d.db.GetPengawasByJadwal(ctx, ujianID, jadwalID)
+}
+
+// AssignPengawas assigns a proctor, rejecting gurus already proctoring an overlapping session
+func (d *ujianDomain) AssignPengawas(ctx context.Context, tenantID, ujianID string, p *model.UjianPengawas) error {
+	if _, err := d.getUjian(ctx, tenantID, ujianID); err != nil {
+		return err
+	}
+	if p.GuruID == "" {
+		return errors.New("guru_id wajib diisi")
+	}
+	guru, err := d.db.GuruExists(ctx, tenantID, p.GuruID)
+	if err != nil {
+		return err
+	}
+	if !guru {
+		return errors.New("guru tidak ditemukan")
+	}
+	jadwal, err := d.db.GetJadwalByID(ctx, ujianID, p.JadwalID)
+	if err != nil {
+		return err
+	}
+	if jadwal == nil {
+		return ErrJadwalNotFound
+	}
+	ruang, err := d.db.GetRuangByID(ctx, ujianID, p.RuangID)
+	if err != nil {
+		return err
+	}
+	if ruang == nil {
+		return ErrRuangNotFound
+	}
+
+	bentrok, err := d.db.GetBentrokPengawas(ctx, tenantID, p.GuruID, jadwal.WaktuMulai, jadwal.WaktuSelesai)
+	if err != nil {
+		return err
+	}
+	if len(bentrok) > 0 {
+		b := bentrok[0]
+		return fmt.Errorf("guru sudah mengawas %s tingkat %s pada %s - %s",
+			b.SubjectNama, b.Tingkat, b.WaktuMulai.Format("02-01-2006 15:04"), b.WaktuSelesai.Format("15:04"))
+	}
+
+	return d.db.CreatePengawas(ctx, p)
+}
+
+func (d *ujianDomain) DeletePengawas(ctx context.Context, tenantID, ujianID, jadwalID, id string) error {
+	if _, err := d.getJadwal(ctx, tenantID, ujianID, jadwalID); err != nil {
+		return err
+	}
+	return d.db.DeletePengawas(ctx, ujianID, jadwalID, id)
+}
+
+// getJadwal loads a session only when it belongs to an exam of the tenant
+func (d *ujianDomain) getJadwal(ctx context.Context, tenantID, ujianID, jadwalID string) (*model.UjianJadwal, error) {
+	if _, err := d.getUjian(ctx, tenantID, ujianID); err != nil {
+		return nil, err
+	}
+	jadwal, err := d.db.GetJadwalByID(ctx, ujianID, jadwalID)
+	if err != nil {
+		return nil, err
+	}
+	if jadwal == nil {
+		return nil, ErrJadwalNotFound
+	}
+	return jadwal, nil
+}
+
+// ==========================================
+// CETAK
+// ==========================================
+
+func (d *ujianDomain) CetakKartu(ctx context.Context, tenantID, ujianID, ruangID, kelasID string) ([]byte, error) {
+	u, err := d.getUjian(ctx, tenantID, ujianID)
+	if err != nil {
+		return nil, err
+	}
+	peserta, err := d.db.GetPeserta(ctx, u.ID, ruangID, kelasID)
+	if err != nil {
+		return nil, err
+	}
+
+	belumLunas := map[string]bool{}
+	if u.CekSPP {
+		ids := make([]string, len(peserta))
+		for i, p := range peserta {
+			ids[i] = p.SantriID
+		}
+		if belumLunas, err = d.db.GetSiswaBelumLunasSPP(ctx, tenantID, ids); err != nil {
+			return nil, err
+		}
+	}
+
+	kartu := make([]model.KartuUjian, len(peserta))
+	for i, p := range peserta {
+		kartu[i] = model.KartuUjian{Peserta: p, BelumLunas: belumLunas[p.SantriID]}
+	}
+	return pdf_utils.GenerateKartuUjianPDF(u, kartu)
+}
+
+// CetakDaftarHadir renders attendance sheets for one room; without jadwalID every session with
+// seated students of that tingkat gets its own page
+func (d *ujianDomain) CetakDaftarHadir(ctx context.Context, tenantID, ujianID, ruangID, jadwalID string) ([]byte, error) {
+	u, err := d.getUjian(ctx, tenantID, ujianID)
+	if err != nil {
+		return nil, err
+	}
+	ruang, err := d.db.GetRuangByID(ctx, u.ID, ruangID)
+	if err != nil {
+		return nil, err
+	}
+	if ruang == nil {
+		return nil, ErrRuangNotFound
+	}
+
+	jadwal, err := d.db.GetJadwalByUjian(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+	peserta, err := d.db.GetPeserta(ctx, u.ID, ruang.ID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var sheets []model.DaftarHadirUjian
+	for _, j := range jadwal {
+		if jadwalID != "" && j.ID != jadwalID {
+			continue
+		}
+		var list []model.UjianPeserta
+		for _, p := range peserta {
+			if p.Tingkat == j.Tingkat {
+				list = append(list, p)
+			}
+		}
+		if len(list) == 0 {
+			continue
+		}
+
+		pengawas, err := d.db.GetPengawasByJadwal(ctx, u.ID, j.ID)
+		if err != nil {
+			return nil, err
+		}
+		var nama []string
+		for _, pg := range pengawas {
+			if pg.RuangID == ruang.ID {
+				nama = append(nama, pg.GuruNama)
+			}
+		}
+
+		sheets = append(sheets, model.DaftarHadirUjian{
+			Ruang:    *ruang,
+			Jadwal:   j,
+			Pengawas: nama,
+			Peserta:  list,
+		})
+	}
+	if jadwalID != "" && len(sheets) == 0 {
+		return nil, ErrJadwalNotFound
+	}
+
+	return pdf_utils.GenerateDaftarHadirUjianPDF(u, sheets)
+}
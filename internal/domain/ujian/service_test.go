@@ -0,0 +1,44 @@
+package ujian_test
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain/ujian"
+	"prabogo/internal/model"
+)
+
+func TestAllocateSeats(t *testing.T) {
+	Convey("Test AllocateSeats", t, func() {
+		siswa := []model.UjianSiswa{
+			{ID: "a1", KelasID: "7A"}, {ID: "a2", KelasID: "7A"}, {ID: "a3", KelasID: "7A"},
+			{ID: "b1", KelasID: "7B"}, {ID: "b2", KelasID: "7B"},
+		}
+		ruang := []model.UjianRuang{
+			{ID: "r1", Nama: "R1", Kapasitas: 3},
+			{ID: "r2", Nama: "R2", Kapasitas: 3},
+		}
+
+		Convey("Neighbouring seats mix classes and rooms fill in order", func() {
+			peserta, err := ujian.AllocateSeats(siswa, ruang, "PTS")
+			So(err, ShouldBeNil)
+			So(peserta, ShouldHaveLength, 5)
+
+			So(peserta[0].SantriID, ShouldEqual, "a1")
+			So(peserta[1].SantriID, ShouldEqual, "b1")
+			So(peserta[2].SantriID, ShouldEqual, "a2")
+			So(peserta[2].RuangID, ShouldEqual, "r1")
+			So(peserta[2].NomorKursi, ShouldEqual, 3)
+
+			So(peserta[3].RuangID, ShouldEqual, "r2")
+			So(peserta[3].NomorKursi, ShouldEqual, 1)
+			So(peserta[4].NomorPeserta, ShouldEqual, "PTS-0005")
+		})
+
+		Convey("Rejects when rooms are too small", func() {
+			_, err := ujian.AllocateSeats(siswa, ruang[:1], "PTS")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
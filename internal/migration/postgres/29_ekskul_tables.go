@@ -0,0 +1,107 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upEkskulTables, downEkskulTables)
+}
+
+func upEkskulTables(ctx context.Context, tx *sql.Tx) error {
+	// Table: sekolah_ekskul (Extracurricular master)
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_ekskul (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL,
+			nama VARCHAR(100) NOT NULL,
+			deskripsi TEXT DEFAULT '',
+			pembina_id UUID REFERENCES sekolah_guru(id) ON DELETE SET NULL,
+			is_wajib BOOLEAN DEFAULT FALSE,
+			is_active BOOLEAN DEFAULT TRUE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_sekolah_ekskul_tenant ON sekolah_ekskul(tenant_id);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create sekolah_ekskul: %w", err)
+	}
+
+	// Table: sekolah_ekskul_anggota (Membership + semester assessment)
+	_, err = tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_ekskul_anggota (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL,
+			ekskul_id UUID NOT NULL REFERENCES sekolah_ekskul(id) ON DELETE CASCADE,
+			santri_id UUID NOT NULL REFERENCES sekolah_siswa(id) ON DELETE CASCADE,
+			semester_id VARCHAR(20) NOT NULL, -- "2025-2026-1"
+			predikat VARCHAR(5) DEFAULT '',
+			deskripsi TEXT DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			UNIQUE(ekskul_id, santri_id, semester_id)
+		);
+		CREATE INDEX IF NOT EXISTS idx_sekolah_ekskul_anggota_tenant ON sekolah_ekskul_anggota(tenant_id);
+		CREATE INDEX IF NOT EXISTS idx_sekolah_ekskul_anggota_santri ON sekolah_ekskul_anggota(santri_id, semester_id);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create sekolah_ekskul_anggota: %w", err)
+	}
+
+	// Table: sekolah_ekskul_sesi (Meetings)
+	_, err = tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_ekskul_sesi (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL,
+			ekskul_id UUID NOT NULL REFERENCES sekolah_ekskul(id) ON DELETE CASCADE,
+			semester_id VARCHAR(20) NOT NULL,
+			tanggal DATE NOT NULL DEFAULT CURRENT_DATE,
+			materi TEXT DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_sekolah_ekskul_sesi_ekskul ON sekolah_ekskul_sesi(ekskul_id, semester_id);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create sekolah_ekskul_sesi: %w", err)
+	}
+
+	// Table: sekolah_ekskul_presensi (Attendance per session)
+	_, err = tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_ekskul_presensi (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			sesi_id UUID NOT NULL REFERENCES sekolah_ekskul_sesi(id) ON DELETE CASCADE,
+			anggota_id UUID NOT NULL REFERENCES sekolah_ekskul_anggota(id) ON DELETE CASCADE,
+			status VARCHAR(20) NOT NULL DEFAULT 'Hadir', -- Hadir, Izin, Sakit, Alpha
+			catatan TEXT DEFAULT '',
+			UNIQUE(sesi_id, anggota_id)
+		);
+		CREATE INDEX IF NOT EXISTS idx_sekolah_ekskul_presensi_anggota ON sekolah_ekskul_presensi(anggota_id);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create sekolah_ekskul_presensi: %w", err)
+	}
+
+	// Trigger for Updated At
+	_, err = tx.ExecContext(ctx, `
+		CREATE TRIGGER update_sekolah_ekskul_updated_at BEFORE UPDATE ON sekolah_ekskul FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+		CREATE TRIGGER update_sekolah_ekskul_anggota_updated_at BEFORE UPDATE ON sekolah_ekskul_anggota FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+		CREATE TRIGGER update_sekolah_ekskul_sesi_updated_at BEFORE UPDATE ON sekolah_ekskul_sesi FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+	`)
+	return err
+}
+
+func downEkskulTables(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP TABLE IF EXISTS sekolah_ekskul_presensi;
+		DROP TABLE IF EXISTS sekolah_ekskul_sesi;
+		DROP TABLE IF EXISTS sekolah_ekskul_anggota;
+		DROP TABLE IF EXISTS sekolah_ekskul;
+	`)
+	return err
+}
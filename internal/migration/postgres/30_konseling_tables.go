@@ -0,0 +1,83 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upKonselingTables, downKonselingTables)
+}
+
+func upKonselingTables(ctx context.Context, tx *sql.Tx) error {
+	// Table: sekolah_konseling_kasus (Confidential BK cases)
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_konseling_kasus (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL,
+			santri_id UUID NOT NULL REFERENCES sekolah_siswa(id) ON DELETE CASCADE,
+			kategori VARCHAR(50) NOT NULL,
+			judul VARCHAR(255) NOT NULL,
+			catatan TEXT DEFAULT '', -- AES-GCM ciphertext
+			status VARCHAR(20) NOT NULL DEFAULT 'Terbuka', -- Terbuka, Proses, Selesai
+			rujuk_orang_tua BOOLEAN DEFAULT FALSE,
+			tanggal_rujukan DATE,
+			konselor_id UUID,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_sekolah_konseling_kasus_tenant ON sekolah_konseling_kasus(tenant_id);
+		CREATE INDEX IF NOT EXISTS idx_sekolah_konseling_kasus_santri ON sekolah_konseling_kasus(santri_id);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create sekolah_konseling_kasus: %w", err)
+	}
+
+	// Table: sekolah_konseling_sesi (Sessions + follow-ups)
+	_, err = tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_konseling_sesi (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			kasus_id UUID NOT NULL REFERENCES sekolah_konseling_kasus(id) ON DELETE CASCADE,
+			tanggal DATE NOT NULL DEFAULT CURRENT_DATE,
+			catatan TEXT DEFAULT '', -- AES-GCM ciphertext
+			tindak_lanjut TEXT DEFAULT '',
+			tanggal_tindak_lanjut DATE,
+			tindak_lanjut_selesai BOOLEAN DEFAULT FALSE,
+			konselor_id UUID,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_sekolah_konseling_sesi_kasus ON sekolah_konseling_sesi(kasus_id);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create sekolah_konseling_sesi: %w", err)
+	}
+
+	// Table: sekolah_konseling_pelanggaran (Link to PelanggaranSiswa)
+	_, err = tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_konseling_pelanggaran (
+			kasus_id UUID NOT NULL REFERENCES sekolah_konseling_kasus(id) ON DELETE CASCADE,
+			pelanggaran_id UUID NOT NULL REFERENCES sekolah_pelanggaran_siswa(id) ON DELETE CASCADE,
+			PRIMARY KEY (kasus_id, pelanggaran_id)
+		);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create sekolah_konseling_pelanggaran: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		CREATE TRIGGER update_sekolah_konseling_kasus_updated_at BEFORE UPDATE ON sekolah_konseling_kasus FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+	`)
+	return err
+}
+
+func downKonselingTables(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP TABLE IF EXISTS sekolah_konseling_pelanggaran;
+		DROP TABLE IF EXISTS sekolah_konseling_sesi;
+		DROP TABLE IF EXISTS sekolah_konseling_kasus;
+	`)
+	return err
+}
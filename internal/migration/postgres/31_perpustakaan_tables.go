@@ -0,0 +1,115 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upPerpustakaanTables, downPerpustakaanTables)
+}
+
+func upPerpustakaanTables(ctx context.Context, tx *sql.Tx) error {
+	// Table: sekolah_buku (Catalog)
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_buku (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL,
+			isbn VARCHAR(20) DEFAULT '',
+			judul VARCHAR(255) NOT NULL,
+			penulis VARCHAR(255) DEFAULT '',
+			penerbit VARCHAR(255) DEFAULT '',
+			tahun_terbit INT DEFAULT 0,
+			kategori VARCHAR(100) DEFAULT '',
+			rak VARCHAR(50) DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_sekolah_buku_tenant ON sekolah_buku(tenant_id);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create sekolah_buku: %w", err)
+	}
+
+	// Table: sekolah_buku_eksemplar (Physical copies)
+	_, err = tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_buku_eksemplar (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL,
+			buku_id UUID NOT NULL REFERENCES sekolah_buku(id) ON DELETE CASCADE,
+			barcode VARCHAR(50) NOT NULL,
+			kondisi VARCHAR(20) DEFAULT 'Baik',
+			status VARCHAR(20) NOT NULL DEFAULT 'Tersedia', -- Tersedia, Dipinjam, Hilang
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			UNIQUE(tenant_id, barcode)
+		);
+		CREATE INDEX IF NOT EXISTS idx_sekolah_buku_eksemplar_buku ON sekolah_buku_eksemplar(buku_id);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create sekolah_buku_eksemplar: %w", err)
+	}
+
+	// Table: sekolah_peminjaman (Loans)
+	_, err = tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_peminjaman (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL,
+			eksemplar_id UUID NOT NULL REFERENCES sekolah_buku_eksemplar(id) ON DELETE CASCADE,
+			anggota_tipe VARCHAR(20) NOT NULL, -- siswa, pegawai
+			anggota_id UUID NOT NULL,
+			anggota_nama VARCHAR(255) NOT NULL,
+			anggota_no_hp VARCHAR(20) DEFAULT '',
+			tanggal_pinjam DATE NOT NULL DEFAULT CURRENT_DATE,
+			jatuh_tempo DATE NOT NULL,
+			tanggal_kembali DATE,
+			jumlah_perpanjang INT DEFAULT 0,
+			denda BIGINT DEFAULT 0,
+			denda_lunas BOOLEAN DEFAULT FALSE,
+			status VARCHAR(20) NOT NULL DEFAULT 'Dipinjam', -- Dipinjam, Kembali, Hilang
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_sekolah_peminjaman_tenant_status ON sekolah_peminjaman(tenant_id, status);
+		CREATE INDEX IF NOT EXISTS idx_sekolah_peminjaman_anggota ON sekolah_peminjaman(anggota_tipe, anggota_id);
+		CREATE INDEX IF NOT EXISTS idx_sekolah_peminjaman_eksemplar ON sekolah_peminjaman(eksemplar_id);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create sekolah_peminjaman: %w", err)
+	}
+
+	// Table: sekolah_perpustakaan_config (Circulation rules per tenant)
+	_, err = tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_perpustakaan_config (
+			tenant_id UUID PRIMARY KEY,
+			lama_pinjam_hari INT NOT NULL DEFAULT 7,
+			maks_perpanjang INT NOT NULL DEFAULT 2,
+			maks_pinjam INT NOT NULL DEFAULT 3,
+			denda_per_hari BIGINT NOT NULL DEFAULT 500,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create sekolah_perpustakaan_config: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		CREATE TRIGGER update_sekolah_buku_updated_at BEFORE UPDATE ON sekolah_buku FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+		CREATE TRIGGER update_sekolah_buku_eksemplar_updated_at BEFORE UPDATE ON sekolah_buku_eksemplar FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+		CREATE TRIGGER update_sekolah_peminjaman_updated_at BEFORE UPDATE ON sekolah_peminjaman FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+	`)
+	return err
+}
+
+func downPerpustakaanTables(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP TABLE IF EXISTS sekolah_perpustakaan_config;
+		DROP TABLE IF EXISTS sekolah_peminjaman;
+		DROP TABLE IF EXISTS sekolah_buku_eksemplar;
+		DROP TABLE IF EXISTS sekolah_buku;
+	`)
+	return err
+}
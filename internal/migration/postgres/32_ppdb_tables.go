@@ -0,0 +1,101 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upPPDBTables, downPPDBTables)
+}
+
+func upPPDBTables(ctx context.Context, tx *sql.Tx) error {
+	// Table: sekolah_ppdb_gelombang (Admission waves)
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_ppdb_gelombang (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL,
+			tahun_ajaran VARCHAR(20) NOT NULL,
+			nama VARCHAR(100) NOT NULL,
+			tanggal_buka DATE NOT NULL,
+			tanggal_tutup DATE NOT NULL,
+			kuota INT NOT NULL DEFAULT 0, -- 0 = unlimited
+			biaya_pendaftaran BIGINT NOT NULL DEFAULT 0,
+			is_active BOOLEAN DEFAULT TRUE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_sekolah_ppdb_gelombang_tenant ON sekolah_ppdb_gelombang(tenant_id);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create sekolah_ppdb_gelombang: %w", err)
+	}
+
+	// Table: sekolah_ppdb_pendaftar (Applicants)
+	_, err = tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_ppdb_pendaftar (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL,
+			gelombang_id UUID NOT NULL REFERENCES sekolah_ppdb_gelombang(id) ON DELETE RESTRICT,
+			no_pendaftaran VARCHAR(50) NOT NULL,
+			nama VARCHAR(255) NOT NULL,
+			nisn VARCHAR(50) DEFAULT '',
+			jenis_kelamin VARCHAR(1) DEFAULT '',
+			tempat_lahir VARCHAR(100) DEFAULT '',
+			tanggal_lahir DATE,
+			alamat TEXT DEFAULT '',
+			asal_sekolah VARCHAR(255) DEFAULT '',
+			nama_wali VARCHAR(100) DEFAULT '',
+			no_hp_wali VARCHAR(20) NOT NULL,
+			email_wali VARCHAR(255) DEFAULT '',
+			status VARCHAR(20) NOT NULL DEFAULT 'Verifikasi', -- Verifikasi, Tes, Diterima, Ditolak
+			nilai_tes NUMERIC(5,2),
+			catatan TEXT DEFAULT '',
+			status_bayar VARCHAR(20) NOT NULL DEFAULT 'Belum', -- Belum, Lunas, Gratis
+			payment_order_id VARCHAR(100),
+			siswa_id UUID REFERENCES sekolah_siswa(id) ON DELETE SET NULL,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			UNIQUE(tenant_id, no_pendaftaran)
+		);
+		CREATE INDEX IF NOT EXISTS idx_sekolah_ppdb_pendaftar_gelombang ON sekolah_ppdb_pendaftar(gelombang_id, status);
+		CREATE INDEX IF NOT EXISTS idx_sekolah_ppdb_pendaftar_order ON sekolah_ppdb_pendaftar(payment_order_id);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create sekolah_ppdb_pendaftar: %w", err)
+	}
+
+	// Table: sekolah_ppdb_dokumen (Applicant documents)
+	_, err = tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_ppdb_dokumen (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			pendaftar_id UUID NOT NULL REFERENCES sekolah_ppdb_pendaftar(id) ON DELETE CASCADE,
+			jenis VARCHAR(50) NOT NULL,
+			url TEXT NOT NULL,
+			verified BOOLEAN DEFAULT FALSE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_sekolah_ppdb_dokumen_pendaftar ON sekolah_ppdb_dokumen(pendaftar_id);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create sekolah_ppdb_dokumen: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		CREATE TRIGGER update_sekolah_ppdb_gelombang_updated_at BEFORE UPDATE ON sekolah_ppdb_gelombang FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+		CREATE TRIGGER update_sekolah_ppdb_pendaftar_updated_at BEFORE UPDATE ON sekolah_ppdb_pendaftar FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+	`)
+	return err
+}
+
+func downPPDBTables(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP TABLE IF EXISTS sekolah_ppdb_dokumen;
+		DROP TABLE IF EXISTS sekolah_ppdb_pendaftar;
+		DROP TABLE IF EXISTS sekolah_ppdb_gelombang;
+	`)
+	return err
+}
@@ -0,0 +1,114 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upUjianTables, downUjianTables)
+}
+
+func upUjianTables(ctx context.Context, tx *sql.Tx) error {
+	// Table: sekolah_ujian (Exam events per semester)
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_ujian (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL,
+			nama VARCHAR(255) NOT NULL,
+			jenis VARCHAR(10) NOT NULL, -- PTS, PAS, PAT, US
+			semester_id VARCHAR(20) NOT NULL,
+			tanggal_mulai DATE NOT NULL,
+			tanggal_selesai DATE NOT NULL,
+			cek_spp BOOLEAN DEFAULT FALSE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_sekolah_ujian_tenant ON sekolah_ujian(tenant_id, semester_id);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create sekolah_ujian: %w", err)
+	}
+
+	// Table: sekolah_ujian_jadwal (Per-mapel sessions by tingkat)
+	_, err = tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_ujian_jadwal (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			ujian_id UUID NOT NULL REFERENCES sekolah_ujian(id) ON DELETE CASCADE,
+			subject_id UUID NOT NULL REFERENCES subjects(id) ON DELETE CASCADE,
+			tingkat VARCHAR(50) NOT NULL,
+			waktu_mulai TIMESTAMP WITH TIME ZONE NOT NULL,
+			waktu_selesai TIMESTAMP WITH TIME ZONE NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_sekolah_ujian_jadwal_ujian ON sekolah_ujian_jadwal(ujian_id);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create sekolah_ujian_jadwal: %w", err)
+	}
+
+	// Table: sekolah_ujian_ruang (Exam rooms)
+	_, err = tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_ujian_ruang (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			ujian_id UUID NOT NULL REFERENCES sekolah_ujian(id) ON DELETE CASCADE,
+			nama VARCHAR(100) NOT NULL,
+			kapasitas INT NOT NULL DEFAULT 20,
+			UNIQUE(ujian_id, nama)
+		);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create sekolah_ujian_ruang: %w", err)
+	}
+
+	// Table: sekolah_ujian_peserta (Seat allocation)
+	_, err = tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_ujian_peserta (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			ujian_id UUID NOT NULL REFERENCES sekolah_ujian(id) ON DELETE CASCADE,
+			ruang_id UUID NOT NULL REFERENCES sekolah_ujian_ruang(id) ON DELETE CASCADE,
+			santri_id UUID NOT NULL REFERENCES sekolah_siswa(id) ON DELETE CASCADE,
+			nomor_kursi INT NOT NULL,
+			nomor_peserta VARCHAR(30) NOT NULL,
+			UNIQUE(ujian_id, santri_id),
+			UNIQUE(ruang_id, nomor_kursi)
+		);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create sekolah_ujian_peserta: %w", err)
+	}
+
+	// Table: sekolah_ujian_pengawas (Proctor assignment per session and room)
+	_, err = tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_ujian_pengawas (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			jadwal_id UUID NOT NULL REFERENCES sekolah_ujian_jadwal(id) ON DELETE CASCADE,
+			ruang_id UUID NOT NULL REFERENCES sekolah_ujian_ruang(id) ON DELETE CASCADE,
+			guru_id UUID NOT NULL REFERENCES sekolah_guru(id) ON DELETE CASCADE,
+			UNIQUE(jadwal_id, ruang_id, guru_id)
+		);
+		CREATE INDEX IF NOT EXISTS idx_sekolah_ujian_pengawas_guru ON sekolah_ujian_pengawas(guru_id);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create sekolah_ujian_pengawas: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		CREATE TRIGGER update_sekolah_ujian_updated_at BEFORE UPDATE ON sekolah_ujian FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+	`)
+	return err
+}
+
+func downUjianTables(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP TABLE IF EXISTS sekolah_ujian_pengawas;
+		DROP TABLE IF EXISTS sekolah_ujian_peserta;
+		DROP TABLE IF EXISTS sekolah_ujian_ruang;
+		DROP TABLE IF EXISTS sekolah_ujian_jadwal;
+		DROP TABLE IF EXISTS sekolah_ujian;
+	`)
+	return err
+}
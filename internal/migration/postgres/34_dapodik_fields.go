@@ -0,0 +1,94 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationNoTxContext(upDapodikFields, downDapodikFields)
+}
+
+// upDapodikFields adds Dapodik core fields to siswa, guru and kelas (rombel)
+func upDapodikFields(ctx context.Context, db *sql.DB) error {
+	queries := []struct {
+		name  string
+		query string
+	}{
+		{
+			name: "add dapodik fields to sekolah_siswa",
+			query: `ALTER TABLE sekolah_siswa
+				ADD COLUMN IF NOT EXISTS nik VARCHAR(20),
+				ADD COLUMN IF NOT EXISTS jenis_kelamin VARCHAR(1),
+				ADD COLUMN IF NOT EXISTS tempat_lahir VARCHAR(100),
+				ADD COLUMN IF NOT EXISTS tanggal_lahir DATE,
+				ADD COLUMN IF NOT EXISTS agama VARCHAR(30),
+				ADD COLUMN IF NOT EXISTS nama_ayah VARCHAR(255),
+				ADD COLUMN IF NOT EXISTS pekerjaan_ayah VARCHAR(100),
+				ADD COLUMN IF NOT EXISTS nama_ibu VARCHAR(255),
+				ADD COLUMN IF NOT EXISTS pekerjaan_ibu VARCHAR(100)`,
+		},
+		{
+			name:  "create idx_sekolah_siswa_nisn",
+			query: `CREATE INDEX IF NOT EXISTS idx_sekolah_siswa_nisn ON sekolah_siswa(tenant_id, nisn)`,
+		},
+		{
+			name:  "create idx_sekolah_siswa_nik",
+			query: `CREATE INDEX IF NOT EXISTS idx_sekolah_siswa_nik ON sekolah_siswa(tenant_id, nik)`,
+		},
+		{
+			name: "add dapodik fields to sekolah_guru",
+			query: `ALTER TABLE sekolah_guru
+				ADD COLUMN IF NOT EXISTS nuptk VARCHAR(20),
+				ADD COLUMN IF NOT EXISTS nik VARCHAR(20),
+				ADD COLUMN IF NOT EXISTS jenis_kelamin VARCHAR(1),
+				ADD COLUMN IF NOT EXISTS tempat_lahir VARCHAR(100),
+				ADD COLUMN IF NOT EXISTS tanggal_lahir DATE`,
+		},
+		{
+			name:  "create idx_sekolah_guru_nuptk",
+			query: `CREATE INDEX IF NOT EXISTS idx_sekolah_guru_nuptk ON sekolah_guru(tenant_id, nuptk)`,
+		},
+		{
+			name: "add rombel fields to sekolah_kelas",
+			query: `ALTER TABLE sekolah_kelas
+				ADD COLUMN IF NOT EXISTS wali_kelas_id UUID REFERENCES sekolah_guru(id) ON DELETE SET NULL,
+				ADD COLUMN IF NOT EXISTS kurikulum VARCHAR(50)`,
+		},
+	}
+
+	for _, q := range queries {
+		if _, err := db.ExecContext(ctx, q.query); err != nil {
+			fmt.Printf("Warning: %s: %v\n", q.name, err)
+			// Continue on error for idempotent migrations
+		}
+	}
+
+	return nil
+}
+
+func downDapodikFields(ctx context.Context, db *sql.DB) error {
+	queries := []string{
+		`ALTER TABLE sekolah_kelas DROP COLUMN IF EXISTS wali_kelas_id, DROP COLUMN IF EXISTS kurikulum`,
+		`DROP INDEX IF EXISTS idx_sekolah_guru_nuptk`,
+		`ALTER TABLE sekolah_guru DROP COLUMN IF EXISTS nuptk, DROP COLUMN IF EXISTS nik, DROP COLUMN IF EXISTS jenis_kelamin,
+			DROP COLUMN IF EXISTS tempat_lahir, DROP COLUMN IF EXISTS tanggal_lahir`,
+		`DROP INDEX IF EXISTS idx_sekolah_siswa_nik`,
+		`DROP INDEX IF EXISTS idx_sekolah_siswa_nisn`,
+		`ALTER TABLE sekolah_siswa DROP COLUMN IF EXISTS nik, DROP COLUMN IF EXISTS jenis_kelamin,
+			DROP COLUMN IF EXISTS tempat_lahir, DROP COLUMN IF EXISTS tanggal_lahir, DROP COLUMN IF EXISTS agama,
+			DROP COLUMN IF EXISTS nama_ayah, DROP COLUMN IF EXISTS pekerjaan_ayah,
+			DROP COLUMN IF EXISTS nama_ibu, DROP COLUMN IF EXISTS pekerjaan_ibu`,
+	}
+
+	for _, q := range queries {
+		if _, err := db.ExecContext(ctx, q); err != nil {
+			fmt.Printf("Warning during rollback: %v\n", err)
+		}
+	}
+
+	return nil
+}
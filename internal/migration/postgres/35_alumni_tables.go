@@ -0,0 +1,90 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAlumniTables, downAlumniTables)
+}
+
+func upAlumniTables(ctx context.Context, tx *sql.Tx) error {
+	// 1. Alumni registry
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_alumni (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL,
+			siswa_id UUID UNIQUE REFERENCES sekolah_siswa(id) ON DELETE SET NULL,
+			nama VARCHAR(255) NOT NULL,
+			nis VARCHAR(50),
+			nisn VARCHAR(50),
+			tahun_lulus INT NOT NULL,
+			kelas_terakhir VARCHAR(100),
+			no_hp VARCHAR(20),
+			email VARCHAR(255),
+			alamat TEXT,
+			kegiatan VARCHAR(30),
+			institusi VARCHAR(255),
+			jurusan VARCHAR(255),
+			token VARCHAR(64) NOT NULL UNIQUE,
+			terakhir_diperbarui TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_sekolah_alumni_tenant_tahun ON sekolah_alumni(tenant_id, tahun_lulus);
+		CREATE TRIGGER update_sekolah_alumni_updated_at BEFORE UPDATE ON sekolah_alumni FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+	`); err != nil {
+		return fmt.Errorf("failed to create sekolah_alumni: %w", err)
+	}
+
+	// 2. Tracer study campaigns
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_tracer_survey (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL,
+			judul VARCHAR(255) NOT NULL,
+			deskripsi TEXT,
+			tahun_lulus_dari INT DEFAULT 0,
+			tahun_lulus_sampai INT DEFAULT 0,
+			pertanyaan JSONB NOT NULL DEFAULT '[]',
+			status VARCHAR(20) NOT NULL DEFAULT 'draft',
+			tanggal_mulai DATE NOT NULL,
+			tanggal_selesai DATE NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_sekolah_tracer_survey_tenant ON sekolah_tracer_survey(tenant_id, status);
+		CREATE TRIGGER update_sekolah_tracer_survey_updated_at BEFORE UPDATE ON sekolah_tracer_survey FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+	`); err != nil {
+		return fmt.Errorf("failed to create sekolah_tracer_survey: %w", err)
+	}
+
+	// 3. Tracer study answers (one per alumni per survey, resubmission overwrites)
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_tracer_jawaban (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			survey_id UUID NOT NULL REFERENCES sekolah_tracer_survey(id) ON DELETE CASCADE,
+			alumni_id UUID NOT NULL REFERENCES sekolah_alumni(id) ON DELETE CASCADE,
+			jawaban JSONB NOT NULL DEFAULT '{}',
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			UNIQUE (survey_id, alumni_id)
+		);
+	`); err != nil {
+		return fmt.Errorf("failed to create sekolah_tracer_jawaban: %w", err)
+	}
+
+	return nil
+}
+
+func downAlumniTables(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP TABLE IF EXISTS sekolah_tracer_jawaban;
+		DROP TABLE IF EXISTS sekolah_tracer_survey;
+		DROP TABLE IF EXISTS sekolah_alumni;
+	`)
+	return err
+}
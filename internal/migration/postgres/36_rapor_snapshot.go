@@ -0,0 +1,62 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationNoTxContext(upRaporSnapshot, downRaporSnapshot)
+}
+
+// upRaporSnapshot extends the rapor snapshot so a printed rapor can be rendered without live data
+func upRaporSnapshot(ctx context.Context, db *sql.DB) error {
+	queries := []struct {
+		name  string
+		query string
+	}{
+		{
+			name: "add header snapshot to sekolah_rapor",
+			query: `ALTER TABLE sekolah_rapor
+				ADD COLUMN IF NOT EXISTS header JSONB`,
+		},
+		{
+			name: "add detail snapshot to sekolah_rapor_nilai",
+			query: `ALTER TABLE sekolah_rapor_nilai
+				ADD COLUMN IF NOT EXISTS urutan INT DEFAULT 0,
+				ADD COLUMN IF NOT EXISTS detail JSONB`,
+		},
+		{
+			name:  "create idx_sekolah_rapor_nilai_rapor",
+			query: `CREATE INDEX IF NOT EXISTS idx_sekolah_rapor_nilai_rapor ON sekolah_rapor_nilai(rapor_id, urutan)`,
+		},
+	}
+
+	for _, q := range queries {
+		if _, err := db.ExecContext(ctx, q.query); err != nil {
+			fmt.Printf("Warning: %s: %v\n", q.name, err)
+			// Continue on error for idempotent migrations
+		}
+	}
+
+	return nil
+}
+
+func downRaporSnapshot(ctx context.Context, db *sql.DB) error {
+	queries := []string{
+		`DROP INDEX IF EXISTS idx_sekolah_rapor_nilai_rapor`,
+		`ALTER TABLE sekolah_rapor_nilai DROP COLUMN IF EXISTS urutan, DROP COLUMN IF EXISTS detail`,
+		`ALTER TABLE sekolah_rapor DROP COLUMN IF EXISTS header`,
+	}
+
+	for _, q := range queries {
+		if _, err := db.ExecContext(ctx, q); err != nil {
+			fmt.Printf("Warning during rollback: %v\n", err)
+		}
+	}
+
+	return nil
+}
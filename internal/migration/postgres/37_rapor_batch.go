@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upRaporBatch, downRaporBatch)
+}
+
+func upRaporBatch(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_rapor_batch (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL,
+			kelas_id UUID NOT NULL REFERENCES sekolah_kelas(id) ON DELETE CASCADE,
+			semester_id VARCHAR(50) NOT NULL,
+			status VARCHAR(20) NOT NULL DEFAULT 'queued', -- queued, processing, done, failed
+			total INT NOT NULL DEFAULT 0,
+			selesai INT NOT NULL DEFAULT 0,
+			gagal INT NOT NULL DEFAULT 0,
+			siswa JSONB NOT NULL DEFAULT '[]', -- per-student catatan & kehadiran
+			errors JSONB NOT NULL DEFAULT '[]',
+			zip_file BYTEA,
+			pdf_file BYTEA,
+			started_at TIMESTAMP WITH TIME ZONE,
+			finished_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_sekolah_rapor_batch_tenant ON sekolah_rapor_batch(tenant_id, created_at DESC);
+		CREATE TRIGGER update_sekolah_rapor_batch_updated_at BEFORE UPDATE ON sekolah_rapor_batch FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+	`); err != nil {
+		return fmt.Errorf("failed to create sekolah_rapor_batch: %w", err)
+	}
+	return nil
+}
+
+func downRaporBatch(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS sekolah_rapor_batch`)
+	return err
+}
@@ -0,0 +1,48 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upRaporLifecycle, downRaporLifecycle)
+}
+
+func upRaporLifecycle(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, `
+		ALTER TABLE sekolah_rapor ADD COLUMN IF NOT EXISTS published_at TIMESTAMP WITH TIME ZONE;
+		UPDATE sekolah_rapor SET published_at = updated_at WHERE status = 'Published' AND published_at IS NULL;
+		CREATE INDEX IF NOT EXISTS idx_sekolah_rapor_santri ON sekolah_rapor(santri_id, status);
+	`); err != nil {
+		return fmt.Errorf("failed to alter sekolah_rapor: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_rapor_status_log (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			rapor_id UUID NOT NULL REFERENCES sekolah_rapor(id) ON DELETE CASCADE,
+			dari_status VARCHAR(50) NOT NULL,
+			ke_status VARCHAR(50) NOT NULL,
+			user_id UUID,
+			alasan TEXT NOT NULL DEFAULT '', -- required when unlocking
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_sekolah_rapor_status_log_rapor ON sekolah_rapor_status_log(rapor_id, created_at);
+	`); err != nil {
+		return fmt.Errorf("failed to create sekolah_rapor_status_log: %w", err)
+	}
+	return nil
+}
+
+func downRaporLifecycle(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP TABLE IF EXISTS sekolah_rapor_status_log;
+		DROP INDEX IF EXISTS idx_sekolah_rapor_santri;
+		ALTER TABLE sekolah_rapor DROP COLUMN IF EXISTS published_at;
+	`)
+	return err
+}
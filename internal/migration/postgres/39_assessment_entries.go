@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAssessmentEntries, downAssessmentEntries)
+}
+
+func upAssessmentEntries(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS assessment_entries (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+			student_id UUID NOT NULL,
+			subject_id UUID NOT NULL REFERENCES subjects(id) ON DELETE CASCADE,
+			semester_id VARCHAR(20) NOT NULL, -- "2025-2026-1"
+			component VARCHAR(100) NOT NULL, -- grading_config component name
+			title VARCHAR(255) NOT NULL,
+			topic TEXT NOT NULL DEFAULT '',
+			date DATE NOT NULL DEFAULT CURRENT_DATE,
+			score DECIMAL(5,2) NOT NULL CHECK (score >= 0 AND score <= 100),
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_assessment_entries_grade ON assessment_entries(tenant_id, student_id, subject_id, semester_id);
+		CREATE INDEX IF NOT EXISTS idx_assessment_entries_subject ON assessment_entries(subject_id, semester_id);
+		CREATE TRIGGER update_assessment_entries_updated_at BEFORE UPDATE ON assessment_entries FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+	`); err != nil {
+		return fmt.Errorf("failed to create assessment_entries: %w", err)
+	}
+	return nil
+}
+
+func downAssessmentEntries(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS assessment_entries`)
+	return err
+}
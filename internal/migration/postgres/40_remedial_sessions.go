@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upRemedialSessions, downRemedialSessions)
+}
+
+func upRemedialSessions(ctx context.Context, tx *sql.Tx) error {
+	// Append-only: sessions are never updated so the history stays auditable
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS remedial_sessions (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+			student_id UUID NOT NULL,
+			subject_id UUID NOT NULL REFERENCES subjects(id) ON DELETE CASCADE,
+			semester_id VARCHAR(20) NOT NULL,
+			type VARCHAR(20) NOT NULL DEFAULT 'remedial', -- remedial, enrichment
+			date DATE NOT NULL DEFAULT CURRENT_DATE,
+			topic TEXT NOT NULL DEFAULT '',
+			kkm INT NOT NULL DEFAULT 0,
+			original_score DECIMAL(5,2) NOT NULL,
+			remedial_score DECIMAL(5,2) NOT NULL,
+			final_score DECIMAL(5,2) NOT NULL,
+			capped BOOLEAN NOT NULL DEFAULT FALSE,
+			notes TEXT NOT NULL DEFAULT '',
+			created_by UUID,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_remedial_sessions_grade ON remedial_sessions(tenant_id, subject_id, semester_id, student_id);
+	`); err != nil {
+		return fmt.Errorf("failed to create remedial_sessions: %w", err)
+	}
+	return nil
+}
+
+func downRemedialSessions(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS remedial_sessions`)
+	return err
+}
@@ -0,0 +1,73 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upMerdekaCPTP, downMerdekaCPTP)
+}
+
+func upMerdekaCPTP(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS capaian_pembelajaran (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+			subject_id UUID NOT NULL REFERENCES subjects(id) ON DELETE CASCADE,
+			fase VARCHAR(2) NOT NULL, -- A-F
+			elemen VARCHAR(255) NOT NULL DEFAULT '',
+			deskripsi TEXT NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_capaian_pembelajaran_subject ON capaian_pembelajaran(tenant_id, subject_id, fase);
+		CREATE TRIGGER update_capaian_pembelajaran_updated_at BEFORE UPDATE ON capaian_pembelajaran FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+		CREATE TABLE IF NOT EXISTS tujuan_pembelajaran (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+			cp_id UUID NOT NULL REFERENCES capaian_pembelajaran(id) ON DELETE CASCADE,
+			subject_id UUID NOT NULL REFERENCES subjects(id) ON DELETE CASCADE,
+			kode VARCHAR(50) NOT NULL DEFAULT '',
+			deskripsi TEXT NOT NULL,
+			urutan INT NOT NULL DEFAULT 0,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_tujuan_pembelajaran_cp ON tujuan_pembelajaran(cp_id, urutan);
+		CREATE TRIGGER update_tujuan_pembelajaran_updated_at BEFORE UPDATE ON tujuan_pembelajaran FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+	`); err != nil {
+		return fmt.Errorf("failed to create capaian/tujuan pembelajaran: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS tp_achievements (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+			student_id UUID NOT NULL,
+			tp_id UUID NOT NULL REFERENCES tujuan_pembelajaran(id) ON DELETE CASCADE,
+			semester_id VARCHAR(20) NOT NULL,
+			score DECIMAL(5,2) NOT NULL CHECK (score >= 0 AND score <= 100),
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			UNIQUE(student_id, tp_id, semester_id)
+		);
+		CREATE INDEX IF NOT EXISTS idx_tp_achievements_student ON tp_achievements(tenant_id, student_id, semester_id);
+	`); err != nil {
+		return fmt.Errorf("failed to create tp_achievements: %w", err)
+	}
+	return nil
+}
+
+func downMerdekaCPTP(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP TABLE IF EXISTS tp_achievements;
+		DROP TABLE IF EXISTS tujuan_pembelajaran;
+		DROP TABLE IF EXISTS capaian_pembelajaran;
+	`)
+	return err
+}
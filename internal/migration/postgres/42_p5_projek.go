@@ -0,0 +1,68 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upP5Projek, downP5Projek)
+}
+
+func upP5Projek(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS p5_projek (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+			semester_id VARCHAR(20) NOT NULL,
+			kelas_id UUID NOT NULL REFERENCES sekolah_kelas(id) ON DELETE CASCADE,
+			tema VARCHAR(100) NOT NULL,
+			judul VARCHAR(255) NOT NULL,
+			deskripsi TEXT NOT NULL DEFAULT '',
+			fasilitator_id UUID REFERENCES sekolah_guru(id) ON DELETE SET NULL,
+			targets JSONB NOT NULL DEFAULT '[]', -- dimensi/elemen/sub-elemen
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_p5_projek_kelas ON p5_projek(tenant_id, semester_id, kelas_id);
+		CREATE TRIGGER update_p5_projek_updated_at BEFORE UPDATE ON p5_projek FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+	`); err != nil {
+		return fmt.Errorf("failed to create p5_projek: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS p5_penilaian (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+			projek_id UUID NOT NULL REFERENCES p5_projek(id) ON DELETE CASCADE,
+			student_id UUID NOT NULL,
+			capaian JSONB NOT NULL DEFAULT '[]', -- target_id -> MB/SB/BSH/SAB
+			catatan TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			UNIQUE(projek_id, student_id)
+		);
+		CREATE INDEX IF NOT EXISTS idx_p5_penilaian_student ON p5_penilaian(tenant_id, student_id);
+	`); err != nil {
+		return fmt.Errorf("failed to create p5_penilaian: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		ALTER TABLE sekolah_rapor ADD COLUMN IF NOT EXISTS p5 JSONB;
+	`); err != nil {
+		return fmt.Errorf("failed to add p5 snapshot to sekolah_rapor: %w", err)
+	}
+	return nil
+}
+
+func downP5Projek(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE sekolah_rapor DROP COLUMN IF EXISTS p5;
+		DROP TABLE IF EXISTS p5_penilaian;
+		DROP TABLE IF EXISTS p5_projek;
+	`)
+	return err
+}
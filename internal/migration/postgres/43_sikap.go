@@ -0,0 +1,63 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upSikap, downSikap)
+}
+
+func upSikap(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS jurnal_sikap (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+			student_id UUID NOT NULL,
+			semester_id VARCHAR(20) NOT NULL,
+			aspek VARCHAR(20) NOT NULL, -- spiritual, sosial
+			tanggal DATE NOT NULL,
+			butir VARCHAR(100) NOT NULL,
+			jenis VARCHAR(10) NOT NULL, -- positif, negatif
+			catatan TEXT NOT NULL DEFAULT '',
+			observer_id UUID REFERENCES users(id) ON DELETE SET NULL,
+			observer_role VARCHAR(50) NOT NULL DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_jurnal_sikap_student ON jurnal_sikap(tenant_id, semester_id, student_id);
+	`); err != nil {
+		return fmt.Errorf("failed to create jurnal_sikap: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sikap_siswa (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+			student_id UUID NOT NULL,
+			semester_id VARCHAR(20) NOT NULL,
+			aspek VARCHAR(20) NOT NULL,
+			predikat VARCHAR(5) NOT NULL,
+			deskripsi TEXT NOT NULL DEFAULT '',
+			edited BOOLEAN NOT NULL DEFAULT FALSE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			UNIQUE(student_id, semester_id, aspek)
+		);
+		CREATE INDEX IF NOT EXISTS idx_sikap_siswa_tenant ON sikap_siswa(tenant_id, semester_id);
+	`); err != nil {
+		return fmt.Errorf("failed to create sikap_siswa: %w", err)
+	}
+	return nil
+}
+
+func downSikap(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP TABLE IF EXISTS sikap_siswa;
+		DROP TABLE IF EXISTS jurnal_sikap;
+	`)
+	return err
+}
@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upPredicateSets, downPredicateSets)
+}
+
+func upPredicateSets(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS erapor_predicate_sets (
+			tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+			subject_type VARCHAR(50) NOT NULL,
+			rules JSONB NOT NULL DEFAULT '[]',
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			PRIMARY KEY (tenant_id, subject_type)
+		);
+		CREATE INDEX IF NOT EXISTS idx_tahfidz_setoran_santri_tanggal ON sekolah_tahfidz_setoran(tenant_id, santri_id, tanggal);
+	`); err != nil {
+		return fmt.Errorf("failed to create erapor_predicate_sets: %w", err)
+	}
+	return nil
+}
+
+func downPredicateSets(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP INDEX IF EXISTS idx_tahfidz_setoran_santri_tanggal;
+		DROP TABLE IF EXISTS erapor_predicate_sets;
+	`)
+	return err
+}
@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upKKMHistorySemester, downKKMHistorySemester)
+}
+
+// kkm_history was created with a UUID semester_id, but grades key semesters as "2025-2026-1"
+func upKKMHistorySemester(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, `
+		ALTER TABLE kkm_history
+		ALTER COLUMN semester_id TYPE VARCHAR(20) USING semester_id::text,
+		ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP;
+		CREATE INDEX IF NOT EXISTS idx_kkm_history_tenant_semester ON kkm_history(tenant_id, semester_id);
+	`); err != nil {
+		return fmt.Errorf("failed to alter kkm_history: %w", err)
+	}
+	return nil
+}
+
+func downKKMHistorySemester(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP INDEX IF EXISTS idx_kkm_history_tenant_semester;
+		DELETE FROM kkm_history;
+		ALTER TABLE kkm_history
+		DROP COLUMN IF EXISTS updated_at,
+		ALTER COLUMN semester_id TYPE UUID USING semester_id::uuid;
+	`)
+	return err
+}
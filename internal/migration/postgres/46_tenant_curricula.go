@@ -0,0 +1,93 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upTenantCurricula, downTenantCurricula)
+}
+
+// upTenantCurricula turns curriculum_references into grading profiles tenants can extend and
+// lets each jenjang of a tenant select the curriculum it follows
+func upTenantCurricula(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, `
+		ALTER TABLE curriculum_references
+		ADD COLUMN IF NOT EXISTS tenant_id UUID REFERENCES tenants(id) ON DELETE CASCADE,
+		ADD COLUMN IF NOT EXISTS mode VARCHAR(20) NOT NULL DEFAULT 'DESKRIPTIF',
+		ADD COLUMN IF NOT EXISTS grading_config JSONB NOT NULL DEFAULT '{}',
+		ADD COLUMN IF NOT EXISTS rapor_template VARCHAR(20) NOT NULL DEFAULT 'MERDEKA';
+
+		ALTER TABLE curriculum_references DROP CONSTRAINT IF EXISTS curriculum_references_code_key;
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_curriculum_references_code
+			ON curriculum_references(COALESCE(tenant_id, '00000000-0000-0000-0000-000000000000'::uuid), code);
+	`); err != nil {
+		return fmt.Errorf("failed to alter curriculum_references: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE curriculum_references SET mode = 'KKM', rapor_template = 'K13', grading_config = '{
+			"use_kkm": true, "kkm_value": 75, "cap_remedial": true,
+			"components": [{"name": "Pengetahuan", "weight": 50}, {"name": "Keterampilan", "weight": 50}],
+			"predicate_rules": []
+		}' WHERE code = 'K13_REVISI' AND tenant_id IS NULL;
+
+		UPDATE curriculum_references SET mode = 'DESKRIPTIF', rapor_template = 'MERDEKA', grading_config = '{
+			"use_descriptive": true,
+			"components": [{"name": "Sumatif", "weight": 60}, {"name": "Formatif", "weight": 40}],
+			"predicate_rules": [
+				{"min_score": 90, "max_score": 100, "predicate": "A", "label": "Sangat Baik"},
+				{"min_score": 80, "max_score": 89, "predicate": "B", "label": "Baik"},
+				{"min_score": 70, "max_score": 79, "predicate": "C", "label": "Cukup"},
+				{"min_score": 0, "max_score": 69, "predicate": "D", "label": "Perlu Bimbingan"}
+			]
+		}' WHERE code = 'MERDEKA' AND tenant_id IS NULL;
+
+		UPDATE curriculum_references SET mode = 'DESKRIPTIF', rapor_template = 'PESANTREN', grading_config = '{
+			"use_descriptive": true,
+			"components": [{"name": "Qiro''ah", "weight": 40}, {"name": "Tarjamah", "weight": 30}, {"name": "Hafalan Matan", "weight": 30}],
+			"predicate_rules": [
+				{"min_score": 90, "max_score": 100, "predicate": "Mumtaz", "label": "Istimewa"},
+				{"min_score": 80, "max_score": 89, "predicate": "Jayyid Jiddan", "label": "Sangat Baik"},
+				{"min_score": 70, "max_score": 79, "predicate": "Jayyid", "label": "Baik"},
+				{"min_score": 0, "max_score": 69, "predicate": "Maqbul", "label": "Cukup"}
+			]
+		}' WHERE code = 'PESANTREN_SALAF' AND tenant_id IS NULL;
+	`); err != nil {
+		return fmt.Errorf("failed to seed curriculum profiles: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS tenant_curricula (
+			tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+			jenjang VARCHAR(10) NOT NULL DEFAULT '', -- '' is the tenant-wide default
+			curriculum_code VARCHAR(50) NOT NULL,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (tenant_id, jenjang)
+		);
+
+		ALTER TABLE tenants ADD COLUMN IF NOT EXISTS school_jenjangs TEXT[];
+	`); err != nil {
+		return fmt.Errorf("failed to create tenant_curricula: %w", err)
+	}
+	return nil
+}
+
+func downTenantCurricula(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP TABLE IF EXISTS tenant_curricula;
+		DELETE FROM curriculum_references WHERE tenant_id IS NOT NULL;
+		DROP INDEX IF EXISTS idx_curriculum_references_code;
+		ALTER TABLE curriculum_references
+		DROP COLUMN IF EXISTS rapor_template,
+		DROP COLUMN IF EXISTS grading_config,
+		DROP COLUMN IF EXISTS mode,
+		DROP COLUMN IF EXISTS tenant_id;
+		ALTER TABLE curriculum_references ADD CONSTRAINT curriculum_references_code_key UNIQUE (code);
+	`)
+	return err
+}
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upGradeStatsIndex, downGradeStatsIndex)
+}
+
+// Grade statistics aggregate a tenant's semester per subject; the index keeps that off a full scan
+func upGradeStatsIndex(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, `
+		CREATE INDEX IF NOT EXISTS idx_student_grades_stats ON student_grades(tenant_id, semester_id, subject_id);
+	`); err != nil {
+		return fmt.Errorf("failed to create idx_student_grades_stats: %w", err)
+	}
+	return nil
+}
+
+func downGradeStatsIndex(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `DROP INDEX IF EXISTS idx_student_grades_stats;`)
+	return err
+}
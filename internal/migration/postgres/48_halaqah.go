@@ -0,0 +1,103 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upHalaqah, downHalaqah)
+}
+
+func upHalaqah(ctx context.Context, tx *sql.Tx) error {
+	// 1. Ustadz contact for tahfidz alerts
+	if _, err := tx.ExecContext(ctx, `
+		ALTER TABLE sekolah_guru ADD COLUMN IF NOT EXISTS no_hp VARCHAR(20);
+	`); err != nil {
+		return fmt.Errorf("failed to alter sekolah_guru: %w", err)
+	}
+
+	// 2. Halaqah groups; a santri belongs to one halaqah at a time
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_halaqah (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL,
+			nama VARCHAR(100) NOT NULL,
+			ustadz_id UUID REFERENCES sekolah_guru(id) ON DELETE SET NULL,
+			kapasitas INT NOT NULL DEFAULT 10,
+			batas_hari_tanpa_setoran INT NOT NULL DEFAULT 7,
+			is_active BOOLEAN NOT NULL DEFAULT TRUE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_sekolah_halaqah_tenant ON sekolah_halaqah(tenant_id);
+		CREATE TRIGGER update_sekolah_halaqah_updated_at
+			BEFORE UPDATE ON sekolah_halaqah
+			FOR EACH ROW
+			EXECUTE FUNCTION update_updated_at_column();
+
+		CREATE TABLE IF NOT EXISTS sekolah_halaqah_anggota (
+			halaqah_id UUID NOT NULL REFERENCES sekolah_halaqah(id) ON DELETE CASCADE,
+			tenant_id UUID NOT NULL,
+			santri_id UUID NOT NULL REFERENCES sekolah_siswa(id) ON DELETE CASCADE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			PRIMARY KEY (halaqah_id, santri_id),
+			UNIQUE (tenant_id, santri_id)
+		);
+	`); err != nil {
+		return fmt.Errorf("failed to create sekolah_halaqah: %w", err)
+	}
+
+	// 3. Hafalan targets per santri per semester
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_tahfidz_target (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL,
+			santri_id UUID NOT NULL REFERENCES sekolah_siswa(id) ON DELETE CASCADE,
+			semester_id VARCHAR(20) NOT NULL,
+			target_juz NUMERIC(4,1) NOT NULL,
+			mulai DATE NOT NULL,
+			selesai DATE NOT NULL,
+			catatan TEXT,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			UNIQUE (tenant_id, santri_id, semester_id)
+		);
+		CREATE TRIGGER update_sekolah_tahfidz_target_updated_at
+			BEFORE UPDATE ON sekolah_tahfidz_target
+			FOR EACH ROW
+			EXECUTE FUNCTION update_updated_at_column();
+	`); err != nil {
+		return fmt.Errorf("failed to create sekolah_tahfidz_target: %w", err)
+	}
+
+	// 4. Alerts sent to the ustadz and wali, so the same alert is not repeated every day
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_tahfidz_alert (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL,
+			santri_id UUID NOT NULL REFERENCES sekolah_siswa(id) ON DELETE CASCADE,
+			jenis VARCHAR(30) NOT NULL,
+			pesan TEXT NOT NULL,
+			dikirim_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_sekolah_tahfidz_alert_santri ON sekolah_tahfidz_alert(tenant_id, santri_id, jenis, dikirim_at);
+	`); err != nil {
+		return fmt.Errorf("failed to create sekolah_tahfidz_alert: %w", err)
+	}
+	return nil
+}
+
+func downHalaqah(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP TABLE IF EXISTS sekolah_tahfidz_alert;
+		DROP TABLE IF EXISTS sekolah_tahfidz_target;
+		DROP TABLE IF EXISTS sekolah_halaqah_anggota;
+		DROP TABLE IF EXISTS sekolah_halaqah;
+		ALTER TABLE sekolah_guru DROP COLUMN IF EXISTS no_hp;
+	`)
+	return err
+}
@@ -0,0 +1,89 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upPoinPelanggaran, downPoinPelanggaran)
+}
+
+func upPoinPelanggaran(ctx context.Context, tx *sql.Tx) error {
+	// 1. Tenant point thresholds; tenants without any use the built-in 25/50/75/100 ladder
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_sanksi_ambang (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL,
+			poin INT NOT NULL CHECK (poin > 0),
+			jenis VARCHAR(50) NOT NULL, -- Teguran, SP1, SP2, Panggilan Wali, ...
+			keterangan TEXT,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			UNIQUE (tenant_id, poin)
+		);
+		CREATE TRIGGER update_sekolah_sanksi_ambang_updated_at
+			BEFORE UPDATE ON sekolah_sanksi_ambang
+			FOR EACH ROW
+			EXECUTE FUNCTION update_updated_at_column();
+	`); err != nil {
+		return fmt.Errorf("failed to create sekolah_sanksi_ambang: %w", err)
+	}
+
+	// 2. Good-behavior credits that reduce the semester's points
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_poin_kredit (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL,
+			santri_id UUID NOT NULL REFERENCES sekolah_siswa(id) ON DELETE CASCADE,
+			tanggal TIMESTAMP WITH TIME ZONE NOT NULL,
+			poin INT NOT NULL CHECK (poin > 0),
+			keterangan TEXT NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_sekolah_poin_kredit_santri ON sekolah_poin_kredit(tenant_id, santri_id, tanggal);
+	`); err != nil {
+		return fmt.Errorf("failed to create sekolah_poin_kredit: %w", err)
+	}
+
+	// 3. Sanctions raised when a santri's points reach a threshold, once per threshold per semester
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_sanksi (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID NOT NULL,
+			santri_id UUID NOT NULL REFERENCES sekolah_siswa(id) ON DELETE CASCADE,
+			semester_id VARCHAR(20) NOT NULL,
+			ambang_poin INT NOT NULL,
+			jenis VARCHAR(50) NOT NULL,
+			total_poin INT NOT NULL,
+			nomor_surat VARCHAR(100) NOT NULL,
+			status VARCHAR(50) NOT NULL DEFAULT 'Pending', -- Pending, Diproses, Selesai
+			catatan TEXT,
+			wali_dikabari_at TIMESTAMP WITH TIME ZONE,
+			selesai_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			UNIQUE (tenant_id, santri_id, semester_id, ambang_poin)
+		);
+		CREATE INDEX IF NOT EXISTS idx_sekolah_sanksi_status ON sekolah_sanksi(tenant_id, status);
+		CREATE TRIGGER update_sekolah_sanksi_updated_at
+			BEFORE UPDATE ON sekolah_sanksi
+			FOR EACH ROW
+			EXECUTE FUNCTION update_updated_at_column();
+	`); err != nil {
+		return fmt.Errorf("failed to create sekolah_sanksi: %w", err)
+	}
+	return nil
+}
+
+func downPoinPelanggaran(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP TABLE IF EXISTS sekolah_sanksi;
+		DROP TABLE IF EXISTS sekolah_poin_kredit;
+		DROP TABLE IF EXISTS sekolah_sanksi_ambang;
+	`)
+	return err
+}
@@ -0,0 +1,50 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upPerizinanWorkflow, downPerizinanWorkflow)
+}
+
+func upPerizinanWorkflow(ctx context.Context, tx *sql.Tx) error {
+	// Approval, gate check-out/check-in and overdue tracking of santri leave
+	if _, err := tx.ExecContext(ctx, `
+		ALTER TABLE sekolah_perizinan
+			ADD COLUMN IF NOT EXISTS catatan_penolakan TEXT,
+			ADD COLUMN IF NOT EXISTS disetujui_at TIMESTAMP WITH TIME ZONE,
+			ADD COLUMN IF NOT EXISTS kode_gate_pass VARCHAR(20),
+			ADD COLUMN IF NOT EXISTS keluar_at TIMESTAMP WITH TIME ZONE,
+			ADD COLUMN IF NOT EXISTS kembali_at TIMESTAMP WITH TIME ZONE,
+			ADD COLUMN IF NOT EXISTS terlambat_dikabari_at TIMESTAMP WITH TIME ZONE,
+			ADD COLUMN IF NOT EXISTS pelanggaran_id UUID REFERENCES sekolah_pelanggaran_siswa(id) ON DELETE SET NULL;
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_sekolah_perizinan_kode_gate_pass
+			ON sekolah_perizinan(kode_gate_pass) WHERE kode_gate_pass IS NOT NULL;
+		CREATE INDEX IF NOT EXISTS idx_sekolah_perizinan_tenant_status
+			ON sekolah_perizinan(tenant_id, status);
+	`); err != nil {
+		return fmt.Errorf("failed to extend sekolah_perizinan: %w", err)
+	}
+	return nil
+}
+
+func downPerizinanWorkflow(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP INDEX IF EXISTS idx_sekolah_perizinan_tenant_status;
+		DROP INDEX IF EXISTS idx_sekolah_perizinan_kode_gate_pass;
+		ALTER TABLE sekolah_perizinan
+			DROP COLUMN IF EXISTS pelanggaran_id,
+			DROP COLUMN IF EXISTS terlambat_dikabari_at,
+			DROP COLUMN IF EXISTS kembali_at,
+			DROP COLUMN IF EXISTS keluar_at,
+			DROP COLUMN IF EXISTS kode_gate_pass,
+			DROP COLUMN IF EXISTS disetujui_at,
+			DROP COLUMN IF EXISTS catatan_penolakan;
+	`)
+	return err
+}
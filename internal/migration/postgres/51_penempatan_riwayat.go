@@ -0,0 +1,42 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upPenempatanRiwayat, downPenempatanRiwayat)
+}
+
+func upPenempatanRiwayat(ctx context.Context, tx *sql.Tx) error {
+	// 1. Close all but the newest active placement of santri placed in several rooms
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE sekolah_penempatan p
+		SET status = 'Pindah', tanggal_keluar = COALESCE(p.tanggal_keluar, CURRENT_DATE)
+		WHERE p.status = 'Aktif' AND EXISTS (
+			SELECT 1 FROM sekolah_penempatan n
+			WHERE n.tenant_id = p.tenant_id AND n.santri_id = p.santri_id AND n.status = 'Aktif'
+				AND (n.tanggal_masuk, n.created_at, n.id) > (p.tanggal_masuk, p.created_at, p.id)
+		);
+	`); err != nil {
+		return fmt.Errorf("failed to close duplicate penempatan: %w", err)
+	}
+
+	// 2. One active room per santri; closed rows are the move history
+	if _, err := tx.ExecContext(ctx, `
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_sekolah_penempatan_santri_aktif
+			ON sekolah_penempatan(tenant_id, santri_id) WHERE status = 'Aktif';
+	`); err != nil {
+		return fmt.Errorf("failed to index active penempatan: %w", err)
+	}
+	return nil
+}
+
+func downPenempatanRiwayat(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `DROP INDEX IF EXISTS idx_sekolah_penempatan_santri_aktif;`)
+	return err
+}
@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upNomorUrut, downNomorUrut)
+}
+
+func upNomorUrut(ctx context.Context, tx *sql.Tx) error {
+	// Per-tenant running numbers (PPDB registration, sanction letters) handed out atomically
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_nomor_urut (
+			tenant_id UUID NOT NULL,
+			jenis VARCHAR(30) NOT NULL,
+			periode VARCHAR(20) NOT NULL,
+			nilai INT NOT NULL DEFAULT 0,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			PRIMARY KEY (tenant_id, jenis, periode)
+		);
+	`); err != nil {
+		return fmt.Errorf("failed to create sekolah_nomor_urut: %w", err)
+	}
+	return nil
+}
+
+func downNomorUrut(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS sekolah_nomor_urut;`)
+	return err
+}
@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upRaporLogo, downRaporLogo)
+}
+
+func upRaporLogo(ctx context.Context, tx *sql.Tx) error {
+	// Logo bytes frozen at rapor generation, keyed by their SHA-256 so every
+	// snapshot of a tenant shares one copy and reprints never refetch the URL
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS sekolah_rapor_logo (
+			tenant_id UUID NOT NULL,
+			hash VARCHAR(64) NOT NULL,
+			logo BYTEA NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			PRIMARY KEY (tenant_id, hash)
+		);
+	`); err != nil {
+		return fmt.Errorf("failed to create sekolah_rapor_logo: %w", err)
+	}
+	return nil
+}
+
+func downRaporLogo(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS sekolah_rapor_logo;`)
+	return err
+}
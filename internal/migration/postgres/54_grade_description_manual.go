@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upGradeDescriptionManual, downGradeDescriptionManual)
+}
+
+func upGradeDescriptionManual(ctx context.Context, tx *sql.Tx) error {
+	// Capaian reworded by the teacher survive later recalculations of the grade
+	if _, err := tx.ExecContext(ctx, `
+		ALTER TABLE student_grades
+		ADD COLUMN IF NOT EXISTS description_manual BOOLEAN NOT NULL DEFAULT FALSE;
+	`); err != nil {
+		return fmt.Errorf("failed to alter student_grades: %w", err)
+	}
+	return nil
+}
+
+func downGradeDescriptionManual(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `ALTER TABLE student_grades DROP COLUMN IF EXISTS description_manual;`)
+	return err
+}
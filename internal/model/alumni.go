@@ -0,0 +1,161 @@
+package model
+
+import "time"
+
+// ==========================================
+// ALUMNI & TRACER STUDY MODELS
+// ==========================================
+
+// Kegiatan alumni setelah lulus
+const (
+	AlumniKegiatanKuliah       = "Kuliah"
+	AlumniKegiatanBekerja      = "Bekerja"
+	AlumniKegiatanWirausaha    = "Wirausaha"
+	AlumniKegiatanMondok       = "Mondok"
+	AlumniKegiatanBelumBekerja = "Belum Bekerja"
+)
+
+// IsValidAlumniKegiatan reports whether k is a known kegiatan (empty means unknown)
+func IsValidAlumniKegiatan(k string) bool {
+	switch k {
+	case "", AlumniKegiatanKuliah, AlumniKegiatanBekerja, AlumniKegiatanWirausaha,
+		AlumniKegiatanMondok, AlumniKegiatanBelumBekerja:
+		return true
+	}
+	return false
+}
+
+// Tracer survey status
+const (
+	TracerStatusDraft   = "draft"
+	TracerStatusAktif   = "aktif"
+	TracerStatusSelesai = "selesai"
+)
+
+// Tracer question types
+const (
+	TracerTipeTeks    = "teks"
+	TracerTipePilihan = "pilihan"
+)
+
+// Alumni is a graduate kept after the siswa record is marked Lulus
+type Alumni struct {
+	ID            string `json:"id" db:"id"`
+	TenantID      string `json:"tenant_id" db:"tenant_id"`
+	SiswaID       string `json:"siswa_id" db:"siswa_id"` // Empty for alumni entered manually
+	Nama          string `json:"nama" db:"nama"`
+	NIS           string `json:"nis" db:"nis"`
+	NISN          string `json:"nisn" db:"nisn"`
+	TahunLulus    int    `json:"tahun_lulus" db:"tahun_lulus"`
+	KelasTerakhir string `json:"kelas_terakhir" db:"kelas_terakhir"`
+
+	// Kontak
+	NoHP   string `json:"no_hp" db:"no_hp"`
+	Email  string `json:"email" db:"email"`
+	Alamat string `json:"alamat" db:"alamat"`
+
+	// Studi lanjut / pekerjaan
+	Kegiatan  string `json:"kegiatan" db:"kegiatan"`
+	Institusi string `json:"institusi" db:"institusi"` // Kampus, perusahaan or pesantren
+	Jurusan   string `json:"jurusan" db:"jurusan"`     // Program studi or jabatan
+
+	Token              string     `json:"-" db:"token"` // Self-service link token
+	TerakhirDiperbarui *time.Time `json:"terakhir_diperbarui,omitempty" db:"terakhir_diperbarui"`
+	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// AlumniFilter for listing alumni
+type AlumniFilter struct {
+	TahunLulus int
+	Kegiatan   string
+	Search     string
+}
+
+// KelulusanInput marks siswa as Lulus and adds them to the alumni registry
+type KelulusanInput struct {
+	TahunLulus int      `json:"tahun_lulus"`
+	KelasID    string   `json:"kelas_id"`  // Graduate a whole class
+	SiswaIDs   []string `json:"siswa_ids"` // Or selected students
+}
+
+// AlumniProfilInput is what an alumni may change through the self-service link
+type AlumniProfilInput struct {
+	NoHP      string `json:"no_hp"`
+	Email     string `json:"email"`
+	Alamat    string `json:"alamat"`
+	Kegiatan  string `json:"kegiatan"`
+	Institusi string `json:"institusi"`
+	Jurusan   string `json:"jurusan"`
+}
+
+// TracerPertanyaan is one question of a tracer study survey
+type TracerPertanyaan struct {
+	Kode       string   `json:"kode"`
+	Pertanyaan string   `json:"pertanyaan"`
+	Tipe       string   `json:"tipe"` // teks, pilihan
+	Pilihan    []string `json:"pilihan,omitempty"`
+	Wajib      bool     `json:"wajib"`
+}
+
+// TracerSurvey is a tracer study campaign targeting a range of graduation years
+type TracerSurvey struct {
+	ID               string             `json:"id" db:"id"`
+	TenantID         string             `json:"tenant_id" db:"tenant_id"`
+	Judul            string             `json:"judul" db:"judul"`
+	Deskripsi        string             `json:"deskripsi" db:"deskripsi"`
+	TahunLulusDari   int                `json:"tahun_lulus_dari" db:"tahun_lulus_dari"`     // 0 = no lower bound
+	TahunLulusSampai int                `json:"tahun_lulus_sampai" db:"tahun_lulus_sampai"` // 0 = no upper bound
+	Pertanyaan       []TracerPertanyaan `json:"pertanyaan" db:"-"`
+	Status           string             `json:"status" db:"status"`
+	TanggalMulai     time.Time          `json:"tanggal_mulai" db:"tanggal_mulai"`
+	TanggalSelesai   time.Time          `json:"tanggal_selesai" db:"tanggal_selesai"`
+	JumlahRespon     int                `json:"jumlah_respon" db:"jumlah_respon"` // Calculated
+	CreatedAt        time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time          `json:"updated_at" db:"updated_at"`
+}
+
+// Targets reports whether an alumni of the given year is within the survey range
+func (s *TracerSurvey) Targets(tahunLulus int) bool {
+	if s.TahunLulusDari > 0 && tahunLulus < s.TahunLulusDari {
+		return false
+	}
+	if s.TahunLulusSampai > 0 && tahunLulus > s.TahunLulusSampai {
+		return false
+	}
+	return true
+}
+
+// IsOpen reports whether the survey accepts answers at the given time
+func (s *TracerSurvey) IsOpen(now time.Time) bool {
+	if s.Status != TracerStatusAktif {
+		return false
+	}
+	day := now.Truncate(24 * time.Hour)
+	return !day.Before(s.TanggalMulai.Truncate(24*time.Hour)) && !day.After(s.TanggalSelesai.Truncate(24*time.Hour))
+}
+
+// TracerJawaban is one alumni's answers to a survey
+type TracerJawaban struct {
+	ID         string            `json:"id" db:"id"`
+	SurveyID   string            `json:"survey_id" db:"survey_id"`
+	AlumniID   string            `json:"alumni_id" db:"alumni_id"`
+	AlumniNama string            `json:"alumni_nama" db:"alumni_nama"` // Joined
+	TahunLulus int               `json:"tahun_lulus" db:"tahun_lulus"` // Joined
+	Jawaban    map[string]string `json:"jawaban" db:"-"`
+	CreatedAt  time.Time         `json:"created_at" db:"created_at"`
+}
+
+// AlumniStatistik summarises one graduation year for akreditasi evidence
+type AlumniStatistik struct {
+	TahunLulus     int     `json:"tahun_lulus" db:"tahun_lulus"`
+	Total          int     `json:"total" db:"total"`
+	Kuliah         int     `json:"kuliah" db:"kuliah"`
+	Bekerja        int     `json:"bekerja" db:"bekerja"`
+	Wirausaha      int     `json:"wirausaha" db:"wirausaha"`
+	Mondok         int     `json:"mondok" db:"mondok"`
+	BelumBekerja   int     `json:"belum_bekerja" db:"belum_bekerja"`
+	TidakDiketahui int     `json:"tidak_diketahui" db:"tidak_diketahui"`
+	Terlacak       int     `json:"terlacak" db:"terlacak"` // Updated their data via self-service
+	PersenTerlacak float64 `json:"persen_terlacak" db:"-"`
+}
@@ -16,30 +16,96 @@ type Asrama struct {
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// Asrama kinds
+const (
+	AsramaPutra = "Putra"
+	AsramaPutri = "Putri"
+)
+
 type Kamar struct {
-	ID         string    `json:"id" db:"id"`
-	TenantID   string    `json:"tenant_id" db:"tenant_id"`
-	AsramaID   string    `json:"asrama_id" db:"asrama_id"`
-	AsramaNama string    `json:"asrama_nama" db:"asrama_nama"` // Populated via join
-	Nomor      string    `json:"nomor" db:"nomor"`
-	Kapasitas  int       `json:"kapasitas" db:"kapasitas"`
-	Terisi     int       `json:"terisi" db:"terisi"` // Calculated
-	Status     string    `json:"status" db:"status"` // Tersedia, Penuh
-	CreatedAt  time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+	ID          string    `json:"id" db:"id"`
+	TenantID    string    `json:"tenant_id" db:"tenant_id"`
+	AsramaID    string    `json:"asrama_id" db:"asrama_id"`
+	AsramaNama  string    `json:"asrama_nama" db:"asrama_nama"`   // Populated via join
+	AsramaJenis string    `json:"asrama_jenis" db:"asrama_jenis"` // Populated via join
+	Nomor       string    `json:"nomor" db:"nomor"`
+	Kapasitas   int       `json:"kapasitas" db:"kapasitas"`
+	Terisi      int       `json:"terisi" db:"terisi"` // Calculated
+	Status      string    `json:"status" db:"status"` // Tersedia, Penuh, Perbaikan
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// KamarPerbaikan is a room that cannot take santri whatever its capacity
+const KamarPerbaikan = "Perbaikan"
+
 type Penempatan struct {
-	ID           string    `json:"id" db:"id"`
-	TenantID     string    `json:"tenant_id" db:"tenant_id"`
-	SantriID     string    `json:"santri_id" db:"santri_id"`
-	SantriNama   string    `json:"santri_nama" db:"santri_nama"` // Populated via join
-	KamarID      string    `json:"kamar_id" db:"kamar_id"`
-	KamarNomor   string    `json:"kamar_nomor" db:"kamar_nomor"` // Populated via join
-	AsramaNama   string    `json:"asrama_nama" db:"asrama_nama"` // Populated via join
-	TanggalMasuk time.Time `json:"tanggal_masuk" db:"tanggal_masuk"`
-	Status       string    `json:"status" db:"status"`
-	Keterangan   string    `json:"keterangan" db:"keterangan"`
-	CreatedAt    time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+	ID            string     `json:"id" db:"id"`
+	TenantID      string     `json:"tenant_id" db:"tenant_id"`
+	SantriID      string     `json:"santri_id" db:"santri_id"`
+	SantriNama    string     `json:"santri_nama" db:"santri_nama"` // Populated via join
+	KamarID       string     `json:"kamar_id" db:"kamar_id"`
+	KamarNomor    string     `json:"kamar_nomor" db:"kamar_nomor"` // Populated via join
+	AsramaID      string     `json:"asrama_id" db:"asrama_id"`     // Populated via join
+	AsramaNama    string     `json:"asrama_nama" db:"asrama_nama"` // Populated via join
+	TanggalMasuk  time.Time  `json:"tanggal_masuk" db:"tanggal_masuk"`
+	TanggalKeluar *time.Time `json:"tanggal_keluar,omitempty" db:"tanggal_keluar"` // set once the placement is closed
+	Status        string     `json:"status" db:"status"`                           // Aktif, Pindah, Keluar
+	Keterangan    string     `json:"keterangan" db:"keterangan"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Penempatan statuses; a santri has at most one Aktif placement
+const (
+	PenempatanAktif  = "Aktif"
+	PenempatanPindah = "Pindah" // moved to another room
+	PenempatanKeluar = "Keluar" // left the asrama
+)
+
+// Orders in which bulk allocation fills rooms, keeping santri of one kelas or of similar age together
+const (
+	AlokasiPerKelas = "kelas"
+	AlokasiPerUsia  = "usia"
+)
+
+// AlokasiInput selects the santri without a room to allocate: all active ones, or only SantriIDs
+// and/or those of KelasID, into the rooms of AsramaID or of every asrama
+type AlokasiInput struct {
+	SantriIDs    []string  `json:"santri_ids"`
+	KelasID      string    `json:"kelas_id"`
+	AsramaID     string    `json:"asrama_id"`
+	Urutan       string    `json:"urutan"`        // kelas, usia
+	TanggalMasuk time.Time `json:"tanggal_masuk"` // default today
+	Simulasi     bool      `json:"simulasi"`      // plan only, save nothing
+}
+
+// AlokasiGagal is a santri bulk allocation could not place
+type AlokasiGagal struct {
+	SantriID   string `json:"santri_id"`
+	SantriNama string `json:"santri_nama"`
+	Alasan     string `json:"alasan"`
+}
+
+// AlokasiHasil is the outcome of a bulk allocation; Disimpan is false for a simulation
+type AlokasiHasil struct {
+	Penempatan []Penempatan   `json:"penempatan"`
+	Gagal      []AlokasiGagal `json:"gagal"`
+	Disimpan   bool           `json:"disimpan"`
+}
+
+// OkupansiAsrama is the occupancy of one asrama and its rooms
+type OkupansiAsrama struct {
+	AsramaID    string  `json:"asrama_id"`
+	AsramaNama  string  `json:"asrama_nama"`
+	Jenis       string  `json:"jenis"`
+	MusyrifNama string  `json:"musyrif_nama"`
+	JumlahKamar int     `json:"jumlah_kamar"`
+	KamarPenuh  int     `json:"kamar_penuh"`
+	KamarKosong int     `json:"kamar_kosong"`
+	Kapasitas   int     `json:"kapasitas"`
+	Terisi      int     `json:"terisi"`
+	Sisa        int     `json:"sisa"`
+	Persen      float64 `json:"persen"`
+	Kamar       []Kamar `json:"kamar"`
 }
@@ -0,0 +1,61 @@
+package model
+
+// ==========================================
+// DAPODIK SYNC MODELS
+// ==========================================
+
+// Dapodik data sets (one per template sheet)
+const (
+	DapodikJenisSiswa  = "siswa"
+	DapodikJenisGuru   = "guru"
+	DapodikJenisRombel = "rombel"
+)
+
+// Diff actions for an imported row
+const (
+	DapodikAksiBaru = "baru" // Not found locally, will be created
+	DapodikAksiUbah = "ubah" // Matched, at least one field differs
+	DapodikAksiSama = "sama" // Matched, nothing to change
+)
+
+// DapodikPerubahan is one changed field of a matched record
+type DapodikPerubahan struct {
+	Field string `json:"field"`
+	Lama  string `json:"lama"`
+	Baru  string `json:"baru"`
+}
+
+// DapodikDiffItem is the preview of one imported row against local data
+type DapodikDiffItem struct {
+	Baris     int                `json:"baris"` // Row number in the sheet
+	Kunci     string             `json:"kunci"` // Matching key, e.g. nisn:0012345678
+	Nama      string             `json:"nama"`
+	Aksi      string             `json:"aksi"`
+	LocalID   string             `json:"local_id,omitempty"`
+	Perubahan []DapodikPerubahan `json:"perubahan,omitempty"`
+}
+
+// DapodikBarisError is a row that could not be imported
+type DapodikBarisError struct {
+	Baris int    `json:"baris"`
+	Pesan string `json:"pesan"`
+}
+
+// DapodikPreview summarises an import file before (or after) applying it
+type DapodikPreview struct {
+	Jenis   string              `json:"jenis"`
+	Total   int                 `json:"total"`
+	Baru    int                 `json:"baru"`
+	Ubah    int                 `json:"ubah"`
+	Sama    int                 `json:"sama"`
+	Items   []DapodikDiffItem   `json:"items"`
+	Errors  []DapodikBarisError `json:"errors,omitempty"`
+	Applied bool                `json:"applied"`
+}
+
+// DapodikImport holds the records to write; entries with an empty ID are inserted
+type DapodikImport struct {
+	Siswa  []Siswa
+	Guru   []Guru
+	Rombel []Kelas
+}
@@ -0,0 +1,77 @@
+package model
+
+import "time"
+
+// ==========================================
+// EKSTRAKURIKULER MODELS
+// ==========================================
+
+// Ekskul represents an extracurricular activity with its pembina
+type Ekskul struct {
+	ID          string    `json:"id" db:"id"`
+	TenantID    string    `json:"tenant_id" db:"tenant_id"`
+	Nama        string    `json:"nama" db:"nama"`           // "Pramuka", "Paskibra"
+	Deskripsi   string    `json:"deskripsi" db:"deskripsi"` // Short description of the activity
+	PembinaID   *string   `json:"pembina_id" db:"pembina_id"`
+	PembinaNama string    `json:"pembina_nama" db:"pembina_nama"` // Joined from sekolah_guru
+	IsWajib     bool      `json:"is_wajib" db:"is_wajib"`         // Ekskul wajib (e.g. Pramuka in K13)
+	IsActive    bool      `json:"is_active" db:"is_active"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// EkskulAnggota represents a member's enrollment in an ekskul for one semester,
+// including the end-of-semester assessment that flows into the rapor
+type EkskulAnggota struct {
+	ID          string    `json:"id" db:"id"`
+	TenantID    string    `json:"tenant_id" db:"tenant_id"`
+	EkskulID    string    `json:"ekskul_id" db:"ekskul_id"`
+	EkskulNama  string    `json:"ekskul_nama" db:"ekskul_nama"` // Joined
+	SantriID    string    `json:"santri_id" db:"santri_id"`
+	SantriNama  string    `json:"santri_nama" db:"santri_nama"` // Joined
+	SemesterID  string    `json:"semester_id" db:"semester_id"` // "2025-2026-1"
+	Predikat    string    `json:"predikat" db:"predikat"`       // A/B/C
+	Deskripsi   string    `json:"deskripsi" db:"deskripsi"`
+	JumlahHadir int       `json:"jumlah_hadir" db:"jumlah_hadir"` // Calculated
+	JumlahSesi  int       `json:"jumlah_sesi" db:"jumlah_sesi"`   // Calculated
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// EkskulSesi represents a single meeting of an ekskul
+type EkskulSesi struct {
+	ID         string           `json:"id" db:"id"`
+	TenantID   string           `json:"tenant_id" db:"tenant_id"`
+	EkskulID   string           `json:"ekskul_id" db:"ekskul_id"`
+	SemesterID string           `json:"semester_id" db:"semester_id"`
+	Tanggal    time.Time        `json:"tanggal" db:"tanggal"`
+	Materi     string           `json:"materi" db:"materi"`
+	Presensi   []EkskulPresensi `json:"presensi,omitempty" db:"-"`
+	CreatedAt  time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// EkskulPresensi is the attendance of one member in one session
+type EkskulPresensi struct {
+	ID         string `json:"id" db:"id"`
+	SesiID     string `json:"sesi_id" db:"sesi_id"`
+	AnggotaID  string `json:"anggota_id" db:"anggota_id"`
+	SantriNama string `json:"santri_nama" db:"santri_nama"` // Joined
+	Status     string `json:"status" db:"status"`           // Hadir, Izin, Sakit, Alpha
+	Catatan    string `json:"catatan" db:"catatan"`
+}
+
+// EkskulPenilaianInput for saving the end-of-semester assessment of a member
+type EkskulPenilaianInput struct {
+	AnggotaID string `json:"anggota_id"`
+	Predikat  string `json:"predikat"`
+	Deskripsi string `json:"deskripsi"`
+}
+
+// Ekskul attendance statuses
+const (
+	EkskulPresensiHadir = "Hadir"
+	EkskulPresensiIzin  = "Izin"
+	EkskulPresensiSakit = "Sakit"
+	EkskulPresensiAlpha = "Alpha"
+)
@@ -8,18 +8,55 @@ import "time"
 
 // GradingComponent represents a single grading component (e.g., Sumatif, Formatif)
 type GradingComponent struct {
-	Name   string `json:"name"`   // "Sumatif", "Formatif", "Praktik"
-	Weight int    `json:"weight"` // Percentage (0-100)
+	Name        string `json:"name"`                  // "Sumatif", "Formatif", "Praktik"
+	Weight      int    `json:"weight"`                // Percentage (0-100)
+	Aggregation string `json:"aggregation,omitempty"` // How assessment entries are combined: average (default), latest, best
 }
 
+// Aggregation rules for combining a component's assessment entries
+const (
+	AggregationAverage = "average"
+	AggregationLatest  = "latest"
+	AggregationBest    = "best"
+)
+
 // GradingConfig represents the grading configuration for a subject
 // Stored as JSONB in PostgreSQL for flexibility
 type GradingConfig struct {
 	UseKKM         bool               `json:"use_kkm"`         // K13 style with KKM threshold
 	KKMValue       int                `json:"kkm_value"`       // Kriteria Ketuntasan Minimal (e.g., 75)
+	CapRemedial    bool               `json:"cap_remedial"`    // Remedial results are capped at the KKM
 	UseDescriptive bool               `json:"use_descriptive"` // Kurikulum Merdeka style with descriptions
 	Components     []GradingComponent `json:"components"`      // Weighted components
 	PredicateRules []PredicateRule    `json:"predicate_rules"` // Rules for converting score to predicate
+
+	// DescriptionTemplate shapes the capaian sentences generated from TP marks (Merdeka)
+	DescriptionTemplate *DescriptionTemplate `json:"description_template,omitempty"`
+	// Tahfidz sets the semester targets a PESANTREN_TAHFIDZ grade is measured against
+	Tahfidz *TahfidzTarget `json:"tahfidz,omitempty"`
+}
+
+// TahfidzTarget is what a santri is expected to reach in one semester
+type TahfidzTarget struct {
+	TargetJuz        int `json:"target_juz"`         // juz of new memorization (ziyadah), default 1
+	MurajaahPerPekan int `json:"murajaah_per_pekan"` // murajaah setoran expected each week, default 1
+}
+
+// DescriptionTemplate holds the sentences for the highest and lowest TPs.
+// {nama} is replaced by the student name and {tp} by the joined TP descriptions.
+type DescriptionTemplate struct {
+	High  string `json:"high"`   // "Ananda {nama} menunjukkan penguasaan yang baik dalam {tp}."
+	Low   string `json:"low"`    // "Ananda {nama} perlu bimbingan dalam {tp}."
+	MaxTP int    `json:"max_tp"` // TPs mentioned per sentence, default 2
+}
+
+// DefaultDescriptionTemplate is used when a subject has no template of its own
+func DefaultDescriptionTemplate() DescriptionTemplate {
+	return DescriptionTemplate{
+		High:  "Ananda {nama} menunjukkan penguasaan yang baik dalam {tp}.",
+		Low:   "Ananda {nama} perlu bimbingan dalam {tp}.",
+		MaxTP: 2,
+	}
 }
 
 // PredicateRule defines how to convert numeric score to predicate
@@ -41,6 +78,10 @@ type Subject struct {
 	IsActive      bool          `json:"is_active"`
 	CreatedAt     time.Time     `json:"created_at"`
 	UpdatedAt     time.Time     `json:"updated_at"`
+
+	// Curriculum is the tenant curriculum profile named by Type, resolved at runtime for
+	// types outside the built-in ones
+	Curriculum *CurriculumProfile `json:"curriculum,omitempty"`
 }
 
 // SubjectInput for creating/updating subjects
@@ -54,33 +95,35 @@ type SubjectInput struct {
 
 // StudentGrade represents a student's grade for a subject in a semester
 type StudentGrade struct {
-	ID              string    `json:"id"`
-	TenantID        string    `json:"tenant_id"`
-	StudentID       string    `json:"student_id"`
-	StudentName     string    `json:"student_name,omitempty"` // Joined from students table
-	SubjectID       string    `json:"subject_id"`
-	SubjectName     string    `json:"subject_name,omitempty"` // Joined from subjects table
-	SemesterID      string    `json:"semester_id"`            // "2025-2026-1" (Year-Semester)
-	ScoreNumeric    float64   `json:"score_numeric"`          // Final calculated score (0-100)
-	ScorePredicate  string    `json:"score_predicate"`        // A/B/C/D
-	DescriptionHigh string    `json:"description_high"`       // Kompetensi tertinggi
-	DescriptionLow  string    `json:"description_low"`        // Kompetensi perlu ditingkatkan
-	ComponentScores []float64 `json:"component_scores"`       // Scores per component (JSONB)
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	ID                string    `json:"id"`
+	TenantID          string    `json:"tenant_id"`
+	StudentID         string    `json:"student_id"`
+	StudentName       string    `json:"student_name,omitempty"` // Joined from students table
+	SubjectID         string    `json:"subject_id"`
+	SubjectName       string    `json:"subject_name,omitempty"` // Joined from subjects table
+	SemesterID        string    `json:"semester_id"`            // "2025-2026-1" (Year-Semester)
+	ScoreNumeric      float64   `json:"score_numeric"`          // Final calculated score (0-100)
+	ScorePredicate    string    `json:"score_predicate"`        // A/B/C/D
+	DescriptionHigh   string    `json:"description_high"`       // Kompetensi tertinggi
+	DescriptionLow    string    `json:"description_low"`        // Kompetensi perlu ditingkatkan
+	DescriptionManual bool      `json:"description_manual"`     // Capaian reworded by hand, kept on recalculation
+	ComponentScores   []float64 `json:"component_scores"`       // Scores per component (JSONB)
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
 }
 
 // StudentGradeInput for saving grades
 type StudentGradeInput struct {
-	TenantID        string    `json:"tenant_id"`
-	StudentID       string    `json:"student_id"`
-	SubjectID       string    `json:"subject_id"`
-	SemesterID      string    `json:"semester_id"`
-	ScoreNumeric    float64   `json:"score_numeric"`
-	ScorePredicate  string    `json:"score_predicate"`
-	DescriptionHigh string    `json:"description_high"`
-	DescriptionLow  string    `json:"description_low"`
-	ComponentScores []float64 `json:"component_scores"`
+	TenantID          string    `json:"tenant_id"`
+	StudentID         string    `json:"student_id"`
+	SubjectID         string    `json:"subject_id"`
+	SemesterID        string    `json:"semester_id"`
+	ScoreNumeric      float64   `json:"score_numeric"`
+	ScorePredicate    string    `json:"score_predicate"`
+	DescriptionHigh   string    `json:"description_high"`
+	DescriptionLow    string    `json:"description_low"`
+	DescriptionManual bool      `json:"-"`
+	ComponentScores   []float64 `json:"component_scores"`
 }
 
 // BatchGradeInput for bulk saving grades
@@ -91,6 +134,171 @@ type BatchGradeInput struct {
 	Grades     []StudentGradeInput `json:"grades"`
 }
 
+// AssessmentEntry is a single raw assessment (e.g. "Sumatif 2", a Formatif quiz) recorded
+// against one grading component. The component score is aggregated from all its entries.
+type AssessmentEntry struct {
+	ID         string    `json:"id" db:"id"`
+	TenantID   string    `json:"tenant_id" db:"tenant_id"`
+	StudentID  string    `json:"student_id" db:"student_id"`
+	SubjectID  string    `json:"subject_id" db:"subject_id"`
+	SemesterID string    `json:"semester_id" db:"semester_id"`
+	Component  string    `json:"component" db:"component"` // GradingComponent.Name
+	Title      string    `json:"title" db:"title"`         // "Sumatif 1", "Kuis Bab 3"
+	Topic      string    `json:"topic" db:"topic"`
+	Date       time.Time `json:"date" db:"date"`
+	Score      float64   `json:"score" db:"score"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// AssessmentEntryInput for recording or correcting an assessment
+type AssessmentEntryInput struct {
+	TenantID   string  `json:"tenant_id"`
+	StudentID  string  `json:"student_id"`
+	SubjectID  string  `json:"subject_id"`
+	SemesterID string  `json:"semester_id"`
+	Component  string  `json:"component"`
+	Title      string  `json:"title"`
+	Topic      string  `json:"topic"`
+	Date       string  `json:"date"` // YYYY-MM-DD
+	Score      float64 `json:"score"`
+}
+
+// Remedial session types
+const (
+	RemedialTypeRemedial   = "remedial"   // below KKM, may raise the final score
+	RemedialTypeEnrichment = "enrichment" // pengayaan for students at/above KKM, recorded only
+)
+
+// RemedialSession is an append-only record of a remedial or enrichment session.
+// OriginalScore is the final grade before the session, FinalScore what it became.
+type RemedialSession struct {
+	ID            string    `json:"id" db:"id"`
+	TenantID      string    `json:"tenant_id" db:"tenant_id"`
+	StudentID     string    `json:"student_id" db:"student_id"`
+	StudentName   string    `json:"student_name,omitempty" db:"student_name"`
+	SubjectID     string    `json:"subject_id" db:"subject_id"`
+	SemesterID    string    `json:"semester_id" db:"semester_id"`
+	Type          string    `json:"type" db:"type"`
+	Date          time.Time `json:"date" db:"date"`
+	Topic         string    `json:"topic" db:"topic"`
+	KKM           int       `json:"kkm" db:"kkm"`
+	OriginalScore float64   `json:"original_score" db:"original_score"`
+	RemedialScore float64   `json:"remedial_score" db:"remedial_score"`
+	FinalScore    float64   `json:"final_score" db:"final_score"`
+	Capped        bool      `json:"capped" db:"capped"`
+	Notes         string    `json:"notes" db:"notes"`
+	CreatedBy     string    `json:"created_by" db:"created_by"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// RemedialInput for recording a session
+type RemedialInput struct {
+	TenantID   string  `json:"tenant_id"`
+	StudentID  string  `json:"student_id"`
+	SubjectID  string  `json:"subject_id"`
+	SemesterID string  `json:"semester_id"`
+	Type       string  `json:"type"` // remedial (default), enrichment
+	Date       string  `json:"date"` // YYYY-MM-DD
+	Topic      string  `json:"topic"`
+	Score      float64 `json:"score"`
+	Notes      string  `json:"notes"`
+	CreatedBy  string  `json:"-"`
+}
+
+// RemedialCandidate is a student whose final grade is still below the subject KKM
+type RemedialCandidate struct {
+	StudentID   string  `json:"student_id"`
+	StudentName string  `json:"student_name"`
+	Score       float64 `json:"score"`
+	KKM         int     `json:"kkm"`
+	Sessions    int     `json:"sessions"` // remedial sessions recorded so far
+}
+
+// KKMHistory pins the KKM of a subject for one semester. Semesters without an entry use the
+// subject's current KKMValue; graded semesters are pinned before that value changes.
+type KKMHistory struct {
+	ID         string    `json:"id" db:"id"`
+	TenantID   string    `json:"tenant_id" db:"tenant_id"`
+	SubjectID  string    `json:"subject_id" db:"subject_id"`
+	SemesterID string    `json:"semester_id" db:"semester_id"`
+	KKMValue   int       `json:"kkm_value" db:"kkm_value"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Fase Kurikulum Merdeka
+var FaseMerdeka = []string{"A", "B", "C", "D", "E", "F"}
+
+// CapaianPembelajaran (CP) is the per-fase learning outcome of a subject, grouped by elemen
+type CapaianPembelajaran struct {
+	ID        string    `json:"id" db:"id"`
+	TenantID  string    `json:"tenant_id" db:"tenant_id"`
+	SubjectID string    `json:"subject_id" db:"subject_id"`
+	Fase      string    `json:"fase" db:"fase"`
+	Elemen    string    `json:"elemen" db:"elemen"`
+	Deskripsi string    `json:"deskripsi" db:"deskripsi"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+
+	TujuanList []TujuanPembelajaran `json:"tujuan_list,omitempty" db:"-"`
+}
+
+// TujuanPembelajaran (TP) is an assessable objective derived from a CP
+type TujuanPembelajaran struct {
+	ID        string    `json:"id" db:"id"`
+	TenantID  string    `json:"tenant_id" db:"tenant_id"`
+	CPID      string    `json:"cp_id" db:"cp_id"`
+	SubjectID string    `json:"subject_id" db:"subject_id"`
+	Kode      string    `json:"kode" db:"kode"`           // "TP 7.1"
+	Deskripsi string    `json:"deskripsi" db:"deskripsi"` // phrased to complete "...dalam {tp}"
+	Urutan    int       `json:"urutan" db:"urutan"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// TPAchievement is a student's mark (0-100) for one TP in a semester
+type TPAchievement struct {
+	ID         string    `json:"id" db:"id"`
+	TenantID   string    `json:"tenant_id" db:"tenant_id"`
+	StudentID  string    `json:"student_id" db:"student_id"`
+	TPID       string    `json:"tp_id" db:"tp_id"`
+	SemesterID string    `json:"semester_id" db:"semester_id"`
+	Score      float64   `json:"score" db:"score"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+
+	// Joined
+	SubjectID   string `json:"subject_id" db:"subject_id"`
+	Kode        string `json:"kode" db:"kode"`
+	TPDeskripsi string `json:"tp_deskripsi" db:"tp_deskripsi"`
+	Urutan      int    `json:"urutan" db:"urutan"`
+}
+
+// TPAchievementInput records the marks of one or more students for a subject
+type TPAchievementInput struct {
+	TenantID   string   `json:"tenant_id"`
+	SubjectID  string   `json:"subject_id"`
+	SemesterID string   `json:"semester_id"`
+	Marks      []TPMark `json:"marks"`
+}
+
+// TPMark is one student's mark for one TP
+type TPMark struct {
+	StudentID string  `json:"student_id"`
+	TPID      string  `json:"tp_id"`
+	Score     float64 `json:"score"`
+}
+
+// GradeDescriptionInput edits the capaian text of a grade by hand
+type GradeDescriptionInput struct {
+	TenantID        string `json:"tenant_id"`
+	StudentID       string `json:"student_id"`
+	SubjectID       string `json:"subject_id"`
+	SemesterID      string `json:"semester_id"`
+	DescriptionHigh string `json:"description_high"`
+	DescriptionLow  string `json:"description_low"`
+}
+
 // RaporData represents complete rapor data for a student
 type RaporData struct {
 	StudentID       string                `json:"student_id"`
@@ -103,6 +311,8 @@ type RaporData struct {
 	Attendance      AttendanceData        `json:"attendance"`
 	Extracurricular []ExtracurricularData `json:"extracurricular"`
 	TeacherNotes    string                `json:"teacher_notes"`
+	// Sikap holds the K13 spiritual and sosial predicates; empty for other curricula
+	Sikap []SikapSiswa `json:"sikap,omitempty"`
 }
 
 // AttendanceData for rapor
@@ -127,6 +337,55 @@ const (
 	SubjectTypePesantrenTahfidz = "PESANTREN_TAHFIDZ"
 )
 
+// Tahfidz grading components, computed from the semester's setoran
+const (
+	TahfidzKomponenJuz      = "Pencapaian Juz"
+	TahfidzKomponenKualitas = "Kualitas"
+	TahfidzKomponenMurajaah = "Murajaah"
+)
+
+// PredicateSet is a tenant-wide predicate scale for one subject type. It applies to the
+// subjects of that type that have no predicate rules of their own.
+type PredicateSet struct {
+	TenantID    string          `json:"tenant_id"`
+	SubjectType string          `json:"subject_type"`
+	Rules       []PredicateRule `json:"rules"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// DefaultPesantrenPredicateRules is the Arabic scale used for kitab and tahfidz
+func DefaultPesantrenPredicateRules() []PredicateRule {
+	return []PredicateRule{
+		{MinScore: 90, MaxScore: 100, Predicate: "Mumtaz", Label: "Istimewa"},
+		{MinScore: 80, MaxScore: 89, Predicate: "Jayyid Jiddan", Label: "Sangat Baik"},
+		{MinScore: 70, MaxScore: 79, Predicate: "Jayyid", Label: "Baik"},
+		{MinScore: 0, MaxScore: 69, Predicate: "Maqbul", Label: "Cukup"},
+	}
+}
+
+// Default grading config for kitab (diniyah) subjects; predicates follow the tenant set
+func DefaultKitabGradingConfig() GradingConfig {
+	return GradingConfig{
+		Components: []GradingComponent{
+			{Name: "Qiro'ah", Weight: 40},
+			{Name: "Tarjamah", Weight: 30},
+			{Name: "Hafalan Matan", Weight: 30},
+		},
+	}
+}
+
+// Default grading config for tahfidz; the components are computed from the setoran
+func DefaultTahfidzGradingConfig() GradingConfig {
+	return GradingConfig{
+		Components: []GradingComponent{
+			{Name: TahfidzKomponenJuz, Weight: 50},
+			{Name: TahfidzKomponenKualitas, Weight: 30},
+			{Name: TahfidzKomponenMurajaah, Weight: 20},
+		},
+		Tahfidz: &TahfidzTarget{TargetJuz: 1, MurajaahPerPekan: 1},
+	}
+}
+
 // Default grading config for Kurikulum Merdeka
 func DefaultMerdekaGradingConfig() GradingConfig {
 	return GradingConfig{
@@ -150,6 +409,7 @@ func DefaultK13GradingConfig() GradingConfig {
 	return GradingConfig{
 		UseKKM:         true,
 		KKMValue:       75,
+		CapRemedial:    true,
 		UseDescriptive: false,
 		Components: []GradingComponent{
 			{Name: "Pengetahuan", Weight: 50},
@@ -40,9 +40,147 @@ type Perizinan struct {
 	Alasan        string    `json:"alasan"`
 	Dari          time.Time `json:"dari"`
 	Sampai        time.Time `json:"sampai"`
-	Status        string    `json:"status"` // Pending, Disetujui, Ditolak
+	Status        string    `json:"status"` // Pending, Disetujui, Ditolak, Keluar, Kembali
 	PenyetujuID   *string   `json:"penyetuju_id"`
 	PenyetujuNama string    `json:"penyetuju_nama" goqu:"skipinsert"` // Joined
 	CreatedAt     time.Time `json:"created_at" goqu:"skipinsert"`
 	UpdatedAt     time.Time `json:"updated_at" goqu:"skipinsert"`
+
+	CatatanPenolakan    string     `json:"catatan_penolakan,omitempty" goqu:"skipinsert"`
+	DisetujuiAt         *time.Time `json:"disetujui_at,omitempty" goqu:"skipinsert"`
+	KodeGatePass        string     `json:"kode_gate_pass,omitempty" goqu:"skipinsert"` // scanned at the gate
+	KeluarAt            *time.Time `json:"keluar_at,omitempty" goqu:"skipinsert"`
+	KembaliAt           *time.Time `json:"kembali_at,omitempty" goqu:"skipinsert"`
+	TerlambatDikabariAt *time.Time `json:"terlambat_dikabari_at,omitempty" goqu:"skipinsert"`
+	PelanggaranID       *string    `json:"pelanggaran_id,omitempty" goqu:"skipinsert"` // recorded for a late return
+	Terlambat           bool       `json:"terlambat" goqu:"skipinsert"`                // out past Sampai, or returned after it
+
+	// Joins
+	KelasNama string `json:"kelas_nama" goqu:"skipinsert"`
+	NamaWali  string `json:"-" goqu:"skipinsert"`
+	NoHPWali  string `json:"-" goqu:"skipinsert"`
+}
+
+// Perizinan statuses, in workflow order
+const (
+	PerizinanPending   = "Pending"
+	PerizinanDisetujui = "Disetujui"
+	PerizinanDitolak   = "Ditolak"
+	PerizinanKeluar    = "Keluar"  // checked out at the gate
+	PerizinanKembali   = "Kembali" // checked back in
+)
+
+// GatePass is what the santri shows at the gate; Kode is encoded into the QR code the client
+// renders, and scanning it checks the santri out and later back in
+type GatePass struct {
+	PerizinanID   string     `json:"perizinan_id"`
+	Kode          string     `json:"kode"`
+	SantriID      string     `json:"santri_id"`
+	SantriNama    string     `json:"santri_nama"`
+	KelasNama     string     `json:"kelas_nama"`
+	Tipe          string     `json:"tipe"`
+	Dari          time.Time  `json:"dari"`
+	Sampai        time.Time  `json:"sampai"`
+	Status        string     `json:"status"`
+	PenyetujuNama string     `json:"penyetuju_nama"`
+	KeluarAt      *time.Time `json:"keluar_at,omitempty"`
+}
+
+// Sanction kinds of the built-in threshold ladder
+const (
+	SanksiTeguran        = "Teguran"
+	SanksiSP1            = "SP1"
+	SanksiSP2            = "SP2"
+	SanksiPanggilanWali  = "Panggilan Wali"
+	SanksiStatusPending  = "Pending"
+	SanksiStatusDiproses = "Diproses"
+	SanksiStatusSelesai  = "Selesai"
+)
+
+// SanksiAmbang raises a sanction of kind Jenis once a santri's semester points reach Poin
+type SanksiAmbang struct {
+	ID         string    `json:"id"`
+	TenantID   string    `json:"tenant_id"`
+	Poin       int       `json:"poin"`
+	Jenis      string    `json:"jenis"`
+	Keterangan string    `json:"keterangan"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// DefaultSanksiAmbang is the ladder used by tenants that have not set their own
+func DefaultSanksiAmbang() []SanksiAmbang {
+	return []SanksiAmbang{
+		{Poin: 25, Jenis: SanksiTeguran, Keterangan: "Teguran tertulis dari bagian keamanan"},
+		{Poin: 50, Jenis: SanksiSP1, Keterangan: "Surat peringatan pertama"},
+		{Poin: 75, Jenis: SanksiSP2, Keterangan: "Surat peringatan kedua"},
+		{Poin: 100, Jenis: SanksiPanggilanWali, Keterangan: "Pemanggilan wali santri"},
+	}
+}
+
+// PoinKredit is a recorded good deed that takes points off a santri's semester total
+type PoinKredit struct {
+	ID         string    `json:"id"`
+	TenantID   string    `json:"tenant_id"`
+	SantriID   string    `json:"santri_id"`
+	SantriNama string    `json:"santri_nama"` // Joined
+	Tanggal    time.Time `json:"tanggal"`
+	Poin       int       `json:"poin"`
+	Keterangan string    `json:"keterangan"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Sanksi is raised once per threshold per semester and tracked until it is carried out
+type Sanksi struct {
+	ID             string     `json:"id"`
+	TenantID       string     `json:"tenant_id"`
+	SantriID       string     `json:"santri_id"`
+	SantriNama     string     `json:"santri_nama"` // Joined
+	KelasNama      string     `json:"kelas_nama"`  // Joined
+	SemesterID     string     `json:"semester_id"`
+	AmbangPoin     int        `json:"ambang_poin"`
+	Jenis          string     `json:"jenis"`
+	TotalPoin      int        `json:"total_poin"` // points when the threshold was reached
+	NomorSurat     string     `json:"nomor_surat"`
+	Status         string     `json:"status"` // Pending, Diproses, Selesai
+	Catatan        string     `json:"catatan"`
+	WaliDikabariAt *time.Time `json:"wali_dikabari_at"`
+	SelesaiAt      *time.Time `json:"selesai_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// RekapPoin is a santri's point balance in one semester
+type RekapPoin struct {
+	SantriID         string `json:"santri_id"`
+	SantriNama       string `json:"santri_nama"`
+	KelasNama        string `json:"kelas_nama"`
+	SemesterID       string `json:"semester_id"`
+	PoinPelanggaran  int    `json:"poin_pelanggaran"`
+	PoinKredit       int    `json:"poin_kredit"`
+	TotalPoin        int    `json:"total_poin"` // never below zero
+	SanksiTerakhir   string `json:"sanksi_terakhir"`
+	AmbangBerikutnya *int   `json:"ambang_berikutnya"`
+}
+
+// PoinSantri details a santri's semester points with what makes them up
+type PoinSantri struct {
+	RekapPoin
+	Pelanggaran []PelanggaranSiswa `json:"pelanggaran"`
+	Kredit      []PoinKredit       `json:"kredit"`
+	Sanksi      []Sanksi           `json:"sanksi"`
+}
+
+// SuratPeringatan is the content of a warning letter for one sanction
+type SuratPeringatan struct {
+	NamaLembaga string
+	Alamat      string
+	Sanksi      Sanksi
+	Semester    string // e.g. "Ganjil 2025/2026"
+	Keterangan  string // what the threshold asks of the santri
+	NIS         string
+	NamaWali    string
+	Pelanggaran []PelanggaranSiswa
+	Kredit      []PoinKredit
+	Tanggal     time.Time
 }
@@ -0,0 +1,70 @@
+package model
+
+import "time"
+
+// ==========================================
+// BIMBINGAN KONSELING (BK) MODELS
+// ==========================================
+
+// KonselingKasus is a confidential counseling case for one student.
+// Catatan is stored encrypted; only BK and kepala sekolah may read it.
+type KonselingKasus struct {
+	ID             string     `json:"id" db:"id"`
+	TenantID       string     `json:"tenant_id" db:"tenant_id"`
+	SantriID       string     `json:"santri_id" db:"santri_id"`
+	SantriNama     string     `json:"santri_nama" db:"santri_nama"` // Joined
+	KelasNama      string     `json:"kelas_nama" db:"kelas_nama"`   // Joined
+	Kategori       string     `json:"kategori" db:"kategori"`       // Pribadi, Sosial, Belajar, Karir, Keluarga
+	Judul          string     `json:"judul" db:"judul"`
+	Catatan        string     `json:"catatan" db:"catatan"` // Encrypted at rest
+	Status         string     `json:"status" db:"status"`   // Terbuka, Proses, Selesai
+	RujukOrangTua  bool       `json:"rujuk_orang_tua" db:"rujuk_orang_tua"`
+	TanggalRujukan *time.Time `json:"tanggal_rujukan" db:"tanggal_rujukan"`
+	KonselorID     string     `json:"konselor_id" db:"konselor_id"` // user_id of BK
+	PelanggaranIDs []string   `json:"pelanggaran_ids,omitempty" db:"-"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// KonselingSesi is one counseling session within a case, with its follow-up plan
+type KonselingSesi struct {
+	ID                  string     `json:"id" db:"id"`
+	KasusID             string     `json:"kasus_id" db:"kasus_id"`
+	Tanggal             time.Time  `json:"tanggal" db:"tanggal"`
+	Catatan             string     `json:"catatan" db:"catatan"` // Encrypted at rest
+	TindakLanjut        string     `json:"tindak_lanjut" db:"tindak_lanjut"`
+	TanggalTindakLanjut *time.Time `json:"tanggal_tindak_lanjut" db:"tanggal_tindak_lanjut"`
+	TindakLanjutSelesai bool       `json:"tindak_lanjut_selesai" db:"tindak_lanjut_selesai"`
+	KonselorID          string     `json:"konselor_id" db:"konselor_id"`
+	CreatedAt           time.Time  `json:"created_at" db:"created_at"`
+}
+
+// KonselingRingkasan is the non-confidential view shown to wali kelas
+type KonselingRingkasan struct {
+	KasusID       string     `json:"kasus_id" db:"kasus_id"`
+	SantriID      string     `json:"santri_id" db:"santri_id"`
+	SantriNama    string     `json:"santri_nama" db:"santri_nama"`
+	Kategori      string     `json:"kategori" db:"kategori"`
+	Status        string     `json:"status" db:"status"`
+	RujukOrangTua bool       `json:"rujuk_orang_tua" db:"rujuk_orang_tua"`
+	JumlahSesi    int        `json:"jumlah_sesi" db:"jumlah_sesi"`
+	SesiTerakhir  *time.Time `json:"sesi_terakhir" db:"sesi_terakhir"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+}
+
+// KonselingDetail bundles a case with its sessions and linked violations
+type KonselingDetail struct {
+	Kasus       KonselingKasus     `json:"kasus"`
+	Sesi        []KonselingSesi    `json:"sesi"`
+	Pelanggaran []PelanggaranSiswa `json:"pelanggaran"`
+}
+
+// Counseling case statuses
+const (
+	KonselingStatusTerbuka = "Terbuka"
+	KonselingStatusProses  = "Proses"
+	KonselingStatusSelesai = "Selesai"
+)
+
+// KonselingKategori lists the accepted case categories
+var KonselingKategori = []string{"Pribadi", "Sosial", "Belajar", "Karir", "Keluarga"}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"time"
+)
+
+// Curriculum grading modes
+const (
+	CurriculumModeKKM        = "KKM"        // K13 style: pass mark and predicates scaled from the KKM
+	CurriculumModeDeskriptif = "DESKRIPTIF" // Merdeka style: predicate ranges with descriptions
+)
+
+// CurriculumProfile is a row of curriculum_references. System profiles (K13_REVISI, MERDEKA,
+// PESANTREN_SALAF) have no tenant; tenants add their own. A subject whose type is the code of a
+// profile is graded with the profile's mode, components and predicate rules.
+type CurriculumProfile struct {
+	ID            string        `json:"id"`
+	TenantID      string        `json:"tenant_id,omitempty"` // empty for system profiles
+	Code          string        `json:"code"`
+	Name          string        `json:"name"`
+	Description   string        `json:"description"`
+	Mode          string        `json:"mode"`           // KKM, DESKRIPTIF
+	GradingConfig GradingConfig `json:"grading_config"` // JSONB
+	RaporTemplate string        `json:"rapor_template"` // K13, MERDEKA, PESANTREN
+	IsActive      bool          `json:"is_active"`
+	IsSystem      bool          `json:"is_system"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+// TenantCurriculum selects the curriculum a jenjang of the tenant follows. An empty Jenjang is
+// the tenant-wide default; hybrid tenants add one row per SchoolJenjangs entry.
+type TenantCurriculum struct {
+	TenantID       string    `json:"tenant_id"`
+	Jenjang        string    `json:"jenjang"`
+	CurriculumCode string    `json:"curriculum_code"`
+	UpdatedAt      time.Time `json:"updated_at"`
+
+	// Joins
+	CurriculumName string `json:"curriculum_name"`
+	RaporTemplate  string `json:"rapor_template"`
+}
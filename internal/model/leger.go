@@ -0,0 +1,53 @@
+package model
+
+// Leger tie rules for the class ranking
+const (
+	LegerTieStandar   = "standar"   // equal averages share a rank, the next rank is skipped (1, 1, 3)
+	LegerTiePadat     = "padat"     // equal averages share a rank without gaps (1, 1, 2)
+	LegerTieKehadiran = "kehadiran" // equal averages are split by fewer alpha, then fewer sakit+izin
+)
+
+// LegerOptions controls how totals and ranks are computed. ExcludeGroups lists rapor
+// kategori (Akademik, Diniyah, Tahfidz) still printed but left out of total, average and rank.
+type LegerOptions struct {
+	TieRule       string   `json:"tie_rule"`
+	ExcludeGroups []string `json:"exclude_groups"`
+}
+
+// LegerSubject is one subject column of the leger
+type LegerSubject struct {
+	ID       string  `json:"id"`
+	Code     string  `json:"code"`
+	Name     string  `json:"name"`
+	Kelompok string  `json:"kelompok"` // rapor kategori
+	Excluded bool    `json:"excluded"` // left out of the ranking
+	Average  float64 `json:"average"`
+}
+
+// LegerRow is one student of the leger. Scores is keyed by subject id; subjects the student
+// has no grade for are absent.
+type LegerRow struct {
+	StudentID string             `json:"student_id"`
+	Nama      string             `json:"nama"`
+	NIS       string             `json:"nis"`
+	Scores    map[string]float64 `json:"scores"`
+	Total     float64            `json:"total"`
+	Average   float64            `json:"average"`
+	Rank      int                `json:"rank"` // 0 when the student has no ranked grade
+	Kehadiran AttendanceData     `json:"kehadiran"`
+}
+
+// Leger is the student x subject score ledger of a class for one semester
+type Leger struct {
+	KelasID       string         `json:"kelas_id"`
+	NamaKelas     string         `json:"nama_kelas"`
+	WaliKelas     string         `json:"wali_kelas"`
+	NamaSekolah   string         `json:"nama_sekolah"`
+	SemesterID    string         `json:"semester_id"`
+	Semester      string         `json:"semester"`
+	TieRule       string         `json:"tie_rule"`
+	ExcludeGroups []string       `json:"exclude_groups"`
+	Subjects      []LegerSubject `json:"subjects"`
+	Rows          []LegerRow     `json:"rows"`
+	ClassAverage  float64        `json:"class_average"`
+}
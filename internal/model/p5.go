@@ -0,0 +1,100 @@
+package model
+
+import "time"
+
+// Dimensi Profil Pelajar Pancasila assessed in a P5 project
+var DimensiP5 = []string{
+	"Beriman, Bertakwa kepada Tuhan YME, dan Berakhlak Mulia",
+	"Berkebinekaan Global",
+	"Bergotong Royong",
+	"Mandiri",
+	"Bernalar Kritis",
+	"Kreatif",
+}
+
+// P5 achievement levels, from lowest to highest
+const (
+	P5LevelMB  = "MB"  // Mulai Berkembang
+	P5LevelSB  = "SB"  // Sedang Berkembang
+	P5LevelBSH = "BSH" // Berkembang Sesuai Harapan
+	P5LevelSAB = "SAB" // Sangat Berkembang
+)
+
+var P5Levels = []string{P5LevelMB, P5LevelSB, P5LevelBSH, P5LevelSAB}
+
+var P5LevelLabel = map[string]string{
+	P5LevelMB:  "Mulai Berkembang",
+	P5LevelSB:  "Sedang Berkembang",
+	P5LevelBSH: "Berkembang Sesuai Harapan",
+	P5LevelSAB: "Sangat Berkembang",
+}
+
+// P5Projek is one Projek Penguatan Profil Pelajar Pancasila of a kelas in a semester
+type P5Projek struct {
+	ID            string     `json:"id" db:"id"`
+	TenantID      string     `json:"tenant_id" db:"tenant_id"`
+	SemesterID    string     `json:"semester_id" db:"semester_id"`
+	KelasID       string     `json:"kelas_id" db:"kelas_id"`
+	Tema          string     `json:"tema" db:"tema"`
+	Judul         string     `json:"judul" db:"judul"`
+	Deskripsi     string     `json:"deskripsi" db:"deskripsi"`
+	FasilitatorID string     `json:"fasilitator_id" db:"fasilitator_id"` // sekolah_guru
+	Targets       []P5Target `json:"targets" db:"-"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Joined
+	NamaKelas       string `json:"nama_kelas" db:"nama_kelas"`
+	NamaFasilitator string `json:"nama_fasilitator" db:"nama_fasilitator"`
+}
+
+// P5Target is a sub-elemen of the profil targeted by a project (stored as JSONB on the project)
+type P5Target struct {
+	ID        string `json:"id"`
+	Dimensi   string `json:"dimensi"`
+	Elemen    string `json:"elemen"`
+	SubElemen string `json:"sub_elemen"`
+}
+
+// P5Penilaian holds the levels a student reached on each target of a project
+type P5Penilaian struct {
+	ID        string      `json:"id" db:"id"`
+	TenantID  string      `json:"tenant_id" db:"tenant_id"`
+	ProjekID  string      `json:"projek_id" db:"projek_id"`
+	StudentID string      `json:"student_id" db:"student_id"`
+	Capaian   []P5Capaian `json:"capaian" db:"-"`
+	Catatan   string      `json:"catatan" db:"catatan"`
+	UpdatedAt time.Time   `json:"updated_at" db:"updated_at"`
+
+	// Joined
+	StudentName string `json:"student_name" db:"student_name"`
+}
+
+type P5Capaian struct {
+	TargetID string `json:"target_id"`
+	Level    string `json:"level"` // MB, SB, BSH, SAB
+}
+
+// P5PenilaianInput records the assessment of several students for one project
+type P5PenilaianInput struct {
+	TenantID string        `json:"tenant_id"`
+	ProjekID string        `json:"projek_id"`
+	Siswa    []P5Penilaian `json:"siswa"`
+}
+
+// RaporP5 is a project as frozen into the rapor snapshot
+type RaporP5 struct {
+	Judul       string           `json:"judul"`
+	Tema        string           `json:"tema"`
+	Deskripsi   string           `json:"deskripsi"`
+	Fasilitator string           `json:"fasilitator"`
+	Capaian     []RaporP5Capaian `json:"capaian"`
+	Catatan     string           `json:"catatan"`
+}
+
+type RaporP5Capaian struct {
+	Dimensi   string `json:"dimensi"`
+	Elemen    string `json:"elemen"`
+	SubElemen string `json:"sub_elemen"`
+	Level     string `json:"level"`
+}
@@ -0,0 +1,128 @@
+package model
+
+import "time"
+
+// ==========================================
+// PERPUSTAKAAN MODELS
+// ==========================================
+
+// Buku is a catalog title; physical copies are tracked as BukuEksemplar
+type Buku struct {
+	ID          string    `json:"id" db:"id"`
+	TenantID    string    `json:"tenant_id" db:"tenant_id"`
+	ISBN        string    `json:"isbn" db:"isbn"`
+	Judul       string    `json:"judul" db:"judul"`
+	Penulis     string    `json:"penulis" db:"penulis"`
+	Penerbit    string    `json:"penerbit" db:"penerbit"`
+	TahunTerbit int       `json:"tahun_terbit" db:"tahun_terbit"`
+	Kategori    string    `json:"kategori" db:"kategori"`
+	Rak         string    `json:"rak" db:"rak"`                           // Shelf location
+	Eksemplar   int       `json:"jumlah_eksemplar" db:"jumlah_eksemplar"` // Calculated
+	Tersedia    int       `json:"tersedia" db:"tersedia"`                 // Calculated
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// BukuEksemplar is one physical copy identified by its barcode
+type BukuEksemplar struct {
+	ID        string    `json:"id" db:"id"`
+	TenantID  string    `json:"tenant_id" db:"tenant_id"`
+	BukuID    string    `json:"buku_id" db:"buku_id"`
+	BukuJudul string    `json:"buku_judul" db:"buku_judul"` // Joined
+	Barcode   string    `json:"barcode" db:"barcode"`
+	Kondisi   string    `json:"kondisi" db:"kondisi"` // Baik, Rusak
+	Status    string    `json:"status" db:"status"`   // Tersedia, Dipinjam, Hilang
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// PerpustakaanAnggota is a borrower resolved from sekolah_siswa or employees
+type PerpustakaanAnggota struct {
+	Tipe      string `json:"tipe" db:"tipe"` // siswa, pegawai
+	ID        string `json:"id" db:"id"`
+	Nama      string `json:"nama" db:"nama"`
+	Identitas string `json:"identitas" db:"identitas"` // NIS / NIP
+	Kelas     string `json:"kelas" db:"kelas"`         // Kelas (siswa) or department (pegawai)
+	NoHP      string `json:"no_hp" db:"no_hp"`         // Wali phone for siswa
+}
+
+// Peminjaman is a single loan of one copy
+type Peminjaman struct {
+	ID               string     `json:"id" db:"id"`
+	TenantID         string     `json:"tenant_id" db:"tenant_id"`
+	EksemplarID      string     `json:"eksemplar_id" db:"eksemplar_id"`
+	Barcode          string     `json:"barcode" db:"barcode"`       // Joined
+	BukuJudul        string     `json:"buku_judul" db:"buku_judul"` // Joined
+	AnggotaTipe      string     `json:"anggota_tipe" db:"anggota_tipe"`
+	AnggotaID        string     `json:"anggota_id" db:"anggota_id"`
+	AnggotaNama      string     `json:"anggota_nama" db:"anggota_nama"`   // Snapshot at loan time
+	AnggotaNoHP      string     `json:"anggota_no_hp" db:"anggota_no_hp"` // Snapshot at loan time
+	TanggalPinjam    time.Time  `json:"tanggal_pinjam" db:"tanggal_pinjam"`
+	JatuhTempo       time.Time  `json:"jatuh_tempo" db:"jatuh_tempo"`
+	TanggalKembali   *time.Time `json:"tanggal_kembali" db:"tanggal_kembali"`
+	JumlahPerpanjang int        `json:"jumlah_perpanjang" db:"jumlah_perpanjang"`
+	Denda            int64      `json:"denda" db:"denda"`
+	DendaLunas       bool       `json:"denda_lunas" db:"denda_lunas"`
+	Status           string     `json:"status" db:"status"` // Dipinjam, Kembali, Hilang
+	HariTerlambat    int        `json:"hari_terlambat" db:"-"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// PeminjamanInput for lending a copy by barcode
+type PeminjamanInput struct {
+	TenantID    string `json:"-"`
+	Barcode     string `json:"barcode"`
+	AnggotaTipe string `json:"anggota_tipe"`
+	AnggotaID   string `json:"anggota_id"`
+}
+
+// PerpustakaanConfig holds per-tenant circulation rules
+type PerpustakaanConfig struct {
+	TenantID       string `json:"tenant_id" db:"tenant_id"`
+	LamaPinjamHari int    `json:"lama_pinjam_hari" db:"lama_pinjam_hari"`
+	MaksPerpanjang int    `json:"maks_perpanjang" db:"maks_perpanjang"`
+	MaksPinjam     int    `json:"maks_pinjam" db:"maks_pinjam"` // Active loans per member
+	DendaPerHari   int64  `json:"denda_per_hari" db:"denda_per_hari"`
+}
+
+// BukuPopuler is a row of the popular books report
+type BukuPopuler struct {
+	BukuID       string `json:"buku_id" db:"buku_id"`
+	Judul        string `json:"judul" db:"judul"`
+	Penulis      string `json:"penulis" db:"penulis"`
+	JumlahPinjam int    `json:"jumlah_pinjam" db:"jumlah_pinjam"`
+}
+
+// PeminjamanFilter for listing loans
+type PeminjamanFilter struct {
+	TenantID    string
+	Status      string
+	AnggotaTipe string
+	AnggotaID   string
+}
+
+// Perpustakaan statuses
+const (
+	EksemplarTersedia = "Tersedia"
+	EksemplarDipinjam = "Dipinjam"
+	EksemplarHilang   = "Hilang"
+
+	PeminjamanDipinjam = "Dipinjam"
+	PeminjamanKembali  = "Kembali"
+	PeminjamanHilang   = "Hilang"
+
+	AnggotaTipeSiswa   = "siswa"
+	AnggotaTipePegawai = "pegawai"
+)
+
+// DefaultPerpustakaanConfig is used until a tenant saves its own rules
+func DefaultPerpustakaanConfig(tenantID string) PerpustakaanConfig {
+	return PerpustakaanConfig{
+		TenantID:       tenantID,
+		LamaPinjamHari: 7,
+		MaksPerpanjang: 2,
+		MaksPinjam:     3,
+		DendaPerHari:   500,
+	}
+}
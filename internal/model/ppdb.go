@@ -0,0 +1,121 @@
+package model
+
+import "time"
+
+// ==========================================
+// PPDB (PENERIMAAN PESERTA DIDIK BARU) MODELS
+// ==========================================
+
+// Selection status flow: Verifikasi -> Tes -> Diterima / Ditolak
+const (
+	PPDBStatusVerifikasi = "Verifikasi"
+	PPDBStatusTes        = "Tes"
+	PPDBStatusDiterima   = "Diterima"
+	PPDBStatusDitolak    = "Ditolak"
+)
+
+// Registration fee status
+const (
+	PPDBBayarBelum  = "Belum"
+	PPDBBayarLunas  = "Lunas"
+	PPDBBayarGratis = "Gratis"
+)
+
+// PPDBDokumenJenis lists accepted applicant document types
+var PPDBDokumenJenis = []string{"Akta Kelahiran", "Kartu Keluarga", "Ijazah", "Rapor", "Pas Foto", "Lainnya"}
+
+// PPDBGelombang is an admission wave with its own period, quota and fee
+type PPDBGelombang struct {
+	ID               string    `json:"id" db:"id"`
+	TenantID         string    `json:"tenant_id" db:"tenant_id"`
+	TahunAjaran      string    `json:"tahun_ajaran" db:"tahun_ajaran"` // e.g. 2026/2027
+	Nama             string    `json:"nama" db:"nama"`                 // e.g. Gelombang 1
+	TanggalBuka      time.Time `json:"tanggal_buka" db:"tanggal_buka"`
+	TanggalTutup     time.Time `json:"tanggal_tutup" db:"tanggal_tutup"`
+	Kuota            int       `json:"kuota" db:"kuota"` // Max accepted applicants, 0 = unlimited
+	BiayaPendaftaran int64     `json:"biaya_pendaftaran" db:"biaya_pendaftaran"`
+	IsActive         bool      `json:"is_active" db:"is_active"`
+	JumlahPendaftar  int       `json:"jumlah_pendaftar" db:"jumlah_pendaftar"` // Calculated
+	JumlahDiterima   int       `json:"jumlah_diterima" db:"jumlah_diterima"`   // Calculated
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// IsOpen reports whether the wave accepts registrations at the given time
+func (g *PPDBGelombang) IsOpen(now time.Time) bool {
+	return g.IsActive && !now.Before(g.TanggalBuka) && now.Before(g.TanggalTutup.AddDate(0, 0, 1))
+}
+
+// PPDBPendaftar is one applicant record
+type PPDBPendaftar struct {
+	ID             string        `json:"id" db:"id"`
+	TenantID       string        `json:"tenant_id" db:"tenant_id"`
+	GelombangID    string        `json:"gelombang_id" db:"gelombang_id"`
+	GelombangNama  string        `json:"gelombang_nama" db:"gelombang_nama"` // Joined
+	NoPendaftaran  string        `json:"no_pendaftaran" db:"no_pendaftaran"`
+	Nama           string        `json:"nama" db:"nama"`
+	NISN           string        `json:"nisn" db:"nisn"`
+	JenisKelamin   string        `json:"jenis_kelamin" db:"jenis_kelamin"` // L, P
+	TempatLahir    string        `json:"tempat_lahir" db:"tempat_lahir"`
+	TanggalLahir   *time.Time    `json:"tanggal_lahir,omitempty" db:"tanggal_lahir"`
+	Alamat         string        `json:"alamat" db:"alamat"`
+	AsalSekolah    string        `json:"asal_sekolah" db:"asal_sekolah"`
+	NamaWali       string        `json:"nama_wali" db:"nama_wali"`
+	NoHPWali       string        `json:"no_hp_wali" db:"no_hp_wali"`
+	EmailWali      string        `json:"email_wali" db:"email_wali"`
+	Status         string        `json:"status" db:"status"`
+	NilaiTes       *float64      `json:"nilai_tes,omitempty" db:"nilai_tes"`
+	Catatan        string        `json:"catatan" db:"catatan"`
+	StatusBayar    string        `json:"status_bayar" db:"status_bayar"`
+	PaymentOrderID string        `json:"payment_order_id,omitempty" db:"payment_order_id"`
+	SiswaID        string        `json:"siswa_id,omitempty" db:"siswa_id"` // Set after conversion
+	Dokumen        []PPDBDokumen `json:"dokumen,omitempty" db:"-"`
+	CreatedAt      time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+// PPDBDokumen is an uploaded applicant document (stored as URL)
+type PPDBDokumen struct {
+	ID          string    `json:"id" db:"id"`
+	PendaftarID string    `json:"pendaftar_id" db:"pendaftar_id"`
+	Jenis       string    `json:"jenis" db:"jenis"`
+	URL         string    `json:"url" db:"url"`
+	Verified    bool      `json:"verified" db:"verified"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// PPDBPendaftarFilter filters the applicant list
+type PPDBPendaftarFilter struct {
+	TenantID    string
+	GelombangID string
+	Status      string
+	Search      string
+}
+
+// PPDBStatusInput changes the selection status of an applicant
+type PPDBStatusInput struct {
+	Status   string   `json:"status"`
+	NilaiTes *float64 `json:"nilai_tes"`
+	Catatan  string   `json:"catatan"`
+}
+
+// PPDBKonversiInput converts an accepted applicant into Siswa
+type PPDBKonversiInput struct {
+	KelasID string `json:"kelas_id"`
+	NIS     string `json:"nis"`
+}
+
+// PPDBRegistrasiResult is returned to the public registration form
+type PPDBRegistrasiResult struct {
+	Pendaftar   *PPDBPendaftar           `json:"pendaftar"`
+	Pembayaran  *SnapTransactionResponse `json:"pembayaran,omitempty"`
+	BiayaDaftar int64                    `json:"biaya_pendaftaran"`
+}
+
+// PPDBInfo is the public, tenant-branded landing data for the registration page
+type PPDBInfo struct {
+	NamaLembaga string          `json:"nama_lembaga"`
+	Alamat      string          `json:"alamat"`
+	Subdomain   string          `json:"subdomain"`
+	Gelombang   []PPDBGelombang `json:"gelombang"`
+}
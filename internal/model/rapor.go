@@ -29,6 +29,11 @@ type Rapor struct {
 	NamaSantri  string       `json:"nama_santri" goqu:"skip" db:"nama_santri"`
 	NamaPeriode string       `json:"nama_periode" goqu:"skip" db:"nama_periode"`
 	NilaiList   []RaporNilai `json:"nilai_list" goqu:"skip" db:"skip"`
+
+	// Header is the identity snapshot frozen at generation time (JSONB)
+	Header *RaporHeader `json:"header,omitempty" goqu:"skip" db:"-"`
+	// P5 holds the Merdeka project assessment frozen at generation time (JSONB)
+	P5 []RaporP5 `json:"p5,omitempty" goqu:"skip" db:"-"`
 }
 
 type RaporNilai struct {
@@ -38,6 +43,90 @@ type RaporNilai struct {
 	Jenis      string    `json:"jenis" goqu:"jenis" db:"jenis"`
 	Nilai      string    `json:"nilai" goqu:"nilai" db:"nilai"`
 	Keterangan string    `json:"keterangan" goqu:"keterangan" db:"keterangan"`
+	Urutan     int       `json:"urutan" goqu:"urutan" db:"urutan"`
 	CreatedAt  time.Time `json:"created_at" goqu:"created_at" db:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at" goqu:"updated_at" db:"updated_at"`
+
+	// Detail holds the curriculum specific scores behind Nilai (JSONB)
+	Detail *RaporNilaiDetail `json:"detail,omitempty" goqu:"skip" db:"-"`
+}
+
+// Kurikulum codes as stored in sekolah_profil.curriculum
+const (
+	KurikulumK13       = "K13"
+	KurikulumMerdeka   = "MERDEKA"
+	KurikulumPesantren = "PESANTREN"
+)
+
+// Rapor lifecycle: Draft -> Submitted (wali kelas) -> Approved (kepala sekolah/pengasuh) -> Published.
+// Grades of the student/semester are locked from Submitted on; unlocking returns the rapor to Draft.
+const (
+	RaporStatusDraft     = "Draft"
+	RaporStatusSubmitted = "Submitted"
+	RaporStatusApproved  = "Approved"
+	RaporStatusPublished = "Published"
+)
+
+// Rapor nilai categories
+const (
+	RaporKategoriSikap           = "Sikap"
+	RaporKategoriAkademik        = "Akademik"
+	RaporKategoriDiniyah         = "Diniyah"
+	RaporKategoriTahfidz         = "Tahfidz"
+	RaporKategoriEkstrakurikuler = "Ekstrakurikuler"
+)
+
+// RaporHeader is the identity, attendance and signatory data frozen into a rapor
+// so reprints match the original even after the profil, kelas or wali kelas change
+type RaporHeader struct {
+	Kurikulum     string         `json:"kurikulum"` // K13, MERDEKA, PESANTREN
+	NamaSekolah   string         `json:"nama_sekolah"`
+	AlamatSekolah string         `json:"alamat_sekolah"`
+	LogoURL       string         `json:"logo_url"`
+	LogoHash      string         `json:"logo_hash,omitempty"` // SHA-256 of the logo stored at generation
+	NamaSiswa     string         `json:"nama_siswa"`
+	NIS           string         `json:"nis"`
+	NISN          string         `json:"nisn"`
+	Kelas         string         `json:"kelas"`
+	Tingkat       string         `json:"tingkat,omitempty"`
+	Jenjang       string         `json:"jenjang,omitempty"` // resolved from the tingkat for hybrid tenants
+	Semester      string         `json:"semester"`
+	WaliKelas     string         `json:"wali_kelas"`
+	NIPWaliKelas  string         `json:"nip_wali_kelas"`
+	KepalaSekolah string         `json:"kepala_sekolah"`
+	TanggalRapor  time.Time      `json:"tanggal_rapor"`
+	Kehadiran     AttendanceData `json:"kehadiran"`
+}
+
+// RaporNilaiDetail keeps the numeric side of a snapshotted grade.
+// K13 fills both pengetahuan (Nilai*) and keterampilan, Merdeka and pesantren only Nilai*.
+type RaporNilaiDetail struct {
+	KKM                   int     `json:"kkm,omitempty"`
+	NilaiAngka            float64 `json:"nilai_angka"`
+	Predikat              string  `json:"predikat"`
+	Deskripsi             string  `json:"deskripsi,omitempty"`
+	NilaiKeterampilan     float64 `json:"nilai_keterampilan,omitempty"`
+	PredikatKeterampilan  string  `json:"predikat_keterampilan,omitempty"`
+	DeskripsiKeterampilan string  `json:"deskripsi_keterampilan,omitempty"`
+}
+
+// RaporStatusLog records one lifecycle transition; Alasan is mandatory when unlocking
+type RaporStatusLog struct {
+	ID         string    `json:"id" db:"id"`
+	RaporID    string    `json:"rapor_id" db:"rapor_id"`
+	DariStatus string    `json:"dari_status" db:"dari_status"`
+	KeStatus   string    `json:"ke_status" db:"ke_status"`
+	UserID     string    `json:"user_id" db:"user_id"`
+	NamaUser   string    `json:"nama_user" db:"nama_user"`
+	Alasan     string    `json:"alasan" db:"alasan"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// RaporWali is a published rapor as listed in the parent portal
+type RaporWali struct {
+	ID          string     `json:"id" db:"id"`
+	SiswaID     string     `json:"siswa_id" db:"siswa_id"`
+	NamaSiswa   string     `json:"nama_siswa" db:"nama_siswa"`
+	Semester    string     `json:"semester" db:"semester"`
+	PublishedAt *time.Time `json:"published_at" db:"published_at"`
 }
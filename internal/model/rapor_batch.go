@@ -0,0 +1,72 @@
+package model
+
+import "time"
+
+const (
+	GenerateRaporBatchMessage = "rapor.batch.generate"
+
+	RaporBatchStatusQueued     = "queued"
+	RaporBatchStatusProcessing = "processing"
+	RaporBatchStatusDone       = "done"
+	RaporBatchStatusFailed     = "failed"
+
+	RaporBatchFileZip = "zip"
+	RaporBatchFilePDF = "pdf"
+)
+
+// RaporBatch is a class-level rapor generation job processed by the message worker.
+// The resulting ZIP and merged PDF are kept in the row and fetched separately.
+type RaporBatch struct {
+	ID         string            `json:"id" db:"id"`
+	TenantID   string            `json:"tenant_id" db:"tenant_id"`
+	KelasID    string            `json:"kelas_id" db:"kelas_id"`
+	KelasNama  string            `json:"kelas_nama" db:"kelas_nama"`
+	SemesterID string            `json:"semester_id" db:"semester_id"`
+	Status     string            `json:"status" db:"status"`
+	Total      int               `json:"total" db:"total"`
+	Selesai    int               `json:"selesai" db:"selesai"`
+	Gagal      int               `json:"gagal" db:"gagal"`
+	Progress   int               `json:"progress" db:"-"` // percent, see HitungProgress
+	Siswa      []RaporBatchSiswa `json:"siswa,omitempty" db:"-"`
+	Errors     []RaporBatchError `json:"errors" db:"-"`
+	StartedAt  *time.Time        `json:"started_at" db:"started_at"`
+	FinishedAt *time.Time        `json:"finished_at" db:"finished_at"`
+	CreatedAt  time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// HitungProgress returns the share of processed students in percent
+func (b RaporBatch) HitungProgress() int {
+	if b.Total == 0 {
+		return 0
+	}
+	return (b.Selesai + b.Gagal) * 100 / b.Total
+}
+
+// RaporBatchSiswa carries the per-student catatan and kehadiran for a batch
+type RaporBatchSiswa struct {
+	SiswaID     string         `json:"siswa_id"`
+	Nama        string         `json:"nama,omitempty"`
+	CatatanWali string         `json:"catatan_wali"`
+	Kehadiran   AttendanceData `json:"kehadiran"`
+}
+
+// RaporBatchError records why one student's rapor could not be produced
+type RaporBatchError struct {
+	SiswaID string `json:"siswa_id"`
+	Nama    string `json:"nama"`
+	Pesan   string `json:"pesan"`
+}
+
+// RaporBatchInput is the request body for starting a batch
+type RaporBatchInput struct {
+	KelasID    string            `json:"kelas_id"`
+	SemesterID string            `json:"semester_id"`
+	Siswa      []RaporBatchSiswa `json:"siswa"`
+}
+
+// RaporBatchMessage is published to the worker to process a queued batch
+type RaporBatchMessage struct {
+	TenantID string `json:"tenant_id"`
+	BatchID  string `json:"batch_id"`
+}
@@ -14,13 +14,14 @@ const (
 	RoleWaliSiswa     = "wali_siswa"
 )
 
-// Roles for Pesantren (6 roles)
+// Roles for Pesantren (7 roles)
 const (
 	RoleAdminPesantren = "admin_pesantren"
 	RolePengasuh       = "pengasuh"
 	RoleSekretaris     = "sekretaris"
 	RoleBendaharaPes   = "bendahara_pesantren"
 	RolePendidikan     = "pendidikan"
+	RoleKeamanan       = "keamanan"
 	RoleWaliSantri     = "wali_santri"
 )
 
@@ -53,6 +54,7 @@ var PesantrenRoles = []RoleInfo{
 	{ID: RoleSekretaris, Name: "Sekretaris", Description: "Akses data santri, surat, dan arsip", PlanType: "pesantren"},
 	{ID: RoleBendaharaPes, Name: "Bendahara", Description: "Akses keuangan, syahriah, dan laporan", PlanType: "pesantren"},
 	{ID: RolePendidikan, Name: "Bagian Pendidikan", Description: "Akses tahfidz, diniyah, dan nilai santri", PlanType: "pesantren"},
+	{ID: RoleKeamanan, Name: "Keamanan", Description: "Akses perizinan, gate pass, dan pelanggaran santri", PlanType: "pesantren"},
 	{ID: RoleWaliSantri, Name: "Wali Santri", Description: "Akses nilai anak, syahriah, dan pengumuman", PlanType: "pesantren"},
 }
 
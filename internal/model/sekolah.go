@@ -1,27 +1,49 @@
 package model
 
+import "time"
+
 // Sekolah Domain Models
 
 type Siswa struct {
-	ID        string `json:"id"`
-	TenantID  string `json:"tenant_id"`
-	NIS       string `json:"nis"`
-	Nama      string `json:"nama"`
-	KelasID   string `json:"kelas_id"`
-	KelasNama string `json:"kelas_nama"` // Populated from join
-	Alamat    string `json:"alamat"`
-	NamaWali  string `json:"nama_wali"`
-	NoHPWali  string `json:"no_hp_wali"`
-	Status    string `json:"status"` // Aktif, Lulus, Pindah
+	ID        string `json:"id" db:"id"`
+	TenantID  string `json:"tenant_id" db:"tenant_id"`
+	NIS       string `json:"nis" db:"nis"` // NIPD in Dapodik
+	Nama      string `json:"nama" db:"nama"`
+	KelasID   string `json:"kelas_id" db:"kelas_id"`
+	KelasNama string `json:"kelas_nama" db:"kelas_nama"` // Populated from join
+	Alamat    string `json:"alamat" db:"alamat"`
+	NamaWali  string `json:"nama_wali" db:"nama_wali"`
+	NoHPWali  string `json:"no_hp_wali" db:"no_hp_wali"`
+	Status    string `json:"status" db:"status"` // Aktif, Lulus, Pindah
+
+	// Dapodik core fields
+	NISN          string     `json:"nisn" db:"nisn"`
+	NIK           string     `json:"nik" db:"nik"`
+	JenisKelamin  string     `json:"jenis_kelamin" db:"jenis_kelamin"` // L, P
+	TempatLahir   string     `json:"tempat_lahir" db:"tempat_lahir"`
+	TanggalLahir  *time.Time `json:"tanggal_lahir,omitempty" db:"tanggal_lahir"`
+	Agama         string     `json:"agama" db:"agama"`
+	NamaAyah      string     `json:"nama_ayah" db:"nama_ayah"`
+	PekerjaanAyah string     `json:"pekerjaan_ayah" db:"pekerjaan_ayah"`
+	NamaIbu       string     `json:"nama_ibu" db:"nama_ibu"`
+	PekerjaanIbu  string     `json:"pekerjaan_ibu" db:"pekerjaan_ibu"`
 }
 
 type Guru struct {
-	ID       string `json:"id"`
-	TenantID string `json:"tenant_id"`
-	NIP      string `json:"nip"`
-	Nama     string `json:"nama"`
-	Jenis    string `json:"jenis"`  // Guru Mapel, Guru Kelas
-	Status   string `json:"status"` // PNS, Honorer
+	ID       string `json:"id" db:"id"`
+	TenantID string `json:"tenant_id" db:"tenant_id"`
+	NIP      string `json:"nip" db:"nip"`
+	Nama     string `json:"nama" db:"nama"`
+	Jenis    string `json:"jenis" db:"jenis"`   // Guru Mapel, Guru Kelas (Jenis PTK)
+	Status   string `json:"status" db:"status"` // PNS, Honorer (Status Kepegawaian)
+	NoHP     string `json:"no_hp" db:"no_hp"`   // For tahfidz and halaqah alerts
+
+	// Dapodik core fields
+	NUPTK        string     `json:"nuptk" db:"nuptk"`
+	NIK          string     `json:"nik" db:"nik"`
+	JenisKelamin string     `json:"jenis_kelamin" db:"jenis_kelamin"` // L, P
+	TempatLahir  string     `json:"tempat_lahir" db:"tempat_lahir"`
+	TanggalLahir *time.Time `json:"tanggal_lahir,omitempty" db:"tanggal_lahir"`
 }
 
 type Mapel struct {
@@ -33,10 +55,15 @@ type Mapel struct {
 }
 
 type Kelas struct {
-	ID       string `json:"id"`
-	TenantID string `json:"tenant_id"`
-	Nama     string `json:"nama"`
-	Tingkat  string `json:"tingkat"` // Level like "10", "11", "12" or "Ula", "Wustha"
-	Urutan   int    `json:"urutan"`
-	Status   string `json:"status"`
+	ID       string `json:"id" db:"id"`
+	TenantID string `json:"tenant_id" db:"tenant_id"`
+	Nama     string `json:"nama" db:"nama"`
+	Tingkat  string `json:"tingkat" db:"tingkat"` // Level like "10", "11", "12" or "Ula", "Wustha"
+	Urutan   int    `json:"urutan" db:"urutan"`
+	Status   string `json:"status" db:"status"`
+
+	// Dapodik rombel fields
+	WaliKelasID   string `json:"wali_kelas_id" db:"wali_kelas_id"`
+	WaliKelasNama string `json:"wali_kelas_nama" db:"wali_kelas_nama"` // Populated from join
+	Kurikulum     string `json:"kurikulum" db:"kurikulum"`
 }
@@ -0,0 +1,90 @@
+package model
+
+import "time"
+
+// K13 attitude aspects
+const (
+	SikapSpiritual = "spiritual"
+	SikapSosial    = "sosial"
+)
+
+// Observation kinds of a journal entry
+const (
+	JurnalSikapPositif = "positif"
+	JurnalSikapNegatif = "negatif"
+)
+
+// K13 sikap predicates, from highest to lowest
+const (
+	SikapPredikatSB = "SB" // Sangat Baik
+	SikapPredikatB  = "B"  // Baik
+	SikapPredikatC  = "C"  // Cukup
+	SikapPredikatK  = "K"  // Kurang
+)
+
+var SikapPredikatLabel = map[string]string{
+	SikapPredikatSB: "Sangat Baik",
+	SikapPredikatB:  "Baik",
+	SikapPredikatC:  "Cukup",
+	SikapPredikatK:  "Kurang",
+}
+
+// JurnalSikap is one attitude observation written by a guru, wali kelas or BK
+type JurnalSikap struct {
+	ID           string    `json:"id" db:"id"`
+	TenantID     string    `json:"tenant_id" db:"tenant_id"`
+	StudentID    string    `json:"student_id" db:"student_id"`
+	SemesterID   string    `json:"semester_id" db:"semester_id"`
+	Aspek        string    `json:"aspek" db:"aspek"` // spiritual, sosial
+	Tanggal      time.Time `json:"tanggal" db:"tanggal"`
+	Butir        string    `json:"butir" db:"butir"` // e.g. "jujur", "disiplin", "ketaatan beribadah"
+	Jenis        string    `json:"jenis" db:"jenis"` // positif, negatif
+	Catatan      string    `json:"catatan" db:"catatan"`
+	ObserverID   string    `json:"observer_id" db:"observer_id"`
+	ObserverRole string    `json:"observer_role" db:"observer_role"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+
+	// Joined
+	StudentName  string `json:"student_name" db:"student_name"`
+	NamaObserver string `json:"nama_observer" db:"nama_observer"`
+}
+
+// JurnalSikapInput records an observation; Tanggal is YYYY-MM-DD and defaults to today
+type JurnalSikapInput struct {
+	TenantID     string `json:"-"`
+	StudentID    string `json:"student_id"`
+	SemesterID   string `json:"semester_id"`
+	Aspek        string `json:"aspek"`
+	Tanggal      string `json:"tanggal"`
+	Butir        string `json:"butir"`
+	Jenis        string `json:"jenis"`
+	Catatan      string `json:"catatan"`
+	ObserverID   string `json:"-"`
+	ObserverRole string `json:"-"`
+}
+
+// SikapSiswa is the semester predicate and description of one aspect. Edited marks a
+// description reworded by the wali kelas, which a new rekap keeps unless asked to overwrite.
+type SikapSiswa struct {
+	ID         string    `json:"id" db:"id"`
+	TenantID   string    `json:"tenant_id" db:"tenant_id"`
+	StudentID  string    `json:"student_id" db:"student_id"`
+	SemesterID string    `json:"semester_id" db:"semester_id"`
+	Aspek      string    `json:"aspek" db:"aspek"`
+	Predikat   string    `json:"predikat" db:"predikat"`
+	Deskripsi  string    `json:"deskripsi" db:"deskripsi"`
+	Edited     bool      `json:"edited" db:"edited"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+
+	// Joined
+	StudentName string `json:"student_name" db:"student_name"`
+}
+
+// SikapRekapInput compiles the journal of a kelas (or one student) into sikap predicates
+type SikapRekapInput struct {
+	TenantID   string `json:"-"`
+	SemesterID string `json:"semester_id"`
+	KelasID    string `json:"kelas_id"`
+	StudentID  string `json:"student_id"`
+	Overwrite  bool   `json:"overwrite"`
+}
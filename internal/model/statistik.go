@@ -0,0 +1,77 @@
+package model
+
+// Groupings of the grade statistics
+const (
+	GradeStatsBySubject  = "subject"
+	GradeStatsByKelas    = "kelas"
+	GradeStatsBySemester = "semester"
+)
+
+// GradeStatsFilter narrows the grades the statistics are computed over. Empty fields do not
+// filter; kelas and tingkat follow the student's current kelas.
+type GradeStatsFilter struct {
+	SemesterIDs []string `json:"semester_ids"`
+	SubjectID   string   `json:"subject_id"`
+	KelasID     string   `json:"kelas_id"`
+	Tingkat     string   `json:"tingkat"`
+}
+
+// GradeStats summarises the final scores of one group (a subject, kelas or semester, or all
+// grades when Key is empty). BelowKKMPct is the share of the grades measured against a KKM
+// (subjects using a KKM) that fall below it.
+type GradeStats struct {
+	Key         string         `json:"key,omitempty"`
+	Label       string         `json:"label,omitempty"`
+	Tingkat     string         `json:"tingkat,omitempty"`
+	KKM         int            `json:"kkm,omitempty"` // per-subject grouping only
+	Count       int            `json:"count"`
+	Mean        float64        `json:"mean"`
+	Median      float64        `json:"median"`
+	StdDev      float64        `json:"std_dev"`
+	Min         float64        `json:"min"`
+	Max         float64        `json:"max"`
+	WithKKM     int            `json:"with_kkm"`
+	BelowKKM    int            `json:"below_kkm"`
+	BelowKKMPct float64        `json:"below_kkm_pct"`
+	Predicates  map[string]int `json:"predicates"`
+}
+
+// GradeStatsReport is the statistics overview of a semester
+type GradeStatsReport struct {
+	SemesterID string           `json:"semester_id"`
+	Semester   string           `json:"semester"`
+	Filter     GradeStatsFilter `json:"filter"`
+	Overall    GradeStats       `json:"overall"`
+	Subjects   []GradeStats     `json:"subjects"`
+	Kelas      []GradeStats     `json:"kelas"`
+}
+
+// HistogramBin counts the scores in [From, To); the last bin includes 100
+type HistogramBin struct {
+	From  int `json:"from"`
+	To    int `json:"to"`
+	Count int `json:"count"`
+}
+
+// ComponentStats summarises one grading component (Sumatif, Praktik, ...) of a subject, from the
+// component scores stored with the final grades
+type ComponentStats struct {
+	Name   string  `json:"name"`
+	Weight int     `json:"weight"`
+	Count  int     `json:"count"`
+	Mean   float64 `json:"mean"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+}
+
+// GradeHistogram is the score distribution of a subject for the teacher, with its components
+type GradeHistogram struct {
+	SubjectID   string           `json:"subject_id"`
+	SubjectName string           `json:"subject_name"`
+	SemesterID  string           `json:"semester_id"`
+	KelasID     string           `json:"kelas_id,omitempty"`
+	BinWidth    int              `json:"bin_width"`
+	Stats       GradeStats       `json:"stats"`
+	Bins        []HistogramBin   `json:"bins"`
+	Components  []ComponentStats `json:"components"`
+}
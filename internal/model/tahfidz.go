@@ -24,3 +24,144 @@ type TahfidzSetoran struct {
 	SantriNama string `json:"santri_nama" goqu:"skipinsert" db:"santri_nama"`
 	UstadzNama string `json:"ustadz_nama" goqu:"skipinsert" db:"ustadz_nama"`
 }
+
+// TahfidzJuzProgress is how much of one juz a santri has memorised
+type TahfidzJuzProgress struct {
+	Juz       int     `json:"juz"`
+	Ayat      int     `json:"ayat"`
+	TotalAyat int     `json:"total_ayat"`
+	Persen    float64 `json:"persen"`
+}
+
+// TahfidzProgress is the cumulative hafalan of a santri from the accepted ziyadah setoran.
+// Overlapping setoran count once; Halaman estimates pages of the 604-page Madinah mushaf.
+type TahfidzProgress struct {
+	SantriID        string               `json:"santri_id"`
+	SantriNama      string               `json:"santri_nama"`
+	UstadzID        *string              `json:"ustadz_id,omitempty"`
+	UstadzNama      string               `json:"ustadz_nama,omitempty"`
+	TotalAyat       int                  `json:"total_ayat"`
+	Halaman         float64              `json:"halaman"`
+	JuzSetara       float64              `json:"juz_setara"` // sum of the memorised share of each juz
+	JuzSelesai      []int                `json:"juz_selesai"`
+	Persen          float64              `json:"persen"` // of the whole mushaf
+	PerJuz          []TahfidzJuzProgress `json:"per_juz"`
+	SetoranTerakhir *time.Time           `json:"setoran_terakhir,omitempty"`
+	Peringkat       int                  `json:"peringkat,omitempty"`
+}
+
+// TahfidzLeaderboard ranks the santri of one halaqah by memorised ayat. Santri outside any
+// halaqah share a board with an empty HalaqahID.
+type TahfidzLeaderboard struct {
+	HalaqahID   string            `json:"halaqah_id"`
+	HalaqahNama string            `json:"halaqah_nama"`
+	UstadzNama  string            `json:"ustadz_nama"`
+	Santri      []TahfidzProgress `json:"santri"`
+}
+
+// Halaqah is a tahfidz study group of santri under one ustadz
+type Halaqah struct {
+	ID                    string           `json:"id"`
+	TenantID              string           `json:"tenant_id"`
+	Nama                  string           `json:"nama"`
+	UstadzID              *string          `json:"ustadz_id"`
+	UstadzNama            string           `json:"ustadz_nama"`
+	Kapasitas             int              `json:"kapasitas"`
+	BatasHariTanpaSetoran int              `json:"batas_hari_tanpa_setoran"` // alert after this many days without setoran
+	IsActive              bool             `json:"is_active"`
+	JumlahAnggota         int              `json:"jumlah_anggota"`
+	Anggota               []HalaqahAnggota `json:"anggota,omitempty"`
+	CreatedAt             time.Time        `json:"created_at"`
+	UpdatedAt             time.Time        `json:"updated_at"`
+}
+
+// HalaqahAnggota is a santri's membership of a halaqah, with what the alerts need
+type HalaqahAnggota struct {
+	TenantID              string    `json:"tenant_id"`
+	HalaqahID             string    `json:"halaqah_id"`
+	HalaqahNama           string    `json:"halaqah_nama"`
+	SantriID              string    `json:"santri_id"`
+	SantriNama            string    `json:"santri_nama"`
+	KelasNama             string    `json:"kelas_nama"`
+	BergabungAt           time.Time `json:"bergabung_at"`
+	UstadzNama            string    `json:"-"`
+	UstadzNoHP            string    `json:"-"`
+	NoHPWali              string    `json:"-"`
+	BatasHariTanpaSetoran int       `json:"-"`
+}
+
+// Status of a santri against their hafalan target
+const (
+	TahfidzTargetTercapai   = "Tercapai"
+	TahfidzTargetSesuai     = "Sesuai Target"
+	TahfidzTargetTertinggal = "Tertinggal"
+)
+
+// TargetHafalan is the new hafalan, in juz, a santri should add between Mulai and Selesai
+type TargetHafalan struct {
+	ID         string    `json:"id"`
+	TenantID   string    `json:"tenant_id"`
+	SantriID   string    `json:"santri_id"`
+	SantriNama string    `json:"santri_nama"`
+	SemesterID string    `json:"semester_id"`
+	TargetJuz  float64   `json:"target_juz"`
+	Mulai      time.Time `json:"mulai"`
+	Selesai    time.Time `json:"selesai"`
+	Catatan    string    `json:"catatan"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// TahfidzTargetStatus compares the hafalan added so far with the pace the target expects, which
+// grows evenly from Mulai to Selesai
+type TahfidzTargetStatus struct {
+	TargetHafalan
+	Tercapai   float64 `json:"tercapai"`   // juz added since Mulai
+	Diharapkan float64 `json:"diharapkan"` // juz expected by now
+	Persen     float64 `json:"persen"`     // of the target
+	Status     string  `json:"status"`
+}
+
+// MurajaahBagian is a continuous range of one surah
+type MurajaahBagian struct {
+	SurahNomor int    `json:"surah_nomor"`
+	Surah      string `json:"surah"`
+	AyatAwal   int    `json:"ayat_awal"`
+	AyatAkhir  int    `json:"ayat_akhir"`
+}
+
+// MurajaahPorsi is the murajaah of one day of the rotation
+type MurajaahPorsi struct {
+	Hari    int              `json:"hari"`
+	Tanggal time.Time        `json:"tanggal"`
+	Bagian  []MurajaahBagian `json:"bagian"`
+	Ayat    int              `json:"ayat"`
+	Halaman float64          `json:"halaman"`
+	HariIni bool             `json:"hari_ini"`
+}
+
+// MurajaahPlan rotates the whole hafalan of a santri over Siklus days, repeating from Mulai
+type MurajaahPlan struct {
+	SantriID   string          `json:"santri_id"`
+	SantriNama string          `json:"santri_nama"`
+	Mulai      time.Time       `json:"mulai"`
+	Siklus     int             `json:"siklus"`
+	Porsi      []MurajaahPorsi `json:"porsi"`
+}
+
+// Kinds of tahfidz alert
+const (
+	TahfidzAlertTanpaSetoran = "TANPA_SETORAN"
+	TahfidzAlertTertinggal   = "TERTINGGAL_TARGET"
+)
+
+// TahfidzAlert warns the ustadz and wali santri that a santri needs attention
+type TahfidzAlert struct {
+	TenantID    string `json:"tenant_id"`
+	SantriID    string `json:"santri_id"`
+	SantriNama  string `json:"santri_nama"`
+	HalaqahID   string `json:"halaqah_id"`
+	HalaqahNama string `json:"halaqah_nama"`
+	Jenis       string `json:"jenis"`
+	Pesan       string `json:"pesan"`
+}
@@ -0,0 +1,98 @@
+package model
+
+import "time"
+
+// ==========================================
+// UJIAN (PTS / PAS) MODELS
+// ==========================================
+
+// Jenis ujian
+const (
+	UjianJenisPTS = "PTS"
+	UjianJenisPAS = "PAS"
+	UjianJenisPAT = "PAT"
+	UjianJenisUS  = "US"
+)
+
+// Ujian is an exam event for one semester
+type Ujian struct {
+	ID             string    `json:"id" db:"id"`
+	TenantID       string    `json:"tenant_id" db:"tenant_id"`
+	NamaSekolah    string    `json:"nama_sekolah" db:"nama_sekolah"` // Joined from tenants
+	Nama           string    `json:"nama" db:"nama"`                 // e.g. PTS Ganjil 2026/2027
+	Jenis          string    `json:"jenis" db:"jenis"`
+	SemesterID     string    `json:"semester_id" db:"semester_id"` // e.g. 2026-2027-1
+	TanggalMulai   time.Time `json:"tanggal_mulai" db:"tanggal_mulai"`
+	TanggalSelesai time.Time `json:"tanggal_selesai" db:"tanggal_selesai"`
+	CekSPP         bool      `json:"cek_spp" db:"cek_spp"` // Flag unpaid SPP on exam cards
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// UjianJadwal is one per-mapel session for a tingkat
+type UjianJadwal struct {
+	ID           string    `json:"id" db:"id"`
+	UjianID      string    `json:"ujian_id" db:"ujian_id"`
+	SubjectID    string    `json:"subject_id" db:"subject_id"`
+	SubjectNama  string    `json:"subject_nama" db:"subject_nama"` // Joined
+	Tingkat      string    `json:"tingkat" db:"tingkat"`
+	WaktuMulai   time.Time `json:"waktu_mulai" db:"waktu_mulai"`
+	WaktuSelesai time.Time `json:"waktu_selesai" db:"waktu_selesai"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// UjianRuang is an exam room with seat capacity
+type UjianRuang struct {
+	ID            string `json:"id" db:"id"`
+	UjianID       string `json:"ujian_id" db:"ujian_id"`
+	Nama          string `json:"nama" db:"nama"`
+	Kapasitas     int    `json:"kapasitas" db:"kapasitas"`
+	JumlahPeserta int    `json:"jumlah_peserta" db:"jumlah_peserta"` // Calculated
+}
+
+// UjianPeserta is a seated student
+type UjianPeserta struct {
+	ID           string `json:"id" db:"id"`
+	UjianID      string `json:"ujian_id" db:"ujian_id"`
+	RuangID      string `json:"ruang_id" db:"ruang_id"`
+	RuangNama    string `json:"ruang_nama" db:"ruang_nama"` // Joined
+	SantriID     string `json:"santri_id" db:"santri_id"`
+	SantriNama   string `json:"santri_nama" db:"santri_nama"` // Joined
+	NIS          string `json:"nis" db:"nis"`                 // Joined
+	KelasID      string `json:"kelas_id" db:"kelas_id"`       // Joined
+	KelasNama    string `json:"kelas_nama" db:"kelas_nama"`   // Joined
+	Tingkat      string `json:"tingkat" db:"tingkat"`         // Joined
+	NomorKursi   int    `json:"nomor_kursi" db:"nomor_kursi"`
+	NomorPeserta string `json:"nomor_peserta" db:"nomor_peserta"`
+}
+
+// UjianPengawas assigns a guru to proctor a session in a room
+type UjianPengawas struct {
+	ID        string `json:"id" db:"id"`
+	JadwalID  string `json:"jadwal_id" db:"jadwal_id"`
+	RuangID   string `json:"ruang_id" db:"ruang_id"`
+	RuangNama string `json:"ruang_nama" db:"ruang_nama"` // Joined
+	GuruID    string `json:"guru_id" db:"guru_id"`
+	GuruNama  string `json:"guru_nama" db:"guru_nama"` // Joined
+}
+
+// UjianSiswa is a candidate student for seat allocation
+type UjianSiswa struct {
+	ID      string `db:"id"`
+	KelasID string `db:"kelas_id"`
+	Tingkat string `db:"tingkat"`
+}
+
+// KartuUjian is one printable exam card
+type KartuUjian struct {
+	Peserta    UjianPeserta
+	BelumLunas bool // Unpaid SPP, only set when Ujian.CekSPP
+}
+
+// DaftarHadirUjian is one attendance sheet for a room and session
+type DaftarHadirUjian struct {
+	Ruang    UjianRuang
+	Jadwal   UjianJadwal
+	Pengawas []string
+	Peserta  []UjianPeserta
+}
@@ -0,0 +1,27 @@
+package inbound_port
+
+import "github.com/gofiber/fiber/v2"
+
+// AlumniHttpPort defines handlers for alumni registry and tracer study module
+type AlumniHttpPort interface {
+	// Registry
+	GetAlumniList(c *fiber.Ctx) error
+	GetAlumniDetail(c *fiber.Ctx) error
+	CreateAlumni(c *fiber.Ctx) error
+	UpdateAlumni(c *fiber.Ctx) error
+	Kelulusan(c *fiber.Ctx) error
+	KirimLinkProfil(c *fiber.Ctx) error
+	GetStatistik(c *fiber.Ctx) error
+
+	// Tracer study
+	GetSurveyList(c *fiber.Ctx) error
+	CreateSurvey(c *fiber.Ctx) error
+	UpdateSurvey(c *fiber.Ctx) error
+	GetSurveyHasil(c *fiber.Ctx) error
+	BroadcastSurvey(c *fiber.Ctx) error
+
+	// Public self-service
+	GetProfil(c *fiber.Ctx) error
+	UpdateProfil(c *fiber.Ctx) error
+	IsiSurvey(c *fiber.Ctx) error
+}
@@ -0,0 +1,10 @@
+package inbound_port
+
+import "github.com/gofiber/fiber/v2"
+
+// DapodikHttpPort defines handlers for Dapodik sync module
+type DapodikHttpPort interface {
+	Export(c *fiber.Ctx) error
+	PreviewImport(c *fiber.Ctx) error
+	ApplyImport(c *fiber.Ctx) error
+}
@@ -0,0 +1,24 @@
+package inbound_port
+
+import "github.com/gofiber/fiber/v2"
+
+// EkskulHttpPort defines handlers for extracurricular module
+type EkskulHttpPort interface {
+	// Ekskul master
+	GetEkskulList(c *fiber.Ctx) error
+	CreateEkskul(c *fiber.Ctx) error
+	UpdateEkskul(c *fiber.Ctx) error
+	DeleteEkskul(c *fiber.Ctx) error
+
+	// Anggota
+	GetAnggota(c *fiber.Ctx) error
+	AddAnggota(c *fiber.Ctx) error
+	RemoveAnggota(c *fiber.Ctx) error
+
+	// Sesi & presensi
+	GetSesi(c *fiber.Ctx) error
+	CreateSesi(c *fiber.Ctx) error
+
+	// Penilaian
+	SavePenilaian(c *fiber.Ctx) error
+}
@@ -9,6 +9,8 @@ type ERaporHttpPort interface {
 	CreateSubject(c *fiber.Ctx) error
 	UpdateSubject(c *fiber.Ctx) error
 	DeleteSubject(c *fiber.Ctx) error
+	GetKKMHistory(c *fiber.Ctx) error
+	SetSemesterKKM(c *fiber.Ctx) error
 
 	// Grade CRUD
 	SaveGrade(c *fiber.Ctx) error
@@ -16,15 +18,100 @@ type ERaporHttpPort interface {
 	GetStudentGrades(c *fiber.Ctx) error
 	GetSubjectGrades(c *fiber.Ctx) error
 
+	// Raw assessment entries, the final grade is recalculated from them
+	GetAssessments(c *fiber.Ctx) error
+	CreateAssessment(c *fiber.Ctx) error
+	UpdateAssessment(c *fiber.Ctx) error
+	DeleteAssessment(c *fiber.Ctx) error
+
+	// K13 remedial & enrichment
+	RecordRemedial(c *fiber.Ctx) error
+	GetRemedialHistory(c *fiber.Ctx) error
+	GetRemedialCandidates(c *fiber.Ctx) error
+
+	// Kurikulum Merdeka CP/TP and capaian descriptions
+	GetCapaianList(c *fiber.Ctx) error
+	CreateCapaian(c *fiber.Ctx) error
+	UpdateCapaian(c *fiber.Ctx) error
+	DeleteCapaian(c *fiber.Ctx) error
+	CreateTujuan(c *fiber.Ctx) error
+	UpdateTujuan(c *fiber.Ctx) error
+	DeleteTujuan(c *fiber.Ctx) error
+	GetTPMarks(c *fiber.Ctx) error
+	SaveTPMarks(c *fiber.Ctx) error
+	GenerateCapaianDescriptions(c *fiber.Ctx) error
+	UpdateGradeDescription(c *fiber.Ctx) error
+
+	// P5 projects
+	GetP5ProjekList(c *fiber.Ctx) error
+	GetP5Projek(c *fiber.Ctx) error
+	CreateP5Projek(c *fiber.Ctx) error
+	UpdateP5Projek(c *fiber.Ctx) error
+	DeleteP5Projek(c *fiber.Ctx) error
+	SaveP5Penilaian(c *fiber.Ctx) error
+	CetakRaporP5(c *fiber.Ctx) error
+
+	// K13 sikap
+	GetJurnalSikap(c *fiber.Ctx) error
+	CreateJurnalSikap(c *fiber.Ctx) error
+	DeleteJurnalSikap(c *fiber.Ctx) error
+	GetSikapSiswa(c *fiber.Ctx) error
+	RekapSikap(c *fiber.Ctx) error
+	UpdateSikapSiswa(c *fiber.Ctx) error
+
+	// Pesantren predicate scales and tahfidz grading
+	GetPredicateSets(c *fiber.Ctx) error
+	SavePredicateSet(c *fiber.Ctx) error
+	DeletePredicateSet(c *fiber.Ctx) error
+	HitungTahfidz(c *fiber.Ctx) error
+
+	// Leger nilai and ranking
+	GetLeger(c *fiber.Ctx) error
+	ExportLeger(c *fiber.Ctx) error
+
+	// Tenant curriculum profiles and their selection per jenjang
+	GetCurriculumProfiles(c *fiber.Ctx) error
+	SaveCurriculumProfile(c *fiber.Ctx) error
+	DeleteCurriculumProfile(c *fiber.Ctx) error
+	GetTenantCurricula(c *fiber.Ctx) error
+	SetTenantCurriculum(c *fiber.Ctx) error
+	DeleteTenantCurriculum(c *fiber.Ctx) error
+
 	// Rapor
 	GetStudentRapor(c *fiber.Ctx) error
 	GenerateRapor(c *fiber.Ctx) error
+	CetakRapor(c *fiber.Ctx) error
+
+	// Lifecycle: Draft -> Submitted -> Approved -> Published, unlock back to Draft
+	SubmitRapor(c *fiber.Ctx) error
+	ApproveRapor(c *fiber.Ctx) error
+	PublishRapor(c *fiber.Ctx) error
+	UnlockRapor(c *fiber.Ctx) error
+	GetRaporRiwayat(c *fiber.Ctx) error
+
+	// Parent portal
+	GetRaporWaliList(c *fiber.Ctx) error
+	CetakRaporWali(c *fiber.Ctx) error
+
+	// Batch generation per kelas
+	CreateRaporBatch(c *fiber.Ctx) error
+	GetRaporBatchList(c *fiber.Ctx) error
+	GetRaporBatch(c *fiber.Ctx) error
+	DownloadRaporBatch(c *fiber.Ctx) error
 	GetRaporHistory(c *fiber.Ctx) error
 
 	// Stats
 	GetStats(c *fiber.Ctx) error
+	CompareKelasStats(c *fiber.Ctx) error
+	CompareSemesterStats(c *fiber.Ctx) error
+	GetGradeHistogram(c *fiber.Ctx) error
 
 	// Curriculum Settings
 	GetCurriculum(c *fiber.Ctx) error
 	SetCurriculum(c *fiber.Ctx) error
 }
+
+// RaporMessagePort handles rapor jobs consumed by the message worker
+type RaporMessagePort interface {
+	GenerateBatch(a any) bool
+}
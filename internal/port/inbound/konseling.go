@@ -0,0 +1,22 @@
+package inbound_port
+
+import "github.com/gofiber/fiber/v2"
+
+// KonselingHttpPort defines handlers for BK counseling module
+type KonselingHttpPort interface {
+	// Kasus (BK & kepala sekolah)
+	GetKasusList(c *fiber.Ctx) error
+	GetKasusDetail(c *fiber.Ctx) error
+	CreateKasus(c *fiber.Ctx) error
+	UpdateKasus(c *fiber.Ctx) error
+
+	// Sesi & tindak lanjut
+	AddSesi(c *fiber.Ctx) error
+	SetTindakLanjutSelesai(c *fiber.Ctx) error
+
+	// Pelanggaran
+	LinkPelanggaran(c *fiber.Ctx) error
+
+	// Ringkasan (wali kelas)
+	GetRingkasanKelas(c *fiber.Ctx) error
+}
@@ -0,0 +1,33 @@
+package inbound_port
+
+import "github.com/gofiber/fiber/v2"
+
+// PerpustakaanHttpPort defines handlers for library module
+type PerpustakaanHttpPort interface {
+	// Katalog
+	GetBukuList(c *fiber.Ctx) error
+	GetBukuDetail(c *fiber.Ctx) error
+	CreateBuku(c *fiber.Ctx) error
+	UpdateBuku(c *fiber.Ctx) error
+	DeleteBuku(c *fiber.Ctx) error
+	AddEksemplar(c *fiber.Ctx) error
+
+	// Anggota
+	SearchAnggota(c *fiber.Ctx) error
+
+	// Sirkulasi
+	GetPeminjamanList(c *fiber.Ctx) error
+	Pinjam(c *fiber.Ctx) error
+	Kembalikan(c *fiber.Ctx) error
+	Perpanjang(c *fiber.Ctx) error
+	BayarDenda(c *fiber.Ctx) error
+
+	// Laporan
+	GetOverdue(c *fiber.Ctx) error
+	GetBukuPopuler(c *fiber.Ctx) error
+	SendOverdueNotices(c *fiber.Ctx) error
+
+	// Config
+	GetConfig(c *fiber.Ctx) error
+	SaveConfig(c *fiber.Ctx) error
+}
@@ -0,0 +1,26 @@
+package inbound_port
+
+import "github.com/gofiber/fiber/v2"
+
+// PPDBHttpPort defines handlers for new-student admission module
+type PPDBHttpPort interface {
+	// Public (tenant subdomain)
+	GetPublicInfo(c *fiber.Ctx) error
+	Daftar(c *fiber.Ctx) error
+	CekStatus(c *fiber.Ctx) error
+	BuatPembayaran(c *fiber.Ctx) error
+	PaymentWebhook(c *fiber.Ctx) error
+
+	// Gelombang
+	GetGelombangList(c *fiber.Ctx) error
+	CreateGelombang(c *fiber.Ctx) error
+	UpdateGelombang(c *fiber.Ctx) error
+
+	// Seleksi
+	GetPendaftarList(c *fiber.Ctx) error
+	GetPendaftarDetail(c *fiber.Ctx) error
+	UpdateStatus(c *fiber.Ctx) error
+	AddDokumen(c *fiber.Ctx) error
+	KonfirmasiBayar(c *fiber.Ctx) error
+	KonversiSiswa(c *fiber.Ctx) error
+}
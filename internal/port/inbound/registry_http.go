@@ -18,4 +18,11 @@ type HttpPort interface {
 	Subscription() SubscriptionHttpPort
 	Analytics() AnalyticsHttpPort
 	Export() ExportHttpPort
+	Ekskul() EkskulHttpPort
+	Konseling() KonselingHttpPort
+	Perpustakaan() PerpustakaanHttpPort
+	PPDB() PPDBHttpPort
+	Ujian() UjianHttpPort
+	Dapodik() DapodikHttpPort
+	Alumni() AlumniHttpPort
 }
@@ -2,4 +2,5 @@ package inbound_port
 
 type MessagePort interface {
 	Client() ClientMessagePort
+	Rapor() RaporMessagePort
 }
@@ -19,18 +19,61 @@ type SekolahHttpPort interface {
 	CreateKamar(c *fiber.Ctx) error
 	GetPenempatanList(c *fiber.Ctx) error
 	CreatePenempatan(c *fiber.Ctx) error
+	PindahKamar(c *fiber.Ctx) error
+	KeluarAsrama(c *fiber.Ctx) error
+	GetRiwayatPenempatan(c *fiber.Ctx) error
+	AlokasiPenempatan(c *fiber.Ctx) error
+	GetOkupansiAsrama(c *fiber.Ctx) error
 
 	// Kepesantrenan
 	GetPelanggaranAturanList(c *fiber.Ctx) error
 	CreatePelanggaranAturan(c *fiber.Ctx) error
 	GetPelanggaranSiswaList(c *fiber.Ctx) error
 	CreatePelanggaranSiswa(c *fiber.Ctx) error
+	// Poin pelanggaran & sanksi
+	GetSanksiAmbang(c *fiber.Ctx) error
+	SaveSanksiAmbang(c *fiber.Ctx) error
+	GetRekapPoin(c *fiber.Ctx) error
+	GetPoinSantri(c *fiber.Ctx) error
+	CreatePoinKredit(c *fiber.Ctx) error
+	GetSanksiList(c *fiber.Ctx) error
+	UpdateSanksiStatus(c *fiber.Ctx) error
+	KirimSanksiWali(c *fiber.Ctx) error
+	CetakSuratPeringatan(c *fiber.Ctx) error
 	GetPerizinanList(c *fiber.Ctx) error
 	CreatePerizinan(c *fiber.Ctx) error
+	GetPerizinan(c *fiber.Ctx) error
+	ApprovePerizinan(c *fiber.Ctx) error
+	RejectPerizinan(c *fiber.Ctx) error
+	GetGatePass(c *fiber.Ctx) error
+	CheckOutPerizinan(c *fiber.Ctx) error
+	CheckInPerizinan(c *fiber.Ctx) error
+	ScanGatePass(c *fiber.Ctx) error
+	GetPerizinanTerlambat(c *fiber.Ctx) error
+	KirimPerizinanTerlambat(c *fiber.Ctx) error
 
 	// Tahfidz
 	GetTahfidzSetoranList(c *fiber.Ctx) error
 	CreateTahfidzSetoran(c *fiber.Ctx) error
+	GetTahfidzProgress(c *fiber.Ctx) error
+	GetTahfidzLeaderboard(c *fiber.Ctx) error
+	GetDaftarSurah(c *fiber.Ctx) error
+	GetMurajaahPlan(c *fiber.Ctx) error
+	GetTahfidzAlerts(c *fiber.Ctx) error
+	KirimTahfidzAlerts(c *fiber.Ctx) error
+	// Halaqah
+	GetHalaqahList(c *fiber.Ctx) error
+	GetHalaqah(c *fiber.Ctx) error
+	CreateHalaqah(c *fiber.Ctx) error
+	UpdateHalaqah(c *fiber.Ctx) error
+	DeleteHalaqah(c *fiber.Ctx) error
+	AddHalaqahAnggota(c *fiber.Ctx) error
+	RemoveHalaqahAnggota(c *fiber.Ctx) error
+	// Target hafalan
+	GetTahfidzTargets(c *fiber.Ctx) error
+	SaveTahfidzTarget(c *fiber.Ctx) error
+	DeleteTahfidzTarget(c *fiber.Ctx) error
+	GetTahfidzTargetStatus(c *fiber.Ctx) error
 
 	// Diniyah
 	GetDiniyahKitabList(c *fiber.Ctx) error
@@ -0,0 +1,31 @@
+package inbound_port
+
+import "github.com/gofiber/fiber/v2"
+
+// UjianHttpPort defines handlers for exam scheduling module
+type UjianHttpPort interface {
+	// Ujian
+	GetUjianList(c *fiber.Ctx) error
+	GetUjianDetail(c *fiber.Ctx) error
+	CreateUjian(c *fiber.Ctx) error
+	UpdateUjian(c *fiber.Ctx) error
+
+	// Jadwal & ruang
+	AddJadwal(c *fiber.Ctx) error
+	DeleteJadwal(c *fiber.Ctx) error
+	AddRuang(c *fiber.Ctx) error
+	DeleteRuang(c *fiber.Ctx) error
+
+	// Tempat duduk
+	AturTempatDuduk(c *fiber.Ctx) error
+	GetPeserta(c *fiber.Ctx) error
+
+	// Pengawas
+	GetPengawas(c *fiber.Ctx) error
+	AssignPengawas(c *fiber.Ctx) error
+	DeletePengawas(c *fiber.Ctx) error
+
+	// Cetak
+	CetakKartu(c *fiber.Ctx) error
+	CetakDaftarHadir(c *fiber.Ctx) error
+}
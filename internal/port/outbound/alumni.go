@@ -0,0 +1,35 @@
+package outbound_port
+
+import (
+	"context"
+
+	"prabogo/internal/model"
+)
+
+// AlumniDatabasePort defines the interface for alumni registry and tracer study database operations
+type AlumniDatabasePort interface {
+	// Kelulusan
+	// GetCalonAlumni returns alumni records prefilled from siswa of a class or the given ids
+	GetCalonAlumni(ctx context.Context, tenantID, kelasID string, siswaIDs []string) ([]model.Alumni, error)
+	// CreateAlumniFromSiswa inserts the alumni (skipping siswa already registered) and marks the siswa Lulus
+	CreateAlumniFromSiswa(ctx context.Context, alumni []model.Alumni) (int, error)
+
+	// Alumni
+	CreateAlumni(ctx context.Context, a *model.Alumni) error
+	UpdateAlumni(ctx context.Context, a *model.Alumni) error
+	UpdateProfil(ctx context.Context, id string, in model.AlumniProfilInput) error
+	GetAlumniByID(ctx context.Context, tenantID, id string) (*model.Alumni, error)
+	GetAlumniByToken(ctx context.Context, token string) (*model.Alumni, error)
+	GetAlumniList(ctx context.Context, tenantID string, filter model.AlumniFilter) ([]model.Alumni, error)
+	GetAlumniTarget(ctx context.Context, tenantID string, dari, sampai int) ([]model.Alumni, error)
+	GetStatistik(ctx context.Context, tenantID string) ([]model.AlumniStatistik, error)
+
+	// Tracer study
+	CreateSurvey(ctx context.Context, s *model.TracerSurvey) error
+	UpdateSurvey(ctx context.Context, s *model.TracerSurvey) error
+	GetSurveyByID(ctx context.Context, tenantID, id string) (*model.TracerSurvey, error)
+	GetSurveyList(ctx context.Context, tenantID, status string) ([]model.TracerSurvey, error)
+	SaveJawaban(ctx context.Context, j *model.TracerJawaban) error
+	GetJawabanBySurvey(ctx context.Context, surveyID string) ([]model.TracerJawaban, error)
+	GetSurveyDijawab(ctx context.Context, alumniID string) (map[string]bool, error)
+}
@@ -0,0 +1,17 @@
+package outbound_port
+
+import (
+	"context"
+
+	"prabogo/internal/model"
+)
+
+// DapodikDatabasePort defines the interface for Dapodik sync database operations
+type DapodikDatabasePort interface {
+	GetSiswa(ctx context.Context, tenantID string) ([]model.Siswa, error)
+	GetGuru(ctx context.Context, tenantID string) ([]model.Guru, error)
+	GetRombel(ctx context.Context, tenantID string) ([]model.Kelas, error)
+
+	// ApplyImport inserts new records and updates the Dapodik fields of matched ones in one transaction
+	ApplyImport(ctx context.Context, tenantID string, data model.DapodikImport) error
+}
@@ -0,0 +1,32 @@
+package outbound_port
+
+import (
+	"context"
+
+	"prabogo/internal/model"
+)
+
+// EkskulDatabasePort defines the interface for extracurricular database operations
+type EkskulDatabasePort interface {
+	// Ekskul master
+	CreateEkskul(ctx context.Context, ekskul *model.Ekskul) error
+	UpdateEkskul(ctx context.Context, ekskul *model.Ekskul) error
+	GetEkskulByID(ctx context.Context, tenantID, id string) (*model.Ekskul, error)
+	GetEkskulByTenant(ctx context.Context, tenantID string) ([]model.Ekskul, error)
+	DeleteEkskul(ctx context.Context, tenantID, id string) error
+	SantriExists(ctx context.Context, tenantID, santriID string) (bool, error)
+	GuruExists(ctx context.Context, tenantID, guruID string) (bool, error)
+
+	// Anggota (membership per semester)
+	AddAnggota(ctx context.Context, anggota *model.EkskulAnggota) error
+	RemoveAnggota(ctx context.Context, tenantID, id string) error
+	GetAnggotaByID(ctx context.Context, tenantID, id string) (*model.EkskulAnggota, error)
+	GetAnggotaByEkskul(ctx context.Context, tenantID, ekskulID, semesterID string) ([]model.EkskulAnggota, error)
+	GetAnggotaBySantri(ctx context.Context, tenantID, santriID, semesterID string) ([]model.EkskulAnggota, error)
+	UpdatePenilaian(ctx context.Context, tenantID, anggotaID, predikat, deskripsi string) error
+
+	// Sesi & presensi
+	CreateSesi(ctx context.Context, sesi *model.EkskulSesi) error
+	GetSesiByEkskul(ctx context.Context, tenantID, ekskulID, semesterID string) ([]model.EkskulSesi, error)
+	SavePresensi(ctx context.Context, sesiID string, presensi []model.EkskulPresensi) error
+}
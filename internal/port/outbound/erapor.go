@@ -2,6 +2,7 @@ package outbound_port
 
 import (
 	"context"
+	"time"
 
 	"prabogo/internal/model"
 )
@@ -20,13 +21,135 @@ type ERaporDatabasePort interface {
 	BatchSaveGrades(ctx context.Context, input *model.BatchGradeInput) ([]model.StudentGrade, error)
 	GetGradesByStudent(ctx context.Context, studentID, semesterID string) ([]model.StudentGrade, error)
 	GetGradesBySubject(ctx context.Context, subjectID, semesterID string) ([]model.StudentGrade, error)
-	GetStudentRapor(ctx context.Context, studentID, semesterID string) (*model.RaporData, error)
+	GetStudentRapor(ctx context.Context, tenantID, studentID, semesterID string) (*model.RaporData, error)
 
-	// Stats
-	GetGradeStats(ctx context.Context, tenantID, semesterID string) (map[string]interface{}, error)
+	// Assessment entries
+	CreateAssessmentEntry(ctx context.Context, e *model.AssessmentEntry) error
+	UpdateAssessmentEntry(ctx context.Context, e *model.AssessmentEntry) error
+	DeleteAssessmentEntry(ctx context.Context, tenantID, id string) error
+	GetAssessmentEntry(ctx context.Context, tenantID, id string) (*model.AssessmentEntry, error)
+	GetAssessmentEntries(ctx context.Context, tenantID, studentID, subjectID, semesterID string) ([]model.AssessmentEntry, error)
+	// GetAssessedStudents lists the student/semester pairs that have entries for a subject
+	GetAssessedStudents(ctx context.Context, tenantID, subjectID string) ([]model.AssessmentEntry, error)
+
+	// Remedial & enrichment (append-only)
+	CreateRemedialSession(ctx context.Context, r *model.RemedialSession) error
+	GetRemedialSessions(ctx context.Context, tenantID, subjectID, semesterID, studentID string) ([]model.RemedialSession, error)
+
+	// KKM per semester
+	GetKKMHistory(ctx context.Context, tenantID, subjectID string) ([]model.KKMHistory, error)
+	// GetSemesterKKM maps subject id to the KKM pinned for the semester
+	GetSemesterKKM(ctx context.Context, tenantID, semesterID string) (map[string]int, error)
+	SaveKKMHistory(ctx context.Context, h *model.KKMHistory) error
+	// GetGradedSemesters lists the semesters that have final grades for a subject
+	GetGradedSemesters(ctx context.Context, subjectID string) ([]string, error)
+
+	// Leger nilai
+	// GetLegerKelas returns the kelas with its active students as empty leger rows; nil when not found
+	GetLegerKelas(ctx context.Context, tenantID, kelasID string) (*model.Leger, error)
+	GetGradesByKelas(ctx context.Context, tenantID, kelasID, semesterID string) ([]model.StudentGrade, error)
+	// GetRaporKehadiran reads the attendance frozen in the students' rapor of the semester
+	GetRaporKehadiran(ctx context.Context, tenantID, semesterID string, siswaIDs []string) (map[string]model.AttendanceData, error)
+
+	// Curriculum profiles (curriculum_references) and their selection per jenjang
+	GetCurriculumProfiles(ctx context.Context, tenantID string) ([]model.CurriculumProfile, error)
+	// GetCurriculumProfile finds a profile by code, the tenant's own before a system one
+	GetCurriculumProfile(ctx context.Context, tenantID, code string) (*model.CurriculumProfile, error)
+	GetCurriculumProfileByID(ctx context.Context, tenantID, id string) (*model.CurriculumProfile, error)
+	SaveCurriculumProfile(ctx context.Context, p *model.CurriculumProfile) error
+	DeleteCurriculumProfile(ctx context.Context, tenantID, id string) error
+	GetTenantCurricula(ctx context.Context, tenantID string) ([]model.TenantCurriculum, error)
+	SaveTenantCurriculum(ctx context.Context, tc *model.TenantCurriculum) error
+	DeleteTenantCurriculum(ctx context.Context, tenantID, jenjang string) error
+	GetTenantJenjangs(ctx context.Context, tenantID string) ([]string, error)
+
+	// Kurikulum Merdeka CP/TP
+	GetCapaianList(ctx context.Context, tenantID, subjectID, fase string) ([]model.CapaianPembelajaran, error)
+	GetCapaian(ctx context.Context, tenantID, id string) (*model.CapaianPembelajaran, error)
+	SaveCapaian(ctx context.Context, cp *model.CapaianPembelajaran) error
+	DeleteCapaian(ctx context.Context, tenantID, id string) error
+	GetTujuan(ctx context.Context, tenantID, id string) (*model.TujuanPembelajaran, error)
+	SaveTujuan(ctx context.Context, tp *model.TujuanPembelajaran) error
+	DeleteTujuan(ctx context.Context, tenantID, id string) error
+	SaveTPAchievements(ctx context.Context, tenantID, semesterID string, marks []model.TPMark) error
+	// GetTPAchievements returns the marks of a subject/semester joined with their TP, optionally for one student
+	GetTPAchievements(ctx context.Context, tenantID, subjectID, semesterID, studentID string) ([]model.TPAchievement, error)
+
+	// P5 projects
+	// GetP5ProjekList lists the projects of a semester, optionally for one kelas
+	GetP5ProjekList(ctx context.Context, tenantID, semesterID, kelasID string) ([]model.P5Projek, error)
+	GetP5Projek(ctx context.Context, tenantID, id string) (*model.P5Projek, error)
+	SaveP5Projek(ctx context.Context, p *model.P5Projek) error
+	DeleteP5Projek(ctx context.Context, tenantID, id string) error
+	// SaveP5Penilaian upserts the assessment of each student on the project
+	SaveP5Penilaian(ctx context.Context, tenantID, projekID string, list []model.P5Penilaian) error
+	// GetP5Penilaian filters by project and/or student; empty filters are ignored
+	GetP5Penilaian(ctx context.Context, tenantID, projekID, studentID string) ([]model.P5Penilaian, error)
+
+	// K13 sikap
+	CreateJurnalSikap(ctx context.Context, j *model.JurnalSikap) error
+	GetJurnalSikapByID(ctx context.Context, tenantID, id string) (*model.JurnalSikap, error)
+	DeleteJurnalSikap(ctx context.Context, tenantID, id string) error
+	// GetJurnalSikap lists the journal of a semester; studentIDs narrows it when not empty
+	GetJurnalSikap(ctx context.Context, tenantID, semesterID string, studentIDs []string) ([]model.JurnalSikap, error)
+	// GetSikapSiswa lists the compiled predicates of a semester; studentIDs narrows it when not empty
+	GetSikapSiswa(ctx context.Context, tenantID, semesterID string, studentIDs []string) ([]model.SikapSiswa, error)
+	GetSikapSiswaByID(ctx context.Context, tenantID, id string) (*model.SikapSiswa, error)
+	// SaveSikapSiswa upserts by student, semester and aspek
+	SaveSikapSiswa(ctx context.Context, list []model.SikapSiswa) error
+
+	// Pesantren grading
+	GetPredicateSets(ctx context.Context, tenantID string) ([]model.PredicateSet, error)
+	SavePredicateSet(ctx context.Context, set *model.PredicateSet) error
+	DeletePredicateSet(ctx context.Context, tenantID, subjectType string) error
+	// GetTahfidzSetoranPeriode lists setoran between start and end (inclusive); santriIDs narrows it when not empty
+	GetTahfidzSetoranPeriode(ctx context.Context, tenantID string, santriIDs []string, start, end time.Time) ([]model.TahfidzSetoran, error)
+
+	// Grade statistics, aggregated in SQL
+	// GetGradeStatsGrouped returns one row per subject, kelas or semester (one row over all
+	// grades when groupBy is empty), with the predicate counts of each group
+	GetGradeStatsGrouped(ctx context.Context, tenantID, groupBy string, filter model.GradeStatsFilter) ([]model.GradeStats, error)
+	// GetGradeHistogram counts the final scores per bin, keyed by the bin's lower bound
+	GetGradeHistogram(ctx context.Context, tenantID string, filter model.GradeStatsFilter, binWidth int) (map[int]int, error)
+	// GetComponentStats summarises the component scores by their 0-based position
+	GetComponentStats(ctx context.Context, tenantID string, filter model.GradeStatsFilter) (map[int]model.ComponentStats, error)
 
 	// Snapshot Operations (Rapor)
 	GetOrCreateRaporPeriode(tenantID, name string) (*model.RaporPeriode, error)
-	CreateRapor(m *model.Rapor) error
-	CreateRaporNilai(m *model.RaporNilai) error
+	// SaveRaporSnapshot writes the rapor header and all of m.NilaiList in one transaction,
+	// replacing a Draft rapor of the same periode and santri. Returns false when that
+	// rapor is already past Draft.
+	SaveRaporSnapshot(ctx context.Context, m *model.Rapor) (bool, error)
+	// SaveRaporLogo keeps the logo bytes frozen into snapshots, keyed by their SHA-256
+	SaveRaporLogo(ctx context.Context, tenantID, hash string, logo []byte) error
+	GetRaporLogo(ctx context.Context, tenantID, hash string) ([]byte, error)
+	GetRaporHeader(ctx context.Context, tenantID, studentID string) (*model.RaporHeader, error)
+	GetRaporByID(ctx context.Context, tenantID, id string) (*model.Rapor, error)
+	GetRaporIDBySiswa(ctx context.Context, periodeID, siswaID string) (string, error)
+
+	// Lifecycle
+	// UpdateRaporStatus moves the rapor from log.DariStatus to log.KeStatus and records the log
+	// in one transaction; it returns false when the rapor is no longer in DariStatus
+	UpdateRaporStatus(ctx context.Context, tenantID string, log *model.RaporStatusLog) (bool, error)
+	GetRaporStatusLog(ctx context.Context, raporID string) ([]model.RaporStatusLog, error)
+	// GetRaporStatusBySiswa maps siswa ID to the status of their rapor for the semester
+	GetRaporStatusBySiswa(ctx context.Context, tenantID, semesterID string, siswaIDs []string) (map[string]string, error)
+	GetSiswaWali(ctx context.Context, tenantID, siswaID string) (*model.Siswa, error)
+	// GetRaporWaliList returns the published rapor of the children whose guardian phone matches the user
+	GetRaporWaliList(ctx context.Context, tenantID, userID string) ([]model.RaporWali, error)
+
+	// Batch generation
+	GetSiswaByKelas(ctx context.Context, tenantID, kelasID string) ([]model.RaporBatchSiswa, error)
+	CreateRaporBatch(ctx context.Context, b *model.RaporBatch) error
+	GetRaporBatch(ctx context.Context, tenantID, id string) (*model.RaporBatch, error)
+	GetRaporBatchList(ctx context.Context, tenantID string) ([]model.RaporBatch, error)
+	// UpdateRaporBatchProgress saves status, counters and errors (not the files)
+	UpdateRaporBatchProgress(ctx context.Context, b *model.RaporBatch) error
+	SaveRaporBatchFiles(ctx context.Context, id string, zipFile, pdfFile []byte) error
+	GetRaporBatchFile(ctx context.Context, tenantID, id, jenis string) ([]byte, error)
+}
+
+// RaporMessagePort publishes rapor jobs to the message worker
+type RaporMessagePort interface {
+	PublishGenerateBatch(msg model.RaporBatchMessage) error
 }
@@ -0,0 +1,34 @@
+package outbound_port
+
+import (
+	"context"
+
+	"prabogo/internal/model"
+)
+
+// KonselingDatabasePort defines the interface for BK counseling database operations.
+// Catatan fields are passed through as-is; encryption is handled by the domain.
+type KonselingDatabasePort interface {
+	// Kasus
+	CreateKasus(ctx context.Context, kasus *model.KonselingKasus) error
+	UpdateKasus(ctx context.Context, kasus *model.KonselingKasus) error
+	GetKasusByID(ctx context.Context, tenantID, id string) (*model.KonselingKasus, error)
+	GetKasusList(ctx context.Context, tenantID, santriID string) ([]model.KonselingKasus, error)
+	SantriExists(ctx context.Context, tenantID, santriID string) (bool, error)
+
+	// Sesi
+	CreateSesi(ctx context.Context, sesi *model.KonselingSesi) error
+	GetSesiByKasus(ctx context.Context, kasusID string) ([]model.KonselingSesi, error)
+	SetTindakLanjutSelesai(ctx context.Context, kasusID, sesiID string, selesai bool) error
+
+	// Pelanggaran links
+	LinkPelanggaran(ctx context.Context, kasusID string, pelanggaranIDs []string) error
+	GetPelanggaranByKasus(ctx context.Context, tenantID, kasusID string) ([]model.PelanggaranSiswa, error)
+	// GetPelanggaranIDsBySantri returns the subset of ids recorded for the santri in the tenant
+	GetPelanggaranIDsBySantri(ctx context.Context, tenantID, santriID string, ids []string) ([]string, error)
+
+	// Ringkasan for wali kelas (no confidential notes)
+	GetRingkasanByKelas(ctx context.Context, tenantID, kelasID string) ([]model.KonselingRingkasan, error)
+	// IsWaliKelas reports whether the user is the guru assigned as wali of the kelas
+	IsWaliKelas(ctx context.Context, tenantID, kelasID, userID string) (bool, error)
+}
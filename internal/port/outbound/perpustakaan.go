@@ -0,0 +1,48 @@
+package outbound_port
+
+import (
+	"context"
+	"time"
+
+	"prabogo/internal/model"
+)
+
+// PerpustakaanDatabasePort defines the interface for library database operations
+type PerpustakaanDatabasePort interface {
+	// Katalog
+	CreateBuku(ctx context.Context, buku *model.Buku) error
+	UpdateBuku(ctx context.Context, buku *model.Buku) error
+	GetBukuByID(ctx context.Context, tenantID, id string) (*model.Buku, error)
+	GetBukuList(ctx context.Context, tenantID, search string) ([]model.Buku, error)
+	DeleteBuku(ctx context.Context, tenantID, id string) error
+
+	// Eksemplar
+	CreateEksemplar(ctx context.Context, eksemplar *model.BukuEksemplar) error
+	GetEksemplarByBuku(ctx context.Context, tenantID, bukuID string) ([]model.BukuEksemplar, error)
+	GetEksemplarByBarcode(ctx context.Context, tenantID, barcode string) (*model.BukuEksemplar, error)
+	UpdateEksemplarStatus(ctx context.Context, id, status string) error
+
+	// Anggota (siswa / pegawai)
+	SearchAnggota(ctx context.Context, tenantID, search string) ([]model.PerpustakaanAnggota, error)
+	GetAnggota(ctx context.Context, tenantID, tipe, id string) (*model.PerpustakaanAnggota, error)
+
+	// Peminjaman
+	CreatePeminjaman(ctx context.Context, p *model.Peminjaman) error
+	// UpdatePeminjaman writes the loan only while it still has statusLama and
+	// reports whether a row was updated
+	UpdatePeminjaman(ctx context.Context, p *model.Peminjaman, statusLama string) (bool, error)
+	// LunasiDenda marks an unpaid fine of a closed loan as paid
+	LunasiDenda(ctx context.Context, tenantID, id string) (bool, error)
+	GetPeminjamanByID(ctx context.Context, tenantID, id string) (*model.Peminjaman, error)
+	GetPeminjamanList(ctx context.Context, filter model.PeminjamanFilter) ([]model.Peminjaman, error)
+	CountActivePeminjaman(ctx context.Context, tenantID, anggotaTipe, anggotaID string) (int, error)
+	// GetOverduePeminjaman returns active loans past due; empty tenantID means all tenants
+	GetOverduePeminjaman(ctx context.Context, tenantID string, asOf time.Time) ([]model.Peminjaman, error)
+
+	// Laporan
+	GetBukuPopuler(ctx context.Context, tenantID string, from, to time.Time, limit int) ([]model.BukuPopuler, error)
+
+	// Config
+	GetConfig(ctx context.Context, tenantID string) (*model.PerpustakaanConfig, error)
+	SaveConfig(ctx context.Context, config *model.PerpustakaanConfig) error
+}
@@ -0,0 +1,41 @@
+package outbound_port
+
+import (
+	"context"
+
+	"prabogo/internal/model"
+)
+
+// PPDBDatabasePort defines the interface for new-student admission database operations
+type PPDBDatabasePort interface {
+	// Gelombang
+	CreateGelombang(ctx context.Context, g *model.PPDBGelombang) error
+	UpdateGelombang(ctx context.Context, g *model.PPDBGelombang) error
+	// GetGelombangByID locks the wave row when called inside a transaction
+	GetGelombangByID(ctx context.Context, tenantID, id string) (*model.PPDBGelombang, error)
+	GetGelombangList(ctx context.Context, tenantID string, activeOnly bool) ([]model.PPDBGelombang, error)
+
+	// Pendaftar
+	CreatePendaftar(ctx context.Context, p *model.PPDBPendaftar) error
+	// UpdatePendaftarStatus writes the selection result only while the applicant
+	// still has statusLama and reports whether a row was updated
+	UpdatePendaftarStatus(ctx context.Context, p *model.PPDBPendaftar, statusLama string) (bool, error)
+	UpdateStatusBayar(ctx context.Context, tenantID, id, statusBayar, orderID string) error
+	GetPendaftarByID(ctx context.Context, tenantID, id string) (*model.PPDBPendaftar, error)
+	GetPendaftarByNo(ctx context.Context, tenantID, noPendaftaran string) (*model.PPDBPendaftar, error)
+	// GetPendaftarForPayment looks the applicant up across tenants for the signed payment webhook
+	GetPendaftarForPayment(ctx context.Context, id string) (*model.PPDBPendaftar, error)
+	GetPendaftarList(ctx context.Context, filter model.PPDBPendaftarFilter) ([]model.PPDBPendaftar, error)
+	// NextNoUrut atomically reserves the next registration sequence for the academic year
+	NextNoUrut(ctx context.Context, tenantID, tahunAjaran string) (int, error)
+	CountDiterima(ctx context.Context, gelombangID string) (int, error)
+
+	// Dokumen
+	AddDokumen(ctx context.Context, d *model.PPDBDokumen) error
+	GetDokumenByPendaftar(ctx context.Context, pendaftarID string) ([]model.PPDBDokumen, error)
+
+	// KelasExists reports whether the kelas belongs to the tenant
+	KelasExists(ctx context.Context, tenantID, kelasID string) (bool, error)
+	// KonversiSiswa inserts the applicant into sekolah_siswa and links siswa_id in one transaction
+	KonversiSiswa(ctx context.Context, p *model.PPDBPendaftar, kelasID, nis string) (string, error)
+}
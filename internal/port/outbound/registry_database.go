@@ -20,6 +20,13 @@ type DatabasePort interface {
 	SDM() SDMDatabasePort
 	Subscription() SubscriptionDatabasePort
 	PesantrenDashboard() PesantrenDashboardPort
+	Ekskul() EkskulDatabasePort
+	Konseling() KonselingDatabasePort
+	Perpustakaan() PerpustakaanDatabasePort
+	PPDB() PPDBDatabasePort
+	Ujian() UjianDatabasePort
+	Dapodik() DapodikDatabasePort
+	Alumni() AlumniDatabasePort
 	DoInTransaction(txFunc InTransaction) (out interface{}, err error)
 }
 
@@ -4,4 +4,5 @@ package outbound_port
 type MessagePort interface {
 	Client() ClientMessagePort
 	WhatsApp() WhatsAppMessagePort
+	Rapor() RaporMessagePort
 }
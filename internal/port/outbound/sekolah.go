@@ -1,11 +1,20 @@
 package outbound_port
 
-import "prabogo/internal/model"
+import (
+	"time"
+
+	"prabogo/internal/model"
+)
 
 type SekolahPort interface {
 	GetSiswaByTenant(tenantID string) ([]model.Siswa, error)
+	// GetSiswaByID returns nil when the siswa does not belong to the tenant
+	GetSiswaByID(tenantID, id string) (*model.Siswa, error)
 	CreateSiswa(siswa model.Siswa) error
 	GetGuruByTenant(tenantID string) ([]model.Guru, error)
+	// GetGuruByUser returns the guru a login belongs to, matched by WhatsApp number; nil when
+	// the user is not a guru of the tenant
+	GetGuruByUser(tenantID, userID string) (*model.Guru, error)
 	CreateGuru(guru model.Guru) error
 	GetMapelByTenant(tenantID string) ([]model.Mapel, error)
 	GetKelasByTenant(tenantID string) ([]model.Kelas, error)
@@ -16,20 +25,93 @@ type SekolahPort interface {
 	CreateAsrama(asrama *model.Asrama) error
 	GetKamarByAsrama(tenantID, asramaID string) ([]model.Kamar, error)
 	CreateKamar(kamar *model.Kamar) error
+	// GetKamarByID returns nil when the room does not belong to the tenant; it locks the room
+	// inside a transaction
+	GetKamarByID(tenantID, id string) (*model.Kamar, error)
 	GetPenempatanByTenant(tenantID string) ([]model.Penempatan, error)
+	// GetPenempatanBySantri lists a santri's placements, newest first
+	GetPenempatanBySantri(tenantID, santriID string) ([]model.Penempatan, error)
+	// GetPenempatanAktif returns nil when the santri has no room
+	GetPenempatanAktif(tenantID, santriID string) (*model.Penempatan, error)
 	CreatePenempatan(penempatan *model.Penempatan) error
+	TutupPenempatan(penempatan *model.Penempatan) error
 
 	// Kepesantrenan
 	GetPelanggaranAturan(tenantID string) ([]model.PelanggaranAturan, error)
 	CreatePelanggaranAturan(m *model.PelanggaranAturan) error
 	GetPelanggaranSiswa(tenantID string) ([]model.PelanggaranSiswa, error)
 	CreatePelanggaranSiswa(m *model.PelanggaranSiswa) error
+	// GetPelanggaranSantri lists a santri's violations dated within [from, to]
+	GetPelanggaranSantri(tenantID, santriID string, from, to time.Time) ([]model.PelanggaranSiswa, error)
 	GetPerizinan(tenantID string) ([]model.Perizinan, error)
 	CreatePerizinan(m *model.Perizinan) error
+	// GetPerizinanByID returns nil when the leave does not belong to the tenant
+	GetPerizinanByID(tenantID, id string) (*model.Perizinan, error)
+	// GetPerizinanByKode finds the leave of a scanned gate pass, nil when none matches
+	GetPerizinanByKode(tenantID, kode string) (*model.Perizinan, error)
+	// GetPerizinanTerlambat lists santri still out after Sampai, of every tenant when tenantID is empty
+	GetPerizinanTerlambat(tenantID string, now time.Time) ([]model.Perizinan, error)
+	// UpdatePerizinan saves the workflow fields of a leave still in statusLama; false when another
+	// request moved it on first
+	UpdatePerizinan(m *model.Perizinan, statusLama string) (bool, error)
+
+	// Poin pelanggaran & sanksi
+	GetSanksiAmbang(tenantID string) ([]model.SanksiAmbang, error)
+	// ReplaceSanksiAmbang swaps the tenant's whole threshold ladder
+	ReplaceSanksiAmbang(tenantID string, list []model.SanksiAmbang) error
+	// GetPoinKredit lists credits dated within [from, to], of every santri when santriID is empty
+	GetPoinKredit(tenantID, santriID string, from, to time.Time) ([]model.PoinKredit, error)
+	CreatePoinKredit(m *model.PoinKredit) error
+	// GetRekapPoin sums the violation and credit points of each santri within [from, to]
+	GetRekapPoin(tenantID string, from, to time.Time) ([]model.RekapPoin, error)
+	// GetSanksi lists sanctions; empty filters match everything
+	GetSanksi(tenantID, santriID, semesterID, status string) ([]model.Sanksi, error)
+	// GetSanksiByID returns nil when the sanction does not belong to the tenant
+	GetSanksiByID(tenantID, id string) (*model.Sanksi, error)
+	// CreateSanksi is a no-op returning false when the santri already has a sanction for the
+	// threshold in the semester
+	CreateSanksi(m *model.Sanksi) (bool, error)
+	UpdateSanksi(m *model.Sanksi) error
+	// NextNomorSanksi atomically reserves the next warning letter number of a calendar year
+	NextNomorSanksi(tenantID string, year int) (int, error)
 
 	// Tahfidz
 	GetTahfidzSetoran(tenantID string) ([]model.TahfidzSetoran, error)
 	CreateTahfidzSetoran(m *model.TahfidzSetoran) error
+	// GetTahfidzZiyadah lists the ziyadah setoran oldest first, of one santri or of every santri
+	// when santriID is empty
+	GetTahfidzZiyadah(tenantID, santriID string) ([]model.TahfidzSetoran, error)
+
+	// Halaqah
+	GetHalaqahByTenant(tenantID string) ([]model.Halaqah, error)
+	// GetHalaqahByID returns nil when the halaqah does not belong to the tenant. Inside a
+	// transaction it locks the halaqah row.
+	GetHalaqahByID(tenantID, id string) (*model.Halaqah, error)
+	CreateHalaqah(h *model.Halaqah) error
+	UpdateHalaqah(h *model.Halaqah) error
+	DeleteHalaqah(tenantID, id string) error
+	// GetHalaqahAnggota lists the santri of one halaqah, or of every active halaqah when
+	// halaqahID is empty. An empty tenantID spans all tenants.
+	GetHalaqahAnggota(tenantID, halaqahID string) ([]model.HalaqahAnggota, error)
+	// AddHalaqahAnggota moves the santri out of any other halaqah of the tenant
+	AddHalaqahAnggota(tenantID, halaqahID, santriID string) error
+	RemoveHalaqahAnggota(tenantID, halaqahID, santriID string) error
+
+	// Target hafalan
+	GetTahfidzTargets(tenantID, semesterID string) ([]model.TargetHafalan, error)
+	// GetTahfidzTargetAktif lists the targets running on the given day; an empty tenantID spans
+	// all tenants
+	GetTahfidzTargetAktif(tenantID string, on time.Time) ([]model.TargetHafalan, error)
+	// SaveTahfidzTarget replaces the santri's target of the same semester
+	SaveTahfidzTarget(t *model.TargetHafalan) error
+	DeleteTahfidzTarget(tenantID, id string) error
+
+	// Tahfidz alerts
+	// GetTahfidzSetoranTerakhir maps each santri to the day of their latest setoran of any kind
+	GetTahfidzSetoranTerakhir(tenantID string) (map[string]time.Time, error)
+	// GetTahfidzAlertTerkirim keys the alerts sent since the given time by "santri_id|jenis"
+	GetTahfidzAlertTerkirim(tenantID string, since time.Time) (map[string]bool, error)
+	SaveTahfidzAlert(alert *model.TahfidzAlert) error
 
 	// Diniyah
 	GetDiniyahKitab(tenantID string) ([]model.DiniyahKitab, error)
@@ -0,0 +1,44 @@
+package outbound_port
+
+import (
+	"context"
+	"time"
+
+	"prabogo/internal/model"
+)
+
+// UjianDatabasePort defines the interface for exam scheduling database operations
+type UjianDatabasePort interface {
+	// Ujian
+	CreateUjian(ctx context.Context, u *model.Ujian) error
+	UpdateUjian(ctx context.Context, u *model.Ujian) error
+	GetUjianByID(ctx context.Context, tenantID, id string) (*model.Ujian, error)
+	GetUjianList(ctx context.Context, tenantID, semesterID string) ([]model.Ujian, error)
+
+	// Jadwal
+	CreateJadwal(ctx context.Context, j *model.UjianJadwal) error
+	DeleteJadwal(ctx context.Context, ujianID, id string) error
+	GetJadwalByID(ctx context.Context, ujianID, id string) (*model.UjianJadwal, error)
+	GetJadwalByUjian(ctx context.Context, ujianID string) ([]model.UjianJadwal, error)
+
+	// Ruang
+	CreateRuang(ctx context.Context, r *model.UjianRuang) error
+	DeleteRuang(ctx context.Context, ujianID, id string) error
+	GetRuangByID(ctx context.Context, ujianID, id string) (*model.UjianRuang, error)
+	GetRuangByUjian(ctx context.Context, ujianID string) ([]model.UjianRuang, error)
+
+	// Peserta & tempat duduk
+	GetSiswaByTingkat(ctx context.Context, tenantID string, tingkat []string) ([]model.UjianSiswa, error)
+	ReplacePeserta(ctx context.Context, ujianID string, peserta []model.UjianPeserta) error
+	GetPeserta(ctx context.Context, ujianID, ruangID, kelasID string) ([]model.UjianPeserta, error)
+	GetSiswaBelumLunasSPP(ctx context.Context, tenantID string, siswaIDs []string) (map[string]bool, error)
+
+	// Pengawas
+	CreatePengawas(ctx context.Context, p *model.UjianPengawas) error
+	DeletePengawas(ctx context.Context, ujianID, jadwalID, id string) error
+	GetPengawasByJadwal(ctx context.Context, ujianID, jadwalID string) ([]model.UjianPengawas, error)
+	// GuruExists reports whether the guru belongs to the tenant
+	GuruExists(ctx context.Context, tenantID, guruID string) (bool, error)
+	// GetBentrokPengawas returns the sessions a guru already proctors that overlap the given window
+	GetBentrokPengawas(ctx context.Context, tenantID, guruID string, mulai, selesai time.Time) ([]model.UjianJadwal, error)
+}
@@ -43,6 +43,21 @@ func (s *Scheduler) Start() {
 		s.checkSubscriptionReminders()
 	})
 
+	// Send library overdue notices every day at 07:00 WIB (00:00 UTC)
+	s.cron.AddFunc("0 0 0 * * *", func() {
+		s.sendPerpustakaanOverdueNotices()
+	})
+
+	// Send tahfidz alerts to ustadz and wali every day at 13:00 WIB (06:00 UTC)
+	s.cron.AddFunc("0 0 6 * * *", func() {
+		s.sendTahfidzAlerts()
+	})
+
+	// Tell wali santri every hour about santri who are not back when their leave ended
+	s.cron.AddFunc("0 0 * * * *", func() {
+		s.sendPerizinanTerlambat()
+	})
+
 	// Also run at startup for testing (delayed by 10 seconds)
 	go func() {
 		time.Sleep(10 * time.Second)
@@ -154,3 +169,40 @@ func (s *Scheduler) sendReminderNotification(sub model.Subscription, daysLeft in
 
 	// TODO: Send WhatsApp to tenant admin
 }
+
+// sendPerpustakaanOverdueNotices sends WhatsApp reminders for overdue library loans of all tenants
+func (s *Scheduler) sendPerpustakaanOverdueNotices() {
+	ctx := s.ctx
+
+	sent, err := s.domain.Perpustakaan().SendOverdueNotices(ctx, "")
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("Failed to send library overdue notices")
+		return
+	}
+
+	log.WithContext(ctx).WithField("count", sent).Info("Library overdue notices sent")
+}
+
+func (s *Scheduler) sendTahfidzAlerts() {
+	ctx := s.ctx
+
+	sent, err := s.domain.Sekolah().KirimTahfidzAlerts(ctx, "")
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("Failed to send tahfidz alerts")
+		return
+	}
+
+	log.WithContext(ctx).WithField("count", sent).Info("Tahfidz alerts sent")
+}
+
+func (s *Scheduler) sendPerizinanTerlambat() {
+	ctx := s.ctx
+
+	sent, err := s.domain.Sekolah().KirimPerizinanTerlambat(ctx, "")
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("Failed to send overdue perizinan notices")
+		return
+	}
+
+	log.WithContext(ctx).WithField("count", sent).Info("Overdue perizinan notices sent")
+}
@@ -246,6 +246,104 @@ func (mr *MockDatabasePortMockRecorder) PesantrenDashboard() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PesantrenDashboard", reflect.TypeOf((*MockDatabasePort)(nil).PesantrenDashboard))
 }
 
+// Ekskul mocks base method.
+func (m *MockDatabasePort) Ekskul() outbound_port.EkskulDatabasePort {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ekskul")
+	ret0, _ := ret[0].(outbound_port.EkskulDatabasePort)
+	return ret0
+}
+
+// Ekskul indicates an expected call of Ekskul.
+func (mr *MockDatabasePortMockRecorder) Ekskul() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ekskul", reflect.TypeOf((*MockDatabasePort)(nil).Ekskul))
+}
+
+// Konseling mocks base method.
+func (m *MockDatabasePort) Konseling() outbound_port.KonselingDatabasePort {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Konseling")
+	ret0, _ := ret[0].(outbound_port.KonselingDatabasePort)
+	return ret0
+}
+
+// Konseling indicates an expected call of Konseling.
+func (mr *MockDatabasePortMockRecorder) Konseling() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Konseling", reflect.TypeOf((*MockDatabasePort)(nil).Konseling))
+}
+
+// Perpustakaan mocks base method.
+func (m *MockDatabasePort) Perpustakaan() outbound_port.PerpustakaanDatabasePort {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Perpustakaan")
+	ret0, _ := ret[0].(outbound_port.PerpustakaanDatabasePort)
+	return ret0
+}
+
+// Perpustakaan indicates an expected call of Perpustakaan.
+func (mr *MockDatabasePortMockRecorder) Perpustakaan() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Perpustakaan", reflect.TypeOf((*MockDatabasePort)(nil).Perpustakaan))
+}
+
+// PPDB mocks base method.
+func (m *MockDatabasePort) PPDB() outbound_port.PPDBDatabasePort {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PPDB")
+	ret0, _ := ret[0].(outbound_port.PPDBDatabasePort)
+	return ret0
+}
+
+// PPDB indicates an expected call of PPDB.
+func (mr *MockDatabasePortMockRecorder) PPDB() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PPDB", reflect.TypeOf((*MockDatabasePort)(nil).PPDB))
+}
+
+// Ujian mocks base method.
+func (m *MockDatabasePort) Ujian() outbound_port.UjianDatabasePort {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ujian")
+	ret0, _ := ret[0].(outbound_port.UjianDatabasePort)
+	return ret0
+}
+
+// Ujian indicates an expected call of Ujian.
+func (mr *MockDatabasePortMockRecorder) Ujian() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ujian", reflect.TypeOf((*MockDatabasePort)(nil).Ujian))
+}
+
+// Dapodik mocks base method.
+func (m *MockDatabasePort) Dapodik() outbound_port.DapodikDatabasePort {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dapodik")
+	ret0, _ := ret[0].(outbound_port.DapodikDatabasePort)
+	return ret0
+}
+
+// Dapodik indicates an expected call of Dapodik.
+func (mr *MockDatabasePortMockRecorder) Dapodik() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dapodik", reflect.TypeOf((*MockDatabasePort)(nil).Dapodik))
+}
+
+// Alumni mocks base method.
+func (m *MockDatabasePort) Alumni() outbound_port.AlumniDatabasePort {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Alumni")
+	ret0, _ := ret[0].(outbound_port.AlumniDatabasePort)
+	return ret0
+}
+
+// Alumni indicates an expected call of Alumni.
+func (mr *MockDatabasePortMockRecorder) Alumni() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Alumni", reflect.TypeOf((*MockDatabasePort)(nil).Alumni))
+}
+
 // MockDatabaseExecutor is a mock of DatabaseExecutor interface.
 type MockDatabaseExecutor struct {
 	ctrl     *gomock.Controller
@@ -61,3 +61,17 @@ func (mr *MockMessagePortMockRecorder) WhatsApp() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WhatsApp", reflect.TypeOf((*MockMessagePort)(nil).WhatsApp))
 }
+
+// Rapor mocks base method.
+func (m *MockMessagePort) Rapor() outbound_port.RaporMessagePort {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rapor")
+	ret0, _ := ret[0].(outbound_port.RaporMessagePort)
+	return ret0
+}
+
+// Rapor indicates an expected call of Rapor.
+func (mr *MockMessagePortMockRecorder) Rapor() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rapor", reflect.TypeOf((*MockMessagePort)(nil).Rapor))
+}
@@ -0,0 +1,46 @@
+package crypto_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/utils/crypto"
+)
+
+func TestCipher(t *testing.T) {
+	Convey("Test Cipher", t, func() {
+		c, err := crypto.NewCipher("test-secret")
+		So(err, ShouldBeNil)
+
+		Convey("Round trip", func() {
+			enc, err := c.Encrypt("catatan rahasia")
+			So(err, ShouldBeNil)
+			So(enc, ShouldNotContainSubstring, "catatan")
+			So(strings.HasPrefix(enc, "enc:v1:"), ShouldBeTrue)
+
+			dec, err := c.Decrypt(enc)
+			So(err, ShouldBeNil)
+			So(dec, ShouldEqual, "catatan rahasia")
+		})
+
+		Convey("Empty value stays empty", func() {
+			enc, err := c.Encrypt("")
+			So(err, ShouldBeNil)
+			So(enc, ShouldEqual, "")
+		})
+
+		Convey("Wrong key fails", func() {
+			enc, _ := c.Encrypt("catatan rahasia")
+			other, _ := crypto.NewCipher("other-secret")
+			_, err := other.Decrypt(enc)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Empty key rejected", func() {
+			_, err := crypto.NewCipher("")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
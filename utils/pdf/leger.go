@@ -0,0 +1,149 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"prabogo/internal/model"
+
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	legerMarginX   = 10.0
+	legerPageWidth = 277.0 // A4 landscape minus margins
+)
+
+// GenerateLegerPDF renders the leger nilai of a class on A4 landscape. Subject columns share the
+// width left by the fixed columns; note is printed under the table when not empty.
+func GenerateLegerPDF(leger *model.Leger, note string) ([]byte, error) {
+	pdf := fpdf.New("L", "mm", "A4", "")
+	pdf.SetMargins(legerMarginX, 10, legerMarginX)
+	pdf.SetAutoPageBreak(false, 10)
+	w := &raporWriter{pdf: pdf, tr: pdf.UnicodeTranslatorFromDescriptor("")}
+	pdf.AddPage()
+
+	pdf.SetFont("Arial", "B", 13)
+	pdf.CellFormat(0, 7, "LEGER NILAI", "", 1, "C", false, 0, "")
+	pdf.SetFont("Arial", "", 10)
+	pdf.CellFormat(0, 5, w.tr(strings.ToUpper(leger.NamaSekolah)), "", 1, "C", false, 0, "")
+	pdf.Ln(2)
+	pdf.CellFormat(25, 5, "Kelas", "", 0, "L", false, 0, "")
+	pdf.CellFormat(100, 5, w.tr(": "+leger.NamaKelas), "", 0, "L", false, 0, "")
+	pdf.CellFormat(25, 5, "Wali Kelas", "", 0, "L", false, 0, "")
+	pdf.CellFormat(0, 5, w.tr(": "+leger.WaliKelas), "", 1, "L", false, 0, "")
+	pdf.CellFormat(25, 5, "Semester", "", 0, "L", false, 0, "")
+	pdf.CellFormat(0, 5, ": "+leger.Semester, "", 1, "L", false, 0, "")
+	pdf.Ln(3)
+
+	labels := []string{"No", "NIS", "Nama"}
+	widths := []float64{8, 20, 45}
+	aligns := "CLL"
+	fixed := []float64{16, 14, 13, 8, 8, 8}
+	rest := legerPageWidth
+	for _, width := range append(append([]float64{}, widths...), fixed...) {
+		rest -= width
+	}
+	subjectW := rest
+	if n := len(leger.Subjects); n > 0 {
+		subjectW = rest / float64(n)
+	}
+	for _, subject := range leger.Subjects {
+		label := subject.Name
+		if subject.Code != "" && subjectW < 14 {
+			label = subject.Code
+		}
+		if subject.Excluded {
+			label += " *"
+		}
+		labels = append(labels, label)
+		widths = append(widths, subjectW)
+		aligns += "C"
+	}
+	labels = append(labels, "Jumlah", "Rata-rata", "Rank", "S", "I", "A")
+	widths = append(widths, fixed...)
+	aligns += "CCCCCC"
+
+	fontSize := 8.0
+	if subjectW < 12 {
+		fontSize = 7
+	}
+	drawHeader := func() {
+		pdf.SetFont("Arial", "B", fontSize)
+		pdf.SetFillColor(230, 230, 230)
+		x, y := pdf.GetXY()
+		const height = 12.0
+		for i, l := range labels {
+			pdf.Rect(x, y, widths[i], height, "FD")
+			lines := pdf.SplitText(w.tr(l), widths[i]-1)
+			if len(lines) > 3 {
+				lines = lines[:3]
+			}
+			pdf.SetXY(x, y+(height-float64(len(lines))*3.5)/2)
+			for _, line := range lines {
+				pdf.CellFormat(widths[i], 3.5, line, "", 2, "C", false, 0, "")
+			}
+			x += widths[i]
+		}
+		pdf.SetXY(legerMarginX, y+height)
+		pdf.SetFont("Arial", "", fontSize)
+	}
+	drawRow := func(cells []string, bold bool) {
+		_, pageH := pdf.GetPageSize()
+		if pdf.GetY()+6 > pageH-10 {
+			pdf.AddPage()
+			drawHeader()
+		}
+		if bold {
+			pdf.SetFont("Arial", "B", fontSize)
+		}
+		for i, c := range cells {
+			text := w.tr(c)
+			if lines := pdf.SplitText(text, widths[i]-1); len(lines) > 0 {
+				text = lines[0]
+			}
+			pdf.CellFormat(widths[i], 6, text, "1", 0, string(aligns[i]), false, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont("Arial", "", fontSize)
+	}
+
+	drawHeader()
+	for i, row := range leger.Rows {
+		cells := []string{fmt.Sprintf("%d", i+1), row.NIS, row.Nama}
+		for _, subject := range leger.Subjects {
+			if score, ok := row.Scores[subject.ID]; ok {
+				cells = append(cells, fmt.Sprintf("%.0f", score))
+			} else {
+				cells = append(cells, "-")
+			}
+		}
+		rank := "-"
+		if row.Rank > 0 {
+			rank = fmt.Sprintf("%d", row.Rank)
+		}
+		cells = append(cells, fmt.Sprintf("%.0f", row.Total), fmt.Sprintf("%.2f", row.Average), rank,
+			fmt.Sprintf("%d", row.Kehadiran.Sakit), fmt.Sprintf("%d", row.Kehadiran.Izin), fmt.Sprintf("%d", row.Kehadiran.Alpha))
+		drawRow(cells, false)
+	}
+
+	averages := []string{"", "", "Rata-rata kelas"}
+	for _, subject := range leger.Subjects {
+		averages = append(averages, fmt.Sprintf("%.2f", subject.Average))
+	}
+	averages = append(averages, "", fmt.Sprintf("%.2f", leger.ClassAverage), "", "", "", "")
+	drawRow(averages, true)
+
+	if note != "" {
+		pdf.Ln(2)
+		pdf.SetFont("Arial", "I", 8)
+		pdf.MultiCell(legerPageWidth, 4, w.tr(note), "", "L", false)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,535 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"prabogo/internal/model"
+
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	raporMarginX   = 15.0
+	raporLineH     = 5.0
+	raporPageWidth = 180.0 // A4 minus margins
+)
+
+var bulanIndonesia = [...]string{
+	"Januari", "Februari", "Maret", "April", "Mei", "Juni",
+	"Juli", "Agustus", "September", "Oktober", "November", "Desember",
+}
+
+// raporWriter wraps fpdf with the table helpers shared by all rapor layouts
+type raporWriter struct {
+	pdf *fpdf.Fpdf
+	tr  func(string) string
+}
+
+// GenerateRaporPDF renders a rapor from its frozen snapshot. The grade section follows the
+// kurikulum recorded in the header: K13 (pengetahuan/keterampilan with KKM), Kurikulum Merdeka
+// (nilai akhir and capaian kompetensi) or pesantren (diniyah and tahfidz). logo may be nil.
+func GenerateRaporPDF(rapor *model.Rapor, logo []byte) ([]byte, error) {
+	return GenerateRaporBundlePDF([]*model.Rapor{rapor}, logo)
+}
+
+// GenerateRaporBundlePDF renders several rapor into one document, each starting on a new page
+func GenerateRaporBundlePDF(rapors []*model.Rapor, logo []byte) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(raporMarginX, 12, raporMarginX)
+	pdf.SetAutoPageBreak(true, 15)
+	w := &raporWriter{pdf: pdf, tr: pdf.UnicodeTranslatorFromDescriptor("")}
+
+	for _, rapor := range rapors {
+		w.rapor(rapor, logo)
+	}
+	if len(rapors) == 0 {
+		pdf.AddPage()
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (w *raporWriter) rapor(rapor *model.Rapor, logo []byte) {
+	h := model.RaporHeader{Kurikulum: model.KurikulumK13, NamaSiswa: rapor.NamaSantri, Semester: rapor.NamaPeriode}
+	if rapor.Header != nil {
+		h = *rapor.Header
+	}
+
+	w.pdf.AddPage()
+	w.kop(h, logo, raporTitle(h))
+	w.identitas(h)
+
+	groups := map[string][]model.RaporNilai{}
+	for _, n := range rapor.NilaiList {
+		groups[n.Kategori] = append(groups[n.Kategori], n)
+	}
+
+	section := 'A'
+	next := func(title string) {
+		w.sectionTitle(fmt.Sprintf("%c. %s", section, title))
+		section++
+	}
+
+	switch h.Kurikulum {
+	case model.KurikulumMerdeka:
+		next("NILAI AKADEMIK")
+		w.nilaiMerdeka(groups[model.RaporKategoriAkademik])
+	case model.KurikulumPesantren:
+		if len(groups[model.RaporKategoriAkademik]) > 0 {
+			next("NILAI AKADEMIK")
+			w.nilaiPesantren("Mata Pelajaran", groups[model.RaporKategoriAkademik])
+		}
+		next("NILAI DINIYAH")
+		w.nilaiPesantren("Kitab", groups[model.RaporKategoriDiniyah])
+		next("NILAI TAHFIDZ")
+		w.nilaiPesantren("Hafalan", groups[model.RaporKategoriTahfidz])
+	default:
+		next("SIKAP")
+		w.sikap(groups[model.RaporKategoriSikap])
+		next("PENGETAHUAN DAN KETERAMPILAN")
+		w.nilaiK13(groups[model.RaporKategoriAkademik])
+		next("DESKRIPSI")
+		w.deskripsiK13(groups[model.RaporKategoriAkademik])
+	}
+
+	next("EKSTRAKURIKULER")
+	w.ekskul(groups[model.RaporKategoriEkstrakurikuler])
+
+	next("KETIDAKHADIRAN")
+	w.kehadiran(h.Kehadiran)
+
+	next("CATATAN WALI KELAS")
+	w.catatan(rapor.CatatanWaliKelas)
+
+	w.tandaTangan(h)
+
+	// The P5 report is printed right behind the academic rapor
+	if len(rapor.P5) > 0 {
+		w.p5(h, rapor.P5, logo)
+	}
+}
+
+// GenerateRaporP5PDF renders only the P5 report of a Kurikulum Merdeka rapor
+func GenerateRaporP5PDF(rapor *model.Rapor, logo []byte) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(raporMarginX, 12, raporMarginX)
+	pdf.SetAutoPageBreak(true, 15)
+	w := &raporWriter{pdf: pdf, tr: pdf.UnicodeTranslatorFromDescriptor("")}
+
+	h := model.RaporHeader{Kurikulum: model.KurikulumMerdeka, NamaSiswa: rapor.NamaSantri, Semester: rapor.NamaPeriode}
+	if rapor.Header != nil {
+		h = *rapor.Header
+	}
+	w.p5(h, rapor.P5, logo)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// p5 prints the Projek Penguatan Profil Pelajar Pancasila report: one block per project with
+// the level reached on each targeted sub-elemen, followed by the level legend
+func (w *raporWriter) p5(h model.RaporHeader, projects []model.RaporP5, logo []byte) {
+	pdf := w.pdf
+	pdf.AddPage()
+	w.kop(h, logo, "RAPOR PROJEK PENGUATAN PROFIL PELAJAR PANCASILA")
+	w.identitas(h)
+
+	widths := []float64{10, 110, 15, 15, 15, 15}
+	for i, projek := range projects {
+		w.sectionTitle(fmt.Sprintf("Projek %d: %s", i+1, projek.Judul))
+		pdf.SetFont("Arial", "I", 9)
+		pdf.CellFormat(0, raporLineH, w.tr("Tema: "+projek.Tema), "", 1, "L", false, 0, "")
+		if projek.Fasilitator != "" {
+			pdf.CellFormat(0, raporLineH, w.tr("Fasilitator: "+projek.Fasilitator), "", 1, "L", false, 0, "")
+		}
+		pdf.SetFont("Arial", "", 9)
+		if strings.TrimSpace(projek.Deskripsi) != "" {
+			pdf.MultiCell(raporPageWidth, raporLineH, w.tr(projek.Deskripsi), "", "L", false)
+		}
+		pdf.Ln(2)
+
+		w.header(widths, "No", "Dimensi / Sub-elemen", model.P5LevelMB, model.P5LevelSB, model.P5LevelBSH, model.P5LevelSAB)
+		if len(projek.Capaian) == 0 {
+			w.emptyRow("Belum ada penilaian")
+		}
+		for j, c := range projek.Capaian {
+			cells := []string{fmt.Sprintf("%d", j+1), c.Dimensi + "\n" + c.SubElemen}
+			for _, level := range model.P5Levels {
+				mark := ""
+				if c.Level == level {
+					mark = "V"
+				}
+				cells = append(cells, mark)
+			}
+			w.row(widths, "CLCCCC", cells...)
+		}
+
+		w.ensureSpace(14)
+		pdf.Ln(1)
+		pdf.SetFont("Arial", "B", 9)
+		pdf.CellFormat(0, raporLineH, "Catatan Proses", "", 1, "L", false, 0, "")
+		w.catatan(projek.Catatan)
+	}
+
+	w.ensureSpace(30)
+	pdf.SetFont("Arial", "B", 9)
+	pdf.CellFormat(0, raporLineH, "Keterangan Tingkat Pencapaian", "", 1, "L", false, 0, "")
+	pdf.SetFont("Arial", "", 9)
+	for _, level := range model.P5Levels {
+		pdf.CellFormat(15, raporLineH, level, "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, raporLineH, ": "+model.P5LevelLabel[level], "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(2)
+
+	w.tandaTangan(h)
+}
+
+func raporTitle(h model.RaporHeader) string {
+	if h.Kurikulum == model.KurikulumPesantren {
+		return "LAPORAN HASIL BELAJAR SANTRI"
+	}
+	return "LAPORAN HASIL BELAJAR PESERTA DIDIK"
+}
+
+// kop draws the school letterhead with the optional logo on the left
+func (w *raporWriter) kop(h model.RaporHeader, logo []byte, title string) {
+	pdf := w.pdf
+	top := pdf.GetY()
+
+	if imageType := logoImageType(logo); imageType != "" {
+		opts := fpdf.ImageOptions{ImageType: imageType}
+		pdf.RegisterImageOptionsReader("logo", opts, bytes.NewReader(logo))
+		if pdf.Ok() {
+			pdf.ImageOptions("logo", raporMarginX, top, 20, 20, false, opts, 0, "")
+		} else {
+			// A corrupt logo must not fail the whole rapor
+			pdf.ClearError()
+		}
+	}
+
+	pdf.SetXY(raporMarginX+22, top+2)
+	pdf.SetFont("Arial", "B", 14)
+	pdf.CellFormat(raporPageWidth-44, 7, w.tr(strings.ToUpper(h.NamaSekolah)), "", 2, "C", false, 0, "")
+	pdf.SetFont("Arial", "", 9)
+	pdf.MultiCell(raporPageWidth-44, 4.5, w.tr(h.AlamatSekolah), "", "C", false)
+
+	y := top + 23
+	if pdf.GetY() > y {
+		y = pdf.GetY() + 1
+	}
+	pdf.SetLineWidth(0.6)
+	pdf.Line(raporMarginX, y, raporMarginX+raporPageWidth, y)
+	pdf.SetLineWidth(0.2)
+	pdf.SetXY(raporMarginX, y+4)
+
+	pdf.SetFont("Arial", "B", 12)
+	pdf.CellFormat(0, 7, title, "", 1, "C", false, 0, "")
+	pdf.Ln(2)
+}
+
+// identitas prints the student identity block in two columns
+func (w *raporWriter) identitas(h model.RaporHeader) {
+	pdf := w.pdf
+	pdf.SetFont("Arial", "", 10)
+
+	nama := "Nama Peserta Didik"
+	if h.Kurikulum == model.KurikulumPesantren {
+		nama = "Nama Santri"
+	}
+	left := [][2]string{{nama, h.NamaSiswa}, {"NIS / NISN", strings.Trim(h.NIS+" / "+h.NISN, " /")}}
+	right := [][2]string{{"Kelas", h.Kelas}, {"Semester", h.Semester}}
+
+	for i := range left {
+		pdf.CellFormat(35, 6, left[i][0], "", 0, "L", false, 0, "")
+		pdf.CellFormat(4, 6, ":", "", 0, "L", false, 0, "")
+		pdf.CellFormat(66, 6, w.tr(left[i][1]), "", 0, "L", false, 0, "")
+		pdf.CellFormat(22, 6, right[i][0], "", 0, "L", false, 0, "")
+		pdf.CellFormat(4, 6, ":", "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 6, w.tr(right[i][1]), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(3)
+}
+
+func (w *raporWriter) sectionTitle(title string) {
+	w.ensureSpace(20)
+	w.pdf.SetFont("Arial", "B", 10)
+	w.pdf.CellFormat(0, 7, title, "", 1, "L", false, 0, "")
+}
+
+// sikap prints the K13 spiritual and sosial predicates with their description
+func (w *raporWriter) sikap(list []model.RaporNilai) {
+	widths := []float64{40, 30, 110}
+	w.header(widths, "Aspek", "Predikat", "Deskripsi")
+	if len(list) == 0 {
+		w.emptyRow("Belum ada penilaian sikap")
+		return
+	}
+	for _, n := range list {
+		w.row(widths, "LCL", n.Jenis, n.Nilai, n.Keterangan)
+	}
+	w.pdf.Ln(3)
+}
+
+// nilaiK13 prints the K13 grade table with KKM and a predicate for each aspect
+func (w *raporWriter) nilaiK13(list []model.RaporNilai) {
+	pdf := w.pdf
+	widths := []float64{10, 82, 16, 18, 18, 18, 18}
+
+	pdf.SetFont("Arial", "B", 9)
+	pdf.SetFillColor(230, 230, 230)
+	x, y := pdf.GetXY()
+	pdf.CellFormat(widths[0], 12, "No", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(widths[1], 12, "Mata Pelajaran", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(widths[2], 12, "KKM", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(widths[3]+widths[4], 6, "Pengetahuan", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(widths[5]+widths[6], 6, "Keterampilan", "1", 0, "C", true, 0, "")
+	pdf.SetXY(x+widths[0]+widths[1]+widths[2], y+6)
+	for _, label := range []string{"Nilai", "Predikat", "Nilai", "Predikat"} {
+		pdf.CellFormat(18, 6, label, "1", 0, "C", true, 0, "")
+	}
+	pdf.SetXY(x, y+12)
+
+	pdf.SetFont("Arial", "", 9)
+	if len(list) == 0 {
+		w.emptyRow("Belum ada nilai")
+		return
+	}
+	for i, n := range list {
+		d := detailOf(n)
+		w.row(widths, "CLCCCCC", fmt.Sprintf("%d", i+1), n.Jenis, formatKKM(d.KKM),
+			formatNilai(d.NilaiAngka), d.Predikat, formatNilai(d.NilaiKeterampilan), d.PredikatKeterampilan)
+	}
+	pdf.Ln(3)
+}
+
+// deskripsiK13 prints the K13 narrative for both aspects
+func (w *raporWriter) deskripsiK13(list []model.RaporNilai) {
+	widths := []float64{10, 50, 60, 60}
+	w.header(widths, "No", "Mata Pelajaran", "Pengetahuan", "Keterampilan")
+	if len(list) == 0 {
+		w.emptyRow("Belum ada nilai")
+		return
+	}
+	for i, n := range list {
+		d := detailOf(n)
+		w.row(widths, "CLLL", fmt.Sprintf("%d", i+1), n.Jenis, d.Deskripsi, d.DeskripsiKeterampilan)
+	}
+	w.pdf.Ln(3)
+}
+
+// nilaiMerdeka prints the Kurikulum Merdeka table: nilai akhir and capaian kompetensi
+func (w *raporWriter) nilaiMerdeka(list []model.RaporNilai) {
+	widths := []float64{10, 50, 20, 100}
+	w.header(widths, "No", "Mata Pelajaran", "Nilai Akhir", "Capaian Kompetensi")
+	if len(list) == 0 {
+		w.emptyRow("Belum ada nilai")
+		return
+	}
+	for i, n := range list {
+		d := detailOf(n)
+		w.row(widths, "CLCL", fmt.Sprintf("%d", i+1), n.Jenis, formatNilai(d.NilaiAngka), d.Deskripsi)
+	}
+	w.pdf.Ln(3)
+}
+
+// nilaiPesantren prints a kitab or tahfidz table with the qualitative predikat
+func (w *raporWriter) nilaiPesantren(label string, list []model.RaporNilai) {
+	widths := []float64{10, 60, 18, 22, 70}
+	w.header(widths, "No", label, "Nilai", "Predikat", "Keterangan")
+	if len(list) == 0 {
+		w.emptyRow("Belum ada nilai")
+		return
+	}
+	for i, n := range list {
+		d := detailOf(n)
+		w.row(widths, "CLCCL", fmt.Sprintf("%d", i+1), n.Jenis, formatNilai(d.NilaiAngka), d.Predikat, d.Deskripsi)
+	}
+	w.pdf.Ln(3)
+}
+
+func (w *raporWriter) ekskul(list []model.RaporNilai) {
+	widths := []float64{10, 60, 22, 88}
+	w.header(widths, "No", "Kegiatan Ekstrakurikuler", "Predikat", "Keterangan")
+	if len(list) == 0 {
+		w.emptyRow("Tidak mengikuti kegiatan ekstrakurikuler")
+		return
+	}
+	for i, n := range list {
+		w.row(widths, "CLCL", fmt.Sprintf("%d", i+1), n.Jenis, n.Nilai, n.Keterangan)
+	}
+	w.pdf.Ln(3)
+}
+
+func (w *raporWriter) kehadiran(k model.AttendanceData) {
+	widths := []float64{70, 30}
+	w.pdf.SetFont("Arial", "", 9)
+	w.row(widths, "LC", "Sakit", fmt.Sprintf("%d hari", k.Sakit))
+	w.row(widths, "LC", "Izin", fmt.Sprintf("%d hari", k.Izin))
+	w.row(widths, "LC", "Tanpa Keterangan", fmt.Sprintf("%d hari", k.Alpha))
+	w.pdf.Ln(3)
+}
+
+func (w *raporWriter) catatan(text string) {
+	if strings.TrimSpace(text) == "" {
+		text = "-"
+	}
+	w.pdf.SetFont("Arial", "", 9)
+	w.pdf.MultiCell(raporPageWidth, raporLineH, w.tr(text), "1", "L", false)
+	w.pdf.Ln(4)
+}
+
+// tandaTangan prints the orang tua, wali kelas and kepala sekolah signature blocks
+func (w *raporWriter) tandaTangan(h model.RaporHeader) {
+	pdf := w.pdf
+	w.ensureSpace(72)
+	pdf.SetFont("Arial", "", 10)
+
+	colW := 70.0
+	rightX := raporMarginX + raporPageWidth - colW
+	y := pdf.GetY()
+
+	tanggal := h.TanggalRapor
+	if tanggal.IsZero() {
+		tanggal = time.Now()
+	}
+
+	pdf.SetXY(raporMarginX, y+5)
+	pdf.CellFormat(colW, 5, "Mengetahui,", "", 2, "C", false, 0, "")
+	pdf.CellFormat(colW, 5, "Orang Tua/Wali", "", 0, "C", false, 0, "")
+	pdf.SetXY(rightX, y)
+	pdf.CellFormat(colW, 5, FormatTanggal(tanggal), "", 2, "C", false, 0, "")
+	pdf.CellFormat(colW, 5, "", "", 2, "C", false, 0, "")
+	pdf.CellFormat(colW, 5, "Wali Kelas", "", 0, "C", false, 0, "")
+
+	pdf.SetXY(raporMarginX, y+30)
+	pdf.CellFormat(colW, 5, "(.................................)", "", 0, "C", false, 0, "")
+	w.namaPenandatangan(rightX, y+30, colW, h.WaliKelas, h.NIPWaliKelas)
+
+	centerX := raporMarginX + (raporPageWidth-colW)/2
+	pdf.SetXY(centerX, y+42)
+	pdf.CellFormat(colW, 5, "Mengetahui,", "", 2, "C", false, 0, "")
+	pdf.CellFormat(colW, 5, "Kepala Sekolah", "", 0, "C", false, 0, "")
+	w.namaPenandatangan(centerX, y+64, colW, h.KepalaSekolah, "")
+}
+
+func (w *raporWriter) namaPenandatangan(x, y, width float64, nama, nip string) {
+	pdf := w.pdf
+	pdf.SetXY(x, y)
+	if nama == "" {
+		pdf.CellFormat(width, 5, "(.................................)", "", 0, "C", false, 0, "")
+		return
+	}
+	pdf.SetFont("Arial", "BU", 10)
+	pdf.CellFormat(width, 5, w.tr(nama), "", 2, "C", false, 0, "")
+	pdf.SetFont("Arial", "", 10)
+	if nip != "" {
+		pdf.CellFormat(width, 5, "NIP. "+nip, "", 0, "C", false, 0, "")
+	}
+}
+
+// header draws a shaded single-line table header
+func (w *raporWriter) header(widths []float64, labels ...string) {
+	w.ensureSpace(14)
+	w.pdf.SetFont("Arial", "B", 9)
+	w.pdf.SetFillColor(230, 230, 230)
+	for i, l := range labels {
+		ln := 0
+		if i == len(labels)-1 {
+			ln = 1
+		}
+		w.pdf.CellFormat(widths[i], 7, l, "1", ln, "C", true, 0, "")
+	}
+	w.pdf.SetFont("Arial", "", 9)
+}
+
+// row draws one table row whose height grows with the longest wrapped cell
+func (w *raporWriter) row(widths []float64, aligns string, cells ...string) {
+	pdf := w.pdf
+	lines := 1
+	for i, c := range cells {
+		if n := len(pdf.SplitText(w.tr(c), widths[i]-2)); n > lines {
+			lines = n
+		}
+	}
+	height := float64(lines)*raporLineH + 1
+	w.ensureSpace(height)
+
+	x, y := pdf.GetXY()
+	for i, c := range cells {
+		pdf.Rect(x, y, widths[i], height, "D")
+		pdf.SetXY(x, y+0.5)
+		pdf.MultiCell(widths[i], raporLineH, w.tr(c), "", string(aligns[i]), false)
+		x += widths[i]
+	}
+	pdf.SetXY(raporMarginX, y+height)
+}
+
+func (w *raporWriter) emptyRow(text string) {
+	w.pdf.SetFont("Arial", "I", 9)
+	w.pdf.CellFormat(raporPageWidth, 7, text, "1", 1, "C", false, 0, "")
+	w.pdf.SetFont("Arial", "", 9)
+	w.pdf.Ln(3)
+}
+
+// ensureSpace starts a new page when the next block would not fit
+func (w *raporWriter) ensureSpace(height float64) {
+	_, pageH := w.pdf.GetPageSize()
+	_, _, _, bottom := w.pdf.GetMargins()
+	if w.pdf.GetY()+height > pageH-bottom {
+		w.pdf.AddPage()
+	}
+}
+
+// detailOf returns the numeric snapshot of a nilai; rapor generated before the detail column
+// existed only carry the predicate and keterangan
+func detailOf(n model.RaporNilai) model.RaporNilaiDetail {
+	if n.Detail != nil {
+		return *n.Detail
+	}
+	return model.RaporNilaiDetail{Predikat: n.Nilai, Deskripsi: n.Keterangan}
+}
+
+func logoImageType(logo []byte) string {
+	if len(logo) == 0 {
+		return ""
+	}
+	switch http.DetectContentType(logo) {
+	case "image/png":
+		return "PNG"
+	case "image/jpeg":
+		return "JPG"
+	case "image/gif":
+		return "GIF"
+	}
+	return ""
+}
+
+func formatNilai(v float64) string {
+	if v == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f", v)
+}
+
+func formatKKM(kkm int) string {
+	if kkm == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d", kkm)
+}
+
+// FormatTanggal formats a date the Indonesian way, e.g. "20 Desember 2026"
+func FormatTanggal(t time.Time) string {
+	return fmt.Sprintf("%d %s %d", t.Day(), bulanIndonesia[t.Month()-1], t.Year())
+}
@@ -0,0 +1,133 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"prabogo/internal/model"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// GenerateSuratPeringatanPDF renders the warning letter of a sanction addressed to the wali, with
+// the semester's violations and credits behind the points
+func GenerateSuratPeringatanPDF(surat *model.SuratPeringatan) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(raporMarginX, 12, raporMarginX)
+	pdf.SetAutoPageBreak(true, 15)
+	w := &raporWriter{pdf: pdf, tr: pdf.UnicodeTranslatorFromDescriptor("")}
+	pdf.AddPage()
+	s := surat.Sanksi
+
+	// -- Kop --
+	pdf.SetFont("Arial", "B", 14)
+	pdf.CellFormat(0, 7, w.tr(strings.ToUpper(surat.NamaLembaga)), "", 1, "C", false, 0, "")
+	if surat.Alamat != "" {
+		pdf.SetFont("Arial", "", 9)
+		pdf.MultiCell(0, 4.5, w.tr(surat.Alamat), "", "C", false)
+	}
+	y := pdf.GetY() + 1
+	pdf.SetLineWidth(0.6)
+	pdf.Line(raporMarginX, y, raporMarginX+raporPageWidth, y)
+	pdf.SetLineWidth(0.2)
+	pdf.SetY(y + 5)
+
+	pdf.SetFont("Arial", "BU", 12)
+	pdf.CellFormat(0, 6, suratTitle(s.Jenis), "", 1, "C", false, 0, "")
+	pdf.SetFont("Arial", "", 10)
+	pdf.CellFormat(0, 5, w.tr("Nomor: "+s.NomorSurat), "", 1, "C", false, 0, "")
+	pdf.Ln(6)
+
+	// -- Addressee and identity --
+	wali := surat.NamaWali
+	if wali == "" {
+		wali = "Wali Santri"
+	}
+	pdf.CellFormat(0, 5, "Kepada Yth.", "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 5, w.tr("Bapak/Ibu "+wali), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 5, "di tempat", "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+	pdf.MultiCell(0, raporLineH, "Assalamu'alaikum warahmatullahi wabarakatuh. Dengan hormat, kami sampaikan bahwa santri:", "", "L", false)
+	pdf.Ln(1)
+	for _, row := range [][2]string{
+		{"Nama", s.SantriNama},
+		{"NIS", surat.NIS},
+		{"Kelas", s.KelasNama},
+		{"Semester", surat.Semester},
+	} {
+		pdf.SetX(raporMarginX + 10)
+		pdf.CellFormat(30, raporLineH, row[0], "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, raporLineH, w.tr(": "+row[1]), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(2)
+	body := fmt.Sprintf("telah mengumpulkan %d poin pelanggaran pada semester ini sehingga mencapai batas %d poin dan dikenai sanksi %s.",
+		s.TotalPoin, s.AmbangPoin, s.Jenis)
+	if surat.Keterangan != "" {
+		body += " " + strings.TrimSuffix(surat.Keterangan, ".") + "."
+	}
+	pdf.MultiCell(0, raporLineH, w.tr(body), "", "J", false)
+	pdf.Ln(4)
+
+	// -- Violations and credits --
+	widths := []float64{10, 32, 118, 20}
+	w.sectionTitle("Rincian Pelanggaran")
+	w.header(widths, "No", "Tanggal", "Pelanggaran", "Poin")
+	if len(surat.Pelanggaran) == 0 {
+		w.emptyRow("Tidak ada pelanggaran")
+	}
+	for i, p := range surat.Pelanggaran {
+		uraian := p.AturanJudul
+		if p.Keterangan != "" {
+			if uraian != "" {
+				uraian += " - "
+			}
+			uraian += p.Keterangan
+		}
+		w.row(widths, "CCLC", fmt.Sprintf("%d", i+1), FormatTanggal(p.Tanggal), uraian, fmt.Sprintf("%d", p.Poin))
+	}
+	if len(surat.Kredit) > 0 {
+		pdf.Ln(3)
+		w.sectionTitle("Pengurangan Poin (Perilaku Baik)")
+		w.header(widths, "No", "Tanggal", "Keterangan", "Poin")
+		for i, k := range surat.Kredit {
+			w.row(widths, "CCLC", fmt.Sprintf("%d", i+1), FormatTanggal(k.Tanggal), k.Keterangan, fmt.Sprintf("-%d", k.Poin))
+		}
+	}
+	pdf.Ln(4)
+
+	w.ensureSpace(60)
+	pdf.MultiCell(0, raporLineH, "Kami mohon perhatian dan kerja sama Bapak/Ibu dalam membina ananda agar tidak mengulangi pelanggaran. Demikian surat ini kami sampaikan. Wassalamu'alaikum warahmatullahi wabarakatuh.", "", "J", false)
+	pdf.Ln(8)
+
+	// -- Signature --
+	x := raporMarginX + raporPageWidth - 70
+	pdf.SetX(x)
+	pdf.CellFormat(70, 5, FormatTanggal(surat.Tanggal), "", 1, "C", false, 0, "")
+	pdf.SetX(x)
+	pdf.CellFormat(70, 5, "Bagian Keamanan,", "", 1, "C", false, 0, "")
+	pdf.Ln(18)
+	pdf.SetX(x)
+	pdf.CellFormat(70, 5, "(______________________)", "", 1, "C", false, 0, "")
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// suratTitle names the letter after its sanction: a warning letter for SP kinds, a reprimand for
+// Teguran and a summons for Panggilan Wali
+func suratTitle(jenis string) string {
+	upper := strings.ToUpper(strings.TrimSpace(jenis))
+	switch {
+	case strings.HasPrefix(upper, "SP"):
+		return fmt.Sprintf("SURAT PERINGATAN (%s)", upper)
+	case upper == strings.ToUpper(model.SanksiTeguran):
+		return "SURAT TEGURAN"
+	case upper == strings.ToUpper(model.SanksiPanggilanWali):
+		return "SURAT PANGGILAN WALI SANTRI"
+	}
+	return "SURAT " + upper
+}
@@ -0,0 +1,160 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"prabogo/internal/model"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// GenerateKartuUjianPDF renders exam cards, eight per A4 page (2 columns x 4 rows)
+func GenerateKartuUjianPDF(ujian *model.Ujian, kartu []model.KartuUjian) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(10, 10, 10)
+	pdf.SetAutoPageBreak(false, 10)
+
+	const (
+		cardW = 92.0
+		cardH = 66.0
+		gapX  = 6.0
+		gapY  = 4.0
+	)
+
+	for i, k := range kartu {
+		pos := i % 8
+		if pos == 0 {
+			pdf.AddPage()
+		}
+		x := 10 + float64(pos%2)*(cardW+gapX)
+		y := 10 + float64(pos/2)*(cardH+gapY)
+
+		pdf.Rect(x, y, cardW, cardH, "D")
+
+		// -- Header --
+		pdf.SetXY(x, y+2)
+		pdf.SetFont("Arial", "B", 10)
+		pdf.CellFormat(cardW, 5, ujian.NamaSekolah, "", 1, "C", false, 0, "")
+		pdf.SetX(x)
+		pdf.SetFont("Arial", "B", 9)
+		pdf.CellFormat(cardW, 5, "KARTU PESERTA "+strings.ToUpper(ujian.Nama), "", 1, "C", false, 0, "")
+		pdf.Line(x+3, y+13, x+cardW-3, y+13)
+
+		// -- Identity --
+		pdf.SetFont("Arial", "", 9)
+		rowY := y + 16
+		printRow := func(label, value string) {
+			pdf.SetXY(x+4, rowY)
+			pdf.CellFormat(26, 5.5, label, "", 0, "L", false, 0, "")
+			pdf.CellFormat(3, 5.5, ":", "", 0, "L", false, 0, "")
+			pdf.CellFormat(cardW-37, 5.5, value, "", 0, "L", false, 0, "")
+			rowY += 5.5
+		}
+
+		printRow("No. Peserta", k.Peserta.NomorPeserta)
+		printRow("Nama", k.Peserta.SantriNama)
+		printRow("NIS", k.Peserta.NIS)
+		printRow("Kelas", k.Peserta.KelasNama)
+		printRow("Ruang / Kursi", fmt.Sprintf("%s / %d", k.Peserta.RuangNama, k.Peserta.NomorKursi))
+
+		if k.BelumLunas {
+			pdf.SetXY(x+4, rowY+1)
+			pdf.SetFont("Arial", "B", 8)
+			pdf.SetTextColor(200, 0, 0)
+			pdf.CellFormat(cardW-8, 5, "* Belum lunas administrasi, harap menghubungi TU", "", 0, "L", false, 0, "")
+			pdf.SetTextColor(0, 0, 0)
+		}
+
+		// -- Signature --
+		pdf.SetXY(x+cardW-42, y+cardH-14)
+		pdf.SetFont("Arial", "", 8)
+		pdf.CellFormat(38, 4, "Panitia Ujian,", "", 0, "C", false, 0, "")
+		pdf.SetXY(x+cardW-42, y+cardH-6)
+		pdf.CellFormat(38, 4, "(___________________)", "", 0, "C", false, 0, "")
+	}
+
+	if len(kartu) == 0 {
+		pdf.AddPage()
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GenerateDaftarHadirUjianPDF renders one attendance sheet page per room and session
+func GenerateDaftarHadirUjianPDF(ujian *model.Ujian, sheets []model.DaftarHadirUjian) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+
+	for _, s := range sheets {
+		pdf.AddPage()
+
+		// -- Header --
+		pdf.SetFont("Arial", "B", 14)
+		pdf.CellFormat(0, 8, ujian.NamaSekolah, "", 1, "C", false, 0, "")
+		pdf.SetFont("Arial", "B", 12)
+		pdf.CellFormat(0, 7, "DAFTAR HADIR "+strings.ToUpper(ujian.Nama), "", 1, "C", false, 0, "")
+		pdf.Ln(2)
+		pdf.Line(10, pdf.GetY(), 200, pdf.GetY())
+		pdf.Ln(4)
+
+		pdf.SetFont("Arial", "", 10)
+		printRow := func(label, value string) {
+			pdf.CellFormat(35, 6, label, "", 0, "L", false, 0, "")
+			pdf.CellFormat(5, 6, ":", "", 0, "L", false, 0, "")
+			pdf.CellFormat(0, 6, value, "", 1, "L", false, 0, "")
+		}
+		printRow("Ruang", s.Ruang.Nama)
+		printRow("Mata Pelajaran", fmt.Sprintf("%s (Tingkat %s)", s.Jadwal.SubjectNama, s.Jadwal.Tingkat))
+		printRow("Hari / Tanggal", s.Jadwal.WaktuMulai.Format("02-01-2006"))
+		printRow("Waktu", fmt.Sprintf("%s - %s", s.Jadwal.WaktuMulai.Format("15:04"), s.Jadwal.WaktuSelesai.Format("15:04")))
+		pdf.Ln(3)
+
+		// -- Table --
+		pdf.SetFont("Arial", "B", 10)
+		pdf.SetFillColor(240, 240, 240)
+		pdf.CellFormat(10, 8, "No", "1", 0, "C", true, 0, "")
+		pdf.CellFormat(30, 8, "No. Peserta", "1", 0, "C", true, 0, "")
+		pdf.CellFormat(70, 8, "Nama", "1", 0, "C", true, 0, "")
+		pdf.CellFormat(30, 8, "Kelas", "1", 0, "C", true, 0, "")
+		pdf.CellFormat(50, 8, "Tanda Tangan", "1", 1, "C", true, 0, "")
+
+		pdf.SetFont("Arial", "", 10)
+		for i, p := range s.Peserta {
+			align := "L"
+			if i%2 == 1 {
+				align = "R"
+			}
+			pdf.CellFormat(10, 8, fmt.Sprintf("%d", p.NomorKursi), "1", 0, "C", false, 0, "")
+			pdf.CellFormat(30, 8, p.NomorPeserta, "1", 0, "C", false, 0, "")
+			pdf.CellFormat(70, 8, p.SantriNama, "1", 0, "L", false, 0, "")
+			pdf.CellFormat(30, 8, p.KelasNama, "1", 0, "C", false, 0, "")
+			pdf.CellFormat(50, 8, fmt.Sprintf("%d.", i+1), "1", 1, align, false, 0, "")
+		}
+
+		// -- Footer / Pengawas --
+		pdf.Ln(8)
+		pdf.SetFont("Arial", "", 10)
+		pdf.CellFormat(0, 6, fmt.Sprintf("Jumlah peserta: %d    Hadir: ______    Tidak hadir: ______", len(s.Peserta)), "", 1, "L", false, 0, "")
+		pdf.Ln(4)
+		for i, nama := range s.Pengawas {
+			pdf.CellFormat(120, 6, fmt.Sprintf("Pengawas %d: %s", i+1, nama), "", 0, "L", false, 0, "")
+			pdf.CellFormat(0, 6, "(___________________)", "", 1, "C", false, 0, "")
+			pdf.Ln(6)
+		}
+	}
+
+	if len(sheets) == 0 {
+		pdf.AddPage()
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
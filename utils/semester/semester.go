@@ -0,0 +1,59 @@
+// Package semester handles academic semester ids of the form "2025-2026-1", where the
+// last part is 1 for Ganjil (July-December) and 2 for Genap (January-June).
+package semester
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrTidakSah = errors.New("format semester harus TTTT-TTTT-1 atau TTTT-TTTT-2")
+
+// Label turns a semester id such as "2025-2026-1" into "Ganjil 2025/2026"
+func Label(semesterID string) string {
+	parts := strings.Split(semesterID, "-")
+	if len(parts) != 3 {
+		return semesterID
+	}
+	switch parts[2] {
+	case "1":
+		return fmt.Sprintf("Ganjil %s/%s", parts[0], parts[1])
+	case "2":
+		return fmt.Sprintf("Genap %s/%s", parts[0], parts[1])
+	}
+	return semesterID
+}
+
+// Of returns the id of the semester a date falls in, the inverse of Range
+func Of(t time.Time) string {
+	if t.Month() >= time.July {
+		return fmt.Sprintf("%d-%d-1", t.Year(), t.Year()+1)
+	}
+	return fmt.Sprintf("%d-%d-2", t.Year()-1, t.Year())
+}
+
+// Range returns the calendar span of a semester id: Ganjil runs July to December of the
+// first year, Genap January to June of the second. The two years must be consecutive.
+func Range(semesterID string) (start, end time.Time, ok bool) {
+	parts := strings.Split(semesterID, "-")
+	if len(parts) != 3 {
+		return time.Time{}, time.Time{}, false
+	}
+	first, err1 := strconv.Atoi(parts[0])
+	second, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil || second != first+1 {
+		return time.Time{}, time.Time{}, false
+	}
+	switch parts[2] {
+	case "1":
+		start = time.Date(first, time.July, 1, 0, 0, 0, 0, time.Local)
+	case "2":
+		start = time.Date(second, time.January, 1, 0, 0, 0, 0, time.Local)
+	default:
+		return time.Time{}, time.Time{}, false
+	}
+	return start, start.AddDate(0, 6, 0).Add(-time.Nanosecond), true
+}
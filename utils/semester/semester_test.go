@@ -0,0 +1,35 @@
+package semester_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/utils/semester"
+)
+
+func TestSemester(t *testing.T) {
+	Convey("Test Label", t, func() {
+		So(semester.Label("2025-2026-1"), ShouldEqual, "Ganjil 2025/2026")
+		So(semester.Label("2025-2026-2"), ShouldEqual, "Genap 2025/2026")
+		So(semester.Label("Semester 1"), ShouldEqual, "Semester 1")
+	})
+
+	Convey("Test Range and Of", t, func() {
+		start, end, ok := semester.Range("2025-2026-1")
+		So(ok, ShouldBeTrue)
+		So(start, ShouldEqual, time.Date(2025, time.July, 1, 0, 0, 0, 0, time.Local))
+		So(end.Month(), ShouldEqual, time.December)
+		So(semester.Of(start), ShouldEqual, "2025-2026-1")
+		So(semester.Of(end), ShouldEqual, "2025-2026-1")
+		So(semester.Of(end.Add(time.Nanosecond)), ShouldEqual, "2025-2026-2")
+
+		_, _, ok = semester.Range("2025-2026-3")
+		So(ok, ShouldBeFalse)
+		_, _, ok = semester.Range("Semester 1")
+		So(ok, ShouldBeFalse)
+		_, _, ok = semester.Range("2025-2030-1")
+		So(ok, ShouldBeFalse)
+	})
+}